@@ -0,0 +1,35 @@
+// Package backup provides commands for backing up and restoring the Oar database.
+package backup
+
+import (
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/oar-cd/oar/services"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdBackup() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Back up and restore the database",
+		Long: `Back up and restore the database, which holds projects, encrypted credentials, users and
+deployment history.
+
+The watcher backs up the database on a schedule (OAR_BACKUP_INTERVAL) and keeps the latest
+backups (OAR_BACKUP_RETENTION). When OAR_USER is set, these commands require that user to be
+a global admin.`,
+	}
+
+	cmd.AddCommand(NewCmdBackupCreate())
+	cmd.AddCommand(NewCmdBackupList())
+	cmd.AddCommand(NewCmdBackupRestore())
+	return cmd
+}
+
+// requireAdmin makes sure the acting user, if any, is a global admin
+func requireAdmin() error {
+	user := app.GetActingUser()
+	if user == nil {
+		return nil
+	}
+	return app.GetRoleService().Authorize(user, nil, services.RoleAdmin)
+}
@@ -0,0 +1,43 @@
+package backup
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/oar-cd/oar/internal/dbutil"
+	"github.com/oar-cd/oar/models"
+	"github.com/oar-cd/oar/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm/logger"
+)
+
+// setupTestBackupService makes the app use a backup service for a fresh file database, which is returned
+func setupTestBackupService(t *testing.T) *services.BackupService {
+	t.Helper()
+	dir := t.TempDir()
+	database, err := dbutil.InitDatabase(dbutil.DBConfig{Path: filepath.Join(dir, "oar.db"), LogLevel: logger.Silent})
+	require.NoError(t, err)
+	require.NoError(t, models.AutoMigrateAll(database))
+	t.Cleanup(func() {
+		if sqlDB, err := database.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	})
+
+	service := services.NewBackupService(database, &services.Config{BackupDir: filepath.Join(dir, "backups")})
+	app.SetBackupServiceForTesting(service)
+	return service
+}
+
+func TestNewCmdBackup(t *testing.T) {
+	cmd := NewCmdBackup()
+
+	assert.Equal(t, "backup", cmd.Use)
+	var names []string
+	for _, sub := range cmd.Commands() {
+		names = append(names, sub.Name())
+	}
+	assert.ElementsMatch(t, []string{"create", "list", "restore"}, names)
+}
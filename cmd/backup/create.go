@@ -0,0 +1,45 @@
+package backup
+
+import (
+	"fmt"
+
+	"github.com/oar-cd/oar/cmd/output"
+	"github.com/oar-cd/oar/cmd/utils"
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdBackupCreate() *cobra.Command {
+	return &cobra.Command{
+		Use:   "create",
+		Short: "Back up the database",
+		Long: `Take a consistent snapshot of the database while Oar keeps running, and remove the backups
+beyond the retention.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := runBackupCreate(cmd)
+			if err != nil {
+				// Silence usage for runtime errors (not argument validation errors)
+				cmd.SilenceUsage = true
+			}
+			return err
+		},
+	}
+}
+
+// runBackupCreate handles the main logic for creating a backup
+func runBackupCreate(cmd *cobra.Command) error {
+	if err := requireAdmin(); err != nil {
+		return err
+	}
+
+	ctx, cancel := utils.InterruptContext(cmd)
+	defer cancel()
+
+	backup, err := app.GetBackupService().Create(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create backup: %w", err)
+	}
+
+	return output.FprintSuccess(cmd, "Backup '%s' created at %s\n", backup.Name, backup.Path)
+}
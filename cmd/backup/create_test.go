@@ -0,0 +1,28 @@
+package backup
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/oar-cd/oar/cmd/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCmdBackupCreate(t *testing.T) {
+	output.InitColors(true)
+	service := setupTestBackupService(t)
+
+	cmd := NewCmdBackupCreate()
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetArgs([]string{})
+
+	require.NoError(t, cmd.Execute())
+
+	backups, err := service.List()
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+	assert.Contains(t, stdout.String(), backups[0].Name)
+	assert.Contains(t, stdout.String(), "created")
+}
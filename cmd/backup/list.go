@@ -0,0 +1,43 @@
+package backup
+
+import (
+	"fmt"
+
+	"github.com/oar-cd/oar/cmd/output"
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdBackupList() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List database backups",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := runBackupList(cmd)
+			if err != nil {
+				// Silence usage for runtime errors (not argument validation errors)
+				cmd.SilenceUsage = true
+			}
+			return err
+		},
+	}
+}
+
+// runBackupList handles the main logic for listing backups
+func runBackupList(cmd *cobra.Command) error {
+	if err := requireAdmin(); err != nil {
+		return err
+	}
+
+	backups, err := app.GetBackupService().List()
+	if err != nil {
+		return fmt.Errorf("failed to list backups: %w", err)
+	}
+
+	out, err := output.PrintBackupList(backups)
+	if err != nil {
+		return err
+	}
+	return output.FprintPlain(cmd, "%s", out)
+}
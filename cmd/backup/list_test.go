@@ -0,0 +1,31 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/oar-cd/oar/cmd/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCmdBackupList(t *testing.T) {
+	output.InitColors(true)
+	service := setupTestBackupService(t)
+
+	cmd := NewCmdBackupList()
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetArgs([]string{})
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, stdout.String(), "No backups found.")
+
+	backup, err := service.Create(context.Background())
+	require.NoError(t, err)
+
+	stdout.Reset()
+	cmd.SetArgs([]string{})
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, stdout.String(), backup.Name)
+}
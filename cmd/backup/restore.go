@@ -0,0 +1,71 @@
+package backup
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+
+	"github.com/oar-cd/oar/cmd/output"
+	"github.com/oar-cd/oar/cmd/utils"
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdBackupRestore() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore <backup>",
+		Short: "Restore the database from a backup",
+		Long: `Replace the contents of the database with those of a backup listed by 'oar backup list'.
+
+The current database is backed up first, so a restore can be undone by restoring that backup.
+Restart Oar afterwards, so that the web UI and the watcher pick up the restored data.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := runBackupRestore(cmd, args)
+			if err != nil {
+				// Silence usage for runtime errors (not argument validation errors)
+				cmd.SilenceUsage = true
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().BoolP("confirm", "y", false, "Skip confirmation prompt and proceed with the restore")
+
+	return cmd
+}
+
+// runBackupRestore handles the main logic for restoring a backup
+func runBackupRestore(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	if err := requireAdmin(); err != nil {
+		return err
+	}
+
+	skipConfirmation, _ := cmd.Flags().GetBool("confirm")
+	if !skipConfirmation {
+		if err := output.FprintWarning(
+			cmd, "All current data will be replaced by backup '%s'. Continue? [y/N]: ", name,
+		); err != nil {
+			return err
+		}
+		answer, _ := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+		if answer = strings.ToLower(strings.TrimSpace(answer)); answer != "y" && answer != "yes" {
+			return output.FprintPlain(cmd, "Restore cancelled.\n")
+		}
+	}
+
+	ctx, cancel := utils.InterruptContext(cmd)
+	defer cancel()
+
+	previous, err := app.GetBackupService().Restore(ctx, name)
+	if err != nil {
+		return fmt.Errorf("failed to restore backup: %w", err)
+	}
+
+	if err := output.FprintSuccess(cmd, "Database restored from backup '%s'\n", name); err != nil {
+		return err
+	}
+	return output.FprintPlain(cmd, "The previous database was backed up as '%s'\n", previous.Name)
+}
@@ -0,0 +1,67 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/oar-cd/oar/cmd/output"
+	"github.com/oar-cd/oar/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCmdBackupRestore(t *testing.T) {
+	output.InitColors(true)
+	service := setupTestBackupService(t)
+	backup, err := service.Create(context.Background())
+	require.NoError(t, err)
+
+	cmd := NewCmdBackupRestore()
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetArgs([]string{"--confirm", backup.Name})
+
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, stdout.String(), "restored from backup")
+
+	// The database as it was before the restore is backed up
+	backups, err := service.List()
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+	assert.Contains(t, stdout.String(), backups[0].Name)
+}
+
+func TestNewCmdBackupRestore_Cancelled(t *testing.T) {
+	output.InitColors(true)
+	service := setupTestBackupService(t)
+	backup, err := service.Create(context.Background())
+	require.NoError(t, err)
+
+	cmd := NewCmdBackupRestore()
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetIn(strings.NewReader("n\n"))
+	cmd.SetArgs([]string{backup.Name})
+
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, stdout.String(), "Restore cancelled.")
+
+	backups, err := service.List()
+	require.NoError(t, err)
+	assert.Len(t, backups, 1)
+}
+
+func TestNewCmdBackupRestore_NotFound(t *testing.T) {
+	setupTestBackupService(t)
+
+	cmd := NewCmdBackupRestore()
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stdout)
+	cmd.SetArgs([]string{"--confirm", "oar-missing.db"})
+
+	err := cmd.Execute()
+	assert.ErrorIs(t, err, services.ErrBackupNotFound)
+}
@@ -0,0 +1,86 @@
+package catalog
+
+import (
+	"fmt"
+
+	"github.com/oar-cd/oar/cmd/output"
+	"github.com/oar-cd/oar/cmd/utils"
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/oar-cd/oar/services"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdCatalogAdd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add",
+		Short: "Add a catalog and sync its projects",
+		Long: `Add a Git repository with a ` + services.CatalogFile + ` file as a catalog, then sync it,
+creating and deploying the projects it lists.`,
+		Example: `  # Add a public catalog
+  oar catalog add --name platform --git-url https://github.com/org/platform.git
+
+  # Add a private catalog with secrets its projects reference
+  oar catalog add --name platform --git-url https://github.com/org/platform.git \
+    --git-auth http --git-username token --git-password ghp_xxxxx \
+    --secret web-db-password=s3cret --secret api-token=xxxxx`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := runCatalogAdd(cmd)
+			if err != nil {
+				// Silence usage for runtime errors (not argument validation errors)
+				cmd.SilenceUsage = true
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().StringP("name", "n", "", "Catalog name")
+	cmd.Flags().StringP("git-url", "u", "", "Git repository URL")
+	cmd.Flags().StringP("branch", "b", "", "Git branch to use (uses repository default if not specified)")
+	cmd.Flags().StringArray("secret", nil, "Secret projects can reference, in name=value format. "+
+		"Can be used multiple times")
+	cmd.Flags().Bool("sync", true, "Sync the catalog after adding it")
+	utils.AddGitAuthFlags(cmd)
+	_ = cmd.MarkFlagRequired("name")
+	_ = cmd.MarkFlagRequired("git-url")
+
+	return cmd
+}
+
+// runCatalogAdd handles the main logic for adding a catalog
+func runCatalogAdd(cmd *cobra.Command) error {
+	name, _ := cmd.Flags().GetString("name")
+	gitURL, _ := cmd.Flags().GetString("git-url")
+	branch, _ := cmd.Flags().GetString("branch")
+	secretFlags, _ := cmd.Flags().GetStringArray("secret")
+	sync, _ := cmd.Flags().GetBool("sync")
+
+	gitAuth, err := utils.GitAuthFromFlags(cmd)
+	if err != nil {
+		return fmt.Errorf("invalid authentication configuration: %w", err)
+	}
+	secrets, err := setSecrets(nil, secretFlags)
+	if err != nil {
+		return err
+	}
+
+	catalogService := app.GetCatalogService()
+	catalog, err := catalogService.Create(&services.Catalog{
+		Name:      name,
+		GitURL:    gitURL,
+		GitBranch: branch,
+		GitAuth:   gitAuth,
+		Secrets:   secrets,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add catalog from %s: %w", gitURL, err)
+	}
+
+	if err := output.FprintSuccess(cmd, "Added catalog '%s' (%s)\n", catalog.Name, catalog.ID); err != nil {
+		return err
+	}
+	if !sync {
+		return nil
+	}
+	return syncCatalog(cmd, catalogService, catalog)
+}
@@ -0,0 +1,135 @@
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/oar-cd/oar/cmd/output"
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/oar-cd/oar/services"
+	"github.com/oar-cd/oar/testing/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCmdCatalogAdd(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		syncResult    *services.CatalogSyncResult
+		expectCatalog *services.Catalog
+		expectSynced  bool
+		expectError   string
+		expectedInOut []string
+	}{
+		{
+			name: "add and sync",
+			args: []string{
+				"--name", "platform", "--git-url", "https://github.com/org/platform.git",
+				"--secret", "web-db-password=s3cret", "--git-auth", "http", "--git-username", "token",
+				"--git-password", "ghp_x",
+			},
+			syncResult: &services.CatalogSyncResult{Created: []string{"web"}},
+			expectCatalog: &services.Catalog{
+				Name:    "platform",
+				GitURL:  "https://github.com/org/platform.git",
+				Secrets: []string{"web-db-password=s3cret"},
+				GitAuth: &services.GitAuthConfig{
+					HTTPAuth: &services.GitHTTPAuthConfig{Username: "token", Password: "ghp_x"},
+				},
+			},
+			expectSynced:  true,
+			expectedInOut: []string{"Added catalog 'platform'", "web", "synced successfully"},
+		},
+		{
+			name: "without sync",
+			args: []string{
+				"--name", "platform", "--git-url", "https://github.com/org/platform.git", "--branch", "prod",
+				"--sync=false",
+			},
+			expectCatalog: &services.Catalog{
+				Name:      "platform",
+				GitURL:    "https://github.com/org/platform.git",
+				GitBranch: "prod",
+			},
+			expectedInOut: []string{"Added catalog 'platform'"},
+		},
+		{
+			name:        "projects failing to sync",
+			args:        []string{"--name", "platform", "--git-url", "https://github.com/org/platform.git"},
+			syncResult:  &services.CatalogSyncResult{Errors: []string{"web: deployment failed"}},
+			expectError: "1 project(s) of catalog 'platform' could not be synced",
+		},
+		{
+			name:        "missing git URL",
+			args:        []string{"--name", "platform"},
+			expectError: `required flag(s) "git-url" not set`,
+		},
+		{
+			name: "invalid secret",
+			args: []string{
+				"--name", "platform", "--git-url", "https://github.com/org/platform.git", "--secret", "token",
+			},
+			expectError: "expected name=value",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output.InitColors(true)
+			var created *services.Catalog
+			synced := false
+			app.SetCatalogServiceForTesting(&mocks.MockCatalogManager{
+				CreateFunc: func(catalog *services.Catalog) (*services.Catalog, error) {
+					created = catalog
+					catalog.ID = uuid.New()
+					return catalog, nil
+				},
+				SyncFunc: func(ctx context.Context, id uuid.UUID) (*services.CatalogSyncResult, error) {
+					synced = true
+					assert.Equal(t, created.ID, id)
+					return tt.syncResult, nil
+				},
+			})
+
+			cmd := NewCmdCatalogAdd()
+			var stdout bytes.Buffer
+			cmd.SetOut(&stdout)
+			cmd.SetErr(&stdout)
+			cmd.SetArgs(tt.args)
+
+			err := cmd.Execute()
+
+			if tt.expectError != "" {
+				assert.ErrorContains(t, err, tt.expectError)
+				return
+			}
+			require.NoError(t, err)
+			tt.expectCatalog.ID = created.ID
+			assert.Equal(t, tt.expectCatalog, created)
+			assert.Equal(t, tt.expectSynced, synced)
+			for _, expected := range tt.expectedInOut {
+				assert.Contains(t, stdout.String(), expected)
+			}
+		})
+	}
+}
+
+func TestNewCmdCatalogAdd_CreateError(t *testing.T) {
+	app.SetCatalogServiceForTesting(&mocks.MockCatalogManager{
+		CreateFunc: func(catalog *services.Catalog) (*services.Catalog, error) {
+			return nil, errors.New("failed to clone repository")
+		},
+	})
+
+	cmd := NewCmdCatalogAdd()
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stdout)
+	cmd.SetArgs([]string{"--name", "platform", "--git-url", "https://github.com/org/platform.git"})
+
+	assert.ErrorContains(t, cmd.Execute(), "failed to add catalog from https://github.com/org/platform.git")
+}
@@ -0,0 +1,81 @@
+// Package catalog provides commands for managing catalogs, Git repositories that declare the projects Oar runs.
+package catalog
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/oar-cd/oar/services"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdCatalog() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "catalog",
+		Short: "Manage catalogs of projects declared in Git",
+		Long: `Manage catalogs. A catalog is a Git repository with an ` + services.CatalogFile + ` file listing
+projects. Syncing a catalog creates the projects it lists, updates those whose settings changed
+and removes those it no longer lists, so the repository decides which projects Oar runs.
+
+Projects reference catalog secrets by name, keeping credentials out of the repository.
+The watcher syncs every catalog before checking projects for new commits.
+Managing catalogs requires the global admin role.`,
+	}
+
+	cmd.AddCommand(NewCmdCatalogList())
+	cmd.AddCommand(NewCmdCatalogAdd())
+	cmd.AddCommand(NewCmdCatalogUpdate())
+	cmd.AddCommand(NewCmdCatalogSync())
+	cmd.AddCommand(NewCmdCatalogRemove())
+	return cmd
+}
+
+// findCatalog looks up a catalog by ID, or by name if the reference is not a valid UUID
+func findCatalog(catalogService services.CatalogManager, ref string) (*services.Catalog, error) {
+	if catalogID, err := uuid.Parse(ref); err == nil {
+		catalog, err := catalogService.Get(catalogID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find catalog %s: %w", catalogID, err)
+		}
+		return catalog, nil
+	}
+
+	catalogs, err := catalogService.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list catalogs: %w", err)
+	}
+	for _, catalog := range catalogs {
+		if catalog.Name == ref {
+			return catalog, nil
+		}
+	}
+	return nil, fmt.Errorf("catalog '%s' not found", ref)
+}
+
+// setSecrets returns secrets with those given as name=value set, replacing secrets of the same name
+func setSecrets(secrets []string, values []string) ([]string, error) {
+	result := slices.Clone(secrets)
+	for _, value := range values {
+		name, _, ok := strings.Cut(value, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid secret %q, expected name=value", value)
+		}
+		result = unsetSecrets(result, []string{name})
+		result = append(result, value)
+	}
+	return result, nil
+}
+
+// unsetSecrets returns secrets without those called one of names
+func unsetSecrets(secrets []string, names []string) []string {
+	var result []string
+	for _, secret := range secrets {
+		name, _, _ := strings.Cut(secret, "=")
+		if !slices.Contains(names, name) {
+			result = append(result, secret)
+		}
+	}
+	return result
+}
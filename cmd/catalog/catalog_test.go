@@ -0,0 +1,79 @@
+package catalog
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/oar-cd/oar/services"
+	"github.com/oar-cd/oar/testing/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupTestCatalog makes the catalog service know a single catalog, which is returned
+func setupTestCatalog(t *testing.T, service *mocks.MockCatalogManager) *services.Catalog {
+	t.Helper()
+	catalog := &services.Catalog{
+		ID:        uuid.New(),
+		Name:      "platform",
+		GitURL:    "https://github.com/org/platform.git",
+		GitBranch: "main",
+		Secrets:   []string{"web-db-password=s3cret"},
+	}
+	service.ListFunc = func() ([]*services.Catalog, error) {
+		return []*services.Catalog{catalog}, nil
+	}
+	service.GetFunc = func(id uuid.UUID) (*services.Catalog, error) {
+		if id != catalog.ID {
+			return nil, errors.New("catalog not found")
+		}
+		return catalog, nil
+	}
+	app.SetCatalogServiceForTesting(service)
+	return catalog
+}
+
+func TestNewCmdCatalog(t *testing.T) {
+	cmd := NewCmdCatalog()
+
+	assert.Equal(t, "catalog", cmd.Use)
+	var names []string
+	for _, sub := range cmd.Commands() {
+		names = append(names, sub.Name())
+	}
+	assert.ElementsMatch(t, []string{"list", "add", "update", "sync", "remove"}, names)
+}
+
+func TestFindCatalog(t *testing.T) {
+	service := &mocks.MockCatalogManager{}
+	catalog := setupTestCatalog(t, service)
+
+	found, err := findCatalog(service, "platform")
+	require.NoError(t, err)
+	assert.Equal(t, catalog, found)
+
+	found, err = findCatalog(service, catalog.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, catalog, found)
+
+	_, err = findCatalog(service, "staging")
+	assert.ErrorContains(t, err, "catalog 'staging' not found")
+
+	_, err = findCatalog(service, uuid.New().String())
+	assert.ErrorContains(t, err, "failed to find catalog")
+}
+
+func TestSetSecrets(t *testing.T) {
+	secrets, err := setSecrets([]string{"a=1", "b=2"}, []string{"b=3", "c=x=y"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a=1", "b=3", "c=x=y"}, secrets)
+
+	_, err = setSecrets(nil, []string{"no-value"})
+	assert.ErrorContains(t, err, "expected name=value")
+	_, err = setSecrets(nil, []string{"=value"})
+	assert.ErrorContains(t, err, "expected name=value")
+
+	assert.Equal(t, []string{"b=2"}, unsetSecrets([]string{"a=1", "b=2"}, []string{"a", "c"}))
+}
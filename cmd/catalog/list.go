@@ -0,0 +1,40 @@
+package catalog
+
+import (
+	"fmt"
+
+	"github.com/oar-cd/oar/cmd/output"
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdCatalogList() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List catalogs",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := runCatalogList(cmd)
+			if err != nil {
+				// Silence usage for runtime errors (not argument validation errors)
+				cmd.SilenceUsage = true
+			}
+			return err
+		},
+	}
+}
+
+// runCatalogList handles the main logic for listing catalogs
+func runCatalogList(cmd *cobra.Command) error {
+	catalogs, err := app.GetCatalogService().List()
+	if err != nil {
+		return fmt.Errorf("failed to list catalogs: %w", err)
+	}
+
+	out, err := output.PrintCatalogList(catalogs)
+	if err != nil {
+		return err
+	}
+
+	return output.FprintPlain(cmd, "%s", out)
+}
@@ -0,0 +1,47 @@
+package catalog
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/oar-cd/oar/cmd/output"
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/oar-cd/oar/services"
+	"github.com/oar-cd/oar/testing/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCmdCatalogList(t *testing.T) {
+	output.InitColors(true)
+	setupTestCatalog(t, &mocks.MockCatalogManager{})
+
+	cmd := NewCmdCatalogList()
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetArgs([]string{})
+
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, stdout.String(), "platform")
+	assert.Contains(t, stdout.String(), "web-db-password")
+	assert.NotContains(t, stdout.String(), "s3cret")
+}
+
+func TestNewCmdCatalogList_Error(t *testing.T) {
+	app.SetCatalogServiceForTesting(&mocks.MockCatalogManager{
+		ListFunc: func() ([]*services.Catalog, error) {
+			return nil, services.ErrPermissionDenied
+		},
+	})
+
+	cmd := NewCmdCatalogList()
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stdout)
+	cmd.SetArgs([]string{})
+
+	err := cmd.Execute()
+	assert.True(t, errors.Is(err, services.ErrPermissionDenied))
+	assert.ErrorContains(t, err, "failed to list catalogs")
+}
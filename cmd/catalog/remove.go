@@ -0,0 +1,50 @@
+package catalog
+
+import (
+	"fmt"
+
+	"github.com/oar-cd/oar/cmd/output"
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdCatalogRemove() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remove <catalog-id|name>",
+		Short: "Remove a catalog",
+		Long: `Remove a catalog. Its projects are kept and can be managed by hand from then on,
+unless --remove-projects is given.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := runCatalogRemove(cmd, args)
+			if err != nil {
+				// Silence usage for runtime errors (not argument validation errors)
+				cmd.SilenceUsage = true
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().Bool("remove-projects", false, "Also stop and remove the projects of the catalog")
+	return cmd
+}
+
+// runCatalogRemove handles the main logic for removing a catalog
+func runCatalogRemove(cmd *cobra.Command, args []string) error {
+	removeProjects, _ := cmd.Flags().GetBool("remove-projects")
+
+	catalogService := app.GetCatalogService()
+	catalog, err := findCatalog(catalogService, args[0])
+	if err != nil {
+		return err
+	}
+
+	if err := catalogService.Remove(catalog.ID, removeProjects); err != nil {
+		return fmt.Errorf("failed to remove catalog '%s': %w", catalog.Name, err)
+	}
+
+	if removeProjects {
+		return output.FprintSuccess(cmd, "Catalog '%s' and its projects removed successfully\n", catalog.Name)
+	}
+	return output.FprintSuccess(cmd, "Catalog '%s' removed successfully, its projects were kept\n", catalog.Name)
+}
@@ -0,0 +1,52 @@
+package catalog
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/oar-cd/oar/testing/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCmdCatalogRemove(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		removeProjects bool
+		expectedInOut  string
+	}{
+		{name: "keep projects", args: []string{"platform"}, expectedInOut: "its projects were kept"},
+		{
+			name:           "remove projects",
+			args:           []string{"platform", "--remove-projects"},
+			removeProjects: true,
+			expectedInOut:  "Catalog 'platform' and its projects removed successfully",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var removedID uuid.UUID
+			var removedProjects bool
+			service := &mocks.MockCatalogManager{
+				RemoveFunc: func(id uuid.UUID, removeProjects bool) error {
+					removedID, removedProjects = id, removeProjects
+					return nil
+				},
+			}
+			catalog := setupTestCatalog(t, service)
+
+			cmd := NewCmdCatalogRemove()
+			var stdout bytes.Buffer
+			cmd.SetOut(&stdout)
+			cmd.SetArgs(tt.args)
+
+			require.NoError(t, cmd.Execute())
+			assert.Equal(t, catalog.ID, removedID)
+			assert.Equal(t, tt.removeProjects, removedProjects)
+			assert.Contains(t, stdout.String(), tt.expectedInOut)
+		})
+	}
+}
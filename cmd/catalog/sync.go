@@ -0,0 +1,67 @@
+package catalog
+
+import (
+	"fmt"
+
+	"github.com/oar-cd/oar/cmd/output"
+	"github.com/oar-cd/oar/cmd/utils"
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/oar-cd/oar/services"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdCatalogSync() *cobra.Command {
+	return &cobra.Command{
+		Use:   "sync <catalog-id|name>",
+		Short: "Sync the projects of a catalog with its repository",
+		Long: `Pull the catalog repository and reconcile its projects: create and deploy new projects,
+update and redeploy changed ones, and remove the projects it no longer lists.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := runCatalogSync(cmd, args)
+			if err != nil {
+				// Silence usage for runtime errors (not argument validation errors)
+				cmd.SilenceUsage = true
+			}
+			return err
+		},
+	}
+}
+
+// runCatalogSync handles the main logic for syncing a catalog
+func runCatalogSync(cmd *cobra.Command, args []string) error {
+	catalogService := app.GetCatalogService()
+	catalog, err := findCatalog(catalogService, args[0])
+	if err != nil {
+		return err
+	}
+	return syncCatalog(cmd, catalogService, catalog)
+}
+
+// syncCatalog syncs catalog and prints what changed, failing if some projects could not be synced
+func syncCatalog(cmd *cobra.Command, catalogService services.CatalogManager, catalog *services.Catalog) error {
+	if err := output.FprintPlain(cmd, "Syncing catalog '%s'\n", catalog.Name); err != nil {
+		return err
+	}
+
+	ctx, cancel := utils.InterruptContext(cmd)
+	defer cancel()
+
+	result, err := catalogService.Sync(ctx, catalog.ID)
+	if err != nil {
+		return fmt.Errorf("failed to sync catalog '%s': %w", catalog.Name, err)
+	}
+
+	out, err := output.PrintCatalogSyncResult(result)
+	if err != nil {
+		return err
+	}
+	if err := output.FprintPlain(cmd, "%s", out); err != nil {
+		return err
+	}
+
+	if len(result.Errors) > 0 {
+		return fmt.Errorf("%d project(s) of catalog '%s' could not be synced", len(result.Errors), catalog.Name)
+	}
+	return output.FprintSuccess(cmd, "Catalog '%s' synced successfully\n", catalog.Name)
+}
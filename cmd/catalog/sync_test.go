@@ -0,0 +1,56 @@
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/oar-cd/oar/cmd/output"
+	"github.com/oar-cd/oar/services"
+	"github.com/oar-cd/oar/testing/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCmdCatalogSync(t *testing.T) {
+	output.InitColors(true)
+	service := &mocks.MockCatalogManager{}
+	catalog := setupTestCatalog(t, service)
+	service.SyncFunc = func(ctx context.Context, id uuid.UUID) (*services.CatalogSyncResult, error) {
+		assert.Equal(t, catalog.ID, id)
+		return &services.CatalogSyncResult{
+			Commit:  "abc123def456789012345678901234567890abcd",
+			Updated: []string{"web"},
+			Removed: []string{"docs"},
+		}, nil
+	}
+
+	cmd := NewCmdCatalogSync()
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetArgs([]string{"platform"})
+
+	require.NoError(t, cmd.Execute())
+	for _, expected := range []string{"Syncing catalog 'platform'", "abc123de", "web", "docs", "synced successfully"} {
+		assert.Contains(t, stdout.String(), expected)
+	}
+}
+
+func TestNewCmdCatalogSync_Error(t *testing.T) {
+	service := &mocks.MockCatalogManager{}
+	setupTestCatalog(t, service)
+	service.SyncFunc = func(ctx context.Context, id uuid.UUID) (*services.CatalogSyncResult, error) {
+		return nil, services.ErrInvalidCatalog
+	}
+
+	cmd := NewCmdCatalogSync()
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stdout)
+	cmd.SetArgs([]string{"platform"})
+
+	err := cmd.Execute()
+	assert.ErrorIs(t, err, services.ErrInvalidCatalog)
+	assert.ErrorContains(t, err, "failed to sync catalog 'platform'")
+}
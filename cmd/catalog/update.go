@@ -0,0 +1,69 @@
+package catalog
+
+import (
+	"fmt"
+
+	"github.com/oar-cd/oar/cmd/output"
+	"github.com/oar-cd/oar/cmd/utils"
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdCatalogUpdate() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update <catalog-id|name>",
+		Short: "Change the branch, credentials or secrets of a catalog",
+		Long:  `Change the settings of a catalog. Changes apply to its projects on the next sync.`,
+		Example: `  # Rotate a secret
+  oar catalog update platform --secret web-db-password=n3w-s3cret
+
+  # Remove a secret no project references anymore
+  oar catalog update platform --unset-secret api-token`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := runCatalogUpdate(cmd, args)
+			if err != nil {
+				// Silence usage for runtime errors (not argument validation errors)
+				cmd.SilenceUsage = true
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().StringP("branch", "b", "", "Git branch to use")
+	cmd.Flags().StringArray("secret", nil, "Secret to set, in name=value format. Can be used multiple times")
+	cmd.Flags().StringArray("unset-secret", nil, "Name of a secret to remove. Can be used multiple times")
+	utils.AddGitAuthFlags(cmd)
+
+	return cmd
+}
+
+// runCatalogUpdate handles the main logic for updating a catalog
+func runCatalogUpdate(cmd *cobra.Command, args []string) error {
+	catalogService := app.GetCatalogService()
+	catalog, err := findCatalog(catalogService, args[0])
+	if err != nil {
+		return err
+	}
+
+	if cmd.Flags().Changed("branch") {
+		catalog.GitBranch, _ = cmd.Flags().GetString("branch")
+	}
+	if cmd.Flags().Changed("git-auth") {
+		if catalog.GitAuth, err = utils.GitAuthFromFlags(cmd); err != nil {
+			return fmt.Errorf("invalid authentication configuration: %w", err)
+		}
+	}
+	unset, _ := cmd.Flags().GetStringArray("unset-secret")
+	catalog.Secrets = unsetSecrets(catalog.Secrets, unset)
+	secretFlags, _ := cmd.Flags().GetStringArray("secret")
+	if catalog.Secrets, err = setSecrets(catalog.Secrets, secretFlags); err != nil {
+		return err
+	}
+
+	if err := catalogService.Update(catalog); err != nil {
+		return fmt.Errorf("failed to update catalog '%s': %w", catalog.Name, err)
+	}
+
+	return output.FprintSuccess(cmd, "Catalog '%s' updated successfully\n", catalog.Name)
+}
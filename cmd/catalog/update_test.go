@@ -0,0 +1,49 @@
+package catalog
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/oar-cd/oar/services"
+	"github.com/oar-cd/oar/testing/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCmdCatalogUpdate(t *testing.T) {
+	var updated *services.Catalog
+	service := &mocks.MockCatalogManager{
+		UpdateFunc: func(catalog *services.Catalog) error {
+			updated = catalog
+			return nil
+		},
+	}
+	catalog := setupTestCatalog(t, service)
+	catalog.GitAuth = &services.GitAuthConfig{HTTPAuth: &services.GitHTTPAuthConfig{Username: "a", Password: "b"}}
+
+	cmd := NewCmdCatalogUpdate()
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetArgs([]string{"platform", "--secret", "api-token=xyz", "--unset-secret", "web-db-password",
+		"--branch", "prod"})
+
+	require.NoError(t, cmd.Execute())
+	require.NotNil(t, updated)
+	assert.Equal(t, "prod", updated.GitBranch)
+	assert.Equal(t, []string{"api-token=xyz"}, updated.Secrets)
+	// Credentials are kept unless given
+	assert.NotNil(t, updated.GitAuth)
+	assert.Contains(t, stdout.String(), "Catalog 'platform' updated successfully")
+}
+
+func TestNewCmdCatalogUpdate_NotFound(t *testing.T) {
+	setupTestCatalog(t, &mocks.MockCatalogManager{})
+
+	cmd := NewCmdCatalogUpdate()
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stdout)
+	cmd.SetArgs([]string{"staging", "--branch", "prod"})
+
+	assert.ErrorContains(t, cmd.Execute(), "catalog 'staging' not found")
+}
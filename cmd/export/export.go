@@ -0,0 +1,122 @@
+// Package export provides the commands for exporting the projects and catalogs of an Oar instance and importing
+// them into another one.
+package export
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/oar-cd/oar/services"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+func NewCmdExport() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export all projects and catalogs",
+		Long: `Export the settings of all projects and catalogs as YAML, to move them to another Oar instance
+with 'oar import'.
+
+Variables, Git credentials and notifiers are encrypted with a passphrase, which is asked for unless
+--passphrase-stdin or --omit-secrets is given. Projects managed by a catalog are not exported,
+importing the catalog recreates them. Users, role bindings, deployment history and volume backups
+are not exported, the export lists them and importing it warns about them.`,
+		Example: `  oar export > oar-backup.yaml
+  oar export --omit-secrets --output oar-backup.yaml`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := runExport(cmd)
+			if err != nil {
+				// Silence usage for runtime errors (not argument validation errors)
+				cmd.SilenceUsage = true
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().StringP("output", "o", "", "Write the export to a file instead of standard output")
+	cmd.Flags().Bool("omit-secrets", false, "Leave variables, Git credentials and notifiers out of the export")
+	cmd.Flags().Bool("passphrase-stdin", false, "Read the passphrase from standard input")
+
+	return cmd
+}
+
+// runExport handles the main logic for exporting
+func runExport(cmd *cobra.Command) error {
+	outputPath, _ := cmd.Flags().GetString("output")
+	omitSecrets, _ := cmd.Flags().GetBool("omit-secrets")
+
+	options := services.ExportOptions{OmitSecrets: omitSecrets}
+	if !omitSecrets {
+		passphrase, err := readPassphrase(cmd, true)
+		if err != nil {
+			return err
+		}
+		options.Passphrase = passphrase
+	}
+
+	export, err := app.GetExportService().Export(options)
+	if err != nil {
+		return fmt.Errorf("failed to export: %w", err)
+	}
+
+	if outputPath == "" {
+		return services.WriteExport(cmd.OutOrStdout(), export)
+	}
+
+	// The export holds credentials, even if encrypted, so only the owner may read it
+	file, err := os.OpenFile(outputPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create export file: %w", err)
+	}
+	if err := services.WriteExport(file, export); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// readPassphrase reads the passphrase either from stdin (--passphrase-stdin) or interactively from the terminal,
+// asking for it twice if confirm is set. Prompts go to stderr, so that they don't end up in an export on stdout.
+func readPassphrase(cmd *cobra.Command, confirm bool) (string, error) {
+	fromStdin, _ := cmd.Flags().GetBool("passphrase-stdin")
+	if fromStdin {
+		line, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+		if err != nil && line == "" {
+			return "", fmt.Errorf("failed to read passphrase from stdin: %w", err)
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", errors.New("stdin is not a terminal, use --passphrase-stdin to provide the passphrase")
+	}
+
+	stderr := cmd.ErrOrStderr()
+	_, _ = fmt.Fprint(stderr, "Passphrase: ")
+	passphrase, err := term.ReadPassword(fd)
+	_, _ = fmt.Fprintln(stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	if !confirm {
+		return string(passphrase), nil
+	}
+
+	_, _ = fmt.Fprint(stderr, "Confirm passphrase: ")
+	confirmation, err := term.ReadPassword(fd)
+	_, _ = fmt.Fprintln(stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	if string(passphrase) != string(confirmation) {
+		return "", errors.New("passphrases do not match")
+	}
+	return string(passphrase), nil
+}
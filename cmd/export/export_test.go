@@ -0,0 +1,152 @@
+package export
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/oar-cd/oar/services"
+	"github.com/oar-cd/oar/testing/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupTestSource makes the services know a single running project with a secret variable
+func setupTestSource(t *testing.T) {
+	t.Helper()
+	project := services.NewProject("web", "https://github.com/org/web.git", []string{"compose.yml"},
+		[]string{"DB_PASSWORD=s3cret"})
+	project.Status = services.ProjectStatusRunning
+	app.SetProjectServiceForTesting(&mocks.MockProjectManager{
+		ListFunc: func() ([]*services.Project, error) {
+			return []*services.Project{&project}, nil
+		},
+	})
+	app.SetCatalogServiceForTesting(&mocks.MockCatalogManager{})
+	app.SetNotificationServiceForTesting(&mocks.MockNotificationManager{})
+}
+
+func TestNewCmdExport(t *testing.T) {
+	setupTestSource(t)
+
+	cmd := NewCmdExport()
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetIn(strings.NewReader("correct horse\n"))
+	cmd.SetArgs([]string{"--passphrase-stdin"})
+
+	require.NoError(t, cmd.Execute())
+
+	export, err := services.ReadExport(&stdout)
+	require.NoError(t, err)
+	require.Len(t, export.Projects, 1)
+	assert.Equal(t, "web", export.Projects[0].Name)
+	assert.NotEmpty(t, export.Projects[0].Secrets)
+	assert.NotContains(t, export.Projects[0].Secrets, "s3cret")
+}
+
+func TestNewCmdExport_OmitSecrets(t *testing.T) {
+	setupTestSource(t)
+	path := filepath.Join(t.TempDir(), "oar-backup.yaml")
+
+	cmd := NewCmdExport()
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetArgs([]string{"--omit-secrets", "--output", path})
+
+	require.NoError(t, cmd.Execute())
+	assert.Empty(t, stdout.String())
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "name: web")
+	assert.NotContains(t, string(content), "secrets")
+}
+
+func TestNewCmdImport(t *testing.T) {
+	setupTestSource(t)
+	path := filepath.Join(t.TempDir(), "oar-backup.yaml")
+
+	exportCmd := NewCmdExport()
+	exportCmd.SetIn(strings.NewReader("correct horse\n"))
+	exportCmd.SetArgs([]string{"--passphrase-stdin", "--output", path})
+	require.NoError(t, exportCmd.Execute())
+
+	var created []*services.Project
+	var deployed []uuid.UUID
+	app.SetProjectServiceForTesting(&mocks.MockProjectManager{
+		CreateFunc: func(project *services.Project) (*services.Project, error) {
+			project.ID = uuid.New()
+			created = append(created, project)
+			return project, nil
+		},
+		DeployPipingFunc: func(projectID uuid.UUID, options services.DeployOptions) error {
+			assert.Equal(t, services.DeploymentTriggerCLI, options.Trigger)
+			deployed = append(deployed, projectID)
+			return nil
+		},
+	})
+
+	cmd := NewCmdImport()
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetIn(strings.NewReader("correct horse\n"))
+	cmd.SetArgs([]string{"--passphrase-stdin", "--deploy", path})
+
+	require.NoError(t, cmd.Execute())
+	for _, expected := range []string{"Importing 1 project(s) and 0 catalog(s)", "web", "completed successfully"} {
+		assert.Contains(t, stdout.String(), expected)
+	}
+	require.Len(t, created, 1)
+	assert.Equal(t, []string{"DB_PASSWORD=s3cret"}, created[0].Variables)
+	assert.Equal(t, []uuid.UUID{created[0].ID}, deployed)
+}
+
+func TestNewCmdImport_WrongPassphrase(t *testing.T) {
+	setupTestSource(t)
+	path := filepath.Join(t.TempDir(), "oar-backup.yaml")
+
+	exportCmd := NewCmdExport()
+	exportCmd.SetIn(strings.NewReader("correct horse\n"))
+	exportCmd.SetArgs([]string{"--passphrase-stdin", "--output", path})
+	require.NoError(t, exportCmd.Execute())
+
+	cmd := NewCmdImport()
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stdout)
+	cmd.SetIn(strings.NewReader("battery staple\n"))
+	cmd.SetArgs([]string{"--passphrase-stdin", path})
+
+	err := cmd.Execute()
+	assert.ErrorIs(t, err, services.ErrInvalidExport)
+}
+
+func TestNewCmdImport_Errors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "oar-backup.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("version: 1\nprojects:\n  - name: web\n"), 0o600))
+	app.SetProjectServiceForTesting(&mocks.MockProjectManager{
+		CreateFunc: func(project *services.Project) (*services.Project, error) {
+			return nil, assert.AnError
+		},
+	})
+	app.SetCatalogServiceForTesting(&mocks.MockCatalogManager{})
+
+	cmd := NewCmdImport()
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stdout)
+	cmd.SetArgs([]string{path})
+
+	err := cmd.Execute()
+	assert.ErrorContains(t, err, "1 project(s) or catalog(s) could not be imported")
+	assert.Contains(t, stdout.String(), assert.AnError.Error())
+}
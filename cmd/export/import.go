@@ -0,0 +1,99 @@
+package export
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+
+	"github.com/oar-cd/oar/cmd/output"
+	"github.com/oar-cd/oar/cmd/utils"
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/oar-cd/oar/services"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdImport() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Import projects and catalogs from an export",
+		Long: `Import the projects and catalogs of a file written by 'oar export', cloning their repositories.
+
+Projects and catalogs whose name is already taken are skipped, and the notifiers of the projects are
+created along with them. With --deploy, projects that were running when exported are deployed and
+catalogs are synced. What the export left out is listed as warnings.`,
+		Example: `  oar import oar-backup.yaml
+  oar import --deploy oar-backup.yaml`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := runImport(cmd, args)
+			if err != nil {
+				// Silence usage for runtime errors (not argument validation errors)
+				cmd.SilenceUsage = true
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().Bool("deploy", false, "Deploy projects that were running and sync catalogs")
+	cmd.Flags().Bool("passphrase-stdin", false, "Read the passphrase from standard input")
+
+	return cmd
+}
+
+// runImport handles the main logic for importing
+func runImport(cmd *cobra.Command, args []string) error {
+	path := args[0]
+	deploy, _ := cmd.Flags().GetBool("deploy")
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open export: %w", err)
+	}
+	export, err := services.ReadExport(file)
+	_ = file.Close()
+	if err != nil {
+		return err
+	}
+
+	options := services.ImportOptions{}
+	// Exports without secrets have no salt and need no passphrase
+	if export.Salt != "" {
+		if options.Passphrase, err = readPassphrase(cmd, false); err != nil {
+			return err
+		}
+	}
+	if deploy {
+		deployOptions := services.DeployOptions{Trigger: services.DeploymentTriggerCLI}
+		if current, err := user.Current(); err == nil {
+			deployOptions.Actor = current.Username
+		}
+		options.Deploy = &deployOptions
+	}
+
+	if err := output.FprintPlain(
+		cmd, "Importing %d project(s) and %d catalog(s) from '%s'\n", len(export.Projects), len(export.Catalogs), path,
+	); err != nil {
+		return err
+	}
+
+	ctx, cancel := utils.InterruptContext(cmd)
+	defer cancel()
+
+	result, err := app.GetExportService().Import(ctx, export, options)
+	if err != nil {
+		return fmt.Errorf("failed to import: %w", err)
+	}
+
+	out, err := output.PrintImportResult(result)
+	if err != nil {
+		return err
+	}
+	if err := output.FprintPlain(cmd, "%s", out); err != nil {
+		return err
+	}
+
+	if len(result.Errors) > 0 {
+		return fmt.Errorf("%d project(s) or catalog(s) could not be imported", len(result.Errors))
+	}
+	return output.FprintSuccess(cmd, "Import completed successfully\n")
+}
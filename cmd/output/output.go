@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/oar-cd/oar/services"
 
 	"github.com/fatih/color"
@@ -99,8 +101,16 @@ func PrintProjectDetails(project *services.Project, short bool) (string, error)
 		{"Git URL", project.GitURL},
 		{"Git Branch", project.GitBranch},
 	}
+	if len(project.Drift) > 0 {
+		data = append(data, []string{"Drift", formatStringList(project.Drift)})
+	}
+	if project.GitRefType != services.GitRefTypeBranch {
+		data = append(data, []string{"Tracks", project.GitRefDescription()})
+	}
 
 	if !short {
+		data = append(data, []string{"Desired State", formatDesiredState(project.DesiredState)})
+
 		// Git authentication section
 		authMethod, authUser := getAuthenticationInfo(project)
 		data = append(data,
@@ -133,6 +143,36 @@ func PrintProjectDetails(project *services.Project, short bool) (string, error)
 		data = append(data,
 			[]string{"Compose Files", formatStringList(project.ComposeFiles)},
 		)
+		if project.Timeouts != (services.ComposeTimeouts{}) {
+			data = append(data, []string{"Timeouts", formatComposeTimeouts(project.Timeouts)})
+		}
+		if len(project.HealthChecks) > 0 {
+			data = append(data,
+				[]string{"Health Checks", formatStringList(services.FormatHealthChecks(project.HealthChecks))},
+			)
+		}
+		if len(project.PreDeployHooks) > 0 {
+			data = append(data,
+				[]string{"Pre-deploy Hooks", formatStringList(services.FormatHooks(project.PreDeployHooks))},
+			)
+		}
+		if len(project.PostDeployHooks) > 0 {
+			data = append(data,
+				[]string{"Post-deploy Hooks", formatStringList(services.FormatHooks(project.PostDeployHooks))},
+			)
+		}
+		if project.AutoRollback {
+			data = append(data, []string{"Auto Rollback", "enabled"})
+		}
+		if project.BackupVolumes {
+			data = append(data, []string{"Backup Volumes", "enabled"})
+		}
+		if project.RequireApproval {
+			data = append(data, []string{"Require Approval", "enabled"})
+		}
+		if project.WatchImages {
+			data = append(data, []string{"Watch Images", "enabled"})
+		}
 
 		// Environment variables
 		if len(project.Variables) > 0 {
@@ -157,6 +197,9 @@ func PrintProjectDetails(project *services.Project, short bool) (string, error)
 		data = append(data,
 			[]string{"Project ID", project.ID.String()},
 		)
+		if project.CatalogID != nil {
+			data = append(data, []string{"Catalog ID", project.CatalogID.String()})
+		}
 	}
 
 	table, err := PrintTable([]string{}, data)
@@ -235,6 +278,18 @@ func formatCommitDetails(commit string) string {
 	return commit
 }
 
+// formatComposeTimeouts formats the Docker Compose timeouts of a project, showing unset ones as defaults
+func formatComposeTimeouts(timeouts services.ComposeTimeouts) string {
+	format := func(d time.Duration) string {
+		if d == 0 {
+			return "default"
+		}
+		return d.String()
+	}
+	return fmt.Sprintf("pull %s, up %s, wait %s, health %s",
+		format(timeouts.Pull), format(timeouts.Up), format(timeouts.Wait), format(timeouts.Health))
+}
+
 // formatStringList formats a list of strings with proper line breaks and numbering
 func formatStringList(items []string) string {
 	if len(items) == 0 {
@@ -312,8 +367,12 @@ func PrintDeploymentList(deployments []*services.Deployment, projectName string)
 		"ID",
 		"Status",
 		"Commit",
-		"Created At",
-		"Updated At",
+		"Previous",
+		"Trigger",
+		"Actor",
+		"Pull",
+		"Started At",
+		"Duration",
 	}
 	var data [][]string
 	for _, deployment := range deployments {
@@ -322,17 +381,36 @@ func PrintDeploymentList(deployments []*services.Deployment, projectName string)
 
 		// Format commit hash (8 chars like git)
 		commit := formatCommitHash(deployment.CommitHash)
+		if deployment.Rollback {
+			commit += " (rollback)"
+		}
+
+		// Deployments recorded before start times were tracked started when they were created
+		startedAt := deployment.StartedAt
+		if startedAt.IsZero() {
+			startedAt = deployment.CreatedAt
+		}
+
+		actor := deployment.Actor
+		if actor == "" {
+			actor = "-"
+		}
 
-		// Format timestamps
-		createdAt := deployment.CreatedAt.Format("2006-01-02 15:04:05")
-		updatedAt := deployment.UpdatedAt.Format("2006-01-02 15:04:05")
+		pull := "no"
+		if deployment.Pull {
+			pull = "yes"
+		}
 
 		data = append(data, []string{
 			deployment.ID.String(),
 			statusStr,
 			commit,
-			createdAt,
-			updatedAt,
+			formatCommitHash(deployment.PreviousCommit),
+			deployment.Trigger.String(),
+			actor,
+			pull,
+			startedAt.Format("2006-01-02 15:04:05"),
+			formatDeploymentDuration(deployment),
 		})
 	}
 
@@ -344,6 +422,312 @@ func PrintDeploymentList(deployments []*services.Deployment, projectName string)
 	return table, nil
 }
 
+func PrintUserList(users []*services.User) (string, error) {
+	if len(users) == 0 {
+		return PrintMessage(Plain, "No users found."), nil
+	}
+
+	header := []string{
+		"ID",
+		"Username",
+		"Created At",
+		"Updated At",
+	}
+	var data [][]string
+	for _, user := range users {
+		data = append(data, []string{
+			user.ID.String(),
+			user.Username,
+			user.CreatedAt.Format("2006-01-02 15:04:05"),
+			user.UpdatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+
+	table, err := PrintTable(header, data)
+	if err != nil {
+		return "", fmt.Errorf("printing user list table: %w", err)
+	}
+
+	return table, nil
+}
+
+// PrintRoleList formats a user's role bindings as a table, naming projects where known
+func PrintRoleList(bindings []*services.RoleBinding, projectNames map[uuid.UUID]string) (string, error) {
+	if len(bindings) == 0 {
+		return PrintMessage(Plain, "No roles found."), nil
+	}
+
+	header := []string{
+		"Scope",
+		"Project ID",
+		"Project",
+		"Role",
+	}
+	var data [][]string
+	for _, binding := range bindings {
+		if binding.IsGlobal() {
+			data = append(data, []string{"global", "", "", binding.Role.String()})
+			continue
+		}
+		data = append(data, []string{
+			"project",
+			binding.ProjectID.String(),
+			projectNames[*binding.ProjectID],
+			binding.Role.String(),
+		})
+	}
+
+	table, err := PrintTable(header, data)
+	if err != nil {
+		return "", fmt.Errorf("printing role list table: %w", err)
+	}
+
+	return table, nil
+}
+
+// PrintNotifierList formats a project's notifiers as a table, showing destinations without credentials
+func PrintNotifierList(notifiers []*services.Notifier, projectName string) (string, error) {
+	if len(notifiers) == 0 {
+		return PrintMessage(Plain, "No notifiers found for project '%s'.", projectName), nil
+	}
+
+	header := []string{
+		"ID",
+		"Type",
+		"Destination",
+		"Events",
+		"Created At",
+	}
+	var data [][]string
+	for _, notifier := range notifiers {
+		events := "all"
+		if len(notifier.Events) > 0 {
+			names := make([]string, len(notifier.Events))
+			for i, event := range notifier.Events {
+				names[i] = string(event)
+			}
+			events = strings.Join(names, ", ")
+		}
+
+		data = append(data, []string{
+			notifier.ID.String(),
+			string(notifier.Type),
+			notifier.Destination(),
+			events,
+			notifier.CreatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+
+	table, err := PrintTable(header, data)
+	if err != nil {
+		return "", fmt.Errorf("printing notifier list table: %w", err)
+	}
+
+	return table, nil
+}
+
+// PrintCatalogList formats catalogs as a table with the outcome of their last sync, without secret values
+func PrintCatalogList(catalogs []*services.Catalog) (string, error) {
+	if len(catalogs) == 0 {
+		return PrintMessage(Plain, "No catalogs found."), nil
+	}
+
+	header := []string{
+		"ID",
+		"Name",
+		"Git URL",
+		"Branch",
+		"Commit",
+		"Secrets",
+		"Last Synced",
+		"Last Sync Error",
+	}
+	var data [][]string
+	for _, catalog := range catalogs {
+		lastSynced := "never"
+		if catalog.LastSyncedAt != nil {
+			lastSynced = catalog.LastSyncedAt.Format("2006-01-02 15:04:05")
+		}
+		lastSyncError := "-"
+		if catalog.LastSyncError != "" {
+			lastSyncError = truncateString(catalog.LastSyncError, 60)
+		}
+
+		data = append(data, []string{
+			catalog.ID.String(),
+			catalog.Name,
+			truncateString(catalog.GitURL, 50),
+			catalog.GitBranch,
+			formatCommitHash(catalog.LastCommitStr()),
+			strings.Join(catalog.SecretNames(), ", "),
+			lastSynced,
+			lastSyncError,
+		})
+	}
+
+	table, err := PrintTable(header, data)
+	if err != nil {
+		return "", fmt.Errorf("printing catalog list table: %w", err)
+	}
+
+	return table, nil
+}
+
+// PrintCatalogSyncResult formats what syncing a catalog changed
+func PrintCatalogSyncResult(result *services.CatalogSyncResult) (string, error) {
+	data := [][]string{
+		{"Commit", formatCommitDetails(result.Commit)},
+		{"Created", formatStringList(result.Created)},
+		{"Updated", formatStringList(result.Updated)},
+		{"Removed", formatStringList(result.Removed)},
+	}
+	if len(result.Errors) > 0 {
+		data = append(data, []string{"Errors", formatStringList(result.Errors)})
+	}
+
+	table, err := PrintTable([]string{}, data)
+	if err != nil {
+		return "", fmt.Errorf("printing catalog sync result table: %w", err)
+	}
+	return table, nil
+}
+
+// PrintBackupList formats database backups as a table, newest first
+func PrintBackupList(backups []*services.Backup) (string, error) {
+	if len(backups) == 0 {
+		return PrintMessage(Plain, "No backups found."), nil
+	}
+
+	header := []string{
+		"Name",
+		"Size",
+		"Created At",
+	}
+	var data [][]string
+	for _, backup := range backups {
+		data = append(data, []string{
+			backup.Name,
+			formatSize(backup.Size),
+			backup.CreatedAt.Local().Format("2006-01-02 15:04:05"),
+		})
+	}
+
+	table, err := PrintTable(header, data)
+	if err != nil {
+		return "", fmt.Errorf("printing backup list table: %w", err)
+	}
+
+	return table, nil
+}
+
+// PrintVolumeBackupList formats the volume backups of a project as a table, newest first
+func PrintVolumeBackupList(backups []*services.VolumeBackup, projectName string) (string, error) {
+	if len(backups) == 0 {
+		return PrintMessage(Plain, "No volume backups found for project '%s'.", projectName), nil
+	}
+
+	header := []string{
+		"Name",
+		"Volumes",
+		"Size",
+		"Created At",
+	}
+	var data [][]string
+	for _, backup := range backups {
+		data = append(data, []string{
+			backup.Name,
+			strings.Join(backup.Volumes, ", "),
+			formatSize(backup.Size),
+			backup.CreatedAt.Local().Format("2006-01-02 15:04:05"),
+		})
+	}
+
+	table, err := PrintTable(header, data)
+	if err != nil {
+		return "", fmt.Errorf("printing volume backup list table: %w", err)
+	}
+
+	return table, nil
+}
+
+// PrintDeployPreview formats what deploying a project would change: the deployed and target commits, the commits
+// in between and the changes to the rendered configuration as a unified diff
+func PrintDeployPreview(preview *services.DeployPreview) (string, error) {
+	buf := strings.Builder{}
+
+	current := formatCommitHash(preview.CurrentCommit)
+	if preview.CurrentCommit == "" {
+		current = "not deployed"
+	}
+	buf.WriteString(PrintMessage(Plain, "Deployed commit: %s", current))
+	buf.WriteString(PrintMessage(Plain, "Target commit: %s (%s)\n",
+		formatCommitHash(preview.TargetCommit), preview.TargetRef))
+
+	commits, err := PrintCommitList(preview.Commits)
+	if err != nil {
+		return "", err
+	}
+	buf.WriteString(commits)
+
+	if preview.ConfigDiff == "" {
+		buf.WriteString(PrintMessage(Plain, "\nConfiguration: unchanged"))
+		return buf.String(), nil
+	}
+	buf.WriteString(PrintMessage(Plain, "\nConfiguration changes:\n"))
+	buf.WriteString(preview.ConfigDiff)
+	return buf.String(), nil
+}
+
+// PrintCommitList formats the commits a deployment would bring in as a table, newest first
+func PrintCommitList(commits []services.GitCommit) (string, error) {
+	if len(commits) == 0 {
+		return PrintMessage(Plain, "No new commits."), nil
+	}
+
+	header := []string{
+		"Commit",
+		"Author",
+		"Date",
+		"Message",
+	}
+	var data [][]string
+	for _, commit := range commits {
+		data = append(data, []string{
+			formatCommitHash(commit.Hash),
+			commit.Author,
+			commit.Date.Local().Format("2006-01-02 15:04:05"),
+			truncateString(commit.Message, 72),
+		})
+	}
+
+	table, err := PrintTable(header, data)
+	if err != nil {
+		return "", fmt.Errorf("printing commit list table: %w", err)
+	}
+
+	return table, nil
+}
+
+// PrintImportResult formats what importing an export did
+func PrintImportResult(result *services.ImportResult) (string, error) {
+	data := [][]string{
+		{"Created", formatStringList(result.Created)},
+		{"Skipped", formatStringList(result.Skipped)},
+	}
+	if len(result.Errors) > 0 {
+		data = append(data, []string{"Errors", formatStringList(result.Errors)})
+	}
+	if len(result.Warnings) > 0 {
+		data = append(data, []string{"Warnings", formatStringList(result.Warnings)})
+	}
+
+	table, err := PrintTable([]string{}, data)
+	if err != nil {
+		return "", fmt.Errorf("printing import result table: %w", err)
+	}
+	return table, nil
+}
+
 // formatProjectStatus applies color coding to project status
 func formatProjectStatus(status string) string {
 	// If colors are not initialized, return plain status
@@ -363,6 +747,14 @@ func formatProjectStatus(status string) string {
 	}
 }
 
+// formatDesiredState describes whether a project is meant to run
+func formatDesiredState(state services.DesiredState) string {
+	if state == services.DesiredStateNone {
+		return "(not recorded)"
+	}
+	return state.String()
+}
+
 // formatDeploymentStatus applies color coding to deployment status
 func formatDeploymentStatus(status string) string {
 	// If colors are not initialized, return plain status
@@ -373,7 +765,7 @@ func formatDeploymentStatus(status string) string {
 	switch strings.ToLower(status) {
 	case "completed":
 		return maybeColorize(Success, status)
-	case "started":
+	case "started", "pending":
 		return maybeColorize(Warning, status)
 	case "failed":
 		return maybeColorize(Error, status)
@@ -382,6 +774,20 @@ func formatDeploymentStatus(status string) string {
 	}
 }
 
+// formatSize formats a size in bytes with a binary unit, e.g. 1.5 MiB
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	value, exponent := float64(size)/unit, 0
+	for value >= unit && exponent < 4 {
+		value /= unit
+		exponent++
+	}
+	return fmt.Sprintf("%.1f %ciB", value, "KMGTP"[exponent])
+}
+
 // truncateString truncates a string to maxLength with "..." if needed
 func truncateString(s string, maxLength int) string {
 	if len(s) <= maxLength {
@@ -401,6 +807,14 @@ func formatCommitHash(commit string) string {
 	return commit
 }
 
+// formatDeploymentDuration formats how long a deployment ran, rounded to seconds
+func formatDeploymentDuration(deployment *services.Deployment) string {
+	if deployment.FinishedAt == nil || deployment.StartedAt.IsZero() {
+		return "-"
+	}
+	return deployment.Duration().Round(time.Second).String()
+}
+
 // CLI flag for disabling color output
 
 // NoColor is a flag that can be used to disable colored output in the CLI.
@@ -239,6 +239,148 @@ func TestPrintProjectDetails(t *testing.T) {
 			short:    true,
 			expected: []string{"short-project", "stopped", "https://github.com/test/short"},
 		},
+		{
+			name: "project tracking a tag",
+			project: &services.Project{
+				ID:         projectID,
+				Name:       "tag-project",
+				Status:     services.ProjectStatusRunning,
+				GitURL:     "https://github.com/test/tag",
+				GitBranch:  "main",
+				GitRefType: services.GitRefTypeTag,
+				GitRef:     "~1.4",
+				WorkingDir: "/tmp/projects/tag-project",
+			},
+			short:    true,
+			expected: []string{"tag-project", "Tracks", "tag ~1.4"},
+		},
+		{
+			name: "project with timeouts",
+			project: &services.Project{
+				ID:           projectID,
+				Name:         "slow-project",
+				Status:       services.ProjectStatusRunning,
+				GitURL:       "https://github.com/test/slow",
+				WorkingDir:   "/tmp/projects/slow-project",
+				ComposeFiles: []string{"compose.yml"},
+				Timeouts:     services.ComposeTimeouts{Pull: 30 * time.Minute, Wait: 90 * time.Second},
+				CreatedAt:    createdAt,
+				UpdatedAt:    updatedAt,
+			},
+			short:    false,
+			expected: []string{"Timeouts", "pull 30m0s, up default, wait 1m30s, health default"},
+		},
+		{
+			name: "project with health checks",
+			project: &services.Project{
+				ID:           projectID,
+				Name:         "checked-project",
+				Status:       services.ProjectStatusRunning,
+				GitURL:       "https://github.com/test/checked",
+				WorkingDir:   "/tmp/projects/checked-project",
+				ComposeFiles: []string{"compose.yml"},
+				HealthChecks: []services.HealthCheck{{Type: services.HealthCheckTypeTCP, Address: "localhost:5432"}},
+				CreatedAt:    createdAt,
+				UpdatedAt:    updatedAt,
+			},
+			short:    false,
+			expected: []string{"Health Checks", "tcp localhost:5432"},
+		},
+		{
+			name: "project with hooks",
+			project: &services.Project{
+				ID:             projectID,
+				Name:           "hooked-project",
+				Status:         services.ProjectStatusRunning,
+				GitURL:         "https://github.com/test/hooked",
+				WorkingDir:     "/tmp/projects/hooked-project",
+				ComposeFiles:   []string{"compose.yml"},
+				PreDeployHooks: []services.Hook{{Type: services.HookTypeRun, Service: "app", Command: "migrate"}},
+				CreatedAt:      createdAt,
+				UpdatedAt:      updatedAt,
+			},
+			short:    false,
+			expected: []string{"Pre-deploy Hooks", "run app migrate"},
+		},
+		{
+			name: "project with auto rollback",
+			project: &services.Project{
+				ID:           projectID,
+				Name:         "safe-project",
+				Status:       services.ProjectStatusRunning,
+				GitURL:       "https://github.com/test/safe",
+				WorkingDir:   "/tmp/projects/safe-project",
+				ComposeFiles: []string{"compose.yml"},
+				AutoRollback: true,
+				CreatedAt:    createdAt,
+				UpdatedAt:    updatedAt,
+			},
+			short:    false,
+			expected: []string{"Auto Rollback", "enabled"},
+		},
+		{
+			name: "project with volume backups",
+			project: &services.Project{
+				ID:            projectID,
+				Name:          "stateful-project",
+				Status:        services.ProjectStatusRunning,
+				GitURL:        "https://github.com/test/stateful",
+				WorkingDir:    "/tmp/projects/stateful-project",
+				ComposeFiles:  []string{"compose.yml"},
+				BackupVolumes: true,
+				CreatedAt:     createdAt,
+				UpdatedAt:     updatedAt,
+			},
+			short:    false,
+			expected: []string{"Backup Volumes", "enabled"},
+		},
+		{
+			name: "project requiring approval",
+			project: &services.Project{
+				ID:              projectID,
+				Name:            "production-project",
+				Status:          services.ProjectStatusRunning,
+				GitURL:          "https://github.com/test/production",
+				WorkingDir:      "/tmp/projects/production-project",
+				ComposeFiles:    []string{"compose.yml"},
+				RequireApproval: true,
+				CreatedAt:       createdAt,
+				UpdatedAt:       updatedAt,
+			},
+			short:    false,
+			expected: []string{"Require Approval", "enabled"},
+		},
+		{
+			name: "project watching images",
+			project: &services.Project{
+				ID:           projectID,
+				Name:         "latest-project",
+				Status:       services.ProjectStatusRunning,
+				GitURL:       "https://github.com/test/latest",
+				WorkingDir:   "/tmp/projects/latest-project",
+				ComposeFiles: []string{"compose.yml"},
+				WatchImages:  true,
+				CreatedAt:    createdAt,
+				UpdatedAt:    updatedAt,
+			},
+			short:    false,
+			expected: []string{"Watch Images", "enabled"},
+		},
+		{
+			name: "project desired running",
+			project: &services.Project{
+				ID:           projectID,
+				Name:         "crashed-project",
+				Status:       services.ProjectStatusRunning,
+				DesiredState: services.DesiredStateRunning,
+				GitURL:       "https://github.com/test/repo",
+				WorkingDir:   "/tmp/projects/crashed-project",
+				CreatedAt:    createdAt,
+				UpdatedAt:    updatedAt,
+			},
+			short:    false,
+			expected: []string{"Desired State", "running"},
+		},
 		{
 			name: "project with SSH auth",
 			project: &services.Project{
@@ -257,7 +399,7 @@ func TestPrintProjectDetails(t *testing.T) {
 				UpdatedAt: updatedAt,
 			},
 			short:    false,
-			expected: []string{"ssh-project", "error", "git@github.com:test/repo.git", "SSH", "git"},
+			expected: []string{"ssh-project", "error", "(not recorded)", "git@github.com:test/repo.git", "SSH", "git"},
 		},
 	}
 
@@ -604,6 +746,7 @@ func TestPrintDeploymentList(t *testing.T) {
 	deploymentID3 := uuid.New()
 	createdAt := time.Date(2023, 1, 15, 10, 30, 0, 0, time.UTC)
 	updatedAt := time.Date(2023, 1, 16, 14, 45, 0, 0, time.UTC)
+	finishedAt := time.Date(2023, 1, 15, 10, 33, 30, 0, time.UTC)
 
 	// Set up colors for testing
 	InitColors(false)
@@ -636,13 +779,44 @@ func TestPrintDeploymentList(t *testing.T) {
 				"ID",
 				"STATUS",
 				"COMMIT",
-				"CREATED AT",
-				"UPDATED AT",
+				"PREVIOUS",
+				"TRIGGER",
+				"ACTOR",
+				"PULL",
+				"STARTED AT",
+				"DURATION",
 				deploymentID1.String(),
 				"completed",
 				"abc123de",
 				"2023-01-15 10:30:00",
-				"2023-01-16 14:45:00",
+			},
+		},
+		{
+			name: "deployment metadata",
+			deployments: []*services.Deployment{
+				{
+					ID:             deploymentID1,
+					Status:         services.DeploymentStatusCompleted,
+					CommitHash:     "abc123def456",
+					PreviousCommit: "0011223344556677",
+					Pull:           true,
+					Trigger:        services.DeploymentTriggerWebhook,
+					Actor:          "octocat",
+					StartedAt:      createdAt.Add(time.Minute),
+					FinishedAt:     &finishedAt,
+					CreatedAt:      createdAt,
+					UpdatedAt:      updatedAt,
+				},
+			},
+			projectName: "test-project",
+			expected: []string{
+				"abc123de",
+				"00112233",
+				"webhook",
+				"octocat",
+				"yes",
+				"2023-01-15 10:31:00",
+				"2m30s",
 			},
 		},
 		{
@@ -666,6 +840,7 @@ func TestPrintDeploymentList(t *testing.T) {
 					ID:         deploymentID3,
 					Status:     services.DeploymentStatusFailed,
 					CommitHash: "ghi789jkl012",
+					Rollback:   true,
 					CreatedAt:  createdAt,
 					UpdatedAt:  updatedAt,
 				},
@@ -675,23 +850,17 @@ func TestPrintDeploymentList(t *testing.T) {
 				"ID",
 				"STATUS",
 				"COMMIT",
-				"CREATED AT",
-				"UPDATED AT",
 				deploymentID1.String(),
 				"completed",
 				"abc123de",
 				"2023-01-15 10:30:00",
-				"2023-01-16 14:45:00",
 				deploymentID2.String(),
 				"started",
 				"def456gh",
-				"2023-01-15 10:30:00",
-				"2023-01-16 14:45:00",
 				deploymentID3.String(),
 				"failed",
-				"ghi789jk",
-				"2023-01-15 10:30:00",
-				"2023-01-16 14:45:00",
+				"ghi789jk (rollback)",
+				"unknown",
 			},
 		},
 	}
@@ -708,6 +877,66 @@ func TestPrintDeploymentList(t *testing.T) {
 	}
 }
 
+func TestPrintUserList(t *testing.T) {
+	userID := uuid.New()
+	createdAt := time.Date(2023, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	empty, err := PrintUserList([]*services.User{})
+	assert.NoError(t, err)
+	assert.Contains(t, empty, "No users found.")
+
+	result, err := PrintUserList([]*services.User{
+		{ID: userID, Username: "alice", CreatedAt: createdAt, UpdatedAt: createdAt},
+	})
+	assert.NoError(t, err)
+	for _, expected := range []string{"USERNAME", userID.String(), "alice", "2023-01-15 10:30:00"} {
+		assert.Contains(t, result, expected)
+	}
+}
+
+func TestPrintRoleList(t *testing.T) {
+	projectID := uuid.New()
+
+	empty, err := PrintRoleList([]*services.RoleBinding{}, nil)
+	assert.NoError(t, err)
+	assert.Contains(t, empty, "No roles found.")
+
+	result, err := PrintRoleList([]*services.RoleBinding{
+		{ID: uuid.New(), Role: services.RoleViewer},
+		{ID: uuid.New(), ProjectID: &projectID, Role: services.RoleDeployer},
+	}, map[uuid.UUID]string{projectID: "web-app"})
+	assert.NoError(t, err)
+	for _, expected := range []string{"SCOPE", "global", "viewer", "project", projectID.String(), "web-app", "deployer"} {
+		assert.Contains(t, result, expected)
+	}
+}
+
+func TestPrintNotifierList(t *testing.T) {
+	empty, err := PrintNotifierList([]*services.Notifier{}, "web-app")
+	assert.NoError(t, err)
+	assert.Contains(t, empty, "No notifiers found for project 'web-app'.")
+
+	result, err := PrintNotifierList([]*services.Notifier{
+		{
+			ID:     uuid.New(),
+			Type:   services.NotifierTypeSlack,
+			Config: services.NotifierConfig{URL: "https://hooks.slack.com/services/T000/B000/secret"},
+		},
+		{
+			ID:     uuid.New(),
+			Type:   services.NotifierTypeEmail,
+			Events: []services.NotificationEvent{services.NotificationEventDeploymentFailed},
+			Config: services.NotifierConfig{SMTPHost: "mail.example.com", To: []string{"ops@example.com"}},
+		},
+	}, "web-app")
+	assert.NoError(t, err)
+	for _, expected := range []string{"slack", "https://hooks.slack.com", "all", "email", "ops@example.com",
+		"deployment_failed"} {
+		assert.Contains(t, result, expected)
+	}
+	assert.NotContains(t, result, "secret")
+}
+
 func TestFormatDeploymentStatus(t *testing.T) {
 	// Set up colors for testing
 	InitColors(false)
@@ -762,3 +991,144 @@ type mockCommand struct {
 func (m *mockCommand) OutOrStdout() io.Writer {
 	return m.buf
 }
+
+func TestPrintCatalogList(t *testing.T) {
+	empty, err := PrintCatalogList([]*services.Catalog{})
+	assert.NoError(t, err)
+	assert.Contains(t, empty, "No catalogs found.")
+
+	commit := "abc123def456789012345678901234567890abcd"
+	syncedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	result, err := PrintCatalogList([]*services.Catalog{
+		{
+			ID:           uuid.New(),
+			Name:         "platform",
+			GitURL:       "https://github.com/org/platform.git",
+			GitBranch:    "main",
+			Secrets:      []string{"db-password=s3cret"},
+			LastCommit:   &commit,
+			LastSyncedAt: &syncedAt,
+		},
+		{ID: uuid.New(), Name: "staging", LastSyncError: "invalid catalog"},
+	})
+	assert.NoError(t, err)
+	for _, expected := range []string{"platform", "abc123de", "db-password", "2025-03-01 12:00:00", "staging",
+		"never", "invalid catalog"} {
+		assert.Contains(t, result, expected)
+	}
+	assert.NotContains(t, result, "s3cret")
+}
+
+func TestPrintCatalogSyncResult(t *testing.T) {
+	result, err := PrintCatalogSyncResult(&services.CatalogSyncResult{
+		Commit:  "abc123def456789012345678901234567890abcd",
+		Created: []string{"web", "api"},
+		Removed: []string{"docs"},
+	})
+	assert.NoError(t, err)
+	for _, expected := range []string{"abc123de", "1. web", "2. api", "docs", "(none)"} {
+		assert.Contains(t, result, expected)
+	}
+	assert.NotContains(t, result, "Errors")
+
+	result, err = PrintCatalogSyncResult(&services.CatalogSyncResult{Errors: []string{"admin: conflict"}})
+	assert.NoError(t, err)
+	assert.Contains(t, result, "admin: conflict")
+}
+
+func TestPrintImportResult(t *testing.T) {
+	result, err := PrintImportResult(&services.ImportResult{Created: []string{"web", "platform"}})
+	assert.NoError(t, err)
+	for _, expected := range []string{"1. web", "2. platform", "(none)"} {
+		assert.Contains(t, result, expected)
+	}
+	assert.NotContains(t, result, "Errors")
+	assert.NotContains(t, result, "Warnings")
+
+	result, err = PrintImportResult(&services.ImportResult{
+		Errors:   []string{"docs: repository not found"},
+		Warnings: []string{"not exported: users"},
+	})
+	assert.NoError(t, err)
+	assert.Contains(t, result, "docs: repository not found")
+	assert.Contains(t, result, "not exported: users")
+}
+
+func TestPrintBackupList(t *testing.T) {
+	empty, err := PrintBackupList([]*services.Backup{})
+	assert.NoError(t, err)
+	assert.Contains(t, empty, "No backups found.")
+
+	createdAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.Local)
+	result, err := PrintBackupList([]*services.Backup{
+		{Name: "oar-20250301T120000.000Z.db", Size: 3 * 1024 * 1024 / 2, CreatedAt: createdAt},
+	})
+	assert.NoError(t, err)
+	for _, expected := range []string{"oar-20250301T120000.000Z.db", "1.5 MiB", "2025-03-01 12:00:00"} {
+		assert.Contains(t, result, expected)
+	}
+}
+
+func TestPrintVolumeBackupList(t *testing.T) {
+	empty, err := PrintVolumeBackupList([]*services.VolumeBackup{}, "my-app")
+	assert.NoError(t, err)
+	assert.Contains(t, empty, "No volume backups found for project 'my-app'.")
+
+	createdAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.Local)
+	result, err := PrintVolumeBackupList([]*services.VolumeBackup{
+		{Name: "20250301T120000.000Z", Volumes: []string{"data", "uploads"}, Size: 2048, CreatedAt: createdAt},
+	}, "my-app")
+	assert.NoError(t, err)
+	for _, expected := range []string{"20250301T120000.000Z", "data, uploads", "2.0 KiB", "2025-03-01 12:00:00"} {
+		assert.Contains(t, result, expected)
+	}
+}
+
+func TestPrintCommitList(t *testing.T) {
+	empty, err := PrintCommitList([]services.GitCommit{})
+	assert.NoError(t, err)
+	assert.Contains(t, empty, "No new commits.")
+
+	date := time.Date(2025, 3, 1, 12, 0, 0, 0, time.Local)
+	result, err := PrintCommitList([]services.GitCommit{
+		{Hash: "abc123def4567890", Author: "alice", Date: date, Message: "Update nginx"},
+	})
+	assert.NoError(t, err)
+	for _, expected := range []string{"abc123de", "alice", "2025-03-01 12:00:00", "Update nginx"} {
+		assert.Contains(t, result, expected)
+	}
+	assert.NotContains(t, result, "abc123def4567890")
+}
+
+func TestPrintDeployPreview(t *testing.T) {
+	result, err := PrintDeployPreview(&services.DeployPreview{
+		CurrentCommit: "1111111111111111",
+		TargetCommit:  "2222222222222222",
+		TargetRef:     "branch main",
+		Commits:       []services.GitCommit{{Hash: "2222222222222222", Author: "alice", Message: "Update nginx"}},
+		ConfigDiff:    "--- 11111111\n+++ 22222222\n@@ -1 +1 @@\n-image: nginx:1.25\n+image: nginx:1.27\n",
+	})
+	assert.NoError(t, err)
+	for _, expected := range []string{
+		"Deployed commit: 11111111",
+		"Target commit: 22222222 (branch main)",
+		"Update nginx",
+		"Configuration changes:",
+		"+image: nginx:1.27",
+	} {
+		assert.Contains(t, result, expected)
+	}
+
+	result, err = PrintDeployPreview(&services.DeployPreview{TargetCommit: "2222222222222222", TargetRef: "tag v1"})
+	assert.NoError(t, err)
+	assert.Contains(t, result, "Deployed commit: not deployed")
+	assert.Contains(t, result, "No new commits.")
+	assert.Contains(t, result, "Configuration: unchanged")
+}
+
+func TestFormatSize(t *testing.T) {
+	assert.Equal(t, "512 B", formatSize(512))
+	assert.Equal(t, "1.0 KiB", formatSize(1024))
+	assert.Equal(t, "126.0 KiB", formatSize(129024))
+	assert.Equal(t, "2.0 GiB", formatSize(2*1024*1024*1024))
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/oar-cd/oar/cmd/output"
+	"github.com/oar-cd/oar/cmd/utils"
 	"github.com/oar-cd/oar/internal/app"
 	"github.com/oar-cd/oar/services"
 	"github.com/spf13/cobra"
@@ -27,6 +28,39 @@ Basic usage:
   oar project add --git-url https://github.com/user/repo.git \
                   --branch feature/new-feature --compose-file compose.yml
 
+  # Deploy the newest tag matching a pattern or a semver constraint instead of the branch head
+  oar project add --git-url https://github.com/user/repo.git --tag 'v*' --compose-file compose.yml
+  oar project add --git-url https://github.com/user/repo.git --tag '~1.4' --compose-file compose.yml
+
+  # Pin an exact commit
+  oar project add --git-url https://github.com/user/repo.git \
+                  --commit 0123456789abcdef0123456789abcdef01234567 --compose-file compose.yml
+
+  # Give slow images and services more time than the defaults
+  oar project add --git-url https://github.com/user/repo.git --compose-file compose.yml \
+                  --pull-timeout 30m --up-timeout 20m --wait-timeout 15m
+
+  # Fail the deployment unless the app answers and the database accepts connections after starting
+  oar project add --git-url https://github.com/user/repo.git --compose-file compose.yml \
+                  --health-check 'http http://localhost:8080/health 200' --health-check 'tcp localhost:5432'
+
+  # Migrate the database before each deployment and warm the cache after it
+  oar project add --git-url https://github.com/user/repo.git --compose-file compose.yml \
+                  --pre-deploy-hook 'run app ./manage.py migrate' \
+                  --post-deploy-hook 'script ./scripts/warm-cache.sh'
+
+  # Roll back to the last successful deployment when a deployment fails
+  oar project add --git-url https://github.com/user/repo.git --compose-file compose.yml --auto-rollback
+
+  # Back up the named volumes before each deployment
+  oar project add --git-url https://github.com/user/repo.git --compose-file compose.yml --backup-volumes
+
+  # Wait for approval before deploying new commits the watcher or webhooks detect
+  oar project add --git-url https://github.com/user/repo.git --compose-file compose.yml --require-approval
+
+  # Redeploy when images pulled by a tag, such as nginx:latest, have new digests in the registry
+  oar project add --git-url https://github.com/user/repo.git --compose-file compose.yml --watch-images
+
 Authentication examples:
   # HTTP authentication (GitHub token, etc.)
   oar project add --git-url https://github.com/user/repo.git \
@@ -61,14 +95,31 @@ Environment variables:
 	cmd.Flags().StringP("git-url", "u", "", "Git repository URL")
 	cmd.Flags().StringP("name", "n", "", "Custom project name (auto-detected if not specified)")
 	cmd.Flags().StringP("branch", "b", "", "Git branch to use (uses repository default if not specified)")
+	cmd.Flags().String("tag", "", "Deploy the newest tag matching a pattern (v*) or semver constraint (~1.4)")
+	cmd.Flags().String("commit", "", "Deploy a pinned commit (full SHA)")
+	cmd.MarkFlagsMutuallyExclusive("tag", "commit")
 	cmd.Flags().
 		StringArrayP("compose-file", "f", nil, `Docker Compose file path, relative to repository root. Can be used multiple times: --compose-file compose.yml --compose-file docker-compose.override.yml`)
 
+	// Docker Compose timeout flags
+	cmd.Flags().Duration("pull-timeout", 0, "Time allowed for pulling images (default OAR_COMPOSE_PULL_TIMEOUT)")
+	cmd.Flags().Duration("up-timeout", 0, "Time allowed for starting the project (default OAR_COMPOSE_UP_TIMEOUT)")
+	cmd.Flags().Duration("wait-timeout", 0, "Time allowed for becoming healthy (default OAR_COMPOSE_WAIT_TIMEOUT)")
+	cmd.Flags().Duration("health-timeout", 0, "Time allowed for health checks (default OAR_HEALTH_CHECK_TIMEOUT)")
+	cmd.Flags().StringArray("health-check", nil, "Health check after starting: http <url> [<status>] [<text>], "+
+		"tcp <host>:<port> or command <service> <command>. Can be used multiple times")
+	cmd.Flags().StringArray("pre-deploy-hook", nil, "Hook to run before deploying: run <service> <command>, "+
+		"exec <service> <command> or script <command>. Can be used multiple times")
+	cmd.Flags().StringArray("post-deploy-hook", nil, "Hook to run after deploying, like --pre-deploy-hook. "+
+		"Can be used multiple times")
+	cmd.Flags().Bool("auto-rollback", false, "Roll back to the last successful deployment when a deployment fails")
+	cmd.Flags().Bool("backup-volumes", false, "Back up the named volumes of the project before each deployment")
+	cmd.Flags().Bool("require-approval", false, "Wait for approval before deploying new commits the watcher or "+
+		"webhooks detect")
+	cmd.Flags().Bool("watch-images", false, "Redeploy when the registry has new digests of the images of the project")
+
 	// Git authentication flags
-	cmd.Flags().String("git-auth", "", "Git authentication method: http, ssh")
-	cmd.Flags().String("git-username", "", "Git username (for HTTP) or SSH user (for SSH)")
-	cmd.Flags().String("git-password", "", "Git password or token (for HTTP authentication)")
-	cmd.Flags().String("git-ssh-key-file", "", "Path to SSH private key file (for SSH authentication)")
+	utils.AddGitAuthFlags(cmd)
 
 	// Environment variable flags
 	cmd.Flags().
@@ -92,10 +143,23 @@ func runProjectAdd(cmd *cobra.Command) error {
 	gitURL, _ := cmd.Flags().GetString("git-url")
 	name, _ := cmd.Flags().GetString("name")
 	branch, _ := cmd.Flags().GetString("branch")
+	tag, _ := cmd.Flags().GetString("tag")
+	commit, _ := cmd.Flags().GetString("commit")
 	composeFiles, _ := cmd.Flags().GetStringArray("compose-file")
+	pullTimeout, _ := cmd.Flags().GetDuration("pull-timeout")
+	upTimeout, _ := cmd.Flags().GetDuration("up-timeout")
+	waitTimeout, _ := cmd.Flags().GetDuration("wait-timeout")
+	healthTimeout, _ := cmd.Flags().GetDuration("health-timeout")
+	healthCheckFlags, _ := cmd.Flags().GetStringArray("health-check")
+	preDeployHookFlags, _ := cmd.Flags().GetStringArray("pre-deploy-hook")
+	postDeployHookFlags, _ := cmd.Flags().GetStringArray("post-deploy-hook")
+	autoRollback, _ := cmd.Flags().GetBool("auto-rollback")
+	backupVolumes, _ := cmd.Flags().GetBool("backup-volumes")
+	requireApproval, _ := cmd.Flags().GetBool("require-approval")
+	watchImages, _ := cmd.Flags().GetBool("watch-images")
 
 	// Build Git authentication config
-	gitAuth, err := buildGitAuthFromFlags(cmd)
+	gitAuth, err := utils.GitAuthFromFlags(cmd)
 	if err != nil {
 		return fmt.Errorf("invalid authentication configuration: %w", err)
 	}
@@ -106,10 +170,44 @@ func runProjectAdd(cmd *cobra.Command) error {
 		return fmt.Errorf("invalid environment variables: %w", err)
 	}
 
+	healthChecks, err := services.ParseHealthChecks(healthCheckFlags)
+	if err != nil {
+		return err
+	}
+	preDeployHooks, err := services.ParseHooks(preDeployHookFlags)
+	if err != nil {
+		return err
+	}
+	postDeployHooks, err := services.ParseHooks(postDeployHookFlags)
+	if err != nil {
+		return err
+	}
+
 	// Create project struct from CLI input
 	project := services.NewProject(name, gitURL, composeFiles, variables)
 	project.GitBranch = branch
 	project.GitAuth = gitAuth
+	project.Timeouts = services.ComposeTimeouts{
+		Pull:   pullTimeout,
+		Up:     upTimeout,
+		Wait:   waitTimeout,
+		Health: healthTimeout,
+	}
+	project.HealthChecks = healthChecks
+	project.PreDeployHooks = preDeployHooks
+	project.PostDeployHooks = postDeployHooks
+	project.AutoRollback = autoRollback
+	project.BackupVolumes = backupVolumes
+	project.RequireApproval = requireApproval
+	project.WatchImages = watchImages
+	switch {
+	case tag != "":
+		project.GitRefType = services.GitRefTypeTag
+		project.GitRef = tag
+	case commit != "":
+		project.GitRefType = services.GitRefTypeCommit
+		project.GitRef = commit
+	}
 
 	// Call service
 	createdProject, err := app.GetProjectService().Create(&project)
@@ -130,64 +228,6 @@ func runProjectAdd(cmd *cobra.Command) error {
 	return nil
 }
 
-// buildGitAuthFromFlags constructs GitAuthConfig from command flags
-func buildGitAuthFromFlags(cmd *cobra.Command) (*services.GitAuthConfig, error) {
-	authMethod, _ := cmd.Flags().GetString("git-auth")
-
-	// No authentication specified
-	if authMethod == "" {
-		return nil, nil
-	}
-
-	switch authMethod {
-	case "http":
-		username, _ := cmd.Flags().GetString("git-username")
-		password, _ := cmd.Flags().GetString("git-password")
-
-		if username == "" && password == "" {
-			return nil, fmt.Errorf("HTTP authentication requires --git-username and --git-password")
-		}
-
-		return &services.GitAuthConfig{
-			HTTPAuth: &services.GitHTTPAuthConfig{
-				Username: username,
-				Password: password,
-			},
-		}, nil
-
-	case "ssh":
-		sshUser, _ := cmd.Flags().GetString("git-username")
-		keyFile, _ := cmd.Flags().GetString("git-ssh-key-file")
-
-		if keyFile == "" {
-			return nil, fmt.Errorf("SSH authentication requires --git-ssh-key-file")
-		}
-
-		// Read private key from file
-		privateKeyBytes, err := os.ReadFile(keyFile)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read SSH key file %s: %w", keyFile, err)
-		}
-
-		privateKey := string(privateKeyBytes)
-
-		// Default SSH user to "git" if not specified
-		if sshUser == "" {
-			sshUser = "git"
-		}
-
-		return &services.GitAuthConfig{
-			SSHAuth: &services.GitSSHAuthConfig{
-				PrivateKey: privateKey,
-				User:       sshUser,
-			},
-		}, nil
-
-	default:
-		return nil, fmt.Errorf("invalid authentication method %q, must be 'http' or 'ssh'", authMethod)
-	}
-}
-
 // buildVariablesFromFlags constructs environment variables from command flags
 func buildVariablesFromFlags(cmd *cobra.Command) ([]string, error) {
 	var variables []string
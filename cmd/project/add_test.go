@@ -183,6 +183,76 @@ func TestProjectAddValidation(t *testing.T) {
 			},
 			expectedError: "git URL is required",
 		},
+		{
+			name: "Tag and commit together should fail",
+			args: []string{
+				"--git-url",
+				"https://github.com/test/repo.git",
+				"--compose-file",
+				"docker-compose.yml",
+				"--tag",
+				"v*",
+				"--commit",
+				"0123456789abcdef0123456789abcdef01234567",
+			},
+			expectedError: "if any flags in the group [tag commit] are set none of the others can be",
+		},
+		{
+			name: "Short commit SHA should fail",
+			args: []string{
+				"--git-url",
+				"https://github.com/test/repo.git",
+				"--name",
+				"test-project",
+				"--compose-file",
+				"docker-compose.yml",
+				"--commit",
+				"0123456",
+			},
+			expectedError: "is not a full commit SHA",
+		},
+		{
+			name: "Negative timeout should fail",
+			args: []string{
+				"--git-url",
+				"https://github.com/test/repo.git",
+				"--name",
+				"test-project",
+				"--compose-file",
+				"docker-compose.yml",
+				"--up-timeout",
+				"-5m",
+			},
+			expectedError: "up timeout must not be negative",
+		},
+		{
+			name: "Invalid health check should fail",
+			args: []string{
+				"--git-url",
+				"https://github.com/test/repo.git",
+				"--name",
+				"test-project",
+				"--compose-file",
+				"docker-compose.yml",
+				"--health-check",
+				"tcp localhost",
+			},
+			expectedError: `invalid health check: "localhost" is not a host:port address`,
+		},
+		{
+			name: "Invalid hook should fail",
+			args: []string{
+				"--git-url",
+				"https://github.com/test/repo.git",
+				"--name",
+				"test-project",
+				"--compose-file",
+				"docker-compose.yml",
+				"--pre-deploy-hook",
+				"migrate",
+			},
+			expectedError: `invalid hook: "migrate" must start with run, exec or script`,
+		},
 	}
 
 	for _, tt := range tests {
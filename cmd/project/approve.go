@@ -0,0 +1,111 @@
+package project
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/oar-cd/oar/cmd/output"
+	"github.com/oar-cd/oar/cmd/utils"
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/oar-cd/oar/services"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdProjectApprove() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "approve <project-id|name> [deployment-id]",
+		Short: "Approve a pending deployment of a project",
+		Long: `Check out the commit of a deployment pending approval and deploy it using Docker Compose.
+Projects that require approval get a pending deployment when the watcher or a webhook
+detects a new commit, instead of being deployed. Without a deployment ID, the pending
+deployment of the project is approved.`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := runProjectApprove(cmd, args)
+			if err != nil {
+				// Silence usage for runtime errors (not argument validation errors)
+				cmd.SilenceUsage = true
+			}
+			return err
+		},
+	}
+	return cmd
+}
+
+// runProjectApprove handles the main logic for approving a pending deployment
+func runProjectApprove(cmd *cobra.Command, args []string) error {
+	projectService := app.GetProjectService()
+
+	project, err := findProject(projectService, args[0])
+	if err != nil {
+		return err
+	}
+	deploymentID, err := findPendingDeployment(projectService, project, args[1:])
+	if err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("Approving deployment %s of project '%s'\n", deploymentID, project.Name)
+	if err := output.FprintPlain(cmd, "%s", message); err != nil {
+		return err
+	}
+
+	// Ctrl-C cancels the deployment
+	ctx, cancel := utils.InterruptContext(cmd)
+	defer cancel()
+
+	if err := projectService.ApprovePiping(ctx, project.ID, deploymentID, deployOptions(false)); err != nil {
+		return err
+	}
+
+	updatedProject, err := projectService.Get(project.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get updated project status: %w", err)
+	}
+
+	if err := output.FprintSuccess(cmd, "\nProject '%s' deployed successfully\n", updatedProject.Name); err != nil {
+		return err
+	}
+	if err := output.FprintPlain(cmd, "Status: %s", updatedProject.Status.String()); err != nil {
+		return err
+	}
+
+	if updatedProject.LastCommit != nil {
+		shortCommit := *updatedProject.LastCommit
+		if len(shortCommit) > 8 {
+			shortCommit = shortCommit[:8]
+		}
+		if err := output.FprintPlain(cmd, "Deployed commit: %s", shortCommit); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// findPendingDeployment parses the deployment ID in args, or finds the deployment of project pending approval if
+// there is none
+func findPendingDeployment(
+	projectService services.ProjectManager,
+	project *services.Project,
+	args []string,
+) (uuid.UUID, error) {
+	if len(args) > 0 {
+		deploymentID, err := uuid.Parse(args[0])
+		if err != nil {
+			return uuid.Nil, fmt.Errorf("invalid deployment ID: %w", err)
+		}
+		return deploymentID, nil
+	}
+
+	deployments, err := projectService.ListDeployments(project.ID)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	for _, deployment := range deployments {
+		if deployment.Status == services.DeploymentStatusPending {
+			return deployment.ID, nil
+		}
+	}
+	return uuid.Nil, fmt.Errorf("project '%s' has no deployment pending approval", project.Name)
+}
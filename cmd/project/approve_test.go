@@ -0,0 +1,113 @@
+package project
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/oar-cd/oar/services"
+	"github.com/oar-cd/oar/testing/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCmdProjectApprove(t *testing.T) {
+	testProjectID := uuid.New()
+	deployedCommit := "abc123def456"
+	testProject := &services.Project{
+		ID:         testProjectID,
+		Name:       "test-project",
+		Status:     services.ProjectStatusRunning,
+		LastCommit: &deployedCommit,
+	}
+	pendingID := uuid.New()
+	deployments := []*services.Deployment{
+		{ID: pendingID, ProjectID: testProjectID, Status: services.DeploymentStatusPending},
+		{ID: uuid.New(), ProjectID: testProjectID, Status: services.DeploymentStatusCompleted},
+	}
+	otherID := uuid.New()
+
+	tests := []struct {
+		name                 string
+		args                 []string
+		deployments          []*services.Deployment
+		mockApproveError     error
+		expectError          string
+		expectedDeploymentID uuid.UUID
+	}{
+		{
+			name:                 "pending deployment of the project",
+			args:                 []string{"test-project"},
+			deployments:          deployments,
+			expectedDeploymentID: pendingID,
+		},
+		{
+			name:                 "deployment by ID",
+			args:                 []string{testProjectID.String(), otherID.String()},
+			expectedDeploymentID: otherID,
+		},
+		{
+			name:        "no pending deployment",
+			args:        []string{"test-project"},
+			deployments: deployments[1:],
+			expectError: "project 'test-project' has no deployment pending approval",
+		},
+		{
+			name:        "invalid deployment ID",
+			args:        []string{"test-project", "not-a-uuid"},
+			expectError: "invalid deployment ID",
+		},
+		{
+			name:             "approve error",
+			args:             []string{"test-project", otherID.String()},
+			mockApproveError: services.ErrDeploymentNotPending,
+			expectError:      "deployment is not pending approval",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var approved uuid.UUID
+			mockService := &mocks.MockProjectManager{
+				ListFunc: func() ([]*services.Project, error) {
+					return []*services.Project{testProject}, nil
+				},
+				GetFunc: func(id uuid.UUID) (*services.Project, error) {
+					if id != testProjectID {
+						return nil, errors.New("record not found")
+					}
+					return testProject, nil
+				},
+				ListDeploymentsFunc: func(projectID uuid.UUID) ([]*services.Deployment, error) {
+					return tt.deployments, nil
+				},
+				ApprovePipingFunc: func(projectID, deploymentID uuid.UUID, options services.DeployOptions) error {
+					assert.Equal(t, services.DeploymentTriggerCLI, options.Trigger)
+					approved = deploymentID
+					return tt.mockApproveError
+				},
+			}
+			app.SetProjectServiceForTesting(mockService)
+
+			cmd := NewCmdProjectApprove()
+			var stdout bytes.Buffer
+			cmd.SetOut(&stdout)
+			cmd.SetErr(&stdout)
+			cmd.SetArgs(tt.args)
+
+			err := cmd.Execute()
+
+			if tt.expectError != "" {
+				assert.ErrorContains(t, err, tt.expectError)
+				assert.True(t, cmd.SilenceUsage)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedDeploymentID, approved)
+			assert.Contains(t, stdout.String(), "deployed successfully")
+			assert.Contains(t, stdout.String(), "Deployed commit: abc123de")
+		})
+	}
+}
@@ -2,10 +2,13 @@ package project
 
 import (
 	"fmt"
+	"os/user"
 
 	"github.com/google/uuid"
 	"github.com/oar-cd/oar/cmd/output"
+	"github.com/oar-cd/oar/cmd/utils"
 	"github.com/oar-cd/oar/internal/app"
+	"github.com/oar-cd/oar/services"
 	"github.com/spf13/cobra"
 )
 
@@ -14,7 +17,10 @@ func NewCmdProjectDeploy() *cobra.Command {
 		Use:   "deploy <project-id>",
 		Short: "Deploy or update a project",
 		Long: `Pull the latest changes from Git and deploy the project using Docker Compose.
-This will update running containers with the latest configuration.`,
+This will update running containers with the latest configuration.
+
+With --preview, the commits the deployment would bring in and how the rendered
+Docker Compose configuration would change are shown, without deploying.`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			err := runProjectDeploy(cmd, args)
@@ -27,6 +33,7 @@ This will update running containers with the latest configuration.`,
 	}
 
 	cmd.Flags().Bool("pull", true, "Pull latest Git changes before deployment")
+	cmd.Flags().Bool("preview", false, "Show what the deployment would change without deploying")
 	return cmd
 }
 
@@ -39,6 +46,7 @@ func runProjectDeploy(cmd *cobra.Command, args []string) error {
 
 	// Get flags
 	pull, _ := cmd.Flags().GetBool("pull")
+	preview, _ := cmd.Flags().GetBool("preview")
 
 	// Get services
 	projectService := app.GetProjectService()
@@ -49,6 +57,10 @@ func runProjectDeploy(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to find project %s: %w", projectID, err)
 	}
 
+	if preview {
+		return runProjectDeployPreview(cmd, projectService, project, pull)
+	}
+
 	// Display deployment info
 	if err := output.FprintPlain(cmd, "Starting deployment for project '%s'\n", project.Name); err != nil {
 		return err
@@ -74,8 +86,12 @@ func runProjectDeploy(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Ctrl-C cancels the deployment
+	ctx, cancel := utils.InterruptContext(cmd)
+	defer cancel()
+
 	// Deploy project with direct stdout/stderr piping
-	err = projectService.DeployPiping(projectID, pull)
+	err = projectService.DeployPiping(ctx, projectID, deployOptions(pull))
 	if err != nil {
 		return err
 	}
@@ -105,3 +121,43 @@ func runProjectDeploy(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// runProjectDeployPreview shows the commits deploying a project would bring in and the changes to its rendered
+// configuration
+func runProjectDeployPreview(
+	cmd *cobra.Command,
+	projectService services.ProjectManager,
+	project *services.Project,
+	pull bool,
+) error {
+	if pull {
+		if err := output.FprintPlain(cmd, "Fetching latest changes from Git...\n"); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := utils.InterruptContext(cmd)
+	defer cancel()
+
+	options := services.DeployOptions{Pull: pull, Trigger: services.DeploymentTriggerCLI}
+	preview, err := projectService.PreviewDeploy(ctx, project.ID, options)
+	if err != nil {
+		return fmt.Errorf("failed to preview deployment of project %s: %w", project.Name, err)
+	}
+
+	details, err := output.PrintDeployPreview(preview)
+	if err != nil {
+		return err
+	}
+	return output.FprintPlain(cmd, "%s", details)
+}
+
+// deployOptions returns the options of deployments started from the CLI. Unless Oar acts as a user (OAR_USER),
+// who records the deployment itself, the operating system user is recorded as the actor.
+func deployOptions(pull bool) services.DeployOptions {
+	options := services.DeployOptions{Pull: pull, Trigger: services.DeploymentTriggerCLI}
+	if current, err := user.Current(); err == nil {
+		options.Actor = current.Username
+	}
+	return options
+}
@@ -2,6 +2,7 @@ package project
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -112,7 +113,8 @@ func TestNewCmdProjectDeploy(t *testing.T) {
 					}
 					return tt.mockProject, nil
 				},
-				DeployPipingFunc: func(projectID uuid.UUID, pull bool) error {
+				DeployPipingFunc: func(projectID uuid.UUID, options services.DeployOptions) error {
+					assert.Equal(t, services.DeploymentTriggerCLI, options.Trigger)
 					return tt.mockDeployError
 				},
 			}
@@ -178,10 +180,110 @@ func TestNewCmdProjectDeployCommand(t *testing.T) {
 	assert.NotNil(t, pullFlag)
 	assert.Equal(t, "true", pullFlag.DefValue) // Default should be true
 
+	previewFlag := cmd.Flags().Lookup("preview")
+	assert.NotNil(t, previewFlag)
+	assert.Equal(t, "false", previewFlag.DefValue)
+
 	// Verify the command can be found by name
 	assert.Equal(t, "deploy", cmd.Name())
 }
 
+func TestCmdProjectDeployPreview(t *testing.T) {
+	testProject := &services.Project{
+		ID:     uuid.New(),
+		Name:   "test-project",
+		GitURL: "https://github.com/test/project.git",
+		Status: services.ProjectStatusRunning,
+	}
+	target := "2222222222222222222222222222222222222222"
+
+	tests := []struct {
+		name         string
+		args         []string
+		previewError error
+		expectPull   bool
+		expectError  bool
+		expectedText []string
+	}{
+		{
+			name:       "preview with pull",
+			args:       []string{testProject.ID.String(), "--preview"},
+			expectPull: true,
+			expectedText: []string{
+				"Fetching latest changes from Git...",
+				"Deployed commit: 11111111",
+				"Target commit: 22222222 (branch main)",
+				"Update nginx",
+				"+image: nginx:1.27",
+			},
+		},
+		{
+			name:         "preview without pull",
+			args:         []string{testProject.ID.String(), "--preview", "--pull=false"},
+			expectedText: []string{"Target commit: 22222222"},
+		},
+		{
+			name:         "preview error",
+			args:         []string{testProject.ID.String(), "--preview"},
+			previewError: errors.New("failed to fetch from remote"),
+			expectPull:   true,
+			expectError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.MockProjectManager{
+				GetFunc: func(id uuid.UUID) (*services.Project, error) {
+					return testProject, nil
+				},
+				PreviewDeployFunc: func(
+					ctx context.Context,
+					projectID uuid.UUID,
+					options services.DeployOptions,
+				) (*services.DeployPreview, error) {
+					assert.Equal(t, testProject.ID, projectID)
+					assert.Equal(t, tt.expectPull, options.Pull)
+					assert.Equal(t, services.DeploymentTriggerCLI, options.Trigger)
+					if tt.previewError != nil {
+						return nil, tt.previewError
+					}
+					return &services.DeployPreview{
+						CurrentCommit: "1111111111111111111111111111111111111111",
+						TargetCommit:  target,
+						TargetRef:     "branch main",
+						Commits:       []services.GitCommit{{Hash: target, Author: "alice", Message: "Update nginx"}},
+						ConfigDiff: "--- 11111111\n+++ 22222222\n@@ -1 +1 @@\n" +
+							"-image: nginx:1.25\n+image: nginx:1.27\n",
+					}, nil
+				},
+				DeployPipingFunc: func(projectID uuid.UUID, options services.DeployOptions) error {
+					t.Error("DeployPiping() called for a preview")
+					return nil
+				},
+			}
+			app.SetProjectServiceForTesting(mockService)
+
+			cmd := NewCmdProjectDeploy()
+			var stdout, stderr bytes.Buffer
+			cmd.SetOut(&stdout)
+			cmd.SetErr(&stderr)
+			cmd.SetArgs(tt.args)
+
+			err := cmd.Execute()
+			if tt.expectError {
+				assert.ErrorContains(t, err, "failed to preview deployment of project test-project")
+				return
+			}
+			assert.NoError(t, err)
+			for _, expected := range tt.expectedText {
+				assert.Contains(t, stdout.String(), expected)
+			}
+			assert.NotContains(t, stdout.String(), "deployed successfully")
+		})
+	}
+}
+
 func TestCmdProjectDeployErrorHandling(t *testing.T) {
 	testProjectID := uuid.New()
 	testProject := &services.Project{
@@ -252,7 +354,8 @@ func TestCmdProjectDeployErrorHandling(t *testing.T) {
 					}
 					return tt.mockProject, nil
 				},
-				DeployPipingFunc: func(projectID uuid.UUID, pull bool) error {
+				DeployPipingFunc: func(projectID uuid.UUID, options services.DeployOptions) error {
+					assert.Equal(t, services.DeploymentTriggerCLI, options.Trigger)
 					return tt.mockDeployError
 				},
 			}
@@ -0,0 +1,232 @@
+package project
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/oar-cd/oar/cmd/output"
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/oar-cd/oar/services"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdProjectNotifier() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "notifier",
+		Short: "Manage deployment notifications of a project",
+		Long: `Manage the notifiers of a project. Notifiers send a message with the commit and the
+last lines of the deployment output when a deployment starts, succeeds or fails, or when
+drift is detected.
+
+Supported types are webhook (JSON POST), slack (Slack-compatible incoming webhook),
+email (SMTP), ntfy and gotify. Managing notifiers requires the admin role on the project.`,
+	}
+
+	cmd.AddCommand(NewCmdProjectNotifierList())
+	cmd.AddCommand(NewCmdProjectNotifierAdd())
+	cmd.AddCommand(NewCmdProjectNotifierRemove())
+	cmd.AddCommand(NewCmdProjectNotifierTest())
+	return cmd
+}
+
+func NewCmdProjectNotifierList() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list <project-id|name>",
+		Short: "List the notifiers of a project",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := runProjectNotifierList(cmd, args)
+			if err != nil {
+				// Silence usage for runtime errors (not argument validation errors)
+				cmd.SilenceUsage = true
+			}
+			return err
+		},
+	}
+}
+
+func NewCmdProjectNotifierAdd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add <project-id|name>",
+		Short: "Add a notifier to a project",
+		Example: `  # Post to a Slack channel when a deployment fails
+  oar project notifier add my-app --type slack --url https://hooks.slack.com/services/... \
+    --event deployment_failed
+
+  # Publish every event to an ntfy topic
+  oar project notifier add my-app --type ntfy --url https://ntfy.sh/my-deployments
+
+  # Push to a Gotify server
+  oar project notifier add my-app --type gotify --url https://gotify.example.com --token AbCdEf
+
+  # Send emails through an SMTP server
+  oar project notifier add my-app --type email --smtp-host smtp.example.com \
+    --smtp-username oar --smtp-password secret --from oar@example.com --to ops@example.com`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := runProjectNotifierAdd(cmd, args)
+			if err != nil {
+				// Silence usage for runtime errors (not argument validation errors)
+				cmd.SilenceUsage = true
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().String("type", "", "Notifier type: webhook, slack, email, ntfy or gotify")
+	cmd.Flags().String("url", "", "Webhook URL, ntfy topic URL or Gotify server URL")
+	cmd.Flags().String("token", "", "ntfy access token or Gotify application token")
+	cmd.Flags().String("smtp-host", "", "SMTP server host")
+	cmd.Flags().Int("smtp-port", 587, "SMTP server port")
+	cmd.Flags().String("smtp-username", "", "SMTP username")
+	cmd.Flags().String("smtp-password", "", "SMTP password")
+	cmd.Flags().String("from", "", "Email sender address")
+	cmd.Flags().StringSlice("to", []string{}, "Email recipient address (can be specified multiple times)")
+	cmd.Flags().StringSlice("event", []string{},
+		"Event to notify about: deployment_started, deployment_succeeded, deployment_failed, drift_detected or "+
+			"approval_required (can be specified multiple times, default all)")
+	_ = cmd.MarkFlagRequired("type")
+
+	return cmd
+}
+
+func NewCmdProjectNotifierRemove() *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove <project-id|name> <notifier-id>",
+		Short: "Remove a notifier from a project",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := runProjectNotifierRemove(cmd, args)
+			if err != nil {
+				// Silence usage for runtime errors (not argument validation errors)
+				cmd.SilenceUsage = true
+			}
+			return err
+		},
+	}
+}
+
+func NewCmdProjectNotifierTest() *cobra.Command {
+	return &cobra.Command{
+		Use:   "test <project-id|name> <notifier-id>",
+		Short: "Send a test notification",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := runProjectNotifierTest(cmd, args)
+			if err != nil {
+				// Silence usage for runtime errors (not argument validation errors)
+				cmd.SilenceUsage = true
+			}
+			return err
+		},
+	}
+}
+
+// runProjectNotifierList handles the main logic for listing a project's notifiers
+func runProjectNotifierList(cmd *cobra.Command, args []string) error {
+	project, err := findProject(app.GetProjectService(), args[0])
+	if err != nil {
+		return err
+	}
+
+	notifiers, err := app.GetNotificationService().ListNotifiers(project.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list notifiers: %w", err)
+	}
+
+	out, err := output.PrintNotifierList(notifiers, project.Name)
+	if err != nil {
+		return err
+	}
+
+	return output.FprintPlain(cmd, "%s", out)
+}
+
+// runProjectNotifierAdd handles the main logic for adding a notifier to a project
+func runProjectNotifierAdd(cmd *cobra.Command, args []string) error {
+	notifierType, _ := cmd.Flags().GetString("type")
+	eventNames, _ := cmd.Flags().GetStringSlice("event")
+
+	parsedType, err := services.ParseNotifierType(notifierType)
+	if err != nil {
+		return err
+	}
+
+	var events []services.NotificationEvent
+	for _, name := range eventNames {
+		event, err := services.ParseNotificationEvent(name)
+		if err != nil {
+			return err
+		}
+		events = append(events, event)
+	}
+
+	var config services.NotifierConfig
+	config.URL, _ = cmd.Flags().GetString("url")
+	config.Token, _ = cmd.Flags().GetString("token")
+	config.SMTPHost, _ = cmd.Flags().GetString("smtp-host")
+	config.SMTPPort, _ = cmd.Flags().GetInt("smtp-port")
+	config.SMTPUsername, _ = cmd.Flags().GetString("smtp-username")
+	config.SMTPPassword, _ = cmd.Flags().GetString("smtp-password")
+	config.From, _ = cmd.Flags().GetString("from")
+	config.To, _ = cmd.Flags().GetStringSlice("to")
+
+	project, err := findProject(app.GetProjectService(), args[0])
+	if err != nil {
+		return err
+	}
+
+	notifier := &services.Notifier{
+		ProjectID: project.ID,
+		Type:      parsedType,
+		Events:    events,
+		Config:    config,
+	}
+	if err := app.GetNotificationService().AddNotifier(notifier); err != nil {
+		return fmt.Errorf("failed to add notifier: %w", err)
+	}
+
+	return output.FprintSuccess(cmd, "Added %s notifier %s to project '%s'\n", notifier.Type, notifier.ID, project.Name)
+}
+
+// runProjectNotifierRemove handles the main logic for removing a notifier from a project
+func runProjectNotifierRemove(cmd *cobra.Command, args []string) error {
+	project, notifierID, err := findProjectNotifier(args)
+	if err != nil {
+		return err
+	}
+
+	if err := app.GetNotificationService().RemoveNotifier(project.ID, notifierID); err != nil {
+		return fmt.Errorf("failed to remove notifier %s: %w", notifierID, err)
+	}
+
+	return output.FprintSuccess(cmd, "Removed notifier %s from project '%s'\n", notifierID, project.Name)
+}
+
+// runProjectNotifierTest handles the main logic for sending a test notification
+func runProjectNotifierTest(cmd *cobra.Command, args []string) error {
+	project, notifierID, err := findProjectNotifier(args)
+	if err != nil {
+		return err
+	}
+
+	if err := app.GetNotificationService().TestNotifier(project.ID, notifierID); err != nil {
+		return fmt.Errorf("failed to send test notification: %w", err)
+	}
+
+	return output.FprintSuccess(cmd, "Test notification sent by notifier %s\n", notifierID)
+}
+
+// findProjectNotifier resolves the project and notifier ID arguments of notifier commands
+func findProjectNotifier(args []string) (*services.Project, uuid.UUID, error) {
+	notifierID, err := uuid.Parse(args[1])
+	if err != nil {
+		return nil, uuid.Nil, fmt.Errorf("invalid notifier ID '%s': must be a valid UUID", args[1])
+	}
+
+	project, err := findProject(app.GetProjectService(), args[0])
+	if err != nil {
+		return nil, uuid.Nil, err
+	}
+	return project, notifierID, nil
+}
@@ -0,0 +1,216 @@
+package project
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/oar-cd/oar/services"
+	"github.com/oar-cd/oar/testing/mocks"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupNotifierTestProject(t *testing.T) *services.Project {
+	t.Helper()
+	testProject := &services.Project{ID: uuid.New(), Name: "test-project"}
+	app.SetProjectServiceForTesting(&mocks.MockProjectManager{
+		ListFunc: func() ([]*services.Project, error) {
+			return []*services.Project{testProject}, nil
+		},
+	})
+	return testProject
+}
+
+func TestNewCmdProjectNotifierAdd(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		mockAddError  error
+		expectError   string
+		expectedType  services.NotifierType
+		expectEvents  []services.NotificationEvent
+		expectConfig  services.NotifierConfig
+		expectedInOut string
+	}{
+		{
+			name: "slack notifier for failures",
+			args: []string{
+				"test-project", "--type", "slack", "--url", "https://hooks.example.com/x",
+				"--event", "deployment_failed",
+			},
+			expectedType:  services.NotifierTypeSlack,
+			expectEvents:  []services.NotificationEvent{services.NotificationEventDeploymentFailed},
+			expectConfig:  services.NotifierConfig{URL: "https://hooks.example.com/x", SMTPPort: 587, To: []string{}},
+			expectedInOut: "Added slack notifier",
+		},
+		{
+			name: "email notifier",
+			args: []string{
+				"test-project", "--type", "email", "--smtp-host", "mail.example.com", "--smtp-port", "25",
+				"--from", "oar@example.com", "--to", "a@example.com", "--to", "b@example.com",
+			},
+			expectedType: services.NotifierTypeEmail,
+			expectConfig: services.NotifierConfig{
+				SMTPHost: "mail.example.com",
+				SMTPPort: 25,
+				From:     "oar@example.com",
+				To:       []string{"a@example.com", "b@example.com"},
+			},
+			expectedInOut: "Added email notifier",
+		},
+		{
+			name:        "missing type",
+			args:        []string{"test-project", "--url", "https://hooks.example.com/x"},
+			expectError: `required flag(s) "type" not set`,
+		},
+		{
+			name:        "invalid type",
+			args:        []string{"test-project", "--type", "pager"},
+			expectError: "invalid notifier type",
+		},
+		{
+			name:        "invalid event",
+			args:        []string{"test-project", "--type", "ntfy", "--event", "deployed"},
+			expectError: "invalid notification event",
+		},
+		{
+			name:         "rejected by service",
+			args:         []string{"test-project", "--type", "gotify", "--url", "https://gotify.example.com"},
+			mockAddError: services.ErrInvalidNotifier,
+			expectError:  "failed to add notifier: invalid notifier",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testProject := setupNotifierTestProject(t)
+			var added *services.Notifier
+			app.SetNotificationServiceForTesting(&mocks.MockNotificationManager{
+				AddNotifierFunc: func(notifier *services.Notifier) error {
+					added = notifier
+					notifier.ID = uuid.New()
+					return tt.mockAddError
+				},
+			})
+
+			cmd := NewCmdProjectNotifierAdd()
+			var stdout bytes.Buffer
+			cmd.SetOut(&stdout)
+			cmd.SetErr(&stdout)
+			cmd.SetArgs(tt.args)
+
+			err := cmd.Execute()
+
+			if tt.expectError != "" {
+				assert.ErrorContains(t, err, tt.expectError)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, added)
+			assert.Equal(t, testProject.ID, added.ProjectID)
+			assert.Equal(t, tt.expectedType, added.Type)
+			assert.Equal(t, tt.expectEvents, added.Events)
+			assert.Equal(t, tt.expectConfig, added.Config)
+			assert.Contains(t, stdout.String(), tt.expectedInOut)
+		})
+	}
+}
+
+func TestNewCmdProjectNotifierList(t *testing.T) {
+	testProject := setupNotifierTestProject(t)
+	app.SetNotificationServiceForTesting(&mocks.MockNotificationManager{
+		ListNotifiersFunc: func(projectID uuid.UUID) ([]*services.Notifier, error) {
+			assert.Equal(t, testProject.ID, projectID)
+			return []*services.Notifier{
+				{
+					ID:     uuid.New(),
+					Type:   services.NotifierTypeNtfy,
+					Config: services.NotifierConfig{URL: "https://ntfy.sh/x"},
+				},
+			}, nil
+		},
+	})
+
+	cmd := NewCmdProjectNotifierList()
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetArgs([]string{"test-project"})
+
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, stdout.String(), "ntfy")
+	assert.Contains(t, stdout.String(), "https://ntfy.sh")
+}
+
+func TestNewCmdProjectNotifierRemoveAndTest(t *testing.T) {
+	notifierID := uuid.New()
+
+	tests := []struct {
+		name         string
+		newCmd       func() *cobra.Command
+		args         []string
+		serviceError error
+		expectError  string
+		expectedText string
+	}{
+		{
+			name:         "remove",
+			newCmd:       NewCmdProjectNotifierRemove,
+			args:         []string{"test-project", notifierID.String()},
+			expectedText: "Removed notifier",
+		},
+		{
+			name:         "test",
+			newCmd:       NewCmdProjectNotifierTest,
+			args:         []string{"test-project", notifierID.String()},
+			expectedText: "Test notification sent",
+		},
+		{
+			name:         "test delivery failure",
+			newCmd:       NewCmdProjectNotifierTest,
+			args:         []string{"test-project", notifierID.String()},
+			serviceError: errors.New("server responded with HTTP 500"),
+			expectError:  "failed to send test notification: server responded with HTTP 500",
+		},
+		{
+			name:        "invalid notifier ID",
+			newCmd:      NewCmdProjectNotifierRemove,
+			args:        []string{"test-project", "not-a-uuid"},
+			expectError: "invalid notifier ID 'not-a-uuid'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testProject := setupNotifierTestProject(t)
+			check := func(projectID, id uuid.UUID) error {
+				assert.Equal(t, testProject.ID, projectID)
+				assert.Equal(t, notifierID, id)
+				return tt.serviceError
+			}
+			app.SetNotificationServiceForTesting(&mocks.MockNotificationManager{
+				RemoveNotifierFunc: check,
+				TestNotifierFunc:   check,
+			})
+
+			cmd := tt.newCmd()
+			var stdout bytes.Buffer
+			cmd.SetOut(&stdout)
+			cmd.SetErr(&stdout)
+			cmd.SetArgs(tt.args)
+
+			err := cmd.Execute()
+
+			if tt.expectError != "" {
+				assert.ErrorContains(t, err, tt.expectError)
+				assert.True(t, cmd.SilenceUsage)
+				return
+			}
+			require.NoError(t, err)
+			assert.Contains(t, stdout.String(), tt.expectedText)
+		})
+	}
+}
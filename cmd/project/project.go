@@ -1,7 +1,13 @@
 // Package project provides commands for managing Docker Compose projects in Oar.
 package project
 
-import "github.com/spf13/cobra"
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/oar-cd/oar/services"
+	"github.com/spf13/cobra"
+)
 
 func NewCmdProject() *cobra.Command {
 	cmd := &cobra.Command{
@@ -14,10 +20,37 @@ func NewCmdProject() *cobra.Command {
 	cmd.AddCommand(NewCmdProjectRemove())
 	cmd.AddCommand(NewCmdProjectShow())
 	cmd.AddCommand(NewCmdProjectDeploy())
+	cmd.AddCommand(NewCmdProjectRollback())
+	cmd.AddCommand(NewCmdProjectApprove())
+	cmd.AddCommand(NewCmdProjectReject())
 	cmd.AddCommand(NewCmdProjectStop())
 	cmd.AddCommand(NewCmdProjectStatus())
 	cmd.AddCommand(NewCmdProjectConfig())
 	cmd.AddCommand(NewCmdProjectLogs())
 	cmd.AddCommand(NewCmdProjectDeployments())
+	cmd.AddCommand(NewCmdProjectNotifier())
+	cmd.AddCommand(NewCmdProjectVolume())
 	return cmd
 }
+
+// findProject looks up a project by ID, or by name if the reference is not a valid UUID
+func findProject(projectService services.ProjectManager, ref string) (*services.Project, error) {
+	if projectID, err := uuid.Parse(ref); err == nil {
+		project, err := projectService.Get(projectID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find project %s: %w", projectID, err)
+		}
+		return project, nil
+	}
+
+	projects, err := projectService.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list projects: %w", err)
+	}
+	for _, project := range projects {
+		if project.Name == ref {
+			return project, nil
+		}
+	}
+	return nil, fmt.Errorf("project '%s' not found", ref)
+}
@@ -30,7 +30,8 @@ func TestNewCmdProject(t *testing.T) {
 	}
 
 	expectedSubcommands := []string{
-		"list", "add", "remove", "show", "deploy", "stop", "status", "config", "logs", "deployments",
+		"list", "add", "remove", "show", "deploy", "rollback", "approve", "reject", "stop", "status", "config", "logs",
+		"deployments", "notifier", "volume",
 	}
 
 	for _, expected := range expectedSubcommands {
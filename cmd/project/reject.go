@@ -0,0 +1,48 @@
+package project
+
+import (
+	"github.com/oar-cd/oar/cmd/output"
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdProjectReject() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reject <project-id|name> [deployment-id]",
+		Short: "Reject a pending deployment of a project",
+		Long: `Reject a deployment pending approval, so that its commit is not deployed.
+The watcher and webhooks don't ask about a rejected commit again until a newer
+commit is pushed. Without a deployment ID, the pending deployment of the project
+is rejected.`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := runProjectReject(cmd, args)
+			if err != nil {
+				// Silence usage for runtime errors (not argument validation errors)
+				cmd.SilenceUsage = true
+			}
+			return err
+		},
+	}
+	return cmd
+}
+
+// runProjectReject handles the main logic for rejecting a pending deployment
+func runProjectReject(cmd *cobra.Command, args []string) error {
+	projectService := app.GetProjectService()
+
+	project, err := findProject(projectService, args[0])
+	if err != nil {
+		return err
+	}
+	deploymentID, err := findPendingDeployment(projectService, project, args[1:])
+	if err != nil {
+		return err
+	}
+
+	if err := projectService.RejectDeployment(project.ID, deploymentID, deployOptions(false)); err != nil {
+		return err
+	}
+
+	return output.FprintSuccess(cmd, "Deployment %s of project '%s' rejected\n", deploymentID, project.Name)
+}
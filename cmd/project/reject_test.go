@@ -0,0 +1,54 @@
+package project
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/oar-cd/oar/services"
+	"github.com/oar-cd/oar/testing/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCmdProjectReject(t *testing.T) {
+	testProject := &services.Project{ID: uuid.New(), Name: "test-project"}
+	pendingID := uuid.New()
+
+	var rejected uuid.UUID
+	mockService := &mocks.MockProjectManager{
+		ListFunc: func() ([]*services.Project, error) {
+			return []*services.Project{testProject}, nil
+		},
+		ListDeploymentsFunc: func(projectID uuid.UUID) ([]*services.Deployment, error) {
+			return []*services.Deployment{{ID: pendingID, Status: services.DeploymentStatusPending}}, nil
+		},
+		RejectDeploymentFunc: func(projectID, deploymentID uuid.UUID, options services.DeployOptions) error {
+			assert.Equal(t, testProject.ID, projectID)
+			rejected = deploymentID
+			return nil
+		},
+	}
+	app.SetProjectServiceForTesting(mockService)
+
+	cmd := NewCmdProjectReject()
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetArgs([]string{"test-project"})
+
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, pendingID, rejected)
+	assert.Contains(t, stdout.String(), "Deployment "+pendingID.String()+" of project 'test-project' rejected")
+
+	// Rejecting a deployment that isn't pending fails
+	mockService.RejectDeploymentFunc = func(projectID, deploymentID uuid.UUID, options services.DeployOptions) error {
+		return services.ErrDeploymentNotPending
+	}
+	cmd = NewCmdProjectReject()
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stdout)
+	cmd.SetArgs([]string{"test-project", uuid.NewString()})
+	assert.ErrorIs(t, cmd.Execute(), services.ErrDeploymentNotPending)
+	assert.True(t, cmd.SilenceUsage)
+}
@@ -0,0 +1,85 @@
+package project
+
+import (
+	"fmt"
+
+	"github.com/oar-cd/oar/cmd/output"
+	"github.com/oar-cd/oar/cmd/utils"
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdProjectRollback() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rollback <project-id|name>",
+		Short: "Roll a project back to an earlier deployment",
+		Long: `Check out the commit of an earlier deployment and deploy it using Docker Compose.
+The deployment is recorded as a rollback. Without --to, the project is rolled back to
+the most recent successful deployment of another commit.
+
+Automatic deployments skip the commit rolled back from until a newer commit is pushed.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := runProjectRollback(cmd, args)
+			if err != nil {
+				// Silence usage for runtime errors (not argument validation errors)
+				cmd.SilenceUsage = true
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().String("to", "", "Deployment ID or commit (prefix) to roll back to")
+	return cmd
+}
+
+// runProjectRollback handles the main logic for rolling back a project
+func runProjectRollback(cmd *cobra.Command, args []string) error {
+	target, _ := cmd.Flags().GetString("to")
+
+	// Get services
+	projectService := app.GetProjectService()
+
+	project, err := findProject(projectService, args[0])
+	if err != nil {
+		return err
+	}
+
+	if err := output.FprintPlain(cmd, "Starting rollback for project '%s'\n", project.Name); err != nil {
+		return err
+	}
+
+	// Ctrl-C cancels the rollback
+	ctx, cancel := utils.InterruptContext(cmd)
+	defer cancel()
+
+	// Roll back project with direct stdout/stderr piping
+	if err := projectService.RollbackPiping(ctx, project.ID, target, deployOptions(false)); err != nil {
+		return err
+	}
+
+	// Get updated project for final status
+	updatedProject, err := projectService.Get(project.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get updated project status: %w", err)
+	}
+
+	if err := output.FprintSuccess(cmd, "\nProject '%s' rolled back successfully\n", updatedProject.Name); err != nil {
+		return err
+	}
+	if err := output.FprintPlain(cmd, "Status: %s", updatedProject.Status.String()); err != nil {
+		return err
+	}
+
+	if updatedProject.LastCommit != nil {
+		shortCommit := *updatedProject.LastCommit
+		if len(shortCommit) > 8 {
+			shortCommit = shortCommit[:8]
+		}
+		if err := output.FprintPlain(cmd, "Deployed commit: %s", shortCommit); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
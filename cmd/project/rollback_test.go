@@ -0,0 +1,109 @@
+package project
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/oar-cd/oar/services"
+	"github.com/oar-cd/oar/testing/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCmdProjectRollback(t *testing.T) {
+	testProjectID := uuid.New()
+	rolledBackCommit := "abc123def456"
+	testProject := &services.Project{
+		ID:         testProjectID,
+		Name:       "test-project",
+		Status:     services.ProjectStatusRunning,
+		LastCommit: &rolledBackCommit,
+	}
+
+	tests := []struct {
+		name              string
+		args              []string
+		mockRollbackError error
+		expectError       string
+		expectedTarget    string
+		expectedText      string
+	}{
+		{
+			name:         "rollback by ID",
+			args:         []string{testProjectID.String()},
+			expectedText: "rolled back successfully",
+		},
+		{
+			name:           "rollback by name to commit",
+			args:           []string{"test-project", "--to", "abc123"},
+			expectedTarget: "abc123",
+			expectedText:   "Deployed commit: abc123de",
+		},
+		{
+			name:        "unknown project name",
+			args:        []string{"other-project"},
+			expectError: "project 'other-project' not found",
+		},
+		{
+			name:              "rollback error",
+			args:              []string{"test-project"},
+			mockRollbackError: services.ErrInvalidRollbackTarget,
+			expectError:       "invalid rollback target",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var target string
+			mockService := &mocks.MockProjectManager{
+				ListFunc: func() ([]*services.Project, error) {
+					return []*services.Project{testProject}, nil
+				},
+				GetFunc: func(id uuid.UUID) (*services.Project, error) {
+					if id != testProjectID {
+						return nil, errors.New("record not found")
+					}
+					return testProject, nil
+				},
+				RollbackPipingFunc: func(projectID uuid.UUID, to string, options services.DeployOptions) error {
+					assert.Equal(t, services.DeploymentTriggerCLI, options.Trigger)
+					target = to
+					return tt.mockRollbackError
+				},
+			}
+			app.SetProjectServiceForTesting(mockService)
+
+			cmd := NewCmdProjectRollback()
+			var stdout bytes.Buffer
+			cmd.SetOut(&stdout)
+			cmd.SetErr(&stdout)
+			cmd.SetArgs(tt.args)
+
+			err := cmd.Execute()
+
+			if tt.expectError != "" {
+				assert.ErrorContains(t, err, tt.expectError)
+				assert.True(t, cmd.SilenceUsage)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedTarget, target)
+			assert.Contains(t, stdout.String(), tt.expectedText)
+		})
+	}
+}
+
+func TestNewCmdProjectRollbackCommand(t *testing.T) {
+	cmd := NewCmdProjectRollback()
+
+	assert.Equal(t, "rollback <project-id|name>", cmd.Use)
+	assert.NotEmpty(t, cmd.Short)
+	assert.NotEmpty(t, cmd.Long)
+
+	toFlag := cmd.Flags().Lookup("to")
+	require.NotNil(t, toFlag)
+	assert.Equal(t, "", toFlag.DefValue)
+}
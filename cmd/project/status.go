@@ -1,11 +1,14 @@
 package project
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/google/uuid"
 	"github.com/oar-cd/oar/cmd/output"
+	"github.com/oar-cd/oar/cmd/utils"
 	"github.com/oar-cd/oar/internal/app"
+	"github.com/oar-cd/oar/services"
 	"github.com/spf13/cobra"
 )
 
@@ -14,7 +17,11 @@ func NewCmdProjectStatus() *cobra.Command {
 		Use:   "status <project-id>",
 		Short: "Show the status of a project's containers",
 		Long: `Display the current status of all containers in a project.
-This shows whether the project is running, uptime, and individual container states.`,
+This shows whether the project is running, uptime, and individual container states.
+
+For a running project, the containers are also compared with the deployed commit:
+the project is out of sync when a service has no container, a container belongs to a
+service that is not declared, or runs another image or configuration than declared.`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runProjectStatus(cmd, args)
@@ -54,6 +61,12 @@ func runProjectStatus(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if projectStatus.Status != "stopped" {
+		if err := printProjectSync(cmd, projectService, projectID); err != nil {
+			return err
+		}
+	}
+
 	// Show individual container statuses
 	if len(projectStatus.Containers) > 0 {
 		if err := output.FprintPlain(cmd, "\nContainers:"); err != nil {
@@ -72,3 +85,30 @@ func runProjectStatus(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// printProjectSync detects the drift of a project and displays whether it is in sync with its deployed commit
+func printProjectSync(cmd *cobra.Command, projectService services.ProjectManager, projectID uuid.UUID) error {
+	ctx, cancel := utils.InterruptContext(cmd)
+	defer cancel()
+
+	drift, err := projectService.DetectDrift(ctx, projectID)
+	if errors.Is(err, services.ErrDeploymentInProgress) {
+		return output.FprintPlain(cmd, "Sync: unknown, a deployment is in progress")
+	}
+	if err != nil {
+		return output.FprintWarning(cmd, "Sync: unknown, failed to detect drift: %v", err)
+	}
+	if len(drift) == 0 {
+		return output.FprintPlain(cmd, "Sync: in sync")
+	}
+
+	if err := output.FprintWarning(cmd, "Sync: out of sync"); err != nil {
+		return err
+	}
+	for _, description := range services.FormatDrift(drift) {
+		if err := output.FprintPlain(cmd, "  %s", description); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package project
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"testing"
 
@@ -21,6 +22,8 @@ func TestNewCmdProjectStatus(t *testing.T) {
 		args         []string
 		mockStatus   *services.ComposeStatus
 		mockError    error
+		mockDrift    []services.ServiceDrift
+		driftError   error
 		expectError  bool
 		expectedText string
 	}{
@@ -39,6 +42,50 @@ func TestNewCmdProjectStatus(t *testing.T) {
 			expectError:  false,
 			expectedText: "Status: running",
 		},
+		{
+			name: "project in sync",
+			args: []string{testProjectID.String()},
+			mockStatus: &services.ComposeStatus{
+				Status:     "running",
+				Containers: []services.ContainerInfo{},
+			},
+			expectError:  false,
+			expectedText: "Sync: in sync",
+		},
+		{
+			name: "project out of sync",
+			args: []string{testProjectID.String()},
+			mockStatus: &services.ComposeStatus{
+				Status: "running",
+				Containers: []services.ContainerInfo{
+					{Service: "web", Status: "Up 2 hours", State: "running"},
+				},
+			},
+			mockDrift: []services.ServiceDrift{
+				{Service: "db", Kind: services.DriftKindMissing},
+				{
+					Service:   "web",
+					Kind:      services.DriftKindImage,
+					Container: "app-web-1",
+					Expected:  "nginx:1.27",
+					Actual:    "nginx:1.25",
+				},
+			},
+			expectError: false,
+			expectedText: "Sync: out of sync\n  db: no container\n" +
+				"  web: container app-web-1 runs image nginx:1.25, nginx:1.27 is declared",
+		},
+		{
+			name: "project being deployed",
+			args: []string{testProjectID.String()},
+			mockStatus: &services.ComposeStatus{
+				Status:     "running",
+				Containers: []services.ContainerInfo{},
+			},
+			driftError:   services.ErrDeploymentInProgress,
+			expectError:  false,
+			expectedText: "Sync: unknown, a deployment is in progress",
+		},
 		{
 			name: "project stopped status",
 			args: []string{testProjectID.String()},
@@ -90,6 +137,9 @@ func TestNewCmdProjectStatus(t *testing.T) {
 					}
 					return tt.mockStatus, nil
 				},
+				DetectDriftFunc: func(ctx context.Context, projectID uuid.UUID) ([]services.ServiceDrift, error) {
+					return tt.mockDrift, tt.driftError
+				},
 			}
 			app.SetProjectServiceForTesting(mockService)
 
@@ -114,6 +164,11 @@ func TestNewCmdProjectStatus(t *testing.T) {
 				}
 
 				if tt.mockStatus != nil {
+					// Sync is only shown for projects with containers
+					if tt.mockStatus.Status == "stopped" {
+						assert.NotContains(t, stdoutStr, "Sync:")
+					}
+
 					// Verify uptime is shown for running projects
 					if tt.mockStatus.Status == "running" && tt.mockStatus.Uptime != "" {
 						assert.Contains(t, stdoutStr, "Uptime:")
@@ -0,0 +1,163 @@
+package project
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+
+	"github.com/oar-cd/oar/cmd/output"
+	"github.com/oar-cd/oar/cmd/utils"
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/oar-cd/oar/services"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdProjectVolume() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "volume",
+		Short: "Back up and restore the volumes of a project",
+		Long: `Back up and restore the named volumes a project declares in its Compose files.
+
+Each volume is archived as a gzipped tarball by a helper container into the backup
+directory, keeping the newest backups of each project (OAR_VOLUME_BACKUP_RETENTION).
+External volumes are not backed up. Listing backups requires the viewer role on the
+project, backing up the deployer role and restoring the admin role.`,
+	}
+
+	cmd.AddCommand(NewCmdProjectVolumeList())
+	cmd.AddCommand(NewCmdProjectVolumeBackup())
+	cmd.AddCommand(NewCmdProjectVolumeRestore())
+	return cmd
+}
+
+func NewCmdProjectVolumeList() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list <project-id|name>",
+		Short: "List the volume backups of a project",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := runProjectVolumeList(cmd, args)
+			if err != nil {
+				// Silence usage for runtime errors (not argument validation errors)
+				cmd.SilenceUsage = true
+			}
+			return err
+		},
+	}
+}
+
+func NewCmdProjectVolumeBackup() *cobra.Command {
+	return &cobra.Command{
+		Use:   "backup <project-id|name>",
+		Short: "Back up the volumes of a project",
+		Long: `Back up the named volumes of a project that exist.
+
+Volumes are archived while the project runs. Stop the project first for a consistent
+backup of a database that writes to its volume.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := runProjectVolumeBackup(cmd, args)
+			if err != nil {
+				// Silence usage for runtime errors (not argument validation errors)
+				cmd.SilenceUsage = true
+			}
+			return err
+		},
+	}
+}
+
+func NewCmdProjectVolumeRestore() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore <project-id|name> <backup>",
+		Short: "Restore the volumes of a project from a backup",
+		Long: `Replace the contents of the volumes of a project with those of a backup listed by
+'oar project volume list'.
+
+The project must be stopped first, and deployed again afterwards.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := runProjectVolumeRestore(cmd, args)
+			if err != nil {
+				// Silence usage for runtime errors (not argument validation errors)
+				cmd.SilenceUsage = true
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().BoolP("confirm", "y", false, "Skip confirmation prompt and proceed with the restore")
+
+	return cmd
+}
+
+// runProjectVolumeList handles the main logic for listing a project's volume backups
+func runProjectVolumeList(cmd *cobra.Command, args []string) error {
+	project, err := findProject(app.GetProjectService(), args[0])
+	if err != nil {
+		return err
+	}
+
+	backups, err := app.GetVolumeBackupService().List(project.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list volume backups: %w", err)
+	}
+
+	out, err := output.PrintVolumeBackupList(backups, project.Name)
+	if err != nil {
+		return err
+	}
+
+	return output.FprintPlain(cmd, "%s", out)
+}
+
+// runProjectVolumeBackup handles the main logic for backing up a project's volumes
+func runProjectVolumeBackup(cmd *cobra.Command, args []string) error {
+	project, err := findProject(app.GetProjectService(), args[0])
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := utils.InterruptContext(cmd)
+	defer cancel()
+
+	backup, err := app.GetVolumeBackupService().Create(ctx, project.ID)
+	if err != nil {
+		return fmt.Errorf("failed to back up volumes: %w", err)
+	}
+
+	return output.FprintSuccess(cmd, "Backed up volumes %s of project '%s' as '%s'\n",
+		strings.Join(backup.Volumes, ", "), project.Name, backup.Name)
+}
+
+// runProjectVolumeRestore handles the main logic for restoring a project's volumes
+func runProjectVolumeRestore(cmd *cobra.Command, args []string) error {
+	name := args[1]
+
+	project, err := findProject(app.GetProjectService(), args[0])
+	if err != nil {
+		return err
+	}
+
+	skipConfirmation, _ := cmd.Flags().GetBool("confirm")
+	if !skipConfirmation {
+		if err := output.FprintWarning(
+			cmd, "The volumes of project '%s' will be replaced by backup '%s'. Continue? [y/N]: ", project.Name, name,
+		); err != nil {
+			return err
+		}
+		answer, _ := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+		if answer = strings.ToLower(strings.TrimSpace(answer)); answer != "y" && answer != "yes" {
+			return output.FprintPlain(cmd, "Restore cancelled.\n")
+		}
+	}
+
+	ctx, cancel := utils.InterruptContext(cmd)
+	defer cancel()
+
+	options := services.DeployOptions{Trigger: services.DeploymentTriggerCLI}
+	if err := app.GetVolumeBackupService().Restore(ctx, project.ID, name, options); err != nil {
+		return fmt.Errorf("failed to restore volumes: %w", err)
+	}
+
+	return output.FprintSuccess(cmd, "Volumes of project '%s' restored from backup '%s'\n", project.Name, name)
+}
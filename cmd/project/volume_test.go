@@ -0,0 +1,134 @@
+package project
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/oar-cd/oar/services"
+	"github.com/oar-cd/oar/testing/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCmdProjectVolumeList(t *testing.T) {
+	testProject := setupNotifierTestProject(t)
+	app.SetVolumeBackupServiceForTesting(&mocks.MockVolumeBackupManager{
+		ListFunc: func(projectID uuid.UUID) ([]*services.VolumeBackup, error) {
+			assert.Equal(t, testProject.ID, projectID)
+			return []*services.VolumeBackup{
+				{Name: "20250301T120000.000Z", Volumes: []string{"data"}, Size: 512, CreatedAt: time.Now()},
+			}, nil
+		},
+	})
+
+	cmd := NewCmdProjectVolumeList()
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetArgs([]string{"test-project"})
+
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, stdout.String(), "20250301T120000.000Z")
+	assert.Contains(t, stdout.String(), "512 B")
+}
+
+func TestNewCmdProjectVolumeBackup(t *testing.T) {
+	testProject := setupNotifierTestProject(t)
+	app.SetVolumeBackupServiceForTesting(&mocks.MockVolumeBackupManager{
+		CreateFunc: func(ctx context.Context, projectID uuid.UUID) (*services.VolumeBackup, error) {
+			assert.Equal(t, testProject.ID, projectID)
+			return &services.VolumeBackup{Name: "20250301T120000.000Z", Volumes: []string{"data", "uploads"}}, nil
+		},
+	})
+
+	cmd := NewCmdProjectVolumeBackup()
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetArgs([]string{"test-project"})
+
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, stdout.String(),
+		"Backed up volumes data, uploads of project 'test-project' as '20250301T120000.000Z'")
+}
+
+func TestNewCmdProjectVolumeRestore(t *testing.T) {
+	tests := []struct {
+		name            string
+		args            []string
+		stdin           string
+		serviceError    error
+		expectRestored  bool
+		expectError     string
+		expectedMessage string
+	}{
+		{
+			name:            "confirmed",
+			args:            []string{"test-project", "20250301T120000.000Z"},
+			stdin:           "y\n",
+			expectRestored:  true,
+			expectedMessage: "Volumes of project 'test-project' restored from backup '20250301T120000.000Z'",
+		},
+		{
+			name:            "skip confirmation",
+			args:            []string{"test-project", "20250301T120000.000Z", "--confirm"},
+			expectRestored:  true,
+			expectedMessage: "restored from backup",
+		},
+		{
+			name:            "cancelled",
+			args:            []string{"test-project", "20250301T120000.000Z"},
+			stdin:           "n\n",
+			expectedMessage: "Restore cancelled.",
+		},
+		{
+			name:           "project running",
+			args:           []string{"test-project", "20250301T120000.000Z", "-y"},
+			serviceError:   services.ErrProjectRunning,
+			expectRestored: true,
+			expectError:    "failed to restore volumes: project is running",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testProject := setupNotifierTestProject(t)
+			restored := false
+			app.SetVolumeBackupServiceForTesting(&mocks.MockVolumeBackupManager{
+				RestoreFunc: func(
+					ctx context.Context,
+					projectID uuid.UUID,
+					name string,
+					options services.DeployOptions,
+				) error {
+					assert.Equal(t, testProject.ID, projectID)
+					assert.Equal(t, "20250301T120000.000Z", name)
+					assert.Equal(t, services.DeploymentTriggerCLI, options.Trigger)
+					restored = true
+					return tt.serviceError
+				},
+			})
+
+			cmd := NewCmdProjectVolumeRestore()
+			var stdout bytes.Buffer
+			cmd.SetOut(&stdout)
+			cmd.SetErr(&stdout)
+			cmd.SetIn(strings.NewReader(tt.stdin))
+			cmd.SetArgs(tt.args)
+
+			err := cmd.Execute()
+
+			assert.Equal(t, tt.expectRestored, restored)
+			if tt.expectError != "" {
+				assert.ErrorContains(t, err, tt.expectError)
+				assert.True(t, cmd.SilenceUsage)
+				return
+			}
+			require.NoError(t, err)
+			assert.Contains(t, stdout.String(), tt.expectedMessage)
+		})
+	}
+}
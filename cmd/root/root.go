@@ -5,6 +5,9 @@ import (
 	"log"
 	"os"
 
+	"github.com/oar-cd/oar/cmd/backup"
+	"github.com/oar-cd/oar/cmd/catalog"
+	"github.com/oar-cd/oar/cmd/export"
 	"github.com/oar-cd/oar/cmd/logs"
 	"github.com/oar-cd/oar/cmd/output"
 	"github.com/oar-cd/oar/cmd/project"
@@ -12,6 +15,7 @@ import (
 	"github.com/oar-cd/oar/cmd/status"
 	"github.com/oar-cd/oar/cmd/stop"
 	"github.com/oar-cd/oar/cmd/update"
+	"github.com/oar-cd/oar/cmd/user"
 	"github.com/oar-cd/oar/cmd/version"
 	"github.com/oar-cd/oar/internal/app"
 	"github.com/oar-cd/oar/logging"
@@ -82,12 +86,17 @@ func NewCmdRoot() *cobra.Command {
 	cmd.PersistentFlags().VarP(logging.LogLevel, "log-level", "l", "Set log verbosity level")
 	cmd.PersistentFlags().VarP(output.NoColor, "no-color", "c", "Disable colored terminal output")
 
+	cmd.AddCommand(backup.NewCmdBackup())
+	cmd.AddCommand(catalog.NewCmdCatalog())
+	cmd.AddCommand(export.NewCmdExport())
+	cmd.AddCommand(export.NewCmdImport())
 	cmd.AddCommand(logs.NewCmdLogs())
 	cmd.AddCommand(project.NewCmdProject())
 	cmd.AddCommand(start.NewCmdStart())
 	cmd.AddCommand(status.NewCmdStatus())
 	cmd.AddCommand(stop.NewCmdStop())
 	cmd.AddCommand(update.NewCmdUpdate())
+	cmd.AddCommand(user.NewCmdUser())
 	cmd.AddCommand(version.NewCmdVersion())
 	return cmd
 }
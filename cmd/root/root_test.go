@@ -38,7 +38,10 @@ func TestNewCmdRoot(t *testing.T) {
 		subcommandNames[i] = subcmd.Name()
 	}
 
-	expectedSubcommands := []string{"logs", "project", "start", "status", "stop", "update", "version"}
+	expectedSubcommands := []string{
+		"backup", "catalog", "export", "import", "logs", "project", "start", "status", "stop", "update", "user",
+		"version",
+	}
 	for _, expected := range expectedSubcommands {
 		assert.Contains(t, subcommandNames, expected, "Expected subcommand %s not found", expected)
 	}
@@ -69,7 +72,7 @@ func TestNewCmdRootPersistentPreRunLogic(t *testing.T) {
 	}
 
 	// These commands should NOT be in the skip list
-	nonSkipCommands := []string{"project"}
+	nonSkipCommands := []string{"project", "user"}
 	for _, cmdName := range nonSkipCommands {
 		assert.NotContains(t, skipInitCommands, cmdName)
 	}
@@ -40,8 +40,11 @@ func runStart(cmd *cobra.Command) error {
 		return err
 	}
 
+	ctx, cancel := utils.InterruptContext(cmd)
+	defer cancel()
+
 	// Use the existing UpPiping method for direct stdout/stderr piping
-	if err := oarComposeProject.UpPiping(); err != nil {
+	if err := oarComposeProject.UpPiping(ctx); err != nil {
 		return fmt.Errorf("failed to start Oar service: %w", err)
 	}
 
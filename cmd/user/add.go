@@ -0,0 +1,75 @@
+package user
+
+import (
+	"fmt"
+
+	"github.com/oar-cd/oar/cmd/output"
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/oar-cd/oar/services"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdUserAdd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add <username>",
+		Short: "Create a user account",
+		Long: `Create a user account for signing in to the web UI.
+
+The password is prompted for interactively. Use --password-stdin to
+read it from standard input instead, e.g. in scripts.
+
+A new user has no access to projects until a role is granted, either
+with --role or later with 'oar user grant'.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := runUserAdd(cmd, args)
+			if err != nil {
+				// Silence usage for runtime errors (not argument validation errors)
+				cmd.SilenceUsage = true
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().Bool("password-stdin", false, "Read the password from standard input")
+	cmd.Flags().String("role", "", "Grant a global role to the user (viewer, deployer or admin)")
+
+	return cmd
+}
+
+// runUserAdd handles the main logic for user creation
+func runUserAdd(cmd *cobra.Command, args []string) error {
+	username := args[0]
+
+	if err := requireAdmin(); err != nil {
+		return err
+	}
+
+	role := services.RoleNone
+	if value, _ := cmd.Flags().GetString("role"); value != "" {
+		var err error
+		role, err = services.ParseRole(value)
+		if err != nil {
+			return err
+		}
+	}
+
+	password, err := readPassword(cmd)
+	if err != nil {
+		return err
+	}
+
+	user, err := app.GetAuthService().CreateUser(username, password)
+	if err != nil {
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+
+	if role != services.RoleNone {
+		if err := app.GetRoleService().Grant(user.ID, nil, role); err != nil {
+			return fmt.Errorf("user '%s' created, but failed to grant role: %w", username, err)
+		}
+		return output.FprintSuccess(cmd, "User '%s' created successfully with global role '%s'\n", username, role)
+	}
+
+	return output.FprintSuccess(cmd, "User '%s' created successfully\n", username)
+}
@@ -0,0 +1,120 @@
+package user
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/oar-cd/oar/services"
+	"github.com/oar-cd/oar/testing/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCmdUserAdd(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		stdin          string
+		createErr      error
+		expectedOutput string
+		expectError    bool
+	}{
+		{
+			name:           "add success",
+			args:           []string{"alice", "--password-stdin"},
+			stdin:          "correct-horse\n",
+			expectedOutput: "User 'alice' created successfully",
+		},
+		{
+			name:        "service error",
+			args:        []string{"alice", "--password-stdin"},
+			stdin:       "short\n",
+			createErr:   errors.New("password must be at least 8 characters long"),
+			expectError: true,
+		},
+		{
+			name:        "no username",
+			args:        []string{},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotUsername, gotPassword string
+			app.SetAuthServiceForTesting(&mocks.MockUserManager{
+				CreateUserFunc: func(username, password string) (*services.User, error) {
+					gotUsername, gotPassword = username, password
+					if tt.createErr != nil {
+						return nil, tt.createErr
+					}
+					return &services.User{Username: username}, nil
+				},
+			})
+
+			cmd := NewCmdUserAdd()
+			buf := &bytes.Buffer{}
+			cmd.SetOut(buf)
+			cmd.SetErr(buf)
+			cmd.SetIn(strings.NewReader(tt.stdin))
+			cmd.SetArgs(tt.args)
+
+			err := cmd.Execute()
+
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "alice", gotUsername)
+			assert.Equal(t, "correct-horse", gotPassword)
+			assert.Contains(t, buf.String(), tt.expectedOutput)
+		})
+	}
+}
+
+func TestNewCmdUserAdd_WithRole(t *testing.T) {
+	var grantedRole services.Role
+	var grantedProjectID *uuid.UUID
+	app.SetActingUserForTesting(nil)
+	app.SetAuthServiceForTesting(&mocks.MockUserManager{})
+	app.SetRoleServiceForTesting(&mocks.MockRoleManager{
+		GrantFunc: func(userID uuid.UUID, projectID *uuid.UUID, role services.Role) error {
+			grantedRole, grantedProjectID = role, projectID
+			return nil
+		},
+	})
+
+	cmd := NewCmdUserAdd()
+	buf := &bytes.Buffer{}
+	cmd.SetOut(buf)
+	cmd.SetIn(strings.NewReader("correct-horse\n"))
+	cmd.SetArgs([]string{"alice", "--password-stdin", "--role", "admin"})
+
+	assert.NoError(t, cmd.Execute())
+	assert.Equal(t, services.RoleAdmin, grantedRole)
+	assert.Nil(t, grantedProjectID)
+	assert.Contains(t, buf.String(), "User 'alice' created successfully with global role 'admin'")
+}
+
+func TestNewCmdUserAdd_InvalidRole(t *testing.T) {
+	app.SetActingUserForTesting(nil)
+	app.SetAuthServiceForTesting(&mocks.MockUserManager{
+		CreateUserFunc: func(username, password string) (*services.User, error) {
+			t.Fatal("CreateUser must not be called")
+			return nil, nil
+		},
+	})
+
+	cmd := NewCmdUserAdd()
+	buf := &bytes.Buffer{}
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetIn(strings.NewReader("correct-horse\n"))
+	cmd.SetArgs([]string{"alice", "--password-stdin", "--role", "owner"})
+
+	assert.ErrorContains(t, cmd.Execute(), "invalid role")
+}
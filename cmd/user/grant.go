@@ -0,0 +1,70 @@
+package user
+
+import (
+	"fmt"
+
+	"github.com/oar-cd/oar/cmd/output"
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/oar-cd/oar/services"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdUserGrant() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "grant <username> <role>",
+		Short: "Grant a role to a user",
+		Long: `Grant a role to a user, either globally or on a single project.
+
+Roles:
+  viewer    view projects, their logs, configuration, status and deployments
+  deployer  everything a viewer may do, plus deploy and stop projects
+  admin     everything a deployer may do, plus edit and remove projects;
+            a global admin may also create projects and manage users
+
+A user has at most one role per scope. Granting a new role replaces the
+previous one in the same scope.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := runUserGrant(cmd, args)
+			if err != nil {
+				// Silence usage for runtime errors (not argument validation errors)
+				cmd.SilenceUsage = true
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().String("project", "", "Grant the role on this project ID only")
+
+	return cmd
+}
+
+// runUserGrant handles the main logic for granting a role
+func runUserGrant(cmd *cobra.Command, args []string) error {
+	username := args[0]
+
+	role, err := services.ParseRole(args[1])
+	if err != nil {
+		return err
+	}
+
+	if err := requireAdmin(); err != nil {
+		return err
+	}
+
+	projectID, err := projectScope(cmd)
+	if err != nil {
+		return err
+	}
+
+	user, err := app.GetAuthService().GetUser(username)
+	if err != nil {
+		return fmt.Errorf("user %q not found: %w", username, err)
+	}
+
+	if err := app.GetRoleService().Grant(user.ID, projectID, role); err != nil {
+		return fmt.Errorf("failed to grant role: %w", err)
+	}
+
+	return output.FprintSuccess(cmd, "Granted role '%s' to user '%s' %s\n", role, username, scopeDescription(projectID))
+}
@@ -0,0 +1,122 @@
+package user
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/oar-cd/oar/services"
+	"github.com/oar-cd/oar/testing/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCmdUserGrant(t *testing.T) {
+	projectID := uuid.New()
+
+	tests := []struct {
+		name              string
+		args              []string
+		expectedProjectID *uuid.UUID
+		expectedRole      services.Role
+		expectedOutput    string
+		expectError       string
+	}{
+		{
+			name:           "global role",
+			args:           []string{"alice", "deployer"},
+			expectedRole:   services.RoleDeployer,
+			expectedOutput: "Granted role 'deployer' to user 'alice' globally",
+		},
+		{
+			name:              "project role",
+			args:              []string{"alice", "viewer", "--project", projectID.String()},
+			expectedProjectID: &projectID,
+			expectedRole:      services.RoleViewer,
+			expectedOutput:    "Granted role 'viewer' to user 'alice' on project " + projectID.String(),
+		},
+		{
+			name:        "invalid role",
+			args:        []string{"alice", "owner"},
+			expectError: "invalid role",
+		},
+		{
+			name:        "invalid project ID",
+			args:        []string{"alice", "viewer", "--project", "nope"},
+			expectError: "invalid project ID",
+		},
+		{
+			name:        "missing role",
+			args:        []string{"alice"},
+			expectError: "accepts 2 arg(s)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID := uuid.New()
+			var granted bool
+			app.SetActingUserForTesting(nil)
+			app.SetProjectServiceForTesting(&mocks.MockProjectManager{})
+			app.SetAuthServiceForTesting(&mocks.MockUserManager{
+				GetUserFunc: func(username string) (*services.User, error) {
+					return &services.User{ID: userID, Username: username}, nil
+				},
+			})
+			app.SetRoleServiceForTesting(&mocks.MockRoleManager{
+				GrantFunc: func(gotUserID uuid.UUID, gotProjectID *uuid.UUID, role services.Role) error {
+					granted = true
+					assert.Equal(t, userID, gotUserID)
+					assert.Equal(t, tt.expectedProjectID, gotProjectID)
+					assert.Equal(t, tt.expectedRole, role)
+					return nil
+				},
+			})
+
+			cmd := NewCmdUserGrant()
+			buf := &bytes.Buffer{}
+			cmd.SetOut(buf)
+			cmd.SetErr(buf)
+			cmd.SetArgs(tt.args)
+
+			err := cmd.Execute()
+
+			if tt.expectError != "" {
+				assert.ErrorContains(t, err, tt.expectError)
+				assert.False(t, granted)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, granted)
+			assert.Contains(t, buf.String(), tt.expectedOutput)
+		})
+	}
+}
+
+func TestNewCmdUserGrant_RequiresAdmin(t *testing.T) {
+	actingUser := &services.User{ID: uuid.New(), Username: "bob"}
+	app.SetActingUserForTesting(actingUser)
+	defer app.SetActingUserForTesting(nil)
+
+	app.SetRoleServiceForTesting(&mocks.MockRoleManager{
+		AuthorizeFunc: func(user *services.User, projectID *uuid.UUID, required services.Role) error {
+			assert.Equal(t, actingUser, user)
+			assert.Nil(t, projectID)
+			assert.Equal(t, services.RoleAdmin, required)
+			return services.ErrPermissionDenied
+		},
+		GrantFunc: func(userID uuid.UUID, projectID *uuid.UUID, role services.Role) error {
+			t.Fatal("Grant must not be called")
+			return nil
+		},
+	})
+
+	cmd := NewCmdUserGrant()
+	buf := &bytes.Buffer{}
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs([]string{"bob", "admin"})
+
+	assert.ErrorIs(t, cmd.Execute(), services.ErrPermissionDenied)
+}
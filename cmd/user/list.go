@@ -0,0 +1,35 @@
+package user
+
+import (
+	"github.com/oar-cd/oar/cmd/output"
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdUserList() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List all user accounts",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requireAdmin(); err != nil {
+				return err
+			}
+
+			users, err := app.GetAuthService().ListUsers()
+			if err != nil {
+				return err
+			}
+
+			if len(users) == 0 {
+				return output.FprintPlain(cmd, "No users found. Create one with 'oar user add <username> --role admin'.\n")
+			}
+
+			out, err := output.PrintUserList(users)
+			if err != nil {
+				return err
+			}
+
+			return output.FprintPlain(cmd, "%s", out)
+		},
+	}
+}
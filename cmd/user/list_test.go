@@ -0,0 +1,64 @@
+package user
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/oar-cd/oar/services"
+	"github.com/oar-cd/oar/testing/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCmdUserList(t *testing.T) {
+	tests := []struct {
+		name           string
+		users          []*services.User
+		listErr        error
+		expectedOutput string
+		expectError    bool
+	}{
+		{
+			name:           "users found",
+			users:          []*services.User{{ID: uuid.New(), Username: "alice"}},
+			expectedOutput: "alice",
+		},
+		{
+			name:           "no users",
+			users:          []*services.User{},
+			expectedOutput: "No users found",
+		},
+		{
+			name:        "service error",
+			listErr:     errors.New("database error"),
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app.SetAuthServiceForTesting(&mocks.MockUserManager{
+				ListUsersFunc: func() ([]*services.User, error) {
+					return tt.users, tt.listErr
+				},
+			})
+
+			cmd := NewCmdUserList()
+			buf := &bytes.Buffer{}
+			cmd.SetOut(buf)
+			cmd.SetErr(buf)
+			cmd.SetArgs([]string{})
+
+			err := cmd.Execute()
+
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Contains(t, buf.String(), tt.expectedOutput)
+		})
+	}
+}
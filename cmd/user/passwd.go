@@ -0,0 +1,52 @@
+package user
+
+import (
+	"fmt"
+
+	"github.com/oar-cd/oar/cmd/output"
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdUserPasswd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "passwd <username>",
+		Short: "Change a user's password",
+		Long: `Change the password of a user account.
+
+All existing sessions of the user are signed out.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := runUserPasswd(cmd, args)
+			if err != nil {
+				// Silence usage for runtime errors (not argument validation errors)
+				cmd.SilenceUsage = true
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().Bool("password-stdin", false, "Read the password from standard input")
+
+	return cmd
+}
+
+// runUserPasswd handles the main logic for password changes
+func runUserPasswd(cmd *cobra.Command, args []string) error {
+	username := args[0]
+
+	if err := requireAdmin(); err != nil {
+		return err
+	}
+
+	password, err := readPassword(cmd)
+	if err != nil {
+		return err
+	}
+
+	if err := app.GetAuthService().SetPassword(username, password); err != nil {
+		return fmt.Errorf("failed to change password: %w", err)
+	}
+
+	return output.FprintSuccess(cmd, "Password for user '%s' changed successfully\n", username)
+}
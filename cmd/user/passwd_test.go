@@ -0,0 +1,52 @@
+package user
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/oar-cd/oar/testing/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCmdUserPasswd(t *testing.T) {
+	tests := []struct {
+		name        string
+		setErr      error
+		expectError bool
+	}{
+		{name: "passwd success"},
+		{name: "unknown user", setErr: errors.New(`user "alice" not found`), expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPassword string
+			app.SetAuthServiceForTesting(&mocks.MockUserManager{
+				SetPasswordFunc: func(username, password string) error {
+					gotPassword = password
+					return tt.setErr
+				},
+			})
+
+			cmd := NewCmdUserPasswd()
+			buf := &bytes.Buffer{}
+			cmd.SetOut(buf)
+			cmd.SetErr(buf)
+			cmd.SetIn(strings.NewReader("battery-staple\n"))
+			cmd.SetArgs([]string{"alice", "--password-stdin"})
+
+			err := cmd.Execute()
+
+			if tt.expectError {
+				assert.ErrorContains(t, err, "failed to change password")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "battery-staple", gotPassword)
+			assert.Contains(t, buf.String(), "Password for user 'alice' changed successfully")
+		})
+	}
+}
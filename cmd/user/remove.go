@@ -0,0 +1,33 @@
+package user
+
+import (
+	"fmt"
+
+	"github.com/oar-cd/oar/cmd/output"
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdUserRemove() *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove <username>",
+		Short: "Remove a user account",
+		Long:  `Remove a user account and sign out all of its sessions.`,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requireAdmin(); err != nil {
+				// Silence usage for runtime errors (not argument validation errors)
+				cmd.SilenceUsage = true
+				return err
+			}
+
+			if err := app.GetAuthService().RemoveUser(args[0]); err != nil {
+				// Silence usage for runtime errors (not argument validation errors)
+				cmd.SilenceUsage = true
+				return fmt.Errorf("failed to remove user: %w", err)
+			}
+
+			return output.FprintSuccess(cmd, "User '%s' removed successfully\n", args[0])
+		},
+	}
+}
@@ -0,0 +1,46 @@
+package user
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/oar-cd/oar/testing/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCmdUserRemove(t *testing.T) {
+	var removed string
+	app.SetAuthServiceForTesting(&mocks.MockUserManager{
+		RemoveUserFunc: func(username string) error {
+			removed = username
+			return nil
+		},
+	})
+
+	cmd := NewCmdUserRemove()
+	buf := &bytes.Buffer{}
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"alice"})
+
+	assert.NoError(t, cmd.Execute())
+	assert.Equal(t, "alice", removed)
+	assert.Contains(t, buf.String(), "User 'alice' removed successfully")
+}
+
+func TestNewCmdUserRemove_Error(t *testing.T) {
+	app.SetAuthServiceForTesting(&mocks.MockUserManager{
+		RemoveUserFunc: func(username string) error {
+			return errors.New(`user "bob" not found`)
+		},
+	})
+
+	cmd := NewCmdUserRemove()
+	buf := &bytes.Buffer{}
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs([]string{"bob"})
+
+	assert.ErrorContains(t, cmd.Execute(), "failed to remove user")
+}
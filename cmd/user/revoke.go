@@ -0,0 +1,55 @@
+package user
+
+import (
+	"fmt"
+
+	"github.com/oar-cd/oar/cmd/output"
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdUserRevoke() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "revoke <username>",
+		Short: "Revoke a user's role",
+		Long:  `Revoke a user's global role, or their role on a single project with --project.`,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := runUserRevoke(cmd, args)
+			if err != nil {
+				// Silence usage for runtime errors (not argument validation errors)
+				cmd.SilenceUsage = true
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().String("project", "", "Revoke the role on this project ID only")
+
+	return cmd
+}
+
+// runUserRevoke handles the main logic for revoking a role
+func runUserRevoke(cmd *cobra.Command, args []string) error {
+	username := args[0]
+
+	if err := requireAdmin(); err != nil {
+		return err
+	}
+
+	projectID, err := projectScope(cmd)
+	if err != nil {
+		return err
+	}
+
+	user, err := app.GetAuthService().GetUser(username)
+	if err != nil {
+		return fmt.Errorf("user %q not found: %w", username, err)
+	}
+
+	if err := app.GetRoleService().Revoke(user.ID, projectID); err != nil {
+		return fmt.Errorf("failed to revoke role: %w", err)
+	}
+
+	return output.FprintSuccess(cmd, "Revoked role of user '%s' %s\n", username, scopeDescription(projectID))
+}
@@ -0,0 +1,53 @@
+package user
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/oar-cd/oar/testing/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCmdUserRevoke(t *testing.T) {
+	projectID := uuid.New()
+	var revokedProjectID *uuid.UUID
+	app.SetActingUserForTesting(nil)
+	app.SetProjectServiceForTesting(&mocks.MockProjectManager{})
+	app.SetAuthServiceForTesting(&mocks.MockUserManager{})
+	app.SetRoleServiceForTesting(&mocks.MockRoleManager{
+		RevokeFunc: func(userID uuid.UUID, projectID *uuid.UUID) error {
+			revokedProjectID = projectID
+			return nil
+		},
+	})
+
+	cmd := NewCmdUserRevoke()
+	buf := &bytes.Buffer{}
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"alice", "--project", projectID.String()})
+
+	assert.NoError(t, cmd.Execute())
+	assert.Equal(t, &projectID, revokedProjectID)
+	assert.Contains(t, buf.String(), "Revoked role of user 'alice' on project "+projectID.String())
+}
+
+func TestNewCmdUserRevoke_Error(t *testing.T) {
+	app.SetActingUserForTesting(nil)
+	app.SetAuthServiceForTesting(&mocks.MockUserManager{})
+	app.SetRoleServiceForTesting(&mocks.MockRoleManager{
+		RevokeFunc: func(userID uuid.UUID, projectID *uuid.UUID) error {
+			return errors.New("no role assigned in this scope")
+		},
+	})
+
+	cmd := NewCmdUserRevoke()
+	buf := &bytes.Buffer{}
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs([]string{"alice"})
+
+	assert.ErrorContains(t, cmd.Execute(), "no role assigned in this scope")
+}
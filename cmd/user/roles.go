@@ -0,0 +1,70 @@
+package user
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/oar-cd/oar/cmd/output"
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdUserRoles() *cobra.Command {
+	return &cobra.Command{
+		Use:   "roles <username>",
+		Short: "List the roles of a user",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := runUserRoles(cmd, args)
+			if err != nil {
+				// Silence usage for runtime errors (not argument validation errors)
+				cmd.SilenceUsage = true
+			}
+			return err
+		},
+	}
+}
+
+// runUserRoles handles the main logic for listing a user's roles
+func runUserRoles(cmd *cobra.Command, args []string) error {
+	username := args[0]
+
+	if err := requireAdmin(); err != nil {
+		return err
+	}
+
+	user, err := app.GetAuthService().GetUser(username)
+	if err != nil {
+		return fmt.Errorf("user %q not found: %w", username, err)
+	}
+
+	bindings, err := app.GetRoleService().ListRoles(user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list roles: %w", err)
+	}
+
+	if len(bindings) == 0 {
+		return output.FprintPlain(
+			cmd,
+			"User '%s' has no roles. Grant one with 'oar user grant %s <role>'.\n",
+			username,
+			username,
+		)
+	}
+
+	projects, err := app.GetProjectService().List()
+	if err != nil {
+		return fmt.Errorf("failed to list projects: %w", err)
+	}
+	projectNames := make(map[uuid.UUID]string, len(projects))
+	for _, p := range projects {
+		projectNames[p.ID] = p.Name
+	}
+
+	out, err := output.PrintRoleList(bindings, projectNames)
+	if err != nil {
+		return err
+	}
+
+	return output.FprintPlain(cmd, "%s", out)
+}
@@ -0,0 +1,63 @@
+package user
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/oar-cd/oar/services"
+	"github.com/oar-cd/oar/testing/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCmdUserRoles(t *testing.T) {
+	projectID := uuid.New()
+
+	tests := []struct {
+		name           string
+		bindings       []*services.RoleBinding
+		expectedOutput []string
+	}{
+		{
+			name:           "no roles",
+			bindings:       []*services.RoleBinding{},
+			expectedOutput: []string{"User 'alice' has no roles"},
+		},
+		{
+			name: "global and project roles",
+			bindings: []*services.RoleBinding{
+				{ID: uuid.New(), Role: services.RoleViewer},
+				{ID: uuid.New(), ProjectID: &projectID, Role: services.RoleAdmin},
+			},
+			expectedOutput: []string{"global", "viewer", projectID.String(), "web-app", "admin"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app.SetActingUserForTesting(nil)
+			app.SetAuthServiceForTesting(&mocks.MockUserManager{})
+			app.SetProjectServiceForTesting(&mocks.MockProjectManager{
+				ListFunc: func() ([]*services.Project, error) {
+					return []*services.Project{{ID: projectID, Name: "web-app"}}, nil
+				},
+			})
+			app.SetRoleServiceForTesting(&mocks.MockRoleManager{
+				ListRolesFunc: func(userID uuid.UUID) ([]*services.RoleBinding, error) {
+					return tt.bindings, nil
+				},
+			})
+
+			cmd := NewCmdUserRoles()
+			buf := &bytes.Buffer{}
+			cmd.SetOut(buf)
+			cmd.SetArgs([]string{"alice"})
+
+			assert.NoError(t, cmd.Execute())
+			for _, expected := range tt.expectedOutput {
+				assert.Contains(t, buf.String(), expected)
+			}
+		})
+	}
+}
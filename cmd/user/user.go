@@ -0,0 +1,113 @@
+// Package user provides commands for managing web UI user accounts in Oar.
+package user
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/oar-cd/oar/cmd/output"
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/oar-cd/oar/services"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+func NewCmdUser() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "user",
+		Short: "Manage web UI user accounts and their roles",
+		Long: `Manage web UI user accounts and their roles.
+
+When OAR_USER is set, these commands require that user to be a global admin.`,
+	}
+
+	cmd.AddCommand(NewCmdUserList())
+	cmd.AddCommand(NewCmdUserAdd())
+	cmd.AddCommand(NewCmdUserRemove())
+	cmd.AddCommand(NewCmdUserPasswd())
+	cmd.AddCommand(NewCmdUserGrant())
+	cmd.AddCommand(NewCmdUserRevoke())
+	cmd.AddCommand(NewCmdUserRoles())
+	return cmd
+}
+
+// readPassword reads a new password either from stdin (--password-stdin) or
+// interactively from the terminal, asking for it twice
+func readPassword(cmd *cobra.Command) (string, error) {
+	fromStdin, _ := cmd.Flags().GetBool("password-stdin")
+	if fromStdin {
+		line, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+		if err != nil && line == "" {
+			return "", fmt.Errorf("failed to read password from stdin: %w", err)
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", errors.New("stdin is not a terminal, use --password-stdin to provide the password")
+	}
+
+	if err := output.FprintPlain(cmd, "Password: "); err != nil {
+		return "", err
+	}
+	password, err := term.ReadPassword(fd)
+	if err != nil {
+		return "", fmt.Errorf("failed to read password: %w", err)
+	}
+
+	if err := output.FprintPlain(cmd, "\nConfirm password: "); err != nil {
+		return "", err
+	}
+	confirmation, err := term.ReadPassword(fd)
+	if err != nil {
+		return "", fmt.Errorf("failed to read password: %w", err)
+	}
+	if err := output.FprintPlain(cmd, "\n"); err != nil {
+		return "", err
+	}
+
+	if string(password) != string(confirmation) {
+		return "", errors.New("passwords do not match")
+	}
+	return string(password), nil
+}
+
+// requireAdmin makes sure the acting user, if any, is a global admin
+func requireAdmin() error {
+	user := app.GetActingUser()
+	if user == nil {
+		return nil
+	}
+	return app.GetRoleService().Authorize(user, nil, services.RoleAdmin)
+}
+
+// projectScope returns the project ID from the --project flag, or nil for the global scope
+func projectScope(cmd *cobra.Command) (*uuid.UUID, error) {
+	value, _ := cmd.Flags().GetString("project")
+	if value == "" {
+		return nil, nil
+	}
+
+	projectID, err := uuid.Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid project ID '%s': must be a valid UUID", value)
+	}
+
+	if _, err := app.GetProjectService().Get(projectID); err != nil {
+		return nil, fmt.Errorf("failed to find project %s: %w", projectID, err)
+	}
+	return &projectID, nil
+}
+
+// scopeDescription describes a role scope for user-facing messages
+func scopeDescription(projectID *uuid.UUID) string {
+	if projectID == nil {
+		return "globally"
+	}
+	return fmt.Sprintf("on project %s", projectID)
+}
@@ -0,0 +1,60 @@
+package user
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCmdUser(t *testing.T) {
+	cmd := NewCmdUser()
+
+	assert.Equal(t, "user", cmd.Use)
+	assert.Nil(t, cmd.RunE)
+
+	var names []string
+	for _, sub := range cmd.Commands() {
+		names = append(names, sub.Name())
+	}
+	assert.ElementsMatch(t, []string{"list", "add", "remove", "passwd", "grant", "revoke", "roles"}, names)
+}
+
+func TestReadPassword_Stdin(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "trailing newline", input: "correct-horse\n", expected: "correct-horse"},
+		{name: "windows newline", input: "correct-horse\r\n", expected: "correct-horse"},
+		{name: "no newline", input: "correct-horse", expected: "correct-horse"},
+		{name: "only first line", input: "first-line\nsecond-line\n", expected: "first-line"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			cmd.Flags().Bool("password-stdin", true, "")
+			cmd.SetIn(strings.NewReader(tt.input))
+
+			password, err := readPassword(cmd)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, password)
+		})
+	}
+}
+
+func TestReadPassword_StdinEmpty(t *testing.T) {
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("password-stdin", true, "")
+	cmd.SetIn(&bytes.Buffer{})
+
+	_, err := readPassword(cmd)
+
+	assert.ErrorContains(t, err, "failed to read password from stdin")
+}
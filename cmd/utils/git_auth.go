@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/oar-cd/oar/services"
+	"github.com/spf13/cobra"
+)
+
+// AddGitAuthFlags adds the flags for authenticating to a Git repository
+func AddGitAuthFlags(cmd *cobra.Command) {
+	cmd.Flags().String("git-auth", "", "Git authentication method: http, ssh")
+	cmd.Flags().String("git-username", "", "Git username (for HTTP) or SSH user (for SSH)")
+	cmd.Flags().String("git-password", "", "Git password or token (for HTTP authentication)")
+	cmd.Flags().String("git-ssh-key-file", "", "Path to SSH private key file (for SSH authentication)")
+}
+
+// GitAuthFromFlags constructs GitAuthConfig from the --git-auth flags added by AddGitAuthFlags
+func GitAuthFromFlags(cmd *cobra.Command) (*services.GitAuthConfig, error) {
+	authMethod, _ := cmd.Flags().GetString("git-auth")
+
+	// No authentication specified
+	if authMethod == "" {
+		return nil, nil
+	}
+
+	switch authMethod {
+	case "http":
+		username, _ := cmd.Flags().GetString("git-username")
+		password, _ := cmd.Flags().GetString("git-password")
+
+		if username == "" && password == "" {
+			return nil, fmt.Errorf("HTTP authentication requires --git-username and --git-password")
+		}
+
+		return &services.GitAuthConfig{
+			HTTPAuth: &services.GitHTTPAuthConfig{
+				Username: username,
+				Password: password,
+			},
+		}, nil
+
+	case "ssh":
+		sshUser, _ := cmd.Flags().GetString("git-username")
+		keyFile, _ := cmd.Flags().GetString("git-ssh-key-file")
+
+		if keyFile == "" {
+			return nil, fmt.Errorf("SSH authentication requires --git-ssh-key-file")
+		}
+
+		// Read private key from file
+		privateKeyBytes, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read SSH key file %s: %w", keyFile, err)
+		}
+
+		privateKey := string(privateKeyBytes)
+
+		// Default SSH user to "git" if not specified
+		if sshUser == "" {
+			sshUser = "git"
+		}
+
+		return &services.GitAuthConfig{
+			SSHAuth: &services.GitSSHAuthConfig{
+				PrivateKey: privateKey,
+				User:       sshUser,
+			},
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("invalid authentication method %q, must be 'http' or 'ssh'", authMethod)
+	}
+}
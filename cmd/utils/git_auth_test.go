@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/oar-cd/oar/services"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitAuthFromFlags(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "id_ed25519")
+	require.NoError(t, os.WriteFile(keyFile, []byte("private key"), 0o600))
+
+	tests := []struct {
+		name        string
+		args        []string
+		expected    *services.GitAuthConfig
+		expectError string
+	}{
+		{name: "none", args: []string{}},
+		{
+			name: "http",
+			args: []string{"--git-auth", "http", "--git-username", "token", "--git-password", "secret"},
+			expected: &services.GitAuthConfig{
+				HTTPAuth: &services.GitHTTPAuthConfig{Username: "token", Password: "secret"},
+			},
+		},
+		{
+			name: "ssh with default user",
+			args: []string{"--git-auth", "ssh", "--git-ssh-key-file", keyFile},
+			expected: &services.GitAuthConfig{
+				SSHAuth: &services.GitSSHAuthConfig{PrivateKey: "private key", User: "git"},
+			},
+		},
+		{
+			name:        "http without credentials",
+			args:        []string{"--git-auth", "http"},
+			expectError: "requires --git-username and --git-password",
+		},
+		{name: "ssh without key", args: []string{"--git-auth", "ssh"}, expectError: "requires --git-ssh-key-file"},
+		{name: "unknown method", args: []string{"--git-auth", "token"}, expectError: "invalid authentication method"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			AddGitAuthFlags(cmd)
+			require.NoError(t, cmd.ParseFlags(tt.args))
+
+			gitAuth, err := GitAuthFromFlags(cmd)
+
+			if tt.expectError != "" {
+				assert.ErrorContains(t, err, tt.expectError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, gitAuth)
+		})
+	}
+}
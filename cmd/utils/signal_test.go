@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterruptContext_WithoutCommandContext(t *testing.T) {
+	ctx, cancel := InterruptContext(&cobra.Command{})
+	defer cancel()
+
+	assert.NoError(t, ctx.Err())
+	cancel()
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+}
+
+func TestInterruptContext_InheritsCommandContext(t *testing.T) {
+	parent, cancelParent := context.WithCancel(context.Background())
+	cmd := &cobra.Command{}
+	cmd.SetContext(parent)
+
+	ctx, cancel := InterruptContext(cmd)
+	defer cancel()
+
+	cancelParent()
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+}
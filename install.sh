@@ -75,6 +75,7 @@ echo "$LATEST_VERSION" >VERSION
 echo ""
 echo "Oar installed successfully ($LATEST_VERSION)!"
 echo "Access Oar web UI at http://127.0.0.1:8080"
+echo "Create an admin user to sign in with: oar user add <username> --role admin"
 echo "Installation directory: $OAR_DIR"
 echo "Data directory: $OAR_DIR/data"
 echo "CLI executable: $BIN_DIR/oar"
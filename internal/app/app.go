@@ -2,6 +2,7 @@
 package app
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/oar-cd/oar/db"
@@ -10,11 +11,19 @@ import (
 )
 
 var (
-	database         *gorm.DB
-	projectService   services.ProjectManager
-	discoveryService *services.ProjectDiscoveryService
-	gitService       services.GitExecutor
-	config           *services.Config
+	database            *gorm.DB
+	projectService      services.ProjectManager
+	authService         services.UserManager
+	roleService         services.RoleManager
+	notificationService services.NotificationManager
+	catalogService      services.CatalogManager
+	volumeBackupService services.VolumeBackupManager
+	actingUser          *services.User
+	discoveryService    *services.ProjectDiscoveryService
+	webhookService      *services.WebhookService
+	backupService       *services.BackupService
+	gitService          services.GitExecutor
+	config              *services.Config
 )
 
 // InitializeWithConfig initializes the app with a pre-configured Config
@@ -52,26 +61,186 @@ func InitializeWithConfig(cfg *services.Config) error {
 	// Initialize repositories
 	projectRepo := services.NewProjectRepository(database, encryption)
 	deploymentRepo := services.NewDeploymentRepository(database)
+	lockRepo := services.NewDeploymentLockRepository(database)
+	userRepo := services.NewUserRepository(database)
+	sessionRepo := services.NewSessionRepository(database)
+	roleBindingRepo := services.NewRoleBindingRepository(database)
+	notifierRepo := services.NewNotifierRepository(database, encryption)
+	catalogRepo := services.NewCatalogRepository(database, encryption)
 
 	// Initialize services with dependency injection
-	projectService = services.NewProjectService(projectRepo, deploymentRepo, gitService, config)
+	notifications := services.NewNotificationService(notifierRepo, projectRepo)
+	notificationService = notifications
+	projectService = services.NewProjectService(
+		projectRepo,
+		deploymentRepo,
+		lockRepo,
+		gitService,
+		notifications,
+		config,
+	)
+	catalogService = services.NewCatalogService(catalogRepo, projectService, gitService, config)
 	discoveryService = services.NewProjectDiscoveryService(gitService, config)
+	authService = services.NewAuthService(userRepo, sessionRepo, config)
+	roleService = services.NewAuthorizationService(roleBindingRepo)
+	webhookService = services.NewWebhookService(projectService, config)
+	backupService = services.NewBackupService(database, config)
+	volumeBackupService = services.NewVolumeBackupService(projectService, config)
+
+	// Resolve the user the CLI acts as, if any
+	actingUser = nil
+	if config.User != "" {
+		actingUser, err = authService.GetUser(config.User)
+		if err != nil {
+			return fmt.Errorf("user %q (OAR_USER) not found: %w", config.User, err)
+		}
+	}
 	return nil
 }
 
+// GetProjectService returns the project service, restricted to the acting user's roles if one is configured
 func GetProjectService() services.ProjectManager {
-	return projectService
+	return GetProjectServiceFor(actingUser)
+}
+
+// GetProjectServiceFor returns the project service restricted to the roles of user.
+// A nil user means the caller is trusted and gets unrestricted access.
+func GetProjectServiceFor(user *services.User) services.ProjectManager {
+	if user == nil {
+		return projectService
+	}
+	return services.NewAuthorizedProjectService(projectService, roleService, user)
+}
+
+// GetNotificationService returns the notification service, restricted to the acting user's roles if one is
+// configured
+func GetNotificationService() services.NotificationManager {
+	return GetNotificationServiceFor(actingUser)
+}
+
+// GetNotificationServiceFor returns the notification service restricted to the roles of user.
+// A nil user means the caller is trusted and gets unrestricted access.
+func GetNotificationServiceFor(user *services.User) services.NotificationManager {
+	if user == nil {
+		return notificationService
+	}
+	return services.NewAuthorizedNotificationService(notificationService, roleService, user)
+}
+
+// GetCatalogService returns the catalog service, restricted to the acting user's roles if one is configured
+func GetCatalogService() services.CatalogManager {
+	return GetCatalogServiceFor(actingUser)
+}
+
+// GetCatalogServiceFor returns the catalog service restricted to the roles of user.
+// A nil user means the caller is trusted and gets unrestricted access.
+func GetCatalogServiceFor(user *services.User) services.CatalogManager {
+	if user == nil {
+		return catalogService
+	}
+	return services.NewAuthorizedCatalogService(catalogService, roleService, user)
+}
+
+// GetVolumeBackupService returns the volume backup service, restricted to the acting user's roles if one is
+// configured
+func GetVolumeBackupService() services.VolumeBackupManager {
+	return GetVolumeBackupServiceFor(actingUser)
+}
+
+// GetVolumeBackupServiceFor returns the volume backup service restricted to the roles of user.
+// A nil user means the caller is trusted and gets unrestricted access.
+func GetVolumeBackupServiceFor(user *services.User) services.VolumeBackupManager {
+	if user == nil {
+		return volumeBackupService
+	}
+	return services.NewAuthorizedVolumeBackupService(volumeBackupService, roleService, user)
+}
+
+// GetExportService returns the export service, working with the projects, catalogs and notifiers the acting user,
+// if any, has access to
+func GetExportService() *services.ExportService {
+	return services.NewExportService(GetProjectService(), GetCatalogService(), GetNotificationService())
+}
+
+func GetAuthService() services.UserManager {
+	return authService
+}
+
+func GetRoleService() services.RoleManager {
+	return roleService
+}
+
+// GetActingUser returns the user the CLI acts as, or nil if access is unrestricted
+func GetActingUser() *services.User {
+	return actingUser
 }
 
 func GetDiscoveryService() *services.ProjectDiscoveryService {
 	return discoveryService
 }
 
+func GetWebhookService() *services.WebhookService {
+	return webhookService
+}
+
+func GetBackupService() *services.BackupService {
+	return backupService
+}
+
 func GetGitService() services.GitExecutor {
 	return gitService
 }
 
+func GetConfig() *services.Config {
+	return config
+}
+
 // SetProjectServiceForTesting allows overriding the project service for testing purposes
 func SetProjectServiceForTesting(service services.ProjectManager) {
 	projectService = service
 }
+
+// SetNotificationServiceForTesting allows overriding the notification service for testing purposes
+func SetNotificationServiceForTesting(service services.NotificationManager) {
+	notificationService = service
+}
+
+// SetCatalogServiceForTesting allows overriding the catalog service for testing purposes
+func SetCatalogServiceForTesting(service services.CatalogManager) {
+	catalogService = service
+}
+
+// SetVolumeBackupServiceForTesting allows overriding the volume backup service for testing purposes
+func SetVolumeBackupServiceForTesting(service services.VolumeBackupManager) {
+	volumeBackupService = service
+}
+
+// SetAuthServiceForTesting allows overriding the auth service for testing purposes
+func SetAuthServiceForTesting(service services.UserManager) {
+	authService = service
+}
+
+// SetWebhookServiceForTesting allows overriding the webhook service for testing purposes
+func SetWebhookServiceForTesting(service *services.WebhookService) {
+	webhookService = service
+}
+
+// SetBackupServiceForTesting allows overriding the backup service for testing purposes
+func SetBackupServiceForTesting(service *services.BackupService) {
+	backupService = service
+}
+
+// SetRoleServiceForTesting allows overriding the role service for testing purposes
+func SetRoleServiceForTesting(service services.RoleManager) {
+	roleService = service
+}
+
+// SetConfigForTesting allows overriding the configuration for testing purposes
+func SetConfigForTesting(c *services.Config) {
+	config = c
+}
+
+// SetActingUserForTesting allows overriding the acting user for testing purposes
+func SetActingUserForTesting(user *services.User) {
+	actingUser = user
+}
@@ -8,6 +8,12 @@ func AllModels() []any {
 	return []any{
 		&ProjectModel{},
 		&DeploymentModel{},
+		&DeploymentLockModel{},
+		&UserModel{},
+		&SessionModel{},
+		&RoleBindingModel{},
+		&NotifierModel{},
+		&CatalogModel{},
 	}
 }
 
@@ -18,14 +18,31 @@ type ProjectModel struct {
 	Name               string  `gorm:"not null;unique;check:name <> ''"`
 	GitURL             string  `gorm:"not null;check:git_url <> ''"`
 	GitBranch          string  `gorm:"not null;check:git_branch <> ''"`    // Git branch (never empty, always set to default branch if not specified)
+	GitRefType         string  `gorm:"not null;default:'branch'"`          // branch, tag, commit
+	GitRef             string  `gorm:"not null;default:''"`                // tag pattern or semver constraint, or commit SHA
 	GitAuthType        *string `gorm:"type:varchar(20)"`                   // "http", "ssh", "oauth", etc.
 	GitAuthCredentials *string `gorm:"type:text"`                          // Encrypted JSON blob containing all auth data
 	WorkingDir         string  `gorm:"not null;check:working_dir <> ''"`   // directory where the project is cloned
 	ComposeFiles       string  `gorm:"not null;check:compose_files <> ''"` // list of compose file paths separated by null character (\0)
 	Variables          string  `gorm:"not null"`                           // Variables separated by null character (\0)
 	Status             string  `gorm:"not null;check:status <> ''"`        // running, stopped, error
+	DesiredState       string  `gorm:"not null;default:''"`                // running, stopped, empty if not recorded
 	LastCommit         *string
-	WatcherEnabled     bool `gorm:"not null"` // Enable automatic deployments on git changes
+	RolledBackCommit   *string    // commit the project was rolled back from, not redeployed automatically
+	WatcherEnabled     bool       `gorm:"not null"`            // Enable automatic deployments on git changes
+	AutoRollback       bool       `gorm:"not null"`            // Roll back when a deployment fails
+	RequireApproval    bool       `gorm:"not null;default:0"`  // Detected changes wait for approval before deploying
+	WatchImages        bool       `gorm:"not null;default:0"`  // Redeploy when images have new digests
+	BackupVolumes      bool       `gorm:"not null;default:0"`  // Back up named volumes before deploying
+	ComposePullTimeout int        `gorm:"not null;default:0"`  // Seconds, 0 uses the global default
+	ComposeUpTimeout   int        `gorm:"not null;default:0"`  // Seconds, 0 uses the global default
+	ComposeWaitTimeout int        `gorm:"not null;default:0"`  // Seconds, 0 uses the global default
+	HealthChecks       string     `gorm:"not null;default:''"` // Health checks separated by null character (\0)
+	HealthCheckTimeout int        `gorm:"not null;default:0"`  // Seconds, 0 uses the global default
+	PreDeployHooks     string     `gorm:"not null;default:''"` // Hooks separated by null character (\0)
+	PostDeployHooks    string     `gorm:"not null;default:''"` // Hooks separated by null character (\0)
+	Drift              string     `gorm:"not null;default:''"` // Drift descriptions separated by null character (\0)
+	CatalogID          *uuid.UUID `gorm:"type:char(36);index"` // Catalog managing the project, nil if added by hand
 
 	Deployments []DeploymentModel `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE"`
 }
@@ -36,10 +53,18 @@ func (ProjectModel) TableName() string {
 
 type DeploymentModel struct {
 	BaseModel
-	ProjectID  uuid.UUID `gorm:"not null;index"`
-	CommitHash string    `gorm:"not null;check:commit_hash <> ''"`
-	Status     string    `gorm:"not null;check:status <> ''"` // in_progress, success, failed
-	Output     string    `gorm:"type:text"`                   // Command output/logs
+	ProjectID      uuid.UUID  `gorm:"not null;index"`
+	CommitHash     string     `gorm:"not null;check:commit_hash <> ''"`
+	PreviousCommit string     // Commit deployed before, empty for the first deployment
+	Status         string     `gorm:"not null;check:status <> ''"` // in_progress, success, failed
+	Output         string     `gorm:"type:text"`                   // Command output/logs
+	Rollback       bool       `gorm:"not null;default:false"`      // Whether the deployment rolled back to an earlier commit
+	Pull           bool       `gorm:"not null;default:false"`      // Whether Git was pulled before deploying
+	Trigger        string     `gorm:"not null;default:unknown"`    // web, cli, api, watcher, webhook, rollback
+	Actor          string     // Who started the deployment
+	HealthChecks   string     `gorm:"type:text"` // JSON results of the project's health checks
+	StartedAt      *time.Time // Nil for deployments recorded before it was tracked
+	FinishedAt     *time.Time // Nil while the deployment runs
 
 	Project ProjectModel `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE"`
 }
@@ -47,3 +72,91 @@ type DeploymentModel struct {
 func (DeploymentModel) TableName() string {
 	return "deployments"
 }
+
+type DeploymentLockModel struct {
+	BaseModel
+	ProjectID uuid.UUID `gorm:"not null;uniqueIndex"`
+	Holder    string    `gorm:"not null;check:holder <> ''"` // Host and process of the deployment
+	Trigger   string    `gorm:"not null;default:unknown"`    // web, cli, api, watcher, webhook, rollback
+	Actor     string    // Who started the deployment
+	ExpiresAt time.Time `gorm:"not null"` // Renewed while the deployment runs, stale afterwards
+
+	CancelRequested bool `gorm:"not null;default:0"` // Set by other processes to cancel the deployment
+
+	Project ProjectModel `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE"`
+}
+
+func (DeploymentLockModel) TableName() string {
+	return "deployment_locks"
+}
+
+type UserModel struct {
+	BaseModel
+	Username     string `gorm:"not null;unique;check:username <> ''"`
+	PasswordHash string `gorm:"not null;check:password_hash <> ''"` // bcrypt hash
+
+	Sessions []SessionModel `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+func (UserModel) TableName() string {
+	return "users"
+}
+
+type SessionModel struct {
+	BaseModel
+	UserID    uuid.UUID `gorm:"not null;index"`
+	TokenHash string    `gorm:"not null;unique;check:token_hash <> ''"` // SHA-256 of the session token, the token itself is only stored in the cookie
+	ExpiresAt time.Time `gorm:"not null;index"`
+
+	User UserModel `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+func (SessionModel) TableName() string {
+	return "sessions"
+}
+
+type RoleBindingModel struct {
+	BaseModel
+	UserID    uuid.UUID  `gorm:"not null;index"`
+	ProjectID *uuid.UUID `gorm:"index"`                     // nil for a global role that applies to all projects
+	Role      string     `gorm:"not null;check:role <> ''"` // viewer, deployer, admin
+
+	User    UserModel    `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Project ProjectModel `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE"`
+}
+
+func (RoleBindingModel) TableName() string {
+	return "role_bindings"
+}
+
+type NotifierModel struct {
+	BaseModel
+	ProjectID uuid.UUID `gorm:"not null;index"`
+	Type      string    `gorm:"not null;check:type <> ''"`   // webhook, slack, email, ntfy, gotify
+	Events    string    `gorm:"not null"`                    // Events separated by null character (\0), all events if empty
+	Config    string    `gorm:"not null;check:config <> ''"` // Encrypted JSON blob with the destination and its credentials
+
+	Project ProjectModel `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE"`
+}
+
+func (NotifierModel) TableName() string {
+	return "notifiers"
+}
+
+type CatalogModel struct {
+	BaseModel
+	Name               string     `gorm:"not null;unique;check:name <> ''"`
+	GitURL             string     `gorm:"not null;check:git_url <> ''"`
+	GitBranch          string     `gorm:"not null;check:git_branch <> ''"`
+	GitAuthType        *string    `gorm:"type:varchar(20)"`                 // "http", "ssh", "oauth", etc.
+	GitAuthCredentials *string    `gorm:"type:text"`                        // Encrypted JSON blob containing all auth data
+	WorkingDir         string     `gorm:"not null;check:working_dir <> ''"` // directory where the catalog is cloned
+	Secrets            string     `gorm:"type:text;not null;default:''"`    // Encrypted secrets separated by null character (\0)
+	LastCommit         *string    // commit of the last sync
+	LastSyncedAt       *time.Time // nil until the catalog is synced
+	LastSyncError      string     `gorm:"type:text"` // why the last sync failed, empty if it didn't
+}
+
+func (CatalogModel) TableName() string {
+	return "catalogs"
+}
@@ -518,3 +518,166 @@ func TestProjectModel_Create_NullComposeFiles_Prevention(t *testing.T) {
 	assert.Error(t, result.Error, "Database should reject empty ComposeFiles")
 	assert.Contains(t, result.Error.Error(), "constraint", "Error should mention constraint violation")
 }
+
+// Tests for UserModel and SessionModel
+func TestUserModel_Create_UniqueUsernameConstraint(t *testing.T) {
+	db := setupTestDB(t)
+
+	user1 := createTestUserModel()
+	require.NoError(t, db.Create(user1).Error)
+
+	user2 := createTestUserModel()
+	result := db.Create(user2)
+
+	assert.Error(t, result.Error)
+	assert.Contains(t, result.Error.Error(), "UNIQUE constraint failed")
+}
+
+func TestUserModel_Create_FieldValidation(t *testing.T) {
+	db := setupTestDB(t)
+
+	tests := []struct {
+		name        string
+		modifyModel func(*UserModel)
+	}{
+		{
+			name:        "empty username",
+			modifyModel: func(u *UserModel) { u.Username = "" },
+		},
+		{
+			name:        "empty password hash",
+			modifyModel: func(u *UserModel) { u.PasswordHash = "" },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := createTestUserModel()
+			tt.modifyModel(user)
+
+			result := db.Create(user)
+
+			assert.Error(t, result.Error)
+			assert.Contains(t, result.Error.Error(), "CHECK constraint failed")
+		})
+	}
+}
+
+func TestSessionModel_ForeignKeyConstraint(t *testing.T) {
+	db := setupTestDB(t)
+
+	session := createTestSessionModel(uuid.New())
+	result := db.Create(session)
+
+	assert.Error(t, result.Error)
+	assert.Contains(t, result.Error.Error(), "FOREIGN KEY constraint failed")
+}
+
+func TestUserModel_CascadeDeletesSessions(t *testing.T) {
+	db := setupTestDB(t)
+
+	user := createTestUserModel()
+	require.NoError(t, db.Create(user).Error)
+	require.NoError(t, db.Create(createTestSessionModel(user.ID)).Error)
+
+	require.NoError(t, db.Delete(user).Error)
+
+	var count int64
+	db.Model(&SessionModel{}).Where("user_id = ?", user.ID).Count(&count)
+	assert.Equal(t, int64(0), count, "Sessions should be deleted via CASCADE")
+}
+
+// Tests for RoleBindingModel
+func TestRoleBindingModel_GlobalAndProjectScoped(t *testing.T) {
+	db := setupTestDB(t)
+
+	user := createTestUserModel()
+	require.NoError(t, db.Create(user).Error)
+	project := createTestProjectModel()
+	require.NoError(t, db.Create(project).Error)
+
+	require.NoError(t, db.Create(createTestRoleBindingModel(user.ID, nil)).Error)
+	require.NoError(t, db.Create(createTestRoleBindingModel(user.ID, &project.ID)).Error)
+
+	var count int64
+	db.Model(&RoleBindingModel{}).Where("user_id = ?", user.ID).Count(&count)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestRoleBindingModel_CascadeOnProjectAndUserDelete(t *testing.T) {
+	db := setupTestDB(t)
+
+	user := createTestUserModel()
+	require.NoError(t, db.Create(user).Error)
+	project := createTestProjectModel()
+	require.NoError(t, db.Create(project).Error)
+
+	require.NoError(t, db.Create(createTestRoleBindingModel(user.ID, nil)).Error)
+	require.NoError(t, db.Create(createTestRoleBindingModel(user.ID, &project.ID)).Error)
+
+	// Deleting the project removes only its project-scoped binding
+	require.NoError(t, db.Delete(project).Error)
+	var count int64
+	db.Model(&RoleBindingModel{}).Where("user_id = ?", user.ID).Count(&count)
+	assert.Equal(t, int64(1), count)
+
+	// Deleting the user removes the rest
+	require.NoError(t, db.Delete(user).Error)
+	db.Model(&RoleBindingModel{}).Where("user_id = ?", user.ID).Count(&count)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestRoleBindingModel_Create_EmptyRole(t *testing.T) {
+	db := setupTestDB(t)
+
+	user := createTestUserModel()
+	require.NoError(t, db.Create(user).Error)
+
+	binding := createTestRoleBindingModel(user.ID, nil)
+	binding.Role = ""
+
+	result := db.Create(binding)
+	assert.Error(t, result.Error)
+	assert.Contains(t, result.Error.Error(), "CHECK constraint failed")
+}
+
+// Tests for DeploymentLockModel
+func TestDeploymentLockModel_UniqueProjectConstraint(t *testing.T) {
+	db := setupTestDB(t)
+
+	project := createTestProjectModel()
+	require.NoError(t, db.Create(project).Error)
+	require.NoError(t, db.Create(createTestDeploymentLockModel(project.ID)).Error)
+
+	result := db.Create(createTestDeploymentLockModel(project.ID))
+	assert.Error(t, result.Error)
+	assert.Contains(t, result.Error.Error(), "UNIQUE constraint failed")
+}
+
+func TestDeploymentLockModel_Create_EmptyHolder(t *testing.T) {
+	db := setupTestDB(t)
+
+	project := createTestProjectModel()
+	require.NoError(t, db.Create(project).Error)
+
+	lock := createTestDeploymentLockModel(project.ID)
+	lock.Holder = ""
+
+	result := db.Create(lock)
+	assert.Error(t, result.Error)
+	assert.Contains(t, result.Error.Error(), "CHECK constraint failed")
+}
+
+func TestDeploymentLockModel_CascadeOnProjectDelete(t *testing.T) {
+	db := setupTestDB(t)
+
+	project := createTestProjectModel()
+	require.NoError(t, db.Create(project).Error)
+	require.NoError(t, db.Create(createTestDeploymentLockModel(project.ID)).Error)
+
+	require.NoError(t, db.Delete(project).Error)
+
+	var count int64
+	db.Model(&DeploymentLockModel{}).Where("project_id = ?", project.ID).Count(&count)
+	assert.Equal(t, int64(0), count, "Deployment locks should be deleted via CASCADE")
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
@@ -57,6 +58,54 @@ func createTestDeploymentModel(projectID uuid.UUID) *DeploymentModel {
 	}
 }
 
+// createTestUserModel creates a test user model for database layer testing
+func createTestUserModel() *UserModel {
+	return &UserModel{
+		BaseModel: BaseModel{
+			ID: uuid.New(),
+		},
+		Username:     "alice",
+		PasswordHash: "$2a$10$abcdefghijklmnopqrstuv",
+	}
+}
+
+// createTestSessionModel creates a test session model for database layer testing
+func createTestSessionModel(userID uuid.UUID) *SessionModel {
+	return &SessionModel{
+		BaseModel: BaseModel{
+			ID: uuid.New(),
+		},
+		UserID:    userID,
+		TokenHash: uuid.NewString(),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+}
+
+// createTestRoleBindingModel creates a test role binding model for database layer testing
+func createTestRoleBindingModel(userID uuid.UUID, projectID *uuid.UUID) *RoleBindingModel {
+	return &RoleBindingModel{
+		BaseModel: BaseModel{
+			ID: uuid.New(),
+		},
+		UserID:    userID,
+		ProjectID: projectID,
+		Role:      "viewer",
+	}
+}
+
+// createTestDeploymentLockModel creates a test deployment lock model for database layer testing
+func createTestDeploymentLockModel(projectID uuid.UUID) *DeploymentLockModel {
+	return &DeploymentLockModel{
+		BaseModel: BaseModel{
+			ID: uuid.New(),
+		},
+		ProjectID: projectID,
+		Holder:    "oar (pid 1)",
+		Trigger:   "web",
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+}
+
 // Utility functions
 func stringPtr(s string) *string {
 	return &s
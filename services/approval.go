@@ -0,0 +1,245 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrCommitRejected is returned when requesting approval of a commit whose deployment was rejected before
+var ErrCommitRejected = errors.New("commit was rejected")
+
+// ErrDeploymentNotPending is returned when approving or rejecting a deployment that isn't pending approval
+var ErrDeploymentNotPending = errors.New("deployment is not pending approval")
+
+// ErrApprovalWithoutWatcher is returned when saving a project that requires approval but doesn't deploy
+// automatically, since only the changes the watcher or webhooks deploy wait for approval
+var ErrApprovalWithoutWatcher = errors.New("requiring approval needs automatic deployments to be enabled")
+
+// RequestApproval records a pending deployment of commit for a project whose changes require approval, instead of
+// deploying it. An empty commit fetches the remote and uses the commit the project's branch, tag pattern or pinned
+// commit resolves to. Pending deployments of other commits are superseded and recorded as rejected, a commit already
+// pending returns its deployment, and a commit that was rejected returns ErrCommitRejected, so that it isn't asked
+// about again until a newer one is pushed. Nil is returned when the commit is the deployed one.
+//
+// With the image trigger, the redeployment of the deployed commit with new images is requested instead. It doesn't
+// supersede a pending deployment, which is returned, and once rejected it isn't asked about again until another
+// commit is deployed.
+func (s *ProjectService) RequestApproval(
+	projectID uuid.UUID,
+	commit string,
+	options DeployOptions,
+) (*Deployment, error) {
+	project, err := s.Get(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("project not found: %w", err)
+	}
+	if s.deploying(projectID) {
+		return nil, ErrDeploymentInProgress
+	}
+
+	if commit == "" {
+		commit, _, err = FetchTargetCommit(s.gitService, project)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %w", project.GitRefDescription(), err)
+		}
+	}
+	images := options.Trigger == DeploymentTriggerImage
+	if commit == project.LastCommitStr() && !images {
+		return nil, nil
+	}
+
+	deployments, err := s.deploymentRepository.ListByProjectID(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	for _, d := range deployments {
+		if images && d.Status == DeploymentStatusPending {
+			return d, nil
+		}
+	}
+	for _, d := range deployments {
+		if d.CommitHash != commit || (d.Trigger == DeploymentTriggerImage) != images {
+			continue
+		}
+		switch d.Status {
+		case DeploymentStatusPending:
+			return d, nil
+		case DeploymentStatusRejected:
+			return nil, fmt.Errorf("%w: %s", ErrCommitRejected, shortCommit(commit))
+		}
+	}
+
+	for _, d := range deployments {
+		if d.Status != DeploymentStatusPending {
+			continue
+		}
+		if err := s.reject(d, "", fmt.Sprintf("Superseded by commit %s", shortCommit(commit))); err != nil {
+			return nil, err
+		}
+	}
+
+	deployment := NewDeployment(projectID, commit)
+	deployment.Status = DeploymentStatusPending
+	deployment.PreviousCommit = project.LastCommitStr()
+	deployment.Trigger = options.Trigger
+	deployment.Actor = options.Actor
+	if err := s.deploymentRepository.Create(&deployment); err != nil {
+		return nil, fmt.Errorf("failed to create deployment record: %w", err)
+	}
+
+	slog.Info("Deployment awaiting approval",
+		"project_id", project.ID,
+		"project_name", project.Name,
+		"deployment_id", deployment.ID,
+		"commit_hash", commit,
+		"trigger", options.Trigger.String(),
+		"actor", options.Actor)
+	s.notify(NotificationEventApprovalRequired, project, &deployment)
+	return &deployment, nil
+}
+
+// ApproveStreaming checks out the commit of a pending deployment and deploys it, streaming output to outputChan.
+// The pending deployment becomes the deployment, keeping the trigger that requested it, with the actor of options
+// as who approved it. Approved deployments never pull from Git, the commit was fetched when it was detected, and
+// pull images when new ones were detected.
+func (s *ProjectService) ApproveStreaming(
+	ctx context.Context,
+	projectID uuid.UUID,
+	deploymentID uuid.UUID,
+	options DeployOptions,
+	outputChan chan<- string,
+) error {
+	pending, err := s.pendingDeployment(projectID, deploymentID)
+	if err != nil {
+		return err
+	}
+	options.Pull = false
+	options.PullImages = pending.Trigger == DeploymentTriggerImage
+	options.Trigger = pending.Trigger
+
+	unlock, err := s.lockProject(projectID, options)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	ctx, done := s.startCancellable(ctx, projectID)
+	defer done()
+
+	// Approved or rejected by someone else while waiting for the lock
+	pending, err = s.pendingDeployment(projectID, deploymentID)
+	if err != nil {
+		return err
+	}
+
+	project, err := s.Get(projectID)
+	if err != nil {
+		return fmt.Errorf("project not found: %w", err)
+	}
+	gitDir, err := project.GitDir()
+	if err != nil {
+		return fmt.Errorf("failed to get git directory: %w", err)
+	}
+
+	output := &deploymentOutput{outputChan: outputChan}
+	output.send(fmt.Sprintf("Deploying approved commit %s...", shortCommit(pending.CommitHash)), "info", "oar")
+
+	if err := s.gitService.Checkout(pending.CommitHash, gitDir); err != nil {
+		output.send(fmt.Sprintf("Failed to check out commit: %v", err), "error", "oar")
+		return fmt.Errorf("failed to check out commit: %w", err)
+	}
+
+	project, commitHash, deployment, err := s.prepareDeployment(projectID, options, pending)
+	if err != nil {
+		return err
+	}
+
+	output.send(fmt.Sprintf("Checked out commit %s", shortCommit(commitHash)), "success", "oar")
+
+	return s.composeUpStreaming(ctx, project, commitHash, deployment, options.PullImages, output)
+}
+
+func (s *ProjectService) ApprovePiping(
+	ctx context.Context,
+	projectID uuid.UUID,
+	deploymentID uuid.UUID,
+	options DeployOptions,
+) error {
+	outputChan := make(chan string, 100)
+	done := make(chan bool)
+
+	go func() {
+		defer func() { done <- true }()
+		for msg := range outputChan {
+			var msgData map[string]string
+			if err := json.Unmarshal([]byte(msg), &msgData); err == nil {
+				fmt.Println(msgData["message"])
+			}
+		}
+	}()
+
+	err := s.ApproveStreaming(ctx, projectID, deploymentID, options, outputChan)
+
+	close(outputChan)
+	<-done
+
+	return err
+}
+
+// RejectDeployment records a pending deployment as rejected by the actor of options. Its commit is not deployed,
+// and automatic deployments don't ask about it again.
+func (s *ProjectService) RejectDeployment(projectID, deploymentID uuid.UUID, options DeployOptions) error {
+	deployment, err := s.pendingDeployment(projectID, deploymentID)
+	if err != nil {
+		return err
+	}
+
+	reason := "Rejected"
+	if options.Actor != "" {
+		reason += " by " + options.Actor
+	}
+	return s.reject(deployment, options.Actor, reason)
+}
+
+// reject records a pending deployment as rejected, with the reason as its output
+func (s *ProjectService) reject(deployment *Deployment, actor, reason string) error {
+	finishedAt := time.Now()
+	deployment.Status = DeploymentStatusRejected
+	deployment.FinishedAt = &finishedAt
+	deployment.Output = reason
+	if actor != "" {
+		deployment.Actor = actor
+	}
+	if err := s.deploymentRepository.Update(deployment); err != nil {
+		return fmt.Errorf("failed to update deployment record: %w", err)
+	}
+
+	slog.Info("Pending deployment rejected",
+		"project_id", deployment.ProjectID,
+		"deployment_id", deployment.ID,
+		"commit_hash", deployment.CommitHash,
+		"reason", reason)
+	return nil
+}
+
+// pendingDeployment returns a deployment of a project that is pending approval
+func (s *ProjectService) pendingDeployment(projectID, deploymentID uuid.UUID) (*Deployment, error) {
+	deployment, err := s.deploymentRepository.FindByID(deploymentID)
+	if err != nil {
+		return nil, fmt.Errorf("deployment not found: %w", err)
+	}
+	if deployment.ProjectID != projectID {
+		return nil, fmt.Errorf("deployment not found: %w", gorm.ErrRecordNotFound)
+	}
+	if deployment.Status != DeploymentStatusPending {
+		return nil, fmt.Errorf("%w: deployment %s is %s", ErrDeploymentNotPending, deploymentID, deployment.Status)
+	}
+	return deployment, nil
+}
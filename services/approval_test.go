@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectService_RequestApproval(t *testing.T) {
+	service, repo, deploymentRepo, _, _ := setupMockProjectService(t)
+	notifications := &MockNotificationSender{}
+	service.notifications = notifications
+
+	project := createTestProject()
+	project.RequireApproval = true
+	repo.projects[project.ID] = project
+
+	options := DeployOptions{Trigger: DeploymentTriggerWatcher}
+	pending, err := service.RequestApproval(project.ID, "bbbb2222", options)
+	require.NoError(t, err)
+	assert.Equal(t, DeploymentStatusPending, pending.Status)
+	assert.Equal(t, "bbbb2222", pending.CommitHash)
+	assert.Equal(t, project.LastCommitStr(), pending.PreviousCommit)
+	assert.Equal(t, DeploymentTriggerWatcher, pending.Trigger)
+	assert.True(t, pending.StartedAt.IsZero())
+	assert.Equal(t, []NotificationEvent{NotificationEventApprovalRequired}, notifications.Events())
+
+	// Asking again about the same commit returns its pending deployment
+	again, err := service.RequestApproval(project.ID, "bbbb2222", options)
+	require.NoError(t, err)
+	assert.Equal(t, pending.ID, again.ID)
+	assert.Len(t, deploymentRepo.deployments, 1)
+
+	// A newer commit supersedes it
+	newer, err := service.RequestApproval(project.ID, "cccc3333", options)
+	require.NoError(t, err)
+	assert.Equal(t, DeploymentStatusPending, newer.Status)
+	superseded := deploymentRepo.deployments[pending.ID]
+	assert.Equal(t, DeploymentStatusRejected, superseded.Status)
+	assert.Equal(t, "Superseded by commit cccc3333", superseded.Output)
+	assert.NotNil(t, superseded.FinishedAt)
+
+	// Rejected commits aren't asked about again
+	_, err = service.RequestApproval(project.ID, "bbbb2222", options)
+	assert.ErrorIs(t, err, ErrCommitRejected)
+
+	// Nor is the deployed commit
+	deployment, err := service.RequestApproval(project.ID, project.LastCommitStr(), options)
+	require.NoError(t, err)
+	assert.Nil(t, deployment)
+	assert.Len(t, deploymentRepo.deployments, 2)
+}
+
+func TestProjectService_RequestApproval_Images(t *testing.T) {
+	service, repo, deploymentRepo, _, _ := setupMockProjectService(t)
+
+	project := createTestProject()
+	project.RequireApproval = true
+	repo.projects[project.ID] = project
+
+	// New images of the deployed commit
+	options := DeployOptions{Trigger: DeploymentTriggerImage}
+	pending, err := service.RequestApproval(project.ID, project.LastCommitStr(), options)
+	require.NoError(t, err)
+	assert.Equal(t, DeploymentStatusPending, pending.Status)
+	assert.Equal(t, project.LastCommitStr(), pending.CommitHash)
+	assert.Equal(t, DeploymentTriggerImage, pending.Trigger)
+
+	// Superseded by a new commit, which it doesn't supersede in turn
+	newer, err := service.RequestApproval(project.ID, "bbbb2222", DeployOptions{Trigger: DeploymentTriggerWatcher})
+	require.NoError(t, err)
+	assert.Equal(t, DeploymentStatusRejected, deploymentRepo.deployments[pending.ID].Status)
+
+	again, err := service.RequestApproval(project.ID, project.LastCommitStr(), options)
+	require.NoError(t, err)
+	assert.Equal(t, newer.ID, again.ID)
+	assert.Len(t, deploymentRepo.deployments, 2)
+
+	// Like rejected commits, it isn't asked about again until another commit is deployed
+	require.NoError(t, service.RejectDeployment(project.ID, newer.ID, DeployOptions{}))
+	_, err = service.RequestApproval(project.ID, project.LastCommitStr(), options)
+	assert.ErrorIs(t, err, ErrCommitRejected)
+}
+
+func TestProjectService_RequestApproval_ResolvesTarget(t *testing.T) {
+	service, repo, _, gitService, _ := setupMockProjectService(t)
+
+	project := createTestProject()
+	repo.projects[project.ID] = project
+
+	gitService.GetRemoteLatestCommitFunc = func(workingDir string, gitBranch string) (string, error) {
+		return "dddd4444", nil
+	}
+
+	options := DeployOptions{Trigger: DeploymentTriggerWebhook, Actor: "octocat"}
+	pending, err := service.RequestApproval(project.ID, "", options)
+	require.NoError(t, err)
+	assert.Equal(t, "dddd4444", pending.CommitHash)
+	assert.Equal(t, "octocat", pending.Actor)
+
+	gitService.FetchFunc = func(gitBranch string, gitAuth *GitAuthConfig, workingDir string) error {
+		return errors.New("network unreachable")
+	}
+	_, err = service.RequestApproval(project.ID, "", options)
+	assert.ErrorContains(t, err, "failed to resolve branch main")
+}
+
+func TestProjectService_ApproveStreaming(t *testing.T) {
+	service, repo, deploymentRepo, gitService, _ := setupMockProjectService(t)
+
+	project := createTestProject()
+	repo.projects[project.ID] = project
+	pending := addTestDeployments(deploymentRepo, project.ID, DeploymentStatusPending, "bbbb2222")[0]
+	pending.Trigger = DeploymentTriggerWatcher
+
+	checkedOut := ""
+	gitService.CheckoutFunc = func(commit string, workingDir string) error {
+		checkedOut = commit
+		return nil
+	}
+	gitService.GetLatestCommitFunc = func(workingDir string) (string, error) {
+		return checkedOut, nil
+	}
+
+	outputChan := make(chan string, 100)
+	options := DeployOptions{Pull: true, Trigger: DeploymentTriggerWeb, Actor: "alice"}
+	err := service.ApproveStreaming(context.Background(), project.ID, pending.ID, options, outputChan)
+	close(outputChan)
+
+	// Docker Compose is not available, but the commit is checked out and the pending deployment is the deployment
+	assert.Error(t, err)
+	assert.Equal(t, "bbbb2222", checkedOut)
+	require.Len(t, deploymentRepo.deployments, 1)
+
+	deployment := deploymentRepo.deployments[pending.ID]
+	assert.Equal(t, DeploymentStatusFailed, deployment.Status)
+	assert.Contains(t, deployment.Output, "Deploying approved commit bbbb2222")
+	assert.Equal(t, DeploymentTriggerWatcher, deployment.Trigger)
+	assert.Equal(t, "alice", deployment.Actor)
+	assert.False(t, deployment.Pull)
+	assert.False(t, deployment.StartedAt.IsZero())
+
+	// It is no longer pending
+	err = service.ApproveStreaming(context.Background(), project.ID, pending.ID, options, make(chan string, 100))
+	assert.ErrorIs(t, err, ErrDeploymentNotPending)
+}
+
+func TestProjectService_ApproveStreaming_CheckoutFails(t *testing.T) {
+	service, repo, deploymentRepo, gitService, _ := setupMockProjectService(t)
+
+	project := createTestProject()
+	repo.projects[project.ID] = project
+	pending := addTestDeployments(deploymentRepo, project.ID, DeploymentStatusPending, "bbbb2222")[0]
+
+	gitService.CheckoutFunc = func(commit string, workingDir string) error {
+		return errors.New("object not found")
+	}
+
+	outputChan := make(chan string, 100)
+	err := service.ApproveStreaming(context.Background(), project.ID, pending.ID, DeployOptions{}, outputChan)
+	close(outputChan)
+
+	assert.ErrorContains(t, err, "failed to check out commit")
+	assert.Equal(t, DeploymentStatusPending, deploymentRepo.deployments[pending.ID].Status)
+}
+
+func TestProjectService_RejectDeployment(t *testing.T) {
+	service, repo, deploymentRepo, _, _ := setupMockProjectService(t)
+
+	project := createTestProject()
+	repo.projects[project.ID] = project
+	pending := addTestDeployments(deploymentRepo, project.ID, DeploymentStatusPending, "bbbb2222")[0]
+	completed := addTestDeployments(deploymentRepo, project.ID, DeploymentStatusCompleted, "cccc3333")[0]
+	other := addTestDeployments(deploymentRepo, uuid.New(), DeploymentStatusPending, "dddd4444")[0]
+
+	err := service.RejectDeployment(project.ID, pending.ID, DeployOptions{Actor: "alice"})
+	require.NoError(t, err)
+	rejected := deploymentRepo.deployments[pending.ID]
+	assert.Equal(t, DeploymentStatusRejected, rejected.Status)
+	assert.Equal(t, "alice", rejected.Actor)
+	assert.Equal(t, "Rejected by alice", rejected.Output)
+	assert.NotNil(t, rejected.FinishedAt)
+
+	err = service.RejectDeployment(project.ID, completed.ID, DeployOptions{})
+	assert.ErrorIs(t, err, ErrDeploymentNotPending)
+
+	// Deployments of other projects aren't found
+	err = service.RejectDeployment(project.ID, other.ID, DeployOptions{})
+	assert.True(t, IsNotFound(err))
+}
@@ -0,0 +1,269 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// MinPasswordLength is the minimum accepted password length
+	MinPasswordLength = 8
+	// maxPasswordLength is the bcrypt input limit in bytes
+	maxPasswordLength = 72
+	// sessionTokenBytes is the amount of random data in a session token
+	sessionTokenBytes = 32
+)
+
+var (
+	// ErrInvalidCredentials is returned when a username/password pair does not match
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	// ErrInvalidSession is returned when a session token is unknown or expired
+	ErrInvalidSession = errors.New("invalid or expired session")
+)
+
+// dummyPasswordHash is compared against when a username does not exist,
+// so that unknown and known usernames take the same time to reject
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("oar-dummy-password"), bcrypt.DefaultCost)
+
+// AuthService manages local user accounts and their login sessions.
+type AuthService struct {
+	userRepository    UserRepository
+	sessionRepository SessionRepository
+	config            *Config
+	now               func() time.Time
+}
+
+// Ensure AuthService implements UserManager
+var _ UserManager = (*AuthService)(nil)
+
+// ListUsers returns all users ordered by username
+func (s *AuthService) ListUsers() ([]*User, error) {
+	return s.userRepository.List()
+}
+
+// GetUser returns a user by username
+func (s *AuthService) GetUser(username string) (*User, error) {
+	return s.userRepository.FindByUsername(username)
+}
+
+// CreateUser creates a new user with a bcrypt-hashed password
+func (s *AuthService) CreateUser(username, password string) (*User, error) {
+	if err := validateUsername(username); err != nil {
+		return nil, err
+	}
+
+	hash, err := hashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &User{
+		ID:           uuid.New(),
+		Username:     username,
+		PasswordHash: hash,
+	}
+	if err := s.userRepository.Create(user); err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	slog.Info("User created", "username", username)
+	return user, nil
+}
+
+// RemoveUser deletes a user together with all of their sessions
+func (s *AuthService) RemoveUser(username string) error {
+	user, err := s.userRepository.FindByUsername(username)
+	if err != nil {
+		return fmt.Errorf("user %q not found: %w", username, err)
+	}
+
+	if err := s.userRepository.Delete(user.ID); err != nil {
+		return fmt.Errorf("failed to remove user: %w", err)
+	}
+
+	slog.Info("User removed", "username", username)
+	return nil
+}
+
+// SetPassword changes a user's password and signs out all of their sessions
+func (s *AuthService) SetPassword(username, password string) error {
+	user, err := s.userRepository.FindByUsername(username)
+	if err != nil {
+		return fmt.Errorf("user %q not found: %w", username, err)
+	}
+
+	hash, err := hashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	user.PasswordHash = hash
+	if err := s.userRepository.Update(user); err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	if err := s.sessionRepository.DeleteByUserID(user.ID); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	slog.Info("User password changed", "username", username)
+	return nil
+}
+
+// Authenticate checks a username and password, returning ErrInvalidCredentials on mismatch
+func (s *AuthService) Authenticate(username, password string) (*User, error) {
+	user, err := s.userRepository.FindByUsername(username)
+	if err != nil {
+		if !IsNotFound(err) {
+			return nil, err
+		}
+		_ = bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(password))
+		return nil, ErrInvalidCredentials
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	return user, nil
+}
+
+// CreateSession starts a new session for a user and returns its token.
+// Only a hash of the token is stored, so the token cannot be recovered from the database.
+func (s *AuthService) CreateSession(userID uuid.UUID) (string, *Session, error) {
+	token, err := generateSessionToken()
+	if err != nil {
+		return "", nil, err
+	}
+
+	session := &Session{
+		ID:        uuid.New(),
+		UserID:    userID,
+		TokenHash: hashSessionToken(token),
+		ExpiresAt: s.now().Add(s.config.SessionTTL),
+	}
+	if err := s.sessionRepository.Create(session); err != nil {
+		return "", nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return token, session, nil
+}
+
+// ValidateSession returns the user owning a session token, or ErrInvalidSession
+func (s *AuthService) ValidateSession(token string) (*User, error) {
+	if token == "" {
+		return nil, ErrInvalidSession
+	}
+
+	session, err := s.sessionRepository.FindByTokenHash(hashSessionToken(token))
+	if err != nil {
+		if IsNotFound(err) {
+			return nil, ErrInvalidSession
+		}
+		return nil, err
+	}
+
+	if session.Expired(s.now()) {
+		if err := s.sessionRepository.Delete(session.ID); err != nil {
+			slog.Warn("Failed to delete expired session", "session_id", session.ID, "error", err)
+		}
+		return nil, ErrInvalidSession
+	}
+
+	user, err := s.userRepository.FindByID(session.UserID)
+	if err != nil {
+		if IsNotFound(err) {
+			return nil, ErrInvalidSession
+		}
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// DeleteSession ends the session identified by a token. Unknown tokens are ignored.
+func (s *AuthService) DeleteSession(token string) error {
+	session, err := s.sessionRepository.FindByTokenHash(hashSessionToken(token))
+	if err != nil {
+		if IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	return s.sessionRepository.Delete(session.ID)
+}
+
+// DeleteExpiredSessions removes all sessions that have expired
+func (s *AuthService) DeleteExpiredSessions() error {
+	deleted, err := s.sessionRepository.DeleteExpired(s.now())
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		slog.Debug("Deleted expired sessions", "count", deleted)
+	}
+	return nil
+}
+
+// validateUsername makes sure a username is non-empty and contains no whitespace
+func validateUsername(username string) error {
+	if username == "" {
+		return errors.New("username cannot be empty")
+	}
+	if strings.IndexFunc(username, unicode.IsSpace) >= 0 {
+		return errors.New("username cannot contain whitespace")
+	}
+	return nil
+}
+
+// hashPassword validates a password and hashes it with bcrypt
+func hashPassword(password string) (string, error) {
+	if len(password) < MinPasswordLength {
+		return "", fmt.Errorf("password must be at least %d characters long", MinPasswordLength)
+	}
+	if len(password) > maxPasswordLength {
+		return "", fmt.Errorf("password must be at most %d bytes long", maxPasswordLength)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return string(hash), nil
+}
+
+// generateSessionToken returns a random URL-safe session token
+func generateSessionToken() (string, error) {
+	b := make([]byte, sessionTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate session token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashSessionToken returns the hex-encoded SHA-256 of a session token
+func hashSessionToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// NewAuthService creates a new AuthService
+func NewAuthService(userRepo UserRepository, sessionRepo SessionRepository, config *Config) *AuthService {
+	return &AuthService{
+		userRepository:    userRepo,
+		sessionRepository: sessionRepo,
+		config:            config,
+		now:               time.Now,
+	}
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupAuthService(t *testing.T) *AuthService {
+	db := setupTestDB(t)
+	return NewAuthService(NewUserRepository(db), NewSessionRepository(db), &Config{SessionTTL: time.Hour})
+}
+
+func TestAuthService_CreateUser(t *testing.T) {
+	tests := []struct {
+		name        string
+		username    string
+		password    string
+		expectError string
+	}{
+		{name: "valid", username: "alice", password: "correct-horse"},
+		{name: "empty username", username: "", password: "correct-horse", expectError: "username cannot be empty"},
+		{name: "whitespace in username", username: "al ice", password: "correct-horse", expectError: "whitespace"},
+		{name: "short password", username: "alice", password: "short", expectError: "at least 8 characters"},
+		{
+			name:        "too long password",
+			username:    "alice",
+			password:    string(make([]byte, 73)),
+			expectError: "at most 72 bytes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := setupAuthService(t)
+
+			user, err := service.CreateUser(tt.username, tt.password)
+
+			if tt.expectError != "" {
+				assert.ErrorContains(t, err, tt.expectError)
+				assert.Nil(t, user)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.username, user.Username)
+			assert.NotEqual(t, tt.password, user.PasswordHash)
+		})
+	}
+}
+
+func TestAuthService_CreateUser_Duplicate(t *testing.T) {
+	service := setupAuthService(t)
+
+	_, err := service.CreateUser("alice", "correct-horse")
+	require.NoError(t, err)
+
+	_, err = service.CreateUser("alice", "another-password")
+	assert.ErrorContains(t, err, "UNIQUE constraint failed")
+}
+
+func TestAuthService_Authenticate(t *testing.T) {
+	service := setupAuthService(t)
+	created, err := service.CreateUser("alice", "correct-horse")
+	require.NoError(t, err)
+
+	user, err := service.Authenticate("alice", "correct-horse")
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, user.ID)
+
+	_, err = service.Authenticate("alice", "wrong-password")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+
+	_, err = service.Authenticate("bob", "correct-horse")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+}
+
+func TestAuthService_SessionLifecycle(t *testing.T) {
+	service := setupAuthService(t)
+	created, err := service.CreateUser("alice", "correct-horse")
+	require.NoError(t, err)
+
+	token, session, err := service.CreateSession(created.ID)
+	require.NoError(t, err)
+	assert.NotEmpty(t, token)
+	assert.NotEqual(t, token, session.TokenHash, "raw token must not be stored")
+
+	user, err := service.ValidateSession(token)
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, user.ID)
+
+	require.NoError(t, service.DeleteSession(token))
+
+	_, err = service.ValidateSession(token)
+	assert.ErrorIs(t, err, ErrInvalidSession)
+
+	// Deleting an unknown session is not an error
+	assert.NoError(t, service.DeleteSession(token))
+}
+
+func TestAuthService_ValidateSession_Expired(t *testing.T) {
+	service := setupAuthService(t)
+	created, err := service.CreateUser("alice", "correct-horse")
+	require.NoError(t, err)
+
+	token, _, err := service.CreateSession(created.ID)
+	require.NoError(t, err)
+
+	service.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+
+	_, err = service.ValidateSession(token)
+	assert.ErrorIs(t, err, ErrInvalidSession)
+
+	// The expired session is removed, so restoring the clock does not revive it
+	service.now = time.Now
+	_, err = service.ValidateSession(token)
+	assert.ErrorIs(t, err, ErrInvalidSession)
+}
+
+func TestAuthService_ValidateSession_Unknown(t *testing.T) {
+	service := setupAuthService(t)
+
+	_, err := service.ValidateSession("")
+	assert.ErrorIs(t, err, ErrInvalidSession)
+
+	_, err = service.ValidateSession("no-such-token")
+	assert.ErrorIs(t, err, ErrInvalidSession)
+}
+
+func TestAuthService_SetPassword_RevokesSessions(t *testing.T) {
+	service := setupAuthService(t)
+	created, err := service.CreateUser("alice", "correct-horse")
+	require.NoError(t, err)
+
+	token, _, err := service.CreateSession(created.ID)
+	require.NoError(t, err)
+
+	require.NoError(t, service.SetPassword("alice", "battery-staple"))
+
+	_, err = service.ValidateSession(token)
+	assert.ErrorIs(t, err, ErrInvalidSession)
+
+	_, err = service.Authenticate("alice", "correct-horse")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+
+	_, err = service.Authenticate("alice", "battery-staple")
+	assert.NoError(t, err)
+}
+
+func TestAuthService_RemoveUser(t *testing.T) {
+	service := setupAuthService(t)
+	created, err := service.CreateUser("alice", "correct-horse")
+	require.NoError(t, err)
+
+	token, _, err := service.CreateSession(created.ID)
+	require.NoError(t, err)
+
+	require.NoError(t, service.RemoveUser("alice"))
+
+	_, err = service.ValidateSession(token)
+	assert.ErrorIs(t, err, ErrInvalidSession)
+
+	err = service.RemoveUser("alice")
+	assert.ErrorContains(t, err, "not found")
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+)
+
+// Role is a level of access to projects. Higher roles include everything lower roles may do.
+type Role int
+
+const (
+	RoleNone Role = iota
+	RoleViewer
+	RoleDeployer
+	RoleAdmin
+)
+
+func (r Role) String() string {
+	switch r {
+	case RoleViewer:
+		return "viewer"
+	case RoleDeployer:
+		return "deployer"
+	case RoleAdmin:
+		return "admin"
+	default:
+		return "none"
+	}
+}
+
+func ParseRole(s string) (Role, error) {
+	switch s {
+	case "viewer":
+		return RoleViewer, nil
+	case "deployer":
+		return RoleDeployer, nil
+	case "admin":
+		return RoleAdmin, nil
+	default:
+		return RoleNone, fmt.Errorf("invalid role: %q (must be viewer, deployer, or admin)", s)
+	}
+}
+
+// ErrPermissionDenied is returned when a user's role does not allow an operation
+var ErrPermissionDenied = errors.New("permission denied")
+
+type userContextKey struct{}
+
+// ContextWithUser returns a copy of ctx carrying the user on whose behalf a request is made
+func ContextWithUser(ctx context.Context, user *User) context.Context {
+	return context.WithValue(ctx, userContextKey{}, user)
+}
+
+// UserFromContext returns the user stored in ctx, or nil if there is none
+func UserFromContext(ctx context.Context) *User {
+	user, _ := ctx.Value(userContextKey{}).(*User)
+	return user
+}
+
+// AuthorizationService manages role bindings and answers access questions.
+type AuthorizationService struct {
+	roleBindingRepository RoleBindingRepository
+}
+
+// Ensure AuthorizationService implements RoleManager
+var _ RoleManager = (*AuthorizationService)(nil)
+
+// Grant gives a user a role, globally if projectID is nil. An existing role in the same scope is replaced.
+func (s *AuthorizationService) Grant(userID uuid.UUID, projectID *uuid.UUID, role Role) error {
+	if role == RoleNone {
+		return errors.New("cannot grant an empty role, revoke it instead")
+	}
+
+	binding, err := s.roleBindingRepository.Find(userID, projectID)
+	if err != nil {
+		if !IsNotFound(err) {
+			return err
+		}
+		binding = &RoleBinding{
+			ID:        uuid.New(),
+			UserID:    userID,
+			ProjectID: projectID,
+		}
+	}
+
+	binding.Role = role
+	if err := s.roleBindingRepository.Save(binding); err != nil {
+		return fmt.Errorf("failed to grant role: %w", err)
+	}
+
+	slog.Info("Role granted", "user_id", userID, "project_id", projectID, "role", role)
+	return nil
+}
+
+// Revoke removes a user's role in a scope, globally if projectID is nil
+func (s *AuthorizationService) Revoke(userID uuid.UUID, projectID *uuid.UUID) error {
+	binding, err := s.roleBindingRepository.Find(userID, projectID)
+	if err != nil {
+		if IsNotFound(err) {
+			return errors.New("no role assigned in this scope")
+		}
+		return err
+	}
+
+	if err := s.roleBindingRepository.Delete(binding.ID); err != nil {
+		return fmt.Errorf("failed to revoke role: %w", err)
+	}
+
+	slog.Info("Role revoked", "user_id", userID, "project_id", projectID, "role", binding.Role)
+	return nil
+}
+
+// ListRoles returns all role bindings of a user
+func (s *AuthorizationService) ListRoles(userID uuid.UUID) ([]*RoleBinding, error) {
+	return s.roleBindingRepository.ListByUserID(userID)
+}
+
+// EffectiveRole returns the highest role a user holds for a project, taking global roles into account.
+// With a nil projectID only the global role is considered.
+func (s *AuthorizationService) EffectiveRole(userID uuid.UUID, projectID *uuid.UUID) (Role, error) {
+	bindings, err := s.roleBindingRepository.ListByUserID(userID)
+	if err != nil {
+		return RoleNone, err
+	}
+
+	role := RoleNone
+	for _, b := range bindings {
+		applies := b.IsGlobal() || (projectID != nil && *b.ProjectID == *projectID)
+		if applies && b.Role > role {
+			role = b.Role
+		}
+	}
+	return role, nil
+}
+
+// Authorize returns an error wrapping ErrPermissionDenied unless the user holds at least the required role
+func (s *AuthorizationService) Authorize(user *User, projectID *uuid.UUID, required Role) error {
+	role, err := s.EffectiveRole(user.ID, projectID)
+	if err != nil {
+		return fmt.Errorf("failed to check permissions: %w", err)
+	}
+
+	if role < required {
+		scope := "global"
+		if projectID != nil {
+			scope = "project"
+		}
+		return fmt.Errorf("%w: %s %s role required", ErrPermissionDenied, scope, required)
+	}
+	return nil
+}
+
+// NewAuthorizationService creates a new AuthorizationService
+func NewAuthorizationService(roleBindingRepo RoleBindingRepository) *AuthorizationService {
+	return &AuthorizationService{
+		roleBindingRepository: roleBindingRepo,
+	}
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupAuthorizationService returns an AuthorizationService backed by a test database
+// together with a user and a project to bind roles to
+func setupAuthorizationService(t *testing.T) (*AuthorizationService, *User, *Project) {
+	db := setupTestDB(t)
+
+	user, err := NewAuthService(NewUserRepository(db), NewSessionRepository(db), &Config{}).
+		CreateUser("alice", "correct-horse")
+	require.NoError(t, err)
+
+	project, err := NewProjectRepository(db, setupTestEncryption(t)).Create(createTestProject())
+	require.NoError(t, err)
+
+	return NewAuthorizationService(NewRoleBindingRepository(db)), user, project
+}
+
+func TestParseRole(t *testing.T) {
+	for _, role := range []Role{RoleViewer, RoleDeployer, RoleAdmin} {
+		parsed, err := ParseRole(role.String())
+		require.NoError(t, err)
+		assert.Equal(t, role, parsed)
+	}
+
+	_, err := ParseRole("none")
+	assert.ErrorContains(t, err, "invalid role")
+	_, err = ParseRole("Admin")
+	assert.ErrorContains(t, err, "invalid role")
+}
+
+func TestUserFromContext(t *testing.T) {
+	assert.Nil(t, UserFromContext(context.Background()))
+
+	user := &User{ID: uuid.New(), Username: "alice"}
+	assert.Equal(t, user, UserFromContext(ContextWithUser(context.Background(), user)))
+}
+
+func TestAuthorizationService_GrantReplacesRoleInScope(t *testing.T) {
+	service, user, project := setupAuthorizationService(t)
+
+	require.NoError(t, service.Grant(user.ID, &project.ID, RoleViewer))
+	require.NoError(t, service.Grant(user.ID, &project.ID, RoleDeployer))
+	require.NoError(t, service.Grant(user.ID, nil, RoleViewer))
+
+	bindings, err := service.ListRoles(user.ID)
+	require.NoError(t, err)
+	require.Len(t, bindings, 2)
+
+	roles := map[bool]Role{}
+	for _, b := range bindings {
+		roles[b.IsGlobal()] = b.Role
+	}
+	assert.Equal(t, RoleViewer, roles[true])
+	assert.Equal(t, RoleDeployer, roles[false])
+}
+
+func TestAuthorizationService_GrantNone(t *testing.T) {
+	service, user, _ := setupAuthorizationService(t)
+
+	assert.Error(t, service.Grant(user.ID, nil, RoleNone))
+}
+
+func TestAuthorizationService_Revoke(t *testing.T) {
+	service, user, project := setupAuthorizationService(t)
+
+	require.NoError(t, service.Grant(user.ID, &project.ID, RoleAdmin))
+	require.NoError(t, service.Revoke(user.ID, &project.ID))
+
+	bindings, err := service.ListRoles(user.ID)
+	require.NoError(t, err)
+	assert.Empty(t, bindings)
+
+	assert.ErrorContains(t, service.Revoke(user.ID, &project.ID), "no role assigned")
+}
+
+func TestAuthorizationService_Authorize(t *testing.T) {
+	otherProjectID := uuid.New()
+
+	tests := []struct {
+		name        string
+		global      Role
+		project     Role
+		scope       string
+		required    Role
+		expectAllow bool
+	}{
+		{name: "no roles", scope: "project", required: RoleViewer},
+		{name: "project viewer reads", project: RoleViewer, scope: "project", required: RoleViewer, expectAllow: true},
+		{name: "project viewer cannot deploy", project: RoleViewer, scope: "project", required: RoleDeployer},
+		{name: "project role does not apply elsewhere", project: RoleAdmin, scope: "other", required: RoleViewer},
+		{name: "project admin is not global admin", project: RoleAdmin, scope: "global", required: RoleAdmin},
+		{
+			name:        "global deployer deploys anywhere",
+			global:      RoleDeployer,
+			scope:       "other",
+			required:    RoleDeployer,
+			expectAllow: true,
+		},
+		{
+			name:        "higher project role wins",
+			global:      RoleViewer,
+			project:     RoleAdmin,
+			scope:       "project",
+			required:    RoleAdmin,
+			expectAllow: true,
+		},
+		{
+			name:        "higher global role wins",
+			global:      RoleAdmin,
+			project:     RoleViewer,
+			scope:       "project",
+			required:    RoleAdmin,
+			expectAllow: true,
+		},
+		{name: "global admin", global: RoleAdmin, scope: "global", required: RoleAdmin, expectAllow: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, user, project := setupAuthorizationService(t)
+			if tt.global != RoleNone {
+				require.NoError(t, service.Grant(user.ID, nil, tt.global))
+			}
+			if tt.project != RoleNone {
+				require.NoError(t, service.Grant(user.ID, &project.ID, tt.project))
+			}
+
+			var projectID *uuid.UUID
+			switch tt.scope {
+			case "project":
+				projectID = &project.ID
+			case "other":
+				projectID = &otherProjectID
+			}
+
+			err := service.Authorize(user, projectID, tt.required)
+
+			if tt.expectAllow {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrPermissionDenied)
+			}
+		})
+	}
+}
+
+func TestAuthorizationService_RolesRemovedWithProject(t *testing.T) {
+	db := setupTestDB(t)
+	user, err := NewAuthService(NewUserRepository(db), NewSessionRepository(db), &Config{}).
+		CreateUser("alice", "correct-horse")
+	require.NoError(t, err)
+	projectRepo := NewProjectRepository(db, setupTestEncryption(t))
+	project, err := projectRepo.Create(createTestProject())
+	require.NoError(t, err)
+
+	service := NewAuthorizationService(NewRoleBindingRepository(db))
+	require.NoError(t, service.Grant(user.ID, &project.ID, RoleDeployer))
+	require.NoError(t, projectRepo.Delete(project.ID))
+
+	bindings, err := service.ListRoles(user.ID)
+	require.NoError(t, err)
+	assert.Empty(t, bindings)
+}
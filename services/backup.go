@@ -0,0 +1,241 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+	"gorm.io/gorm"
+)
+
+// Backups are named after the time they were taken, e.g. oar-20250102T150405.000Z.db
+const (
+	backupPrefix     = "oar-"
+	backupExt        = ".db"
+	backupTimeFormat = "20060102T150405.000Z"
+)
+
+// ErrBackupNotFound is returned when restoring a backup that doesn't exist
+var ErrBackupNotFound = errors.New("backup not found")
+
+// Backup is a snapshot of the database in the backup directory
+type Backup struct {
+	Name      string
+	Path      string
+	Size      int64
+	CreatedAt time.Time
+}
+
+// BackupService takes, lists and restores backups of the database
+type BackupService struct {
+	db        *gorm.DB
+	dir       string
+	retention int
+}
+
+// Create backs up the database and removes the backups beyond the retention
+func (s *BackupService) Create(ctx context.Context) (*Backup, error) {
+	backup, err := s.create(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// The backup was taken, failing to clean up old ones only costs disk space
+	if err := s.prune(); err != nil {
+		slog.Warn("Failed to remove old database backups", "error", err)
+	}
+	return backup, nil
+}
+
+// List returns the backups, newest first
+func (s *BackupService) List() ([]*Backup, error) {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []*Backup{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup directory: %w", err)
+	}
+
+	backups := []*Backup{}
+	for _, entry := range entries {
+		name := entry.Name()
+		timestamp, ok := strings.CutPrefix(name, backupPrefix)
+		if !ok || entry.IsDir() {
+			continue
+		}
+		timestamp, ok = strings.CutSuffix(timestamp, backupExt)
+		if !ok {
+			continue
+		}
+		createdAt, err := time.Parse(backupTimeFormat, timestamp)
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read backup %s: %w", name, err)
+		}
+
+		backups = append(backups, &Backup{
+			Name:      name,
+			Path:      filepath.Join(s.dir, name),
+			Size:      info.Size(),
+			CreatedAt: createdAt,
+		})
+	}
+
+	slices.SortFunc(backups, func(a, b *Backup) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return backups, nil
+}
+
+// Restore replaces the contents of the database with those of the named backup. The database is copied with the
+// SQLite online backup API, so it stays usable by other processes, which see the restored data. The current
+// contents are backed up first, that backup is returned.
+func (s *BackupService) Restore(ctx context.Context, name string) (*Backup, error) {
+	backups, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	index := slices.IndexFunc(backups, func(backup *Backup) bool { return backup.Name == name })
+	if index < 0 {
+		return nil, fmt.Errorf("%w: %s", ErrBackupNotFound, name)
+	}
+	backup := backups[index]
+
+	source, err := sql.Open("sqlite3", "file:"+backup.Path+"?mode=ro")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open backup %s: %w", name, err)
+	}
+	defer func() { _ = source.Close() }()
+
+	var integrity string
+	if err := source.QueryRowContext(ctx, "PRAGMA integrity_check").Scan(&integrity); err != nil {
+		return nil, fmt.Errorf("failed to check backup %s: %w", name, err)
+	}
+	if integrity != "ok" {
+		return nil, fmt.Errorf("backup %s is corrupt: %s", name, integrity)
+	}
+
+	// Not pruned, so that restoring the oldest backup doesn't remove it
+	current, err := s.create(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to back up the current database: %w", err)
+	}
+
+	sourceConn, err := source.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open backup %s: %w", name, err)
+	}
+	defer func() { _ = sourceConn.Close() }()
+
+	database, err := s.db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+	destinationConn, err := database.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+	defer func() { _ = destinationConn.Close() }()
+
+	err = destinationConn.Raw(func(destination any) error {
+		return sourceConn.Raw(func(source any) error {
+			return copyDatabase(destination, source)
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore backup %s: %w", name, err)
+	}
+
+	slog.Info("Database restored", "backup", name, "previous_database", current.Name)
+	return current, nil
+}
+
+// create takes a backup of the database
+func (s *BackupService) create(ctx context.Context) (*Backup, error) {
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	createdAt := time.Now().UTC().Truncate(time.Millisecond)
+	name := backupPrefix + createdAt.Format(backupTimeFormat) + backupExt
+	path := filepath.Join(s.dir, name)
+
+	// VACUUM INTO writes a consistent copy of the database while it is in use
+	if err := s.db.WithContext(ctx).Exec("VACUUM INTO ?", path).Error; err != nil {
+		return nil, fmt.Errorf("failed to back up database: %w", err)
+	}
+	// The database holds encrypted credentials and sessions
+	if err := os.Chmod(path, 0o600); err != nil {
+		return nil, fmt.Errorf("failed to restrict backup permissions: %w", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup %s: %w", name, err)
+	}
+
+	slog.Info("Database backed up", "backup", name, "size", info.Size())
+	return &Backup{Name: name, Path: path, Size: info.Size(), CreatedAt: createdAt}, nil
+}
+
+// prune removes the backups beyond the retention, oldest first
+func (s *BackupService) prune() error {
+	if s.retention <= 0 {
+		return nil
+	}
+
+	backups, err := s.List()
+	if err != nil {
+		return err
+	}
+	for _, backup := range backups[min(s.retention, len(backups)):] {
+		if err := os.Remove(backup.Path); err != nil {
+			return fmt.Errorf("failed to remove backup %s: %w", backup.Name, err)
+		}
+		slog.Info("Old database backup removed", "backup", backup.Name)
+	}
+	return nil
+}
+
+// copyDatabase copies the main database of source over that of destination, both raw SQLite driver connections
+func copyDatabase(destination, source any) error {
+	destinationConn, ok := destination.(*sqlite3.SQLiteConn)
+	if !ok {
+		return fmt.Errorf("unexpected database driver connection %T", destination)
+	}
+	sourceConn, ok := source.(*sqlite3.SQLiteConn)
+	if !ok {
+		return fmt.Errorf("unexpected database driver connection %T", source)
+	}
+
+	backup, err := destinationConn.Backup("main", sourceConn, "main")
+	if err != nil {
+		return err
+	}
+	if _, err := backup.Step(-1); err != nil {
+		_ = backup.Finish()
+		return err
+	}
+	return backup.Finish()
+}
+
+// NewBackupService creates a new BackupService
+func NewBackupService(db *gorm.DB, config *Config) *BackupService {
+	return &BackupService{
+		db:        db,
+		dir:       config.BackupDir,
+		retention: config.BackupRetention,
+	}
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/oar-cd/oar/internal/dbutil"
+	"github.com/oar-cd/oar/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// setupBackupService creates a backup service for a file database, which backups need, with the given retention
+func setupBackupService(t *testing.T, retention int) (*BackupService, *gorm.DB) {
+	t.Helper()
+	dir := t.TempDir()
+	database, err := dbutil.InitDatabase(dbutil.DBConfig{
+		Path:     filepath.Join(dir, "oar.db"),
+		LogLevel: logger.Silent,
+	})
+	require.NoError(t, err)
+	require.NoError(t, models.AutoMigrateAll(database))
+	t.Cleanup(func() {
+		if sqlDB, err := database.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	})
+
+	config := &Config{BackupDir: filepath.Join(dir, BackupsDir), BackupRetention: retention}
+	return NewBackupService(database, config), database
+}
+
+// usernames returns the names of the users in database
+func usernames(t *testing.T, database *gorm.DB) []string {
+	t.Helper()
+	users, err := NewUserRepository(database).List()
+	require.NoError(t, err)
+	var names []string
+	for _, user := range users {
+		names = append(names, user.Username)
+	}
+	return names
+}
+
+func TestBackupService_CreateRestore(t *testing.T) {
+	service, database := setupBackupService(t, 0)
+	users := NewUserRepository(database)
+	require.NoError(t, users.Create(&User{ID: uuid.New(), Username: "alice", PasswordHash: "hash"}))
+
+	backups, err := service.List()
+	require.NoError(t, err)
+	assert.Empty(t, backups)
+
+	backup, err := service.Create(context.Background())
+	require.NoError(t, err)
+	assert.Positive(t, backup.Size)
+	info, err := os.Stat(backup.Path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	require.NoError(t, users.Create(&User{ID: uuid.New(), Username: "bob", PasswordHash: "hash"}))
+	assert.ElementsMatch(t, []string{"alice", "bob"}, usernames(t, database))
+
+	current, err := service.Restore(context.Background(), backup.Name)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice"}, usernames(t, database))
+
+	// The database as it was before the restore is kept as the newest backup
+	backups, err = service.List()
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+	assert.Equal(t, current.Name, backups[0].Name)
+	assert.Equal(t, backup.Name, backups[1].Name)
+
+	_, err = service.Restore(context.Background(), current.Name)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"alice", "bob"}, usernames(t, database))
+}
+
+func TestBackupService_Retention(t *testing.T) {
+	service, _ := setupBackupService(t, 2)
+
+	var names []string
+	for range 4 {
+		backup, err := service.Create(context.Background())
+		require.NoError(t, err)
+		names = append(names, backup.Name)
+	}
+
+	backups, err := service.List()
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+	assert.Equal(t, names[3], backups[0].Name)
+	assert.Equal(t, names[2], backups[1].Name)
+}
+
+func TestBackupService_List_IgnoresOtherFiles(t *testing.T) {
+	service, _ := setupBackupService(t, 0)
+	backup, err := service.Create(context.Background())
+	require.NoError(t, err)
+
+	for _, name := range []string{"notes.txt", "oar-latest.db", "oar-20250102T150405.000Z.db-journal"} {
+		require.NoError(t, os.WriteFile(filepath.Join(service.dir, name), []byte("x"), 0o600))
+	}
+
+	backups, err := service.List()
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+	assert.Equal(t, backup.Name, backups[0].Name)
+}
+
+func TestBackupService_Restore_Invalid(t *testing.T) {
+	service, database := setupBackupService(t, 0)
+	users := NewUserRepository(database)
+	require.NoError(t, users.Create(&User{ID: uuid.New(), Username: "alice", PasswordHash: "hash"}))
+
+	_, err := service.Restore(context.Background(), "../oar.db")
+	assert.ErrorIs(t, err, ErrBackupNotFound)
+
+	name := "oar-20250102T150405.000Z.db"
+	require.NoError(t, os.MkdirAll(service.dir, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(service.dir, name), []byte("not a database"), 0o600))
+
+	_, err = service.Restore(context.Background(), name)
+	assert.ErrorContains(t, err, "failed to check backup")
+
+	// Nothing changed, and no backup of the current database was taken
+	assert.Equal(t, []string{"alice"}, usernames(t, database))
+	backups, err := service.List()
+	require.NoError(t, err)
+	assert.Len(t, backups, 1)
+}
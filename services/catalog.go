@@ -0,0 +1,466 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gosimple/slug"
+	"gopkg.in/yaml.v3"
+)
+
+// CatalogFile is the name of the file listing the projects of a catalog in the root of its repository
+const CatalogFile = ".oar-catalog.yaml"
+
+// ErrInvalidCatalog is returned for catalogs with invalid settings, and for catalog files that can't be used,
+// failing the sync of the catalog
+var ErrInvalidCatalog = errors.New("invalid catalog")
+
+// CatalogProject is a project listed in a catalog file. Settings that are not listed, such as hooks or health
+// checks, belong in the project manifest of the project's own repository.
+type CatalogProject struct {
+	Name           string            `yaml:"name"`
+	GitURL         string            `yaml:"git_url"`
+	Branch         string            `yaml:"branch"`           // Default branch of the repository if empty
+	ComposeFiles   []string          `yaml:"compose_files"`    // Relative to the root of the project's repository
+	Variables      map[string]string `yaml:"variables"`        // Variable name to value
+	Secrets        map[string]string `yaml:"secrets"`          // Variable name to the name of a secret of the catalog
+	CatalogGitAuth bool              `yaml:"catalog_git_auth"` // Clone with the Git authentication of the catalog
+	Automatic      *bool             `yaml:"automatic"`        // Deploy new commits automatically, true if not set
+}
+
+// catalogFile is the contents of a CatalogFile
+type catalogFile struct {
+	Projects []CatalogProject `yaml:"projects"`
+}
+
+// ReadCatalogFile reads and validates the list of projects of the catalog checked out in dir
+func ReadCatalogFile(dir string) ([]CatalogProject, error) {
+	data, err := os.ReadFile(filepath.Join(dir, CatalogFile))
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read %s: %w", ErrInvalidCatalog, CatalogFile, err)
+	}
+
+	var file catalogFile
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidCatalog, CatalogFile, err)
+	}
+
+	names := make(map[string]bool, len(file.Projects))
+	for i, project := range file.Projects {
+		if err := project.validate(); err != nil {
+			return nil, fmt.Errorf("%w: %s: project %d: %w", ErrInvalidCatalog, CatalogFile, i+1, err)
+		}
+		if names[project.Name] {
+			return nil, fmt.Errorf("%w: %s: project %q is listed twice", ErrInvalidCatalog, CatalogFile, project.Name)
+		}
+		names[project.Name] = true
+	}
+	return file.Projects, nil
+}
+
+// validate checks that the listed project has what creating it needs
+func (p *CatalogProject) validate() error {
+	if strings.TrimSpace(p.Name) == "" {
+		return errors.New("name is required")
+	}
+	if strings.TrimSpace(p.GitURL) == "" {
+		return fmt.Errorf("%s: git_url is required", p.Name)
+	}
+	if len(p.ComposeFiles) == 0 {
+		return fmt.Errorf("%s: compose_files are required", p.Name)
+	}
+	for _, file := range p.ComposeFiles {
+		if file == "" || !filepath.IsLocal(file) {
+			return fmt.Errorf("%s: compose file %q is not a path within the repository", p.Name, file)
+		}
+	}
+	for name := range p.Variables {
+		if name == "" || strings.Contains(name, "=") {
+			return fmt.Errorf("%s: invalid variable name %q", p.Name, name)
+		}
+	}
+	for name, secret := range p.Secrets {
+		if name == "" || strings.Contains(name, "=") {
+			return fmt.Errorf("%s: invalid variable name %q", p.Name, name)
+		}
+		if secret == "" {
+			return fmt.Errorf("%s: secret of variable %s is not named", p.Name, name)
+		}
+		if _, ok := p.Variables[name]; ok {
+			return fmt.Errorf("%s: variable %s is both a variable and a secret", p.Name, name)
+		}
+	}
+	return nil
+}
+
+// automatic reports whether new commits of the project are deployed automatically
+func (p *CatalogProject) automatic() bool {
+	return p.Automatic == nil || *p.Automatic
+}
+
+// variables returns the variables of the project in KEY=value format, sorted by name, with its secrets looked up
+// in catalog
+func (p *CatalogProject) variables(catalog *Catalog) ([]string, error) {
+	variables := make([]string, 0, len(p.Variables)+len(p.Secrets))
+	for name, value := range p.Variables {
+		variables = append(variables, name+"="+value)
+	}
+	for name, secret := range p.Secrets {
+		value, ok := catalog.Secret(secret)
+		if !ok {
+			return nil, fmt.Errorf("secret %q is not set in catalog %s", secret, catalog.Name)
+		}
+		variables = append(variables, name+"="+value)
+	}
+	sort.Strings(variables)
+	return variables, nil
+}
+
+// CatalogSyncResult is what syncing a catalog changed
+type CatalogSyncResult struct {
+	Commit  string   // Commit of the catalog that was synced
+	Created []string // Names of the projects created
+	Updated []string // Names of the projects whose settings changed
+	Removed []string // Names of the projects no longer listed, removed
+	Errors  []string // Projects that could not be synced and why
+}
+
+// CatalogService manages catalogs and syncs the projects they list
+type CatalogService struct {
+	catalogRepository CatalogRepository
+	projects          ProjectManager
+	gitService        GitExecutor
+	config            *Config
+}
+
+// Ensure CatalogService implements CatalogManager
+var _ CatalogManager = (*CatalogService)(nil)
+
+// List returns all catalogs
+func (s *CatalogService) List() ([]*Catalog, error) {
+	return s.catalogRepository.List()
+}
+
+// Get retrieves a catalog by ID
+func (s *CatalogService) Get(id uuid.UUID) (*Catalog, error) {
+	return s.catalogRepository.FindByID(id)
+}
+
+// Create clones the repository of a new catalog. Its projects are created by syncing it.
+func (s *CatalogService) Create(catalog *Catalog) (*Catalog, error) {
+	if err := validateCatalog(catalog); err != nil {
+		return nil, err
+	}
+	if catalog.ID == uuid.Nil {
+		catalog.ID = uuid.New()
+	}
+
+	dirName := fmt.Sprintf("%s-%s", catalog.ID.String(), slug.Make(catalog.Name))
+	catalog.WorkingDir = filepath.Join(s.config.DataDir, CatalogsDir, dirName)
+	gitDir, err := catalog.GitDir()
+	if err != nil {
+		return nil, err
+	}
+
+	// Detect default branch if none specified
+	if catalog.GitBranch == "" {
+		defaultBranch, err := s.gitService.GetDefaultBranch(catalog.GitURL, catalog.GitAuth)
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine default branch: %w", err)
+		}
+		catalog.GitBranch = defaultBranch
+	}
+
+	if err := s.gitService.Clone(catalog.GitURL, catalog.GitBranch, catalog.GitAuth, gitDir); err != nil {
+		slog.Error("Service operation failed",
+			"layer", "service",
+			"operation", "create_catalog",
+			"catalog_id", catalog.ID,
+			"catalog_name", catalog.Name,
+			"git_url", catalog.GitURL,
+			"error", err)
+		return nil, err
+	}
+
+	if err := s.catalogRepository.Create(catalog); err != nil {
+		if cleanupErr := os.RemoveAll(catalog.WorkingDir); cleanupErr != nil {
+			slog.Error("Failed to remove catalog directory after creation failure",
+				"working_dir", catalog.WorkingDir,
+				"error", cleanupErr)
+		}
+		return nil, err
+	}
+	return catalog, nil
+}
+
+// Update saves the settings of a catalog, such as its secrets. They apply to its projects on the next sync.
+func (s *CatalogService) Update(catalog *Catalog) error {
+	if err := validateCatalog(catalog); err != nil {
+		return err
+	}
+	return s.catalogRepository.Update(catalog)
+}
+
+// Remove removes a catalog. The projects it manages are removed along with it if removeProjects is set, or else
+// kept as projects managed by hand.
+func (s *CatalogService) Remove(id uuid.UUID, removeProjects bool) error {
+	catalog, err := s.catalogRepository.FindByID(id)
+	if err != nil {
+		return err
+	}
+
+	projects, err := s.projects.List()
+	if err != nil {
+		return err
+	}
+	for _, project := range projects {
+		if !managedBy(project, catalog) {
+			continue
+		}
+		if removeProjects {
+			err = s.projects.Remove(project.ID)
+		} else {
+			project.CatalogID = nil
+			err = s.projects.Update(project)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to release project %s: %w", project.Name, err)
+		}
+	}
+
+	// Rename rather than remove the directory, as projects do
+	if err := os.Rename(catalog.WorkingDir, GetDeletedDirectoryPath(catalog.WorkingDir)); err != nil {
+		slog.Warn("Failed to rename catalog directory, continuing with deletion",
+			"catalog_id", catalog.ID,
+			"working_dir", catalog.WorkingDir,
+			"error", err)
+	}
+	return s.catalogRepository.Delete(id)
+}
+
+// Sync pulls the repository of a catalog and creates, updates and removes the projects it manages to match its
+// catalog file. Created projects are deployed, and updated ones are redeployed if they are running. A project
+// that can't be synced doesn't stop the others and is reported in the result. A catalog that can't be pulled or
+// read returns an error, leaving its projects as they are.
+func (s *CatalogService) Sync(ctx context.Context, id uuid.UUID) (*CatalogSyncResult, error) {
+	catalog, err := s.catalogRepository.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.sync(ctx, catalog)
+
+	now := time.Now()
+	catalog.LastSyncedAt = &now
+	catalog.LastSyncError = ""
+	if err != nil {
+		catalog.LastSyncError = err.Error()
+	} else if len(result.Errors) > 0 {
+		catalog.LastSyncError = strings.Join(result.Errors, "; ")
+	}
+	if result != nil && result.Commit != "" {
+		catalog.LastCommit = &result.Commit
+	}
+	if updateErr := s.catalogRepository.Update(catalog); updateErr != nil {
+		slog.Error("Failed to record catalog sync",
+			"catalog_id", catalog.ID,
+			"catalog_name", catalog.Name,
+			"error", updateErr)
+	}
+
+	if err != nil {
+		slog.Error("Catalog sync failed",
+			"catalog_id", catalog.ID,
+			"catalog_name", catalog.Name,
+			"error", err)
+		return nil, err
+	}
+	slog.Info("Catalog synced",
+		"catalog_id", catalog.ID,
+		"catalog_name", catalog.Name,
+		"commit", result.Commit,
+		"created", result.Created,
+		"updated", result.Updated,
+		"removed", result.Removed,
+		"errors", result.Errors)
+	return result, nil
+}
+
+// sync pulls the repository of catalog and reconciles its projects
+func (s *CatalogService) sync(ctx context.Context, catalog *Catalog) (*CatalogSyncResult, error) {
+	gitDir, err := catalog.GitDir()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.gitService.Pull(catalog.GitBranch, catalog.GitAuth, gitDir); err != nil {
+		return nil, fmt.Errorf("failed to pull catalog: %w", err)
+	}
+	commit, err := s.gitService.GetLatestCommit(gitDir)
+	if err != nil {
+		return nil, err
+	}
+
+	listed, err := ReadCatalogFile(gitDir)
+	if err != nil {
+		return &CatalogSyncResult{Commit: commit}, err
+	}
+
+	projects, err := s.projects.List()
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]*Project, len(projects))
+	for _, project := range projects {
+		existing[project.Name] = project
+	}
+
+	result := &CatalogSyncResult{Commit: commit}
+	names := make(map[string]bool, len(listed))
+	for _, spec := range listed {
+		names[spec.Name] = true
+		if err := s.syncProject(ctx, catalog, &spec, existing[spec.Name], result); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", spec.Name, err))
+		}
+	}
+
+	for _, project := range projects {
+		if !managedBy(project, catalog) || names[project.Name] {
+			continue
+		}
+		if err := s.projects.Remove(project.ID); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: failed to remove: %v", project.Name, err))
+			continue
+		}
+		result.Removed = append(result.Removed, project.Name)
+	}
+	return result, nil
+}
+
+// syncProject creates the listed project, or updates it if it exists, and deploys it
+func (s *CatalogService) syncProject(
+	ctx context.Context,
+	catalog *Catalog,
+	spec *CatalogProject,
+	existing *Project,
+	result *CatalogSyncResult,
+) error {
+	variables, err := spec.variables(catalog)
+	if err != nil {
+		return err
+	}
+	var gitAuth *GitAuthConfig
+	if spec.CatalogGitAuth {
+		gitAuth = catalog.GitAuth
+	}
+
+	if existing == nil {
+		project := NewProject(spec.Name, spec.GitURL, spec.ComposeFiles, variables)
+		project.GitBranch = spec.Branch
+		project.GitAuth = gitAuth
+		project.WatcherEnabled = spec.automatic()
+		project.CatalogID = &catalog.ID
+
+		created, err := s.projects.Create(&project)
+		if err != nil {
+			return err
+		}
+		result.Created = append(result.Created, created.Name)
+		return s.deploy(ctx, catalog, created)
+	}
+
+	if !managedBy(existing, catalog) {
+		return errors.New("a project with this name exists and is not managed by the catalog")
+	}
+	if existing.GitURL != spec.GitURL {
+		return fmt.Errorf("git URL can't change from %s, list the project under a new name instead", existing.GitURL)
+	}
+
+	project := *existing
+	if spec.Branch != "" {
+		project.GitBranch = spec.Branch
+	}
+	project.ComposeFiles = spec.ComposeFiles
+	project.Variables = variables
+	project.GitAuth = gitAuth
+	project.WatcherEnabled = spec.automatic()
+	project.RequireApproval = project.RequireApproval && project.WatcherEnabled // Nothing left to approve
+	if !catalogProjectChanged(existing, &project) {
+		return nil
+	}
+
+	if err := s.projects.Update(&project); err != nil {
+		return err
+	}
+	result.Updated = append(result.Updated, project.Name)
+	if project.Status != ProjectStatusRunning {
+		return nil
+	}
+	return s.deploy(ctx, catalog, &project)
+}
+
+// deploy deploys a project the catalog created or changed
+func (s *CatalogService) deploy(ctx context.Context, catalog *Catalog, project *Project) error {
+	options := DeployOptions{Pull: true, Trigger: DeploymentTriggerCatalog, Actor: catalog.Name}
+	if err := s.projects.DeployPiping(ctx, project.ID, options); err != nil {
+		return fmt.Errorf("deployment failed: %w", err)
+	}
+	return nil
+}
+
+// managedBy reports whether project is managed by catalog
+func managedBy(project *Project, catalog *Catalog) bool {
+	return project.CatalogID != nil && *project.CatalogID == catalog.ID
+}
+
+// catalogProjectChanged reports whether a catalog changed any of the settings of a project it manages
+func catalogProjectChanged(old, new *Project) bool {
+	return old.GitBranch != new.GitBranch ||
+		!slices.Equal(old.ComposeFiles, new.ComposeFiles) ||
+		!slices.Equal(old.Variables, new.Variables) ||
+		!reflect.DeepEqual(old.GitAuth, new.GitAuth) ||
+		old.WatcherEnabled != new.WatcherEnabled
+}
+
+// validateCatalog checks the settings of a catalog
+func validateCatalog(catalog *Catalog) error {
+	if strings.TrimSpace(catalog.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidCatalog)
+	}
+	if strings.TrimSpace(catalog.GitURL) == "" {
+		return fmt.Errorf("%w: git URL is required", ErrInvalidCatalog)
+	}
+	for _, secret := range catalog.Secrets {
+		if name, _, ok := strings.Cut(secret, "="); !ok || name == "" {
+			return fmt.Errorf("%w: secrets must be in KEY=value format, got: %q", ErrInvalidCatalog, secret)
+		}
+	}
+	return nil
+}
+
+// NewCatalogService creates a catalog service that manages projects through projects
+func NewCatalogService(
+	catalogRepository CatalogRepository,
+	projects ProjectManager,
+	gitService GitExecutor,
+	config *Config,
+) *CatalogService {
+	return &CatalogService{
+		catalogRepository: catalogRepository,
+		projects:          projects,
+		gitService:        gitService,
+		config:            config,
+	}
+}
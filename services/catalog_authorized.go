@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// AuthorizedCatalogService wraps a CatalogManager and checks the acting user's role before every operation.
+// Catalogs create and remove projects, so managing them requires a global admin role, and viewing them a global
+// viewer role.
+type AuthorizedCatalogService struct {
+	inner CatalogManager
+	roles RoleManager
+	user  *User
+}
+
+// Ensure AuthorizedCatalogService implements CatalogManager
+var _ CatalogManager = (*AuthorizedCatalogService)(nil)
+
+func (s *AuthorizedCatalogService) List() ([]*Catalog, error) {
+	if err := s.roles.Authorize(s.user, nil, RoleViewer); err != nil {
+		return nil, err
+	}
+	return s.inner.List()
+}
+
+func (s *AuthorizedCatalogService) Get(id uuid.UUID) (*Catalog, error) {
+	if err := s.roles.Authorize(s.user, nil, RoleViewer); err != nil {
+		return nil, err
+	}
+	return s.inner.Get(id)
+}
+
+func (s *AuthorizedCatalogService) Create(catalog *Catalog) (*Catalog, error) {
+	if err := s.roles.Authorize(s.user, nil, RoleAdmin); err != nil {
+		return nil, err
+	}
+	return s.inner.Create(catalog)
+}
+
+func (s *AuthorizedCatalogService) Update(catalog *Catalog) error {
+	if err := s.roles.Authorize(s.user, nil, RoleAdmin); err != nil {
+		return err
+	}
+	return s.inner.Update(catalog)
+}
+
+func (s *AuthorizedCatalogService) Remove(id uuid.UUID, removeProjects bool) error {
+	if err := s.roles.Authorize(s.user, nil, RoleAdmin); err != nil {
+		return err
+	}
+	return s.inner.Remove(id, removeProjects)
+}
+
+func (s *AuthorizedCatalogService) Sync(ctx context.Context, id uuid.UUID) (*CatalogSyncResult, error) {
+	if err := s.roles.Authorize(s.user, nil, RoleAdmin); err != nil {
+		return nil, err
+	}
+	return s.inner.Sync(ctx, id)
+}
+
+// NewAuthorizedCatalogService wraps inner so that every operation is performed on behalf of user
+func NewAuthorizedCatalogService(inner CatalogManager, roles RoleManager, user *User) *AuthorizedCatalogService {
+	return &AuthorizedCatalogService{
+		inner: inner,
+		roles: roles,
+		user:  user,
+	}
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthorizedCatalogService_RequiresGlobalRole(t *testing.T) {
+	calls := map[string]struct {
+		required Role
+		call     func(s CatalogManager) error
+	}{
+		"list": {RoleViewer, func(s CatalogManager) error {
+			_, err := s.List()
+			return err
+		}},
+		"get": {RoleViewer, func(s CatalogManager) error {
+			_, err := s.Get(uuid.New())
+			return err
+		}},
+		"create": {RoleAdmin, func(s CatalogManager) error {
+			_, err := s.Create(&Catalog{Name: "platform"})
+			return err
+		}},
+		"update": {RoleAdmin, func(s CatalogManager) error {
+			return s.Update(&Catalog{Name: "platform"})
+		}},
+		"remove": {RoleAdmin, func(s CatalogManager) error {
+			return s.Remove(uuid.New(), true)
+		}},
+		"sync": {RoleAdmin, func(s CatalogManager) error {
+			_, err := s.Sync(context.Background(), uuid.New())
+			return err
+		}},
+	}
+
+	for name, tt := range calls {
+		for _, granted := range []Role{RoleNone, RoleViewer, RoleDeployer, RoleAdmin} {
+			t.Run(name+" as "+granted.String(), func(t *testing.T) {
+				roles, user, _ := setupAuthorizationService(t)
+				if granted != RoleNone {
+					require.NoError(t, roles.Grant(user.ID, nil, granted))
+				}
+				service := NewAuthorizedCatalogService(&MockCatalogManager{}, roles, user)
+
+				err := tt.call(service)
+
+				if granted >= tt.required {
+					assert.NoError(t, err)
+				} else {
+					assert.ErrorIs(t, err, ErrPermissionDenied)
+				}
+			})
+		}
+	}
+}
+
+func TestAuthorizedCatalogService_ProjectRoleIsNotEnough(t *testing.T) {
+	roles, user, project := setupAuthorizationService(t)
+	require.NoError(t, roles.Grant(user.ID, &project.ID, RoleAdmin))
+	service := NewAuthorizedCatalogService(&MockCatalogManager{}, roles, user)
+
+	_, err := service.List()
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+}
@@ -0,0 +1,387 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCatalogProjects keeps the projects of a catalog in memory, standing in for the project service. Operations
+// catalogs don't use panic.
+type fakeCatalogProjects struct {
+	ProjectManager
+	projects   map[uuid.UUID]*Project
+	deployed   []string
+	deployErrs map[string]error
+}
+
+func newFakeCatalogProjects(projects ...*Project) *fakeCatalogProjects {
+	fake := &fakeCatalogProjects{projects: make(map[uuid.UUID]*Project), deployErrs: make(map[string]error)}
+	for _, project := range projects {
+		fake.projects[project.ID] = project
+	}
+	return fake
+}
+
+func (f *fakeCatalogProjects) List() ([]*Project, error) {
+	projects := make([]*Project, 0, len(f.projects))
+	for _, project := range f.projects {
+		copied := *project
+		projects = append(projects, &copied)
+	}
+	return projects, nil
+}
+
+func (f *fakeCatalogProjects) Create(project *Project) (*Project, error) {
+	created := *project
+	f.projects[project.ID] = &created
+	return &created, nil
+}
+
+func (f *fakeCatalogProjects) Update(project *Project) error {
+	updated := *project
+	f.projects[project.ID] = &updated
+	return nil
+}
+
+func (f *fakeCatalogProjects) Remove(projectID uuid.UUID) error {
+	delete(f.projects, projectID)
+	return nil
+}
+
+func (f *fakeCatalogProjects) DeployPiping(ctx context.Context, projectID uuid.UUID, options DeployOptions) error {
+	project := f.projects[projectID]
+	f.deployed = append(f.deployed, project.Name)
+	if err := f.deployErrs[project.Name]; err != nil {
+		return err
+	}
+	project.Status = ProjectStatusRunning
+	return nil
+}
+
+// byName returns the project called name, or nil
+func (f *fakeCatalogProjects) byName(name string) *Project {
+	for _, project := range f.projects {
+		if project.Name == name {
+			return project
+		}
+	}
+	return nil
+}
+
+// setupCatalogService creates a catalog service with a catalog whose repository is checked out in a temporary
+// directory
+func setupCatalogService(
+	t *testing.T,
+	projects *fakeCatalogProjects,
+) (*CatalogService, *MockCatalogRepository, *Catalog, string) {
+	t.Helper()
+
+	workingDir := t.TempDir()
+	gitDir := filepath.Join(workingDir, GitDir)
+	require.NoError(t, os.Mkdir(gitDir, 0o755))
+
+	catalog := &Catalog{
+		ID:         uuid.New(),
+		Name:       "platform",
+		GitURL:     "https://github.com/org/platform.git",
+		GitBranch:  "main",
+		GitAuth:    &GitAuthConfig{HTTPAuth: &GitHTTPAuthConfig{Username: "oar", Password: "token"}},
+		WorkingDir: workingDir,
+		Secrets:    []string{"web-db-password=s3cret"},
+	}
+	repo := NewMockCatalogRepository()
+	require.NoError(t, repo.Create(catalog))
+
+	gitService := &MockGitExecutor{
+		GetLatestCommitFunc: func(workingDir string) (string, error) {
+			return "abc123def456789012345678901234567890abcd", nil
+		},
+	}
+	service := NewCatalogService(repo, projects, gitService, &Config{DataDir: t.TempDir()})
+	return service, repo, catalog, gitDir
+}
+
+// writeCatalogFile writes a catalog file into dir
+func writeCatalogFile(t *testing.T, dir, contents string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, CatalogFile), []byte(contents), 0o644))
+}
+
+func TestReadCatalogFile(t *testing.T) {
+	dir := t.TempDir()
+	writeCatalogFile(t, dir, `
+projects:
+  - name: web
+    git_url: https://github.com/org/web.git
+    branch: production
+    compose_files: [compose.yml]
+    variables:
+      LOG_LEVEL: info
+    secrets:
+      DB_PASSWORD: web-db-password
+    catalog_git_auth: true
+  - name: docs
+    git_url: https://github.com/org/docs.git
+    compose_files: [compose.yml]
+    automatic: false
+`)
+
+	projects, err := ReadCatalogFile(dir)
+	require.NoError(t, err)
+	require.Len(t, projects, 2)
+
+	web := projects[0]
+	assert.Equal(t, "web", web.Name)
+	assert.Equal(t, "production", web.Branch)
+	assert.Equal(t, map[string]string{"LOG_LEVEL": "info"}, web.Variables)
+	assert.Equal(t, map[string]string{"DB_PASSWORD": "web-db-password"}, web.Secrets)
+	assert.True(t, web.CatalogGitAuth)
+	assert.True(t, web.automatic())
+	assert.False(t, projects[1].automatic())
+}
+
+func TestReadCatalogFile_Invalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		contents    string
+		expectedErr string
+	}{
+		{name: "unknown key", contents: "apps: []", expectedErr: "field apps not found"},
+		{
+			name:        "missing name",
+			contents:    "projects: [{git_url: https://github.com/org/web.git, compose_files: [compose.yml]}]",
+			expectedErr: "project 1: name is required",
+		},
+		{
+			name:        "missing compose files",
+			contents:    "projects: [{name: web, git_url: https://github.com/org/web.git}]",
+			expectedErr: "web: compose_files are required",
+		},
+		{
+			name: "compose file outside",
+			contents: "projects: [{name: web, git_url: https://github.com/org/web.git, " +
+				"compose_files: [../compose.yml]}]",
+			expectedErr: "is not a path within the repository",
+		},
+		{
+			name: "variable and secret",
+			contents: "projects: [{name: web, git_url: https://github.com/org/web.git, compose_files: [a.yml], " +
+				"variables: {TOKEN: x}, secrets: {TOKEN: token}}]",
+			expectedErr: "variable TOKEN is both a variable and a secret",
+		},
+		{
+			name: "listed twice",
+			contents: `
+projects:
+  - {name: web, git_url: https://github.com/org/web.git, compose_files: [compose.yml]}
+  - {name: web, git_url: https://github.com/org/web2.git, compose_files: [compose.yml]}
+`,
+			expectedErr: `project "web" is listed twice`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeCatalogFile(t, dir, tt.contents)
+
+			_, err := ReadCatalogFile(dir)
+			assert.ErrorIs(t, err, ErrInvalidCatalog)
+			assert.ErrorContains(t, err, tt.expectedErr)
+		})
+	}
+
+	_, err := ReadCatalogFile(t.TempDir())
+	assert.ErrorIs(t, err, ErrInvalidCatalog)
+}
+
+func TestCatalog_Secrets(t *testing.T) {
+	catalog := &Catalog{Secrets: []string{"db-password=s3cret", "api-token=a=b"}}
+
+	value, ok := catalog.Secret("api-token")
+	assert.True(t, ok)
+	assert.Equal(t, "a=b", value)
+	_, ok = catalog.Secret("s3cret")
+	assert.False(t, ok)
+	assert.Equal(t, []string{"db-password", "api-token"}, catalog.SecretNames())
+}
+
+func TestCatalogService_Create(t *testing.T) {
+	var clonedInto, clonedBranch string
+	gitService := &MockGitExecutor{
+		GetDefaultBranchFunc: func(gitURL string, gitAuth *GitAuthConfig) (string, error) {
+			return "main", nil
+		},
+		CloneFunc: func(gitURL string, gitBranch string, gitAuth *GitAuthConfig, workingDir string) error {
+			clonedInto, clonedBranch = workingDir, gitBranch
+			return nil
+		},
+	}
+	config := &Config{DataDir: t.TempDir()}
+	repo := NewMockCatalogRepository()
+	service := NewCatalogService(repo, newFakeCatalogProjects(), gitService, config)
+
+	catalog, err := service.Create(&Catalog{Name: "Platform Apps", GitURL: "https://github.com/org/platform.git"})
+	require.NoError(t, err)
+
+	assert.NotEqual(t, uuid.Nil, catalog.ID)
+	assert.Equal(t, "main", catalog.GitBranch)
+	assert.Equal(t, "main", clonedBranch)
+	assert.Equal(t, filepath.Join(config.DataDir, CatalogsDir, catalog.ID.String()+"-platform-apps", GitDir),
+		clonedInto)
+	assert.Contains(t, repo.catalogs, catalog.ID)
+
+	_, err = service.Create(&Catalog{Name: "broken", GitURL: "https://github.com/org/platform.git",
+		Secrets: []string{"no-value"}})
+	assert.ErrorIs(t, err, ErrInvalidCatalog)
+	assert.ErrorContains(t, err, "KEY=value")
+}
+
+func TestCatalogService_Sync(t *testing.T) {
+	ctx := context.Background()
+	byHand := NewProject("admin", "https://github.com/org/admin.git", []string{"compose.yml"}, nil)
+	projects := newFakeCatalogProjects(&byHand)
+	service, repo, catalog, gitDir := setupCatalogService(t, projects)
+
+	// The first sync creates and deploys the listed projects
+	writeCatalogFile(t, gitDir, `
+projects:
+  - name: web
+    git_url: https://github.com/org/web.git
+    compose_files: [compose.yml]
+    variables: {LOG_LEVEL: info}
+    secrets: {DB_PASSWORD: web-db-password}
+    catalog_git_auth: true
+  - name: docs
+    git_url: https://github.com/org/docs.git
+    compose_files: [compose.yml]
+    automatic: false
+`)
+	result, err := service.Sync(ctx, catalog.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"web", "docs"}, result.Created)
+	assert.Empty(t, result.Errors)
+	assert.Equal(t, []string{"web", "docs"}, projects.deployed)
+
+	web := projects.byName("web")
+	require.NotNil(t, web)
+	assert.Equal(t, &catalog.ID, web.CatalogID)
+	assert.Equal(t, []string{"DB_PASSWORD=s3cret", "LOG_LEVEL=info"}, web.Variables)
+	assert.Equal(t, catalog.GitAuth, web.GitAuth)
+	assert.True(t, web.WatcherEnabled)
+	assert.Nil(t, projects.byName("docs").GitAuth)
+	assert.False(t, projects.byName("docs").WatcherEnabled)
+
+	// Syncing again without changes does nothing
+	projects.deployed = nil
+	result, err = service.Sync(ctx, catalog.ID)
+	require.NoError(t, err)
+	assert.Empty(t, result.Created)
+	assert.Empty(t, result.Updated)
+	assert.Empty(t, projects.deployed)
+
+	// Changed projects are updated and redeployed, unlisted ones removed, and those that can't be synced reported
+	writeCatalogFile(t, gitDir, `
+projects:
+  - name: web
+    git_url: https://github.com/org/web.git
+    compose_files: [compose.yml, compose.prod.yml]
+    variables: {LOG_LEVEL: debug}
+    secrets: {DB_PASSWORD: web-db-password}
+    catalog_git_auth: true
+  - name: admin
+    git_url: https://github.com/org/admin.git
+    compose_files: [compose.yml]
+  - name: api
+    git_url: https://github.com/org/api.git
+    compose_files: [compose.yml]
+    secrets: {TOKEN: api-token}
+`)
+	result, err = service.Sync(ctx, catalog.ID)
+	require.NoError(t, err)
+	assert.Empty(t, result.Created)
+	assert.Equal(t, []string{"web"}, result.Updated)
+	assert.Equal(t, []string{"docs"}, result.Removed)
+	assert.Equal(t, []string{
+		"admin: a project with this name exists and is not managed by the catalog",
+		`api: secret "api-token" is not set in catalog platform`,
+	}, result.Errors)
+	assert.Equal(t, []string{"web"}, projects.deployed)
+
+	web = projects.byName("web")
+	assert.Equal(t, []string{"compose.yml", "compose.prod.yml"}, web.ComposeFiles)
+	assert.Equal(t, []string{"DB_PASSWORD=s3cret", "LOG_LEVEL=debug"}, web.Variables)
+	assert.Nil(t, projects.byName("docs"))
+	assert.Nil(t, projects.byName("admin").CatalogID)
+
+	saved, err := repo.FindByID(catalog.ID)
+	require.NoError(t, err)
+	assert.Equal(t, result.Commit, saved.LastCommitStr())
+	assert.NotNil(t, saved.LastSyncedAt)
+	assert.Equal(t, "admin: a project with this name exists and is not managed by the catalog; "+
+		`api: secret "api-token" is not set in catalog platform`, saved.LastSyncError)
+}
+
+func TestCatalogService_Sync_DeploymentFailure(t *testing.T) {
+	projects := newFakeCatalogProjects()
+	projects.deployErrs["web"] = errors.New("compose up failed")
+	service, _, catalog, gitDir := setupCatalogService(t, projects)
+	writeCatalogFile(t, gitDir, "projects: [{name: web, git_url: https://github.com/org/web.git, "+
+		"compose_files: [compose.yml]}]")
+
+	result, err := service.Sync(context.Background(), catalog.ID)
+	require.NoError(t, err)
+
+	// The project is created all the same
+	assert.Equal(t, []string{"web"}, result.Created)
+	assert.Equal(t, []string{"web: deployment failed: compose up failed"}, result.Errors)
+	assert.NotNil(t, projects.byName("web"))
+}
+
+func TestCatalogService_Sync_InvalidFile(t *testing.T) {
+	managed := NewProject("web", "https://github.com/org/web.git", []string{"compose.yml"}, nil)
+	projects := newFakeCatalogProjects(&managed)
+	service, repo, catalog, gitDir := setupCatalogService(t, projects)
+	managed.CatalogID = &catalog.ID
+	writeCatalogFile(t, gitDir, "projects: {name: web}")
+
+	result, err := service.Sync(context.Background(), catalog.ID)
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, ErrInvalidCatalog)
+
+	// Projects are left as they are and the failure is recorded
+	assert.NotNil(t, projects.byName("web"))
+	saved, err := repo.FindByID(catalog.ID)
+	require.NoError(t, err)
+	assert.Contains(t, saved.LastSyncError, "invalid catalog")
+}
+
+func TestCatalogService_Remove(t *testing.T) {
+	for _, removeProjects := range []bool{false, true} {
+		t.Run(map[bool]string{false: "keep projects", true: "remove projects"}[removeProjects], func(t *testing.T) {
+			managed := NewProject("web", "https://github.com/org/web.git", []string{"compose.yml"}, nil)
+			byHand := NewProject("admin", "https://github.com/org/admin.git", []string{"compose.yml"}, nil)
+			projects := newFakeCatalogProjects(&managed, &byHand)
+			service, repo, catalog, _ := setupCatalogService(t, projects)
+			managed.CatalogID = &catalog.ID
+
+			require.NoError(t, service.Remove(catalog.ID, removeProjects))
+
+			assert.NotContains(t, repo.catalogs, catalog.ID)
+			assert.NotNil(t, projects.byName("admin"))
+			if removeProjects {
+				assert.Nil(t, projects.byName("web"))
+			} else {
+				require.NotNil(t, projects.byName("web"))
+				assert.Nil(t, projects.byName("web").CatalogID)
+			}
+		})
+	}
+}
@@ -2,21 +2,74 @@ package services
 
 import (
 	"bufio"
+	"bytes"
+	"cmp"
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"log/slog"
+	"math"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 )
 
+// commandWaitDelay is how long a cancelled Docker Compose command gets to exit after being interrupted
+// before it is killed
+const commandWaitDelay = 10 * time.Second
+
+// ErrComposeTimeout is returned by a Docker Compose command that was stopped for running longer than its timeout
+var ErrComposeTimeout = errors.New("timed out")
+
+// configHashLabel is the label Docker Compose records the hash of the configuration of a service in on its
+// containers, to recreate them when the configuration changes
+const configHashLabel = "com.docker.compose.config-hash"
+
 type ContainerInfo struct {
 	Service    string `json:"Service"`
 	Name       string `json:"Name"`
+	Image      string `json:"Image"`
 	State      string `json:"State"`
 	Status     string `json:"Status"`
 	RunningFor string `json:"RunningFor"`
+	ExitCode   int    `json:"ExitCode"`
+	Labels     string `json:"Labels"` // Comma-separated key=value pairs
+}
+
+// failed reports whether the container exited with an error, died or was created but never started
+func (c ContainerInfo) failed() bool {
+	switch c.State {
+	case "exited":
+		return c.ExitCode != 0
+	case "dead", "created":
+		return true
+	default:
+		return false
+	}
+}
+
+// Label returns the value of the label key of the container, or "" if it has none
+func (c ContainerInfo) Label(key string) string {
+	for label := range strings.SplitSeq(c.Labels, ",") {
+		if value, ok := strings.CutPrefix(label, key+"="); ok {
+			return value
+		}
+	}
+	return ""
+}
+
+// ComposeVolume is a named volume declared by a Docker Compose project
+type ComposeVolume struct {
+	Key    string // Key of the volume in the Compose files
+	Name   string // Name of the Docker volume
+	Exists bool   // Whether Docker Compose has created the volume
 }
 
 type ComposeStatus struct {
@@ -32,10 +85,15 @@ type ComposeProject struct {
 	WorkingDir string
 	// ComposeFiles is a list of Docker Compose files for the project.
 	ComposeFiles []string
+	// Profiles is a list of Docker Compose profiles to enable, as declared by the manifest of the repository.
+	Profiles []string
 	// Variables contains variables in KEY=value format
 	Variables []string
 	// Config holds configuration for docker commands and timeouts
 	Config *Config
+	// Timeouts limits pulling images, starting the project and waiting for its services to be healthy.
+	// Zero timeouts don't limit anything.
+	Timeouts ComposeTimeouts
 }
 
 // Ensure ComposeProject implements ComposeProjectInterface
@@ -52,28 +110,121 @@ func NewComposeProject(p *Project, config *Config) *ComposeProject {
 		return nil
 	}
 
-	return &ComposeProject{
+	manifest, err := ReadManifest(gitDir)
+	if err != nil {
+		slog.Warn("Ignoring invalid project manifest",
+			"layer", "docker_compose",
+			"operation", "create_compose_project",
+			"project_name", p.Name,
+			"error", err)
+	}
+
+	return newComposeProject(p, gitDir, manifest, config)
+}
+
+// newComposeProject creates a ComposeProject for a project checked out in gitDir, with the settings declared in
+// its manifest in place of those of the project
+func newComposeProject(p *Project, gitDir string, manifest *Manifest, config *Config) *ComposeProject {
+	p = manifest.Apply(p)
+
+	composeProject := &ComposeProject{
 		Name:         p.Name,
 		WorkingDir:   gitDir,
 		ComposeFiles: p.ComposeFiles,
 		Variables:    p.Variables,
 		Config:       config,
+		Timeouts:     p.Timeouts.Or(config.ComposeTimeouts()),
+	}
+	if manifest != nil {
+		composeProject.Profiles = manifest.Profiles
 	}
+	return composeProject
 }
 
 func (p *ComposeProject) Up() (string, error) {
-	cmd := p.commandUp()
+	cmd := p.commandUp(context.Background())
 	return p.executeCommand(cmd)
 }
 
-func (p *ComposeProject) UpStreaming(outputChan chan<- string) error {
-	cmd := p.commandUp()
-	return p.executeCommandStreaming(cmd, outputChan)
+// UpStreaming starts the project, streaming output to outputChan. Cancelling ctx interrupts the command and
+// returns the context's error, running out of the up timeout returns ErrComposeTimeout.
+func (p *ComposeProject) UpStreaming(ctx context.Context, outputChan chan<- string) error {
+	return p.withTimeout(ctx, "up", p.Timeouts.Up, func(ctx context.Context) error {
+		return p.executeCommandStreaming(p.commandUp(ctx), outputChan)
+	})
 }
 
-func (p *ComposeProject) UpPiping() error {
-	cmd := p.commandUp()
-	return p.executeCommandPiping(cmd)
+// UpPiping starts the project, piping output to the terminal. Cancelling ctx interrupts the command and
+// returns the context's error, running out of the up timeout returns ErrComposeTimeout.
+func (p *ComposeProject) UpPiping(ctx context.Context) error {
+	return p.withTimeout(ctx, "up", p.Timeouts.Up, func(ctx context.Context) error {
+		return p.executeCommandPiping(p.commandUp(ctx))
+	})
+}
+
+// PullStreaming pulls the images of the project, streaming output to outputChan. Cancelling ctx interrupts
+// the command and returns the context's error, running out of the pull timeout returns ErrComposeTimeout.
+func (p *ComposeProject) PullStreaming(ctx context.Context, outputChan chan<- string) error {
+	return p.withTimeout(ctx, "pull", p.Timeouts.Pull, func(ctx context.Context) error {
+		return p.executeCommandStreaming(p.commandPull(ctx), outputChan)
+	})
+}
+
+// Exec runs command with sh in the container of service, returning its combined output. Cancelling ctx
+// interrupts the command and returns the context's error.
+func (p *ComposeProject) Exec(ctx context.Context, service, command string) (string, error) {
+	out, err := p.commandExec(ctx, service, command).CombinedOutput()
+	return string(out), contextError(ctx, err)
+}
+
+// RunHookStreaming runs the command of a deployment hook, streaming output to outputChan. Cancelling ctx
+// interrupts the command and returns the context's error.
+func (p *ComposeProject) RunHookStreaming(ctx context.Context, hook Hook, outputChan chan<- string) error {
+	var cmd *exec.Cmd
+	switch hook.Type {
+	case HookTypeRun:
+		cmd = p.commandRun(ctx, hook.Service, hook.Command)
+	case HookTypeExec:
+		cmd = p.commandExec(ctx, hook.Service, hook.Command)
+	case HookTypeScript:
+		cmd = p.commandScript(ctx, hook.Command)
+	default:
+		return fmt.Errorf("%w: unknown type", ErrInvalidHook)
+	}
+	return contextError(ctx, p.executeCommandStreaming(cmd, outputChan))
+}
+
+// withTimeout runs a command, interrupting it once timeout has passed. A command that runs out of time
+// returns ErrComposeTimeout.
+func (p *ComposeProject) withTimeout(
+	ctx context.Context,
+	command string,
+	timeout time.Duration,
+	run func(ctx context.Context) error,
+) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	err := contextError(ctx, run(ctx))
+	if errors.Is(err, context.DeadlineExceeded) {
+		slog.Warn("Docker Compose command timed out",
+			"project_name", p.Name,
+			"command", command,
+			"timeout", timeout)
+		return fmt.Errorf("docker compose %s %w after %s", command, ErrComposeTimeout, timeout)
+	}
+	return err
+}
+
+// contextError returns the context's error in place of err if ctx was cancelled while the command ran
+func contextError(ctx context.Context, err error) error {
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
 }
 
 func (p *ComposeProject) Down() (string, error) {
@@ -111,7 +262,400 @@ func (p *ComposeProject) GetConfig() (string, error) {
 	return p.executeCommand(cmd)
 }
 
-func (p *ComposeProject) prepareCommand(command string, args []string) *exec.Cmd {
+// Volumes returns the named volumes the project declares. External volumes belong to something else and are left
+// out.
+func (p *ComposeProject) Volumes(ctx context.Context) ([]ComposeVolume, error) {
+	out, err := p.output(ctx, p.prepareCommand(ctx, "config", []string{"--format", "json"}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Docker Compose configuration: %w", err)
+	}
+	var config struct {
+		Volumes map[string]struct {
+			Name     string `json:"name"`
+			External bool   `json:"external"`
+		} `json:"volumes"`
+	}
+	if err := json.Unmarshal([]byte(out), &config); err != nil {
+		return nil, fmt.Errorf("failed to parse Docker Compose configuration: %w", err)
+	}
+
+	out, err = p.output(ctx, p.prepareDockerCommand(ctx,
+		"volume", "ls", "--quiet", "--filter", "label=com.docker.compose.project="+p.Name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list volumes: %w", err)
+	}
+	existing := strings.Fields(out)
+
+	var volumes []ComposeVolume
+	for key, volume := range config.Volumes {
+		if volume.External {
+			continue
+		}
+		volumes = append(volumes, ComposeVolume{
+			Key:    key,
+			Name:   volume.Name,
+			Exists: slices.Contains(existing, volume.Name),
+		})
+	}
+	slices.SortFunc(volumes, func(a, b ComposeVolume) int { return strings.Compare(a.Key, b.Key) })
+	return volumes, nil
+}
+
+// composeService is the part of the configuration of a service that drift detection, reconciliation and image
+// watching use
+type composeService struct {
+	Image      string          `json:"image"`
+	Build      json.RawMessage `json:"build"`
+	PullPolicy string          `json:"pull_policy"`
+	Scale      *int            `json:"scale"`
+	Deploy     *struct {
+		Replicas *int `json:"replicas"`
+	} `json:"deploy"`
+}
+
+// pulled reports whether the image of the service is pulled from a registry by a tag, which can point to another
+// digest later. Images built from source, never pulled or pinned by digest are not.
+func (s composeService) pulled() bool {
+	switch {
+	case s.Image == "", s.Build != nil, strings.Contains(s.Image, "@"):
+		return false
+	default:
+		return s.PullPolicy != "never" && s.PullPolicy != "build"
+	}
+}
+
+// scaledToZero reports whether the service is not expected to have containers
+func (s composeService) scaledToZero() bool {
+	return s.Scale != nil && *s.Scale == 0 || s.Deploy != nil && s.Deploy.Replicas != nil && *s.Deploy.Replicas == 0
+}
+
+// services returns the services the project declares, by name, as rendered by docker compose config
+func (p *ComposeProject) services(ctx context.Context) (map[string]composeService, error) {
+	out, err := p.output(ctx, p.prepareCommand(ctx, "config", []string{"--format", "json"}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Docker Compose configuration: %w", err)
+	}
+	var config struct {
+		Services map[string]composeService `json:"services"`
+	}
+	if err := json.Unmarshal([]byte(out), &config); err != nil {
+		return nil, fmt.Errorf("failed to parse Docker Compose configuration: %w", err)
+	}
+	return config.Services, nil
+}
+
+// allContainers returns the containers of the project, stopped ones included
+func (p *ComposeProject) allContainers(ctx context.Context) ([]ContainerInfo, error) {
+	out, err := p.output(ctx, p.prepareCommand(ctx, "ps", []string{"--all", "--format", "json"}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+	return p.parseContainers(out), nil
+}
+
+// Drift compares the services the project declares with its containers, stopped ones included. It reports
+// declared services without containers, containers of services that aren't declared, and containers whose image
+// or configuration hash differ from the declared ones, such as containers recreated by hand. Services scaled to
+// zero are not expected to have containers.
+func (p *ComposeProject) Drift(ctx context.Context) ([]ServiceDrift, error) {
+	services, err := p.services(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// One "<service> <hash>" line per service, the hash Docker Compose labels the containers with
+	out, err := p.output(ctx, p.prepareCommand(ctx, "config", []string{"--hash", "*"}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash Docker Compose configuration: %w", err)
+	}
+	hashes := make(map[string]string)
+	for line := range strings.Lines(out) {
+		if service, hash, ok := strings.Cut(strings.TrimSpace(line), " "); ok {
+			hashes[service] = hash
+		}
+	}
+
+	containers, err := p.allContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var drift []ServiceDrift
+	for _, container := range containers {
+		service, declared := services[container.Service]
+		switch {
+		case !declared:
+			drift = append(drift, ServiceDrift{
+				Service:   container.Service,
+				Kind:      DriftKindExtra,
+				Container: container.Name,
+			})
+		case service.Image != "" && container.Image != service.Image:
+			drift = append(drift, ServiceDrift{
+				Service:   container.Service,
+				Kind:      DriftKindImage,
+				Container: container.Name,
+				Expected:  service.Image,
+				Actual:    container.Image,
+			})
+		case hashes[container.Service] != "" && container.Label(configHashLabel) != hashes[container.Service]:
+			drift = append(drift, ServiceDrift{
+				Service:   container.Service,
+				Kind:      DriftKindConfig,
+				Container: container.Name,
+				Expected:  hashes[container.Service],
+				Actual:    container.Label(configHashLabel),
+			})
+		}
+	}
+	for name, service := range services {
+		if !service.scaledToZero() && !hasContainer(containers, name) {
+			drift = append(drift, ServiceDrift{Service: name, Kind: DriftKindMissing})
+		}
+	}
+
+	slices.SortFunc(drift, func(a, b ServiceDrift) int {
+		return cmp.Or(strings.Compare(a.Service, b.Service), strings.Compare(a.Container, b.Container))
+	})
+	return drift, nil
+}
+
+// DownServices returns the declared services that are down, sorted: those without a container, such as after
+// the containers were removed, and those with a container that exited with an error, died or was never started,
+// such as after the host rebooted. Containers that exited cleanly, such as those of one-off jobs, and services
+// scaled to zero are not down.
+func (p *ComposeProject) DownServices(ctx context.Context) ([]string, error) {
+	services, err := p.services(ctx)
+	if err != nil {
+		return nil, err
+	}
+	containers, err := p.allContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var down []string
+	for name, service := range services {
+		if service.scaledToZero() {
+			continue
+		}
+		failed := slices.ContainsFunc(containers, func(container ContainerInfo) bool {
+			return container.Service == name && container.failed()
+		})
+		if failed || !hasContainer(containers, name) {
+			down = append(down, name)
+		}
+	}
+	slices.Sort(down)
+	return down, nil
+}
+
+// ImageUpdates compares the images the containers of the project run with the digests the images their services
+// declare resolve to in the registry, and reports the containers running an older image, sorted. Only images pulled
+// by a tag are compared, and images that weren't pulled from a registry, such as those loaded by hand, are skipped.
+// Images the registry can't be asked about are logged and skipped, so that one unreachable registry doesn't hold
+// back the others.
+func (p *ComposeProject) ImageUpdates(ctx context.Context) ([]ImageUpdate, error) {
+	services, err := p.services(ctx)
+	if err != nil {
+		return nil, err
+	}
+	containers, err := p.allContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	containers = slices.DeleteFunc(containers, func(container ContainerInfo) bool {
+		service, declared := services[container.Service]
+		return !declared || !service.pulled()
+	})
+	if len(containers) == 0 {
+		return nil, nil
+	}
+
+	imageIDs, err := p.containerImages(ctx, containers)
+	if err != nil {
+		return nil, err
+	}
+	pulledDigests, err := p.repoDigests(ctx, imageIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	latest := make(map[string]string) // Digest of each image in the registry, empty if it couldn't be resolved
+	var updates []ImageUpdate
+	for i, container := range containers {
+		image := services[container.Service].Image
+		pulled := pulledDigests[imageIDs[i]]
+		if len(pulled) == 0 {
+			continue
+		}
+		digest, resolved := latest[image]
+		if !resolved {
+			digest, err = p.registryDigest(ctx, image)
+			if err != nil {
+				slog.Warn("Failed to resolve image digest",
+					"layer", "docker_compose",
+					"project_name", p.Name,
+					"image", image,
+					"error", err)
+			}
+			latest[image] = digest
+		}
+		if digest == "" || slices.Contains(pulled, digest) {
+			continue
+		}
+		updates = append(updates, ImageUpdate{
+			Service:   container.Service,
+			Container: container.Name,
+			Image:     image,
+			Current:   imageIDs[i],
+			Latest:    digest,
+		})
+	}
+
+	slices.SortFunc(updates, func(a, b ImageUpdate) int {
+		return cmp.Or(strings.Compare(a.Service, b.Service), strings.Compare(a.Container, b.Container))
+	})
+	return updates, nil
+}
+
+// containerImages returns the IDs of the images containers run, in the same order
+func (p *ComposeProject) containerImages(ctx context.Context, containers []ContainerInfo) ([]string, error) {
+	args := []string{"container", "inspect", "--format", "{{.Image}}"}
+	for _, container := range containers {
+		args = append(args, container.Name)
+	}
+	out, err := p.output(ctx, p.prepareDockerCommand(ctx, args...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect containers: %w", err)
+	}
+	imageIDs := strings.Fields(out)
+	if len(imageIDs) != len(containers) {
+		return nil, fmt.Errorf("failed to inspect containers: expected %d images, got %d",
+			len(containers), len(imageIDs))
+	}
+	return imageIDs, nil
+}
+
+// repoDigests returns the digests images were pulled by from their registries, by image ID. Images that weren't
+// pulled from a registry have none.
+func (p *ComposeProject) repoDigests(ctx context.Context, imageIDs []string) (map[string][]string, error) {
+	args := []string{"image", "inspect", "--format", "{{.Id}} {{json .RepoDigests}}"}
+	args = append(args, slices.Compact(slices.Sorted(slices.Values(imageIDs)))...)
+	out, err := p.output(ctx, p.prepareDockerCommand(ctx, args...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect images: %w", err)
+	}
+
+	digests := make(map[string][]string)
+	for line := range strings.Lines(out) {
+		id, repoDigests, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if !ok {
+			continue
+		}
+		var references []string
+		if err := json.Unmarshal([]byte(repoDigests), &references); err != nil {
+			return nil, fmt.Errorf("failed to parse digests of image %s: %w", id, err)
+		}
+		for _, reference := range references {
+			if _, digest, ok := strings.Cut(reference, "@"); ok {
+				digests[id] = append(digests[id], digest)
+			}
+		}
+	}
+	return digests, nil
+}
+
+// registryDigest returns the digest image resolves to in its registry, with the credentials Docker has for it
+func (p *ComposeProject) registryDigest(ctx context.Context, image string) (string, error) {
+	out, err := p.output(ctx, p.prepareDockerCommand(ctx,
+		"buildx", "imagetools", "inspect", "--format", "{{json .Manifest}}", image))
+	if err != nil {
+		return "", err
+	}
+	var manifest struct {
+		Digest string `json:"digest"`
+	}
+	if err := json.Unmarshal([]byte(out), &manifest); err != nil {
+		return "", fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	if manifest.Digest == "" {
+		return "", fmt.Errorf("manifest has no digest")
+	}
+	return manifest.Digest, nil
+}
+
+// hasContainer reports whether one of containers belongs to service
+func hasContainer(containers []ContainerInfo, service string) bool {
+	return slices.ContainsFunc(containers, func(container ContainerInfo) bool {
+		return container.Service == service
+	})
+}
+
+// BackupVolume writes a gzipped tarball of the contents of the named volume to w, archived by a helper container.
+// Cancelling ctx interrupts the command and returns the context's error.
+func (p *ComposeProject) BackupVolume(ctx context.Context, volume string, w io.Writer) error {
+	cmd := p.prepareDockerCommand(ctx, "run", "--rm", "--volume", volume+":/volume:ro",
+		p.Config.VolumeBackupImage, "tar", "-czf", "-", "-C", "/volume", ".")
+	cmd.Stdout = w
+	return p.run(ctx, cmd)
+}
+
+// RestoreVolume replaces the contents of the named volume with those of the gzipped tarball read from r, extracted
+// by a helper container. Cancelling ctx interrupts the command and returns the context's error.
+func (p *ComposeProject) RestoreVolume(ctx context.Context, volume string, r io.Reader) error {
+	cmd := p.prepareDockerCommand(ctx, "run", "--rm", "--interactive", "--volume", volume+":/volume",
+		p.Config.VolumeBackupImage, "sh", "-c", "find /volume -mindepth 1 -delete && tar -xzf - -C /volume")
+	cmd.Stdin = r
+	return p.run(ctx, cmd)
+}
+
+// output runs cmd and returns its standard output, with its error output in the error if it fails
+func (p *ComposeProject) output(ctx context.Context, cmd *exec.Cmd) (string, error) {
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	if err := p.run(ctx, cmd); err != nil {
+		return "", err
+	}
+	return stdout.String(), nil
+}
+
+// run runs cmd, with its error output in the error if it fails. Cancelling ctx returns the context's error.
+func (p *ComposeProject) run(ctx context.Context, cmd *exec.Cmd) error {
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return fmt.Errorf("%w: %s", err, message)
+		}
+		return err
+	}
+	return nil
+}
+
+// prepareDockerCommand prepares a Docker command that is not a Docker Compose one, such as running a helper
+// container
+func (p *ComposeProject) prepareDockerCommand(ctx context.Context, args ...string) *exec.Cmd {
+	commandArgs := append([]string{"--host", p.Config.DockerHost}, args...)
+
+	slog.Debug("Executing Docker command",
+		"command", p.Config.DockerCommand,
+		"args", commandArgs,
+		"project_name", p.Name)
+
+	cmd := exec.CommandContext(ctx, p.Config.DockerCommand, commandArgs...)
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(os.Interrupt)
+	}
+	cmd.WaitDelay = commandWaitDelay
+	cmd.Env = append(os.Environ(), "NO_COLOR=1")
+	return cmd
+}
+
+func (p *ComposeProject) prepareCommand(ctx context.Context, command string, args []string) *exec.Cmd {
 	// Build docker compose command
 	commandArgs := []string{
 		"--host", p.Config.DockerHost,
@@ -125,6 +669,11 @@ func (p *ComposeProject) prepareCommand(command string, args []string) *exec.Cmd
 		commandArgs = append(commandArgs, "--file", filepath.Join(p.WorkingDir, file))
 	}
 
+	// Enable profiles
+	for _, profile := range p.Profiles {
+		commandArgs = append(commandArgs, "--profile", profile)
+	}
+
 	// Add the specific command and its arguments
 	commandArgs = append(commandArgs, command)
 	commandArgs = append(commandArgs, args...)
@@ -135,7 +684,12 @@ func (p *ComposeProject) prepareCommand(command string, args []string) *exec.Cmd
 		"project_name", p.Name)
 
 	// Create command
-	cmd := exec.Command(p.Config.DockerCommand, commandArgs...)
+	cmd := exec.CommandContext(ctx, p.Config.DockerCommand, commandArgs...)
+	// Interrupt rather than kill on cancellation so Docker Compose can stop cleanly
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(os.Interrupt)
+	}
+	cmd.WaitDelay = commandWaitDelay
 	// Do not set cmd.Dir to avoid Docker resolving container paths as host paths.
 	// The compose files are already specified with absolute paths via --file flags.
 
@@ -285,24 +839,71 @@ func (p *ComposeProject) executeCommandPiping(cmd *exec.Cmd) error {
 	return nil
 }
 
-func (p *ComposeProject) commandUp() *exec.Cmd {
-	return p.prepareCommand("up", []string{"--detach", "--wait", "--quiet-pull", "--no-color", "--remove-orphans"})
+func (p *ComposeProject) commandUp(ctx context.Context) *exec.Cmd {
+	args := []string{"--detach", "--wait", "--quiet-pull", "--no-color", "--remove-orphans"}
+	if p.Timeouts.Wait > 0 {
+		args = append(args, "--wait-timeout", strconv.Itoa(int(math.Ceil(p.Timeouts.Wait.Seconds()))))
+	}
+	return p.prepareCommand(ctx, "up", args)
+}
+
+func (p *ComposeProject) commandPull(ctx context.Context) *exec.Cmd {
+	// Services built from source have no image to pull
+	return p.prepareCommand(ctx, "pull", []string{"--quiet", "--ignore-buildable"})
+}
+
+func (p *ComposeProject) commandExec(ctx context.Context, service, command string) *exec.Cmd {
+	return p.prepareCommand(ctx, "exec", []string{"--no-TTY", service, "sh", "-c", command})
+}
+
+func (p *ComposeProject) commandRun(ctx context.Context, service, command string) *exec.Cmd {
+	return p.prepareCommand(ctx, "run", []string{"--rm", "--no-TTY", service, "sh", "-c", command})
+}
+
+// commandScript runs command with sh on the host, in the repository. The environment has the variables of the
+// project and points docker compose at the project, so scripts can run Docker Compose commands of their own.
+func (p *ComposeProject) commandScript(ctx context.Context, command string) *exec.Cmd {
+	files := make([]string, len(p.ComposeFiles))
+	for i, file := range p.ComposeFiles {
+		files[i] = filepath.Join(p.WorkingDir, file)
+	}
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(os.Interrupt)
+	}
+	cmd.WaitDelay = commandWaitDelay
+	cmd.Dir = p.WorkingDir
+	cmd.Env = append(os.Environ(),
+		"NO_COLOR=1",
+		"DOCKER_HOST="+p.Config.DockerHost,
+		"COMPOSE_PROJECT_NAME="+p.Name,
+		"COMPOSE_FILE="+strings.Join(files, string(os.PathListSeparator)),
+		"COMPOSE_PROFILES="+strings.Join(p.Profiles, ","),
+	)
+	cmd.Env = append(cmd.Env, p.Variables...)
+
+	slog.Debug("Executing hook script",
+		"command", command,
+		"project_name", p.Name)
+
+	return cmd
 }
 
 func (p *ComposeProject) commandDown() *exec.Cmd {
-	return p.prepareCommand("down", []string{"--remove-orphans"})
+	return p.prepareCommand(context.Background(), "down", []string{"--remove-orphans"})
 }
 
 func (p *ComposeProject) commandLogs() *exec.Cmd {
-	return p.prepareCommand("logs", []string{"--follow"})
+	return p.prepareCommand(context.Background(), "logs", []string{"--follow"})
 }
 
 func (p *ComposeProject) commandConfig() *exec.Cmd {
-	return p.prepareCommand("config", []string{})
+	return p.prepareCommand(context.Background(), "config", []string{})
 }
 
 func (p *ComposeProject) commandPs() *exec.Cmd {
-	return p.prepareCommand("ps", []string{"--format", "json"})
+	return p.prepareCommand(context.Background(), "ps", []string{"--format", "json"})
 }
 
 func (p *ComposeProject) Status() (*ComposeStatus, error) {
@@ -318,24 +919,7 @@ func (p *ComposeProject) Status() (*ComposeStatus, error) {
 		return nil, err
 	}
 
-	var containers []ContainerInfo
-	lines := strings.Split(strings.TrimSpace(output), "\n")
-
-	for _, line := range lines {
-		if strings.TrimSpace(line) == "" {
-			continue
-		}
-
-		var container ContainerInfo
-		if err := json.Unmarshal([]byte(line), &container); err != nil {
-			slog.Error("Failed to parse container JSON",
-				"project_name", p.Name,
-				"line", line,
-				"error", err)
-			continue
-		}
-		containers = append(containers, container)
-	}
+	containers := p.parseContainers(output)
 
 	// Determine overall project status
 	projectStatus := "stopped"
@@ -364,3 +948,26 @@ func (p *ComposeProject) Status() (*ComposeStatus, error) {
 		Uptime:     uptime,
 	}, nil
 }
+
+// parseContainers parses the output of docker compose ps --format json, one container per line
+func (p *ComposeProject) parseContainers(output string) []ContainerInfo {
+	var containers []ContainerInfo
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		var container ContainerInfo
+		if err := json.Unmarshal([]byte(line), &container); err != nil {
+			slog.Error("Failed to parse container JSON",
+				"project_name", p.Name,
+				"line", line,
+				"error", err)
+			continue
+		}
+		containers = append(containers, container)
+	}
+	return containers
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -287,7 +288,7 @@ func TestComposeProject_Integration_Streaming(t *testing.T) {
 	// Start streaming in goroutine
 	done := make(chan error, 1)
 	go func() {
-		done <- testProject.Project.UpStreaming(outputChan)
+		done <- testProject.Project.UpStreaming(context.Background(), outputChan)
 	}()
 
 	// Collect output for a reasonable time
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -50,6 +51,25 @@ func TestNewComposeProject_Success(t *testing.T) {
 	assert.Equal(t, testProject.Variables, composeProject.Variables)
 }
 
+func TestNewComposeProject_Timeouts(t *testing.T) {
+	testProject := createTestProjectWithOptions(ProjectOptions{Name: "test-compose-project"})
+	testProject.WorkingDir = t.TempDir()
+	testProject.Timeouts = ComposeTimeouts{Up: time.Hour}
+
+	config := &Config{
+		DockerCommand:      "docker",
+		ComposePullTimeout: 15 * time.Minute,
+		ComposeUpTimeout:   10 * time.Minute,
+		ComposeWaitTimeout: 5 * time.Minute,
+	}
+
+	composeProject := NewComposeProject(testProject, config)
+
+	// The project's own timeouts take precedence over the defaults
+	expected := ComposeTimeouts{Pull: 15 * time.Minute, Up: time.Hour, Wait: 5 * time.Minute}
+	assert.Equal(t, expected, composeProject.Timeouts)
+}
+
 func TestNewComposeProject_InvalidProject(t *testing.T) {
 	// Create project with invalid working directory
 	testProject := createTestProjectWithOptions(ProjectOptions{
@@ -78,6 +98,43 @@ func TestNewComposeProject_InvalidProject(t *testing.T) {
 	assert.Nil(t, composeProject)
 }
 
+func TestNewComposeProject_Manifest(t *testing.T) {
+	testProject := createTestProjectWithOptions(ProjectOptions{Name: "test-compose-project"})
+	testProject.WorkingDir = t.TempDir()
+	testProject.Timeouts = ComposeTimeouts{Up: time.Hour, Wait: time.Hour}
+	gitDir := filepath.Join(testProject.WorkingDir, GitDir)
+	require.NoError(t, os.Mkdir(gitDir, 0o755))
+	manifest := `
+compose_files: [compose.yml, compose.prod.yml]
+profiles: [workers]
+timeouts:
+  up: 20m
+`
+	require.NoError(t, os.WriteFile(filepath.Join(gitDir, ManifestFile), []byte(manifest), 0o644))
+
+	composeProject := NewComposeProject(testProject, &Config{DockerCommand: "docker"})
+
+	// The manifest takes precedence over the project's own settings
+	assert.Equal(t, []string{"compose.yml", "compose.prod.yml"}, composeProject.ComposeFiles)
+	assert.Equal(t, []string{"workers"}, composeProject.Profiles)
+	assert.Equal(t, ComposeTimeouts{Up: 20 * time.Minute, Wait: time.Hour}, composeProject.Timeouts)
+	assert.Equal(t, []string{"docker-compose.yml"}, testProject.ComposeFiles)
+}
+
+func TestNewComposeProject_InvalidManifest(t *testing.T) {
+	testProject := createTestProjectWithOptions(ProjectOptions{Name: "test-compose-project"})
+	testProject.WorkingDir = t.TempDir()
+	gitDir := filepath.Join(testProject.WorkingDir, GitDir)
+	require.NoError(t, os.Mkdir(gitDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(gitDir, ManifestFile), []byte("profiles: workers"), 0o644))
+
+	composeProject := NewComposeProject(testProject, &Config{DockerCommand: "docker"})
+
+	// An invalid manifest is ignored
+	assert.Equal(t, testProject.ComposeFiles, composeProject.ComposeFiles)
+	assert.Empty(t, composeProject.Profiles)
+}
+
 // Tests for ComposeProject.prepareCommand
 func TestComposeProject_PrepareCommand_Basic(t *testing.T) {
 	composeProject := createTestComposeProject()
@@ -85,7 +142,7 @@ func TestComposeProject_PrepareCommand_Basic(t *testing.T) {
 	composeProject.WorkingDir = tempDir
 
 	// Test
-	cmd := composeProject.prepareCommand("up", []string{"--detach"})
+	cmd := composeProject.prepareCommand(context.Background(), "up", []string{"--detach"})
 
 	// Assertions
 	assert.NotNil(t, cmd)
@@ -117,7 +174,7 @@ func TestComposeProject_PrepareCommand_MultipleFiles(t *testing.T) {
 	composeProject.WorkingDir = tempDir
 
 	// Test
-	cmd := composeProject.prepareCommand("down", []string{"--remove-orphans"})
+	cmd := composeProject.prepareCommand(context.Background(), "down", []string{"--remove-orphans"})
 
 	// Assertions
 	assert.NotNil(t, cmd)
@@ -138,6 +195,28 @@ func TestComposeProject_PrepareCommand_MultipleFiles(t *testing.T) {
 	assert.Equal(t, expectedArgs, cmd.Args)
 }
 
+func TestComposeProject_PrepareCommand_Profiles(t *testing.T) {
+	composeProject := createTestComposeProject()
+	composeProject.Profiles = []string{"workers", "debug"}
+	tempDir := t.TempDir()
+	composeProject.WorkingDir = tempDir
+
+	cmd := composeProject.prepareCommand(context.Background(), "up", []string{})
+
+	expectedArgs := []string{
+		"docker",
+		"--host", "unix:///var/run/docker.sock",
+		"compose",
+		"--progress", "plain",
+		"--project-name", "test-project",
+		"--file", filepath.Join(tempDir, "docker-compose.yml"),
+		"--profile", "workers",
+		"--profile", "debug",
+		"up",
+	}
+	assert.Equal(t, expectedArgs, cmd.Args)
+}
+
 func TestComposeProject_PrepareCommand_NoFiles(t *testing.T) {
 	composeProject := createTestComposeProject()
 	composeProject.ComposeFiles = []string{} // No compose files
@@ -145,7 +224,7 @@ func TestComposeProject_PrepareCommand_NoFiles(t *testing.T) {
 	composeProject.WorkingDir = tempDir
 
 	// Test
-	cmd := composeProject.prepareCommand("ps", []string{})
+	cmd := composeProject.prepareCommand(context.Background(), "ps", []string{})
 
 	// Assertions
 	assert.NotNil(t, cmd)
@@ -169,7 +248,7 @@ func TestComposeProject_CommandUp(t *testing.T) {
 	composeProject.WorkingDir = tempDir
 
 	// Test
-	cmd := composeProject.commandUp()
+	cmd := composeProject.commandUp(context.Background())
 
 	// Assertions
 	assert.NotNil(t, cmd)
@@ -182,6 +261,72 @@ func TestComposeProject_CommandUp(t *testing.T) {
 	assert.Contains(t, args, "--quiet-pull")
 	assert.Contains(t, args, "--no-color")
 	assert.Contains(t, args, "--remove-orphans")
+	assert.NotContains(t, args, "--wait-timeout")
+}
+
+func TestComposeProject_CommandUp_WaitTimeout(t *testing.T) {
+	composeProject := createTestComposeProject()
+	composeProject.Timeouts.Wait = 90 * time.Second
+
+	cmd := composeProject.commandUp(context.Background())
+	assert.Equal(t, []string{"--wait-timeout", "90"}, cmd.Args[len(cmd.Args)-2:])
+
+	// Compose waits in whole seconds, so a fraction of a second rounds up
+	composeProject.Timeouts.Wait = 1500 * time.Millisecond
+	cmd = composeProject.commandUp(context.Background())
+	assert.Equal(t, []string{"--wait-timeout", "2"}, cmd.Args[len(cmd.Args)-2:])
+}
+
+func TestComposeProject_CommandPull(t *testing.T) {
+	composeProject := createTestComposeProject()
+
+	cmd := composeProject.commandPull(context.Background())
+
+	assert.Equal(t, []string{"pull", "--quiet", "--ignore-buildable"}, cmd.Args[len(cmd.Args)-3:])
+}
+
+func TestComposeProject_CommandRun(t *testing.T) {
+	composeProject := createTestComposeProject()
+
+	cmd := composeProject.commandRun(context.Background(), "app", "./manage.py migrate")
+
+	expected := []string{"run", "--rm", "--no-TTY", "app", "sh", "-c", "./manage.py migrate"}
+	assert.Equal(t, expected, cmd.Args[len(cmd.Args)-7:])
+}
+
+func TestComposeProject_CommandScript(t *testing.T) {
+	composeProject := createTestComposeProject()
+	composeProject.WorkingDir = t.TempDir()
+	composeProject.ComposeFiles = []string{"compose.yml", "compose.prod.yml"}
+
+	cmd := composeProject.commandScript(context.Background(), "./scripts/warm.sh")
+
+	assert.Equal(t, []string{"sh", "-c", "./scripts/warm.sh"}, cmd.Args)
+	assert.Equal(t, composeProject.WorkingDir, cmd.Dir)
+	assert.Contains(t, cmd.Env, "KEY1=value1")
+	assert.Contains(t, cmd.Env, "COMPOSE_PROJECT_NAME=test-project")
+	assert.Contains(t, cmd.Env, "COMPOSE_PROFILES=")
+	assert.Contains(t, cmd.Env, "DOCKER_HOST=unix:///var/run/docker.sock")
+	assert.Contains(t, cmd.Env, "COMPOSE_FILE="+filepath.Join(composeProject.WorkingDir, "compose.yml")+
+		string(os.PathListSeparator)+filepath.Join(composeProject.WorkingDir, "compose.prod.yml"))
+}
+
+func TestComposeProject_RunHookStreaming_Script(t *testing.T) {
+	composeProject := createTestComposeProject()
+	composeProject.WorkingDir = t.TempDir()
+
+	outputChan := make(chan string, 100)
+	hook := Hook{Type: HookTypeScript, Command: "true"}
+	assert.NoError(t, composeProject.RunHookStreaming(context.Background(), hook, outputChan))
+
+	hook.Command = "exit 3"
+	err := composeProject.RunHookStreaming(context.Background(), hook, outputChan)
+	assert.ErrorContains(t, err, "exit status 3")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = composeProject.RunHookStreaming(ctx, Hook{Type: HookTypeScript, Command: "sleep 60"}, outputChan)
+	assert.ErrorIs(t, err, context.Canceled)
 }
 
 func TestComposeProject_CommandDown(t *testing.T) {
@@ -317,6 +462,139 @@ func TestComposeProject_ExecuteCommandStreaming_Error(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestComposeProject_UpStreaming_Cancelled(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping streaming command test in short mode")
+	}
+
+	composeProject := createTestComposeProject()
+
+	// Stand in for a Docker Compose up that waits forever on a healthcheck
+	dockerCommand := filepath.Join(t.TempDir(), "docker")
+	require.NoError(t, os.WriteFile(dockerCommand, []byte("#!/bin/sh\nexec sleep 60\n"), 0o755))
+	composeProject.Config.DockerCommand = dockerCommand
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	outputChan := make(chan string, 10)
+	start := time.Now()
+	err := composeProject.UpStreaming(ctx, outputChan)
+	close(outputChan)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(start), commandWaitDelay)
+}
+
+func TestComposeProject_UpStreaming_TimedOut(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping streaming command test in short mode")
+	}
+
+	composeProject := createTestComposeProject()
+	composeProject.Timeouts.Up = 100 * time.Millisecond
+
+	dockerCommand := filepath.Join(t.TempDir(), "docker")
+	require.NoError(t, os.WriteFile(dockerCommand, []byte("#!/bin/sh\nexec sleep 60\n"), 0o755))
+	composeProject.Config.DockerCommand = dockerCommand
+
+	outputChan := make(chan string, 10)
+	start := time.Now()
+	err := composeProject.UpStreaming(context.Background(), outputChan)
+	close(outputChan)
+
+	assert.ErrorIs(t, err, ErrComposeTimeout)
+	assert.NotErrorIs(t, err, context.Canceled)
+	assert.EqualError(t, err, "docker compose up timed out after 100ms")
+	assert.Less(t, time.Since(start), commandWaitDelay)
+}
+
+func TestComposeProject_PullStreaming_TimedOut(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping streaming command test in short mode")
+	}
+
+	composeProject := createTestComposeProject()
+	composeProject.Timeouts.Pull = 100 * time.Millisecond
+
+	dockerCommand := filepath.Join(t.TempDir(), "docker")
+	require.NoError(t, os.WriteFile(dockerCommand, []byte("#!/bin/sh\nexec sleep 60\n"), 0o755))
+	composeProject.Config.DockerCommand = dockerCommand
+
+	outputChan := make(chan string, 10)
+	err := composeProject.PullStreaming(context.Background(), outputChan)
+	close(outputChan)
+
+	assert.ErrorIs(t, err, ErrComposeTimeout)
+	assert.EqualError(t, err, "docker compose pull timed out after 100ms")
+}
+
+func TestComposeProject_UpStreaming_CancelledBeforeTimeout(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping streaming command test in short mode")
+	}
+
+	composeProject := createTestComposeProject()
+	composeProject.Timeouts.Up = time.Minute
+
+	dockerCommand := filepath.Join(t.TempDir(), "docker")
+	require.NoError(t, os.WriteFile(dockerCommand, []byte("#!/bin/sh\nexec sleep 60\n"), 0o755))
+	composeProject.Config.DockerCommand = dockerCommand
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	outputChan := make(chan string, 10)
+	err := composeProject.UpStreaming(ctx, outputChan)
+	close(outputChan)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.NotErrorIs(t, err, ErrComposeTimeout)
+}
+
+// Tests for ComposeTimeouts
+func TestComposeTimeouts_Or(t *testing.T) {
+	defaults := ComposeTimeouts{Pull: 15 * time.Minute, Up: 10 * time.Minute, Wait: 5 * time.Minute}
+
+	assert.Equal(t, defaults, ComposeTimeouts{}.Or(defaults))
+	assert.Equal(t,
+		ComposeTimeouts{Pull: 15 * time.Minute, Up: time.Hour, Wait: 5 * time.Minute},
+		ComposeTimeouts{Up: time.Hour}.Or(defaults))
+}
+
+func TestComposeTimeouts_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		timeouts ComposeTimeouts
+		wantErr  string
+	}{
+		{name: "defaults", timeouts: ComposeTimeouts{}},
+		{name: "whole seconds", timeouts: ComposeTimeouts{Pull: time.Hour, Up: 90 * time.Second, Wait: time.Second}},
+		{
+			name:     "negative",
+			timeouts: ComposeTimeouts{Up: -time.Second},
+			wantErr:  "invalid timeout: up timeout must not be negative, got: -1s",
+		},
+		{
+			name:     "fraction of a second",
+			timeouts: ComposeTimeouts{Wait: 1500 * time.Millisecond},
+			wantErr:  "invalid timeout: wait timeout must be a whole number of seconds, got: 1.5s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.timeouts.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, ErrInvalidTimeout)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
 // Tests for high-level operations (Up, Down, Logs)
 // Note: These tests use mocked Docker commands since we can't assume Docker is available
 
@@ -354,7 +632,7 @@ func TestComposeProject_EmptyProjectName(t *testing.T) {
 	composeProject.WorkingDir = tempDir
 
 	// Test
-	cmd := composeProject.prepareCommand("up", []string{})
+	cmd := composeProject.prepareCommand(context.Background(), "up", []string{})
 
 	// Assertions
 	assert.NotNil(t, cmd)
@@ -369,7 +647,7 @@ func TestComposeProject_InvalidWorkingDirectory(t *testing.T) {
 	composeProject.WorkingDir = "/non/existent/directory"
 
 	// Test
-	cmd := composeProject.prepareCommand("up", []string{})
+	cmd := composeProject.prepareCommand(context.Background(), "up", []string{})
 
 	// Assertions // prepareCommand doesn't validate directory existence
 	assert.NotNil(t, cmd)
@@ -13,8 +13,10 @@ import (
 const (
 	DataDir     = ".oar"
 	ProjectsDir = "projects"
+	CatalogsDir = "catalogs"
 	GitDir      = "git"
 	TmpDir      = "tmp"
+	BackupsDir  = "backups"
 )
 
 // EnvProvider abstracts environment variable access for testing
@@ -64,6 +66,7 @@ type Config struct {
 	DatabasePath string
 	TmpDir       string
 	WorkspaceDir string
+	BackupDir    string // Database backups
 
 	// Logging
 	LogLevel     string
@@ -73,6 +76,13 @@ type Config struct {
 	DockerHost    string
 	DockerCommand string
 
+	// Docker Compose timeouts, used for projects that don't set their own
+	ComposePullTimeout time.Duration // Pulling images
+	ComposeUpTimeout   time.Duration // Starting the project, including waiting for its services to be healthy
+	ComposeWaitTimeout time.Duration // Waiting for the services to be healthy (up --wait-timeout)
+	HealthCheckTimeout time.Duration // Waiting for the health checks of the project to pass
+	HookTimeout        time.Duration // Running a single deployment hook
+
 	// HTTP server
 	HTTPHost string
 	HTTPPort int
@@ -81,11 +91,32 @@ type Config struct {
 	GitTimeout time.Duration
 
 	// Watcher
-	PollInterval time.Duration
+	PollInterval       time.Duration
+	ImagePollInterval  time.Duration // Time between checks of the registry for new digests of watched images
+	WatcherMetricsPort int           // Port the watcher serves /metrics on (on HTTPHost), 0 disables it
+
+	// Metrics
+	MetricsToken string // Bearer token that lets Prometheus scrape /metrics without signing in, optional
+
+	// Database backups, taken by the watcher
+	BackupInterval  time.Duration // Time between backups, 0 disables scheduled backups
+	BackupRetention int           // Number of backups kept, 0 keeps all of them
+
+	// Volume backups
+	VolumeBackupImage     string // Image of the helper container that archives and restores volumes
+	VolumeBackupRetention int    // Number of volume backups kept per project, 0 keeps all of them
+
+	// Webhooks
+	WebhookSecret string // Shared secret for verifying Git webhook deliveries, webhooks are disabled if empty
 
 	// Encryption
 	EncryptionKey string
 
+	// Authentication
+	SessionTTL          time.Duration
+	SessionCookieSecure bool   // Only send the session cookie over HTTPS
+	User                string // User the CLI acts as, subject to their roles (CLI only)
+
 	// Environment provider for testing
 	env EnvProvider
 }
@@ -111,6 +142,7 @@ func newConfigForCLIWithEnv(env EnvProvider) (*Config, error) {
 
 	// Override with environment variables
 	c.loadFromEnv()
+	c.User = c.env.Getenv("OAR_USER")
 
 	// Derive dependent paths
 	c.derivePaths()
@@ -165,10 +197,22 @@ func (c *Config) setDefaults() {
 	c.ColorEnabled = true
 	c.DockerHost = "unix:///var/run/docker.sock"
 	c.DockerCommand = "docker"
+	c.ComposePullTimeout = 15 * time.Minute
+	c.ComposeUpTimeout = 10 * time.Minute
+	c.ComposeWaitTimeout = 5 * time.Minute
+	c.HealthCheckTimeout = 2 * time.Minute
+	c.HookTimeout = 10 * time.Minute
 	c.HTTPHost = "127.0.0.1"
 	c.HTTPPort = 8080
 	c.GitTimeout = 5 * time.Minute
 	c.PollInterval = 5 * time.Minute
+	c.ImagePollInterval = time.Hour
+	c.WatcherMetricsPort = 8081
+	c.BackupInterval = 24 * time.Hour
+	c.BackupRetention = 7
+	c.VolumeBackupImage = "alpine:3"
+	c.VolumeBackupRetention = 5
+	c.SessionTTL = 7 * 24 * time.Hour
 	// Don't set default encryption key - it must be provided explicitly
 }
 
@@ -197,6 +241,31 @@ func (c *Config) loadFromEnv() {
 	if v := c.env.Getenv("OAR_DOCKER_COMMAND"); v != "" {
 		c.DockerCommand = v
 	}
+	if v := c.env.Getenv("OAR_COMPOSE_PULL_TIMEOUT"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			c.ComposePullTimeout = d
+		}
+	}
+	if v := c.env.Getenv("OAR_COMPOSE_UP_TIMEOUT"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			c.ComposeUpTimeout = d
+		}
+	}
+	if v := c.env.Getenv("OAR_COMPOSE_WAIT_TIMEOUT"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			c.ComposeWaitTimeout = d
+		}
+	}
+	if v := c.env.Getenv("OAR_HEALTH_CHECK_TIMEOUT"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			c.HealthCheckTimeout = d
+		}
+	}
+	if v := c.env.Getenv("OAR_HOOK_TIMEOUT"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			c.HookTimeout = d
+		}
+	}
 	if v := c.env.Getenv("OAR_HTTP_HOST"); v != "" {
 		c.HTTPHost = v
 	}
@@ -215,9 +284,66 @@ func (c *Config) loadFromEnv() {
 			c.PollInterval = d
 		}
 	}
+	if v := c.env.Getenv("OAR_IMAGE_POLL_INTERVAL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			c.ImagePollInterval = d
+		}
+	}
+	if v := c.env.Getenv("OAR_WATCHER_METRICS_PORT"); v != "" {
+		if port, err := strconv.Atoi(v); err == nil {
+			c.WatcherMetricsPort = port
+		}
+	}
+	if v := c.env.Getenv("OAR_BACKUP_DIR"); v != "" {
+		c.BackupDir = v
+	}
+	if v := c.env.Getenv("OAR_BACKUP_INTERVAL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			c.BackupInterval = d
+		}
+	}
+	if v := c.env.Getenv("OAR_BACKUP_RETENTION"); v != "" {
+		if retention, err := strconv.Atoi(v); err == nil {
+			c.BackupRetention = retention
+		}
+	}
+	if v := c.env.Getenv("OAR_VOLUME_BACKUP_IMAGE"); v != "" {
+		c.VolumeBackupImage = v
+	}
+	if v := c.env.Getenv("OAR_VOLUME_BACKUP_RETENTION"); v != "" {
+		if retention, err := strconv.Atoi(v); err == nil {
+			c.VolumeBackupRetention = retention
+		}
+	}
 	if v := c.env.Getenv("OAR_ENCRYPTION_KEY"); v != "" {
 		c.EncryptionKey = v
 	}
+	if v := c.env.Getenv("OAR_WEBHOOK_SECRET"); v != "" {
+		c.WebhookSecret = v
+	}
+	if v := c.env.Getenv("OAR_METRICS_TOKEN"); v != "" {
+		c.MetricsToken = v
+	}
+	if v := c.env.Getenv("OAR_SESSION_TTL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			c.SessionTTL = d
+		}
+	}
+	if v := c.env.Getenv("OAR_SESSION_COOKIE_SECURE"); v != "" {
+		if secure, err := strconv.ParseBool(v); err == nil {
+			c.SessionCookieSecure = secure
+		}
+	}
+}
+
+// ComposeTimeouts returns the Docker Compose timeouts of projects that don't set their own
+func (c *Config) ComposeTimeouts() ComposeTimeouts {
+	return ComposeTimeouts{
+		Pull:   c.ComposePullTimeout,
+		Up:     c.ComposeUpTimeout,
+		Wait:   c.ComposeWaitTimeout,
+		Health: c.HealthCheckTimeout,
+	}
 }
 
 // readEncryptionKeyFromEnvFile attempts to read OAR_ENCRYPTION_KEY from .env file in installation directory
@@ -242,6 +368,9 @@ func (c *Config) derivePaths() {
 	if c.DatabasePath == "" {
 		c.DatabasePath = filepath.Join(c.DataDir, "oar.db")
 	}
+	if c.BackupDir == "" {
+		c.BackupDir = filepath.Join(c.DataDir, BackupsDir)
+	}
 }
 
 // validate ensures configuration values are valid
@@ -259,15 +388,56 @@ func (c *Config) validate() error {
 		return fmt.Errorf("invalid HTTP port: %d (must be 1-65535)", c.HTTPPort)
 	}
 
+	// Validate watcher metrics port
+	if c.WatcherMetricsPort < 0 || c.WatcherMetricsPort > 65535 {
+		return fmt.Errorf("invalid watcher metrics port: %d (must be 0-65535)", c.WatcherMetricsPort)
+	}
+
 	// Validate timeout
 	if c.GitTimeout <= 0 {
 		return fmt.Errorf("git timeout must be positive, got: %v", c.GitTimeout)
 	}
 
+	// Validate Docker Compose timeouts
+	if c.ComposePullTimeout <= 0 {
+		return fmt.Errorf("compose pull timeout must be positive, got: %v", c.ComposePullTimeout)
+	}
+	if c.ComposeUpTimeout <= 0 {
+		return fmt.Errorf("compose up timeout must be positive, got: %v", c.ComposeUpTimeout)
+	}
+	if c.ComposeWaitTimeout <= 0 {
+		return fmt.Errorf("compose wait timeout must be positive, got: %v", c.ComposeWaitTimeout)
+	}
+	if c.HealthCheckTimeout <= 0 {
+		return fmt.Errorf("health check timeout must be positive, got: %v", c.HealthCheckTimeout)
+	}
+	if c.HookTimeout <= 0 {
+		return fmt.Errorf("hook timeout must be positive, got: %v", c.HookTimeout)
+	}
+
 	// Validate poll interval
 	if c.PollInterval <= 0 {
 		return fmt.Errorf("poll interval must be positive, got: %v", c.PollInterval)
 	}
+	if c.ImagePollInterval <= 0 {
+		return fmt.Errorf("image poll interval must be positive, got: %v", c.ImagePollInterval)
+	}
+
+	// Validate backups
+	if c.BackupInterval < 0 {
+		return fmt.Errorf("backup interval must not be negative, got: %v", c.BackupInterval)
+	}
+	if c.BackupRetention < 0 {
+		return fmt.Errorf("backup retention must not be negative, got: %d", c.BackupRetention)
+	}
+	if c.VolumeBackupRetention < 0 {
+		return fmt.Errorf("volume backup retention must not be negative, got: %d", c.VolumeBackupRetention)
+	}
+
+	// Validate session TTL
+	if c.SessionTTL <= 0 {
+		return fmt.Errorf("session TTL must be positive, got: %v", c.SessionTTL)
+	}
 
 	// Validate Docker command is not empty
 	if c.DockerCommand == "" {
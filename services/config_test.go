@@ -89,16 +89,74 @@ func TestNewConfigForWebApp(t *testing.T) {
 	if config.PollInterval != 5*time.Minute {
 		t.Errorf("NewConfigForWebApp() PollInterval = %v, want 5m", config.PollInterval)
 	}
+	if config.ImagePollInterval != time.Hour {
+		t.Errorf("NewConfigForWebApp() ImagePollInterval = %v, want 1h", config.ImagePollInterval)
+	}
+	if config.WatcherMetricsPort != 8081 {
+		t.Errorf("NewConfigForWebApp() WatcherMetricsPort = %v, want 8081", config.WatcherMetricsPort)
+	}
+	if expectedBackupDir := filepath.Join(expectedDataDir, "backups"); config.BackupDir != expectedBackupDir {
+		t.Errorf("NewConfigForWebApp() BackupDir = %v, want %v", config.BackupDir, expectedBackupDir)
+	}
+	if config.BackupInterval != 24*time.Hour {
+		t.Errorf("NewConfigForWebApp() BackupInterval = %v, want 24h", config.BackupInterval)
+	}
+	if config.BackupRetention != 7 {
+		t.Errorf("NewConfigForWebApp() BackupRetention = %v, want 7", config.BackupRetention)
+	}
+	if config.VolumeBackupImage != "alpine:3" {
+		t.Errorf("NewConfigForWebApp() VolumeBackupImage = %v, want alpine:3", config.VolumeBackupImage)
+	}
+	if config.VolumeBackupRetention != 5 {
+		t.Errorf("NewConfigForWebApp() VolumeBackupRetention = %v, want 5", config.VolumeBackupRetention)
+	}
+	if config.SessionTTL != 7*24*time.Hour {
+		t.Errorf("NewConfigForWebApp() SessionTTL = %v, want 168h", config.SessionTTL)
+	}
+	if config.SessionCookieSecure {
+		t.Errorf("NewConfigForWebApp() SessionCookieSecure = true, want false")
+	}
+	if config.ComposePullTimeout != 15*time.Minute {
+		t.Errorf("NewConfigForWebApp() ComposePullTimeout = %v, want 15m", config.ComposePullTimeout)
+	}
+	if config.ComposeUpTimeout != 10*time.Minute {
+		t.Errorf("NewConfigForWebApp() ComposeUpTimeout = %v, want 10m", config.ComposeUpTimeout)
+	}
+	if config.ComposeWaitTimeout != 5*time.Minute {
+		t.Errorf("NewConfigForWebApp() ComposeWaitTimeout = %v, want 5m", config.ComposeWaitTimeout)
+	}
+	if config.HealthCheckTimeout != 2*time.Minute {
+		t.Errorf("NewConfigForWebApp() HealthCheckTimeout = %v, want 2m", config.HealthCheckTimeout)
+	}
+	if config.HookTimeout != 10*time.Minute {
+		t.Errorf("NewConfigForWebApp() HookTimeout = %v, want 10m", config.HookTimeout)
+	}
 }
 
 func TestNewConfigForWebApp_WithEnvVars(t *testing.T) {
 	// Use mock environment with custom values
 	envVars := map[string]string{
-		"OAR_HTTP_PORT":      "3000",
-		"OAR_HTTP_HOST":      "0.0.0.0",
-		"OAR_POLL_INTERVAL":  "2m",
-		"XDG_DATA_HOME":      "/custom/data",
-		"OAR_ENCRYPTION_KEY": generateTestKey(), // Required for config validation
+		"OAR_HTTP_PORT":               "3000",
+		"OAR_HTTP_HOST":               "0.0.0.0",
+		"OAR_POLL_INTERVAL":           "2m",
+		"OAR_IMAGE_POLL_INTERVAL":     "30m",
+		"OAR_SESSION_TTL":             "12h",
+		"OAR_SESSION_COOKIE_SECURE":   "true",
+		"OAR_WEBHOOK_SECRET":          "s3cret",
+		"OAR_METRICS_TOKEN":           "scrape-token",
+		"OAR_WATCHER_METRICS_PORT":    "0",
+		"OAR_COMPOSE_PULL_TIMEOUT":    "30m",
+		"OAR_COMPOSE_UP_TIMEOUT":      "20m",
+		"OAR_COMPOSE_WAIT_TIMEOUT":    "90s",
+		"OAR_HEALTH_CHECK_TIMEOUT":    "45s",
+		"OAR_HOOK_TIMEOUT":            "3m",
+		"OAR_BACKUP_DIR":              "/backups",
+		"OAR_BACKUP_INTERVAL":         "6h",
+		"OAR_BACKUP_RETENTION":        "0",
+		"OAR_VOLUME_BACKUP_IMAGE":     "busybox",
+		"OAR_VOLUME_BACKUP_RETENTION": "2",
+		"XDG_DATA_HOME":               "/custom/data",
+		"OAR_ENCRYPTION_KEY":          generateTestKey(), // Required for config validation
 	}
 	mockEnv := NewMockEnvProvider("/home/testuser", envVars)
 	config, err := NewConfigForWebAppWithEnv(mockEnv)
@@ -119,6 +177,77 @@ func TestNewConfigForWebApp_WithEnvVars(t *testing.T) {
 	if config.PollInterval != 2*time.Minute {
 		t.Errorf("NewConfigForWebApp() PollInterval = %v, want 2m", config.PollInterval)
 	}
+	if config.ImagePollInterval != 30*time.Minute {
+		t.Errorf("NewConfigForWebApp() ImagePollInterval = %v, want 30m", config.ImagePollInterval)
+	}
+	if config.SessionTTL != 12*time.Hour {
+		t.Errorf("NewConfigForWebApp() SessionTTL = %v, want 12h", config.SessionTTL)
+	}
+	if !config.SessionCookieSecure {
+		t.Errorf("NewConfigForWebApp() SessionCookieSecure = false, want true")
+	}
+	if config.WebhookSecret != "s3cret" {
+		t.Errorf("NewConfigForWebApp() WebhookSecret = %q, want s3cret", config.WebhookSecret)
+	}
+	if config.WatcherMetricsPort != 0 {
+		t.Errorf("NewConfigForWebApp() WatcherMetricsPort = %v, want 0", config.WatcherMetricsPort)
+	}
+	if config.MetricsToken != "scrape-token" {
+		t.Errorf("NewConfigForWebApp() MetricsToken = %q, want scrape-token", config.MetricsToken)
+	}
+	if config.ComposePullTimeout != 30*time.Minute {
+		t.Errorf("NewConfigForWebApp() ComposePullTimeout = %v, want 30m", config.ComposePullTimeout)
+	}
+	if config.ComposeUpTimeout != 20*time.Minute {
+		t.Errorf("NewConfigForWebApp() ComposeUpTimeout = %v, want 20m", config.ComposeUpTimeout)
+	}
+	if config.ComposeWaitTimeout != 90*time.Second {
+		t.Errorf("NewConfigForWebApp() ComposeWaitTimeout = %v, want 90s", config.ComposeWaitTimeout)
+	}
+	if config.HealthCheckTimeout != 45*time.Second {
+		t.Errorf("NewConfigForWebApp() HealthCheckTimeout = %v, want 45s", config.HealthCheckTimeout)
+	}
+	if config.HookTimeout != 3*time.Minute {
+		t.Errorf("NewConfigForWebApp() HookTimeout = %v, want 3m", config.HookTimeout)
+	}
+	if config.BackupDir != "/backups" {
+		t.Errorf("NewConfigForWebApp() BackupDir = %v, want /backups", config.BackupDir)
+	}
+	if config.BackupInterval != 6*time.Hour {
+		t.Errorf("NewConfigForWebApp() BackupInterval = %v, want 6h", config.BackupInterval)
+	}
+	if config.BackupRetention != 0 {
+		t.Errorf("NewConfigForWebApp() BackupRetention = %v, want 0", config.BackupRetention)
+	}
+	if config.VolumeBackupImage != "busybox" {
+		t.Errorf("NewConfigForWebApp() VolumeBackupImage = %v, want busybox", config.VolumeBackupImage)
+	}
+	if config.VolumeBackupRetention != 2 {
+		t.Errorf("NewConfigForWebApp() VolumeBackupRetention = %v, want 2", config.VolumeBackupRetention)
+	}
+}
+
+func TestConfig_UserOnlyForCLI(t *testing.T) {
+	mockEnv := NewMockEnvProvider("/home/testuser", map[string]string{
+		"OAR_USER":           "alice",
+		"OAR_ENCRYPTION_KEY": generateTestKey(), // Required for config validation
+	})
+
+	cliConfig, err := NewConfigForCLIWithEnv(mockEnv)
+	if err != nil {
+		t.Fatalf("NewConfigForCLI() error = %v", err)
+	}
+	if cliConfig.User != "alice" {
+		t.Errorf("NewConfigForCLI() User = %q, want alice", cliConfig.User)
+	}
+
+	webConfig, err := NewConfigForWebAppWithEnv(mockEnv)
+	if err != nil {
+		t.Fatalf("NewConfigForWebApp() error = %v", err)
+	}
+	if webConfig.User != "" {
+		t.Errorf("NewConfigForWebApp() User = %q, want empty", webConfig.User)
+	}
 }
 
 func TestConfig_RequiresEncryptionKey(t *testing.T) {
@@ -9,6 +9,9 @@ const (
 	DeploymentStatusCompleted
 	DeploymentStatusFailed
 	DeploymentStatusUnknown
+	DeploymentStatusCancelled
+	DeploymentStatusPending  // Awaiting approval, see RequestApproval
+	DeploymentStatusRejected // Rejected or superseded while pending, never deployed
 )
 
 func (s DeploymentStatus) String() string {
@@ -21,6 +24,12 @@ func (s DeploymentStatus) String() string {
 		return "failed"
 	case DeploymentStatusUnknown:
 		return "unknown"
+	case DeploymentStatusCancelled:
+		return "cancelled"
+	case DeploymentStatusPending:
+		return "pending"
+	case DeploymentStatusRejected:
+		return "rejected"
 	default:
 		return "unknown"
 	}
@@ -36,7 +45,86 @@ func ParseDeploymentStatus(s string) (DeploymentStatus, error) {
 		return DeploymentStatusFailed, nil
 	case "unknown":
 		return DeploymentStatusUnknown, nil
+	case "cancelled":
+		return DeploymentStatusCancelled, nil
+	case "pending":
+		return DeploymentStatusPending, nil
+	case "rejected":
+		return DeploymentStatusRejected, nil
 	default:
 		return DeploymentStatusUnknown, fmt.Errorf("invalid deployment status: %q", s)
 	}
 }
+
+// DeploymentTrigger is what started a deployment
+type DeploymentTrigger int
+
+const (
+	DeploymentTriggerUnknown DeploymentTrigger = iota
+	DeploymentTriggerWeb
+	DeploymentTriggerCLI
+	DeploymentTriggerAPI
+	DeploymentTriggerWatcher
+	DeploymentTriggerWebhook
+	DeploymentTriggerRollback
+	DeploymentTriggerAutoRollback
+	DeploymentTriggerCatalog
+	DeploymentTriggerReconcile
+	DeploymentTriggerImage
+)
+
+func (t DeploymentTrigger) String() string {
+	switch t {
+	case DeploymentTriggerWeb:
+		return "web"
+	case DeploymentTriggerCLI:
+		return "cli"
+	case DeploymentTriggerAPI:
+		return "api"
+	case DeploymentTriggerWatcher:
+		return "watcher"
+	case DeploymentTriggerWebhook:
+		return "webhook"
+	case DeploymentTriggerRollback:
+		return "rollback"
+	case DeploymentTriggerAutoRollback:
+		return "auto_rollback"
+	case DeploymentTriggerCatalog:
+		return "catalog"
+	case DeploymentTriggerReconcile:
+		return "reconcile"
+	case DeploymentTriggerImage:
+		return "image"
+	default:
+		return "unknown"
+	}
+}
+
+func ParseDeploymentTrigger(s string) (DeploymentTrigger, error) {
+	switch s {
+	case "web":
+		return DeploymentTriggerWeb, nil
+	case "cli":
+		return DeploymentTriggerCLI, nil
+	case "api":
+		return DeploymentTriggerAPI, nil
+	case "watcher":
+		return DeploymentTriggerWatcher, nil
+	case "webhook":
+		return DeploymentTriggerWebhook, nil
+	case "rollback":
+		return DeploymentTriggerRollback, nil
+	case "auto_rollback":
+		return DeploymentTriggerAutoRollback, nil
+	case "catalog":
+		return DeploymentTriggerCatalog, nil
+	case "reconcile":
+		return DeploymentTriggerReconcile, nil
+	case "image":
+		return DeploymentTriggerImage, nil
+	case "unknown":
+		return DeploymentTriggerUnknown, nil
+	default:
+		return DeploymentTriggerUnknown, fmt.Errorf("invalid deployment trigger: %q", s)
+	}
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// deploymentLockTTL is how long a deployment lock is valid without being renewed. Locks are renewed while the
+// deployment runs, so the lock of a process that died goes stale after this long.
+const deploymentLockTTL = 2 * time.Minute
+
+// deploymentLockMaxAge is how long a deployment lock is renewed for at most. A deployment that hangs past it, longer
+// than any deployment takes with the default timeouts, no longer blocks other deployments once its lock expires.
+const deploymentLockMaxAge = 2 * time.Hour
+
+// deploymentCancelCheckInterval is how often a deployment checks its lock for a cancellation requested by another
+// process
+const deploymentCancelCheckInterval = 2 * time.Second
+
+// ErrDeploymentInProgress is returned when deploying a project that the web app, the watcher or the CLI is
+// already deploying
+var ErrDeploymentInProgress = errors.New("deployment already in progress")
+
+// LockProject acquires the deployment lock of the project for an operation that must not run alongside a
+// deployment, and returns the function that releases it
+func (s *ProjectService) LockProject(projectID uuid.UUID, options DeployOptions) (func(), error) {
+	return s.lockProject(projectID, options)
+}
+
+// lockProject acquires the deployment lock of the project and keeps renewing it until the returned function
+// releases it
+func (s *ProjectService) lockProject(projectID uuid.UUID, options DeployOptions) (func(), error) {
+	if s.lockRepository == nil {
+		return func() {}, nil
+	}
+
+	now := time.Now()
+	lock := &DeploymentLock{
+		ID:        uuid.New(),
+		ProjectID: projectID,
+		Holder:    lockHolder(),
+		Trigger:   options.Trigger,
+		Actor:     options.Actor,
+		ExpiresAt: now.Add(deploymentLockTTL),
+	}
+
+	acquired, err := s.lockRepository.Acquire(lock, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire deployment lock: %w", err)
+	}
+	if !acquired {
+		return nil, s.deploymentInProgressError(projectID)
+	}
+
+	slog.Debug("Deployment lock acquired", "project_id", projectID, "lock_id", lock.ID, "holder", lock.Holder)
+
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		s.renewLock(lock, now.Add(deploymentLockMaxAge), stop)
+	}()
+
+	return func() {
+		close(stop)
+		<-stopped
+		if err := s.lockRepository.Release(lock.ID); err != nil {
+			slog.Error("Service operation failed",
+				"layer", "service",
+				"operation", "release_deployment_lock",
+				"project_id", projectID,
+				"lock_id", lock.ID,
+				"error", err)
+			return
+		}
+		slog.Debug("Deployment lock released", "project_id", projectID, "lock_id", lock.ID)
+	}, nil
+}
+
+// renewLock renews lock until stop is closed, or until it can't be renewed past maxExpiresAt. Meanwhile, it
+// cancels the deployment when another process requests it.
+func (s *ProjectService) renewLock(lock *DeploymentLock, maxExpiresAt time.Time, stop <-chan struct{}) {
+	ticker := time.NewTicker(deploymentLockTTL / 4)
+	defer ticker.Stop()
+	cancelCheck := time.NewTicker(deploymentCancelCheckInterval)
+	defer cancelCheck.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-cancelCheck.C:
+			if s.cancelRequested(lock) && s.cancelRunning(lock.ProjectID) {
+				cancelCheck.Stop()
+			}
+		case <-ticker.C:
+			expiresAt, last := lockExpiry(time.Now(), maxExpiresAt)
+			renewed, err := s.lockRepository.Renew(lock.ID, expiresAt)
+			switch {
+			case err != nil:
+				slog.Error("Service operation failed",
+					"layer", "service",
+					"operation", "renew_deployment_lock",
+					"project_id", lock.ProjectID,
+					"lock_id", lock.ID,
+					"error", err)
+			case !renewed:
+				slog.Warn("Deployment lock was taken over after it expired",
+					"project_id", lock.ProjectID,
+					"lock_id", lock.ID)
+				return
+			case last:
+				slog.Warn("Deployment lock is no longer renewed, the deployment is taking too long",
+					"project_id", lock.ProjectID,
+					"lock_id", lock.ID,
+					"expires_at", expiresAt)
+				return
+			}
+		}
+	}
+}
+
+// cancelRequested reports whether another process requested the cancellation of the deployment holding lock
+func (s *ProjectService) cancelRequested(lock *DeploymentLock) bool {
+	held, err := s.lockRepository.FindByProjectID(lock.ProjectID)
+	if err != nil || held.ID != lock.ID {
+		return false
+	}
+	return held.CancelRequested
+}
+
+// lockExpiry returns when a lock renewed at now expires, and whether it can't be renewed any further because it
+// reached maxExpiresAt
+func lockExpiry(now, maxExpiresAt time.Time) (time.Time, bool) {
+	expiresAt := now.Add(deploymentLockTTL)
+	if !expiresAt.Before(maxExpiresAt) {
+		return maxExpiresAt, true
+	}
+	return expiresAt, false
+}
+
+// deploymentInProgressError describes the deployment holding the lock of the project
+func (s *ProjectService) deploymentInProgressError(projectID uuid.UUID) error {
+	lock, err := s.lockRepository.FindByProjectID(projectID)
+	if err != nil {
+		// Released in the meantime
+		return ErrDeploymentInProgress
+	}
+
+	startedBy := lock.Trigger.String()
+	if lock.Actor != "" {
+		startedBy += " (" + lock.Actor + ")"
+	}
+	return fmt.Errorf("%w: started by %s on %s at %s",
+		ErrDeploymentInProgress, startedBy, lock.Holder, lock.CreatedAt.Format("2006-01-02 15:04:05"))
+}
+
+// lockHolder identifies this process in deployment locks
+func lockHolder() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s (pid %d)", hostname, os.Getpid())
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupLockingProjectService creates a mocked project service whose deployment locks are stored in a database
+// with the project
+func setupLockingProjectService(t *testing.T) (*ProjectService, *Project, DeploymentLockRepository) {
+	service, repo, _, gitService, _ := setupMockProjectService(t)
+	gitService.GetLatestCommitFunc = func(workingDir string) (string, error) {
+		return "abc123def456789012345678901234567890abcd", nil
+	}
+
+	db := setupTestDB(t)
+	project, err := NewProjectRepository(db, setupTestEncryption(t)).Create(createTestProject())
+	require.NoError(t, err)
+	repo.projects[project.ID] = project
+
+	locks := NewDeploymentLockRepository(db)
+	service.lockRepository = locks
+	return service, project, locks
+}
+
+func TestProjectService_LockProject(t *testing.T) {
+	service, project, locks := setupLockingProjectService(t)
+
+	unlock, err := service.lockProject(project.ID, DeployOptions{Trigger: DeploymentTriggerWeb, Actor: "alice"})
+	require.NoError(t, err)
+
+	lock, err := locks.FindByProjectID(project.ID)
+	require.NoError(t, err)
+	assert.Equal(t, lockHolder(), lock.Holder)
+	assert.Equal(t, DeploymentTriggerWeb, lock.Trigger)
+	assert.Equal(t, "alice", lock.Actor)
+	assert.WithinDuration(t, time.Now().Add(deploymentLockTTL), lock.ExpiresAt, time.Minute)
+
+	_, err = service.lockProject(project.ID, DeployOptions{Trigger: DeploymentTriggerWatcher})
+	assert.ErrorIs(t, err, ErrDeploymentInProgress)
+	assert.ErrorContains(t, err, "started by web (alice) on "+lockHolder())
+
+	unlock()
+	_, err = locks.FindByProjectID(project.ID)
+	assert.True(t, IsNotFound(err))
+
+	unlock, err = service.lockProject(project.ID, DeployOptions{Trigger: DeploymentTriggerWatcher})
+	require.NoError(t, err)
+	unlock()
+}
+
+func TestProjectService_LockProject_StaleLock(t *testing.T) {
+	service, project, locks := setupLockingProjectService(t)
+
+	// Left behind by a process that died
+	stale := &DeploymentLock{
+		ID:        project.ID,
+		ProjectID: project.ID,
+		Holder:    "crashed (pid 1)",
+		ExpiresAt: time.Now().Add(-time.Second),
+	}
+	acquired, err := locks.Acquire(stale, time.Now().Add(-deploymentLockTTL))
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	unlock, err := service.lockProject(project.ID, DeployOptions{Trigger: DeploymentTriggerCLI})
+	require.NoError(t, err)
+	defer unlock()
+
+	lock, err := locks.FindByProjectID(project.ID)
+	require.NoError(t, err)
+	assert.Equal(t, lockHolder(), lock.Holder)
+}
+
+func TestLockExpiry(t *testing.T) {
+	now := time.Now()
+
+	expiresAt, last := lockExpiry(now, now.Add(deploymentLockMaxAge))
+	assert.Equal(t, now.Add(deploymentLockTTL), expiresAt)
+	assert.False(t, last)
+
+	// Not renewed past the maximum age
+	maxExpiresAt := now.Add(deploymentLockTTL / 2)
+	expiresAt, last = lockExpiry(now, maxExpiresAt)
+	assert.Equal(t, maxExpiresAt, expiresAt)
+	assert.True(t, last)
+}
+
+func TestProjectService_DeployStreaming_Locked(t *testing.T) {
+	service, project, locks := setupLockingProjectService(t)
+
+	held := &DeploymentLock{
+		ID:        project.ID,
+		ProjectID: project.ID,
+		Holder:    "watcher (pid 1)",
+		Trigger:   DeploymentTriggerWatcher,
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+	acquired, err := locks.Acquire(held, time.Now())
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	outputChan := make(chan string, 100)
+	err = service.DeployStreaming(context.Background(), project.ID, DeployOptions{}, outputChan)
+	assert.ErrorIs(t, err, ErrDeploymentInProgress)
+	assert.ErrorContains(t, err, "started by watcher on watcher (pid 1)")
+
+	err = service.RollbackStreaming(context.Background(), project.ID, "", DeployOptions{}, outputChan)
+	assert.ErrorIs(t, err, ErrDeploymentInProgress)
+	close(outputChan)
+
+	// The lock of the running deployment is left alone
+	lock, err := locks.FindByProjectID(project.ID)
+	require.NoError(t, err)
+	assert.Equal(t, held.ID, lock.ID)
+}
+
+func TestProjectService_DeployStreaming_ReleasesLock(t *testing.T) {
+	service, project, locks := setupLockingProjectService(t)
+
+	// Docker Compose is not available, so the deployment fails
+	outputChan := make(chan string, 100)
+	err := service.DeployStreaming(context.Background(), project.ID, DeployOptions{}, outputChan)
+	close(outputChan)
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrDeploymentInProgress)
+
+	_, err = locks.FindByProjectID(project.ID)
+	assert.True(t, IsNotFound(err))
+}
+
+func TestProjectService_CancelDeployment_OtherProcess(t *testing.T) {
+	service, project, locks := setupLockingProjectService(t)
+
+	held := &DeploymentLock{
+		ID:        uuid.New(),
+		ProjectID: project.ID,
+		Holder:    "watcher (pid 1)",
+		Trigger:   DeploymentTriggerWatcher,
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+	acquired, err := locks.Acquire(held, time.Now())
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	// The process holding the lock is asked to cancel
+	require.NoError(t, service.CancelDeployment(project.ID))
+	lock, err := locks.FindByProjectID(project.ID)
+	require.NoError(t, err)
+	assert.True(t, lock.CancelRequested)
+
+	require.NoError(t, locks.Release(held.ID))
+	assert.ErrorIs(t, service.CancelDeployment(project.ID), ErrNoDeploymentInProgress)
+}
+
+func TestProjectService_LockProject_CancelRequested(t *testing.T) {
+	service, project, locks := setupLockingProjectService(t)
+
+	unlock, err := service.lockProject(project.ID, DeployOptions{Trigger: DeploymentTriggerWatcher})
+	require.NoError(t, err)
+	defer unlock()
+	ctx, done := service.startCancellable(context.Background(), project.ID)
+	defer done()
+
+	// Requested by another process
+	requested, err := locks.RequestCancel(project.ID, time.Now())
+	require.NoError(t, err)
+	require.True(t, requested)
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * deploymentCancelCheckInterval):
+		t.Fatal("deployment was not cancelled")
+	}
+}
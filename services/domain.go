@@ -1,27 +1,93 @@
 package services
 
 import (
+	"errors"
 	"fmt"
 	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 type Project struct {
-	ID             uuid.UUID
-	Name           string
-	GitURL         string
-	GitBranch      string         // Git branch to use (never empty, always set to default branch if not specified)
-	GitAuth        *GitAuthConfig // Git authentication configuration
-	WorkingDir     string
-	ComposeFiles   []string
-	Variables      []string // Variables in .env format, one per string
-	Status         ProjectStatus
-	LastCommit     *string
-	WatcherEnabled bool // Enable automatic deployments on git changes
-	CreatedAt      time.Time
-	UpdatedAt      time.Time
+	ID               uuid.UUID
+	Name             string
+	GitURL           string
+	GitBranch        string         // Git branch to use (never empty, always set to default branch if not specified)
+	GitRefType       GitRefType     // What to deploy: the head of GitBranch, a tag or a pinned commit
+	GitRef           string         // Tag pattern or semver constraint, or commit SHA, depending on GitRefType
+	GitAuth          *GitAuthConfig // Git authentication configuration
+	WorkingDir       string
+	ComposeFiles     []string
+	Variables        []string      // Variables in .env format, one per string
+	Status           ProjectStatus // Outcome of the last deployment or stop, see DesiredState for what is intended
+	DesiredState     DesiredState  // Whether the project is meant to run, the watcher brings it back up if it is
+	LastCommit       *string
+	RolledBackCommit *string         // Commit the project was rolled back from, skipped by automatic deployments
+	WatcherEnabled   bool            // Enable automatic deployments on git changes
+	RequireApproval  bool            // Changes the watcher or webhooks detect wait for approval, see RequestApproval
+	WatchImages      bool            // Redeploy when the registry has new digests of the images, see CheckImages
+	AutoRollback     bool            // Roll back to the last successful deployment when a deployment fails
+	BackupVolumes    bool            // Back up the named volumes of the project before each deployment
+	HealthChecks     []HealthCheck   // Checked after Docker Compose has started the project
+	PreDeployHooks   []Hook          // Run before Docker Compose deploys the project
+	PostDeployHooks  []Hook          // Run after the project has started and passed its health checks
+	Timeouts         ComposeTimeouts // Deployment timeouts, zero ones use the defaults of Config
+	CatalogID        *uuid.UUID      // Catalog the project is managed by, nil for projects added by hand
+	Drift            []string        // Differences between the deployed commit and the containers, see DetectDrift
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// ErrInvalidTimeout is returned for Docker Compose timeouts that can't be used
+var ErrInvalidTimeout = errors.New("invalid timeout")
+
+// ComposeTimeouts limits how long Docker Compose may take to deploy a project, and how long its health checks
+// may take to pass afterwards. Zero durations use the defaults of Config.
+type ComposeTimeouts struct {
+	Pull   time.Duration // Pulling images
+	Up     time.Duration // Starting the project, including waiting for its services to be healthy
+	Wait   time.Duration // Waiting for the services to be healthy
+	Health time.Duration // Waiting for the health checks of the project to pass
+}
+
+// Or returns the timeouts, with the zero ones replaced by those of defaults
+func (t ComposeTimeouts) Or(defaults ComposeTimeouts) ComposeTimeouts {
+	if t.Pull == 0 {
+		t.Pull = defaults.Pull
+	}
+	if t.Up == 0 {
+		t.Up = defaults.Up
+	}
+	if t.Wait == 0 {
+		t.Wait = defaults.Wait
+	}
+	if t.Health == 0 {
+		t.Health = defaults.Health
+	}
+	return t
+}
+
+// Validate checks that none of the timeouts is negative or has a fraction of a second
+func (t ComposeTimeouts) Validate() error {
+	timeouts := []struct {
+		name     string
+		duration time.Duration
+	}{{"pull", t.Pull}, {"up", t.Up}, {"wait", t.Wait}, {"health", t.Health}}
+
+	for _, timeout := range timeouts {
+		if timeout.duration < 0 {
+			return fmt.Errorf("%w: %s timeout must not be negative, got: %v",
+				ErrInvalidTimeout, timeout.name, timeout.duration)
+		}
+		if timeout.duration%time.Second != 0 {
+			return fmt.Errorf("%w: %s timeout must be a whole number of seconds, got: %v",
+				ErrInvalidTimeout, timeout.name, timeout.duration)
+		}
+	}
+	return nil
 }
 
 func (p *Project) GitDir() (string, error) {
@@ -38,6 +104,13 @@ func (p *Project) LastCommitStr() string {
 	return *p.LastCommit
 }
 
+func (p *Project) RolledBackCommitStr() string {
+	if p.RolledBackCommit == nil {
+		return ""
+	}
+	return *p.RolledBackCommit
+}
+
 func NewProject(name, gitURL string, composeFiles []string, variables []string) Project {
 	return Project{
 		ID:             uuid.New(),
@@ -47,18 +120,49 @@ func NewProject(name, gitURL string, composeFiles []string, variables []string)
 		ComposeFiles:   composeFiles,
 		Variables:      variables,
 		Status:         ProjectStatusStopped,
+		DesiredState:   DesiredStateStopped,
 		WatcherEnabled: true, // Default to enabled
 	}
 }
 
 type Deployment struct {
-	ID         uuid.UUID
-	ProjectID  uuid.UUID
-	CommitHash string
-	Status     DeploymentStatus
-	Output     string
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
+	ID             uuid.UUID
+	ProjectID      uuid.UUID
+	CommitHash     string
+	PreviousCommit string // Commit deployed before this deployment, empty for the first one
+	Status         DeploymentStatus
+	Output         string
+	Rollback       bool                // Whether the deployment rolled back to an earlier commit
+	Pull           bool                // Whether the latest changes were pulled from Git before deploying
+	Trigger        DeploymentTrigger   // What started the deployment
+	Actor          string              // Who started the deployment, empty if unknown
+	HealthChecks   []HealthCheckResult // Outcome of the health checks of the project, empty if none ran
+	StartedAt      time.Time
+	FinishedAt     *time.Time // Nil while the deployment runs
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// Duration returns how long the deployment ran, or zero if it has not finished
+func (d *Deployment) Duration() time.Duration {
+	if d.FinishedAt == nil || d.StartedAt.IsZero() {
+		return 0
+	}
+	return d.FinishedAt.Sub(d.StartedAt)
+}
+
+// Deployed reports whether the deployment ran, unlike deployments pending approval or rejected
+func (d *Deployment) Deployed() bool {
+	return d.Status != DeploymentStatusPending && d.Status != DeploymentStatusRejected
+}
+
+// DeployOptions describe how and why a deployment is started
+type DeployOptions struct {
+	Pull       bool              // Pull the latest changes from Git before deploying
+	PullImages bool              // Pull the images of the project before starting it, not only those it is missing
+	Commit     string            // Commit to check out and deploy instead of the checked out one, without pulling
+	Trigger    DeploymentTrigger // What starts the deployment
+	Actor      string            // Who starts the deployment, e.g. a username
 }
 
 func NewDeployment(projectID uuid.UUID, commitHash string) Deployment {
@@ -69,7 +173,141 @@ func NewDeployment(projectID uuid.UUID, commitHash string) Deployment {
 	}
 }
 
+// DeploymentLock is held on a project by the process deploying it, so that the web app, the watcher and
+// the CLI never deploy a project at the same time
+type DeploymentLock struct {
+	ID        uuid.UUID
+	ProjectID uuid.UUID
+	Holder    string // Host and process of the deployment
+	Trigger   DeploymentTrigger
+	Actor     string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	CancelRequested bool // Another process asked the one deploying to cancel the deployment
+}
+
+func (l *DeploymentLock) Expired(now time.Time) bool {
+	return !now.Before(l.ExpiresAt)
+}
+
 type DeploymentResult struct {
 	Status DeploymentStatus
 	Output string
 }
+
+type User struct {
+	ID           uuid.UUID
+	Username     string
+	PasswordHash string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+type Session struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	TokenHash string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (s *Session) Expired(now time.Time) bool {
+	return !now.Before(s.ExpiresAt)
+}
+
+// RoleBinding grants a user a role, either globally (ProjectID is nil) or for a single project
+type RoleBinding struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	ProjectID *uuid.UUID
+	Role      Role
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (b *RoleBinding) IsGlobal() bool {
+	return b.ProjectID == nil
+}
+
+// Notifier sends notifications about a project's deployments to a chat, email or push service
+type Notifier struct {
+	ID        uuid.UUID
+	ProjectID uuid.UUID
+	Type      NotifierType
+	Events    []NotificationEvent // Events to notify about, all events if empty
+	Config    NotifierConfig
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NotifierConfig holds where a notifier delivers notifications. Which fields are used depends on the type.
+type NotifierConfig struct {
+	URL          string   `json:"url,omitempty"`   // Webhook URL, ntfy topic URL or Gotify server URL
+	Token        string   `json:"token,omitempty"` // ntfy access token or Gotify application token
+	SMTPHost     string   `json:"smtp_host,omitempty"`
+	SMTPPort     int      `json:"smtp_port,omitempty"`
+	SMTPUsername string   `json:"smtp_username,omitempty"`
+	SMTPPassword string   `json:"smtp_password,omitempty"`
+	From         string   `json:"from,omitempty"`
+	To           []string `json:"to,omitempty"`
+}
+
+// Subscribed reports whether the notifier notifies about event
+func (n *Notifier) Subscribed(event NotificationEvent) bool {
+	return len(n.Events) == 0 || slices.Contains(n.Events, event)
+}
+
+// Catalog is a Git repository that lists projects in its CatalogFile. Syncing a catalog creates, updates and
+// removes the projects it manages to match the list.
+type Catalog struct {
+	ID            uuid.UUID
+	Name          string
+	GitURL        string
+	GitBranch     string         // Git branch to use (never empty, always set to default branch if not specified)
+	GitAuth       *GitAuthConfig // Git authentication configuration, shared with projects that ask for it
+	WorkingDir    string
+	Secrets       []string   // Secrets in KEY=value format, one per string, referenced by the listed projects
+	LastCommit    *string    // Commit of the last sync
+	LastSyncedAt  *time.Time // Nil until the catalog is synced
+	LastSyncError string     // Why the last sync failed, completely or for some projects, empty if it didn't
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// GitDir returns the directory the catalog's repository is cloned into
+func (c *Catalog) GitDir() (string, error) {
+	if c.WorkingDir == "" {
+		return "", fmt.Errorf("working directory is not set for catalog %s", c.Name)
+	}
+	return filepath.Join(c.WorkingDir, GitDir), nil
+}
+
+func (c *Catalog) LastCommitStr() string {
+	if c.LastCommit == nil {
+		return ""
+	}
+	return *c.LastCommit
+}
+
+// Secret returns the value of the secret called name, and whether the catalog has it
+func (c *Catalog) Secret(name string) (string, bool) {
+	for _, secret := range c.Secrets {
+		key, value, _ := strings.Cut(secret, "=")
+		if key == name {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// SecretNames returns the names of the catalog's secrets, for showing them without their values
+func (c *Catalog) SecretNames() []string {
+	names := make([]string, len(c.Secrets))
+	for i, secret := range c.Secrets {
+		names[i], _, _ = strings.Cut(secret, "=")
+	}
+	return names
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DriftKind is how the containers of a project differ from the services its deployed commit declares
+type DriftKind string
+
+const (
+	DriftKindMissing DriftKind = "missing" // A declared service has no container
+	DriftKindExtra   DriftKind = "extra"   // A container belongs to a service that isn't declared
+	DriftKindImage   DriftKind = "image"   // A container runs another image than the declared one
+	DriftKindConfig  DriftKind = "config"  // A container was created from another configuration of its service
+)
+
+// ServiceDrift is a difference between a service of a project and its containers
+type ServiceDrift struct {
+	Service   string
+	Kind      DriftKind
+	Container string // Empty for missing services
+	Expected  string // Declared image or configuration hash
+	Actual    string // Image or configuration hash of the container
+}
+
+// String describes the difference, e.g. "web: container web-1 runs image nginx:1.25, nginx:1.27 is declared"
+func (d ServiceDrift) String() string {
+	switch d.Kind {
+	case DriftKindMissing:
+		return fmt.Sprintf("%s: no container", d.Service)
+	case DriftKindExtra:
+		return fmt.Sprintf("%s: container %s belongs to a service that is not declared", d.Service, d.Container)
+	case DriftKindImage:
+		return fmt.Sprintf("%s: container %s runs image %s, %s is declared",
+			d.Service, d.Container, d.Actual, d.Expected)
+	case DriftKindConfig:
+		return fmt.Sprintf("%s: container %s was created from another configuration", d.Service, d.Container)
+	default:
+		return fmt.Sprintf("%s: %s", d.Service, d.Kind)
+	}
+}
+
+// FormatDrift describes each difference, as recorded in Project.Drift
+func FormatDrift(drift []ServiceDrift) []string {
+	descriptions := make([]string, 0, len(drift))
+	for _, d := range drift {
+		descriptions = append(descriptions, d.String())
+	}
+	return descriptions
+}
+
+// DetectDrift compares the services the deployed commit of a running project declares with its containers, and
+// records the differences on the project as its drift. A project going out of sync is notified about with the
+// drift_detected event. Projects that aren't running have no drift. Projects being deployed aren't checked, since
+// their containers are being changed, and ErrDeploymentInProgress is returned.
+func (s *ProjectService) DetectDrift(ctx context.Context, projectID uuid.UUID) ([]ServiceDrift, error) {
+	project, err := s.Get(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("project not found: %w", err)
+	}
+	if project.Status != ProjectStatusRunning {
+		return nil, nil
+	}
+	if s.deploying(projectID) {
+		return nil, ErrDeploymentInProgress
+	}
+
+	// The checkout is of the deployed commit, deployments check out the commit they deploy
+	composeProject := NewComposeProject(project, s.config)
+	if composeProject == nil {
+		return nil, fmt.Errorf("failed to create compose project")
+	}
+	drift, err := composeProject.Drift(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect drift: %w", err)
+	}
+
+	// A deployment that started in the meantime changes the containers, it decides whether they are in sync
+	if s.deploying(projectID) {
+		return nil, ErrDeploymentInProgress
+	}
+
+	descriptions := FormatDrift(drift)
+	previous := project.Drift
+	if slices.Equal(descriptions, previous) {
+		return drift, nil
+	}
+	if err := s.projectRepository.UpdateDrift(projectID, descriptions); err != nil {
+		return nil, fmt.Errorf("failed to record drift: %w", err)
+	}
+
+	switch {
+	case len(descriptions) == 0:
+		slog.Info("Project is back in sync", "project_id", project.ID, "project_name", project.Name)
+	case len(previous) == 0:
+		slog.Warn("Project is out of sync",
+			"project_id", project.ID,
+			"project_name", project.Name,
+			"commit_hash", project.LastCommitStr(),
+			"drift", descriptions)
+		if s.notifications != nil {
+			s.notifications.Notify(NewDriftNotification(project, descriptions))
+		}
+	}
+	return drift, nil
+}
+
+// deploying reports whether the deployment lock of a project is held, by this process or another one
+func (s *ProjectService) deploying(projectID uuid.UUID) bool {
+	if s.lockRepository == nil {
+		return false
+	}
+	lock, err := s.lockRepository.FindByProjectID(projectID)
+	return err == nil && lock.ExpiresAt.After(time.Now())
+}
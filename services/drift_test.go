@@ -0,0 +1,226 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// driftComposeConfig declares the services web and worker, and a service scaled to zero
+const driftComposeConfig = `{"services": {
+	"web": {"image": "nginx:1.27"},
+	"worker": {"build": {"context": "."}},
+	"debug": {"image": "busybox", "deploy": {"replicas": 0}}
+}}`
+
+// driftContainer formats a container as listed by docker compose ps --format json
+func driftContainer(service, name, image, hash string) string {
+	return `{"Service": "` + service + `", "Name": "` + name + `", "Image": "` + image + `", "State": "running", ` +
+		`"Labels": "com.docker.compose.project=test-project,com.docker.compose.config-hash=` + hash + `"}`
+}
+
+// setupDriftDocker writes a script standing in for Docker to dir and returns its path. The Compose configuration
+// is driftComposeConfig, with hashes web-hash and worker-hash, the containers are listed by dir/ps, and pulling,
+// starting and stopping the project succeed.
+func setupDriftDocker(t *testing.T, dir string, containers ...string) string {
+	t.Helper()
+	t.Setenv("OAR_TEST_DIR", dir)
+	writeDriftContainers(t, dir, containers...)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.json"), []byte(driftComposeConfig), 0o600))
+	hashes := "web web-hash\nworker worker-hash\ndebug debug-hash\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "hashes"), []byte(hashes), 0o600))
+
+	script := `#!/bin/sh
+while [ "$1" != "compose" ]; do shift; done
+shift
+while case "$1" in --*) true ;; *) false ;; esac; do shift 2; done
+case "$1 $2" in
+"config --format") cat "$OAR_TEST_DIR/config.json" ;;
+"config --hash") cat "$OAR_TEST_DIR/hashes" ;;
+"ps --all") cat "$OAR_TEST_DIR/ps" ;;
+"pull "* | "up "* | "down "*) ;;
+*) exit 1 ;;
+esac
+`
+	dockerCommand := filepath.Join(dir, "docker")
+	require.NoError(t, os.WriteFile(dockerCommand, []byte(script), 0o755))
+	return dockerCommand
+}
+
+// writeDriftContainers replaces the containers the script of setupDriftDocker lists
+func writeDriftContainers(t *testing.T, dir string, containers ...string) {
+	t.Helper()
+	ps := []byte(strings.Join(containers, "\n"))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ps"), ps, 0o600))
+}
+
+func TestComposeProject_Drift(t *testing.T) {
+	web := driftContainer("web", "test-project-web-1", "nginx:1.27", "web-hash")
+	worker := driftContainer("worker", "test-project-worker-1", "test-project-worker", "worker-hash")
+
+	tests := []struct {
+		name       string
+		containers []string
+		expected   []ServiceDrift
+	}{
+		{
+			name:       "in sync",
+			containers: []string{web, worker},
+		},
+		{
+			name: "taken down",
+			expected: []ServiceDrift{
+				{Service: "web", Kind: DriftKindMissing},
+				{Service: "worker", Kind: DriftKindMissing},
+			},
+		},
+		{
+			name: "extra service",
+			containers: []string{
+				web, worker, driftContainer("cache", "test-project-cache-1", "redis:7", "cache-hash"),
+			},
+			expected: []ServiceDrift{{Service: "cache", Kind: DriftKindExtra, Container: "test-project-cache-1"}},
+		},
+		{
+			name:       "other image",
+			containers: []string{driftContainer("web", "test-project-web-1", "nginx:1.25", "web-hash"), worker},
+			expected: []ServiceDrift{{
+				Service:   "web",
+				Kind:      DriftKindImage,
+				Container: "test-project-web-1",
+				Expected:  "nginx:1.27",
+				Actual:    "nginx:1.25",
+			}},
+		},
+		{
+			name: "other configuration",
+			containers: []string{
+				web,
+				worker,
+				driftContainer("worker", "test-project-worker-2", "test-project-worker", "edited-hash"),
+			},
+			expected: []ServiceDrift{{
+				Service:   "worker",
+				Kind:      DriftKindConfig,
+				Container: "test-project-worker-2",
+				Expected:  "worker-hash",
+				Actual:    "edited-hash",
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			composeProject := createTestComposeProject()
+			composeProject.Config.DockerCommand = setupDriftDocker(t, t.TempDir(), tt.containers...)
+
+			drift, err := composeProject.Drift(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, drift)
+		})
+	}
+}
+
+func TestServiceDrift_String(t *testing.T) {
+	assert.Equal(t, []string{
+		"web: no container",
+		"cache: container app-cache-1 belongs to a service that is not declared",
+		"web: container app-web-1 runs image nginx:1.25, nginx:1.27 is declared",
+		"web: container app-web-1 was created from another configuration",
+	}, FormatDrift([]ServiceDrift{
+		{Service: "web", Kind: DriftKindMissing},
+		{Service: "cache", Kind: DriftKindExtra, Container: "app-cache-1"},
+		{Service: "web", Kind: DriftKindImage, Container: "app-web-1", Expected: "nginx:1.27", Actual: "nginx:1.25"},
+		{Service: "web", Kind: DriftKindConfig, Container: "app-web-1", Expected: "a", Actual: "b"},
+	}))
+}
+
+func TestProjectService_DetectDrift(t *testing.T) {
+	service, repo, _, _, _ := setupMockProjectService(t)
+	notifications := &MockNotificationSender{}
+	service.notifications = notifications
+
+	tempDir := t.TempDir()
+	web := driftContainer("web", "test-project-web-1", "nginx:1.27", "web-hash")
+	worker := driftContainer("worker", "test-project-worker-1", "test-project-worker", "worker-hash")
+	service.config.DockerCommand = setupDriftDocker(t, tempDir, web, worker)
+
+	project := createTestProject()
+	project.WorkingDir = tempDir
+	project.Status = ProjectStatusRunning
+	require.NoError(t, os.Mkdir(filepath.Join(tempDir, GitDir), 0o755))
+	repo.projects[project.ID] = project
+
+	drift, err := service.DetectDrift(context.Background(), project.ID)
+	require.NoError(t, err)
+	assert.Empty(t, drift)
+	assert.Empty(t, project.Drift)
+
+	// Taken down by hand, which is notified about once
+	writeDriftContainers(t, tempDir)
+	for range 2 {
+		drift, err = service.DetectDrift(context.Background(), project.ID)
+		require.NoError(t, err)
+		assert.Len(t, drift, 2)
+	}
+	assert.Equal(t, []string{"web: no container", "worker: no container"}, project.Drift)
+	assert.Equal(t, []NotificationEvent{NotificationEventDriftDetected}, notifications.Events())
+	assert.Equal(t, "web: no container\nworker: no container", notifications.notifications[0].Output)
+
+	writeDriftContainers(t, tempDir, web, worker)
+	drift, err = service.DetectDrift(context.Background(), project.ID)
+	require.NoError(t, err)
+	assert.Empty(t, drift)
+	assert.Empty(t, project.Drift)
+	assert.Len(t, notifications.Events(), 1)
+
+	// Stopped projects have no drift
+	writeDriftContainers(t, tempDir)
+	project.Status = ProjectStatusStopped
+	drift, err = service.DetectDrift(context.Background(), project.ID)
+	require.NoError(t, err)
+	assert.Empty(t, drift)
+	assert.Empty(t, project.Drift)
+}
+
+func TestProjectService_DetectDrift_Deploying(t *testing.T) {
+	service, project, locks := setupLockingProjectService(t)
+	tempDir := t.TempDir()
+	service.config.DockerCommand = setupDriftDocker(t, tempDir)
+	project.WorkingDir = tempDir
+	project.Status = ProjectStatusRunning
+	require.NoError(t, os.Mkdir(filepath.Join(tempDir, GitDir), 0o755))
+
+	lock := &DeploymentLock{
+		ID:        uuid.New(),
+		ProjectID: project.ID,
+		Holder:    "other",
+		Trigger:   DeploymentTriggerWeb,
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+	acquired, err := locks.Acquire(lock, time.Now())
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	_, err = service.DetectDrift(context.Background(), project.ID)
+	assert.ErrorIs(t, err, ErrDeploymentInProgress)
+	assert.Empty(t, project.Drift)
+
+	// A stale lock of a process that died doesn't stop the check
+	require.NoError(t, locks.Release(lock.ID))
+	lock.ID = uuid.New()
+	lock.ExpiresAt = time.Now().Add(-time.Minute)
+	_, err = locks.Acquire(lock, time.Now().Add(-2*time.Minute))
+	require.NoError(t, err)
+
+	drift, err := service.DetectDrift(context.Background(), project.ID)
+	require.NoError(t, err)
+	assert.Len(t, drift, 2)
+}
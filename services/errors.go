@@ -1,9 +1,17 @@
 package services
 
 import (
+	"errors"
 	"strings"
+
+	"gorm.io/gorm"
 )
 
+// IsNotFound reports whether err means that a requested record does not exist
+func IsNotFound(err error) bool {
+	return errors.Is(err, gorm.ErrRecordNotFound)
+}
+
 // FormatErrorForUser converts technical errors to user-friendly messages
 // This should only be called at the handler level
 func FormatErrorForUser(err error) string {
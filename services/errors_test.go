@@ -2,9 +2,11 @@ package services
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
 )
 
 func TestFormatErrorForUser(t *testing.T) {
@@ -62,3 +64,10 @@ func TestFormatErrorForUser(t *testing.T) {
 		})
 	}
 }
+
+func TestIsNotFound(t *testing.T) {
+	assert.True(t, IsNotFound(gorm.ErrRecordNotFound))
+	assert.True(t, IsNotFound(fmt.Errorf("project not found: %w", gorm.ErrRecordNotFound)))
+	assert.False(t, IsNotFound(errors.New("record not found")))
+	assert.False(t, IsNotFound(nil))
+}
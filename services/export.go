@@ -0,0 +1,486 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"golang.org/x/crypto/argon2"
+	"gopkg.in/yaml.v3"
+)
+
+// ExportVersion is the version of the export format written by Export
+const ExportVersion = 1
+
+// ErrInvalidExport is returned for exports that can't be imported, and for secrets that can't be decrypted with
+// the passphrase given
+var ErrInvalidExport = errors.New("invalid export")
+
+// Export holds the settings of the projects and catalogs of an Oar instance, for moving them to another one.
+// Projects managed by a catalog are left out, as syncing the catalog creates them. Variables, credentials and
+// notifiers are encrypted with a key derived from a passphrase, or left out.
+type Export struct {
+	Version    int               `yaml:"version"`
+	ExportedAt time.Time         `yaml:"exported_at"`
+	Salt       string            `yaml:"salt,omitempty"`    // Salt of the passphrase key, empty if secrets are left out
+	Omitted    []string          `yaml:"omitted,omitempty"` // What the export leaves out, reported on import
+	Projects   []ExportedProject `yaml:"projects"`
+	Catalogs   []ExportedCatalog `yaml:"catalogs"`
+}
+
+// exportOmitted is what every export leaves out
+var exportOmitted = []string{"users", "role bindings", "deployment history", "volume backups"}
+
+// exportOmittedSecrets is what an export without secrets leaves out on top of exportOmitted, variables including
+// the secrets of catalogs
+var exportOmittedSecrets = []string{"variables", "Git credentials", "notifiers"}
+
+// ExportedProject is an exported project
+type ExportedProject struct {
+	Name            string           `yaml:"name"`
+	GitURL          string           `yaml:"git_url"`
+	GitBranch       string           `yaml:"git_branch"`
+	GitRefType      string           `yaml:"git_ref_type"`
+	GitRef          string           `yaml:"git_ref,omitempty"`
+	ComposeFiles    []string         `yaml:"compose_files"`
+	WatcherEnabled  bool             `yaml:"watcher_enabled"`
+	RequireApproval bool             `yaml:"require_approval,omitempty"`
+	WatchImages     bool             `yaml:"watch_images,omitempty"`
+	AutoRollback    bool             `yaml:"auto_rollback"`
+	BackupVolumes   bool             `yaml:"backup_volumes,omitempty"`
+	HealthChecks    []string         `yaml:"health_checks,omitempty"`
+	PreDeployHooks  []string         `yaml:"pre_deploy_hooks,omitempty"`
+	PostDeployHooks []string         `yaml:"post_deploy_hooks,omitempty"`
+	Timeouts        ManifestTimeouts `yaml:"timeouts,omitempty"`
+	Running         bool             `yaml:"running"`           // Whether the project was running when exported
+	Secrets         string           `yaml:"secrets,omitempty"` // Encrypted variables, Git credentials and notifiers
+}
+
+// ExportedCatalog is an exported catalog
+type ExportedCatalog struct {
+	Name      string `yaml:"name"`
+	GitURL    string `yaml:"git_url"`
+	GitBranch string `yaml:"git_branch"`
+	Secrets   string `yaml:"secrets,omitempty"` // Encrypted secrets and Git credentials
+}
+
+// exportedSecrets are the secrets of an exported project or catalog before they are encrypted
+type exportedSecrets struct {
+	Variables []string            `json:"variables,omitempty"` // Variables of a project, secrets of a catalog
+	GitAuth   *GitAuthCredentials `json:"git_auth,omitempty"`
+	Notifiers []exportedNotifier  `json:"notifiers,omitempty"` // Notifiers of a project
+}
+
+// exportedNotifier is a notifier of an exported project. It's exported with the secrets, as webhook URLs and
+// tokens give access to the services notified.
+type exportedNotifier struct {
+	Type   NotifierType        `json:"type"`
+	Events []NotificationEvent `json:"events,omitempty"`
+	Config NotifierConfig      `json:"config"`
+}
+
+// ExportOptions control what Export writes
+type ExportOptions struct {
+	Passphrase  string // Encrypts variables, credentials and notifiers, required unless OmitSecrets is set
+	OmitSecrets bool   // Leave variables, credentials and notifiers out
+}
+
+// ImportOptions control what Import does
+type ImportOptions struct {
+	Passphrase string         // Decrypts the variables, credentials and notifiers of the export
+	Deploy     *DeployOptions // Deploy projects that were running and sync catalogs, nil to only create them
+}
+
+// ImportResult is what importing an export did
+type ImportResult struct {
+	Created  []string // Names of the projects and catalogs created
+	Skipped  []string // Names of the projects and catalogs that already exist, left as they are
+	Errors   []string // Projects and catalogs that could not be imported or deployed, and why
+	Warnings []string // What the export left out, to set up again by hand if needed
+}
+
+// ExportService exports and imports the projects and catalogs of an Oar instance
+type ExportService struct {
+	projects      ProjectManager
+	catalogs      CatalogManager
+	notifications NotificationManager
+}
+
+// Export collects the settings of all projects not managed by a catalog and of all catalogs
+func (s *ExportService) Export(options ExportOptions) (*Export, error) {
+	if !options.OmitSecrets && options.Passphrase == "" {
+		return nil, errors.New("a passphrase is required to export secrets")
+	}
+
+	export := &Export{Version: ExportVersion, ExportedAt: time.Now().UTC(), Omitted: slices.Clone(exportOmitted)}
+	var encryption *EncryptionService
+	if options.OmitSecrets {
+		export.Omitted = append(export.Omitted, exportOmittedSecrets...)
+	} else {
+		salt := make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, fmt.Errorf("failed to generate salt: %w", err)
+		}
+		export.Salt = base64.StdEncoding.EncodeToString(salt)
+
+		var err error
+		if encryption, err = passphraseEncryption(options.Passphrase, export.Salt); err != nil {
+			return nil, err
+		}
+	}
+
+	projects, err := s.projects.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list projects: %w", err)
+	}
+	for _, project := range projects {
+		if project.CatalogID != nil {
+			continue
+		}
+		var notifiers []*Notifier
+		if encryption != nil {
+			if notifiers, err = s.notifications.ListNotifiers(project.ID); err != nil {
+				return nil, fmt.Errorf("failed to list notifiers of project %s: %w", project.Name, err)
+			}
+		}
+		secrets, err := encryptSecrets(encryption, project.Variables, project.GitAuth, notifiers)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt secrets of project %s: %w", project.Name, err)
+		}
+		export.Projects = append(export.Projects, ExportedProject{
+			Name:            project.Name,
+			GitURL:          project.GitURL,
+			GitBranch:       project.GitBranch,
+			GitRefType:      project.GitRefType.String(),
+			GitRef:          project.GitRef,
+			ComposeFiles:    project.ComposeFiles,
+			WatcherEnabled:  project.WatcherEnabled,
+			RequireApproval: project.RequireApproval,
+			WatchImages:     project.WatchImages,
+			AutoRollback:    project.AutoRollback,
+			BackupVolumes:   project.BackupVolumes,
+			HealthChecks:    FormatHealthChecks(project.HealthChecks),
+			PreDeployHooks:  FormatHooks(project.PreDeployHooks),
+			PostDeployHooks: FormatHooks(project.PostDeployHooks),
+			Timeouts: ManifestTimeouts{
+				Pull:   project.Timeouts.Pull,
+				Up:     project.Timeouts.Up,
+				Wait:   project.Timeouts.Wait,
+				Health: project.Timeouts.Health,
+			},
+			Running: project.Status == ProjectStatusRunning,
+			Secrets: secrets,
+		})
+	}
+
+	catalogs, err := s.catalogs.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list catalogs: %w", err)
+	}
+	for _, catalog := range catalogs {
+		secrets, err := encryptSecrets(encryption, catalog.Secrets, catalog.GitAuth, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt secrets of catalog %s: %w", catalog.Name, err)
+		}
+		export.Catalogs = append(export.Catalogs, ExportedCatalog{
+			Name:      catalog.Name,
+			GitURL:    catalog.GitURL,
+			GitBranch: catalog.GitBranch,
+			Secrets:   secrets,
+		})
+	}
+	return export, nil
+}
+
+// Import creates the projects and catalogs of an export, cloning their repositories, along with the notifiers of
+// the projects. Projects and catalogs whose name is taken are skipped. A project or catalog that can't be imported
+// doesn't stop the others and is reported in the result, as is what the export left out. Secrets that can't be
+// decrypted with the passphrase fail the import before anything is created.
+func (s *ExportService) Import(ctx context.Context, export *Export, options ImportOptions) (*ImportResult, error) {
+	projects, notifiers, catalogs, err := s.decode(export, options.Passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	existingProjects, err := s.projects.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list projects: %w", err)
+	}
+	projectNames := make(map[string]bool, len(existingProjects))
+	for _, project := range existingProjects {
+		projectNames[project.Name] = true
+	}
+	existingCatalogs, err := s.catalogs.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list catalogs: %w", err)
+	}
+	catalogNames := make(map[string]bool, len(existingCatalogs))
+	for _, catalog := range existingCatalogs {
+		catalogNames[catalog.Name] = true
+	}
+
+	result := &ImportResult{}
+	for _, omitted := range export.Omitted {
+		result.Warnings = append(result.Warnings, "not exported: "+omitted)
+	}
+	for i, project := range projects {
+		if projectNames[project.Name] {
+			result.Skipped = append(result.Skipped, project.Name)
+			continue
+		}
+		created, err := s.projects.Create(project)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", project.Name, err))
+			continue
+		}
+		result.Created = append(result.Created, created.Name)
+
+		for _, notifier := range notifiers[i] {
+			notifier.ProjectID = created.ID
+			if err := s.notifications.AddNotifier(notifier); err != nil {
+				result.Errors = append(result.Errors,
+					fmt.Sprintf("%s: %s notifier: %v", created.Name, notifier.Type, err))
+			}
+		}
+
+		if options.Deploy != nil && export.Projects[i].Running {
+			if err := s.projects.DeployPiping(ctx, created.ID, *options.Deploy); err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("%s: deployment failed: %v", created.Name, err))
+			}
+		}
+	}
+
+	// Catalogs come last, so that the projects they manage don't take the names of exported ones
+	for _, catalog := range catalogs {
+		if catalogNames[catalog.Name] {
+			result.Skipped = append(result.Skipped, catalog.Name)
+			continue
+		}
+		created, err := s.catalogs.Create(catalog)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", catalog.Name, err))
+			continue
+		}
+		result.Created = append(result.Created, created.Name)
+
+		if options.Deploy != nil {
+			if _, err := s.catalogs.Sync(ctx, created.ID); err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("%s: sync failed: %v", created.Name, err))
+			}
+		}
+	}
+	return result, nil
+}
+
+// decode converts the projects, their notifiers and the catalogs of an export to the service representation,
+// decrypting their secrets
+func (s *ExportService) decode(export *Export, passphrase string) ([]*Project, [][]*Notifier, []*Catalog, error) {
+	if export.Version != ExportVersion {
+		return nil, nil, nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidExport, export.Version)
+	}
+
+	var encryption *EncryptionService
+	if export.Salt != "" {
+		if passphrase == "" {
+			return nil, nil, nil, fmt.Errorf("%w: the export has secrets, a passphrase is required",
+				ErrInvalidExport)
+		}
+		var err error
+		if encryption, err = passphraseEncryption(passphrase, export.Salt); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	projects := make([]*Project, len(export.Projects))
+	notifiers := make([][]*Notifier, len(export.Projects))
+	for i, exported := range export.Projects {
+		project, projectNotifiers, err := exported.toProject(encryption)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("%w: project %s: %w", ErrInvalidExport, exported.Name, err)
+		}
+		projects[i] = project
+		notifiers[i] = projectNotifiers
+	}
+
+	catalogs := make([]*Catalog, len(export.Catalogs))
+	for i, exported := range export.Catalogs {
+		secrets, err := decryptSecrets(encryption, exported.Secrets)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("%w: catalog %s: %w", ErrInvalidExport, exported.Name, err)
+		}
+		catalogs[i] = &Catalog{
+			Name:      exported.Name,
+			GitURL:    exported.GitURL,
+			GitBranch: exported.GitBranch,
+			GitAuth:   secrets.gitAuth(),
+			Secrets:   secrets.Variables,
+		}
+	}
+	return projects, notifiers, catalogs, nil
+}
+
+// toProject converts an exported project to a new project and its notifiers, decrypting its secrets with
+// encryption
+func (p *ExportedProject) toProject(encryption *EncryptionService) (*Project, []*Notifier, error) {
+	secrets, err := decryptSecrets(encryption, p.Secrets)
+	if err != nil {
+		return nil, nil, err
+	}
+	gitRefType, err := ParseGitRefType(p.GitRefType)
+	if err != nil {
+		return nil, nil, err
+	}
+	healthChecks, err := ParseHealthChecks(p.HealthChecks)
+	if err != nil {
+		return nil, nil, err
+	}
+	preDeployHooks, err := ParseHooks(p.PreDeployHooks)
+	if err != nil {
+		return nil, nil, err
+	}
+	postDeployHooks, err := ParseHooks(p.PostDeployHooks)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	project := NewProject(p.Name, p.GitURL, p.ComposeFiles, secrets.Variables)
+	project.GitBranch = p.GitBranch
+	project.GitRefType = gitRefType
+	project.GitRef = p.GitRef
+	project.GitAuth = secrets.gitAuth()
+	project.WatcherEnabled = p.WatcherEnabled
+	project.RequireApproval = p.RequireApproval
+	project.WatchImages = p.WatchImages
+	project.AutoRollback = p.AutoRollback
+	project.BackupVolumes = p.BackupVolumes
+	project.HealthChecks = healthChecks
+	project.PreDeployHooks = preDeployHooks
+	project.PostDeployHooks = postDeployHooks
+	project.Timeouts = ComposeTimeouts{
+		Pull:   p.Timeouts.Pull,
+		Up:     p.Timeouts.Up,
+		Wait:   p.Timeouts.Wait,
+		Health: p.Timeouts.Health,
+	}
+
+	notifiers := make([]*Notifier, len(secrets.Notifiers))
+	for i, exported := range secrets.Notifiers {
+		notifiers[i] = &Notifier{Type: exported.Type, Events: exported.Events, Config: exported.Config}
+		if err := ValidateNotifier(notifiers[i]); err != nil {
+			return nil, nil, err
+		}
+	}
+	return &project, notifiers, nil
+}
+
+// gitAuth returns the Git credentials of the secrets, or nil
+func (s *exportedSecrets) gitAuth() *GitAuthConfig {
+	if s.GitAuth == nil {
+		return nil
+	}
+	return &GitAuthConfig{HTTPAuth: s.GitAuth.HTTP, SSHAuth: s.GitAuth.SSH}
+}
+
+// encryptSecrets encrypts variables, Git credentials and notifiers with encryption. A nil encryption, for exports
+// without secrets, returns an empty string.
+func encryptSecrets(
+	encryption *EncryptionService,
+	variables []string,
+	gitAuth *GitAuthConfig,
+	notifiers []*Notifier,
+) (string, error) {
+	if encryption == nil || (len(variables) == 0 && gitAuth == nil && len(notifiers) == 0) {
+		return "", nil
+	}
+
+	secrets := exportedSecrets{Variables: variables}
+	if gitAuth != nil {
+		secrets.GitAuth = &GitAuthCredentials{HTTP: gitAuth.HTTPAuth, SSH: gitAuth.SSHAuth}
+	}
+	for _, notifier := range notifiers {
+		secrets.Notifiers = append(secrets.Notifiers,
+			exportedNotifier{Type: notifier.Type, Events: notifier.Events, Config: notifier.Config})
+	}
+	data, err := json.Marshal(secrets)
+	if err != nil {
+		return "", err
+	}
+	return encryption.Encrypt(string(data))
+}
+
+// decryptSecrets decrypts the secrets encrypted by encryptSecrets
+func decryptSecrets(encryption *EncryptionService, encrypted string) (*exportedSecrets, error) {
+	secrets := &exportedSecrets{}
+	if encrypted == "" {
+		return secrets, nil
+	}
+	if encryption == nil {
+		return nil, errors.New("secrets without a salt to decrypt them with")
+	}
+
+	data, err := encryption.Decrypt(encrypted)
+	if err != nil {
+		return nil, errors.New("failed to decrypt secrets, check the passphrase")
+	}
+	if err := json.Unmarshal([]byte(data), secrets); err != nil {
+		return nil, fmt.Errorf("failed to parse secrets: %w", err)
+	}
+	return secrets, nil
+}
+
+// passphraseEncryption returns an encryption service keyed with the passphrase and base64-encoded salt
+func passphraseEncryption(passphrase, salt string) (*EncryptionService, error) {
+	saltBytes, err := base64.StdEncoding.DecodeString(salt)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid salt: %v", ErrInvalidExport, err)
+	}
+	key := argon2.IDKey([]byte(passphrase), saltBytes, 1, 64*1024, 4, 32)
+	return NewEncryptionService(base64.URLEncoding.EncodeToString(key))
+}
+
+// ReadExport reads an export written by WriteExport
+func ReadExport(r io.Reader) (*Export, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read export: %w", err)
+	}
+
+	var export Export
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&export); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidExport, err)
+	}
+	return &export, nil
+}
+
+// WriteExport writes export as YAML
+func WriteExport(w io.Writer, export *Export) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(export); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	return encoder.Close()
+}
+
+// NewExportService creates an export service that reads and creates projects, catalogs and notifiers through
+// projects, catalogs and notifications
+func NewExportService(
+	projects ProjectManager,
+	catalogs CatalogManager,
+	notifications NotificationManager,
+) *ExportService {
+	return &ExportService{
+		projects:      projects,
+		catalogs:      catalogs,
+		notifications: notifications,
+	}
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeExportCatalogs keeps catalogs in memory, standing in for the catalog service
+type fakeExportCatalogs struct {
+	MockCatalogManager
+	catalogs []*Catalog
+	synced   []string
+}
+
+func (f *fakeExportCatalogs) List() ([]*Catalog, error) {
+	return f.catalogs, nil
+}
+
+func (f *fakeExportCatalogs) Create(catalog *Catalog) (*Catalog, error) {
+	catalog.ID = uuid.New()
+	f.catalogs = append(f.catalogs, catalog)
+	return catalog, nil
+}
+
+func (f *fakeExportCatalogs) Sync(ctx context.Context, id uuid.UUID) (*CatalogSyncResult, error) {
+	for _, catalog := range f.catalogs {
+		if catalog.ID == id {
+			f.synced = append(f.synced, catalog.Name)
+		}
+	}
+	return &CatalogSyncResult{}, nil
+}
+
+// fakeExportNotifications keeps notifiers in memory, standing in for the notification service
+type fakeExportNotifications struct {
+	MockNotificationManager
+	notifiers map[uuid.UUID][]*Notifier
+}
+
+func newFakeExportNotifications() *fakeExportNotifications {
+	return &fakeExportNotifications{notifiers: make(map[uuid.UUID][]*Notifier)}
+}
+
+func (f *fakeExportNotifications) ListNotifiers(projectID uuid.UUID) ([]*Notifier, error) {
+	return f.notifiers[projectID], nil
+}
+
+func (f *fakeExportNotifications) AddNotifier(notifier *Notifier) error {
+	if err := ValidateNotifier(notifier); err != nil {
+		return err
+	}
+	f.notifiers[notifier.ProjectID] = append(f.notifiers[notifier.ProjectID], notifier)
+	return nil
+}
+
+// setupExportSource creates an export service for an instance with a running private project, a stopped public
+// one, a project managed by a catalog, and the catalog. The private project has a notifier.
+func setupExportSource() *ExportService {
+	web := NewProject("web", "https://github.com/org/web.git", []string{"compose.yml"},
+		[]string{"DB_PASSWORD=s3cret"})
+	web.GitBranch = "main"
+	web.GitAuth = &GitAuthConfig{HTTPAuth: &GitHTTPAuthConfig{Username: "token", Password: "ghp_secret"}}
+	web.Status = ProjectStatusRunning
+	web.WatcherEnabled = true
+	web.AutoRollback = true
+	web.RequireApproval = true
+	web.WatchImages = true
+	web.HealthChecks = []HealthCheck{{Type: HealthCheckTypeTCP, Address: "localhost:5432"}}
+	web.PreDeployHooks = []Hook{{Type: HookTypeRun, Service: "app", Command: "migrate"}}
+	web.Timeouts = ComposeTimeouts{Up: 20 * time.Minute}
+
+	docs := NewProject("docs", "https://github.com/org/docs.git", []string{"compose.yml"}, nil)
+	docs.GitRefType = GitRefTypeTag
+	docs.GitRef = "v*"
+	docs.Status = ProjectStatusStopped
+
+	catalog := &Catalog{
+		ID:        uuid.New(),
+		Name:      "platform",
+		GitURL:    "https://github.com/org/platform.git",
+		GitBranch: "main",
+		Secrets:   []string{"api-token=t0ken"},
+	}
+	managed := NewProject("api", "https://github.com/org/api.git", []string{"compose.yml"}, nil)
+	managed.CatalogID = &catalog.ID
+
+	notifications := newFakeExportNotifications()
+	notifications.notifiers[web.ID] = []*Notifier{{
+		ID:        uuid.New(),
+		ProjectID: web.ID,
+		Type:      NotifierTypeSlack,
+		Events:    []NotificationEvent{NotificationEventDeploymentFailed},
+		Config:    NotifierConfig{URL: "https://hooks.slack.com/services/T000/B000/hook-secret"},
+	}}
+
+	return NewExportService(newFakeCatalogProjects(&web, &docs, &managed),
+		&fakeExportCatalogs{catalogs: []*Catalog{catalog}}, notifications)
+}
+
+func TestExportService_ExportImport(t *testing.T) {
+	export, err := setupExportSource().Export(ExportOptions{Passphrase: "correct horse"})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteExport(&buf, export))
+	for _, secret := range []string{"s3cret", "ghp_secret", "t0ken", "hook-secret"} {
+		assert.NotContains(t, buf.String(), secret)
+	}
+	assert.NotContains(t, buf.String(), "api.git")
+
+	read, err := ReadExport(&buf)
+	require.NoError(t, err)
+
+	projects := newFakeCatalogProjects()
+	catalogs := &fakeExportCatalogs{}
+	notifications := newFakeExportNotifications()
+	service := NewExportService(projects, catalogs, notifications)
+	result, err := service.Import(context.Background(), read, ImportOptions{
+		Passphrase: "correct horse",
+		Deploy:     &DeployOptions{Trigger: DeploymentTriggerCLI},
+	})
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{"web", "docs", "platform"}, result.Created)
+	assert.Empty(t, result.Skipped)
+	assert.Empty(t, result.Errors)
+	assert.Equal(t, []string{
+		"not exported: users",
+		"not exported: role bindings",
+		"not exported: deployment history",
+		"not exported: volume backups",
+	}, result.Warnings)
+
+	// Only the project that was running is deployed
+	assert.Equal(t, []string{"web"}, projects.deployed)
+	assert.Equal(t, []string{"platform"}, catalogs.synced)
+
+	web := projects.byName("web")
+	require.NotNil(t, web)
+	assert.Equal(t, []string{"DB_PASSWORD=s3cret"}, web.Variables)
+	assert.Equal(t, "ghp_secret", web.GitAuth.HTTPAuth.Password)
+	assert.Equal(t, "main", web.GitBranch)
+	assert.True(t, web.WatcherEnabled)
+	assert.True(t, web.AutoRollback)
+	assert.True(t, web.RequireApproval)
+	assert.True(t, web.WatchImages)
+	assert.Equal(t, []string{"tcp localhost:5432"}, FormatHealthChecks(web.HealthChecks))
+	assert.Equal(t, []string{"run app migrate"}, FormatHooks(web.PreDeployHooks))
+	assert.Equal(t, ComposeTimeouts{Up: 20 * time.Minute}, web.Timeouts)
+	require.Len(t, notifications.notifiers[web.ID], 1)
+	notifier := notifications.notifiers[web.ID][0]
+	assert.Equal(t, NotifierTypeSlack, notifier.Type)
+	assert.Equal(t, []NotificationEvent{NotificationEventDeploymentFailed}, notifier.Events)
+	assert.Equal(t, "https://hooks.slack.com/services/T000/B000/hook-secret", notifier.Config.URL)
+
+	docs := projects.byName("docs")
+	require.NotNil(t, docs)
+	assert.Equal(t, GitRefTypeTag, docs.GitRefType)
+	assert.Equal(t, "v*", docs.GitRef)
+	assert.Nil(t, docs.GitAuth)
+
+	require.Len(t, catalogs.catalogs, 1)
+	assert.Equal(t, []string{"api-token=t0ken"}, catalogs.catalogs[0].Secrets)
+	assert.Nil(t, projects.byName("api"))
+}
+
+func TestExportService_OmitSecrets(t *testing.T) {
+	service := setupExportSource()
+
+	_, err := service.Export(ExportOptions{})
+	assert.ErrorContains(t, err, "a passphrase is required")
+
+	export, err := service.Export(ExportOptions{OmitSecrets: true})
+	require.NoError(t, err)
+	assert.Empty(t, export.Salt)
+	for _, project := range export.Projects {
+		assert.Empty(t, project.Secrets)
+	}
+
+	projects := newFakeCatalogProjects()
+	notifications := newFakeExportNotifications()
+	result, err := NewExportService(projects, &fakeExportCatalogs{}, notifications).
+		Import(context.Background(), export, ImportOptions{})
+	require.NoError(t, err)
+	assert.Len(t, result.Created, 3)
+	assert.Contains(t, result.Warnings, "not exported: notifiers")
+	assert.Empty(t, notifications.notifiers)
+	assert.Empty(t, projects.deployed)
+	assert.Empty(t, projects.byName("web").Variables)
+	assert.Nil(t, projects.byName("web").GitAuth)
+}
+
+func TestExportService_Import_WrongPassphrase(t *testing.T) {
+	export, err := setupExportSource().Export(ExportOptions{Passphrase: "correct horse"})
+	require.NoError(t, err)
+
+	projects := newFakeCatalogProjects()
+	service := NewExportService(projects, &fakeExportCatalogs{}, newFakeExportNotifications())
+
+	for _, passphrase := range []string{"", "battery staple"} {
+		result, err := service.Import(context.Background(), export, ImportOptions{Passphrase: passphrase})
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrInvalidExport)
+	}
+	// Nothing is created
+	assert.Empty(t, projects.projects)
+}
+
+func TestExportService_Import_SkipsExisting(t *testing.T) {
+	export, err := setupExportSource().Export(ExportOptions{OmitSecrets: true})
+	require.NoError(t, err)
+
+	existing := NewProject("web", "https://github.com/other/web.git", []string{"compose.yml"}, nil)
+	projects := newFakeCatalogProjects(&existing)
+	catalogs := &fakeExportCatalogs{catalogs: []*Catalog{{ID: uuid.New(), Name: "platform"}}}
+
+	result, err := NewExportService(projects, catalogs, newFakeExportNotifications()).
+		Import(context.Background(), export, ImportOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"docs"}, result.Created)
+	assert.Equal(t, []string{"web", "platform"}, result.Skipped)
+	assert.Equal(t, "https://github.com/other/web.git", projects.byName("web").GitURL)
+}
+
+func TestReadExport_Invalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		export      string
+		expectedErr string
+	}{
+		{name: "malformed", export: "projects: [", expectedErr: "did not find expected"},
+		{name: "unknown key", export: "version: 1\nusers: []", expectedErr: "field users not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadExport(bytes.NewBufferString(tt.export))
+			assert.ErrorIs(t, err, ErrInvalidExport)
+			assert.ErrorContains(t, err, tt.expectedErr)
+		})
+	}
+
+	export, err := ReadExport(bytes.NewBufferString("version: 2\nprojects: []"))
+	require.NoError(t, err)
+	_, err = NewExportService(newFakeCatalogProjects(), &fakeExportCatalogs{}, newFakeExportNotifications()).
+		Import(context.Background(), export, ImportOptions{})
+	assert.ErrorContains(t, err, "unsupported version 2")
+}
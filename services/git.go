@@ -3,11 +3,19 @@ package services
 import (
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
 	"github.com/go-git/go-git/v5/plumbing/transport"
 	"github.com/go-git/go-git/v5/plumbing/transport/http"
 	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
@@ -31,6 +39,14 @@ type GitSSHAuthConfig struct {
 	User       string // SSH user (default: "git")
 }
 
+// GitCommit is a commit of a project's repository
+type GitCommit struct {
+	Hash    string
+	Author  string // Name of the author
+	Date    time.Time
+	Message string // First line of the commit message
+}
+
 // GitAuthType represents the Git authentication method type
 type GitAuthType string
 
@@ -341,6 +357,270 @@ func (s *GitService) GetRemoteLatestCommit(workingDir string, gitBranch string)
 	return ref.Hash().String(), nil
 }
 
+// FetchTags fetches all tags from the remote, replacing local tags that were moved upstream
+func (s *GitService) FetchTags(gitAuth *GitAuthConfig, workingDir string) error {
+	slog.Debug("Fetching tags from Git repository", "working_dir", workingDir)
+
+	repo, err := git.PlainOpen(workingDir)
+	if err != nil {
+		slog.Error("Service operation failed",
+			"layer", "git",
+			"operation", "git_fetch_tags",
+			"working_dir", workingDir,
+			"error", err)
+		return err
+	}
+
+	authMethod, err := s.createAuthMethod(gitAuth)
+	if err != nil {
+		return fmt.Errorf("failed to create auth method: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.GitTimeout)
+	defer cancel()
+
+	err = repo.FetchContext(ctx, &git.FetchOptions{
+		Auth:     authMethod,
+		RefSpecs: []config.RefSpec{"+refs/tags/*:refs/tags/*"},
+		Tags:     git.NoTags,
+	})
+	if err != nil && err != git.NoErrAlreadyUpToDate {
+		slog.Error("Service operation failed",
+			"layer", "git",
+			"operation", "git_fetch_tags",
+			"working_dir", workingDir,
+			"error", err)
+		return err
+	}
+
+	return nil
+}
+
+// ListTags returns the commit every tag of the repository points to, keyed by tag name.
+// Annotated tags are resolved to the commit they tag.
+func (s *GitService) ListTags(workingDir string) (map[string]string, error) {
+	repo, err := git.PlainOpen(workingDir)
+	if err != nil {
+		slog.Error("Service operation failed",
+			"layer", "git",
+			"operation", "git_list_tags",
+			"working_dir", workingDir,
+			"error", err)
+		return nil, err
+	}
+
+	refs, err := repo.Tags()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	tags := make(map[string]string)
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		hash := ref.Hash()
+		if tag, err := repo.TagObject(hash); err == nil {
+			commit, err := tag.Commit()
+			if err != nil {
+				return nil // Annotated tag of something other than a commit
+			}
+			hash = commit.Hash
+		}
+		tags[ref.Name().Short()] = hash.String()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// Checkout resets the working tree and the current branch to the given commit. The branch stays
+// checked out, so a later pull fast-forwards it to the remote again.
+func (s *GitService) Checkout(commit string, workingDir string) error {
+	repo, err := git.PlainOpen(workingDir)
+	if err != nil {
+		slog.Error("Service operation failed",
+			"layer", "git",
+			"operation", "git_checkout",
+			"commit", commit,
+			"working_dir", workingDir,
+			"error", err)
+		return err
+	}
+
+	hash, err := repo.ResolveRevision(plumbing.Revision(commit))
+	if err != nil {
+		slog.Error("Service operation failed",
+			"layer", "git",
+			"operation", "git_checkout",
+			"commit", commit,
+			"working_dir", workingDir,
+			"error", err)
+		return fmt.Errorf("failed to resolve commit %s: %w", commit, err)
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		slog.Error("Service operation failed",
+			"layer", "git",
+			"operation", "git_checkout",
+			"commit", commit,
+			"working_dir", workingDir,
+			"error", err)
+		return err
+	}
+
+	if err := worktree.Reset(&git.ResetOptions{Commit: *hash, Mode: git.HardReset}); err != nil {
+		slog.Error("Service operation failed",
+			"layer", "git",
+			"operation", "git_checkout",
+			"commit", commit,
+			"working_dir", workingDir,
+			"error", err)
+		return fmt.Errorf("failed to check out commit %s: %w", commit, err)
+	}
+
+	slog.Info("Commit checked out successfully", "commit", hash.String(), "working_dir", workingDir)
+	return nil
+}
+
+// Log returns the commits reachable from to that aren't reachable from from, newest first and at most limit of
+// them. An empty from lists the history of to.
+func (s *GitService) Log(workingDir, from, to string, limit int) ([]GitCommit, error) {
+	repo, err := git.PlainOpen(workingDir)
+	if err != nil {
+		slog.Error("Service operation failed",
+			"layer", "git",
+			"operation", "git_log",
+			"working_dir", workingDir,
+			"error", err)
+		return nil, err
+	}
+
+	toHash, err := repo.ResolveRevision(plumbing.Revision(to))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve commit %s: %w", to, err)
+	}
+
+	// Commits already deployed with from
+	excluded := make(map[plumbing.Hash]bool)
+	if from != "" {
+		fromHash, err := repo.ResolveRevision(plumbing.Revision(from))
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve commit %s: %w", from, err)
+		}
+		history, err := repo.Log(&git.LogOptions{From: *fromHash})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read history of %s: %w", from, err)
+		}
+		err = history.ForEach(func(commit *object.Commit) error {
+			excluded[commit.Hash] = true
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read history of %s: %w", from, err)
+		}
+	}
+
+	history, err := repo.Log(&git.LogOptions{From: *toHash})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history of %s: %w", to, err)
+	}
+	commits := []GitCommit{}
+	err = history.ForEach(func(commit *object.Commit) error {
+		if len(commits) >= limit {
+			return storer.ErrStop
+		}
+		if excluded[commit.Hash] {
+			return nil
+		}
+		message, _, _ := strings.Cut(strings.TrimSpace(commit.Message), "\n")
+		commits = append(commits, GitCommit{
+			Hash:    commit.Hash.String(),
+			Author:  commit.Author.Name,
+			Date:    commit.Author.When,
+			Message: message,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history of %s: %w", to, err)
+	}
+	return commits, nil
+}
+
+// Export writes the files of commit to dir, leaving the checkout of workingDir as it is. Submodules are left out.
+func (s *GitService) Export(commit, workingDir, dir string) error {
+	repo, err := git.PlainOpen(workingDir)
+	if err != nil {
+		slog.Error("Service operation failed",
+			"layer", "git",
+			"operation", "git_export",
+			"commit", commit,
+			"working_dir", workingDir,
+			"error", err)
+		return err
+	}
+
+	hash, err := repo.ResolveRevision(plumbing.Revision(commit))
+	if err != nil {
+		return fmt.Errorf("failed to resolve commit %s: %w", commit, err)
+	}
+	commitObject, err := repo.CommitObject(*hash)
+	if err != nil {
+		return fmt.Errorf("failed to read commit %s: %w", commit, err)
+	}
+	tree, err := commitObject.Tree()
+	if err != nil {
+		return fmt.Errorf("failed to read commit %s: %w", commit, err)
+	}
+
+	err = tree.Files().ForEach(func(file *object.File) error {
+		if !filepath.IsLocal(file.Name) {
+			return fmt.Errorf("file %s is outside of the repository", file.Name)
+		}
+		return exportFile(file, filepath.Join(dir, filepath.FromSlash(file.Name)))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to export commit %s: %w", commit, err)
+	}
+	return nil
+}
+
+// exportFile writes a file of a commit to path
+func exportFile(file *object.File, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	if file.Mode == filemode.Symlink {
+		target, err := file.Contents()
+		if err != nil {
+			return err
+		}
+		return os.Symlink(target, path)
+	}
+
+	perm := os.FileMode(0o644)
+	if file.Mode == filemode.Executable {
+		perm = 0o755
+	}
+	reader, err := file.Reader()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = reader.Close() }()
+	out, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, reader); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
 // TestAuthentication tests Git authentication using ls-remote operation
 // This is more resistant to credential caching than clone operations
 func (s *GitService) TestAuthentication(gitURL string, gitAuth *GitAuthConfig) error {
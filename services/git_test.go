@@ -1,8 +1,14 @@
 package services
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
 )
 
 func TestGitService_GetLatestCommit_InvalidRepo(t *testing.T) {
@@ -239,3 +245,241 @@ func TestGitService_GetRemoteLatestCommit_WithBranch(t *testing.T) {
 		t.Errorf("GetRemoteLatestCommit() expected error for non-existent repository with empty branch")
 	}
 }
+
+func TestGitService_Checkout_InvalidRepo(t *testing.T) {
+	config := &Config{
+		GitTimeout: 5 * time.Minute,
+	}
+	service := NewGitService(config)
+
+	// Test with non-existent directory
+	err := service.Checkout("abc123", "/non/existent/path")
+	if err == nil {
+		t.Errorf("Checkout() expected error for non-existent repository")
+	}
+}
+
+func TestGitService_Checkout_KeepsBranch(t *testing.T) {
+	service := NewGitService(&Config{GitTimeout: 5 * time.Minute})
+	repoDir := t.TempDir()
+
+	repo, err := git.PlainInit(repoDir, false)
+	if err != nil {
+		t.Fatalf("Failed to init repository: %v", err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("Failed to get worktree: %v", err)
+	}
+
+	// Create two commits, each with its own version of a file
+	var commits []plumbing.Hash
+	for _, content := range []string{"v1", "v2"} {
+		if err := os.WriteFile(filepath.Join(repoDir, "version"), []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+		if _, err := worktree.Add("version"); err != nil {
+			t.Fatalf("Failed to stage file: %v", err)
+		}
+		commit, err := worktree.Commit(content, &git.CommitOptions{
+			Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+		})
+		if err != nil {
+			t.Fatalf("Failed to commit: %v", err)
+		}
+		commits = append(commits, commit)
+	}
+
+	if err := service.Checkout(commits[0].String()[:8], repoDir); err != nil {
+		t.Fatalf("Checkout() unexpected error: %v", err)
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatalf("Failed to get HEAD: %v", err)
+	}
+	if head.Hash() != commits[0] {
+		t.Errorf("Checkout() HEAD = %s, want %s", head.Hash(), commits[0])
+	}
+	if !head.Name().IsBranch() {
+		t.Errorf("Checkout() detached HEAD, want branch to stay checked out")
+	}
+
+	content, err := os.ReadFile(filepath.Join(repoDir, "version"))
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	if string(content) != "v1" {
+		t.Errorf("Checkout() working tree content = %q, want %q", content, "v1")
+	}
+}
+
+func TestGitService_FetchTags_InvalidRepo(t *testing.T) {
+	service := NewGitService(&Config{GitTimeout: 5 * time.Minute})
+
+	err := service.FetchTags(nil, "/non/existent/path")
+	if err == nil {
+		t.Errorf("FetchTags() expected error for non-existent repository")
+	}
+}
+
+func TestGitService_ListTags(t *testing.T) {
+	service := NewGitService(&Config{GitTimeout: 5 * time.Minute})
+	repoDir := t.TempDir()
+
+	repo, err := git.PlainInit(repoDir, false)
+	if err != nil {
+		t.Fatalf("Failed to init repository: %v", err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("Failed to get worktree: %v", err)
+	}
+	signature := &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()}
+	commit, err := worktree.Commit("initial", &git.CommitOptions{Author: signature, AllowEmptyCommits: true})
+	if err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+
+	if _, err := repo.CreateTag("v1.0.0", commit, nil); err != nil {
+		t.Fatalf("Failed to create lightweight tag: %v", err)
+	}
+	annotated := &git.CreateTagOptions{Tagger: signature, Message: "v1.1.0"}
+	if _, err := repo.CreateTag("v1.1.0", commit, annotated); err != nil {
+		t.Fatalf("Failed to create annotated tag: %v", err)
+	}
+
+	tags, err := service.ListTags(repoDir)
+	if err != nil {
+		t.Fatalf("ListTags() unexpected error: %v", err)
+	}
+
+	want := map[string]string{"v1.0.0": commit.String(), "v1.1.0": commit.String()}
+	if len(tags) != len(want) {
+		t.Fatalf("ListTags() = %v, want %v", tags, want)
+	}
+	for name, hash := range want {
+		if tags[name] != hash {
+			t.Errorf("ListTags()[%q] = %q, want %q", name, tags[name], hash)
+		}
+	}
+}
+
+// commitFiles creates a commit of repoDir with the given files, returning its hash
+func commitFiles(t *testing.T, repo *git.Repository, repoDir, message string, files map[string]string) plumbing.Hash {
+	t.Helper()
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("Failed to get worktree: %v", err)
+	}
+	for name, content := range files {
+		path := filepath.Join(repoDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+		if _, err := worktree.Add(name); err != nil {
+			t.Fatalf("Failed to stage file: %v", err)
+		}
+	}
+	commit, err := worktree.Commit(message, &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+	})
+	if err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+	return commit
+}
+
+func TestGitService_Log(t *testing.T) {
+	service := NewGitService(&Config{GitTimeout: 5 * time.Minute})
+	repoDir := t.TempDir()
+
+	repo, err := git.PlainInit(repoDir, false)
+	if err != nil {
+		t.Fatalf("Failed to init repository: %v", err)
+	}
+	var commits []plumbing.Hash
+	for _, message := range []string{"first", "second", "third\n\nWith a body", "fourth"} {
+		commits = append(commits, commitFiles(t, repo, repoDir, message, map[string]string{"version": message}))
+	}
+
+	log, err := service.Log(repoDir, commits[1].String(), commits[3].String(), 50)
+	if err != nil {
+		t.Fatalf("Log() unexpected error: %v", err)
+	}
+	if len(log) != 2 {
+		t.Fatalf("Log() returned %d commits, want 2", len(log))
+	}
+	if log[0].Hash != commits[3].String() || log[0].Message != "fourth" {
+		t.Errorf("Log()[0] = %+v, want commit %s with message %q", log[0], commits[3], "fourth")
+	}
+	if log[1].Hash != commits[2].String() || log[1].Message != "third" {
+		t.Errorf("Log()[1] = %+v, want commit %s with message %q", log[1], commits[2], "third")
+	}
+	if log[0].Author != "test" {
+		t.Errorf("Log()[0].Author = %q, want %q", log[0].Author, "test")
+	}
+
+	// Without a starting commit the whole history is listed, up to the limit
+	log, err = service.Log(repoDir, "", commits[3].String(), 3)
+	if err != nil {
+		t.Fatalf("Log() unexpected error: %v", err)
+	}
+	if len(log) != 3 {
+		t.Errorf("Log() returned %d commits, want 3", len(log))
+	}
+
+	// Going back in history lists no commits
+	log, err = service.Log(repoDir, commits[3].String(), commits[1].String(), 50)
+	if err != nil {
+		t.Fatalf("Log() unexpected error: %v", err)
+	}
+	if len(log) != 0 {
+		t.Errorf("Log() returned %d commits, want 0", len(log))
+	}
+
+	if _, err := service.Log(repoDir, "", "unknown", 50); err == nil {
+		t.Errorf("Log() expected error for unknown commit")
+	}
+}
+
+func TestGitService_Export(t *testing.T) {
+	service := NewGitService(&Config{GitTimeout: 5 * time.Minute})
+	repoDir := t.TempDir()
+
+	repo, err := git.PlainInit(repoDir, false)
+	if err != nil {
+		t.Fatalf("Failed to init repository: %v", err)
+	}
+	first := commitFiles(t, repo, repoDir, "first", map[string]string{
+		"compose.yaml":    "v1",
+		"config/app.conf": "setting=1",
+	})
+	commitFiles(t, repo, repoDir, "second", map[string]string{"compose.yaml": "v2"})
+
+	dir := t.TempDir()
+	if err := service.Export(first.String(), repoDir, dir); err != nil {
+		t.Fatalf("Export() unexpected error: %v", err)
+	}
+	for name, want := range map[string]string{"compose.yaml": "v1", "config/app.conf": "setting=1"} {
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("Failed to read exported file: %v", err)
+		}
+		if string(content) != want {
+			t.Errorf("Export() %s content = %q, want %q", name, content, want)
+		}
+	}
+
+	// The checkout is left as it is
+	content, err := os.ReadFile(filepath.Join(repoDir, "compose.yaml"))
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	if string(content) != "v2" {
+		t.Errorf("Export() changed working tree content to %q, want %q", content, "v2")
+	}
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// GitRefType selects what a project deploys from its repository
+type GitRefType int
+
+const (
+	GitRefTypeBranch GitRefType = iota // Head of the project's branch
+	GitRefTypeTag                      // Newest tag matching a pattern or semver constraint
+	GitRefTypeCommit                   // A pinned commit
+)
+
+// ErrInvalidGitRef is returned when a tag pattern or commit SHA is not usable for its ref type
+var ErrInvalidGitRef = errors.New("invalid git ref")
+
+// ErrNoMatchingTag is returned when no tag of the repository matches a project's tag pattern
+var ErrNoMatchingTag = errors.New("no matching tag")
+
+func (t GitRefType) String() string {
+	switch t {
+	case GitRefTypeBranch:
+		return "branch"
+	case GitRefTypeTag:
+		return "tag"
+	case GitRefTypeCommit:
+		return "commit"
+	default:
+		return "branch"
+	}
+}
+
+func ParseGitRefType(s string) (GitRefType, error) {
+	switch s {
+	case "branch", "":
+		return GitRefTypeBranch, nil
+	case "tag":
+		return GitRefTypeTag, nil
+	case "commit":
+		return GitRefTypeCommit, nil
+	default:
+		return GitRefTypeBranch, fmt.Errorf("invalid git ref type: %q", s)
+	}
+}
+
+// ValidateGitRef checks that ref can be used with refType: tag mode needs a pattern, a constraint or a
+// tag name, commit mode a full commit SHA. Branch mode uses the project's branch and takes no ref.
+func ValidateGitRef(refType GitRefType, ref string) error {
+	switch refType {
+	case GitRefTypeTag:
+		if ref == "" {
+			return fmt.Errorf("%w: a tag pattern is required", ErrInvalidGitRef)
+		}
+		if isTagGlob(ref) {
+			if _, err := path.Match(ref, ""); err != nil {
+				return fmt.Errorf("%w: malformed tag pattern %q", ErrInvalidGitRef, ref)
+			}
+		}
+	case GitRefTypeCommit:
+		if !isFullCommitSHA(ref) {
+			return fmt.Errorf("%w: %q is not a full commit SHA", ErrInvalidGitRef, ref)
+		}
+	default:
+		if ref != "" {
+			return fmt.Errorf("%w: branch tracking does not take a ref", ErrInvalidGitRef)
+		}
+	}
+	return nil
+}
+
+// TagMatches reports whether tag is selected by pattern, which is a glob like "v*", a semver constraint
+// like "~1.4" or an exact tag name
+func TagMatches(pattern, tag string) bool {
+	if tag == pattern {
+		return true
+	}
+	if isTagGlob(pattern) {
+		matched, _ := path.Match(pattern, tag)
+		return matched
+	}
+	constraint, err := semver.NewConstraint(pattern)
+	if err != nil {
+		return false
+	}
+	version, err := semver.NewVersion(tag)
+	if err != nil {
+		return false
+	}
+	return constraint.Check(version)
+}
+
+// NewestMatchingTag returns the newest of tags selected by pattern. Tags that are semantic versions are
+// ordered by version and rank above other tags, which are ordered by name.
+func NewestMatchingTag(tags []string, pattern string) (string, error) {
+	var newest string
+	var newestVersion *semver.Version
+	for _, tag := range tags {
+		if !TagMatches(pattern, tag) {
+			continue
+		}
+		version, err := semver.NewVersion(tag)
+		if err != nil {
+			version = nil
+		}
+		switch {
+		case newest == "":
+		case version != nil && (newestVersion == nil || version.GreaterThan(newestVersion)):
+		case version == nil && newestVersion == nil && tag > newest:
+		default:
+			continue
+		}
+		newest, newestVersion = tag, version
+	}
+	if newest == "" {
+		return "", fmt.Errorf("%w: %q", ErrNoMatchingTag, pattern)
+	}
+	return newest, nil
+}
+
+// FetchTargetCommit fetches from the remote and returns the commit the project should run, along with a
+// description of the ref it was taken from: the branch, the newest matching tag, or the pinned commit
+func FetchTargetCommit(gitService GitExecutor, project *Project) (string, string, error) {
+	gitDir, err := project.GitDir()
+	if err != nil {
+		return "", "", err
+	}
+
+	switch project.GitRefType {
+	case GitRefTypeTag:
+		if err := gitService.FetchTags(project.GitAuth, gitDir); err != nil {
+			countGitFetchError(project)
+			return "", "", fmt.Errorf("failed to fetch tags from remote: %w", err)
+		}
+		tags, err := gitService.ListTags(gitDir)
+		if err != nil {
+			return "", "", err
+		}
+		names := make([]string, 0, len(tags))
+		for name := range tags {
+			names = append(names, name)
+		}
+		sort.Strings(names) // Equal versions like "1.0" and "v1.0" resolve the same way every time
+		tag, err := NewestMatchingTag(names, project.GitRef)
+		if err != nil {
+			return "", "", err
+		}
+		return tags[tag], "tag " + tag, nil
+	case GitRefTypeCommit:
+		// The pinned commit only has to be present locally, fetching the branch brings in new history
+		if err := gitService.Fetch(project.GitBranch, project.GitAuth, gitDir); err != nil {
+			countGitFetchError(project)
+			return "", "", fmt.Errorf("failed to fetch from remote: %w", err)
+		}
+		return project.GitRef, "commit " + shortCommit(project.GitRef), nil
+	default:
+		if err := gitService.Fetch(project.GitBranch, project.GitAuth, gitDir); err != nil {
+			countGitFetchError(project)
+			return "", "", fmt.Errorf("failed to fetch from remote: %w", err)
+		}
+		commit, err := gitService.GetRemoteLatestCommit(gitDir, project.GitBranch)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to get remote commit: %w", err)
+		}
+		return commit, "branch " + project.GitBranch, nil
+	}
+}
+
+// GitRefDescription describes what the project tracks, e.g. "branch main" or "tag ~1.4"
+func (p *Project) GitRefDescription() string {
+	switch p.GitRefType {
+	case GitRefTypeTag:
+		return "tag " + p.GitRef
+	case GitRefTypeCommit:
+		return "commit " + shortCommit(p.GitRef)
+	default:
+		return "branch " + p.GitBranch
+	}
+}
+
+func isTagGlob(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[")
+}
+
+func isFullCommitSHA(s string) bool {
+	if len(s) != 40 && len(s) != 64 {
+		return false
+	}
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPinnedCommit = "0123456789abcdef0123456789abcdef01234567"
+
+func TestGitRefType_String(t *testing.T) {
+	assert.Equal(t, "branch", GitRefTypeBranch.String())
+	assert.Equal(t, "tag", GitRefTypeTag.String())
+	assert.Equal(t, "commit", GitRefTypeCommit.String())
+}
+
+func TestParseGitRefType(t *testing.T) {
+	tests := []struct {
+		input       string
+		expected    GitRefType
+		expectError bool
+	}{
+		{input: "branch", expected: GitRefTypeBranch},
+		{input: "", expected: GitRefTypeBranch},
+		{input: "tag", expected: GitRefTypeTag},
+		{input: "commit", expected: GitRefTypeCommit},
+		{input: "sha", expected: GitRefTypeBranch, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			refType, err := ParseGitRefType(tt.input)
+			assert.Equal(t, tt.expected, refType)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateGitRef(t *testing.T) {
+	tests := []struct {
+		name        string
+		refType     GitRefType
+		ref         string
+		expectError bool
+	}{
+		{name: "branch", refType: GitRefTypeBranch},
+		{name: "branch with ref", refType: GitRefTypeBranch, ref: "v*", expectError: true},
+		{name: "tag glob", refType: GitRefTypeTag, ref: "v*"},
+		{name: "tag constraint", refType: GitRefTypeTag, ref: "~1.4"},
+		{name: "exact tag", refType: GitRefTypeTag, ref: "release-2024"},
+		{name: "malformed tag glob", refType: GitRefTypeTag, ref: "v[", expectError: true},
+		{name: "empty tag", refType: GitRefTypeTag, expectError: true},
+		{name: "commit", refType: GitRefTypeCommit, ref: testPinnedCommit},
+		{name: "short commit", refType: GitRefTypeCommit, ref: "0123456", expectError: true},
+		{name: "uppercase commit", refType: GitRefTypeCommit, ref: strings.ToUpper(testPinnedCommit), expectError: true},
+		{name: "empty commit", refType: GitRefTypeCommit, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateGitRef(tt.refType, tt.ref)
+			if tt.expectError {
+				assert.ErrorIs(t, err, ErrInvalidGitRef)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTagMatches(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		tag      string
+		expected bool
+	}{
+		{pattern: "v*", tag: "v1.0.0", expected: true},
+		{pattern: "v*", tag: "release-1", expected: false},
+		{pattern: "release-*", tag: "release-1", expected: true},
+		{pattern: "~1.4", tag: "v1.4.7", expected: true},
+		{pattern: "~1.4", tag: "1.4.0", expected: true},
+		{pattern: "~1.4", tag: "v1.5.0", expected: false},
+		{pattern: "~1.4", tag: "latest", expected: false},
+		{pattern: ">=2.0.0", tag: "v2.1.0", expected: true},
+		{pattern: "stable", tag: "stable", expected: true},
+		{pattern: "stable", tag: "unstable", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.tag, func(t *testing.T) {
+			assert.Equal(t, tt.expected, TagMatches(tt.pattern, tt.tag))
+		})
+	}
+}
+
+func TestNewestMatchingTag(t *testing.T) {
+	tags := []string{"v1.4.0", "v1.10.0", "v1.4.12", "v1.9.3", "v2.0.0-rc.1", "nightly-b", "nightly-a", "latest"}
+
+	tests := []struct {
+		pattern  string
+		expected string
+	}{
+		{pattern: "v*", expected: "v2.0.0-rc.1"},
+		{pattern: "~1.4", expected: "v1.4.12"},
+		{pattern: "^1", expected: "v1.10.0"},
+		{pattern: "nightly-*", expected: "nightly-b"},
+		{pattern: "*", expected: "v2.0.0-rc.1"},
+		{pattern: "latest", expected: "latest"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			tag, err := NewestMatchingTag(tags, tt.pattern)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, tag)
+		})
+	}
+
+	_, err := NewestMatchingTag(tags, "~3.0")
+	assert.ErrorIs(t, err, ErrNoMatchingTag)
+}
+
+func TestFetchTargetCommit(t *testing.T) {
+	t.Run("branch", func(t *testing.T) {
+		project := createTestProject()
+		fetchedBranch := ""
+		gitService := &MockGitExecutor{
+			FetchFunc: func(gitBranch string, gitAuth *GitAuthConfig, workingDir string) error {
+				fetchedBranch = gitBranch
+				return nil
+			},
+		}
+
+		commit, ref, err := FetchTargetCommit(gitService, project)
+
+		require.NoError(t, err)
+		assert.Equal(t, project.GitBranch, fetchedBranch)
+		assert.Equal(t, "mock-remote-commit-hash", commit)
+		assert.Equal(t, "branch "+project.GitBranch, ref)
+	})
+
+	t.Run("tag", func(t *testing.T) {
+		project := createTestProject()
+		project.GitRefType = GitRefTypeTag
+		project.GitRef = "~1.4"
+		gitService := &MockGitExecutor{
+			ListTagsFunc: func(workingDir string) (map[string]string, error) {
+				return map[string]string{"v1.4.1": "aaa", "v1.4.2": "bbb", "v1.5.0": "ccc"}, nil
+			},
+		}
+
+		commit, ref, err := FetchTargetCommit(gitService, project)
+
+		require.NoError(t, err)
+		assert.Equal(t, "bbb", commit)
+		assert.Equal(t, "tag v1.4.2", ref)
+	})
+
+	t.Run("tag fetch fails", func(t *testing.T) {
+		project := createTestProject()
+		project.GitRefType = GitRefTypeTag
+		project.GitRef = "v*"
+		gitService := &MockGitExecutor{
+			FetchTagsFunc: func(gitAuth *GitAuthConfig, workingDir string) error {
+				return errors.New("network unreachable")
+			},
+		}
+
+		_, _, err := FetchTargetCommit(gitService, project)
+
+		assert.ErrorContains(t, err, "failed to fetch tags from remote")
+	})
+
+	t.Run("commit", func(t *testing.T) {
+		project := createTestProject()
+		project.GitRefType = GitRefTypeCommit
+		project.GitRef = testPinnedCommit
+
+		commit, ref, err := FetchTargetCommit(&MockGitExecutor{}, project)
+
+		require.NoError(t, err)
+		assert.Equal(t, testPinnedCommit, commit)
+		assert.Equal(t, "commit 01234567", ref)
+	})
+}
+
+func TestProject_GitRefDescription(t *testing.T) {
+	project := &Project{GitBranch: "main"}
+	assert.Equal(t, "branch main", project.GitRefDescription())
+
+	project.GitRefType, project.GitRef = GitRefTypeTag, "v*"
+	assert.Equal(t, "tag v*", project.GitRefDescription())
+
+	project.GitRefType, project.GitRef = GitRefTypeCommit, testPinnedCommit
+	assert.Equal(t, "commit 01234567", project.GitRefDescription())
+}
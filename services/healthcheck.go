@@ -0,0 +1,366 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	// healthCheckInterval is how long to wait before trying a health check that did not pass again
+	healthCheckInterval = 2 * time.Second
+	// healthCheckAttemptTimeout limits a single attempt of a health check
+	healthCheckAttemptTimeout = 10 * time.Second
+	// healthCheckMaxBody is how much of an HTTP response is searched for the expected text
+	healthCheckMaxBody = 1 << 20
+)
+
+var (
+	// ErrInvalidHealthCheck is returned for health checks that can't be run
+	ErrInvalidHealthCheck = errors.New("invalid health check")
+	// ErrHealthCheckFailed is returned when a health check of a deployment did not pass in time
+	ErrHealthCheckFailed = errors.New("health check failed")
+)
+
+// HealthCheckType is what a health check probes
+type HealthCheckType int
+
+const (
+	HealthCheckTypeUnknown HealthCheckType = iota
+	HealthCheckTypeHTTP
+	HealthCheckTypeTCP
+	HealthCheckTypeCommand
+)
+
+func (t HealthCheckType) String() string {
+	switch t {
+	case HealthCheckTypeHTTP:
+		return "http"
+	case HealthCheckTypeTCP:
+		return "tcp"
+	case HealthCheckTypeCommand:
+		return "command"
+	default:
+		return "unknown"
+	}
+}
+
+// HealthCheck verifies that a deployed project works, beyond the healthchecks of its containers. Health checks
+// are written one per line, as parsed by ParseHealthCheck.
+type HealthCheck struct {
+	Type    HealthCheckType
+	URL     string // URL requested by HTTP checks
+	Status  int    // HTTP status expected by HTTP checks, 0 for any 2xx status
+	Body    string // Text the body of the response must contain for HTTP checks
+	Address string // host:port connected to by TCP checks
+	Service string // Service whose container runs the command of command checks
+	Command string // Shell command of command checks, passing when it exits with 0
+}
+
+// ParseHealthCheck parses a health check written as one of
+//
+//	http <url> [<status>] [<text>]
+//	tcp <host>:<port>
+//	command <service> <command>
+func ParseHealthCheck(s string) (HealthCheck, error) {
+	kind, rest := cutField(strings.TrimSpace(s))
+
+	var check HealthCheck
+	switch kind {
+	case "http":
+		check.Type = HealthCheckTypeHTTP
+		check.URL, rest = cutField(rest)
+		if field, text := cutField(rest); len(field) == 3 {
+			if status, err := strconv.Atoi(field); err == nil {
+				check.Status = status
+				rest = text
+			}
+		}
+		check.Body = rest
+	case "tcp":
+		check.Type = HealthCheckTypeTCP
+		check.Address, rest = cutField(rest)
+		if rest != "" {
+			return HealthCheck{}, fmt.Errorf(
+				"%w: unexpected %q after the address of %q", ErrInvalidHealthCheck, rest, s)
+		}
+	case "command":
+		check.Type = HealthCheckTypeCommand
+		check.Service, check.Command = cutField(rest)
+	default:
+		return HealthCheck{}, fmt.Errorf("%w: %q must start with http, tcp or command", ErrInvalidHealthCheck, s)
+	}
+
+	if err := check.Validate(); err != nil {
+		return HealthCheck{}, err
+	}
+	return check, nil
+}
+
+// ParseHealthChecks parses health checks written one per string, skipping empty ones
+func ParseHealthChecks(lines []string) ([]HealthCheck, error) {
+	var checks []HealthCheck
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		check, err := ParseHealthCheck(line)
+		if err != nil {
+			return nil, err
+		}
+		checks = append(checks, check)
+	}
+	return checks, nil
+}
+
+// validateHealthChecks validates each of checks
+func validateHealthChecks(checks []HealthCheck) error {
+	for _, check := range checks {
+		if err := check.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FormatHealthChecks writes health checks one per string, the way ParseHealthChecks reads them
+func FormatHealthChecks(checks []HealthCheck) []string {
+	lines := make([]string, len(checks))
+	for i, check := range checks {
+		lines[i] = check.String()
+	}
+	return lines
+}
+
+// cutField cuts the first whitespace-separated field off s
+func cutField(s string) (string, string) {
+	i := strings.IndexFunc(s, unicode.IsSpace)
+	if i < 0 {
+		return s, ""
+	}
+	return s[:i], strings.TrimSpace(s[i:])
+}
+
+// Validate checks that the health check has what its type needs
+func (c HealthCheck) Validate() error {
+	switch c.Type {
+	case HealthCheckTypeHTTP:
+		u, err := url.Parse(c.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: %q is not an http or https URL", ErrInvalidHealthCheck, c.URL)
+		}
+		if c.Status != 0 && (c.Status < 100 || c.Status > 599) {
+			return fmt.Errorf("%w: %d is not an HTTP status", ErrInvalidHealthCheck, c.Status)
+		}
+	case HealthCheckTypeTCP:
+		host, port, err := net.SplitHostPort(c.Address)
+		if err != nil || host == "" || port == "" {
+			return fmt.Errorf("%w: %q is not a host:port address", ErrInvalidHealthCheck, c.Address)
+		}
+	case HealthCheckTypeCommand:
+		if c.Service == "" || c.Command == "" {
+			return fmt.Errorf("%w: command checks need a service and a command", ErrInvalidHealthCheck)
+		}
+	default:
+		return fmt.Errorf("%w: unknown type", ErrInvalidHealthCheck)
+	}
+	return nil
+}
+
+// String writes the health check the way ParseHealthCheck reads it
+func (c HealthCheck) String() string {
+	fields := []string{c.Type.String()}
+	switch c.Type {
+	case HealthCheckTypeHTTP:
+		fields = append(fields, c.URL)
+		if c.Status != 0 {
+			fields = append(fields, strconv.Itoa(c.Status))
+		}
+		if c.Body != "" {
+			fields = append(fields, c.Body)
+		}
+	case HealthCheckTypeTCP:
+		fields = append(fields, c.Address)
+	case HealthCheckTypeCommand:
+		fields = append(fields, c.Service, c.Command)
+	}
+	return strings.Join(fields, " ")
+}
+
+// HealthCheckResult is the outcome of a health check of a deployment
+type HealthCheckResult struct {
+	Check    string `json:"check"` // The health check, as written in the project
+	Passed   bool   `json:"passed"`
+	Message  string `json:"message"` // What the last attempt found, such as the HTTP status or an error
+	Attempts int    `json:"attempts"`
+}
+
+// execFunc runs a shell command in a container of a service of the project, returning its output
+type execFunc func(ctx context.Context, service, command string) (string, error)
+
+// checkHealth runs the health checks of a project Docker Compose has started, reporting their results in the
+// output of the deployment
+func (s *ProjectService) checkHealth(
+	ctx context.Context,
+	project *Project,
+	composeProject *ComposeProject,
+	output *deploymentOutput,
+) ([]HealthCheckResult, error) {
+	output.send(fmt.Sprintf("Running %d health check(s)...", len(project.HealthChecks)), "info", "oar")
+
+	report := func(result HealthCheckResult) {
+		if result.Passed {
+			output.send(fmt.Sprintf("Health check passed: %s (%s)", result.Check, result.Message), "success", "oar")
+			return
+		}
+		output.send(fmt.Sprintf("Health check failed after %d attempt(s): %s (%s)",
+			result.Attempts, result.Check, result.Message), "error", "oar")
+	}
+	return runHealthChecks(ctx, project.HealthChecks, composeProject.Timeouts.Health, composeProject.Exec, report)
+}
+
+// runHealthChecks runs health checks one after the other, trying each again until it passes or timeout has
+// passed since the first attempt of the first one, a zero timeout trying forever. Each result is passed to
+// report as soon as it is known. A health check that does not pass in time returns ErrHealthCheckFailed along
+// with the results so far.
+func runHealthChecks(
+	ctx context.Context,
+	checks []HealthCheck,
+	timeout time.Duration,
+	exec execFunc,
+	report func(HealthCheckResult),
+) ([]HealthCheckResult, error) {
+	deadline := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		deadline, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	results := make([]HealthCheckResult, 0, len(checks))
+	for _, check := range checks {
+		result := HealthCheckResult{Check: check.String()}
+		for {
+			result.Attempts++
+			attempt, cancelAttempt := context.WithTimeout(deadline, healthCheckAttemptTimeout)
+			message, err := check.probe(attempt, exec)
+			cancelAttempt()
+
+			if err == nil {
+				result.Passed, result.Message = true, message
+				break
+			}
+			result.Message = err.Error()
+			if !waitForRetry(deadline) {
+				break
+			}
+		}
+
+		results = append(results, result)
+		report(result)
+
+		if !result.Passed {
+			if ctx.Err() != nil {
+				return results, ctx.Err()
+			}
+			slog.Warn("Health check did not pass",
+				"check", result.Check,
+				"attempts", result.Attempts,
+				"message", result.Message)
+			return results, fmt.Errorf("%w: %s did not pass within %s: %s",
+				ErrHealthCheckFailed, result.Check, timeout, result.Message)
+		}
+	}
+	return results, nil
+}
+
+// waitForRetry waits until a health check can be tried again, returning false if ctx is done first
+func waitForRetry(ctx context.Context) bool {
+	timer := time.NewTimer(healthCheckInterval)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// probe runs one attempt of the health check, describing what it found
+func (c HealthCheck) probe(ctx context.Context, exec execFunc) (string, error) {
+	switch c.Type {
+	case HealthCheckTypeHTTP:
+		return c.probeHTTP(ctx)
+	case HealthCheckTypeTCP:
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", c.Address)
+		if err != nil {
+			return "", err
+		}
+		_ = conn.Close()
+		return "connected", nil
+	case HealthCheckTypeCommand:
+		output, err := exec(ctx, c.Service, c.Command)
+		output = lastLine(output)
+		if err != nil {
+			if output != "" {
+				return "", fmt.Errorf("%w: %s", err, output)
+			}
+			return "", err
+		}
+		if output == "" {
+			output = "exit status 0"
+		}
+		return output, nil
+	default:
+		return "", fmt.Errorf("%w: unknown type", ErrInvalidHealthCheck)
+	}
+}
+
+// probeHTTP requests the URL of an HTTP check
+func (c HealthCheck) probeHTTP(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.URL, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	status := fmt.Sprintf("HTTP %d", resp.StatusCode)
+	switch {
+	case c.Status != 0 && resp.StatusCode != c.Status:
+		return "", fmt.Errorf("%s, expected %d", status, c.Status)
+	case c.Status == 0 && (resp.StatusCode < 200 || resp.StatusCode > 299):
+		return "", fmt.Errorf("%s, expected 2xx", status)
+	}
+
+	if c.Body != "" {
+		body, err := io.ReadAll(io.LimitReader(resp.Body, healthCheckMaxBody))
+		if err != nil {
+			return "", fmt.Errorf("%s, failed to read body: %w", status, err)
+		}
+		if !strings.Contains(string(body), c.Body) {
+			return "", fmt.Errorf("%s without %q in the body", status, c.Body)
+		}
+	}
+	return status, nil
+}
+
+// lastLine returns the last non-empty line of output
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
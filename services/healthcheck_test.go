@@ -0,0 +1,216 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHealthCheck(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    HealthCheck
+		expectedErr string
+	}{
+		{
+			name:     "http",
+			input:    "http http://localhost:8080/health",
+			expected: HealthCheck{Type: HealthCheckTypeHTTP, URL: "http://localhost:8080/health"},
+		},
+		{
+			name:  "http with status and text",
+			input: "  http https://example.com/status 204   all systems go ",
+			expected: HealthCheck{
+				Type:   HealthCheckTypeHTTP,
+				URL:    "https://example.com/status",
+				Status: 204,
+				Body:   "all systems go",
+			},
+		},
+		{
+			name:     "http with text only",
+			input:    "http http://localhost/health ok",
+			expected: HealthCheck{Type: HealthCheckTypeHTTP, URL: "http://localhost/health", Body: "ok"},
+		},
+		{
+			name:     "tcp",
+			input:    "tcp db:5432",
+			expected: HealthCheck{Type: HealthCheckTypeTCP, Address: "db:5432"},
+		},
+		{
+			name:     "command",
+			input:    "command db pg_isready -U postgres",
+			expected: HealthCheck{Type: HealthCheckTypeCommand, Service: "db", Command: "pg_isready -U postgres"},
+		},
+		{name: "unknown type", input: "ping localhost", expectedErr: "must start with http, tcp or command"},
+		{name: "http without URL", input: "http", expectedErr: "is not an http or https URL"},
+		{name: "http with other scheme", input: "http ftp://localhost", expectedErr: "is not an http or https URL"},
+		{
+			name:        "http with invalid status",
+			input:       "http http://localhost 999",
+			expectedErr: "999 is not an HTTP status",
+		},
+		{name: "tcp without port", input: "tcp localhost", expectedErr: "is not a host:port address"},
+		{name: "tcp with extra fields", input: "tcp localhost:80 now", expectedErr: "unexpected"},
+		{name: "command without command", input: "command db", expectedErr: "need a service and a command"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check, err := ParseHealthCheck(tt.input)
+
+			if tt.expectedErr != "" {
+				assert.ErrorIs(t, err, ErrInvalidHealthCheck)
+				assert.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, check)
+
+			// Written back the way it is read
+			parsed, err := ParseHealthCheck(check.String())
+			require.NoError(t, err)
+			assert.Equal(t, check, parsed)
+		})
+	}
+}
+
+func TestParseHealthChecks(t *testing.T) {
+	checks, err := ParseHealthChecks([]string{"tcp db:5432", "", "  ", "command web true"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tcp db:5432", "command web true"}, FormatHealthChecks(checks))
+
+	_, err = ParseHealthChecks([]string{"tcp db:5432", "tcp db"})
+	assert.ErrorIs(t, err, ErrInvalidHealthCheck)
+}
+
+func TestRunHealthChecks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/health":
+			_, _ = fmt.Fprint(w, "status: ok")
+		case "/created":
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = listener.Close() }()
+
+	exec := func(ctx context.Context, service, command string) (string, error) {
+		if command == "true" {
+			return "ready\n", nil
+		}
+		return "not ready\n", errors.New("exit status 1")
+	}
+
+	tests := []struct {
+		name            string
+		check           string
+		expectedMessage string
+		expectedPassed  bool
+	}{
+		{name: "http", check: "http " + server.URL + "/health", expectedMessage: "HTTP 200", expectedPassed: true},
+		{
+			name:            "http with text",
+			check:           "http " + server.URL + "/health ok",
+			expectedMessage: "HTTP 200",
+			expectedPassed:  true,
+		},
+		{
+			name:            "http with status",
+			check:           "http " + server.URL + "/created 201",
+			expectedMessage: "HTTP 201",
+			expectedPassed:  true,
+		},
+		{
+			name:            "http with other status",
+			check:           "http " + server.URL + "/health 204",
+			expectedMessage: "HTTP 200, expected 204",
+		},
+		{name: "http failing", check: "http " + server.URL + "/down", expectedMessage: "HTTP 503, expected 2xx"},
+		{
+			name:            "http without text",
+			check:           "http " + server.URL + "/health degraded",
+			expectedMessage: `HTTP 200 without "degraded" in the body`,
+		},
+		{name: "tcp", check: "tcp " + listener.Addr().String(), expectedMessage: "connected", expectedPassed: true},
+		{name: "command", check: "command web true", expectedMessage: "ready", expectedPassed: true},
+		{name: "command failing", check: "command web false", expectedMessage: "exit status 1: not ready"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check, err := ParseHealthCheck(tt.check)
+			require.NoError(t, err)
+
+			var reported []HealthCheckResult
+			report := func(result HealthCheckResult) { reported = append(reported, result) }
+			results, err := runHealthChecks(
+				context.Background(), []HealthCheck{check}, 100*time.Millisecond, exec, report)
+
+			require.Len(t, results, 1)
+			assert.Equal(t, results, reported)
+			assert.Equal(t, tt.check, results[0].Check)
+			assert.Equal(t, tt.expectedPassed, results[0].Passed)
+			assert.Equal(t, tt.expectedMessage, results[0].Message)
+			assert.Equal(t, 1, results[0].Attempts)
+			if tt.expectedPassed {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrHealthCheckFailed)
+				assert.ErrorContains(t, err, "did not pass within 100ms: "+tt.expectedMessage)
+			}
+		})
+	}
+}
+
+func TestRunHealthChecks_StopsAtFailure(t *testing.T) {
+	checks, err := ParseHealthChecks([]string{"command web true", "command web false", "command web true"})
+	require.NoError(t, err)
+
+	exec := func(ctx context.Context, service, command string) (string, error) {
+		if command == "true" {
+			return "", nil
+		}
+		return "", errors.New("exit status 1")
+	}
+
+	results, err := runHealthChecks(
+		context.Background(), checks, 100*time.Millisecond, exec, func(HealthCheckResult) {})
+
+	assert.ErrorIs(t, err, ErrHealthCheckFailed)
+	require.Len(t, results, 2)
+	assert.True(t, results[0].Passed)
+	assert.Equal(t, "exit status 0", results[0].Message)
+	assert.False(t, results[1].Passed)
+}
+
+func TestRunHealthChecks_Cancelled(t *testing.T) {
+	check, err := ParseHealthCheck("command web false")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	exec := func(ctx context.Context, service, command string) (string, error) {
+		cancel()
+		return "", errors.New("exit status 1")
+	}
+
+	_, err = runHealthChecks(ctx, []HealthCheck{check}, time.Minute, exec, func(HealthCheckResult) {})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.NotErrorIs(t, err, ErrHealthCheckFailed)
+}
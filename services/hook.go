@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+var (
+	// ErrInvalidHook is returned for deployment hooks that can't be run
+	ErrInvalidHook = errors.New("invalid hook")
+	// ErrHookFailed is returned when a deployment hook exits with an error, aborting the deployment
+	ErrHookFailed = errors.New("hook failed")
+)
+
+// HookType is how a deployment hook runs its command
+type HookType int
+
+const (
+	HookTypeUnknown HookType = iota
+	HookTypeRun
+	HookTypeExec
+	HookTypeScript
+)
+
+func (t HookType) String() string {
+	switch t {
+	case HookTypeRun:
+		return "run"
+	case HookTypeExec:
+		return "exec"
+	case HookTypeScript:
+		return "script"
+	default:
+		return "unknown"
+	}
+}
+
+// HookPhase is when a deployment hook runs
+type HookPhase string
+
+const (
+	HookPhasePreDeploy  HookPhase = "pre-deploy"  // Before Docker Compose pulls images and starts the project
+	HookPhasePostDeploy HookPhase = "post-deploy" // After the project has started and passed its health checks
+)
+
+// Hook is a command run before or after Docker Compose deploys a project, such as a database migration. Hooks
+// are written one per line, as parsed by ParseHook.
+type Hook struct {
+	Type    HookType
+	Service string // Service the command runs in, empty for scripts
+	Command string // Shell command, run with sh
+}
+
+// ParseHook parses a deployment hook written as one of
+//
+//	run <service> <command>     in a new container of the service, removed afterwards
+//	exec <service> <command>    in the running container of the service
+//	script <command>            on the Oar host, in the directory of the repository
+func ParseHook(s string) (Hook, error) {
+	kind, rest := cutField(strings.TrimSpace(s))
+
+	var hook Hook
+	switch kind {
+	case "run":
+		hook.Type = HookTypeRun
+		hook.Service, hook.Command = cutField(rest)
+	case "exec":
+		hook.Type = HookTypeExec
+		hook.Service, hook.Command = cutField(rest)
+	case "script":
+		hook.Type = HookTypeScript
+		hook.Command = rest
+	default:
+		return Hook{}, fmt.Errorf("%w: %q must start with run, exec or script", ErrInvalidHook, s)
+	}
+
+	if err := hook.Validate(); err != nil {
+		return Hook{}, err
+	}
+	return hook, nil
+}
+
+// ParseHooks parses deployment hooks written one per string, skipping empty ones
+func ParseHooks(lines []string) ([]Hook, error) {
+	var hooks []Hook
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		hook, err := ParseHook(line)
+		if err != nil {
+			return nil, err
+		}
+		hooks = append(hooks, hook)
+	}
+	return hooks, nil
+}
+
+// validateHooks validates each of hooks
+func validateHooks(hooks []Hook) error {
+	for _, hook := range hooks {
+		if err := hook.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FormatHooks writes deployment hooks one per string, the way ParseHooks reads them
+func FormatHooks(hooks []Hook) []string {
+	lines := make([]string, len(hooks))
+	for i, hook := range hooks {
+		lines[i] = hook.String()
+	}
+	return lines
+}
+
+// Validate checks that the hook has what its type needs
+func (h Hook) Validate() error {
+	switch h.Type {
+	case HookTypeRun, HookTypeExec:
+		if h.Service == "" || h.Command == "" {
+			return fmt.Errorf("%w: %s hooks need a service and a command", ErrInvalidHook, h.Type)
+		}
+	case HookTypeScript:
+		if h.Command == "" {
+			return fmt.Errorf("%w: script hooks need a command", ErrInvalidHook)
+		}
+	default:
+		return fmt.Errorf("%w: unknown type", ErrInvalidHook)
+	}
+	return nil
+}
+
+// String writes the hook the way ParseHook reads it
+func (h Hook) String() string {
+	if h.Type == HookTypeScript {
+		return h.Type.String() + " " + h.Command
+	}
+	return strings.Join([]string{h.Type.String(), h.Service, h.Command}, " ")
+}
+
+// runHooks runs the hooks of a deployment phase one after the other, streaming their output into the output of
+// the deployment. The first hook that fails or runs longer than the hook timeout stops the others and returns
+// ErrHookFailed; cancelling ctx interrupts the running hook and returns the context's error.
+func (s *ProjectService) runHooks(
+	ctx context.Context,
+	phase HookPhase,
+	hooks []Hook,
+	composeProject *ComposeProject,
+	output *deploymentOutput,
+) error {
+	for _, hook := range hooks {
+		output.send(fmt.Sprintf("Running %s hook: %s", phase, hook), "info", "oar")
+
+		hookCtx, cancel := ctx, context.CancelFunc(func() {})
+		if s.config.HookTimeout > 0 {
+			hookCtx, cancel = context.WithTimeout(ctx, s.config.HookTimeout)
+		}
+		err := output.stream(func(outputChan chan<- string) error {
+			return composeProject.RunHookStreaming(hookCtx, hook, outputChan)
+		})
+		timedOut := errors.Is(hookCtx.Err(), context.DeadlineExceeded)
+		cancel()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if timedOut {
+				err = fmt.Errorf("timed out after %s", s.config.HookTimeout)
+			}
+			slog.Warn("Deployment hook failed",
+				"project_name", composeProject.Name,
+				"phase", phase,
+				"hook", hook.String(),
+				"error", err)
+			return fmt.Errorf("%w: %s hook %q: %w", ErrHookFailed, phase, hook, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHook(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    Hook
+		expectedErr string
+	}{
+		{
+			name:     "run",
+			input:    "run app ./manage.py migrate --noinput",
+			expected: Hook{Type: HookTypeRun, Service: "app", Command: "./manage.py migrate --noinput"},
+		},
+		{
+			name:     "exec",
+			input:    "  exec cache   redis-cli FLUSHALL ",
+			expected: Hook{Type: HookTypeExec, Service: "cache", Command: "redis-cli FLUSHALL"},
+		},
+		{
+			name:     "script",
+			input:    "script ./scripts/warm-cache.sh https://example.com",
+			expected: Hook{Type: HookTypeScript, Command: "./scripts/warm-cache.sh https://example.com"},
+		},
+		{name: "unknown type", input: "shell ls", expectedErr: "must start with run, exec or script"},
+		{name: "run without command", input: "run app", expectedErr: "run hooks need a service and a command"},
+		{name: "exec without service", input: "exec", expectedErr: "exec hooks need a service and a command"},
+		{name: "script without command", input: "script  ", expectedErr: "script hooks need a command"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hook, err := ParseHook(tt.input)
+
+			if tt.expectedErr != "" {
+				assert.ErrorIs(t, err, ErrInvalidHook)
+				assert.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, hook)
+
+			// Written back the way it is read
+			parsed, err := ParseHook(hook.String())
+			require.NoError(t, err)
+			assert.Equal(t, hook, parsed)
+		})
+	}
+}
+
+func TestParseHooks(t *testing.T) {
+	hooks, err := ParseHooks([]string{"run app migrate", "", " ", "script make warm"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"run app migrate", "script make warm"}, FormatHooks(hooks))
+
+	_, err = ParseHooks([]string{"run app migrate", "run app"})
+	assert.ErrorIs(t, err, ErrInvalidHook)
+}
+
+func TestProjectService_RunHooks_Timeout(t *testing.T) {
+	service, _, _, _, _ := setupMockProjectService(t)
+	service.config.HookTimeout = 100 * time.Millisecond
+
+	composeProject := createTestComposeProject()
+	composeProject.WorkingDir = t.TempDir()
+	hooks, err := ParseHooks([]string{"script exec sleep 10", "script echo never"})
+	require.NoError(t, err)
+
+	outputChan := make(chan string, 100)
+	output := &deploymentOutput{outputChan: outputChan}
+	start := time.Now()
+	err = service.runHooks(context.Background(), HookPhasePreDeploy, hooks, composeProject, output)
+	close(outputChan)
+
+	assert.ErrorIs(t, err, ErrHookFailed)
+	assert.ErrorContains(t, err, `pre-deploy hook "script exec sleep 10": timed out after 100ms`)
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.NotContains(t, output.buffer.String(), "script echo never")
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ImageUpdate is a container running an older image than the one the tag its service declares points to in the
+// registry, such as after a new image was pushed as latest
+type ImageUpdate struct {
+	Service   string
+	Container string
+	Image     string // Image the service declares, e.g. nginx:latest
+	Current   string // ID of the image the container runs
+	Latest    string // Digest the image resolves to in the registry
+}
+
+// String describes the update, e.g. "web: nginx:latest has a new digest sha256:0123456789ab"
+func (u ImageUpdate) String() string {
+	return fmt.Sprintf("%s: %s has a new digest %s", u.Service, u.Image, shortDigest(u.Latest))
+}
+
+// FormatImageUpdates describes each update
+func FormatImageUpdates(updates []ImageUpdate) []string {
+	descriptions := make([]string, 0, len(updates))
+	for _, u := range updates {
+		descriptions = append(descriptions, u.String())
+	}
+	return descriptions
+}
+
+// shortDigest shortens a digest to 12 hex characters like Docker shortens image IDs
+func shortDigest(digest string) string {
+	algorithm, hex, ok := strings.Cut(digest, ":")
+	if !ok || len(hex) <= 12 {
+		return digest
+	}
+	return algorithm + ":" + hex[:12]
+}
+
+// CheckImages redeploys a running project that watches its images when the registry has new digests of images its
+// containers run, such as images pulled by a floating tag like latest. The checked out commit is deployed again,
+// without pulling from Git, which pulls the new images, and recorded with the image trigger. Projects that require
+// approval get a pending deployment instead, see RequestApproval. The updates are returned, none if the images are
+// up to date or the project doesn't watch its images or isn't running. ErrDeploymentInProgress is returned when
+// the project is being deployed.
+func (s *ProjectService) CheckImages(ctx context.Context, projectID uuid.UUID) ([]ImageUpdate, error) {
+	project, err := s.Get(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("project not found: %w", err)
+	}
+	if !project.WatchImages || project.Status != ProjectStatusRunning {
+		return nil, nil
+	}
+
+	composeProject := NewComposeProject(project, s.config)
+	if composeProject == nil {
+		return nil, fmt.Errorf("failed to create compose project")
+	}
+	updates, err := composeProject.ImageUpdates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check images: %w", err)
+	}
+	if len(updates) == 0 {
+		return nil, nil
+	}
+
+	options := DeployOptions{PullImages: true, Trigger: DeploymentTriggerImage}
+	if project.RequireApproval {
+		deployment, err := s.RequestApproval(projectID, project.LastCommitStr(), options)
+		if err != nil {
+			return updates, err
+		}
+		slog.Info("New image digests detected, redeployment awaiting approval",
+			"project_id", project.ID,
+			"project_name", project.Name,
+			"deployment_id", deployment.ID,
+			"updates", FormatImageUpdates(updates))
+		return updates, nil
+	}
+
+	slog.Info("New image digests detected, redeploying project",
+		"project_id", project.ID,
+		"project_name", project.Name,
+		"commit_hash", project.LastCommitStr(),
+		"updates", FormatImageUpdates(updates))
+	if err := s.DeployPiping(ctx, projectID, options); err != nil {
+		return updates, err
+	}
+	slog.Info("Project redeployed with new images",
+		"project_id", project.ID,
+		"project_name", project.Name,
+		"updates", FormatImageUpdates(updates))
+	return updates, nil
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// imagesComposeConfig declares services with images pulled by a tag, built from source, pinned by digest and never
+// pulled
+const imagesComposeConfig = `{"services": {
+	"web": {"image": "nginx:latest"},
+	"db": {"image": "postgres:16"},
+	"private": {"image": "registry.example.com/app:1"},
+	"worker": {"image": "worker:latest", "build": {"context": "."}},
+	"cache": {"image": "redis@sha256:cccc"},
+	"tool": {"image": "tool:latest", "pull_policy": "never"},
+	"loaded": {"image": "loaded:latest"}
+}}`
+
+// imagesContainers are the containers of the services of imagesComposeConfig, by name, with the IDs of the images
+// they run. The second web container was recreated with the latest image.
+var imagesContainers = map[string]string{
+	"test-project-web-1":     "sha256:web-old",
+	"test-project-web-2":     "sha256:web-new",
+	"test-project-db-1":      "sha256:db",
+	"test-project-private-1": "sha256:private",
+	"test-project-worker-1":  "sha256:worker",
+	"test-project-cache-1":   "sha256:cache",
+	"test-project-tool-1":    "sha256:tool",
+	"test-project-loaded-1":  "sha256:loaded",
+}
+
+// setupImagesDocker writes a script standing in for Docker to dir and returns its path. The Compose configuration is
+// imagesComposeConfig and the containers are imagesContainers. The registry has a new digest of nginx:latest, the
+// digest postgres:16 was pulled by, and can't be reached for registry.example.com. Pulling and starting the project
+// are recorded in dir/ran.
+func setupImagesDocker(t *testing.T, dir string) string {
+	t.Helper()
+	t.Setenv("OAR_TEST_DIR", dir)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.json"), []byte(imagesComposeConfig), 0o600))
+
+	var ps, containers []string
+	for name, image := range imagesContainers {
+		service := strings.TrimSuffix(strings.TrimSuffix(strings.TrimPrefix(name, "test-project-"), "-1"), "-2")
+		ps = append(ps, driftContainer(service, name, "", ""))
+		containers = append(containers, name+" "+image)
+	}
+	writeDriftContainers(t, dir, ps...)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "containers"), []byte(strings.Join(containers, "\n")), 0o600))
+
+	images := `sha256:web-old ["nginx@sha256:1111"]
+sha256:web-new ["nginx@sha256:2222"]
+sha256:db ["postgres@sha256:3333"]
+sha256:private ["registry.example.com/app@sha256:4444"]
+sha256:worker []
+sha256:cache ["redis@sha256:cccc"]
+sha256:tool []
+sha256:loaded []
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "images"), []byte(images), 0o600))
+	registry := "nginx:latest sha256:2222\npostgres:16 sha256:3333\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "registry"), []byte(registry), 0o600))
+
+	script := `#!/bin/sh
+while [ "$1" = "--host" ]; do shift 2; done
+case "$1" in
+container)
+	shift 4
+	for name in "$@"; do grep "^$name " "$OAR_TEST_DIR/containers" | cut -d' ' -f2; done ;;
+image)
+	shift 4
+	for id in "$@"; do grep "^$id " "$OAR_TEST_DIR/images"; done ;;
+buildx)
+	digest=$(grep "^$6 " "$OAR_TEST_DIR/registry" | cut -d' ' -f2)
+	[ -n "$digest" ] || { echo "$6: failed to resolve" >&2; exit 1; }
+	echo "{\"mediaType\": \"application/vnd.oci.image.index.v1+json\", \"digest\": \"$digest\", \"size\": 1}" ;;
+compose)
+	shift
+	while case "$1" in --*) true ;; *) false ;; esac; do shift 2; done
+	case "$1 $2" in
+	"config --format") cat "$OAR_TEST_DIR/config.json" ;;
+	"ps --all") cat "$OAR_TEST_DIR/ps" ;;
+	"pull "* | "up "*) echo "$1" >> "$OAR_TEST_DIR/ran" ;;
+	*) exit 1 ;;
+	esac ;;
+*) exit 1 ;;
+esac
+`
+	dockerCommand := filepath.Join(dir, "docker")
+	require.NoError(t, os.WriteFile(dockerCommand, []byte(script), 0o755))
+	return dockerCommand
+}
+
+func TestComposeProject_ImageUpdates(t *testing.T) {
+	tempDir := t.TempDir()
+	composeProject := createTestComposeProject()
+	composeProject.Config.DockerCommand = setupImagesDocker(t, tempDir)
+
+	updates, err := composeProject.ImageUpdates(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []ImageUpdate{{
+		Service:   "web",
+		Container: "test-project-web-1",
+		Image:     "nginx:latest",
+		Current:   "sha256:web-old",
+		Latest:    "sha256:2222",
+	}}, updates)
+
+	// Only the first web container runs the image the registry has
+	registry := "nginx:latest sha256:1111\n"
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "registry"), []byte(registry), 0o600))
+	updates, err = composeProject.ImageUpdates(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, updates, 1)
+	assert.Equal(t, "test-project-web-2", updates[0].Container)
+	assert.Equal(t, "sha256:1111", updates[0].Latest)
+
+	// No containers
+	writeDriftContainers(t, tempDir)
+	updates, err = composeProject.ImageUpdates(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, updates)
+}
+
+func TestImageUpdate_String(t *testing.T) {
+	assert.Equal(t, []string{
+		"web: nginx:latest has a new digest sha256:0123456789ab",
+		"db: postgres:16 has a new digest sha256:3333",
+	}, FormatImageUpdates([]ImageUpdate{
+		{Service: "web", Image: "nginx:latest", Latest: "sha256:0123456789abcdef0123456789abcdef"},
+		{Service: "db", Image: "postgres:16", Latest: "sha256:3333"},
+	}))
+}
+
+func TestProjectService_CheckImages(t *testing.T) {
+	service, repo, deploymentRepo, gitService, _ := setupMockProjectService(t)
+	gitService.GetLatestCommitFunc = func(workingDir string) (string, error) {
+		return "abc123def456", nil
+	}
+
+	tempDir := t.TempDir()
+	service.config.DockerCommand = setupImagesDocker(t, tempDir)
+
+	project := createTestProject()
+	project.WorkingDir = tempDir
+	project.Status = ProjectStatusRunning
+	require.NoError(t, os.Mkdir(filepath.Join(tempDir, GitDir), 0o755))
+	repo.projects[project.ID] = project
+
+	// Left as it is unless it watches its images
+	updates, err := service.CheckImages(context.Background(), project.ID)
+	require.NoError(t, err)
+	assert.Empty(t, updates)
+	assert.Empty(t, deploymentRepo.deployments)
+
+	project.WatchImages = true
+	updates, err = service.CheckImages(context.Background(), project.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"web: nginx:latest has a new digest sha256:2222"}, FormatImageUpdates(updates))
+
+	require.Len(t, deploymentRepo.deployments, 1)
+	for _, deployment := range deploymentRepo.deployments {
+		assert.Equal(t, DeploymentTriggerImage, deployment.Trigger)
+		assert.Equal(t, DeploymentStatusCompleted, deployment.Status)
+		assert.False(t, deployment.Pull)
+	}
+	ran, err := os.ReadFile(filepath.Join(tempDir, "ran"))
+	require.NoError(t, err)
+	assert.Equal(t, "pull\nup\n", string(ran))
+
+	// Stopped projects aren't checked
+	project.Status = ProjectStatusStopped
+	updates, err = service.CheckImages(context.Background(), project.ID)
+	require.NoError(t, err)
+	assert.Empty(t, updates)
+	assert.Len(t, deploymentRepo.deployments, 1)
+}
+
+func TestProjectService_CheckImages_RequireApproval(t *testing.T) {
+	service, repo, deploymentRepo, gitService, _ := setupMockProjectService(t)
+	gitService.GetLatestCommitFunc = func(workingDir string) (string, error) {
+		return "abc123", nil
+	}
+
+	tempDir := t.TempDir()
+	service.config.DockerCommand = setupImagesDocker(t, tempDir)
+
+	project := createTestProject()
+	project.WorkingDir = tempDir
+	project.Status = ProjectStatusRunning
+	project.WatchImages = true
+	project.RequireApproval = true
+	require.NoError(t, os.Mkdir(filepath.Join(tempDir, GitDir), 0o755))
+	repo.projects[project.ID] = project
+
+	// The redeployment waits for approval
+	updates, err := service.CheckImages(context.Background(), project.ID)
+	require.NoError(t, err)
+	assert.Len(t, updates, 1)
+	require.Len(t, deploymentRepo.deployments, 1)
+	var pending *Deployment
+	for _, deployment := range deploymentRepo.deployments {
+		pending = deployment
+	}
+	assert.Equal(t, DeploymentStatusPending, pending.Status)
+	assert.Equal(t, DeploymentTriggerImage, pending.Trigger)
+	assert.Equal(t, project.LastCommitStr(), pending.CommitHash)
+	assert.NoFileExists(t, filepath.Join(tempDir, "ran"))
+
+	// Checking again keeps the pending deployment
+	_, err = service.CheckImages(context.Background(), project.ID)
+	require.NoError(t, err)
+	assert.Len(t, deploymentRepo.deployments, 1)
+
+	// Approving it pulls the new images
+	outputChan := make(chan string, 100)
+	err = service.ApproveStreaming(context.Background(), project.ID, pending.ID, DeployOptions{}, outputChan)
+	close(outputChan)
+	require.NoError(t, err)
+	assert.Equal(t, DeploymentStatusCompleted, deploymentRepo.deployments[pending.ID].Status)
+	ran, err := os.ReadFile(filepath.Join(tempDir, "ran"))
+	require.NoError(t, err)
+	assert.Equal(t, "pull\nup\n", string(ran))
+}
+
+func TestProjectService_CheckImages_Deploying(t *testing.T) {
+	service, project, locks := setupLockingProjectService(t)
+	tempDir := t.TempDir()
+	service.config.DockerCommand = setupImagesDocker(t, tempDir)
+	project.WorkingDir = tempDir
+	project.Status = ProjectStatusRunning
+	project.WatchImages = true
+
+	acquired, err := locks.Acquire(&DeploymentLock{
+		ID:        uuid.New(),
+		ProjectID: project.ID,
+		Holder:    "other",
+		Trigger:   DeploymentTriggerWeb,
+		ExpiresAt: time.Now().Add(time.Minute),
+	}, time.Now())
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	_, err = service.CheckImages(context.Background(), project.ID)
+	assert.ErrorIs(t, err, ErrDeploymentInProgress)
+}
@@ -1,6 +1,9 @@
 package services
 
 import (
+	"context"
+	"io"
+
 	"github.com/google/uuid"
 )
 
@@ -11,6 +14,11 @@ type GitExecutor interface {
 	Fetch(gitBranch string, gitAuth *GitAuthConfig, workingDir string) error
 	GetLatestCommit(workingDir string) (string, error)
 	GetRemoteLatestCommit(workingDir string, gitBranch string) (string, error)
+	FetchTags(gitAuth *GitAuthConfig, workingDir string) error
+	ListTags(workingDir string) (map[string]string, error)
+	Checkout(commit string, workingDir string) error
+	Log(workingDir, from, to string, limit int) ([]GitCommit, error)
+	Export(commit, workingDir, dir string) error
 	TestAuthentication(gitURL string, gitAuth *GitAuthConfig) error
 	GetDefaultBranch(gitURL string, gitAuth *GitAuthConfig) (string, error)
 }
@@ -22,8 +30,17 @@ type ComposeProjectInterface interface {
 	Logs() (string, error)
 	GetConfig() (string, error)
 	Status() (*ComposeStatus, error)
-	UpStreaming(outputChan chan<- string) error
-	UpPiping() error
+	UpStreaming(ctx context.Context, outputChan chan<- string) error
+	UpPiping(ctx context.Context) error
+	PullStreaming(ctx context.Context, outputChan chan<- string) error
+	Exec(ctx context.Context, service, command string) (string, error)
+	RunHookStreaming(ctx context.Context, hook Hook, outputChan chan<- string) error
+	Volumes(ctx context.Context) ([]ComposeVolume, error)
+	BackupVolume(ctx context.Context, volume string, w io.Writer) error
+	RestoreVolume(ctx context.Context, volume string, r io.Reader) error
+	Drift(ctx context.Context) ([]ServiceDrift, error)
+	DownServices(ctx context.Context) ([]string, error)
+	ImageUpdates(ctx context.Context) ([]ImageUpdate, error)
 	DownStreaming(outputChan chan<- string) error
 	DownPiping() error
 	LogsStreaming(outputChan chan<- string) error
@@ -37,8 +54,28 @@ type ProjectManager interface {
 	Create(project *Project) (*Project, error)
 	Update(project *Project) error
 	Remove(projectID uuid.UUID) error
-	DeployStreaming(projectID uuid.UUID, pull bool, outputChan chan<- string) error
-	DeployPiping(projectID uuid.UUID, pull bool) error
+	DeployStreaming(ctx context.Context, projectID uuid.UUID, options DeployOptions, outputChan chan<- string) error
+	DeployPiping(ctx context.Context, projectID uuid.UUID, options DeployOptions) error
+	RollbackStreaming(
+		ctx context.Context,
+		projectID uuid.UUID,
+		target string,
+		options DeployOptions,
+		outputChan chan<- string,
+	) error
+	RollbackPiping(ctx context.Context, projectID uuid.UUID, target string, options DeployOptions) error
+	RequestApproval(projectID uuid.UUID, commit string, options DeployOptions) (*Deployment, error)
+	ApproveStreaming(
+		ctx context.Context,
+		projectID uuid.UUID,
+		deploymentID uuid.UUID,
+		options DeployOptions,
+		outputChan chan<- string,
+	) error
+	ApprovePiping(ctx context.Context, projectID uuid.UUID, deploymentID uuid.UUID, options DeployOptions) error
+	RejectDeployment(projectID, deploymentID uuid.UUID, options DeployOptions) error
+	CancelDeployment(projectID uuid.UUID) error
+	LockProject(projectID uuid.UUID, options DeployOptions) (func(), error)
 	Stop(projectID uuid.UUID) error
 	StopStreaming(projectID uuid.UUID, outputChan chan<- string) error
 	StopPiping(projectID uuid.UUID) error
@@ -46,5 +83,62 @@ type ProjectManager interface {
 	GetLogsPiping(projectID uuid.UUID) error
 	GetConfig(projectID uuid.UUID) (string, error)
 	GetStatus(projectID uuid.UUID) (*ComposeStatus, error)
+	DetectDrift(ctx context.Context, projectID uuid.UUID) ([]ServiceDrift, error)
+	Reconcile(ctx context.Context, projectID uuid.UUID) ([]string, error)
+	CheckImages(ctx context.Context, projectID uuid.UUID) ([]ImageUpdate, error)
+	PreviewDeploy(ctx context.Context, projectID uuid.UUID, options DeployOptions) (*DeployPreview, error)
 	ListDeployments(projectID uuid.UUID) ([]*Deployment, error)
 }
+
+// UserManager defines the contract for user account and session management
+type UserManager interface {
+	ListUsers() ([]*User, error)
+	GetUser(username string) (*User, error)
+	CreateUser(username, password string) (*User, error)
+	RemoveUser(username string) error
+	SetPassword(username, password string) error
+	Authenticate(username, password string) (*User, error)
+	CreateSession(userID uuid.UUID) (string, *Session, error)
+	ValidateSession(token string) (*User, error)
+	DeleteSession(token string) error
+	DeleteExpiredSessions() error
+}
+
+// RoleManager defines the contract for role-based access control
+type RoleManager interface {
+	Grant(userID uuid.UUID, projectID *uuid.UUID, role Role) error
+	Revoke(userID uuid.UUID, projectID *uuid.UUID) error
+	ListRoles(userID uuid.UUID) ([]*RoleBinding, error)
+	EffectiveRole(userID uuid.UUID, projectID *uuid.UUID) (Role, error)
+	Authorize(user *User, projectID *uuid.UUID, required Role) error
+}
+
+// NotificationManager defines the contract for managing the notifiers of projects
+type NotificationManager interface {
+	ListNotifiers(projectID uuid.UUID) ([]*Notifier, error)
+	AddNotifier(notifier *Notifier) error
+	RemoveNotifier(projectID, notifierID uuid.UUID) error
+	TestNotifier(projectID, notifierID uuid.UUID) error
+}
+
+// CatalogManager defines the contract for managing catalogs and syncing the projects they list
+type CatalogManager interface {
+	List() ([]*Catalog, error)
+	Get(id uuid.UUID) (*Catalog, error)
+	Create(catalog *Catalog) (*Catalog, error)
+	Update(catalog *Catalog) error
+	Remove(id uuid.UUID, removeProjects bool) error
+	Sync(ctx context.Context, id uuid.UUID) (*CatalogSyncResult, error)
+}
+
+// VolumeBackupManager defines the contract for backing up and restoring the named volumes of projects
+type VolumeBackupManager interface {
+	List(projectID uuid.UUID) ([]*VolumeBackup, error)
+	Create(ctx context.Context, projectID uuid.UUID) (*VolumeBackup, error)
+	Restore(ctx context.Context, projectID uuid.UUID, name string, options DeployOptions) error
+}
+
+// NotificationSender defines the contract for delivering notifications to the notifiers of a project
+type NotificationSender interface {
+	Notify(notification *Notification)
+}
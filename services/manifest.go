@@ -0,0 +1,173 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ManifestFile is the name of the project manifest in the root of a repository
+const ManifestFile = ".oar.yaml"
+
+// ErrInvalidManifest is returned for project manifests that can't be used, failing the deployment
+var ErrInvalidManifest = errors.New("invalid manifest")
+
+// Manifest declares project settings in the repository itself. Settings it declares override those of the
+// project in Oar when the project is deployed, the others are left as they are. Git settings, variables and
+// credentials can only be set in Oar.
+type Manifest struct {
+	ComposeFiles []string             `yaml:"compose_files"` // Relative to the root of the repository
+	Profiles     []string             `yaml:"profiles"`      // Docker Compose profiles to enable
+	HealthChecks []string             `yaml:"health_checks"` // As parsed by ParseHealthCheck
+	Hooks        ManifestHooks        `yaml:"hooks"`
+	Timeouts     ManifestTimeouts     `yaml:"timeouts"`
+	Deploy       ManifestDeployPolicy `yaml:"deploy"`
+
+	healthChecks    []HealthCheck
+	preDeployHooks  []Hook
+	postDeployHooks []Hook
+}
+
+// ManifestHooks are the deployment hooks of a manifest, as parsed by ParseHook
+type ManifestHooks struct {
+	PreDeploy  []string `yaml:"pre_deploy"`
+	PostDeploy []string `yaml:"post_deploy"`
+}
+
+// ManifestTimeouts are the deployment timeouts of a manifest, durations such as 10m
+type ManifestTimeouts struct {
+	Pull   time.Duration `yaml:"pull"`
+	Up     time.Duration `yaml:"up"`
+	Wait   time.Duration `yaml:"wait"`
+	Health time.Duration `yaml:"health"`
+}
+
+// ManifestDeployPolicy is how a manifest wants the project deployed
+type ManifestDeployPolicy struct {
+	Automatic     *bool `yaml:"automatic"`      // Deploy new commits automatically
+	AutoRollback  *bool `yaml:"auto_rollback"`  // Roll back to the last successful deployment when a deployment fails
+	BackupVolumes *bool `yaml:"backup_volumes"` // Back up the named volumes before each deployment
+}
+
+// ReadManifest reads and validates the manifest in the root of the repository checked out in dir. A repository
+// without a manifest returns nil.
+func ReadManifest(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", ManifestFile, err)
+	}
+
+	var manifest Manifest
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&manifest); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidManifest, ManifestFile, err)
+	}
+
+	if err := manifest.validate(); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidManifest, ManifestFile, err)
+	}
+	return &manifest, nil
+}
+
+// validate checks the settings of the manifest, parsing its health checks and hooks
+func (m *Manifest) validate() error {
+	for _, file := range m.ComposeFiles {
+		if file == "" || filepath.IsAbs(file) || !filepath.IsLocal(file) {
+			return fmt.Errorf("compose file %q is not a path within the repository", file)
+		}
+	}
+	for _, profile := range m.Profiles {
+		if strings.TrimSpace(profile) == "" {
+			return errors.New("profiles must not be empty")
+		}
+	}
+
+	var err error
+	if m.healthChecks, err = ParseHealthChecks(m.HealthChecks); err != nil {
+		return err
+	}
+	if m.preDeployHooks, err = ParseHooks(m.Hooks.PreDeploy); err != nil {
+		return err
+	}
+	if m.postDeployHooks, err = ParseHooks(m.Hooks.PostDeploy); err != nil {
+		return err
+	}
+
+	timeouts := ComposeTimeouts{
+		Pull:   m.Timeouts.Pull,
+		Up:     m.Timeouts.Up,
+		Wait:   m.Timeouts.Wait,
+		Health: m.Timeouts.Health,
+	}
+	return timeouts.Validate()
+}
+
+// Apply returns a copy of project with the settings the manifest declares in place of its own. A nil manifest
+// returns project itself.
+func (m *Manifest) Apply(project *Project) *Project {
+	if m == nil {
+		return project
+	}
+
+	applied := *project
+	if len(m.ComposeFiles) > 0 {
+		applied.ComposeFiles = m.ComposeFiles
+	}
+	// An empty list declared in the manifest removes those of the project
+	if m.HealthChecks != nil {
+		applied.HealthChecks = m.healthChecks
+	}
+	if m.Hooks.PreDeploy != nil {
+		applied.PreDeployHooks = m.preDeployHooks
+	}
+	if m.Hooks.PostDeploy != nil {
+		applied.PostDeployHooks = m.postDeployHooks
+	}
+	applied.Timeouts = ComposeTimeouts{
+		Pull:   m.Timeouts.Pull,
+		Up:     m.Timeouts.Up,
+		Wait:   m.Timeouts.Wait,
+		Health: m.Timeouts.Health,
+	}.Or(project.Timeouts)
+	if m.Deploy.Automatic != nil {
+		applied.WatcherEnabled = *m.Deploy.Automatic
+	}
+	if m.Deploy.AutoRollback != nil {
+		applied.AutoRollback = *m.Deploy.AutoRollback
+	}
+	if m.Deploy.BackupVolumes != nil {
+		applied.BackupVolumes = *m.Deploy.BackupVolumes
+	}
+	return &applied
+}
+
+// AutomaticDeployments reports whether new commits of a project are deployed automatically, as declared by the
+// manifest of its checkout or else by the project. An invalid manifest is logged and ignored; deploying the
+// project reports it.
+func AutomaticDeployments(project *Project) bool {
+	gitDir, err := project.GitDir()
+	if err != nil {
+		return project.WatcherEnabled
+	}
+	manifest, err := ReadManifest(gitDir)
+	if err != nil {
+		slog.Warn("Ignoring invalid project manifest",
+			"project_id", project.ID,
+			"project_name", project.Name,
+			"error", err)
+		return project.WatcherEnabled
+	}
+	return manifest.Apply(project).WatcherEnabled
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeManifest writes a manifest into dir
+func writeManifest(t *testing.T, dir, manifest string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ManifestFile), []byte(manifest), 0o644))
+}
+
+func TestReadManifest(t *testing.T) {
+	dir := t.TempDir()
+	writeManifest(t, dir, `
+compose_files:
+  - compose.yml
+  - deploy/compose.prod.yml
+profiles: [workers]
+health_checks:
+  - http http://localhost:8080/health
+hooks:
+  pre_deploy:
+    - run app ./manage.py migrate
+  post_deploy: []
+timeouts:
+  pull: 15m
+  health: 30s
+deploy:
+  automatic: false
+  auto_rollback: true
+  backup_volumes: true
+`)
+
+	manifest, err := ReadManifest(dir)
+	require.NoError(t, err)
+	require.NotNil(t, manifest)
+
+	assert.Equal(t, []string{"compose.yml", "deploy/compose.prod.yml"}, manifest.ComposeFiles)
+	assert.Equal(t, []string{"workers"}, manifest.Profiles)
+	assert.Equal(t, ManifestTimeouts{Pull: 15 * time.Minute, Health: 30 * time.Second}, manifest.Timeouts)
+	require.NotNil(t, manifest.Deploy.Automatic)
+	assert.False(t, *manifest.Deploy.Automatic)
+	require.NotNil(t, manifest.Deploy.AutoRollback)
+	assert.True(t, *manifest.Deploy.AutoRollback)
+	require.NotNil(t, manifest.Deploy.BackupVolumes)
+	assert.True(t, *manifest.Deploy.BackupVolumes)
+	assert.Equal(t, []string{"http http://localhost:8080/health"}, FormatHealthChecks(manifest.healthChecks))
+	assert.Equal(t, []string{"run app ./manage.py migrate"}, FormatHooks(manifest.preDeployHooks))
+}
+
+func TestReadManifest_Missing(t *testing.T) {
+	manifest, err := ReadManifest(t.TempDir())
+	require.NoError(t, err)
+	assert.Nil(t, manifest)
+}
+
+func TestReadManifest_Empty(t *testing.T) {
+	dir := t.TempDir()
+	writeManifest(t, dir, "")
+
+	manifest, err := ReadManifest(dir)
+	require.NoError(t, err)
+	require.NotNil(t, manifest)
+	assert.Equal(t, Manifest{}, *manifest)
+}
+
+func TestReadManifest_Invalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		manifest    string
+		expectedErr string
+	}{
+		{name: "malformed", manifest: "compose_files: [compose.yml", expectedErr: "did not find expected"},
+		{name: "unknown key", manifest: "git_branch: main", expectedErr: "field git_branch not found"},
+		{name: "wrong type", manifest: "profiles: workers", expectedErr: "cannot unmarshal"},
+		{name: "absolute compose file", manifest: "compose_files: [/etc/compose.yml]", expectedErr: "not a path"},
+		{name: "compose file outside", manifest: "compose_files: [../compose.yml]", expectedErr: "not a path"},
+		{name: "empty profile", manifest: `profiles: [""]`, expectedErr: "profiles must not be empty"},
+		{name: "health check", manifest: "health_checks: [ping localhost]", expectedErr: "invalid health check"},
+		{name: "hook", manifest: "hooks: {post_deploy: [exec app]}", expectedErr: "invalid hook"},
+		{name: "timeout", manifest: "timeouts: {up: 10}", expectedErr: "cannot unmarshal"},
+		{name: "negative timeout", manifest: "timeouts: {up: -1m}", expectedErr: "invalid timeout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeManifest(t, dir, tt.manifest)
+
+			manifest, err := ReadManifest(dir)
+			assert.Nil(t, manifest)
+			assert.ErrorIs(t, err, ErrInvalidManifest)
+			assert.ErrorContains(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestManifest_Apply(t *testing.T) {
+	project := createTestProject()
+	project.WatcherEnabled = true
+	project.HealthChecks = []HealthCheck{{Type: HealthCheckTypeTCP, Address: "localhost:5432"}}
+	project.PostDeployHooks = []Hook{{Type: HookTypeExec, Service: "cache", Command: "flush"}}
+	project.Timeouts = ComposeTimeouts{Up: time.Hour, Wait: time.Hour}
+
+	dir := t.TempDir()
+	writeManifest(t, dir, `
+compose_files: [compose.yml]
+health_checks: []
+hooks:
+  pre_deploy: [script make warm]
+timeouts:
+  up: 20m
+deploy:
+  automatic: false
+`)
+	manifest, err := ReadManifest(dir)
+	require.NoError(t, err)
+
+	applied := manifest.Apply(project)
+
+	// Declared settings override those of the project, others are kept
+	assert.Equal(t, []string{"compose.yml"}, applied.ComposeFiles)
+	assert.Empty(t, applied.HealthChecks)
+	assert.Equal(t, []string{"script make warm"}, FormatHooks(applied.PreDeployHooks))
+	assert.Equal(t, project.PostDeployHooks, applied.PostDeployHooks)
+	assert.Equal(t, ComposeTimeouts{Up: 20 * time.Minute, Wait: time.Hour}, applied.Timeouts)
+	assert.False(t, applied.WatcherEnabled)
+	assert.Equal(t, project.AutoRollback, applied.AutoRollback)
+	assert.Equal(t, project.BackupVolumes, applied.BackupVolumes)
+	assert.Equal(t, project.Variables, applied.Variables)
+
+	// The project itself is left as it is
+	assert.Equal(t, []string{"docker-compose.yml"}, project.ComposeFiles)
+	assert.Len(t, project.HealthChecks, 1)
+	assert.True(t, project.WatcherEnabled)
+
+	// Without a manifest nothing changes
+	var none *Manifest
+	assert.Same(t, project, none.Apply(project))
+}
+
+func TestAutomaticDeployments(t *testing.T) {
+	project := createTestProject()
+	project.WorkingDir = t.TempDir()
+	project.WatcherEnabled = true
+	gitDir := filepath.Join(project.WorkingDir, GitDir)
+	require.NoError(t, os.Mkdir(gitDir, 0o755))
+
+	// The project decides without a manifest
+	assert.True(t, AutomaticDeployments(project))
+
+	// The manifest wins over the project
+	writeManifest(t, gitDir, "deploy: {automatic: false}")
+	assert.False(t, AutomaticDeployments(project))
+
+	project.WatcherEnabled = false
+	writeManifest(t, gitDir, "deploy: {automatic: true}")
+	assert.True(t, AutomaticDeployments(project))
+
+	// Invalid manifests are ignored
+	writeManifest(t, gitDir, "deploy: {automatic: sometimes}")
+	assert.False(t, AutomaticDeployments(project))
+}
@@ -1,7 +1,10 @@
 package services
 
 import (
+	"encoding/json"
+	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/oar-cd/oar/models"
 )
@@ -20,6 +23,17 @@ func (m *ProjectMapper) ToDomain(p *models.ProjectModel) *Project {
 		status = ProjectStatusUnknown
 	}
 
+	// Projects last deployed or stopped before desired states were recorded are meant to be as they are
+	desiredState, err := ParseDesiredState(p.DesiredState)
+	if err != nil || desiredState == DesiredStateNone {
+		desiredState = desiredStateOf(status)
+	}
+
+	gitRefType, err := ParseGitRefType(p.GitRefType)
+	if err != nil {
+		gitRefType = GitRefTypeBranch
+	}
+
 	// Decrypt authentication data if present
 	var gitAuth *GitAuthConfig
 	if p.GitAuthType != nil && p.GitAuthCredentials != nil && m.encryption != nil {
@@ -38,20 +52,61 @@ func (m *ProjectMapper) ToDomain(p *models.ProjectModel) *Project {
 		}
 	}
 
+	healthChecks, err := ParseHealthChecks(parseFiles(p.HealthChecks))
+	if err != nil {
+		slog.Error("Failed to parse health checks",
+			"project_id", p.ID,
+			"project_name", p.Name,
+			"error", err)
+	}
+	preDeployHooks, err := ParseHooks(parseFiles(p.PreDeployHooks))
+	if err != nil {
+		slog.Error("Failed to parse pre-deploy hooks",
+			"project_id", p.ID,
+			"project_name", p.Name,
+			"error", err)
+	}
+	postDeployHooks, err := ParseHooks(parseFiles(p.PostDeployHooks))
+	if err != nil {
+		slog.Error("Failed to parse post-deploy hooks",
+			"project_id", p.ID,
+			"project_name", p.Name,
+			"error", err)
+	}
+
 	return &Project{
-		ID:             p.ID,
-		Name:           p.Name,
-		GitURL:         p.GitURL,
-		GitBranch:      p.GitBranch,
-		GitAuth:        gitAuth,
-		WorkingDir:     p.WorkingDir,
-		ComposeFiles:   parseFiles(p.ComposeFiles),
-		Variables:      parseFiles(p.Variables),
-		Status:         status,
-		LastCommit:     p.LastCommit,
-		WatcherEnabled: p.WatcherEnabled,
-		CreatedAt:      p.CreatedAt,
-		UpdatedAt:      p.UpdatedAt,
+		ID:               p.ID,
+		Name:             p.Name,
+		GitURL:           p.GitURL,
+		GitBranch:        p.GitBranch,
+		GitRefType:       gitRefType,
+		GitRef:           p.GitRef,
+		GitAuth:          gitAuth,
+		WorkingDir:       p.WorkingDir,
+		ComposeFiles:     parseFiles(p.ComposeFiles),
+		Variables:        parseFiles(p.Variables),
+		Status:           status,
+		DesiredState:     desiredState,
+		LastCommit:       p.LastCommit,
+		RolledBackCommit: p.RolledBackCommit,
+		WatcherEnabled:   p.WatcherEnabled,
+		RequireApproval:  p.RequireApproval,
+		WatchImages:      p.WatchImages,
+		AutoRollback:     p.AutoRollback,
+		BackupVolumes:    p.BackupVolumes,
+		HealthChecks:     healthChecks,
+		PreDeployHooks:   preDeployHooks,
+		PostDeployHooks:  postDeployHooks,
+		Drift:            parseFiles(p.Drift),
+		Timeouts: ComposeTimeouts{
+			Pull:   time.Duration(p.ComposePullTimeout) * time.Second,
+			Up:     time.Duration(p.ComposeUpTimeout) * time.Second,
+			Wait:   time.Duration(p.ComposeWaitTimeout) * time.Second,
+			Health: time.Duration(p.HealthCheckTimeout) * time.Second,
+		},
+		CatalogID: p.CatalogID,
+		CreatedAt: p.CreatedAt,
+		UpdatedAt: p.UpdatedAt,
 	}
 }
 
@@ -62,15 +117,32 @@ func (m *ProjectMapper) ToModel(p *Project) *models.ProjectModel {
 			CreatedAt: p.CreatedAt,
 			UpdatedAt: p.UpdatedAt,
 		},
-		Name:           p.Name,
-		GitURL:         p.GitURL,
-		GitBranch:      p.GitBranch,
-		WorkingDir:     p.WorkingDir,
-		ComposeFiles:   serializeFiles(p.ComposeFiles),
-		Variables:      serializeFiles(p.Variables),
-		Status:         p.Status.String(),
-		LastCommit:     p.LastCommit,
-		WatcherEnabled: p.WatcherEnabled,
+		Name:               p.Name,
+		GitURL:             p.GitURL,
+		GitBranch:          p.GitBranch,
+		GitRefType:         p.GitRefType.String(),
+		GitRef:             p.GitRef,
+		WorkingDir:         p.WorkingDir,
+		ComposeFiles:       serializeFiles(p.ComposeFiles),
+		Variables:          serializeFiles(p.Variables),
+		Status:             p.Status.String(),
+		DesiredState:       p.DesiredState.String(),
+		LastCommit:         p.LastCommit,
+		RolledBackCommit:   p.RolledBackCommit,
+		WatcherEnabled:     p.WatcherEnabled,
+		RequireApproval:    p.RequireApproval,
+		WatchImages:        p.WatchImages,
+		AutoRollback:       p.AutoRollback,
+		BackupVolumes:      p.BackupVolumes,
+		ComposePullTimeout: int(p.Timeouts.Pull / time.Second),
+		ComposeUpTimeout:   int(p.Timeouts.Up / time.Second),
+		ComposeWaitTimeout: int(p.Timeouts.Wait / time.Second),
+		HealthChecks:       serializeFiles(FormatHealthChecks(p.HealthChecks)),
+		HealthCheckTimeout: int(p.Timeouts.Health / time.Second),
+		PreDeployHooks:     serializeFiles(FormatHooks(p.PreDeployHooks)),
+		PostDeployHooks:    serializeFiles(FormatHooks(p.PostDeployHooks)),
+		Drift:              serializeFiles(p.Drift),
+		CatalogID:          p.CatalogID,
 	}
 
 	// Encrypt authentication data if present
@@ -99,27 +171,354 @@ func (m *DeploymentMapper) ToDomain(d *models.DeploymentModel) *Deployment {
 		status = DeploymentStatusUnknown
 	}
 
+	trigger, err := ParseDeploymentTrigger(d.Trigger)
+	if err != nil {
+		trigger = DeploymentTriggerUnknown
+	}
+
+	var startedAt time.Time
+	if d.StartedAt != nil {
+		startedAt = *d.StartedAt
+	}
+
+	var healthChecks []HealthCheckResult
+	if d.HealthChecks != "" {
+		if err := json.Unmarshal([]byte(d.HealthChecks), &healthChecks); err != nil {
+			slog.Error("Failed to parse health check results", "deployment_id", d.ID, "error", err)
+		}
+	}
+
 	return &Deployment{
-		ID:         d.ID,
-		ProjectID:  d.ProjectID,
-		CommitHash: d.CommitHash,
-		Status:     status,
-		Output:     d.Output,
-		CreatedAt:  d.CreatedAt,
-		UpdatedAt:  d.UpdatedAt,
+		ID:             d.ID,
+		ProjectID:      d.ProjectID,
+		CommitHash:     d.CommitHash,
+		PreviousCommit: d.PreviousCommit,
+		Status:         status,
+		Output:         d.Output,
+		Rollback:       d.Rollback,
+		Pull:           d.Pull,
+		Trigger:        trigger,
+		Actor:          d.Actor,
+		HealthChecks:   healthChecks,
+		StartedAt:      startedAt,
+		FinishedAt:     d.FinishedAt,
+		CreatedAt:      d.CreatedAt,
+		UpdatedAt:      d.UpdatedAt,
 	}
 }
 
 func (m *DeploymentMapper) ToModel(d *Deployment) *models.DeploymentModel {
+	var startedAt *time.Time
+	if !d.StartedAt.IsZero() {
+		startedAt = &d.StartedAt
+	}
+
+	var healthChecks string
+	if len(d.HealthChecks) > 0 {
+		data, _ := json.Marshal(d.HealthChecks) // Plain strings, booleans and numbers always marshal
+		healthChecks = string(data)
+	}
+
 	return &models.DeploymentModel{
 		BaseModel: models.BaseModel{
 			ID:        d.ID,
 			CreatedAt: d.CreatedAt,
 			UpdatedAt: d.UpdatedAt,
 		},
-		ProjectID:  d.ProjectID,
-		CommitHash: d.CommitHash,
-		Status:     d.Status.String(),
-		Output:     d.Output,
+		ProjectID:      d.ProjectID,
+		CommitHash:     d.CommitHash,
+		PreviousCommit: d.PreviousCommit,
+		Status:         d.Status.String(),
+		Output:         d.Output,
+		Rollback:       d.Rollback,
+		Pull:           d.Pull,
+		Trigger:        d.Trigger.String(),
+		Actor:          d.Actor,
+		HealthChecks:   healthChecks,
+		StartedAt:      startedAt,
+		FinishedAt:     d.FinishedAt,
+	}
+}
+
+type UserMapper struct{}
+
+func (m *UserMapper) ToDomain(u *models.UserModel) *User {
+	return &User{
+		ID:           u.ID,
+		Username:     u.Username,
+		PasswordHash: u.PasswordHash,
+		CreatedAt:    u.CreatedAt,
+		UpdatedAt:    u.UpdatedAt,
+	}
+}
+
+func (m *UserMapper) ToModel(u *User) *models.UserModel {
+	return &models.UserModel{
+		BaseModel: models.BaseModel{
+			ID:        u.ID,
+			CreatedAt: u.CreatedAt,
+			UpdatedAt: u.UpdatedAt,
+		},
+		Username:     u.Username,
+		PasswordHash: u.PasswordHash,
+	}
+}
+
+type DeploymentLockMapper struct{}
+
+func (m *DeploymentLockMapper) ToDomain(l *models.DeploymentLockModel) *DeploymentLock {
+	trigger, err := ParseDeploymentTrigger(l.Trigger)
+	if err != nil {
+		trigger = DeploymentTriggerUnknown
+	}
+
+	return &DeploymentLock{
+		ID:        l.ID,
+		ProjectID: l.ProjectID,
+		Holder:    l.Holder,
+		Trigger:   trigger,
+		Actor:     l.Actor,
+		ExpiresAt: l.ExpiresAt,
+		CreatedAt: l.CreatedAt,
+		UpdatedAt: l.UpdatedAt,
+
+		CancelRequested: l.CancelRequested,
+	}
+}
+
+func (m *DeploymentLockMapper) ToModel(l *DeploymentLock) *models.DeploymentLockModel {
+	return &models.DeploymentLockModel{
+		BaseModel: models.BaseModel{
+			ID:        l.ID,
+			CreatedAt: l.CreatedAt,
+			UpdatedAt: l.UpdatedAt,
+		},
+		ProjectID: l.ProjectID,
+		Holder:    l.Holder,
+		Trigger:   l.Trigger.String(),
+		Actor:     l.Actor,
+		// Locks are shared with processes in other time zones, and compared as text by SQLite
+		ExpiresAt: l.ExpiresAt.UTC(),
+
+		CancelRequested: l.CancelRequested,
 	}
 }
+
+type SessionMapper struct{}
+
+func (m *SessionMapper) ToDomain(s *models.SessionModel) *Session {
+	return &Session{
+		ID:        s.ID,
+		UserID:    s.UserID,
+		TokenHash: s.TokenHash,
+		ExpiresAt: s.ExpiresAt,
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+	}
+}
+
+func (m *SessionMapper) ToModel(s *Session) *models.SessionModel {
+	return &models.SessionModel{
+		BaseModel: models.BaseModel{
+			ID:        s.ID,
+			CreatedAt: s.CreatedAt,
+			UpdatedAt: s.UpdatedAt,
+		},
+		UserID:    s.UserID,
+		TokenHash: s.TokenHash,
+		ExpiresAt: s.ExpiresAt,
+	}
+}
+
+type RoleBindingMapper struct{}
+
+func (m *RoleBindingMapper) ToDomain(b *models.RoleBindingModel) *RoleBinding {
+	role, err := ParseRole(b.Role)
+	if err != nil {
+		// An unknown role must never grant anything
+		slog.Error("Invalid role in role binding", "role_binding_id", b.ID, "role", b.Role, "error", err)
+		role = RoleNone
+	}
+
+	return &RoleBinding{
+		ID:        b.ID,
+		UserID:    b.UserID,
+		ProjectID: b.ProjectID,
+		Role:      role,
+		CreatedAt: b.CreatedAt,
+		UpdatedAt: b.UpdatedAt,
+	}
+}
+
+func (m *RoleBindingMapper) ToModel(b *RoleBinding) *models.RoleBindingModel {
+	return &models.RoleBindingModel{
+		BaseModel: models.BaseModel{
+			ID:        b.ID,
+			CreatedAt: b.CreatedAt,
+			UpdatedAt: b.UpdatedAt,
+		},
+		UserID:    b.UserID,
+		ProjectID: b.ProjectID,
+		Role:      b.Role.String(),
+	}
+}
+
+type NotifierMapper struct {
+	encryption *EncryptionService
+}
+
+func (m *NotifierMapper) ToDomain(n *models.NotifierModel) *Notifier {
+	notifierType, err := ParseNotifierType(n.Type)
+	if err != nil {
+		slog.Error("Invalid notifier type", "notifier_id", n.ID, "type", n.Type, "error", err)
+	}
+
+	var events []NotificationEvent
+	for _, event := range parseFiles(n.Events) {
+		events = append(events, NotificationEvent(event))
+	}
+
+	// Decrypt the configuration, a notifier that can't be decrypted has nowhere to deliver to
+	var config NotifierConfig
+	data := n.Config
+	if m.encryption != nil {
+		data, err = m.encryption.Decrypt(n.Config)
+	}
+	if err == nil {
+		err = json.Unmarshal([]byte(data), &config)
+	}
+	if err != nil {
+		slog.Error("Failed to decrypt notifier configuration", "notifier_id", n.ID, "error", err)
+	}
+
+	return &Notifier{
+		ID:        n.ID,
+		ProjectID: n.ProjectID,
+		Type:      notifierType,
+		Events:    events,
+		Config:    config,
+		CreatedAt: n.CreatedAt,
+		UpdatedAt: n.UpdatedAt,
+	}
+}
+
+func (m *NotifierMapper) ToModel(n *Notifier) (*models.NotifierModel, error) {
+	data, err := json.Marshal(n.Config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize notifier configuration: %w", err)
+	}
+
+	config := string(data)
+	if m.encryption != nil {
+		config, err = m.encryption.Encrypt(config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt notifier configuration: %w", err)
+		}
+	}
+
+	events := make([]string, len(n.Events))
+	for i, event := range n.Events {
+		events[i] = string(event)
+	}
+
+	return &models.NotifierModel{
+		BaseModel: models.BaseModel{
+			ID:        n.ID,
+			CreatedAt: n.CreatedAt,
+			UpdatedAt: n.UpdatedAt,
+		},
+		ProjectID: n.ProjectID,
+		Type:      string(n.Type),
+		Events:    serializeFiles(events),
+		Config:    config,
+	}, nil
+}
+
+type CatalogMapper struct {
+	encryption *EncryptionService
+}
+
+func (m *CatalogMapper) ToDomain(c *models.CatalogModel) *Catalog {
+	// Decrypt authentication data if present
+	var gitAuth *GitAuthConfig
+	if c.GitAuthType != nil && c.GitAuthCredentials != nil && m.encryption != nil {
+		decryptedAuth, err := m.encryption.DecryptGitAuthConfig(*c.GitAuthType, *c.GitAuthCredentials)
+		if err != nil {
+			slog.Error("Failed to decrypt Git authentication",
+				"catalog_id", c.ID,
+				"catalog_name", c.Name,
+				"auth_type", *c.GitAuthType,
+				"error", err)
+		} else {
+			gitAuth = decryptedAuth
+		}
+	}
+
+	// Decrypt secrets, the projects referencing them fail to sync if they can't be decrypted
+	secrets := c.Secrets
+	if secrets != "" && m.encryption != nil {
+		var err error
+		secrets, err = m.encryption.Decrypt(c.Secrets)
+		if err != nil {
+			slog.Error("Failed to decrypt catalog secrets",
+				"catalog_id", c.ID,
+				"catalog_name", c.Name,
+				"error", err)
+			secrets = ""
+		}
+	}
+
+	return &Catalog{
+		ID:            c.ID,
+		Name:          c.Name,
+		GitURL:        c.GitURL,
+		GitBranch:     c.GitBranch,
+		GitAuth:       gitAuth,
+		WorkingDir:    c.WorkingDir,
+		Secrets:       parseFiles(secrets),
+		LastCommit:    c.LastCommit,
+		LastSyncedAt:  c.LastSyncedAt,
+		LastSyncError: c.LastSyncError,
+		CreatedAt:     c.CreatedAt,
+		UpdatedAt:     c.UpdatedAt,
+	}
+}
+
+func (m *CatalogMapper) ToModel(c *Catalog) (*models.CatalogModel, error) {
+	model := &models.CatalogModel{
+		BaseModel: models.BaseModel{
+			ID:        c.ID,
+			CreatedAt: c.CreatedAt,
+			UpdatedAt: c.UpdatedAt,
+		},
+		Name:          c.Name,
+		GitURL:        c.GitURL,
+		GitBranch:     c.GitBranch,
+		WorkingDir:    c.WorkingDir,
+		Secrets:       serializeFiles(c.Secrets),
+		LastCommit:    c.LastCommit,
+		LastSyncedAt:  c.LastSyncedAt,
+		LastSyncError: c.LastSyncError,
+	}
+
+	if model.Secrets != "" && m.encryption != nil {
+		secrets, err := m.encryption.Encrypt(model.Secrets)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt catalog secrets: %w", err)
+		}
+		model.Secrets = secrets
+	}
+
+	if c.GitAuth != nil && m.encryption != nil {
+		authType, encryptedCredentials, err := m.encryption.EncryptGitAuthConfig(c.GitAuth)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt Git authentication: %w", err)
+		}
+		if authType != "" && encryptedCredentials != "" {
+			model.GitAuthType = &authType
+			model.GitAuthCredentials = &encryptedCredentials
+		}
+	}
+
+	return model, nil
+}
@@ -0,0 +1,217 @@
+package services
+
+import (
+	"crypto/subtle"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// metricsRegistry holds the metrics recorded while Oar runs. The web app and the watcher each serve it at
+// /metrics, together with the per-project metrics gathered at scrape time by ProjectCollector.
+var metricsRegistry = prometheus.NewRegistry()
+
+var (
+	deploymentsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "oar",
+		Name:      "deployments_total",
+		Help:      "Number of finished deployments by project and status.",
+	}, []string{"project", "status"})
+
+	deploymentDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "oar",
+		Name:      "deployment_duration_seconds",
+		Help:      "Duration of deployments, from the Git pull to Docker Compose finishing, by project and status.",
+		Buckets:   []float64{5, 10, 30, 60, 120, 300, 600, 1200, 1800},
+	}, []string{"project", "status"})
+
+	watcherPollDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "oar",
+		Name:      "watcher_poll_duration_seconds",
+		Help:      "Duration of watcher poll cycles, including the deployments they trigger.",
+		Buckets:   []float64{0.5, 1, 5, 10, 30, 60, 300, 600, 1800},
+	})
+
+	gitFetchErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "oar",
+		Name:      "git_fetch_errors_total",
+		Help:      "Number of failed fetches from the Git remote by project.",
+	}, []string{"project"})
+)
+
+func init() {
+	metricsRegistry.MustRegister(
+		deploymentsTotal,
+		deploymentDuration,
+		watcherPollDuration,
+		gitFetchErrorsTotal,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// countGitFetchError records a failed fetch from the Git remote of project
+func countGitFetchError(project *Project) {
+	gitFetchErrorsTotal.WithLabelValues(project.Name).Inc()
+}
+
+// ObserveWatcherPoll records the duration of a watcher poll cycle
+func ObserveWatcherPoll(duration time.Duration) {
+	watcherPollDuration.Observe(duration.Seconds())
+}
+
+// observeDeployment records a finished deployment of project. Deployments that failed before they were
+// finished, e.g. on a Git pull, are timed until now.
+func observeDeployment(project *Project, deployment *Deployment, result DeploymentStatus) {
+	status := result.String()
+	deploymentsTotal.WithLabelValues(project.Name, status).Inc()
+
+	duration := deployment.Duration()
+	if deployment.FinishedAt == nil && !deployment.StartedAt.IsZero() {
+		duration = time.Since(deployment.StartedAt)
+	}
+	if duration > 0 {
+		deploymentDuration.WithLabelValues(project.Name, status).Observe(duration.Seconds())
+	}
+}
+
+// projectMetricsTTL is how long ProjectCollector serves the project metrics it read before reading them again.
+// Reading them lists the containers of every project with Docker Compose, which scrapes must not do at will.
+const projectMetricsTTL = 15 * time.Second
+
+// ProjectCollector reports the containers and the time since the last successful deployment of every project.
+// The values are read when Prometheus scrapes, at most once every projectMetricsTTL, so they are current in every
+// process serving /metrics.
+type ProjectCollector struct {
+	projectService ProjectManager
+
+	containers          *prometheus.Desc
+	containersRunning   *prometheus.Desc
+	sinceLastDeployment *prometheus.Desc
+
+	mu          sync.Mutex // Held while reading, so that concurrent scrapes wait for a single read
+	metrics     []prometheus.Metric
+	collectedAt time.Time
+}
+
+// Ensure ProjectCollector implements prometheus.Collector
+var _ prometheus.Collector = (*ProjectCollector)(nil)
+
+func (c *ProjectCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.containers
+	ch <- c.containersRunning
+	ch <- c.sinceLastDeployment
+}
+
+func (c *ProjectCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.collectedAt.IsZero() || time.Since(c.collectedAt) >= projectMetricsTTL {
+		metrics, err := c.read()
+		if err != nil {
+			slog.Error("Service operation failed",
+				"layer", "service",
+				"operation", "collect_metrics",
+				"error", err)
+			return
+		}
+		c.metrics = metrics
+		c.collectedAt = time.Now()
+	}
+
+	for _, metric := range c.metrics {
+		ch <- metric
+	}
+}
+
+// read reads the metrics of every project
+func (c *ProjectCollector) read() ([]prometheus.Metric, error) {
+	projects, err := c.projectService.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var metrics []prometheus.Metric
+
+	for _, project := range projects {
+		if status, err := c.projectService.GetStatus(project.ID); err == nil {
+			running := 0
+			for _, container := range status.Containers {
+				if container.State == "running" {
+					running++
+				}
+			}
+			metrics = append(metrics,
+				prometheus.MustNewConstMetric(
+					c.containers, prometheus.GaugeValue, float64(len(status.Containers)), project.Name),
+				prometheus.MustNewConstMetric(
+					c.containersRunning, prometheus.GaugeValue, float64(running), project.Name))
+		}
+
+		deployments, err := c.projectService.ListDeployments(project.ID)
+		if err != nil {
+			continue
+		}
+		// Deployments are listed newest first
+		for _, deployment := range deployments {
+			if deployment.Status == DeploymentStatusCompleted {
+				// Deployments recorded before they had a finish time only have the time they were last saved
+				finishedAt := deployment.UpdatedAt
+				if deployment.FinishedAt != nil {
+					finishedAt = *deployment.FinishedAt
+				}
+				metrics = append(metrics, prometheus.MustNewConstMetric(
+					c.sinceLastDeployment,
+					prometheus.GaugeValue,
+					time.Since(finishedAt).Seconds(),
+					project.Name))
+				break
+			}
+		}
+	}
+	return metrics, nil
+}
+
+// NewProjectCollector creates a ProjectCollector for the projects of projectService
+func NewProjectCollector(projectService ProjectManager) *ProjectCollector {
+	return &ProjectCollector{
+		projectService: projectService,
+		containers: prometheus.NewDesc(
+			"oar_project_containers",
+			"Number of containers of a project.",
+			[]string{"project"}, nil),
+		containersRunning: prometheus.NewDesc(
+			"oar_project_containers_running",
+			"Number of running containers of a project.",
+			[]string{"project"}, nil),
+		sinceLastDeployment: prometheus.NewDesc(
+			"oar_project_seconds_since_last_successful_deployment",
+			"Time since the last successful deployment of a project finished.",
+			[]string{"project"}, nil),
+	}
+}
+
+// MetricsTokenValid reports whether r carries token, which must not be empty, as its bearer token
+func MetricsTokenValid(r *http.Request, token string) bool {
+	if token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+token)) == 1
+}
+
+// NewMetricsHandler serves Oar's metrics and those of the projects of projectService in the Prometheus text format
+func NewMetricsHandler(projectService ProjectManager) http.Handler {
+	projectRegistry := prometheus.NewRegistry()
+	projectRegistry.MustRegister(NewProjectCollector(projectService))
+
+	return promhttp.HandlerFor(
+		prometheus.Gatherers{metricsRegistry, projectRegistry},
+		promhttp.HandlerOpts{ErrorLog: slog.NewLogLogger(slog.Default().Handler(), slog.LevelError)},
+	)
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func scrapeMetrics(t *testing.T, projectService ProjectManager) string {
+	t.Helper()
+	w := httptest.NewRecorder()
+	NewMetricsHandler(projectService).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	return w.Body.String()
+}
+
+func TestMetricsHandler_ProjectMetrics(t *testing.T) {
+	project := &Project{ID: uuid.New(), Name: "metrics-app"}
+	finishedAt := time.Now().Add(-time.Hour)
+	projectService := &MockProjectManager{
+		ListFunc: func() ([]*Project, error) {
+			return []*Project{project}, nil
+		},
+		GetStatusFunc: func(projectID uuid.UUID) (*ComposeStatus, error) {
+			return &ComposeStatus{Containers: []ContainerInfo{
+				{Service: "web", State: "running"},
+				{Service: "db", State: "running"},
+				{Service: "migrate", State: "exited"},
+			}}, nil
+		},
+		ListDeploymentsFunc: func(projectID uuid.UUID) ([]*Deployment, error) {
+			return []*Deployment{
+				{Status: DeploymentStatusFailed, UpdatedAt: time.Now()},
+				// Saved again after it finished
+				{Status: DeploymentStatusCompleted, FinishedAt: &finishedAt, UpdatedAt: time.Now()},
+			}, nil
+		},
+	}
+
+	body := scrapeMetrics(t, projectService)
+
+	assert.Contains(t, body, `oar_project_containers{project="metrics-app"} 3`)
+	assert.Contains(t, body, `oar_project_containers_running{project="metrics-app"} 2`)
+	assert.Contains(t, body, `oar_project_seconds_since_last_successful_deployment{project="metrics-app"} 36`)
+	assert.Contains(t, body, "oar_watcher_poll_duration_seconds_count")
+	assert.Contains(t, body, "go_goroutines")
+}
+
+func TestMetricsHandler_NoSuccessfulDeployment(t *testing.T) {
+	project := &Project{ID: uuid.New(), Name: "never-deployed"}
+	projectService := &MockProjectManager{
+		ListFunc: func() ([]*Project, error) {
+			return []*Project{project}, nil
+		},
+		GetStatusFunc: func(projectID uuid.UUID) (*ComposeStatus, error) {
+			return &ComposeStatus{}, nil
+		},
+		ListDeploymentsFunc: func(projectID uuid.UUID) ([]*Deployment, error) {
+			return []*Deployment{{Status: DeploymentStatusFailed}}, nil
+		},
+	}
+
+	body := scrapeMetrics(t, projectService)
+
+	assert.Contains(t, body, `oar_project_containers{project="never-deployed"} 0`)
+	assert.NotContains(t, body, `oar_project_seconds_since_last_successful_deployment{project="never-deployed"}`)
+}
+
+func TestMetricsHandler_RecordedMetrics(t *testing.T) {
+	project := &Project{Name: "recorded-app"}
+
+	observeDeployment(project, &Deployment{StartedAt: time.Now().Add(-time.Minute)}, DeploymentStatusCompleted)
+	observeDeployment(project, &Deployment{StartedAt: time.Now().Add(-time.Second)}, DeploymentStatusFailed)
+	observeDeployment(project, &Deployment{StartedAt: time.Now().Add(-time.Second)}, DeploymentStatusFailed)
+	countGitFetchError(project)
+
+	body := scrapeMetrics(t, &MockProjectManager{})
+
+	assert.Contains(t, body, `oar_deployments_total{project="recorded-app",status="completed"} 1`)
+	assert.Contains(t, body, `oar_deployments_total{project="recorded-app",status="failed"} 2`)
+	assert.Contains(t, body, `oar_deployment_duration_seconds_count{project="recorded-app",status="completed"} 1`)
+	assert.Contains(t, body, `oar_git_fetch_errors_total{project="recorded-app"} 1`)
+}
+
+func TestMetricsHandler_CachesProjectMetrics(t *testing.T) {
+	project := &Project{ID: uuid.New(), Name: "cached-app"}
+	statusCalls := 0
+	handler := NewMetricsHandler(&MockProjectManager{
+		ListFunc: func() ([]*Project, error) {
+			return []*Project{project}, nil
+		},
+		GetStatusFunc: func(projectID uuid.UUID) (*ComposeStatus, error) {
+			statusCalls++
+			return &ComposeStatus{Containers: []ContainerInfo{{Service: "web", State: "running"}}}, nil
+		},
+	})
+
+	// Scrapes in quick succession list the containers of the projects once
+	for range 3 {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `oar_project_containers_running{project="cached-app"} 1`)
+	}
+	assert.Equal(t, 1, statusCalls)
+}
+
+func TestMetricsTokenValid(t *testing.T) {
+	request := func(authorization string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		if authorization != "" {
+			r.Header.Set("Authorization", authorization)
+		}
+		return r
+	}
+
+	assert.True(t, MetricsTokenValid(request("Bearer scrape-token"), "scrape-token"))
+	assert.False(t, MetricsTokenValid(request("Bearer other-token"), "scrape-token"))
+	assert.False(t, MetricsTokenValid(request("scrape-token"), "scrape-token"))
+	assert.False(t, MetricsTokenValid(request(""), "scrape-token"))
+	// Without a configured token, no request carries it
+	assert.False(t, MetricsTokenValid(request("Bearer "), ""))
+}
@@ -1,7 +1,11 @@
 package services
 
 import (
+	"context"
+
 	"fmt"
+	"io"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,6 +20,11 @@ type MockGitExecutor struct {
 	FetchFunc                 func(gitBranch string, gitAuth *GitAuthConfig, workingDir string) error
 	GetLatestCommitFunc       func(workingDir string) (string, error)
 	GetRemoteLatestCommitFunc func(workingDir string, gitBranch string) (string, error)
+	FetchTagsFunc             func(gitAuth *GitAuthConfig, workingDir string) error
+	ListTagsFunc              func(workingDir string) (map[string]string, error)
+	CheckoutFunc              func(commit string, workingDir string) error
+	LogFunc                   func(workingDir, from, to string, limit int) ([]GitCommit, error)
+	ExportFunc                func(commit, workingDir, dir string) error
 	TestAuthenticationFunc    func(gitURL string, gitAuth *GitAuthConfig) error
 	GetDefaultBranchFunc      func(gitURL string, gitAuth *GitAuthConfig) (string, error)
 }
@@ -55,6 +64,41 @@ func (m *MockGitExecutor) GetRemoteLatestCommit(workingDir string, gitBranch str
 	return "mock-remote-commit-hash", nil
 }
 
+func (m *MockGitExecutor) FetchTags(gitAuth *GitAuthConfig, workingDir string) error {
+	if m.FetchTagsFunc != nil {
+		return m.FetchTagsFunc(gitAuth, workingDir)
+	}
+	return nil
+}
+
+func (m *MockGitExecutor) ListTags(workingDir string) (map[string]string, error) {
+	if m.ListTagsFunc != nil {
+		return m.ListTagsFunc(workingDir)
+	}
+	return map[string]string{}, nil
+}
+
+func (m *MockGitExecutor) Checkout(commit string, workingDir string) error {
+	if m.CheckoutFunc != nil {
+		return m.CheckoutFunc(commit, workingDir)
+	}
+	return nil
+}
+
+func (m *MockGitExecutor) Log(workingDir, from, to string, limit int) ([]GitCommit, error) {
+	if m.LogFunc != nil {
+		return m.LogFunc(workingDir, from, to, limit)
+	}
+	return []GitCommit{}, nil
+}
+
+func (m *MockGitExecutor) Export(commit, workingDir, dir string) error {
+	if m.ExportFunc != nil {
+		return m.ExportFunc(commit, workingDir, dir)
+	}
+	return nil
+}
+
 func (m *MockGitExecutor) TestAuthentication(gitURL string, gitAuth *GitAuthConfig) error {
 	if m.TestAuthenticationFunc != nil {
 		return m.TestAuthenticationFunc(gitURL, gitAuth)
@@ -200,16 +244,65 @@ func (m *MockComposeProject) Status() (*ComposeStatus, error) {
 	return args.Get(0).(*ComposeStatus), args.Error(1)
 }
 
-func (m *MockComposeProject) UpStreaming(outputChan chan<- string) error {
+func (m *MockComposeProject) UpStreaming(ctx context.Context, outputChan chan<- string) error {
 	args := m.Called(outputChan)
 	return args.Error(0)
 }
 
-func (m *MockComposeProject) UpPiping() error {
+func (m *MockComposeProject) UpPiping(ctx context.Context) error {
 	args := m.Called()
 	return args.Error(0)
 }
 
+func (m *MockComposeProject) PullStreaming(ctx context.Context, outputChan chan<- string) error {
+	args := m.Called(outputChan)
+	return args.Error(0)
+}
+
+func (m *MockComposeProject) Exec(ctx context.Context, service, command string) (string, error) {
+	args := m.Called(service, command)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockComposeProject) RunHookStreaming(ctx context.Context, hook Hook, outputChan chan<- string) error {
+	args := m.Called(hook, outputChan)
+	return args.Error(0)
+}
+
+func (m *MockComposeProject) Volumes(ctx context.Context) ([]ComposeVolume, error) {
+	args := m.Called()
+	volumes, _ := args.Get(0).([]ComposeVolume)
+	return volumes, args.Error(1)
+}
+
+func (m *MockComposeProject) BackupVolume(ctx context.Context, volume string, w io.Writer) error {
+	args := m.Called(volume, w)
+	return args.Error(0)
+}
+
+func (m *MockComposeProject) RestoreVolume(ctx context.Context, volume string, r io.Reader) error {
+	args := m.Called(volume, r)
+	return args.Error(0)
+}
+
+func (m *MockComposeProject) Drift(ctx context.Context) ([]ServiceDrift, error) {
+	args := m.Called()
+	drift, _ := args.Get(0).([]ServiceDrift)
+	return drift, args.Error(1)
+}
+
+func (m *MockComposeProject) DownServices(ctx context.Context) ([]string, error) {
+	args := m.Called()
+	down, _ := args.Get(0).([]string)
+	return down, args.Error(1)
+}
+
+func (m *MockComposeProject) ImageUpdates(ctx context.Context) ([]ImageUpdate, error) {
+	args := m.Called()
+	updates, _ := args.Get(0).([]ImageUpdate)
+	return updates, args.Error(1)
+}
+
 func (m *MockComposeProject) DownStreaming(outputChan chan<- string) error {
 	args := m.Called(outputChan)
 	return args.Error(0)
@@ -232,21 +325,43 @@ func (m *MockComposeProject) LogsPiping() error {
 
 // MockProjectManager implements the ProjectManager interface for testing
 type MockProjectManager struct {
-	ListFunc             func() ([]*Project, error)
-	GetFunc              func(id uuid.UUID) (*Project, error)
-	CreateFunc           func(project *Project) (*Project, error)
-	UpdateFunc           func(project *Project) error
-	RemoveFunc           func(projectID uuid.UUID) error
-	DeployStreamingFunc  func(projectID uuid.UUID, pull bool, outputChan chan<- string) error
-	DeployPipingFunc     func(projectID uuid.UUID, pull bool) error
-	StopFunc             func(projectID uuid.UUID) error
-	StopStreamingFunc    func(projectID uuid.UUID, outputChan chan<- string) error
-	StopPipingFunc       func(projectID uuid.UUID) error
-	GetLogsStreamingFunc func(projectID uuid.UUID, outputChan chan<- string) error
-	GetLogsPipingFunc    func(projectID uuid.UUID) error
-	GetConfigFunc        func(projectID uuid.UUID) (string, error)
-	GetStatusFunc        func(projectID uuid.UUID) (*ComposeStatus, error)
-	ListDeploymentsFunc  func(projectID uuid.UUID) ([]*Deployment, error)
+	ListFunc              func() ([]*Project, error)
+	GetFunc               func(id uuid.UUID) (*Project, error)
+	CreateFunc            func(project *Project) (*Project, error)
+	UpdateFunc            func(project *Project) error
+	RemoveFunc            func(projectID uuid.UUID) error
+	CancelDeploymentFunc  func(projectID uuid.UUID) error
+	LockProjectFunc       func(projectID uuid.UUID, options DeployOptions) (func(), error)
+	DeployStreamingFunc   func(projectID uuid.UUID, options DeployOptions, outputChan chan<- string) error
+	DeployPipingFunc      func(projectID uuid.UUID, options DeployOptions) error
+	RollbackPipingFunc    func(projectID uuid.UUID, target string, options DeployOptions) error
+	RequestApprovalFunc   func(projectID uuid.UUID, commit string, options DeployOptions) (*Deployment, error)
+	ApprovePipingFunc     func(projectID, deploymentID uuid.UUID, options DeployOptions) error
+	RejectDeploymentFunc  func(projectID, deploymentID uuid.UUID, options DeployOptions) error
+	StopFunc              func(projectID uuid.UUID) error
+	StopStreamingFunc     func(projectID uuid.UUID, outputChan chan<- string) error
+	StopPipingFunc        func(projectID uuid.UUID) error
+	GetLogsStreamingFunc  func(projectID uuid.UUID, outputChan chan<- string) error
+	GetLogsPipingFunc     func(projectID uuid.UUID) error
+	GetConfigFunc         func(projectID uuid.UUID) (string, error)
+	GetStatusFunc         func(projectID uuid.UUID) (*ComposeStatus, error)
+	DetectDriftFunc       func(ctx context.Context, projectID uuid.UUID) ([]ServiceDrift, error)
+	ReconcileFunc         func(ctx context.Context, projectID uuid.UUID) ([]string, error)
+	CheckImagesFunc       func(ctx context.Context, projectID uuid.UUID) ([]ImageUpdate, error)
+	PreviewDeployFunc     func(ctx context.Context, projectID uuid.UUID, options DeployOptions) (*DeployPreview, error)
+	ListDeploymentsFunc   func(projectID uuid.UUID) ([]*Deployment, error)
+	RollbackStreamingFunc func(
+		projectID uuid.UUID,
+		target string,
+		options DeployOptions,
+		outputChan chan<- string,
+	) error
+	ApproveStreamingFunc func(
+		projectID uuid.UUID,
+		deploymentID uuid.UUID,
+		options DeployOptions,
+		outputChan chan<- string,
+	) error
 }
 
 func (m *MockProjectManager) List() ([]*Project, error) {
@@ -284,20 +399,107 @@ func (m *MockProjectManager) Remove(projectID uuid.UUID) error {
 	return nil
 }
 
-func (m *MockProjectManager) DeployStreaming(projectID uuid.UUID, pull bool, outputChan chan<- string) error {
+func (m *MockProjectManager) DeployStreaming(
+	ctx context.Context,
+	projectID uuid.UUID,
+	options DeployOptions,
+	outputChan chan<- string,
+) error {
 	if m.DeployStreamingFunc != nil {
-		return m.DeployStreamingFunc(projectID, pull, outputChan)
+		return m.DeployStreamingFunc(projectID, options, outputChan)
 	}
 	return nil
 }
 
-func (m *MockProjectManager) DeployPiping(projectID uuid.UUID, pull bool) error {
+func (m *MockProjectManager) DeployPiping(ctx context.Context, projectID uuid.UUID, options DeployOptions) error {
 	if m.DeployPipingFunc != nil {
-		return m.DeployPipingFunc(projectID, pull)
+		return m.DeployPipingFunc(projectID, options)
+	}
+	return nil
+}
+
+func (m *MockProjectManager) RollbackStreaming(
+	ctx context.Context,
+	projectID uuid.UUID,
+	target string,
+	options DeployOptions,
+	outputChan chan<- string,
+) error {
+	if m.RollbackStreamingFunc != nil {
+		return m.RollbackStreamingFunc(projectID, target, options, outputChan)
+	}
+	return nil
+}
+
+func (m *MockProjectManager) RollbackPiping(
+	ctx context.Context,
+	projectID uuid.UUID,
+	target string,
+	options DeployOptions,
+) error {
+	if m.RollbackPipingFunc != nil {
+		return m.RollbackPipingFunc(projectID, target, options)
+	}
+	return nil
+}
+
+func (m *MockProjectManager) RequestApproval(
+	projectID uuid.UUID,
+	commit string,
+	options DeployOptions,
+) (*Deployment, error) {
+	if m.RequestApprovalFunc != nil {
+		return m.RequestApprovalFunc(projectID, commit, options)
+	}
+	return nil, nil
+}
+
+func (m *MockProjectManager) ApproveStreaming(
+	ctx context.Context,
+	projectID uuid.UUID,
+	deploymentID uuid.UUID,
+	options DeployOptions,
+	outputChan chan<- string,
+) error {
+	if m.ApproveStreamingFunc != nil {
+		return m.ApproveStreamingFunc(projectID, deploymentID, options, outputChan)
+	}
+	return nil
+}
+
+func (m *MockProjectManager) ApprovePiping(
+	ctx context.Context,
+	projectID uuid.UUID,
+	deploymentID uuid.UUID,
+	options DeployOptions,
+) error {
+	if m.ApprovePipingFunc != nil {
+		return m.ApprovePipingFunc(projectID, deploymentID, options)
 	}
 	return nil
 }
 
+func (m *MockProjectManager) RejectDeployment(projectID, deploymentID uuid.UUID, options DeployOptions) error {
+	if m.RejectDeploymentFunc != nil {
+		return m.RejectDeploymentFunc(projectID, deploymentID, options)
+	}
+	return nil
+}
+
+func (m *MockProjectManager) CancelDeployment(projectID uuid.UUID) error {
+	if m.CancelDeploymentFunc != nil {
+		return m.CancelDeploymentFunc(projectID)
+	}
+	return nil
+}
+
+func (m *MockProjectManager) LockProject(projectID uuid.UUID, options DeployOptions) (func(), error) {
+	if m.LockProjectFunc != nil {
+		return m.LockProjectFunc(projectID, options)
+	}
+	return func() {}, nil
+}
+
 func (m *MockProjectManager) Stop(projectID uuid.UUID) error {
 	if m.StopFunc != nil {
 		return m.StopFunc(projectID)
@@ -347,9 +549,183 @@ func (m *MockProjectManager) GetStatus(projectID uuid.UUID) (*ComposeStatus, err
 	return &ComposeStatus{}, nil
 }
 
+func (m *MockProjectManager) DetectDrift(ctx context.Context, projectID uuid.UUID) ([]ServiceDrift, error) {
+	if m.DetectDriftFunc != nil {
+		return m.DetectDriftFunc(ctx, projectID)
+	}
+	return nil, nil
+}
+
+func (m *MockProjectManager) Reconcile(ctx context.Context, projectID uuid.UUID) ([]string, error) {
+	if m.ReconcileFunc != nil {
+		return m.ReconcileFunc(ctx, projectID)
+	}
+	return nil, nil
+}
+
+func (m *MockProjectManager) CheckImages(ctx context.Context, projectID uuid.UUID) ([]ImageUpdate, error) {
+	if m.CheckImagesFunc != nil {
+		return m.CheckImagesFunc(ctx, projectID)
+	}
+	return nil, nil
+}
+
+func (m *MockProjectManager) PreviewDeploy(
+	ctx context.Context,
+	projectID uuid.UUID,
+	options DeployOptions,
+) (*DeployPreview, error) {
+	if m.PreviewDeployFunc != nil {
+		return m.PreviewDeployFunc(ctx, projectID, options)
+	}
+	return &DeployPreview{}, nil
+}
+
 func (m *MockProjectManager) ListDeployments(projectID uuid.UUID) ([]*Deployment, error) {
 	if m.ListDeploymentsFunc != nil {
 		return m.ListDeploymentsFunc(projectID)
 	}
 	return []*Deployment{}, nil
 }
+
+// MockNotificationSender records the notifications it is asked to send
+type MockNotificationSender struct {
+	mu            sync.Mutex
+	notifications []*Notification
+}
+
+func (m *MockNotificationSender) Notify(notification *Notification) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.notifications = append(m.notifications, notification)
+}
+
+// Events returns the events of the recorded notifications in order
+func (m *MockNotificationSender) Events() []NotificationEvent {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	events := make([]NotificationEvent, len(m.notifications))
+	for i, notification := range m.notifications {
+		events[i] = notification.Event
+	}
+	return events
+}
+
+// MockNotificationManager implements the NotificationManager interface for testing
+type MockNotificationManager struct{}
+
+func (m *MockNotificationManager) ListNotifiers(projectID uuid.UUID) ([]*Notifier, error) {
+	return []*Notifier{}, nil
+}
+
+func (m *MockNotificationManager) AddNotifier(notifier *Notifier) error {
+	return nil
+}
+
+func (m *MockNotificationManager) RemoveNotifier(projectID, notifierID uuid.UUID) error {
+	return nil
+}
+
+func (m *MockNotificationManager) TestNotifier(projectID, notifierID uuid.UUID) error {
+	return nil
+}
+
+// MockVolumeBackupManager implements the VolumeBackupManager interface for testing
+type MockVolumeBackupManager struct{}
+
+func (m *MockVolumeBackupManager) List(projectID uuid.UUID) ([]*VolumeBackup, error) {
+	return []*VolumeBackup{}, nil
+}
+
+func (m *MockVolumeBackupManager) Create(ctx context.Context, projectID uuid.UUID) (*VolumeBackup, error) {
+	return &VolumeBackup{ProjectID: projectID}, nil
+}
+
+func (m *MockVolumeBackupManager) Restore(
+	ctx context.Context,
+	projectID uuid.UUID,
+	name string,
+	options DeployOptions,
+) error {
+	return nil
+}
+
+// MockCatalogManager implements the CatalogManager interface for testing
+type MockCatalogManager struct{}
+
+func (m *MockCatalogManager) List() ([]*Catalog, error) {
+	return []*Catalog{}, nil
+}
+
+func (m *MockCatalogManager) Get(id uuid.UUID) (*Catalog, error) {
+	return &Catalog{ID: id}, nil
+}
+
+func (m *MockCatalogManager) Create(catalog *Catalog) (*Catalog, error) {
+	return catalog, nil
+}
+
+func (m *MockCatalogManager) Update(catalog *Catalog) error {
+	return nil
+}
+
+func (m *MockCatalogManager) Remove(id uuid.UUID, removeProjects bool) error {
+	return nil
+}
+
+func (m *MockCatalogManager) Sync(ctx context.Context, id uuid.UUID) (*CatalogSyncResult, error) {
+	return &CatalogSyncResult{}, nil
+}
+
+// MockCatalogRepository keeps catalogs in memory for testing
+type MockCatalogRepository struct {
+	catalogs map[uuid.UUID]*Catalog
+}
+
+func NewMockCatalogRepository() *MockCatalogRepository {
+	return &MockCatalogRepository{catalogs: make(map[uuid.UUID]*Catalog)}
+}
+
+func (m *MockCatalogRepository) FindByID(id uuid.UUID) (*Catalog, error) {
+	catalog, ok := m.catalogs[id]
+	if !ok {
+		return nil, fmt.Errorf("catalog not found")
+	}
+	found := *catalog
+	return &found, nil
+}
+
+func (m *MockCatalogRepository) FindByName(name string) (*Catalog, error) {
+	for _, catalog := range m.catalogs {
+		if catalog.Name == name {
+			found := *catalog
+			return &found, nil
+		}
+	}
+	return nil, fmt.Errorf("catalog not found")
+}
+
+func (m *MockCatalogRepository) List() ([]*Catalog, error) {
+	catalogs := make([]*Catalog, 0, len(m.catalogs))
+	for _, catalog := range m.catalogs {
+		catalogs = append(catalogs, catalog)
+	}
+	return catalogs, nil
+}
+
+func (m *MockCatalogRepository) Create(catalog *Catalog) error {
+	saved := *catalog
+	m.catalogs[catalog.ID] = &saved
+	return nil
+}
+
+func (m *MockCatalogRepository) Update(catalog *Catalog) error {
+	saved := *catalog
+	m.catalogs[catalog.ID] = &saved
+	return nil
+}
+
+func (m *MockCatalogRepository) Delete(id uuid.UUID) error {
+	delete(m.catalogs, id)
+	return nil
+}
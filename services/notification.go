@@ -0,0 +1,542 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// NotifierType is the kind of service a notifier delivers notifications to
+type NotifierType string
+
+const (
+	NotifierTypeWebhook NotifierType = "webhook" // JSON POST of the notification to any URL
+	NotifierTypeSlack   NotifierType = "slack"   // Slack-compatible incoming webhook, e.g. Slack or Mattermost
+	NotifierTypeEmail   NotifierType = "email"   // Email over SMTP
+	NotifierTypeNtfy    NotifierType = "ntfy"    // ntfy topic
+	NotifierTypeGotify  NotifierType = "gotify"  // Gotify server
+)
+
+func ParseNotifierType(s string) (NotifierType, error) {
+	switch t := NotifierType(s); t {
+	case NotifierTypeWebhook, NotifierTypeSlack, NotifierTypeEmail, NotifierTypeNtfy, NotifierTypeGotify:
+		return t, nil
+	default:
+		return "", fmt.Errorf("invalid notifier type: %q (must be webhook, slack, email, ntfy or gotify)", s)
+	}
+}
+
+// NotificationEvent is something that happened to a project that notifiers can notify about
+type NotificationEvent string
+
+const (
+	NotificationEventDeploymentStarted   NotificationEvent = "deployment_started"
+	NotificationEventDeploymentSucceeded NotificationEvent = "deployment_succeeded"
+	NotificationEventDeploymentFailed    NotificationEvent = "deployment_failed"
+	NotificationEventDriftDetected       NotificationEvent = "drift_detected"
+	NotificationEventApprovalRequired    NotificationEvent = "approval_required"
+	// NotificationEventTest is sent when testing a notifier, notifiers can't subscribe to it
+	NotificationEventTest NotificationEvent = "test"
+)
+
+// NotificationEvents are the events notifiers can subscribe to
+var NotificationEvents = []NotificationEvent{
+	NotificationEventDeploymentStarted,
+	NotificationEventDeploymentSucceeded,
+	NotificationEventDeploymentFailed,
+	NotificationEventDriftDetected,
+	NotificationEventApprovalRequired,
+}
+
+func ParseNotificationEvent(s string) (NotificationEvent, error) {
+	for _, event := range NotificationEvents {
+		if string(event) == s {
+			return event, nil
+		}
+	}
+	return "", fmt.Errorf(
+		"invalid notification event: %q (must be deployment_started, deployment_succeeded, "+
+			"deployment_failed, drift_detected or approval_required)", s)
+}
+
+var (
+	// ErrInvalidNotifier is returned when a notifier's configuration is incomplete for its type
+	ErrInvalidNotifier = errors.New("invalid notifier")
+	// ErrNotificationDelivery is returned when a notifier's service rejects or can't be reached for a notification
+	ErrNotificationDelivery = errors.New("notification delivery failed")
+)
+
+const (
+	// notificationTimeout bounds the delivery of a notification to a single notifier
+	notificationTimeout = 10 * time.Second
+	// notificationOutputLines is how many trailing lines of deployment output a notification includes
+	notificationOutputLines = 20
+)
+
+// Notification describes an event of a project. Webhook notifiers receive it as JSON.
+type Notification struct {
+	Event        NotificationEvent `json:"event"`
+	ProjectID    uuid.UUID         `json:"project_id"`
+	ProjectName  string            `json:"project_name"`
+	DeploymentID *uuid.UUID        `json:"deployment_id,omitempty"`
+	CommitHash   string            `json:"commit_hash,omitempty"`
+	Output       string            `json:"output,omitempty"` // Last lines of the deployment output, or the drift
+	Time         time.Time         `json:"time"`
+}
+
+// NewDeploymentNotification creates a notification about a deployment of project
+func NewDeploymentNotification(event NotificationEvent, project *Project, deployment *Deployment) *Notification {
+	return &Notification{
+		Event:        event,
+		ProjectID:    project.ID,
+		ProjectName:  project.Name,
+		DeploymentID: &deployment.ID,
+		CommitHash:   deployment.CommitHash,
+		Output:       outputExcerpt(deployment.Output, notificationOutputLines),
+		Time:         time.Now(),
+	}
+}
+
+// NewDriftNotification creates a notification about project going out of sync, with the differences found
+func NewDriftNotification(project *Project, drift []string) *Notification {
+	return &Notification{
+		Event:       NotificationEventDriftDetected,
+		ProjectID:   project.ID,
+		ProjectName: project.Name,
+		CommitHash:  project.LastCommitStr(),
+		Output:      outputExcerpt(strings.Join(drift, "\n"), notificationOutputLines),
+		Time:        time.Now(),
+	}
+}
+
+// Title summarizes the notification in one line, e.g. "web: deployment failed (1a2b3c4d)"
+func (n *Notification) Title() string {
+	var what string
+	switch n.Event {
+	case NotificationEventDeploymentStarted:
+		what = "deployment started"
+	case NotificationEventDeploymentSucceeded:
+		what = "deployment succeeded"
+	case NotificationEventDeploymentFailed:
+		what = "deployment failed"
+	case NotificationEventDriftDetected:
+		what = "drift detected"
+	case NotificationEventApprovalRequired:
+		what = "deployment awaiting approval"
+	case NotificationEventTest:
+		what = "test notification"
+	default:
+		what = string(n.Event)
+	}
+
+	title := fmt.Sprintf("%s: %s", n.ProjectName, what)
+	if n.CommitHash != "" {
+		title += fmt.Sprintf(" (%s)", shortCommit(n.CommitHash))
+	}
+	return title
+}
+
+// Text is the title followed by the output excerpt, if any
+func (n *Notification) Text() string {
+	if n.Output == "" {
+		return n.Title()
+	}
+	return n.Title() + "\n\n" + n.Output
+}
+
+// NotificationService manages the notifiers of projects and delivers notifications to them
+type NotificationService struct {
+	notifierRepository NotifierRepository
+	projectRepository  ProjectRepository
+	client             *http.Client
+}
+
+// Ensure NotificationService implements NotificationManager and NotificationSender
+var (
+	_ NotificationManager = (*NotificationService)(nil)
+	_ NotificationSender  = (*NotificationService)(nil)
+)
+
+// ListNotifiers lists the notifiers of a project
+func (s *NotificationService) ListNotifiers(projectID uuid.UUID) ([]*Notifier, error) {
+	return s.notifierRepository.ListByProjectID(projectID)
+}
+
+// AddNotifier validates a notifier and adds it to its project
+func (s *NotificationService) AddNotifier(notifier *Notifier) error {
+	if err := ValidateNotifier(notifier); err != nil {
+		return err
+	}
+	if notifier.ID == uuid.Nil {
+		notifier.ID = uuid.New()
+	}
+	return s.notifierRepository.Create(notifier)
+}
+
+// RemoveNotifier removes a notifier from a project
+func (s *NotificationService) RemoveNotifier(projectID, notifierID uuid.UUID) error {
+	if _, err := s.findNotifier(projectID, notifierID); err != nil {
+		return err
+	}
+	return s.notifierRepository.Delete(notifierID)
+}
+
+// TestNotifier sends a test notification to a notifier and returns the delivery error, if any
+func (s *NotificationService) TestNotifier(projectID, notifierID uuid.UUID) error {
+	notifier, err := s.findNotifier(projectID, notifierID)
+	if err != nil {
+		return err
+	}
+	project, err := s.projectRepository.FindByID(projectID)
+	if err != nil {
+		return err
+	}
+
+	return s.send(notifier, &Notification{
+		Event:       NotificationEventTest,
+		ProjectID:   project.ID,
+		ProjectName: project.Name,
+		Time:        time.Now(),
+	})
+}
+
+// Notify delivers a notification to all notifiers of its project subscribed to its event. Deliveries run
+// concurrently and Notify waits for them to finish. Failures are logged, they never fail the caller.
+func (s *NotificationService) Notify(notification *Notification) {
+	notifiers, err := s.notifierRepository.ListByProjectID(notification.ProjectID)
+	if err != nil {
+		slog.Error("Service operation failed",
+			"layer", "service",
+			"operation", "notify",
+			"project_id", notification.ProjectID,
+			"event", notification.Event,
+			"error", err)
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, notifier := range notifiers {
+		if !notifier.Subscribed(notification.Event) {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := s.send(notifier, notification); err != nil {
+				slog.Error("Service operation failed",
+					"layer", "service",
+					"operation", "notify",
+					"project_id", notification.ProjectID,
+					"notifier_id", notifier.ID,
+					"notifier_type", notifier.Type,
+					"event", notification.Event,
+					"error", err)
+				return
+			}
+			slog.Debug("Notification sent",
+				"project_id", notification.ProjectID,
+				"notifier_id", notifier.ID,
+				"notifier_type", notifier.Type,
+				"event", notification.Event)
+		}()
+	}
+	wg.Wait()
+}
+
+// findNotifier returns a notifier of a project, treating notifiers of other projects as not found
+func (s *NotificationService) findNotifier(projectID, notifierID uuid.UUID) (*Notifier, error) {
+	notifier, err := s.notifierRepository.FindByID(notifierID)
+	if err != nil {
+		return nil, err
+	}
+	if notifier.ProjectID != projectID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return notifier, nil
+}
+
+// send delivers a notification to a single notifier
+func (s *NotificationService) send(notifier *Notifier, notification *Notification) error {
+	ctx, cancel := context.WithTimeout(context.Background(), notificationTimeout)
+	defer cancel()
+
+	var err error
+	switch notifier.Type {
+	case NotifierTypeWebhook:
+		err = s.postJSON(ctx, notifier.Config.URL, notification, nil)
+	case NotifierTypeSlack:
+		err = s.postJSON(ctx, notifier.Config.URL, map[string]string{"text": slackText(notification)}, nil)
+	case NotifierTypeNtfy:
+		err = s.sendNtfy(ctx, notifier, notification)
+	case NotifierTypeGotify:
+		err = s.sendGotify(ctx, notifier, notification)
+	case NotifierTypeEmail:
+		err = sendEmail(ctx, notifier, notification)
+	default:
+		return fmt.Errorf("%w: unsupported notifier type %q", ErrInvalidNotifier, notifier.Type)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrNotificationDelivery, notifier.Type, err)
+	}
+	return nil
+}
+
+// sendNtfy publishes a notification to an ntfy topic, see https://docs.ntfy.sh/publish/
+func (s *NotificationService) sendNtfy(ctx context.Context, notifier *Notifier, notification *Notification) error {
+	header := http.Header{
+		"Title":    {notification.Title()},
+		"Priority": {"default"},
+	}
+	switch notification.Event {
+	case NotificationEventDeploymentFailed, NotificationEventDriftDetected:
+		header.Set("Priority", "high")
+		header.Set("Tags", "warning")
+	case NotificationEventDeploymentSucceeded:
+		header.Set("Tags", "white_check_mark")
+	case NotificationEventDeploymentStarted:
+		header.Set("Tags", "rocket")
+	case NotificationEventApprovalRequired:
+		header.Set("Tags", "hourglass")
+	}
+	if notifier.Config.Token != "" {
+		header.Set("Authorization", "Bearer "+notifier.Config.Token)
+	}
+
+	body := notification.Output
+	if body == "" {
+		body = notification.Title()
+	}
+	return s.post(ctx, notifier.Config.URL, "text/plain; charset=utf-8", []byte(body), header)
+}
+
+// sendGotify posts a notification as a Gotify message, see https://gotify.net/docs/pushmsg
+func (s *NotificationService) sendGotify(ctx context.Context, notifier *Notifier, notification *Notification) error {
+	priority := 5
+	if notification.Event == NotificationEventDeploymentFailed || notification.Event == NotificationEventDriftDetected {
+		priority = 8
+	}
+
+	message := map[string]any{
+		"title":    notification.Title(),
+		"message":  notification.Text(),
+		"priority": priority,
+	}
+	header := http.Header{"X-Gotify-Key": {notifier.Config.Token}}
+	return s.postJSON(ctx, strings.TrimRight(notifier.Config.URL, "/")+"/message", message, header)
+}
+
+func (s *NotificationService) postJSON(ctx context.Context, url string, body any, header http.Header) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	return s.post(ctx, url, "application/json", data, header)
+}
+
+func (s *NotificationService) post(
+	ctx context.Context,
+	url string,
+	contentType string,
+	body []byte,
+	header http.Header,
+) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("User-Agent", "Oar")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("server responded with HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// sendEmail sends a notification as a plain text email. The connection is upgraded with STARTTLS when the
+// server supports it. Delivery gives up when ctx is done, so that an unresponsive server doesn't hold up
+// deployments.
+func sendEmail(ctx context.Context, notifier *Notifier, notification *Notification) error {
+	config := notifier.Config
+	port := config.SMTPPort
+	if port == 0 {
+		port = 587
+	}
+	addr := net.JoinHostPort(config.SMTPHost, strconv.Itoa(port))
+
+	var auth smtp.Auth
+	if config.SMTPUsername != "" {
+		auth = smtp.PlainAuth("", config.SMTPUsername, config.SMTPPassword, config.SMTPHost)
+	}
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", config.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(config.To, ", "))
+	fmt.Fprintf(&msg, "Subject: [Oar] %s\r\n", notification.Title())
+	fmt.Fprintf(&msg, "Date: %s\r\n", notification.Time.Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(strings.ReplaceAll(notification.Text(), "\n", "\r\n"))
+	msg.WriteString("\r\n")
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close() //nolint:errcheck
+	// Reads and writes time out with ctx
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return err
+		}
+	}
+	client, err := smtp.NewClient(conn, config.SMTPHost)
+	if err != nil {
+		return err
+	}
+	defer client.Close() //nolint:errcheck
+	return deliverEmail(client, config, auth, msg.String())
+}
+
+// deliverEmail sends a message over an SMTP connection like smtp.SendMail
+func deliverEmail(client *smtp.Client, config NotifierConfig, auth smtp.Auth, msg string) error {
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: config.SMTPHost}); err != nil {
+			return err
+		}
+	}
+	if auth != nil {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return errors.New("smtp: server doesn't support AUTH")
+		}
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(config.From); err != nil {
+		return err
+	}
+	for _, to := range config.To {
+		if err := client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write([]byte(msg)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// slackText formats a notification as Slack mrkdwn, with the output excerpt in a code block
+func slackText(notification *Notification) string {
+	text := "*" + notification.Title() + "*"
+	if notification.Output != "" {
+		text += "\n```\n" + notification.Output + "\n```"
+	}
+	return text
+}
+
+// ValidateNotifier checks that a notifier has everything its type needs to deliver notifications
+func ValidateNotifier(notifier *Notifier) error {
+	for _, event := range notifier.Events {
+		if _, err := ParseNotificationEvent(string(event)); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidNotifier, err)
+		}
+	}
+
+	config := notifier.Config
+	switch notifier.Type {
+	case NotifierTypeWebhook, NotifierTypeSlack, NotifierTypeNtfy:
+		return validateNotifierURL(config.URL)
+	case NotifierTypeGotify:
+		if config.Token == "" {
+			return fmt.Errorf("%w: gotify requires an application token", ErrInvalidNotifier)
+		}
+		return validateNotifierURL(config.URL)
+	case NotifierTypeEmail:
+		if config.SMTPHost == "" || config.From == "" || len(config.To) == 0 {
+			return fmt.Errorf("%w: email requires an SMTP host, a sender and at least one recipient",
+				ErrInvalidNotifier)
+		}
+		return nil
+	default:
+		_, err := ParseNotifierType(string(notifier.Type))
+		return fmt.Errorf("%w: %v", ErrInvalidNotifier, err)
+	}
+}
+
+func validateNotifierURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: %q is not an HTTP(S) URL", ErrInvalidNotifier, rawURL)
+	}
+	return nil
+}
+
+// Destination describes where a notifier delivers to without revealing credentials: the recipients of
+// emails, or the host of other services, as webhook paths usually embed a secret
+func (n *Notifier) Destination() string {
+	if n.Type == NotifierTypeEmail {
+		return strings.Join(n.Config.To, ", ")
+	}
+	u, err := url.Parse(n.Config.URL)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// outputExcerpt returns the last lines of a deployment's output
+func outputExcerpt(output string, lines int) string {
+	output = strings.TrimRight(output, "\n")
+	all := strings.Split(output, "\n")
+	if len(all) > lines {
+		all = all[len(all)-lines:]
+	}
+	return strings.Join(all, "\n")
+}
+
+// NewNotificationService creates a new NotificationService
+func NewNotificationService(
+	notifierRepository NotifierRepository,
+	projectRepository ProjectRepository,
+) *NotificationService {
+	return &NotificationService{
+		notifierRepository: notifierRepository,
+		projectRepository:  projectRepository,
+		client:             &http.Client{Timeout: notificationTimeout},
+	}
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"github.com/google/uuid"
+)
+
+// AuthorizedNotificationService wraps a NotificationManager and checks the acting user's role before every
+// operation. Notifiers hold credentials of external services, so managing them requires the admin role on
+// their project.
+type AuthorizedNotificationService struct {
+	inner NotificationManager
+	roles RoleManager
+	user  *User
+}
+
+// Ensure AuthorizedNotificationService implements NotificationManager
+var _ NotificationManager = (*AuthorizedNotificationService)(nil)
+
+func (s *AuthorizedNotificationService) ListNotifiers(projectID uuid.UUID) ([]*Notifier, error) {
+	if err := s.roles.Authorize(s.user, &projectID, RoleAdmin); err != nil {
+		return nil, err
+	}
+	return s.inner.ListNotifiers(projectID)
+}
+
+func (s *AuthorizedNotificationService) AddNotifier(notifier *Notifier) error {
+	if err := s.roles.Authorize(s.user, &notifier.ProjectID, RoleAdmin); err != nil {
+		return err
+	}
+	return s.inner.AddNotifier(notifier)
+}
+
+func (s *AuthorizedNotificationService) RemoveNotifier(projectID, notifierID uuid.UUID) error {
+	if err := s.roles.Authorize(s.user, &projectID, RoleAdmin); err != nil {
+		return err
+	}
+	return s.inner.RemoveNotifier(projectID, notifierID)
+}
+
+func (s *AuthorizedNotificationService) TestNotifier(projectID, notifierID uuid.UUID) error {
+	if err := s.roles.Authorize(s.user, &projectID, RoleAdmin); err != nil {
+		return err
+	}
+	return s.inner.TestNotifier(projectID, notifierID)
+}
+
+// NewAuthorizedNotificationService wraps inner so that every operation is performed on behalf of user
+func NewAuthorizedNotificationService(
+	inner NotificationManager,
+	roles RoleManager,
+	user *User,
+) *AuthorizedNotificationService {
+	return &AuthorizedNotificationService{
+		inner: inner,
+		roles: roles,
+		user:  user,
+	}
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthorizedNotificationService_RequiresProjectAdmin(t *testing.T) {
+	calls := map[string]func(s NotificationManager, p *Project) error{
+		"list": func(s NotificationManager, p *Project) error {
+			_, err := s.ListNotifiers(p.ID)
+			return err
+		},
+		"add": func(s NotificationManager, p *Project) error {
+			return s.AddNotifier(&Notifier{ProjectID: p.ID})
+		},
+		"remove": func(s NotificationManager, p *Project) error {
+			return s.RemoveNotifier(p.ID, uuid.New())
+		},
+		"test": func(s NotificationManager, p *Project) error {
+			return s.TestNotifier(p.ID, uuid.New())
+		},
+	}
+
+	for name, call := range calls {
+		for _, granted := range []Role{RoleNone, RoleViewer, RoleDeployer, RoleAdmin} {
+			t.Run(name+" as "+granted.String(), func(t *testing.T) {
+				roles, user, project := setupAuthorizationService(t)
+				if granted != RoleNone {
+					require.NoError(t, roles.Grant(user.ID, &project.ID, granted))
+				}
+				service := NewAuthorizedNotificationService(&MockNotificationManager{}, roles, user)
+
+				err := call(service, project)
+
+				if granted == RoleAdmin {
+					assert.NoError(t, err)
+				} else {
+					assert.ErrorIs(t, err, ErrPermissionDenied)
+				}
+			})
+		}
+	}
+}
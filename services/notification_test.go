@@ -0,0 +1,428 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// receivedRequest is an HTTP request captured by a notification stand-in server
+type receivedRequest struct {
+	Path   string
+	Header http.Header
+	Body   string
+}
+
+// setupNotificationServer starts an HTTP server that records requests and responds with status
+func setupNotificationServer(t *testing.T, status int) (*httptest.Server, func() []receivedRequest) {
+	t.Helper()
+	var mu sync.Mutex
+	var requests []receivedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		requests = append(requests, receivedRequest{Path: r.URL.Path, Header: r.Header, Body: string(body)})
+		mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []receivedRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]receivedRequest(nil), requests...)
+	}
+}
+
+// setupSMTPServer starts a minimal SMTP server that accepts a single message and returns its data
+func setupSMTPServer(t *testing.T) (string, int, <-chan string) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	messages := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close() //nolint:errcheck
+
+		reader := bufio.NewReader(conn)
+		reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+		reply("220 localhost ESMTP")
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			command := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+				reply("250 localhost")
+			case command == "DATA":
+				reply("354 End data with <CR><LF>.<CR><LF>")
+				var data strings.Builder
+				for {
+					line, err := reader.ReadString('\n')
+					if err != nil || line == ".\r\n" {
+						break
+					}
+					data.WriteString(line)
+				}
+				messages <- data.String()
+				reply("250 OK")
+			case command == "QUIT":
+				reply("221 Bye")
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port, messages
+}
+
+// setupNotificationService creates a NotificationService backed by the test database with a project
+func setupNotificationService(t *testing.T) (*NotificationService, *Project) {
+	t.Helper()
+	db := setupTestDB(t)
+	encryption := setupTestEncryption(t)
+	projectRepo := NewProjectRepository(db, encryption)
+	project, err := projectRepo.Create(createTestProject())
+	require.NoError(t, err)
+	return NewNotificationService(NewNotifierRepository(db, encryption), projectRepo), project
+}
+
+func testNotification(event NotificationEvent) *Notification {
+	deploymentID := uuid.New()
+	return &Notification{
+		Event:        event,
+		ProjectID:    uuid.New(),
+		ProjectName:  "web",
+		DeploymentID: &deploymentID,
+		CommitHash:   "1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b",
+		Output:       "Container web-1 Started",
+		Time:         time.Now(),
+	}
+}
+
+func TestParseNotifierType(t *testing.T) {
+	for _, notifierType := range []NotifierType{
+		NotifierTypeWebhook, NotifierTypeSlack, NotifierTypeEmail, NotifierTypeNtfy, NotifierTypeGotify,
+	} {
+		parsed, err := ParseNotifierType(string(notifierType))
+		require.NoError(t, err)
+		assert.Equal(t, notifierType, parsed)
+	}
+
+	_, err := ParseNotifierType("pagerduty")
+	assert.ErrorContains(t, err, "invalid notifier type")
+}
+
+func TestParseNotificationEvent(t *testing.T) {
+	for _, event := range NotificationEvents {
+		parsed, err := ParseNotificationEvent(string(event))
+		require.NoError(t, err)
+		assert.Equal(t, event, parsed)
+	}
+
+	_, err := ParseNotificationEvent(string(NotificationEventTest))
+	assert.ErrorContains(t, err, "invalid notification event")
+}
+
+func TestNewDeploymentNotification(t *testing.T) {
+	project := createTestProject()
+	deployment := NewDeployment(project.ID, "1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b")
+	var lines []string
+	for i := range 30 {
+		lines = append(lines, "line "+strconv.Itoa(i))
+	}
+	deployment.Output = strings.Join(lines, "\n") + "\n"
+
+	notification := NewDeploymentNotification(NotificationEventDeploymentFailed, project, &deployment)
+
+	assert.Equal(t, NotificationEventDeploymentFailed, notification.Event)
+	assert.Equal(t, project.ID, notification.ProjectID)
+	assert.Equal(t, deployment.ID, *notification.DeploymentID)
+	assert.Equal(t, "test-project: deployment failed (1a2b3c4d)", notification.Title())
+	assert.Equal(t, strings.Join(lines[10:], "\n"), notification.Output)
+	assert.True(t, strings.HasPrefix(notification.Text(), notification.Title()+"\n\nline 10\n"))
+}
+
+func TestValidateNotifier(t *testing.T) {
+	tests := []struct {
+		name        string
+		notifier    Notifier
+		expectedErr string
+	}{
+		{
+			name:     "webhook",
+			notifier: Notifier{Type: NotifierTypeWebhook, Config: NotifierConfig{URL: "https://example.com/hook"}},
+		},
+		{
+			name:        "webhook without URL",
+			notifier:    Notifier{Type: NotifierTypeWebhook},
+			expectedErr: "is not an HTTP(S) URL",
+		},
+		{
+			name:        "slack with non-HTTP URL",
+			notifier:    Notifier{Type: NotifierTypeSlack, Config: NotifierConfig{URL: "ftp://example.com"}},
+			expectedErr: "is not an HTTP(S) URL",
+		},
+		{
+			name:     "ntfy",
+			notifier: Notifier{Type: NotifierTypeNtfy, Config: NotifierConfig{URL: "https://ntfy.sh/deploys"}},
+		},
+		{
+			name:        "gotify without token",
+			notifier:    Notifier{Type: NotifierTypeGotify, Config: NotifierConfig{URL: "https://gotify.example.com"}},
+			expectedErr: "gotify requires an application token",
+		},
+		{
+			name: "email",
+			notifier: Notifier{Type: NotifierTypeEmail, Config: NotifierConfig{
+				SMTPHost: "mail.example.com", From: "oar@example.com", To: []string{"ops@example.com"},
+			}},
+		},
+		{
+			name:        "email without recipients",
+			notifier:    Notifier{Type: NotifierTypeEmail, Config: NotifierConfig{SMTPHost: "mail.example.com"}},
+			expectedErr: "email requires an SMTP host, a sender and at least one recipient",
+		},
+		{
+			name:        "unknown type",
+			notifier:    Notifier{Type: "pagerduty"},
+			expectedErr: "invalid notifier type",
+		},
+		{
+			name: "unknown event",
+			notifier: Notifier{
+				Type:   NotifierTypeNtfy,
+				Events: []NotificationEvent{"deployed"},
+				Config: NotifierConfig{URL: "https://ntfy.sh/deploys"},
+			},
+			expectedErr: "invalid notification event",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateNotifier(&tt.notifier)
+
+			if tt.expectedErr != "" {
+				assert.ErrorIs(t, err, ErrInvalidNotifier)
+				assert.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestNotifier_Destination(t *testing.T) {
+	slack := &Notifier{
+		Type:   NotifierTypeSlack,
+		Config: NotifierConfig{URL: "https://hooks.slack.com/services/T/B/secret"},
+	}
+	assert.Equal(t, "https://hooks.slack.com", slack.Destination())
+
+	email := &Notifier{Type: NotifierTypeEmail, Config: NotifierConfig{To: []string{"a@example.com", "b@example.com"}}}
+	assert.Equal(t, "a@example.com, b@example.com", email.Destination())
+}
+
+func TestNotificationService_send(t *testing.T) {
+	service, _ := setupNotificationService(t)
+	server, requests := setupNotificationServer(t, http.StatusOK)
+	notification := testNotification(NotificationEventDeploymentFailed)
+
+	t.Run("webhook", func(t *testing.T) {
+		notifier := &Notifier{Type: NotifierTypeWebhook, Config: NotifierConfig{URL: server.URL + "/hook"}}
+		require.NoError(t, service.send(notifier, notification))
+
+		received := requests()[len(requests())-1]
+		assert.Equal(t, "/hook", received.Path)
+		assert.Equal(t, "application/json", received.Header.Get("Content-Type"))
+		var payload Notification
+		require.NoError(t, json.Unmarshal([]byte(received.Body), &payload))
+		assert.Equal(t, NotificationEventDeploymentFailed, payload.Event)
+		assert.Equal(t, notification.CommitHash, payload.CommitHash)
+		assert.Equal(t, notification.Output, payload.Output)
+	})
+
+	t.Run("slack", func(t *testing.T) {
+		notifier := &Notifier{Type: NotifierTypeSlack, Config: NotifierConfig{URL: server.URL + "/services/x"}}
+		require.NoError(t, service.send(notifier, notification))
+
+		received := requests()[len(requests())-1]
+		var payload map[string]string
+		require.NoError(t, json.Unmarshal([]byte(received.Body), &payload))
+		assert.Equal(t, "*web: deployment failed (1a2b3c4d)*\n```\nContainer web-1 Started\n```", payload["text"])
+	})
+
+	t.Run("ntfy", func(t *testing.T) {
+		notifier := &Notifier{
+			Type:   NotifierTypeNtfy,
+			Config: NotifierConfig{URL: server.URL + "/deploys", Token: "tk_secret"},
+		}
+		require.NoError(t, service.send(notifier, notification))
+
+		received := requests()[len(requests())-1]
+		assert.Equal(t, "/deploys", received.Path)
+		assert.Equal(t, "web: deployment failed (1a2b3c4d)", received.Header.Get("Title"))
+		assert.Equal(t, "high", received.Header.Get("Priority"))
+		assert.Equal(t, "Bearer tk_secret", received.Header.Get("Authorization"))
+		assert.Equal(t, "Container web-1 Started", received.Body)
+	})
+
+	t.Run("gotify", func(t *testing.T) {
+		notifier := &Notifier{Type: NotifierTypeGotify, Config: NotifierConfig{URL: server.URL + "/", Token: "app"}}
+		require.NoError(t, service.send(notifier, notification))
+
+		received := requests()[len(requests())-1]
+		assert.Equal(t, "/message", received.Path)
+		assert.Equal(t, "app", received.Header.Get("X-Gotify-Key"))
+		var payload map[string]any
+		require.NoError(t, json.Unmarshal([]byte(received.Body), &payload))
+		assert.Equal(t, "web: deployment failed (1a2b3c4d)", payload["title"])
+		assert.Equal(t, float64(8), payload["priority"])
+	})
+
+	t.Run("email", func(t *testing.T) {
+		host, port, messages := setupSMTPServer(t)
+		notifier := &Notifier{Type: NotifierTypeEmail, Config: NotifierConfig{
+			SMTPHost: host,
+			SMTPPort: port,
+			From:     "oar@example.com",
+			To:       []string{"ops@example.com"},
+		}}
+		require.NoError(t, service.send(notifier, notification))
+
+		message := <-messages
+		assert.Contains(t, message, "To: ops@example.com\r\n")
+		assert.Contains(t, message, "Subject: [Oar] web: deployment failed (1a2b3c4d)\r\n")
+		assert.Contains(t, message, "\r\n\r\nweb: deployment failed (1a2b3c4d)\r\n\r\nContainer web-1 Started\r\n")
+	})
+
+	t.Run("error response", func(t *testing.T) {
+		failing, _ := setupNotificationServer(t, http.StatusUnauthorized)
+		notifier := &Notifier{Type: NotifierTypeWebhook, Config: NotifierConfig{URL: failing.URL}}
+
+		err := service.send(notifier, notification)
+
+		assert.ErrorIs(t, err, ErrNotificationDelivery)
+		assert.ErrorContains(t, err, "HTTP 401")
+	})
+}
+
+func TestSendEmail_UnresponsiveServer(t *testing.T) {
+	// Accepts connections but never greets
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		t.Cleanup(func() { _ = conn.Close() })
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	notifier := &Notifier{Type: NotifierTypeEmail, Config: NotifierConfig{
+		SMTPHost: "127.0.0.1",
+		SMTPPort: addr.Port,
+		From:     "oar@example.com",
+		To:       []string{"ops@example.com"},
+	}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err = sendEmail(ctx, notifier, testNotification(NotificationEventDeploymentFailed))
+
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestNotificationService_Notify(t *testing.T) {
+	service, project := setupNotificationService(t)
+	server, requests := setupNotificationServer(t, http.StatusOK)
+	failing, _ := setupNotificationServer(t, http.StatusInternalServerError)
+
+	require.NoError(t, service.AddNotifier(&Notifier{
+		ProjectID: project.ID,
+		Type:      NotifierTypeWebhook,
+		Config:    NotifierConfig{URL: server.URL + "/all"},
+	}))
+	require.NoError(t, service.AddNotifier(&Notifier{
+		ProjectID: project.ID,
+		Type:      NotifierTypeWebhook,
+		Events:    []NotificationEvent{NotificationEventDeploymentFailed},
+		Config:    NotifierConfig{URL: server.URL + "/failures"},
+	}))
+	require.NoError(t, service.AddNotifier(&Notifier{
+		ProjectID: project.ID,
+		Type:      NotifierTypeWebhook,
+		Config:    NotifierConfig{URL: failing.URL},
+	}))
+
+	notification := testNotification(NotificationEventDeploymentSucceeded)
+	notification.ProjectID = project.ID
+	service.Notify(notification)
+
+	received := requests()
+	require.Len(t, received, 1, "Only notifiers subscribed to the event are notified")
+	assert.Equal(t, "/all", received[0].Path)
+
+	notification.Event = NotificationEventDeploymentFailed
+	service.Notify(notification)
+
+	paths := []string{}
+	for _, r := range requests()[1:] {
+		paths = append(paths, r.Path)
+	}
+	assert.ElementsMatch(t, []string{"/all", "/failures"}, paths)
+}
+
+func TestNotificationService_ManageNotifiers(t *testing.T) {
+	service, project := setupNotificationService(t)
+	server, requests := setupNotificationServer(t, http.StatusOK)
+
+	err := service.AddNotifier(&Notifier{ProjectID: project.ID, Type: NotifierTypeSlack})
+	assert.ErrorIs(t, err, ErrInvalidNotifier)
+
+	notifier := &Notifier{ProjectID: project.ID, Type: NotifierTypeSlack, Config: NotifierConfig{URL: server.URL}}
+	require.NoError(t, service.AddNotifier(notifier))
+	assert.NotEqual(t, uuid.Nil, notifier.ID)
+
+	require.NoError(t, service.TestNotifier(project.ID, notifier.ID))
+	require.Len(t, requests(), 1)
+	assert.Contains(t, requests()[0].Body, "test-project: test notification")
+
+	// Notifiers are only reachable through their own project
+	otherProject := uuid.New()
+	assert.True(t, IsNotFound(service.TestNotifier(otherProject, notifier.ID)))
+	assert.True(t, IsNotFound(service.RemoveNotifier(otherProject, notifier.ID)))
+
+	require.NoError(t, service.RemoveNotifier(project.ID, notifier.ID))
+	notifiers, err := service.ListNotifiers(project.ID)
+	require.NoError(t, err)
+	assert.Empty(t, notifiers)
+}
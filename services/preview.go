@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// previewCommitLimit is the most commits a deploy preview lists
+const previewCommitLimit = 50
+
+// DeployPreview is what deploying a project would change: the commits between the deployed commit and the one
+// that would be deployed, and how the rendered Compose configuration differs between them
+type DeployPreview struct {
+	CurrentCommit string // Deployed commit, empty if the project was never deployed
+	TargetCommit  string
+	TargetRef     string      // What the target commit was resolved from, e.g. "branch main" or "tag v1.2.0"
+	Commits       []GitCommit // Newest first, at most previewCommitLimit of them
+	ConfigDiff    string      // Unified diff of the output of "docker compose config", empty if it is unchanged
+}
+
+// UpToDate reports whether the target commit is the deployed one
+func (p *DeployPreview) UpToDate() bool {
+	return p.CurrentCommit == p.TargetCommit
+}
+
+// PreviewDeploy shows what deploying a project would change without deploying it. With options.Pull, the remote is
+// fetched and the commit the project's branch, tag pattern or pinned commit resolves to is the target, as it would
+// be deployed; otherwise the checked out commit is. Fetching updates the refs of the checkout, so it holds the
+// deployment lock of the project, taken for options.Trigger and options.Actor. The configuration is rendered at
+// both commits from exports of them, so the checkout is left as it is.
+func (s *ProjectService) PreviewDeploy(
+	ctx context.Context,
+	projectID uuid.UUID,
+	options DeployOptions,
+) (*DeployPreview, error) {
+	project, err := s.Get(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("project not found: %w", err)
+	}
+	gitDir, err := project.GitDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get git directory: %w", err)
+	}
+
+	preview := &DeployPreview{CurrentCommit: project.LastCommitStr()}
+	if options.Pull {
+		unlock, err := s.lockProject(projectID, options)
+		if err != nil {
+			return nil, err
+		}
+		preview.TargetCommit, preview.TargetRef, err = FetchTargetCommit(s.gitService, project)
+		unlock()
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %w", project.GitRefDescription(), err)
+		}
+	} else {
+		preview.TargetCommit, err = s.gitService.GetLatestCommit(gitDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get checked out commit: %w", err)
+		}
+		preview.TargetRef = "checked out commit"
+	}
+
+	preview.Commits, err = s.gitService.Log(gitDir, preview.CurrentCommit, preview.TargetCommit, previewCommitLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits: %w", err)
+	}
+
+	current := ""
+	if preview.CurrentCommit != "" {
+		current, err = s.renderConfigAt(ctx, project, gitDir, preview.CurrentCommit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render configuration of deployed commit: %w", err)
+		}
+	}
+	target, err := s.renderConfigAt(ctx, project, gitDir, preview.TargetCommit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render configuration of commit %s: %w",
+			shortCommit(preview.TargetCommit), err)
+	}
+
+	preview.ConfigDiff, err = difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(current),
+		B:        difflib.SplitLines(target),
+		FromFile: commitLabel(preview.CurrentCommit),
+		ToFile:   commitLabel(preview.TargetCommit),
+		Context:  3,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare configurations: %w", err)
+	}
+
+	slog.Debug("Deploy previewed",
+		"project_id", project.ID,
+		"project_name", project.Name,
+		"current_commit", preview.CurrentCommit,
+		"target_commit", preview.TargetCommit,
+		"commits", len(preview.Commits))
+	return preview, nil
+}
+
+// renderConfigAt renders the Compose configuration of a project at commit, with the project's manifest as of that
+// commit. Paths into the export are reported as paths into gitDir, so that they don't show up as changes.
+func (s *ProjectService) renderConfigAt(ctx context.Context, project *Project, gitDir, commit string) (string, error) {
+	dir, err := os.MkdirTemp(s.config.TmpDir, "preview-")
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	if err := s.gitService.Export(commit, gitDir, dir); err != nil {
+		return "", err
+	}
+	manifest, err := ReadManifest(dir)
+	if err != nil {
+		slog.Warn("Ignoring invalid project manifest", "project_id", project.ID, "commit", commit, "error", err)
+	}
+
+	composeProject := newComposeProject(project, dir, manifest, s.config)
+	out, err := composeProject.output(ctx, composeProject.prepareCommand(ctx, "config", []string{}))
+	if err != nil {
+		return "", err
+	}
+	return strings.ReplaceAll(out, dir, gitDir), nil
+}
+
+// commitLabel names a commit in the headers of a configuration diff
+func commitLabel(commit string) string {
+	if commit == "" {
+		return "not deployed"
+	}
+	return shortCommit(commit)
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupPreviewDocker writes a script standing in for Docker to dir and returns its path. Rendering the
+// configuration prints the Compose file followed by its path.
+func setupPreviewDocker(t *testing.T, dir string) string {
+	t.Helper()
+	script := `#!/bin/sh
+while [ "$1" != "compose" ]; do shift; done
+shift
+file=""
+while case "$1" in --*) true ;; *) false ;; esac; do
+	if [ "$1" = "--file" ]; then file="$2"; fi
+	shift 2
+done
+case "$1" in
+config) cat "$file" && echo "path: $file" ;;
+*) exit 1 ;;
+esac
+`
+	dockerCommand := filepath.Join(dir, "docker")
+	require.NoError(t, os.WriteFile(dockerCommand, []byte(script), 0o755))
+	return dockerCommand
+}
+
+func TestProjectService_PreviewDeploy(t *testing.T) {
+	service, repo, _, gitService, _ := setupMockProjectService(t)
+	tempDir := t.TempDir()
+	service.config.DockerCommand = setupPreviewDocker(t, tempDir)
+	service.config.TmpDir = t.TempDir()
+
+	project := createTestProject()
+	project.WorkingDir = tempDir
+	project.LastCommit = stringPtr("1111111111111111111111111111111111111111")
+	repo.projects[project.ID] = project
+	gitDir := filepath.Join(tempDir, GitDir)
+
+	target := "2222222222222222222222222222222222222222"
+	composeFiles := map[string]string{
+		*project.LastCommit: "services:\n  web:\n    image: nginx:1.25\n",
+		target:              "services:\n  web:\n    image: nginx:1.27\n",
+	}
+	var fetched bool
+	gitService.FetchFunc = func(gitBranch string, gitAuth *GitAuthConfig, workingDir string) error {
+		fetched = true
+		return nil
+	}
+	gitService.GetRemoteLatestCommitFunc = func(workingDir string, gitBranch string) (string, error) {
+		return target, nil
+	}
+	gitService.LogFunc = func(workingDir, from, to string, limit int) ([]GitCommit, error) {
+		assert.Equal(t, gitDir, workingDir)
+		assert.Equal(t, *project.LastCommit, from)
+		assert.Equal(t, target, to)
+		return []GitCommit{{Hash: target, Author: "alice", Date: time.Now(), Message: "Update nginx"}}, nil
+	}
+	gitService.ExportFunc = func(commit, workingDir, dir string) error {
+		assert.Equal(t, service.config.TmpDir, filepath.Dir(dir))
+		return os.WriteFile(filepath.Join(dir, "docker-compose.yml"), []byte(composeFiles[commit]), 0o644)
+	}
+
+	preview, err := service.PreviewDeploy(context.Background(), project.ID, DeployOptions{Pull: true})
+	require.NoError(t, err)
+	assert.True(t, fetched)
+	assert.False(t, preview.UpToDate())
+	assert.Equal(t, target, preview.TargetCommit)
+	assert.Equal(t, "branch main", preview.TargetRef)
+	require.Len(t, preview.Commits, 1)
+	assert.Equal(t, "Update nginx", preview.Commits[0].Message)
+
+	// Paths into the exports are reported as paths into the checkout, so only the image changed
+	assert.Contains(t, preview.ConfigDiff, "--- 11111111\n+++ 22222222\n")
+	assert.Contains(t, preview.ConfigDiff, "-    image: nginx:1.25\n+    image: nginx:1.27\n")
+	assert.Contains(t, preview.ConfigDiff, " path: "+filepath.Join(gitDir, "docker-compose.yml"))
+	assert.NotContains(t, preview.ConfigDiff, service.config.TmpDir)
+
+	// The exports are removed
+	entries, err := os.ReadDir(service.config.TmpDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestProjectService_PreviewDeploy_NoPull(t *testing.T) {
+	service, repo, _, gitService, _ := setupMockProjectService(t)
+	tempDir := t.TempDir()
+	service.config.DockerCommand = setupPreviewDocker(t, tempDir)
+
+	project := createTestProject()
+	project.WorkingDir = tempDir
+	project.LastCommit = nil
+	repo.projects[project.ID] = project
+
+	gitService.FetchFunc = func(gitBranch string, gitAuth *GitAuthConfig, workingDir string) error {
+		t.Error("Fetch() called without pull")
+		return nil
+	}
+	gitService.GetLatestCommitFunc = func(workingDir string) (string, error) {
+		return "3333333333333333333333333333333333333333", nil
+	}
+	gitService.ExportFunc = func(commit, workingDir, dir string) error {
+		return os.WriteFile(filepath.Join(dir, "docker-compose.yml"), []byte("services: {}\n"), 0o644)
+	}
+
+	// A project that was never deployed is compared with an empty configuration
+	preview, err := service.PreviewDeploy(context.Background(), project.ID, DeployOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "checked out commit", preview.TargetRef)
+	assert.Empty(t, preview.CurrentCommit)
+	assert.Contains(t, preview.ConfigDiff, "--- not deployed\n+++ 33333333\n")
+	assert.Contains(t, preview.ConfigDiff, "+services: {}\n")
+}
+
+func TestProjectService_PreviewDeploy_UpToDate(t *testing.T) {
+	service, repo, _, gitService, _ := setupMockProjectService(t)
+	tempDir := t.TempDir()
+	service.config.DockerCommand = setupPreviewDocker(t, tempDir)
+
+	project := createTestProject()
+	project.WorkingDir = tempDir
+	repo.projects[project.ID] = project
+
+	gitService.GetLatestCommitFunc = func(workingDir string) (string, error) {
+		return project.LastCommitStr(), nil
+	}
+	gitService.ExportFunc = func(commit, workingDir, dir string) error {
+		return os.WriteFile(filepath.Join(dir, "docker-compose.yml"), []byte("services: {}\n"), 0o644)
+	}
+
+	preview, err := service.PreviewDeploy(context.Background(), project.ID, DeployOptions{})
+	require.NoError(t, err)
+	assert.True(t, preview.UpToDate())
+	assert.Empty(t, preview.Commits)
+	assert.Empty(t, preview.ConfigDiff)
+}
+
+func TestProjectService_PreviewDeploy_Errors(t *testing.T) {
+	service, repo, _, gitService, _ := setupMockProjectService(t)
+	tempDir := t.TempDir()
+	service.config.DockerCommand = setupPreviewDocker(t, tempDir)
+
+	project := createTestProject()
+	project.WorkingDir = tempDir
+	repo.projects[project.ID] = project
+
+	gitService.FetchFunc = func(gitBranch string, gitAuth *GitAuthConfig, workingDir string) error {
+		return errors.New("network unreachable")
+	}
+	_, err := service.PreviewDeploy(context.Background(), project.ID, DeployOptions{Pull: true})
+	assert.ErrorContains(t, err, "failed to resolve branch main")
+
+	// The Compose file doesn't exist at the target commit
+	_, err = service.PreviewDeploy(context.Background(), project.ID, DeployOptions{})
+	assert.ErrorContains(t, err, "failed to render configuration")
+}
+
+func TestProjectService_PreviewDeploy_Locked(t *testing.T) {
+	service, project, locks := setupLockingProjectService(t)
+	gitService := service.gitService.(*MockGitExecutor)
+	options := DeployOptions{Pull: true, Trigger: DeploymentTriggerWeb, Actor: "alice"}
+
+	// The fetch runs under the deployment lock, which is released before rendering
+	gitService.FetchFunc = func(gitBranch string, gitAuth *GitAuthConfig, workingDir string) error {
+		lock, err := locks.FindByProjectID(project.ID)
+		require.NoError(t, err)
+		assert.Equal(t, DeploymentTriggerWeb, lock.Trigger)
+		assert.Equal(t, "alice", lock.Actor)
+		return errors.New("network unreachable")
+	}
+	_, err := service.PreviewDeploy(context.Background(), project.ID, options)
+	assert.ErrorContains(t, err, "failed to resolve branch main")
+	_, err = locks.FindByProjectID(project.ID)
+	assert.Error(t, err)
+
+	// A deployment in progress keeps the preview from fetching
+	unlock, err := service.lockProject(project.ID, DeployOptions{Trigger: DeploymentTriggerWatcher})
+	require.NoError(t, err)
+	defer unlock()
+	gitService.FetchFunc = func(gitBranch string, gitAuth *GitAuthConfig, workingDir string) error {
+		t.Error("Fetch() called during a deployment")
+		return nil
+	}
+	_, err = service.PreviewDeploy(context.Background(), project.ID, options)
+	assert.ErrorIs(t, err, ErrDeploymentInProgress)
+}
@@ -2,12 +2,16 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/gosimple/slug"
@@ -52,6 +56,15 @@ func ParseProjectStatus(s string) (ProjectStatus, error) {
 	}
 }
 
+// ErrInvalidRollbackTarget is returned when a rollback target does not resolve to an earlier deployment
+var ErrInvalidRollbackTarget = errors.New("invalid rollback target")
+
+// ErrDeploymentCancelled is returned by a deployment that was cancelled before it finished
+var ErrDeploymentCancelled = errors.New("deployment cancelled")
+
+// ErrNoDeploymentInProgress is returned when cancelling a project that has no deployment running
+var ErrNoDeploymentInProgress = errors.New("no deployment in progress")
+
 // GetDeletedDirectoryPath calculates the path where a project directory will be moved when deleted
 func GetDeletedDirectoryPath(workingDir string) string {
 	deletedDirName := fmt.Sprintf("deleted-%s", filepath.Base(workingDir))
@@ -62,8 +75,18 @@ func GetDeletedDirectoryPath(workingDir string) string {
 type ProjectService struct {
 	projectRepository    ProjectRepository
 	deploymentRepository DeploymentRepository
+	lockRepository       DeploymentLockRepository // Optional, nil disables deployment locks
 	gitService           GitExecutor
+	notifications        NotificationSender // Optional, nil disables notifications
 	config               *Config
+
+	runningMu sync.Mutex
+	running   map[uuid.UUID]*runningDeployment // Deployments in progress in this process, by project
+}
+
+// runningDeployment is a deployment in progress that can be cancelled
+type runningDeployment struct {
+	cancel context.CancelFunc
 }
 
 // Ensure ProjectService implements ProjectManager
@@ -108,6 +131,24 @@ func (s *ProjectService) Create(project *Project) (*Project, error) {
 	if len(project.ComposeFiles) == 0 {
 		return nil, fmt.Errorf("compose files are required")
 	}
+	if err := normalizeGitRef(project); err != nil {
+		return nil, err
+	}
+	if err := project.Timeouts.Validate(); err != nil {
+		return nil, err
+	}
+	if err := validateHealthChecks(project.HealthChecks); err != nil {
+		return nil, err
+	}
+	if err := validateHooks(project.PreDeployHooks); err != nil {
+		return nil, err
+	}
+	if err := validateHooks(project.PostDeployHooks); err != nil {
+		return nil, err
+	}
+	if project.RequireApproval && !project.WatcherEnabled {
+		return nil, ErrApprovalWithoutWatcher
+	}
 
 	// Create directory name: <project_id>-<normalized_project_name>
 	normalizedName := slug.Make(project.Name)
@@ -162,9 +203,29 @@ func (s *ProjectService) Create(project *Project) (*Project, error) {
 		return nil, err
 	}
 
+	// Move off the branch head when the project tracks a tag or a pinned commit
+	if project.GitRefType != GitRefTypeBranch {
+		if err := s.checkoutGitRef(project); err != nil {
+			slog.Error("Service operation failed",
+				"layer", "service",
+				"operation", "create_project_checkout",
+				"project_id", project.ID,
+				"project_name", project.Name,
+				"git_ref", project.GitRefDescription(),
+				"error", err)
+			if cleanupErr := os.RemoveAll(project.WorkingDir); cleanupErr != nil {
+				slog.Error("Failed to remove project directory after checkout failure",
+					"working_dir", project.WorkingDir,
+					"error", cleanupErr)
+			}
+			return nil, err
+		}
+	}
+
 	// Get commit info
 	commit, _ := s.gitService.GetLatestCommit(gitDir)
 	project.LastCommit = &commit
+	project.DesiredState = DesiredStateStopped // Until it is deployed
 
 	// Save working directory for cleanup before repository call
 	workingDir := project.WorkingDir
@@ -205,43 +266,55 @@ func (s *ProjectService) Update(project *Project) error {
 	if len(project.ComposeFiles) == 0 {
 		return fmt.Errorf("compose files are required")
 	}
+	if err := normalizeGitRef(project); err != nil {
+		return err
+	}
+	if err := project.Timeouts.Validate(); err != nil {
+		return err
+	}
+	if err := validateHealthChecks(project.HealthChecks); err != nil {
+		return err
+	}
+	if err := validateHooks(project.PreDeployHooks); err != nil {
+		return err
+	}
+	if err := validateHooks(project.PostDeployHooks); err != nil {
+		return err
+	}
+	if project.RequireApproval && !project.WatcherEnabled {
+		return ErrApprovalWithoutWatcher
+	}
 	return s.projectRepository.Update(project)
 }
 
+// DeployStreaming deploys the project, streaming output to outputChan. The deployment is cancelled when ctx is
+// cancelled or CancelDeployment is called for the project.
 func (s *ProjectService) DeployStreaming(
+	ctx context.Context,
 	projectID uuid.UUID,
-	pull bool,
+	options DeployOptions,
 	outputChan chan<- string,
 ) error {
-	project, commitHash, deployment, composeProject, err := s.prepareDeployment(projectID, pull)
+	unlock, err := s.lockProject(projectID, options)
 	if err != nil {
 		return err
 	}
+	defer unlock()
 
-	// Create a buffer to capture clean output for the deployment record
-	var outputBuffer strings.Builder
-
-	// Helper function to capture clean message and send JSON to channel
-	captureAndSendJSON := func(cleanMsg, msgType, source string) {
-		// Store clean message for database
-		outputBuffer.WriteString(cleanMsg + "\n")
+	ctx, done := s.startCancellable(ctx, projectID)
+	defer done()
 
-		// Create JSON message for web UI
-		msgData := map[string]string{
-			"type":    msgType,
-			"message": cleanMsg,
-		}
-		if source != "" {
-			msgData["source"] = source
-		}
-
-		if jsonMsg, err := json.Marshal(msgData); err == nil {
-			outputChan <- string(jsonMsg)
-		}
+	project, commitHash, deployment, err := s.prepareDeployment(projectID, options, nil)
+	if err != nil {
+		return err
 	}
 
+	// Capture clean output for the deployment record while streaming JSON to the channel
+	output := &deploymentOutput{outputChan: outputChan}
+	captureAndSendJSON := output.send
+
 	// Streaming-specific messages
-	if pull {
+	if options.Pull {
 		captureAndSendJSON("Pulling latest changes from Git...", "info", "oar")
 
 		// Get commit hash before pull
@@ -261,6 +334,7 @@ func (s *ProjectService) DeployStreaming(
 		if err := s.pullLatestChanges(project); err != nil {
 			errMsg := fmt.Sprintf("Failed to pull latest changes: %v", err)
 			captureAndSendJSON(errMsg, "error", "oar")
+			s.handleCheckoutError(project, &deployment, output.buffer.String())
 			return err
 		}
 
@@ -279,100 +353,252 @@ func (s *ProjectService) DeployStreaming(
 		afterHash := afterCommit
 		if afterCommit != "unknown" {
 			afterHash = afterCommit[:8]
+
+			// Record the commit that is actually deployed, not the one checked out before the pull
+			commitHash = afterCommit
+			deployment.CommitHash = afterCommit
 		}
 
 		successMsg := fmt.Sprintf("Git pull completed successfully (from %s to %s)", beforeHash, afterHash)
 		captureAndSendJSON(successMsg, "success", "oar")
 	}
 
-	captureAndSendJSON("Starting Docker Compose deployment...", "info", "oar")
+	return s.composeUpStreaming(ctx, project, commitHash, deployment, options.PullImages, output)
+}
 
-	// Create a capturing channel that forwards to the original channel
-	capturingChan := make(chan string, 100) // buffered channel
+func (s *ProjectService) DeployPiping(ctx context.Context, projectID uuid.UUID, options DeployOptions) error {
+	// Create a local channel to capture streaming output
+	outputChan := make(chan string, 100)
 	done := make(chan bool)
 
+	// Start a goroutine to consume JSON messages and display clean messages to terminal
 	go func() {
 		defer func() { done <- true }()
-		for msg := range capturingChan {
-			// msg is now clean output from Docker, store it and wrap in JSON
-			outputBuffer.WriteString(msg + "\n")
-
-			// Wrap in JSON for web UI
-			msgData := map[string]string{
-				"type":    "docker",
-				"message": msg,
-			}
-			if jsonMsg, err := json.Marshal(msgData); err == nil {
-				outputChan <- string(jsonMsg)
+		for msg := range outputChan {
+			// Extract clean message from JSON
+			var msgData map[string]string
+			if err := json.Unmarshal([]byte(msg), &msgData); err == nil {
+				// Display clean message to terminal
+				fmt.Println(msgData["message"])
 			}
 		}
 	}()
 
-	// Execute deployment with streaming
-	err = composeProject.UpStreaming(capturingChan)
-	close(capturingChan) // Signal that we're done sending to the capturing channel
-	<-done               // Wait for the goroutine to finish processing all messages
+	// Use DeployStreaming internally (it now stores clean output in database)
+	err := s.DeployStreaming(ctx, projectID, options, outputChan)
 
-	// Store the captured output in the deployment record
-	deployment.Output = outputBuffer.String()
+	// Close channel and wait for goroutine to finish
+	close(outputChan)
+	<-done
+
+	return err
+}
+
+// RollbackStreaming checks out the commit of an earlier deployment and deploys it, recording a new
+// deployment marked as a rollback. The target is a deployment ID or a (prefix of a) previously deployed
+// commit; an empty target rolls back to the most recent successful deployment of another commit.
+// Rollbacks never pull and are recorded with the rollback trigger and the actor of options.
+func (s *ProjectService) RollbackStreaming(
+	ctx context.Context,
+	projectID uuid.UUID,
+	target string,
+	options DeployOptions,
+	outputChan chan<- string,
+) error {
+	options.Pull = false
+	options.Trigger = DeploymentTriggerRollback
 
+	unlock, err := s.lockProject(projectID, options)
 	if err != nil {
-		return s.handleDeploymentError(project, &deployment, err)
+		return err
 	}
+	defer unlock()
 
-	// Complete deployment
-	if err := s.completeDeployment(project, commitHash, deployment); err != nil {
+	ctx, done := s.startCancellable(ctx, projectID)
+	defer done()
+
+	project, err := s.Get(projectID)
+	if err != nil {
+		return fmt.Errorf("project not found: %w", err)
+	}
+
+	commit, err := s.rollbackCommit(project, target)
+	if err != nil {
 		return err
 	}
 
-	// Send success message
-	captureAndSendJSON("Docker Compose deployment completed successfully", "success", "oar")
+	gitDir, err := project.GitDir()
+	if err != nil {
+		return fmt.Errorf("failed to get git directory: %w", err)
+	}
 
-	return nil
+	output := &deploymentOutput{outputChan: outputChan}
+	output.send(fmt.Sprintf("Rolling back to commit %s...", shortCommit(commit)), "info", "oar")
+
+	if err := s.gitService.Checkout(commit, gitDir); err != nil {
+		output.send(fmt.Sprintf("Failed to check out commit: %v", err), "error", "oar")
+		return fmt.Errorf("failed to check out commit: %w", err)
+	}
+
+	project, commitHash, deployment, err := s.prepareDeployment(projectID, options, nil)
+	if err != nil {
+		return err
+	}
+
+	output.send(fmt.Sprintf("Checked out commit %s", shortCommit(commitHash)), "success", "oar")
+
+	return s.composeUpStreaming(ctx, project, commitHash, deployment, false, output)
 }
 
-func (s *ProjectService) DeployPiping(projectID uuid.UUID, pull bool) error {
-	// Create a local channel to capture streaming output
+func (s *ProjectService) RollbackPiping(
+	ctx context.Context,
+	projectID uuid.UUID,
+	target string,
+	options DeployOptions,
+) error {
 	outputChan := make(chan string, 100)
 	done := make(chan bool)
 
-	// Start a goroutine to consume JSON messages and display clean messages to terminal
 	go func() {
 		defer func() { done <- true }()
 		for msg := range outputChan {
-			// Extract clean message from JSON
 			var msgData map[string]string
 			if err := json.Unmarshal([]byte(msg), &msgData); err == nil {
-				// Display clean message to terminal
 				fmt.Println(msgData["message"])
 			}
 		}
 	}()
 
-	// Use DeployStreaming internally (it now stores clean output in database)
-	err := s.DeployStreaming(projectID, pull, outputChan)
+	err := s.RollbackStreaming(ctx, projectID, target, options, outputChan)
 
-	// Close channel and wait for goroutine to finish
 	close(outputChan)
 	<-done
 
 	return err
 }
 
-// prepareDeployment handles the common setup logic for both streaming and piping deployments
+// CancelDeployment cancels the deployment or rollback of the project in progress. Deployments running in another
+// process, such as the watcher or the CLI, are asked to cancel through their deployment lock, and are cancelled
+// once that process notices.
+func (s *ProjectService) CancelDeployment(projectID uuid.UUID) error {
+	if s.cancelRunning(projectID) {
+		return nil
+	}
+	if s.lockRepository == nil {
+		return ErrNoDeploymentInProgress
+	}
+
+	requested, err := s.lockRepository.RequestCancel(projectID, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to request cancellation: %w", err)
+	}
+	if !requested {
+		return ErrNoDeploymentInProgress
+	}
+	slog.Info("Requested cancellation of deployment running in another process", "project_id", projectID)
+	return nil
+}
+
+// cancelRunning cancels the deployment of the project in progress in this process, reporting whether there was one
+func (s *ProjectService) cancelRunning(projectID uuid.UUID) bool {
+	s.runningMu.Lock()
+	defer s.runningMu.Unlock()
+
+	running, ok := s.running[projectID]
+	if !ok {
+		return false
+	}
+
+	slog.Info("Cancelling deployment", "project_id", projectID)
+	running.cancel()
+	return true
+}
+
+// startCancellable registers a deployment of the project as in progress, returning a context that
+// CancelDeployment cancels and a function to call once the deployment is done
+func (s *ProjectService) startCancellable(ctx context.Context, projectID uuid.UUID) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	running := &runningDeployment{cancel: cancel}
+
+	s.runningMu.Lock()
+	if s.running == nil {
+		s.running = make(map[uuid.UUID]*runningDeployment)
+	}
+	s.running[projectID] = running
+	s.runningMu.Unlock()
+
+	return ctx, func() {
+		s.runningMu.Lock()
+		if s.running[projectID] == running {
+			delete(s.running, projectID)
+		}
+		s.runningMu.Unlock()
+		cancel()
+	}
+}
+
+// rollbackCommit resolves the commit a rollback target refers to among the project's deployments
+func (s *ProjectService) rollbackCommit(project *Project, target string) (string, error) {
+	deployments, err := s.deploymentRepository.ListByProjectID(project.ID)
+	if err != nil {
+		return "", fmt.Errorf("failed to list deployments: %w", err)
+	}
+
+	// Without a target, go back to the most recent successful deployment of another commit
+	if target == "" {
+		for _, d := range deployments {
+			if d.Status == DeploymentStatusCompleted && d.CommitHash != project.LastCommitStr() {
+				return d.CommitHash, nil
+			}
+		}
+		return "", fmt.Errorf("%w: no earlier successful deployment of another commit", ErrInvalidRollbackTarget)
+	}
+
+	if deploymentID, err := uuid.Parse(target); err == nil {
+		for _, d := range deployments {
+			if d.ID == deploymentID && !d.Deployed() {
+				return "", fmt.Errorf("%w: deployment %s is %s and was never deployed",
+					ErrInvalidRollbackTarget, deploymentID, d.Status)
+			}
+			if d.ID == deploymentID {
+				return d.CommitHash, nil
+			}
+		}
+		return "", fmt.Errorf("%w: deployment %s not found", ErrInvalidRollbackTarget, deploymentID)
+	}
+
+	var commit string
+	for _, d := range deployments {
+		if !d.Deployed() || !strings.HasPrefix(d.CommitHash, strings.ToLower(target)) || d.CommitHash == commit {
+			continue
+		}
+		if commit != "" {
+			return "", fmt.Errorf("%w: commit %q is ambiguous", ErrInvalidRollbackTarget, target)
+		}
+		commit = d.CommitHash
+	}
+	if commit == "" {
+		return "", fmt.Errorf("%w: commit %q was never deployed", ErrInvalidRollbackTarget, target)
+	}
+	return commit, nil
+}
+
+// prepareDeployment handles the common setup logic for both streaming and piping deployments. The deployment is
+// recorded as a new one, or as pending, an approved deployment that keeps its ID and trigger, when it isn't nil.
 func (s *ProjectService) prepareDeployment(
 	projectID uuid.UUID,
-	pull bool,
-) (*Project, string, Deployment, *ComposeProject, error) {
+	options DeployOptions,
+	pending *Deployment,
+) (*Project, string, Deployment, error) {
 	// Get project
 	project, err := s.Get(projectID)
 	if err != nil {
-		return nil, "", Deployment{}, nil, fmt.Errorf("project not found: %w", err)
+		return nil, "", Deployment{}, fmt.Errorf("project not found: %w", err)
 	}
 
 	gitDir, err := project.GitDir()
 	if err != nil {
-		return nil, "", Deployment{}, nil, fmt.Errorf("failed to get git directory: %w", err)
+		return nil, "", Deployment{}, fmt.Errorf("failed to get git directory: %w", err)
 	}
 
 	commitHash, err := s.gitService.GetLatestCommit(gitDir)
@@ -382,15 +608,44 @@ func (s *ProjectService) prepareDeployment(
 			"operation", "deploy_project",
 			"project_id", project.ID,
 			"error", err)
-		return nil, "", Deployment{}, nil, err
+		return nil, "", Deployment{}, err
+	}
+
+	// A given commit is checked out once the deployment is recorded, so that failing to do so is recorded too
+	if options.Commit != "" {
+		commitHash = options.Commit
 	}
 
 	deployment := NewDeployment(projectID, commitHash)
+	if pending != nil {
+		deployment = *pending
+		deployment.CommitHash = commitHash
+		options.Trigger = pending.Trigger
+	}
 	deployment.Status = DeploymentStatusStarted
+	deployment.Rollback = options.Trigger == DeploymentTriggerRollback ||
+		options.Trigger == DeploymentTriggerAutoRollback
+	deployment.PreviousCommit = project.LastCommitStr()
+	deployment.Pull = options.Pull
+	deployment.Trigger = options.Trigger
+	deployment.Actor = options.Actor
+	deployment.StartedAt = time.Now()
 
 	// Create deployment record immediately
-	if err := s.deploymentRepository.Create(&deployment); err != nil {
-		return nil, "", Deployment{}, nil, fmt.Errorf("failed to create deployment record: %w", err)
+	if pending != nil {
+		if err := s.deploymentRepository.Update(&deployment); err != nil {
+			return nil, "", Deployment{}, fmt.Errorf("failed to update deployment record: %w", err)
+		}
+	} else if err := s.deploymentRepository.Create(&deployment); err != nil {
+		return nil, "", Deployment{}, fmt.Errorf("failed to create deployment record: %w", err)
+	}
+
+	if options.Commit != "" {
+		if err := s.gitService.Checkout(options.Commit, gitDir); err != nil {
+			err = fmt.Errorf("failed to check out commit: %w", err)
+			s.handleCheckoutError(project, &deployment, fmt.Sprintf("ERROR: %v", err))
+			return nil, "", Deployment{}, err
+		}
 	}
 
 	// Log deployment start
@@ -400,17 +655,187 @@ func (s *ProjectService) prepareDeployment(
 		"deployment_id", deployment.ID,
 		"commit_hash", commitHash,
 		"compose_files", project.ComposeFiles,
-		"pull", pull)
+		"pull", options.Pull,
+		"trigger", options.Trigger.String(),
+		"actor", options.Actor)
 
-	composeProject := NewComposeProject(project, s.config)
+	return project, commitHash, deployment, nil
+}
+
+// composeUpStreaming runs Docker Compose up for a prepared deployment and records its outcome. Up only pulls the
+// images the project is missing, unless pullImages pulls all of them first. Settings declared in the manifest of
+// the checked out commit win over those of the project, an invalid manifest fails the deployment.
+func (s *ProjectService) composeUpStreaming(
+	ctx context.Context,
+	project *Project,
+	commitHash string,
+	deployment Deployment,
+	pullImages bool,
+	output *deploymentOutput,
+) error {
+	s.notify(NotificationEventDeploymentStarted, project, &deployment)
+	output.send("Starting Docker Compose deployment...", "info", "oar")
+
+	gitDir, err := project.GitDir()
+	var manifest *Manifest
+	if err == nil {
+		manifest, err = ReadManifest(gitDir)
+	}
+	if manifest != nil {
+		output.send(fmt.Sprintf("Using project settings from %s", ManifestFile), "info", "oar")
+	}
+	settings := manifest.Apply(project)
+	composeProject := newComposeProject(project, gitDir, manifest, s.config)
+
+	if err == nil && settings.BackupVolumes {
+		err = s.backupVolumesBeforeDeploy(ctx, project, composeProject, output)
+	}
+	if err == nil {
+		err = s.runHooks(ctx, HookPhasePreDeploy, settings.PreDeployHooks, composeProject, output)
+	}
+	if err == nil {
+		// Execute deployment with streaming, pulling images first when asked to so that pulling and starting
+		// have their own timeouts
+		err = output.stream(func(outputChan chan<- string) error {
+			if pullImages {
+				if err := composeProject.PullStreaming(ctx, outputChan); err != nil {
+					return err
+				}
+			}
+			return composeProject.UpStreaming(ctx, outputChan)
+		})
+	}
+	if err == nil && len(settings.HealthChecks) > 0 {
+		deployment.HealthChecks, err = s.checkHealth(ctx, settings, composeProject, output)
+	}
+	if err == nil {
+		err = s.runHooks(ctx, HookPhasePostDeploy, settings.PostDeployHooks, composeProject, output)
+	}
+
+	// Store the captured output in the deployment record
+	deployment.Output = output.buffer.String()
+
+	if err != nil {
+		err = s.handleDeploymentError(project, &deployment, err)
+		if settings.AutoRollback && !deployment.Rollback && deployment.Status == DeploymentStatusFailed {
+			s.autoRollback(ctx, project.ID, &deployment, output.outputChan)
+		}
+		return err
+	}
+
+	// Complete deployment
+	if err := s.completeDeployment(project, commitHash, &deployment); err != nil {
+		return err
+	}
+
+	// Send success message
+	output.send("Docker Compose deployment completed successfully", "success", "oar")
+	deployment.Output = output.buffer.String()
+	observeDeployment(project, &deployment, DeploymentStatusCompleted)
+	s.notify(NotificationEventDeploymentSucceeded, project, &deployment)
 
-	return project, commitHash, deployment, composeProject, nil
+	return nil
+}
+
+// autoRollback redeploys the commit of the last completed deployment after the failed deployment of a project
+// with automatic rollbacks enabled. The rollback is recorded as a deployment of its own, and automatic
+// deployments skip the failed commit until a newer one is pushed.
+func (s *ProjectService) autoRollback(
+	ctx context.Context,
+	projectID uuid.UUID,
+	failed *Deployment,
+	outputChan chan<- string,
+) {
+	output := &deploymentOutput{outputChan: outputChan}
+
+	deployments, err := s.deploymentRepository.ListByProjectID(projectID)
+	if err != nil {
+		slog.Error("Service operation failed",
+			"layer", "service",
+			"operation", "auto_rollback",
+			"project_id", projectID,
+			"error", err)
+		return
+	}
+
+	var commit string
+	for _, d := range deployments {
+		if d.Status == DeploymentStatusCompleted {
+			commit = d.CommitHash
+			break
+		}
+	}
+
+	// Redeploying the commit that failed would not roll anything back
+	if commit == "" || commit == failed.CommitHash {
+		slog.Info("No earlier successful deployment, skipping automatic rollback",
+			"project_id", projectID,
+			"deployment_id", failed.ID)
+		output.send("No earlier successful deployment to roll back to", "info", "oar")
+		return
+	}
+
+	output.send(fmt.Sprintf("Rolling back automatically to commit %s...", shortCommit(commit)), "info", "oar")
+
+	project, err := s.Get(projectID)
+	if err != nil {
+		output.send(fmt.Sprintf("Automatic rollback failed: %v", err), "error", "oar")
+		return
+	}
+	gitDir, err := project.GitDir()
+	if err != nil {
+		output.send(fmt.Sprintf("Automatic rollback failed: %v", err), "error", "oar")
+		return
+	}
+	if err := s.gitService.Checkout(commit, gitDir); err != nil {
+		output.send(fmt.Sprintf("Failed to check out commit: %v", err), "error", "oar")
+		return
+	}
+
+	options := DeployOptions{Trigger: DeploymentTriggerAutoRollback}
+	project, commitHash, deployment, err := s.prepareDeployment(projectID, options, nil)
+	if err != nil {
+		output.send(fmt.Sprintf("Automatic rollback failed: %v", err), "error", "oar")
+		return
+	}
+
+	output.send(fmt.Sprintf("Checked out commit %s", shortCommit(commitHash)), "success", "oar")
+
+	if err := s.composeUpStreaming(ctx, project, commitHash, deployment, false, output); err != nil {
+		output.send(fmt.Sprintf("Automatic rollback failed: %v", err), "error", "oar")
+		slog.Error("Automatic rollback failed",
+			"project_id", projectID,
+			"deployment_id", deployment.ID,
+			"failed_deployment_id", failed.ID,
+			"error", err)
+		return
+	}
+
+	project.RolledBackCommit = &failed.CommitHash
+	if err := s.projectRepository.Update(project); err != nil {
+		slog.Error("Failed to record rolled back commit",
+			"project_id", projectID,
+			"rolled_back_commit", failed.CommitHash,
+			"error", err)
+	}
+
+	slog.Info("Project rolled back automatically",
+		"project_id", projectID,
+		"deployment_id", deployment.ID,
+		"commit_hash", commitHash,
+		"rolled_back_commit", failed.CommitHash)
 }
 
 // handleDeploymentError handles deployment errors consistently
 func (s *ProjectService) handleDeploymentError(project *Project, deployment *Deployment, err error) error {
+	if errors.Is(err, context.Canceled) {
+		return s.handleDeploymentCancelled(project, deployment)
+	}
+
 	// Update deployment record as failed and append error info to output
+	finishedAt := time.Now()
 	deployment.Status = DeploymentStatusFailed
+	deployment.FinishedAt = &finishedAt
 
 	// Append error information to the existing output
 	if deployment.Output != "" {
@@ -441,25 +866,83 @@ func (s *ProjectService) handleDeploymentError(project *Project, deployment *Dep
 		"deployment_id", deployment.ID,
 		"error", err,
 	)
+	observeDeployment(project, deployment, DeploymentStatusFailed)
+	s.notify(NotificationEventDeploymentFailed, project, deployment)
 	return fmt.Errorf("failed to start project: %w", err)
 }
 
+// handleCheckoutError records a deployment that failed before Docker Compose ran, because the commit to deploy
+// couldn't be pulled or checked out, with output as its output. Nothing was deployed, so the project status is
+// left as it was.
+func (s *ProjectService) handleCheckoutError(project *Project, deployment *Deployment, output string) {
+	finishedAt := time.Now()
+	deployment.Status = DeploymentStatusFailed
+	deployment.FinishedAt = &finishedAt
+	deployment.Output = output
+	if updateErr := s.deploymentRepository.Update(deployment); updateErr != nil {
+		slog.Error("Failed to update deployment record as failed",
+			"deployment_id", deployment.ID,
+			"project_id", deployment.ProjectID,
+			"error", updateErr)
+	}
+	observeDeployment(project, deployment, DeploymentStatusFailed)
+	s.notify(NotificationEventDeploymentFailed, project, deployment)
+}
+
+// handleDeploymentCancelled records a deployment as cancelled. The project status is left as it was, since
+// the containers may be in any state Docker Compose had brought them to.
+func (s *ProjectService) handleDeploymentCancelled(project *Project, deployment *Deployment) error {
+	finishedAt := time.Now()
+	deployment.Status = DeploymentStatusCancelled
+	deployment.FinishedAt = &finishedAt
+
+	if deployment.Output != "" {
+		deployment.Output += "\n"
+	}
+	deployment.Output += "CANCELLED"
+
+	if updateErr := s.deploymentRepository.Update(deployment); updateErr != nil {
+		slog.Error("Failed to update deployment record as cancelled",
+			"deployment_id", deployment.ID,
+			"project_id", deployment.ProjectID,
+			"error", updateErr)
+	}
+
+	slog.Warn("Docker Compose up cancelled",
+		"project_id", deployment.ProjectID,
+		"deployment_id", deployment.ID)
+	observeDeployment(project, deployment, DeploymentStatusCancelled)
+	return ErrDeploymentCancelled
+}
+
 // completeDeployment handles the post-deployment database updates
-func (s *ProjectService) completeDeployment(project *Project, commitHash string, deployment Deployment) error {
+func (s *ProjectService) completeDeployment(project *Project, commitHash string, deployment *Deployment) error {
 	slog.Debug(
 		"Docker Compose project started",
 		"project_id", project.ID,
 	)
 
 	// Update deployment
+	finishedAt := time.Now()
 	deployment.Status = DeploymentStatusCompleted
-
-	// Update project
+	deployment.FinishedAt = &finishedAt
+
+	// Update project. A rollback remembers the commit it rolled back from, so that automatic
+	// deployments don't roll forward to it again; deploying another commit forgets it.
+	previousCommit := project.LastCommitStr()
+	switch {
+	case deployment.Rollback && project.RolledBackCommit == nil && previousCommit != commitHash:
+		project.RolledBackCommit = &previousCommit
+	case !deployment.Rollback && previousCommit != commitHash, project.RolledBackCommitStr() == commitHash:
+		project.RolledBackCommit = nil
+	}
 	project.Status = ProjectStatusRunning
+	project.DesiredState = DesiredStateRunning
 	project.LastCommit = &commitHash
+	project.Drift = nil // The containers were just brought in line with the commit
 
 	// TODO: Transaction
-	if err := s.deploymentRepository.Update(&deployment); err != nil {
+	if err := s.deploymentRepository.Update(deployment); err != nil {
 		return fmt.Errorf("failed to update deployment record: %w", err)
 	}
 
@@ -510,6 +993,8 @@ func (s *ProjectService) Stop(projectID uuid.UUID) error {
 	)
 
 	project.Status = ProjectStatusStopped
+	project.DesiredState = DesiredStateStopped
+	project.Drift = nil
 	return s.Update(project)
 }
 
@@ -588,6 +1073,8 @@ func (s *ProjectService) StopStreaming(projectID uuid.UUID, outputChan chan<- st
 	)
 
 	project.Status = ProjectStatusStopped
+	project.DesiredState = DesiredStateStopped
+	project.Drift = nil
 	err = s.Update(project)
 	if err != nil {
 		return fmt.Errorf("failed to update project status: %w", err)
@@ -635,6 +1122,8 @@ func (s *ProjectService) StopPiping(projectID uuid.UUID) error {
 	)
 
 	project.Status = ProjectStatusStopped
+	project.DesiredState = DesiredStateStopped
+	project.Drift = nil
 	return s.Update(project)
 }
 
@@ -873,7 +1362,12 @@ func (s *ProjectService) pullLatestChanges(project *Project) error {
 		return fmt.Errorf("failed to get git directory: %w", err)
 	}
 
+	if project.GitRefType != GitRefTypeBranch {
+		return s.checkoutGitRef(project)
+	}
+
 	if err = s.gitService.Pull(project.GitBranch, project.GitAuth, gitDir); err != nil {
+		countGitFetchError(project)
 		slog.Error("Failed to pull changes", "project_id", project.ID, "error", err)
 		return fmt.Errorf("failed to pull changes: %w", err)
 	}
@@ -882,6 +1376,39 @@ func (s *ProjectService) pullLatestChanges(project *Project) error {
 	return nil
 }
 
+// checkoutGitRef fetches the newest commit matching a project's tag pattern or its pinned commit and checks
+// it out
+func (s *ProjectService) checkoutGitRef(project *Project) error {
+	gitDir, err := project.GitDir()
+	if err != nil {
+		return fmt.Errorf("failed to get git directory: %w", err)
+	}
+
+	commit, ref, err := FetchTargetCommit(s.gitService, project)
+	if err != nil {
+		slog.Error("Failed to resolve git ref", "project_id", project.ID, "git_ref", project.GitRef, "error", err)
+		return fmt.Errorf("failed to resolve %s: %w", project.GitRefDescription(), err)
+	}
+
+	if err := s.gitService.Checkout(commit, gitDir); err != nil {
+		slog.Error("Failed to check out git ref", "project_id", project.ID, "git_ref", ref, "error", err)
+		return fmt.Errorf("failed to check out %s: %w", ref, err)
+	}
+
+	slog.Debug("Git ref checked out", "project_id", project.ID, "git_ref", ref, "commit", commit)
+	return nil
+}
+
+// normalizeGitRef trims the project's tag pattern or commit SHA, lowercases commit SHAs so that they compare
+// equal to the hashes Git reports, and validates the result
+func normalizeGitRef(project *Project) error {
+	project.GitRef = strings.TrimSpace(project.GitRef)
+	if project.GitRefType == GitRefTypeCommit {
+		project.GitRef = strings.ToLower(project.GitRef)
+	}
+	return ValidateGitRef(project.GitRefType, project.GitRef)
+}
+
 // ListDeployments lists all deployments for a specific project
 func (s *ProjectService) ListDeployments(projectID uuid.UUID) ([]*Deployment, error) {
 	// First verify the project exists
@@ -907,17 +1434,82 @@ func (s *ProjectService) ListDeployments(projectID uuid.UUID) ([]*Deployment, er
 	return deployments, nil
 }
 
+// deploymentOutput stores the clean output of a deployment for its record and streams it as JSON messages
+type deploymentOutput struct {
+	buffer     strings.Builder
+	outputChan chan<- string
+}
+
+// stream runs a command that streams its output to a channel, storing the output like the messages of send
+// and forwarding it as Docker output
+func (o *deploymentOutput) stream(run func(outputChan chan<- string) error) error {
+	// Create a capturing channel that forwards to the original channel
+	capturingChan := make(chan string, 100) // buffered channel
+	done := make(chan bool)
+
+	go func() {
+		defer func() { done <- true }()
+		for msg := range capturingChan {
+			// msg is now clean output from Docker, store it and wrap in JSON
+			o.send(msg, "docker", "")
+		}
+	}()
+
+	err := run(capturingChan)
+	close(capturingChan) // Signal that we're done sending to the capturing channel
+	<-done               // Wait for the goroutine to finish processing all messages
+	return err
+}
+
+func (o *deploymentOutput) send(cleanMsg, msgType, source string) {
+	// Store clean message for database
+	o.buffer.WriteString(cleanMsg + "\n")
+
+	// Create JSON message for web UI
+	msgData := map[string]string{
+		"type":    msgType,
+		"message": cleanMsg,
+	}
+	if source != "" {
+		msgData["source"] = source
+	}
+
+	if jsonMsg, err := json.Marshal(msgData); err == nil {
+		o.outputChan <- string(jsonMsg)
+	}
+}
+
+// notify sends a notification about a deployment to the project's notifiers, if notifications are enabled
+func (s *ProjectService) notify(event NotificationEvent, project *Project, deployment *Deployment) {
+	if s.notifications == nil {
+		return
+	}
+	s.notifications.Notify(NewDeploymentNotification(event, project, deployment))
+}
+
+// shortCommit returns the first 8 characters of a commit hash
+func shortCommit(commit string) string {
+	if len(commit) > 8 {
+		return commit[:8]
+	}
+	return commit
+}
+
 // NewProjectService creates a new ProjectService with dependency injection
 func NewProjectService(
 	projectRepository ProjectRepository,
 	deploymentRepository DeploymentRepository,
+	lockRepository DeploymentLockRepository,
 	gitService GitExecutor,
+	notifications NotificationSender,
 	config *Config,
 ) *ProjectService {
 	return &ProjectService{
 		projectRepository:    projectRepository,
 		deploymentRepository: deploymentRepository,
+		lockRepository:       lockRepository,
 		gitService:           gitService,
+		notifications:        notifications,
 		config:               config,
 	}
 }
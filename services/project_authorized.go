@@ -0,0 +1,330 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+)
+
+// AuthorizedProjectService wraps a ProjectManager and checks the acting user's role before every operation.
+//
+// Viewers may read projects, their logs, configuration, status and deployments.
+// Deployers may additionally deploy, roll back, cancel deployments and stop. Admins may update and remove projects,
+// and creating a project requires a global admin role.
+type AuthorizedProjectService struct {
+	inner ProjectManager
+	roles RoleManager
+	user  *User
+}
+
+// Ensure AuthorizedProjectService implements ProjectManager
+var _ ProjectManager = (*AuthorizedProjectService)(nil)
+
+// List returns the projects the user may view
+func (s *AuthorizedProjectService) List() ([]*Project, error) {
+	bindings, err := s.roles.ListRoles(s.user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := make(map[uuid.UUID]bool)
+	for _, b := range bindings {
+		if b.Role < RoleViewer {
+			continue
+		}
+		if b.IsGlobal() {
+			return s.inner.List()
+		}
+		visible[*b.ProjectID] = true
+	}
+
+	if len(visible) == 0 {
+		return []*Project{}, nil
+	}
+
+	projects, err := s.inner.List()
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]*Project, 0, len(visible))
+	for _, p := range projects {
+		if visible[p.ID] {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered, nil
+}
+
+func (s *AuthorizedProjectService) Get(id uuid.UUID) (*Project, error) {
+	if err := s.authorize(&id, RoleViewer); err != nil {
+		return nil, err
+	}
+	return s.inner.Get(id)
+}
+
+func (s *AuthorizedProjectService) Create(project *Project) (*Project, error) {
+	if err := s.authorize(nil, RoleAdmin); err != nil {
+		return nil, err
+	}
+	return s.inner.Create(project)
+}
+
+func (s *AuthorizedProjectService) Update(project *Project) error {
+	if err := s.authorize(&project.ID, RoleAdmin); err != nil {
+		return err
+	}
+	return s.inner.Update(project)
+}
+
+func (s *AuthorizedProjectService) Remove(projectID uuid.UUID) error {
+	if err := s.authorize(&projectID, RoleAdmin); err != nil {
+		return err
+	}
+	return s.inner.Remove(projectID)
+}
+
+// DeployStreaming deploys the project, recording the user as the actor of the deployment
+func (s *AuthorizedProjectService) DeployStreaming(
+	ctx context.Context,
+	projectID uuid.UUID,
+	options DeployOptions,
+	outputChan chan<- string,
+) error {
+	if err := s.authorize(&projectID, RoleDeployer); err != nil {
+		return err
+	}
+	options.Actor = s.user.Username
+	return s.inner.DeployStreaming(ctx, projectID, options, outputChan)
+}
+
+// DeployPiping deploys the project, recording the user as the actor of the deployment
+func (s *AuthorizedProjectService) DeployPiping(
+	ctx context.Context,
+	projectID uuid.UUID,
+	options DeployOptions,
+) error {
+	if err := s.authorize(&projectID, RoleDeployer); err != nil {
+		return err
+	}
+	options.Actor = s.user.Username
+	return s.inner.DeployPiping(ctx, projectID, options)
+}
+
+// RollbackStreaming rolls the project back, recording the user as the actor of the deployment
+func (s *AuthorizedProjectService) RollbackStreaming(
+	ctx context.Context,
+	projectID uuid.UUID,
+	target string,
+	options DeployOptions,
+	outputChan chan<- string,
+) error {
+	if err := s.authorize(&projectID, RoleDeployer); err != nil {
+		return err
+	}
+	options.Actor = s.user.Username
+	return s.inner.RollbackStreaming(ctx, projectID, target, options, outputChan)
+}
+
+// RollbackPiping rolls the project back, recording the user as the actor of the deployment
+func (s *AuthorizedProjectService) RollbackPiping(
+	ctx context.Context,
+	projectID uuid.UUID,
+	target string,
+	options DeployOptions,
+) error {
+	if err := s.authorize(&projectID, RoleDeployer); err != nil {
+		return err
+	}
+	options.Actor = s.user.Username
+	return s.inner.RollbackPiping(ctx, projectID, target, options)
+}
+
+// RequestApproval records a pending deployment, recording the user as its actor
+func (s *AuthorizedProjectService) RequestApproval(
+	projectID uuid.UUID,
+	commit string,
+	options DeployOptions,
+) (*Deployment, error) {
+	if err := s.authorize(&projectID, RoleDeployer); err != nil {
+		return nil, err
+	}
+	options.Actor = s.user.Username
+	return s.inner.RequestApproval(projectID, commit, options)
+}
+
+// ApproveStreaming deploys a pending deployment, recording the user as the actor of the deployment
+func (s *AuthorizedProjectService) ApproveStreaming(
+	ctx context.Context,
+	projectID uuid.UUID,
+	deploymentID uuid.UUID,
+	options DeployOptions,
+	outputChan chan<- string,
+) error {
+	if err := s.authorize(&projectID, RoleDeployer); err != nil {
+		return err
+	}
+	options.Actor = s.user.Username
+	return s.inner.ApproveStreaming(ctx, projectID, deploymentID, options, outputChan)
+}
+
+// ApprovePiping deploys a pending deployment, recording the user as the actor of the deployment
+func (s *AuthorizedProjectService) ApprovePiping(
+	ctx context.Context,
+	projectID uuid.UUID,
+	deploymentID uuid.UUID,
+	options DeployOptions,
+) error {
+	if err := s.authorize(&projectID, RoleDeployer); err != nil {
+		return err
+	}
+	options.Actor = s.user.Username
+	return s.inner.ApprovePiping(ctx, projectID, deploymentID, options)
+}
+
+// RejectDeployment rejects a pending deployment, recording the user as who rejected it
+func (s *AuthorizedProjectService) RejectDeployment(projectID, deploymentID uuid.UUID, options DeployOptions) error {
+	if err := s.authorize(&projectID, RoleDeployer); err != nil {
+		return err
+	}
+	options.Actor = s.user.Username
+	return s.inner.RejectDeployment(projectID, deploymentID, options)
+}
+
+func (s *AuthorizedProjectService) CancelDeployment(projectID uuid.UUID) error {
+	if err := s.authorize(&projectID, RoleDeployer); err != nil {
+		return err
+	}
+	return s.inner.CancelDeployment(projectID)
+}
+
+func (s *AuthorizedProjectService) LockProject(projectID uuid.UUID, options DeployOptions) (func(), error) {
+	if err := s.authorize(&projectID, RoleDeployer); err != nil {
+		return nil, err
+	}
+	options.Actor = s.user.Username
+	return s.inner.LockProject(projectID, options)
+}
+
+func (s *AuthorizedProjectService) Stop(projectID uuid.UUID) error {
+	if err := s.authorize(&projectID, RoleDeployer); err != nil {
+		return err
+	}
+	return s.inner.Stop(projectID)
+}
+
+func (s *AuthorizedProjectService) StopStreaming(projectID uuid.UUID, outputChan chan<- string) error {
+	if err := s.authorize(&projectID, RoleDeployer); err != nil {
+		return err
+	}
+	return s.inner.StopStreaming(projectID, outputChan)
+}
+
+func (s *AuthorizedProjectService) StopPiping(projectID uuid.UUID) error {
+	if err := s.authorize(&projectID, RoleDeployer); err != nil {
+		return err
+	}
+	return s.inner.StopPiping(projectID)
+}
+
+func (s *AuthorizedProjectService) GetLogsStreaming(projectID uuid.UUID, outputChan chan<- string) error {
+	if err := s.authorize(&projectID, RoleViewer); err != nil {
+		return err
+	}
+	return s.inner.GetLogsStreaming(projectID, outputChan)
+}
+
+func (s *AuthorizedProjectService) GetLogsPiping(projectID uuid.UUID) error {
+	if err := s.authorize(&projectID, RoleViewer); err != nil {
+		return err
+	}
+	return s.inner.GetLogsPiping(projectID)
+}
+
+func (s *AuthorizedProjectService) GetConfig(projectID uuid.UUID) (string, error) {
+	if err := s.authorize(&projectID, RoleViewer); err != nil {
+		return "", err
+	}
+	return s.inner.GetConfig(projectID)
+}
+
+func (s *AuthorizedProjectService) GetStatus(projectID uuid.UUID) (*ComposeStatus, error) {
+	if err := s.authorize(&projectID, RoleViewer); err != nil {
+		return nil, err
+	}
+	return s.inner.GetStatus(projectID)
+}
+
+func (s *AuthorizedProjectService) DetectDrift(ctx context.Context, projectID uuid.UUID) ([]ServiceDrift, error) {
+	if err := s.authorize(&projectID, RoleViewer); err != nil {
+		return nil, err
+	}
+	return s.inner.DetectDrift(ctx, projectID)
+}
+
+func (s *AuthorizedProjectService) Reconcile(ctx context.Context, projectID uuid.UUID) ([]string, error) {
+	if err := s.authorize(&projectID, RoleDeployer); err != nil {
+		return nil, err
+	}
+	return s.inner.Reconcile(ctx, projectID)
+}
+
+func (s *AuthorizedProjectService) CheckImages(ctx context.Context, projectID uuid.UUID) ([]ImageUpdate, error) {
+	if err := s.authorize(&projectID, RoleDeployer); err != nil {
+		return nil, err
+	}
+	return s.inner.CheckImages(ctx, projectID)
+}
+
+func (s *AuthorizedProjectService) PreviewDeploy(
+	ctx context.Context,
+	projectID uuid.UUID,
+	options DeployOptions,
+) (*DeployPreview, error) {
+	// Fetching changes the checkout, which viewers may not do
+	required := RoleViewer
+	if options.Pull {
+		required = RoleDeployer
+	}
+	if err := s.authorize(&projectID, required); err != nil {
+		return nil, err
+	}
+	options.Actor = s.user.Username
+	return s.inner.PreviewDeploy(ctx, projectID, options)
+}
+
+func (s *AuthorizedProjectService) ListDeployments(projectID uuid.UUID) ([]*Deployment, error) {
+	if err := s.authorize(&projectID, RoleViewer); err != nil {
+		return nil, err
+	}
+	return s.inner.ListDeployments(projectID)
+}
+
+// authorize checks the acting user's role and logs denied attempts
+func (s *AuthorizedProjectService) authorize(projectID *uuid.UUID, required Role) error {
+	err := s.roles.Authorize(s.user, projectID, required)
+	if err != nil {
+		logArgs := []any{
+			"layer", "service",
+			"operation", "authorize",
+			"username", s.user.Username,
+			"required_role", required,
+			"error", err,
+		}
+		if projectID != nil {
+			logArgs = append(logArgs, "project_id", *projectID)
+		}
+		slog.Warn("Permission denied", logArgs...)
+	}
+	return err
+}
+
+// NewAuthorizedProjectService wraps inner so that every operation is performed on behalf of user
+func NewAuthorizedProjectService(inner ProjectManager, roles RoleManager, user *User) *AuthorizedProjectService {
+	return &AuthorizedProjectService{
+		inner: inner,
+		roles: roles,
+		user:  user,
+	}
+}
@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthorizedProjectService_List(t *testing.T) {
+	roles, user, project := setupAuthorizationService(t)
+	other := &Project{ID: uuid.New(), Name: "other"}
+	inner := &MockProjectManager{
+		ListFunc: func() ([]*Project, error) {
+			return []*Project{project, other}, nil
+		},
+	}
+	service := NewAuthorizedProjectService(inner, roles, user)
+
+	projects, err := service.List()
+	require.NoError(t, err)
+	assert.Empty(t, projects)
+
+	require.NoError(t, roles.Grant(user.ID, &project.ID, RoleViewer))
+	projects, err = service.List()
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+	assert.Equal(t, project.ID, projects[0].ID)
+
+	require.NoError(t, roles.Grant(user.ID, nil, RoleViewer))
+	projects, err = service.List()
+	require.NoError(t, err)
+	assert.Len(t, projects, 2)
+}
+
+func TestAuthorizedProjectService_Operations(t *testing.T) {
+	tests := []struct {
+		name     string
+		call     func(s ProjectManager, p *Project) error
+		required Role
+	}{
+		{name: "get", required: RoleViewer, call: func(s ProjectManager, p *Project) error {
+			_, err := s.Get(p.ID)
+			return err
+		}},
+		{name: "status", required: RoleViewer, call: func(s ProjectManager, p *Project) error {
+			_, err := s.GetStatus(p.ID)
+			return err
+		}},
+		{name: "drift", required: RoleViewer, call: func(s ProjectManager, p *Project) error {
+			_, err := s.DetectDrift(context.Background(), p.ID)
+			return err
+		}},
+		{name: "deploy preview", required: RoleViewer, call: func(s ProjectManager, p *Project) error {
+			_, err := s.PreviewDeploy(context.Background(), p.ID, DeployOptions{})
+			return err
+		}},
+		{name: "deploy preview with pull", required: RoleDeployer, call: func(s ProjectManager, p *Project) error {
+			_, err := s.PreviewDeploy(context.Background(), p.ID, DeployOptions{Pull: true})
+			return err
+		}},
+		{name: "deployments", required: RoleViewer, call: func(s ProjectManager, p *Project) error {
+			_, err := s.ListDeployments(p.ID)
+			return err
+		}},
+		{name: "logs", required: RoleViewer, call: func(s ProjectManager, p *Project) error {
+			return s.GetLogsPiping(p.ID)
+		}},
+		{name: "deploy", required: RoleDeployer, call: func(s ProjectManager, p *Project) error {
+			return s.DeployPiping(context.Background(), p.ID, DeployOptions{})
+		}},
+		{name: "rollback", required: RoleDeployer, call: func(s ProjectManager, p *Project) error {
+			return s.RollbackPiping(context.Background(), p.ID, "", DeployOptions{})
+		}},
+		{name: "request approval", required: RoleDeployer, call: func(s ProjectManager, p *Project) error {
+			_, err := s.RequestApproval(p.ID, "", DeployOptions{})
+			return err
+		}},
+		{name: "approve", required: RoleDeployer, call: func(s ProjectManager, p *Project) error {
+			return s.ApprovePiping(context.Background(), p.ID, uuid.New(), DeployOptions{})
+		}},
+		{name: "reject", required: RoleDeployer, call: func(s ProjectManager, p *Project) error {
+			return s.RejectDeployment(p.ID, uuid.New(), DeployOptions{})
+		}},
+		{name: "reconcile", required: RoleDeployer, call: func(s ProjectManager, p *Project) error {
+			_, err := s.Reconcile(context.Background(), p.ID)
+			return err
+		}},
+		{name: "check images", required: RoleDeployer, call: func(s ProjectManager, p *Project) error {
+			_, err := s.CheckImages(context.Background(), p.ID)
+			return err
+		}},
+		{name: "cancel deployment", required: RoleDeployer, call: func(s ProjectManager, p *Project) error {
+			return s.CancelDeployment(p.ID)
+		}},
+		{name: "lock project", required: RoleDeployer, call: func(s ProjectManager, p *Project) error {
+			unlock, err := s.LockProject(p.ID, DeployOptions{})
+			if err == nil {
+				unlock()
+			}
+			return err
+		}},
+		{name: "stop", required: RoleDeployer, call: func(s ProjectManager, p *Project) error {
+			return s.Stop(p.ID)
+		}},
+		{name: "update", required: RoleAdmin, call: func(s ProjectManager, p *Project) error {
+			return s.Update(p)
+		}},
+		{name: "remove", required: RoleAdmin, call: func(s ProjectManager, p *Project) error {
+			return s.Remove(p.ID)
+		}},
+	}
+
+	for _, tt := range tests {
+		for _, granted := range []Role{RoleNone, RoleViewer, RoleDeployer, RoleAdmin} {
+			t.Run(tt.name+" as "+granted.String(), func(t *testing.T) {
+				roles, user, project := setupAuthorizationService(t)
+				if granted != RoleNone {
+					require.NoError(t, roles.Grant(user.ID, &project.ID, granted))
+				}
+				service := NewAuthorizedProjectService(&MockProjectManager{}, roles, user)
+
+				err := tt.call(service, project)
+
+				if granted >= tt.required {
+					assert.NoError(t, err)
+				} else {
+					assert.ErrorIs(t, err, ErrPermissionDenied)
+				}
+			})
+		}
+	}
+}
+
+func TestAuthorizedProjectService_CreateRequiresGlobalAdmin(t *testing.T) {
+	roles, user, project := setupAuthorizationService(t)
+	service := NewAuthorizedProjectService(&MockProjectManager{}, roles, user)
+
+	require.NoError(t, roles.Grant(user.ID, &project.ID, RoleAdmin))
+	_, err := service.Create(&Project{Name: "new"})
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+
+	require.NoError(t, roles.Grant(user.ID, nil, RoleAdmin))
+	_, err = service.Create(&Project{Name: "new"})
+	assert.NoError(t, err)
+}
+
+func TestAuthorizedProjectService_RecordsActor(t *testing.T) {
+	roles, user, project := setupAuthorizationService(t)
+	require.NoError(t, roles.Grant(user.ID, &project.ID, RoleDeployer))
+
+	var actors []string
+	inner := &MockProjectManager{
+		DeployPipingFunc: func(projectID uuid.UUID, options DeployOptions) error {
+			actors = append(actors, options.Actor)
+			return nil
+		},
+		RollbackPipingFunc: func(projectID uuid.UUID, target string, options DeployOptions) error {
+			actors = append(actors, options.Actor)
+			return nil
+		},
+		ApprovePipingFunc: func(projectID, deploymentID uuid.UUID, options DeployOptions) error {
+			actors = append(actors, options.Actor)
+			return nil
+		},
+		RejectDeploymentFunc: func(projectID, deploymentID uuid.UUID, options DeployOptions) error {
+			actors = append(actors, options.Actor)
+			return nil
+		},
+		LockProjectFunc: func(projectID uuid.UUID, options DeployOptions) (func(), error) {
+			actors = append(actors, options.Actor)
+			return func() {}, nil
+		},
+	}
+	service := NewAuthorizedProjectService(inner, roles, user)
+
+	// The signed-in user is recorded, whoever the caller claims started the deployment
+	require.NoError(t, service.DeployPiping(context.Background(), project.ID, DeployOptions{Actor: "someone-else"}))
+	require.NoError(t, service.RollbackPiping(context.Background(), project.ID, "", DeployOptions{}))
+	require.NoError(t, service.ApprovePiping(context.Background(), project.ID, uuid.New(), DeployOptions{}))
+	require.NoError(t, service.RejectDeployment(project.ID, uuid.New(), DeployOptions{}))
+	unlock, err := service.LockProject(project.ID, DeployOptions{})
+	require.NoError(t, err)
+	unlock()
+	assert.Equal(t, []string{user.Username, user.Username, user.Username, user.Username, user.Username}, actors)
+}
@@ -59,7 +59,12 @@ func TestProjectManager_Integration_CompleteLifecycle(t *testing.T) {
 	// Start deployment in goroutine
 	go func() {
 		defer close(outputChan)
-		deployDone <- projectManager.DeployStreaming(createdProject.ID, false, outputChan)
+		deployDone <- projectManager.DeployStreaming(
+			context.Background(),
+			createdProject.ID,
+			DeployOptions{},
+			outputChan,
+		)
 	}()
 
 	// Collect deployment output
@@ -302,7 +307,12 @@ func TestProjectManager_Integration_MergeStrategy(t *testing.T) {
 
 	go func() {
 		defer close(outputChan)
-		deployDone <- projectManager.DeployStreaming(createdProject.ID, false, outputChan)
+		deployDone <- projectManager.DeployStreaming(
+			context.Background(),
+			createdProject.ID,
+			DeployOptions{},
+			outputChan,
+		)
 	}()
 
 	timeout := time.After(60 * time.Second)
@@ -401,7 +411,12 @@ func TestProjectManager_Integration_ExtendStrategy(t *testing.T) {
 
 	go func() {
 		defer close(outputChan)
-		deployDone <- projectManager.DeployStreaming(createdProject.ID, false, outputChan)
+		deployDone <- projectManager.DeployStreaming(
+			context.Background(),
+			createdProject.ID,
+			DeployOptions{},
+			outputChan,
+		)
 	}()
 
 	timeout := time.After(60 * time.Second)
@@ -474,7 +489,12 @@ func TestProjectManager_Integration_IncludeStrategy(t *testing.T) {
 
 	go func() {
 		defer close(outputChan)
-		deployDone <- projectManager.DeployStreaming(createdProject.ID, false, outputChan)
+		deployDone <- projectManager.DeployStreaming(
+			context.Background(),
+			createdProject.ID,
+			DeployOptions{},
+			outputChan,
+		)
 	}()
 
 	timeout := time.After(60 * time.Second)
@@ -558,7 +578,12 @@ func TestProjectManager_Integration_Variables(t *testing.T) {
 
 	go func() {
 		defer close(outputChan)
-		deployDone <- projectManager.DeployStreaming(createdProject.ID, false, outputChan)
+		deployDone <- projectManager.DeployStreaming(
+			context.Background(),
+			createdProject.ID,
+			DeployOptions{},
+			outputChan,
+		)
 	}()
 
 	timeout := time.After(60 * time.Second)
@@ -1,11 +1,15 @@
 package services
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -100,6 +104,7 @@ func TestProjectService_Create_Success(t *testing.T) {
 	assert.NotEmpty(t, createdProject.WorkingDir)
 	assert.NotNil(t, createdProject.LastCommit)
 	assert.Equal(t, "abc123", *createdProject.LastCommit)
+	assert.Equal(t, DesiredStateStopped, createdProject.DesiredState)
 	assert.NotNil(t, createdProject.CreatedAt)
 	assert.NotNil(t, createdProject.UpdatedAt)
 }
@@ -124,6 +129,81 @@ func TestProjectService_Create_GitCloneFails(t *testing.T) {
 	assert.Contains(t, err.Error(), "git clone failed")
 }
 
+func TestProjectService_Create_WithTag(t *testing.T) {
+	service, _, _, gitService, _ := setupMockProjectService(t)
+
+	gitService.CloneFunc = func(gitURL string, gitBranch string, gitAuth *GitAuthConfig, workingDir string) error {
+		return os.MkdirAll(filepath.Join(workingDir, ".git"), 0o755)
+	}
+	gitService.ListTagsFunc = func(workingDir string) (map[string]string, error) {
+		return map[string]string{"v1.0.0": "aaa111", "v1.1.0": "bbb222"}, nil
+	}
+	checkedOut := ""
+	gitService.CheckoutFunc = func(commit string, workingDir string) error {
+		checkedOut = commit
+		return nil
+	}
+	gitService.GetLatestCommitFunc = func(workingDir string) (string, error) {
+		return checkedOut, nil
+	}
+
+	testProject := createTestProject()
+	testProject.GitRefType = GitRefTypeTag
+	testProject.GitRef = " v* "
+
+	createdProject, err := service.Create(testProject)
+
+	require.NoError(t, err)
+	assert.Equal(t, "bbb222", checkedOut)
+	assert.Equal(t, "v*", createdProject.GitRef)
+	assert.Equal(t, "bbb222", createdProject.LastCommitStr())
+}
+
+func TestProjectService_Create_NoMatchingTag(t *testing.T) {
+	service, repo, _, gitService, _ := setupMockProjectService(t)
+
+	gitService.CloneFunc = func(gitURL string, gitBranch string, gitAuth *GitAuthConfig, workingDir string) error {
+		return os.MkdirAll(filepath.Join(workingDir, ".git"), 0o755)
+	}
+
+	testProject := createTestProject()
+	testProject.GitRefType = GitRefTypeTag
+	testProject.GitRef = "~2.0"
+
+	createdProject, err := service.Create(testProject)
+
+	assert.ErrorIs(t, err, ErrNoMatchingTag)
+	assert.Nil(t, createdProject)
+	assert.Empty(t, repo.projects)
+	assert.NoDirExists(t, testProject.WorkingDir)
+}
+
+func TestProjectService_Create_InvalidGitRef(t *testing.T) {
+	service, _, _, _, _ := setupMockProjectService(t)
+
+	testProject := createTestProject()
+	testProject.GitRefType = GitRefTypeCommit
+	testProject.GitRef = "abc123"
+
+	createdProject, err := service.Create(testProject)
+
+	assert.ErrorIs(t, err, ErrInvalidGitRef)
+	assert.Nil(t, createdProject)
+}
+
+func TestProjectService_Create_ApprovalWithoutWatcher(t *testing.T) {
+	service, _, _, _, _ := setupMockProjectService(t)
+
+	testProject := createTestProject()
+	testProject.WatcherEnabled = false
+	testProject.RequireApproval = true
+
+	createdProject, err := service.Create(testProject)
+
+	assert.ErrorIs(t, err, ErrApprovalWithoutWatcher)
+	assert.Nil(t, createdProject)
+}
+
 func TestProjectService_Create_DuplicateName(t *testing.T) {
 	service, repo, _, gitService, _ := setupMockProjectService(t)
 
@@ -327,6 +407,24 @@ func TestProjectService_Update_EmptyGitURL(t *testing.T) {
 	assert.Contains(t, err.Error(), "git URL is required")
 }
 
+func TestProjectService_Update_NormalizesCommit(t *testing.T) {
+	service, repo, _, _, _ := setupMockProjectService(t)
+
+	testProject := createTestProject()
+	repo.projects[testProject.ID] = testProject
+
+	testProject.GitRefType = GitRefTypeCommit
+	testProject.GitRef = "0123456789ABCDEF0123456789ABCDEF01234567"
+
+	err := service.Update(testProject)
+
+	require.NoError(t, err)
+	assert.Equal(t, "0123456789abcdef0123456789abcdef01234567", repo.projects[testProject.ID].GitRef)
+
+	testProject.GitRef = "0123456"
+	assert.ErrorIs(t, service.Update(testProject), ErrInvalidGitRef)
+}
+
 func TestProjectService_Update_EmptyComposeFiles(t *testing.T) {
 	service, repo, _, _, _ := setupMockProjectService(t)
 
@@ -396,6 +494,41 @@ func TestParseProjectStatus(t *testing.T) {
 	}
 }
 
+// Tests for DeploymentTrigger enum
+func TestParseDeploymentTrigger(t *testing.T) {
+	for _, trigger := range []DeploymentTrigger{
+		DeploymentTriggerUnknown,
+		DeploymentTriggerWeb,
+		DeploymentTriggerCLI,
+		DeploymentTriggerAPI,
+		DeploymentTriggerWatcher,
+		DeploymentTriggerWebhook,
+		DeploymentTriggerRollback,
+		DeploymentTriggerAutoRollback,
+		DeploymentTriggerCatalog,
+		DeploymentTriggerReconcile,
+		DeploymentTriggerImage,
+	} {
+		t.Run(trigger.String(), func(t *testing.T) {
+			parsed, err := ParseDeploymentTrigger(trigger.String())
+			assert.NoError(t, err)
+			assert.Equal(t, trigger, parsed)
+		})
+	}
+
+	_, err := ParseDeploymentTrigger("cron")
+	assert.ErrorContains(t, err, "invalid deployment trigger")
+}
+
+func TestDeployment_Duration(t *testing.T) {
+	startedAt := time.Now()
+	finishedAt := startedAt.Add(90 * time.Second)
+
+	assert.Equal(t, 90*time.Second, (&Deployment{StartedAt: startedAt, FinishedAt: &finishedAt}).Duration())
+	assert.Zero(t, (&Deployment{StartedAt: startedAt}).Duration())
+	assert.Zero(t, (&Deployment{FinishedAt: &finishedAt}).Duration())
+}
+
 // Integration test for project directory structure
 func TestProjectService_ProjectDirectoryStructure(t *testing.T) {
 	service, _, _, gitService, tempDir := setupMockProjectService(t)
@@ -564,7 +697,7 @@ func TestProjectService_DeployStreaming_CommitHashDisplay(t *testing.T) {
 	}()
 
 	// Test deployment with pull=true to trigger commit hash display
-	err := service.DeployStreaming(testProject.ID, true, outputChan)
+	err := service.DeployStreaming(context.Background(), testProject.ID, DeployOptions{Pull: true}, outputChan)
 	close(outputChan)
 	<-done
 
@@ -596,3 +729,800 @@ func TestProjectService_DeployStreaming_CommitHashDisplay(t *testing.T) {
 	// Verify deployment was created
 	assert.Len(t, deploymentRepo.deployments, 1)
 }
+
+// addTestDeployments records deployments of the given commits for a project, the last one being the newest
+func addTestDeployments(
+	repo *MockDeploymentRepository,
+	projectID uuid.UUID,
+	status DeploymentStatus,
+	commits ...string,
+) []*Deployment {
+	deployments := make([]*Deployment, len(commits))
+	for i, commit := range commits {
+		deployment := createTestDeployment(projectID)
+		deployment.CommitHash = commit
+		deployment.Status = status
+		deployment.CreatedAt = time.Now().Add(time.Duration(i-len(commits)) * time.Minute)
+		repo.deployments[deployment.ID] = deployment
+		deployments[i] = deployment
+	}
+	return deployments
+}
+
+func TestProjectService_rollbackCommit(t *testing.T) {
+	service, _, deploymentRepo, _, _ := setupMockProjectService(t)
+
+	project := createTestProject()
+	project.LastCommit = stringPtr("cccc3333")
+	deployments := addTestDeployments(
+		deploymentRepo, project.ID, DeploymentStatusCompleted,
+		"aaaa1111", "abab2222", "bbbb2222", "cccc3333",
+	)
+	addTestDeployments(deploymentRepo, project.ID, DeploymentStatusFailed, "dddd4444")
+	addTestDeployments(deploymentRepo, uuid.New(), DeploymentStatusCompleted, "eeee5555")
+	pending := addTestDeployments(deploymentRepo, project.ID, DeploymentStatusPending, "ffff6666")
+
+	tests := []struct {
+		name           string
+		target         string
+		expectedCommit string
+		expectedErr    string
+	}{
+		{name: "previous successful deployment", target: "", expectedCommit: "bbbb2222"},
+		{name: "deployment ID", target: deployments[0].ID.String(), expectedCommit: "aaaa1111"},
+		{name: "full commit", target: "abab2222", expectedCommit: "abab2222"},
+		{name: "commit prefix", target: "AAAA", expectedCommit: "aaaa1111"},
+		{name: "failed deployment commit", target: "dddd", expectedCommit: "dddd4444"},
+		{name: "ambiguous commit prefix", target: "a", expectedErr: `commit "a" is ambiguous`},
+		{name: "commit of another project", target: "eeee", expectedErr: `commit "eeee" was never deployed`},
+		{name: "pending commit", target: "ffff", expectedErr: `commit "ffff" was never deployed`},
+		{name: "pending deployment", target: pending[0].ID.String(), expectedErr: "is pending and was never deployed"},
+		{name: "unknown deployment", target: uuid.NewString(), expectedErr: "not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commit, err := service.rollbackCommit(project, tt.target)
+
+			if tt.expectedErr != "" {
+				assert.ErrorIs(t, err, ErrInvalidRollbackTarget)
+				assert.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedCommit, commit)
+		})
+	}
+}
+
+func TestProjectService_rollbackCommit_NothingToRollBackTo(t *testing.T) {
+	service, _, deploymentRepo, _, _ := setupMockProjectService(t)
+
+	project := createTestProject()
+	addTestDeployments(deploymentRepo, project.ID, DeploymentStatusCompleted, project.LastCommitStr())
+
+	_, err := service.rollbackCommit(project, "")
+
+	assert.ErrorIs(t, err, ErrInvalidRollbackTarget)
+}
+
+func TestProjectService_RollbackStreaming_ChecksOutCommit(t *testing.T) {
+	service, repo, deploymentRepo, gitService, _ := setupMockProjectService(t)
+
+	project := createTestProject()
+	repo.projects[project.ID] = project
+	addTestDeployments(deploymentRepo, project.ID, DeploymentStatusCompleted, "aaaa1111", project.LastCommitStr())
+
+	checkedOut := ""
+	gitService.CheckoutFunc = func(commit string, workingDir string) error {
+		checkedOut = commit
+		return nil
+	}
+	gitService.GetLatestCommitFunc = func(workingDir string) (string, error) {
+		return checkedOut, nil
+	}
+
+	outputChan := make(chan string, 100)
+	options := DeployOptions{Pull: true, Trigger: DeploymentTriggerWeb, Actor: "alice"}
+	err := service.RollbackStreaming(context.Background(), project.ID, "", options, outputChan)
+	close(outputChan)
+
+	// Docker Compose is not available, but the commit is checked out and a rollback deployment recorded
+	assert.Error(t, err)
+	assert.Equal(t, "aaaa1111", checkedOut)
+
+	deployments, _ := deploymentRepo.ListByProjectID(project.ID)
+	require.Len(t, deployments, 3)
+	assert.Equal(t, "aaaa1111", deployments[0].CommitHash)
+	assert.True(t, deployments[0].Rollback)
+	assert.Contains(t, deployments[0].Output, "Rolling back to commit aaaa1111")
+
+	// Rollbacks never pull and are recorded as such, with who started them and what was deployed before
+	assert.Equal(t, DeploymentTriggerRollback, deployments[0].Trigger)
+	assert.Equal(t, "alice", deployments[0].Actor)
+	assert.False(t, deployments[0].Pull)
+	assert.Equal(t, project.LastCommitStr(), deployments[0].PreviousCommit)
+	assert.False(t, deployments[0].StartedAt.IsZero())
+	require.NotNil(t, deployments[0].FinishedAt)
+	assert.False(t, deployments[0].FinishedAt.Before(deployments[0].StartedAt))
+}
+
+func TestProjectService_RollbackStreaming_CheckoutFails(t *testing.T) {
+	service, repo, deploymentRepo, gitService, _ := setupMockProjectService(t)
+
+	project := createTestProject()
+	repo.projects[project.ID] = project
+	addTestDeployments(deploymentRepo, project.ID, DeploymentStatusCompleted, "aaaa1111", project.LastCommitStr())
+
+	gitService.CheckoutFunc = func(commit string, workingDir string) error {
+		return fmt.Errorf("object not found")
+	}
+
+	outputChan := make(chan string, 100)
+	err := service.RollbackStreaming(context.Background(), project.ID, "aaaa1111", DeployOptions{}, outputChan)
+	close(outputChan)
+
+	assert.ErrorContains(t, err, "failed to check out commit")
+	assert.Len(t, deploymentRepo.deployments, 2, "No deployment should be recorded")
+}
+
+func TestProjectService_pullLatestChanges_PinnedCommit(t *testing.T) {
+	service, _, _, gitService, _ := setupMockProjectService(t)
+
+	project := createTestProject()
+	project.GitRefType = GitRefTypeCommit
+	project.GitRef = "0123456789abcdef0123456789abcdef01234567"
+
+	gitService.PullFunc = func(gitBranch string, auth *GitAuthConfig, workingDir string) error {
+		t.Error("Pull() called for a project pinned to a commit")
+		return nil
+	}
+	checkedOut := ""
+	gitService.CheckoutFunc = func(commit string, workingDir string) error {
+		checkedOut = commit
+		return nil
+	}
+
+	err := service.pullLatestChanges(project)
+
+	require.NoError(t, err)
+	assert.Equal(t, project.GitRef, checkedOut)
+}
+
+func TestProjectService_completeDeployment_RolledBackCommit(t *testing.T) {
+	tests := []struct {
+		name             string
+		lastCommit       string
+		rolledBackCommit *string
+		commit           string
+		rollback         bool
+		expected         *string
+	}{
+		{
+			name:       "rollback remembers previous commit",
+			lastCommit: "new",
+			commit:     "old",
+			rollback:   true,
+			expected:   stringPtr("new"),
+		},
+		{
+			name:             "second rollback keeps first rolled back commit",
+			lastCommit:       "old",
+			rolledBackCommit: stringPtr("new"),
+			commit:           "older",
+			rollback:         true,
+			expected:         stringPtr("new"),
+		},
+		{
+			name:             "rollback to rolled back commit forgets it",
+			lastCommit:       "old",
+			rolledBackCommit: stringPtr("new"),
+			commit:           "new",
+			rollback:         true,
+		},
+		{
+			name:             "redeploy keeps rolled back commit",
+			lastCommit:       "old",
+			rolledBackCommit: stringPtr("new"),
+			commit:           "old",
+			expected:         stringPtr("new"),
+		},
+		{
+			name:             "deploy of another commit forgets it",
+			lastCommit:       "old",
+			rolledBackCommit: stringPtr("new"),
+			commit:           "newer",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repo, deploymentRepo, _, _ := setupMockProjectService(t)
+
+			project := createTestProject()
+			project.LastCommit = &tt.lastCommit
+			project.RolledBackCommit = tt.rolledBackCommit
+			repo.projects[project.ID] = project
+
+			deployment := NewDeployment(project.ID, tt.commit)
+			deployment.Rollback = tt.rollback
+			require.NoError(t, deploymentRepo.Create(&deployment))
+
+			require.NoError(t, service.completeDeployment(project, tt.commit, &deployment))
+
+			assert.Equal(t, tt.commit, project.LastCommitStr())
+			assert.Equal(t, tt.expected, project.RolledBackCommit)
+		})
+	}
+}
+
+func TestProjectService_DeployStreaming_Notifications(t *testing.T) {
+	tests := []struct {
+		name           string
+		pullError      error
+		expectedEvents []NotificationEvent
+		expectedOutput string
+	}{
+		{
+			// Docker Compose is not available, so the deployment starts and fails
+			name:           "compose failure",
+			expectedEvents: []NotificationEvent{NotificationEventDeploymentStarted, NotificationEventDeploymentFailed},
+			expectedOutput: "ERROR: ",
+		},
+		{
+			name:           "pull failure",
+			pullError:      fmt.Errorf("authentication required"),
+			expectedEvents: []NotificationEvent{NotificationEventDeploymentFailed},
+			expectedOutput: "Failed to pull latest changes: failed to pull changes: authentication required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repo, deploymentRepo, gitService, _ := setupMockProjectService(t)
+			notifications := &MockNotificationSender{}
+			service.notifications = notifications
+
+			project := createTestProject()
+			repo.projects[project.ID] = project
+			gitService.GetLatestCommitFunc = func(workingDir string) (string, error) {
+				return "abc123def456789012345678901234567890abcd", nil
+			}
+			gitService.PullFunc = func(gitBranch string, gitAuth *GitAuthConfig, workingDir string) error {
+				return tt.pullError
+			}
+
+			outputChan := make(chan string, 100)
+			err := service.DeployStreaming(context.Background(), project.ID, DeployOptions{Pull: true}, outputChan)
+			close(outputChan)
+
+			assert.Error(t, err)
+			assert.Equal(t, tt.expectedEvents, notifications.Events())
+
+			last := notifications.notifications[len(notifications.notifications)-1]
+			assert.Equal(t, project.ID, last.ProjectID)
+			assert.Equal(t, project.Name, last.ProjectName)
+			assert.Equal(t, "abc123def456789012345678901234567890abcd", last.CommitHash)
+			assert.NotNil(t, last.DeploymentID)
+			assert.Contains(t, last.Output, tt.expectedOutput)
+
+			// The deployment is recorded as finished
+			require.Len(t, deploymentRepo.deployments, 1)
+			for _, deployment := range deploymentRepo.deployments {
+				assert.Equal(t, DeploymentStatusFailed, deployment.Status)
+				assert.NotNil(t, deployment.FinishedAt)
+				assert.Contains(t, deployment.Output, tt.expectedOutput)
+			}
+		})
+	}
+}
+
+func TestProjectService_DeployStreaming_Cancelled(t *testing.T) {
+	service, repo, deploymentRepo, gitService, _ := setupMockProjectService(t)
+	notifications := &MockNotificationSender{}
+	service.notifications = notifications
+
+	project := createTestProject()
+	project.Status = ProjectStatusRunning
+	repo.projects[project.ID] = project
+	gitService.GetLatestCommitFunc = func(workingDir string) (string, error) {
+		return "abc123def456789012345678901234567890abcd", nil
+	}
+
+	// Cancelled before Docker Compose starts
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	outputChan := make(chan string, 100)
+	err := service.DeployStreaming(ctx, project.ID, DeployOptions{}, outputChan)
+	close(outputChan)
+
+	assert.ErrorIs(t, err, ErrDeploymentCancelled)
+	require.Len(t, deploymentRepo.deployments, 1)
+	for _, deployment := range deploymentRepo.deployments {
+		assert.Equal(t, DeploymentStatusCancelled, deployment.Status)
+		assert.NotNil(t, deployment.FinishedAt)
+		assert.Contains(t, deployment.Output, "CANCELLED")
+	}
+	assert.Equal(t, ProjectStatusRunning, repo.projects[project.ID].Status)
+	assert.Equal(t, []NotificationEvent{NotificationEventDeploymentStarted}, notifications.Events())
+}
+
+func TestProjectService_DeployStreaming_TimedOut(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping streaming command test in short mode")
+	}
+
+	service, repo, deploymentRepo, gitService, _ := setupMockProjectService(t)
+
+	// Stand in for Docker Compose, pulling right away and starting a project that never gets healthy
+	dockerCommand := filepath.Join(t.TempDir(), "docker")
+	script := "#!/bin/sh\ncase \" $* \" in *\" up \"*) exec sleep 60 ;; esac\n"
+	require.NoError(t, os.WriteFile(dockerCommand, []byte(script), 0o755))
+	service.config.DockerCommand = dockerCommand
+
+	project := createTestProject()
+	project.Status = ProjectStatusRunning
+	project.Timeouts = ComposeTimeouts{Up: 100 * time.Millisecond}
+	repo.projects[project.ID] = project
+	gitService.GetLatestCommitFunc = func(workingDir string) (string, error) {
+		return "abc123def456789012345678901234567890abcd", nil
+	}
+
+	outputChan := make(chan string, 100)
+	err := service.DeployStreaming(context.Background(), project.ID, DeployOptions{}, outputChan)
+	close(outputChan)
+
+	assert.ErrorIs(t, err, ErrComposeTimeout)
+	require.Len(t, deploymentRepo.deployments, 1)
+	for _, deployment := range deploymentRepo.deployments {
+		assert.Equal(t, DeploymentStatusFailed, deployment.Status)
+		assert.NotNil(t, deployment.FinishedAt)
+		assert.Contains(t, deployment.Output, "ERROR: docker compose up timed out after 100ms")
+	}
+	assert.Equal(t, ProjectStatusError, repo.projects[project.ID].Status)
+}
+
+func TestProjectService_DeployStreaming_AutoRollback(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping streaming command test in short mode")
+	}
+
+	tests := []struct {
+		name               string
+		autoRollback       bool
+		completedCommits   []string
+		expectedCommit     string
+		expectedRolledBack *string
+	}{
+		{
+			name:               "rolls back to last successful deployment",
+			autoRollback:       true,
+			completedCommits:   []string{"aaaa1111", "abab2222"},
+			expectedCommit:     "abab2222",
+			expectedRolledBack: stringPtr("bbbb3333"),
+		},
+		{
+			name:             "disabled",
+			completedCommits: []string{"abab2222"},
+			expectedCommit:   "bbbb3333",
+		},
+		{
+			name:           "nothing to roll back to",
+			autoRollback:   true,
+			expectedCommit: "bbbb3333",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repo, deploymentRepo, gitService, _ := setupMockProjectService(t)
+
+			// Stand in for Docker Compose, failing to start the project the first time only
+			dir := t.TempDir()
+			dockerCommand := filepath.Join(dir, "docker")
+			started := filepath.Join(dir, "started")
+			script := fmt.Sprintf(
+				"#!/bin/sh\ncase \" $* \" in *\" up \"*) [ -e %[1]s ] && exit 0; touch %[1]s; exit 1 ;; esac\n",
+				started,
+			)
+			require.NoError(t, os.WriteFile(dockerCommand, []byte(script), 0o755))
+			service.config.DockerCommand = dockerCommand
+
+			project := createTestProject()
+			project.Status = ProjectStatusRunning
+			project.AutoRollback = tt.autoRollback
+			if len(tt.completedCommits) > 0 {
+				project.LastCommit = &tt.completedCommits[len(tt.completedCommits)-1]
+			}
+			repo.projects[project.ID] = project
+			addTestDeployments(deploymentRepo, project.ID, DeploymentStatusCompleted, tt.completedCommits...)
+
+			checkedOut := "bbbb3333"
+			gitService.CheckoutFunc = func(commit string, workingDir string) error {
+				checkedOut = commit
+				return nil
+			}
+			gitService.GetLatestCommitFunc = func(workingDir string) (string, error) {
+				return checkedOut, nil
+			}
+
+			outputChan := make(chan string, 100)
+			options := DeployOptions{Trigger: DeploymentTriggerWatcher}
+			err := service.DeployStreaming(context.Background(), project.ID, options, outputChan)
+			close(outputChan)
+
+			// The deployment failed, whether or not it was rolled back
+			assert.ErrorContains(t, err, "failed to start project")
+			assert.Equal(t, tt.expectedCommit, checkedOut)
+			assert.Equal(t, tt.expectedRolledBack, repo.projects[project.ID].RolledBackCommit)
+
+			deployments, _ := deploymentRepo.ListByProjectID(project.ID)
+			if tt.expectedRolledBack == nil {
+				require.Len(t, deployments, len(tt.completedCommits)+1)
+				assert.Equal(t, DeploymentStatusFailed, deployments[0].Status)
+				assert.Equal(t, ProjectStatusError, repo.projects[project.ID].Status)
+				return
+			}
+
+			// Both the failure and the rollback are recorded
+			require.Len(t, deployments, len(tt.completedCommits)+2)
+			assert.Equal(t, "bbbb3333", deployments[1].CommitHash)
+			assert.Equal(t, DeploymentStatusFailed, deployments[1].Status)
+
+			rollback := deployments[0]
+			assert.Equal(t, tt.expectedCommit, rollback.CommitHash)
+			assert.Equal(t, DeploymentStatusCompleted, rollback.Status)
+			assert.True(t, rollback.Rollback)
+			assert.Equal(t, DeploymentTriggerAutoRollback, rollback.Trigger)
+			assert.Contains(t, rollback.Output, "Rolling back automatically to commit "+tt.expectedCommit)
+
+			assert.Equal(t, ProjectStatusRunning, repo.projects[project.ID].Status)
+			assert.Equal(t, tt.expectedCommit, repo.projects[project.ID].LastCommitStr())
+		})
+	}
+}
+
+func TestProjectService_DeployStreaming_HealthChecks(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping streaming command test in short mode")
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name           string
+		check          string
+		expectedStatus DeploymentStatus
+		expectedOutput string
+	}{
+		{
+			name:           "passing",
+			check:          "http " + server.URL + "/health",
+			expectedStatus: DeploymentStatusCompleted,
+			expectedOutput: "Health check passed: http " + server.URL + "/health (HTTP 200)",
+		},
+		{
+			name:           "failing",
+			check:          "http " + server.URL + "/down",
+			expectedStatus: DeploymentStatusFailed,
+			expectedOutput: "ERROR: health check failed: http " + server.URL + "/down did not pass within 100ms",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repo, deploymentRepo, gitService, _ := setupMockProjectService(t)
+
+			// Stand in for Docker Compose, starting the project right away
+			dockerCommand := filepath.Join(t.TempDir(), "docker")
+			require.NoError(t, os.WriteFile(dockerCommand, []byte("#!/bin/sh\nexit 0\n"), 0o755))
+			service.config.DockerCommand = dockerCommand
+
+			project := createTestProject()
+			check, err := ParseHealthCheck(tt.check)
+			require.NoError(t, err)
+			project.HealthChecks = []HealthCheck{check}
+			project.Timeouts = ComposeTimeouts{Health: 100 * time.Millisecond}
+			repo.projects[project.ID] = project
+			gitService.GetLatestCommitFunc = func(workingDir string) (string, error) {
+				return "abc123def456789012345678901234567890abcd", nil
+			}
+
+			outputChan := make(chan string, 100)
+			err = service.DeployStreaming(context.Background(), project.ID, DeployOptions{}, outputChan)
+			close(outputChan)
+
+			if tt.expectedStatus == DeploymentStatusCompleted {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrHealthCheckFailed)
+			}
+
+			// The results are stored with the deployment
+			require.Len(t, deploymentRepo.deployments, 1)
+			for _, deployment := range deploymentRepo.deployments {
+				assert.Equal(t, tt.expectedStatus, deployment.Status)
+				assert.Contains(t, deployment.Output, tt.expectedOutput)
+				require.Len(t, deployment.HealthChecks, 1)
+				assert.Equal(t, tt.check, deployment.HealthChecks[0].Check)
+				assert.Equal(t, tt.expectedStatus == DeploymentStatusCompleted, deployment.HealthChecks[0].Passed)
+			}
+		})
+	}
+}
+
+func TestProjectService_DeployStreaming_Hooks(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping streaming command test in short mode")
+	}
+
+	tests := []struct {
+		name             string
+		preDeployHooks   []string
+		postDeployHooks  []string
+		expectedStatus   DeploymentStatus
+		expectedCommands []string
+		expectedOutput   string
+	}{
+		{
+			name:            "passing",
+			preDeployHooks:  []string{"run app migrate", "script echo script >> $OAR_TEST_LOG"},
+			postDeployHooks: []string{"exec cache flush"},
+			expectedStatus:  DeploymentStatusCompleted,
+			expectedCommands: []string{
+				"run --rm --no-TTY app sh -c migrate",
+				"script",
+				"up --detach --wait --quiet-pull --no-color --remove-orphans",
+				"exec --no-TTY cache sh -c flush",
+			},
+			expectedOutput: "Running post-deploy hook: exec cache flush",
+		},
+		{
+			name:             "failing pre-deploy hook",
+			preDeployHooks:   []string{"script exit 3", "run app migrate"},
+			postDeployHooks:  []string{"exec cache flush"},
+			expectedStatus:   DeploymentStatusFailed,
+			expectedCommands: nil,
+			expectedOutput:   `ERROR: hook failed: pre-deploy hook "script exit 3": exit status 3`,
+		},
+		{
+			name:            "failing post-deploy hook",
+			postDeployHooks: []string{"exec cache fail"},
+			expectedStatus:  DeploymentStatusFailed,
+			expectedCommands: []string{
+				"up --detach --wait --quiet-pull --no-color --remove-orphans",
+				"exec --no-TTY cache sh -c fail",
+			},
+			expectedOutput: `ERROR: hook failed: post-deploy hook "exec cache fail": exit status 1`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repo, deploymentRepo, gitService, _ := setupMockProjectService(t)
+
+			// Stand in for Docker Compose, logging its commands without the global options and failing those
+			// that run "fail"
+			tempDir := t.TempDir()
+			log := filepath.Join(tempDir, "log")
+			t.Setenv("OAR_TEST_LOG", log)
+			script := `#!/bin/sh
+while [ "$1" != "compose" ]; do shift; done
+shift
+while case "$1" in --*) true ;; *) false ;; esac; do shift 2; done
+echo "$*" >> "$OAR_TEST_LOG"
+case " $* " in *" fail "*) exit 1 ;; esac
+`
+			dockerCommand := filepath.Join(tempDir, "docker")
+			require.NoError(t, os.WriteFile(dockerCommand, []byte(script), 0o755))
+			service.config.DockerCommand = dockerCommand
+
+			project := createTestProject()
+			project.WorkingDir = tempDir
+			require.NoError(t, os.Mkdir(filepath.Join(tempDir, GitDir), 0o755))
+			var err error
+			project.PreDeployHooks, err = ParseHooks(tt.preDeployHooks)
+			require.NoError(t, err)
+			project.PostDeployHooks, err = ParseHooks(tt.postDeployHooks)
+			require.NoError(t, err)
+			repo.projects[project.ID] = project
+			gitService.GetLatestCommitFunc = func(workingDir string) (string, error) {
+				return "abc123def456789012345678901234567890abcd", nil
+			}
+
+			outputChan := make(chan string, 100)
+			err = service.DeployStreaming(context.Background(), project.ID, DeployOptions{}, outputChan)
+			close(outputChan)
+
+			if tt.expectedStatus == DeploymentStatusCompleted {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrHookFailed)
+			}
+
+			var commands []string
+			if data, err := os.ReadFile(log); err == nil {
+				commands = strings.Split(strings.TrimSpace(string(data)), "\n")
+			}
+			assert.Equal(t, tt.expectedCommands, commands)
+
+			require.Len(t, deploymentRepo.deployments, 1)
+			for _, deployment := range deploymentRepo.deployments {
+				assert.Equal(t, tt.expectedStatus, deployment.Status)
+				assert.Contains(t, deployment.Output, tt.expectedOutput)
+			}
+		})
+	}
+}
+
+func TestProjectService_DeployStreaming_Manifest(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping streaming command test in short mode")
+	}
+
+	tests := []struct {
+		name             string
+		manifest         string
+		expectedStatus   DeploymentStatus
+		expectedCommands []string
+		expectedOutput   string
+	}{
+		{
+			name: "valid",
+			manifest: `
+compose_files: [compose.yml, compose.prod.yml]
+profiles: [workers]
+hooks:
+  pre_deploy: [run app migrate]
+`,
+			expectedStatus: DeploymentStatusCompleted,
+			expectedCommands: []string{
+				"--file compose.yml --file compose.prod.yml --profile workers run --rm --no-TTY app sh -c migrate",
+				"--file compose.yml --file compose.prod.yml --profile workers " +
+					"up --detach --wait --quiet-pull --no-color --remove-orphans",
+			},
+			expectedOutput: "Using project settings from .oar.yaml",
+		},
+		{
+			name:             "invalid",
+			manifest:         "compose_files: [../compose.yml]",
+			expectedStatus:   DeploymentStatusFailed,
+			expectedCommands: nil,
+			expectedOutput:   `ERROR: invalid manifest: .oar.yaml: compose file "../compose.yml" is not a path`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repo, deploymentRepo, gitService, _ := setupMockProjectService(t)
+
+			// Stand in for Docker Compose, logging its commands with the compose files relative to the repository
+			tempDir := t.TempDir()
+			gitDir := filepath.Join(tempDir, GitDir)
+			log := filepath.Join(tempDir, "log")
+			t.Setenv("OAR_TEST_LOG", log)
+			script := `#!/bin/sh
+while [ "$1" != "--project-name" ]; do shift; done
+shift 2
+echo "$*" | sed "s|$OAR_TEST_GIT_DIR/||g" >> "$OAR_TEST_LOG"
+`
+			t.Setenv("OAR_TEST_GIT_DIR", gitDir)
+			dockerCommand := filepath.Join(tempDir, "docker")
+			require.NoError(t, os.WriteFile(dockerCommand, []byte(script), 0o755))
+			service.config.DockerCommand = dockerCommand
+
+			project := createTestProject()
+			project.WorkingDir = tempDir
+			require.NoError(t, os.Mkdir(gitDir, 0o755))
+			require.NoError(t, os.WriteFile(filepath.Join(gitDir, ManifestFile), []byte(tt.manifest), 0o644))
+			repo.projects[project.ID] = project
+			gitService.GetLatestCommitFunc = func(workingDir string) (string, error) {
+				return "abc123def456789012345678901234567890abcd", nil
+			}
+
+			outputChan := make(chan string, 100)
+			err := service.DeployStreaming(context.Background(), project.ID, DeployOptions{}, outputChan)
+			close(outputChan)
+
+			if tt.expectedStatus == DeploymentStatusCompleted {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrInvalidManifest)
+			}
+
+			var commands []string
+			if data, err := os.ReadFile(log); err == nil {
+				commands = strings.Split(strings.TrimSpace(string(data)), "\n")
+			}
+			assert.Equal(t, tt.expectedCommands, commands)
+
+			require.Len(t, deploymentRepo.deployments, 1)
+			for _, deployment := range deploymentRepo.deployments {
+				assert.Equal(t, tt.expectedStatus, deployment.Status)
+				assert.Contains(t, deployment.Output, tt.expectedOutput)
+			}
+
+			// The manifest does not change the project's own settings
+			assert.Equal(t, []string{"docker-compose.yml"}, repo.projects[project.ID].ComposeFiles)
+			assert.Empty(t, repo.projects[project.ID].PreDeployHooks)
+		})
+	}
+}
+
+func TestProjectService_Update_InvalidHook(t *testing.T) {
+	service, repo, _, _, _ := setupMockProjectService(t)
+
+	project := createTestProject()
+	repo.projects[project.ID] = project
+
+	project.PostDeployHooks = []Hook{{Type: HookTypeExec, Command: "flush"}}
+	assert.ErrorIs(t, service.Update(project), ErrInvalidHook)
+}
+
+func TestProjectService_Update_InvalidHealthCheck(t *testing.T) {
+	service, repo, _, _, _ := setupMockProjectService(t)
+
+	project := createTestProject()
+	repo.projects[project.ID] = project
+
+	project.HealthChecks = []HealthCheck{{Type: HealthCheckTypeTCP, Address: "localhost"}}
+	assert.ErrorIs(t, service.Update(project), ErrInvalidHealthCheck)
+}
+
+func TestProjectService_Update_InvalidTimeouts(t *testing.T) {
+	service, repo, _, _, _ := setupMockProjectService(t)
+
+	project := createTestProject()
+	repo.projects[project.ID] = project
+
+	project.Timeouts.Pull = -time.Minute
+	assert.ErrorIs(t, service.Update(project), ErrInvalidTimeout)
+}
+
+func TestProjectService_Update_ApprovalWithoutWatcher(t *testing.T) {
+	service, repo, _, _, _ := setupMockProjectService(t)
+
+	project := createTestProject()
+	project.RequireApproval = true
+	repo.projects[project.ID] = project
+
+	project.WatcherEnabled = false
+	assert.ErrorIs(t, service.Update(project), ErrApprovalWithoutWatcher)
+}
+
+func TestProjectService_CancelDeployment(t *testing.T) {
+	service, _, _, _, _ := setupMockProjectService(t)
+	projectID := uuid.New()
+
+	assert.ErrorIs(t, service.CancelDeployment(projectID), ErrNoDeploymentInProgress)
+
+	ctx, done := service.startCancellable(context.Background(), projectID)
+	assert.NoError(t, ctx.Err())
+
+	require.NoError(t, service.CancelDeployment(projectID))
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+
+	done()
+	assert.ErrorIs(t, service.CancelDeployment(projectID), ErrNoDeploymentInProgress)
+}
+
+func TestProjectService_CancelDeployment_LatestDeployment(t *testing.T) {
+	service, _, _, _, _ := setupMockProjectService(t)
+	projectID := uuid.New()
+
+	first, doneFirst := service.startCancellable(context.Background(), projectID)
+	second, doneSecond := service.startCancellable(context.Background(), projectID)
+	defer doneSecond()
+
+	// The first deployment finishing doesn't unregister the second one
+	doneFirst()
+	require.NoError(t, service.CancelDeployment(projectID))
+	assert.ErrorIs(t, first.Err(), context.Canceled)
+	assert.ErrorIs(t, second.Err(), context.Canceled)
+}
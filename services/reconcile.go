@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+)
+
+// DesiredState is the state a project is meant to be in. Unlike its Status, the outcome of the last deployment or
+// stop, it doesn't change when the containers go down by themselves, so the watcher can bring them back up.
+type DesiredState int
+
+const (
+	DesiredStateNone    DesiredState = iota // Not recorded, the project is left as it is
+	DesiredStateRunning                     // Deployed, brought back up when its containers go down
+	DesiredStateStopped                     // Created or stopped
+)
+
+func (s DesiredState) String() string {
+	switch s {
+	case DesiredStateRunning:
+		return "running"
+	case DesiredStateStopped:
+		return "stopped"
+	default:
+		return ""
+	}
+}
+
+func ParseDesiredState(s string) (DesiredState, error) {
+	switch s {
+	case "running":
+		return DesiredStateRunning, nil
+	case "stopped":
+		return DesiredStateStopped, nil
+	case "":
+		return DesiredStateNone, nil
+	default:
+		return DesiredStateNone, fmt.Errorf("invalid desired state: %q", s)
+	}
+}
+
+// desiredStateOf returns the desired state of a project with status, for projects whose desired state wasn't
+// recorded. Projects that failed to deploy are left as they are.
+func desiredStateOf(status ProjectStatus) DesiredState {
+	switch status {
+	case ProjectStatusRunning:
+		return DesiredStateRunning
+	case ProjectStatusStopped:
+		return DesiredStateStopped
+	default:
+		return DesiredStateNone
+	}
+}
+
+// Reconcile brings a project that is desired running back up when some of its services are down, because their
+// containers exited with an error, were removed or didn't start again after the host rebooted. The last commit
+// deployed successfully is checked out and deployed again, rather than a newer one that failed to deploy, and
+// recorded with the reconcile trigger. The services that were down
+// are returned, none if the project is up or isn't desired running. Projects being deployed aren't checked, and
+// ErrDeploymentInProgress is returned.
+func (s *ProjectService) Reconcile(ctx context.Context, projectID uuid.UUID) ([]string, error) {
+	project, err := s.Get(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("project not found: %w", err)
+	}
+	if project.DesiredState != DesiredStateRunning || project.LastCommit == nil {
+		return nil, nil
+	}
+	if s.deploying(projectID) {
+		return nil, ErrDeploymentInProgress
+	}
+
+	composeProject := NewComposeProject(project, s.config)
+	if composeProject == nil {
+		return nil, fmt.Errorf("failed to create compose project")
+	}
+	down, err := composeProject.DownServices(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check services: %w", err)
+	}
+	if len(down) == 0 {
+		return nil, nil
+	}
+
+	slog.Warn("Project services are down, bringing them back up",
+		"project_id", project.ID,
+		"project_name", project.Name,
+		"commit_hash", project.LastCommitStr(),
+		"services", down)
+	options := DeployOptions{Commit: project.LastCommitStr(), Trigger: DeploymentTriggerReconcile}
+	if err := s.DeployPiping(ctx, projectID, options); err != nil {
+		return down, err
+	}
+	slog.Info("Project brought back up",
+		"project_id", project.ID,
+		"project_name", project.Name,
+		"services", down)
+	return down, nil
+}
@@ -0,0 +1,245 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/oar-cd/oar/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reconcileContainer formats a container in state as listed by docker compose ps --all --format json
+func reconcileContainer(service, state string, exitCode int) string {
+	return `{"Service": "` + service + `", "Name": "test-project-` + service + `-1", "State": "` + state + `", ` +
+		`"ExitCode": ` + strconv.Itoa(exitCode) + `}`
+}
+
+func TestParseDesiredState(t *testing.T) {
+	for _, state := range []DesiredState{DesiredStateNone, DesiredStateRunning, DesiredStateStopped} {
+		parsed, err := ParseDesiredState(state.String())
+		assert.NoError(t, err)
+		assert.Equal(t, state, parsed)
+	}
+
+	_, err := ParseDesiredState("paused")
+	assert.ErrorContains(t, err, "invalid desired state")
+}
+
+func TestProjectMapper_DesiredState(t *testing.T) {
+	tests := []struct {
+		status       string
+		desiredState string
+		expected     DesiredState
+	}{
+		{status: "running", desiredState: "stopped", expected: DesiredStateStopped},
+		{status: "error", desiredState: "running", expected: DesiredStateRunning},
+		// Not recorded yet
+		{status: "running", expected: DesiredStateRunning},
+		{status: "stopped", expected: DesiredStateStopped},
+		{status: "error", expected: DesiredStateNone},
+	}
+
+	mapper := NewProjectMapper(nil)
+	for _, tt := range tests {
+		t.Run(tt.status+" "+tt.desiredState, func(t *testing.T) {
+			project := mapper.ToDomain(&models.ProjectModel{Status: tt.status, DesiredState: tt.desiredState})
+			assert.Equal(t, tt.expected, project.DesiredState)
+			assert.Equal(t, tt.expected.String(), mapper.ToModel(project).DesiredState)
+		})
+	}
+}
+
+func TestComposeProject_DownServices(t *testing.T) {
+	tests := []struct {
+		name       string
+		containers []string
+		expected   []string
+	}{
+		{
+			name:       "up",
+			containers: []string{reconcileContainer("web", "running", 0), reconcileContainer("worker", "running", 0)},
+		},
+		{
+			name:       "job finished",
+			containers: []string{reconcileContainer("web", "running", 0), reconcileContainer("worker", "exited", 0)},
+		},
+		{
+			name:       "removed",
+			containers: []string{reconcileContainer("web", "running", 0)},
+			expected:   []string{"worker"},
+		},
+		{
+			name:       "crashed",
+			containers: []string{reconcileContainer("web", "exited", 1), reconcileContainer("worker", "running", 0)},
+			expected:   []string{"web"},
+		},
+		{
+			name:       "host rebooted",
+			containers: []string{reconcileContainer("web", "exited", 9), reconcileContainer("worker", "created", 0)},
+			expected:   []string{"web", "worker"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			composeProject := createTestComposeProject()
+			composeProject.Config.DockerCommand = setupDriftDocker(t, t.TempDir(), tt.containers...)
+
+			down, err := composeProject.DownServices(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, down)
+		})
+	}
+}
+
+func TestProjectService_Reconcile(t *testing.T) {
+	service, repo, deploymentRepo, gitService, _ := setupMockProjectService(t)
+	// A newer commit that failed to deploy is checked out
+	checkedOut := "bbbb2222"
+	gitService.CheckoutFunc = func(commit string, workingDir string) error {
+		checkedOut = commit
+		return nil
+	}
+	gitService.GetLatestCommitFunc = func(workingDir string) (string, error) {
+		return checkedOut, nil
+	}
+
+	tempDir := t.TempDir()
+	service.config.DockerCommand = setupDriftDocker(t, tempDir, reconcileContainer("web", "running", 0))
+
+	project := createTestProject()
+	project.WorkingDir = tempDir
+	project.Status = ProjectStatusRunning
+	require.NoError(t, os.Mkdir(filepath.Join(tempDir, GitDir), 0o755))
+	repo.projects[project.ID] = project
+
+	// Left as it is until it is deployed
+	down, err := service.Reconcile(context.Background(), project.ID)
+	require.NoError(t, err)
+	assert.Empty(t, down)
+	assert.Empty(t, deploymentRepo.deployments)
+
+	project.DesiredState = DesiredStateRunning
+	down, err = service.Reconcile(context.Background(), project.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"worker"}, down)
+
+	// The last commit deployed successfully is brought back up
+	require.Len(t, deploymentRepo.deployments, 1)
+	for _, deployment := range deploymentRepo.deployments {
+		assert.Equal(t, DeploymentTriggerReconcile, deployment.Trigger)
+		assert.Equal(t, DeploymentStatusCompleted, deployment.Status)
+		assert.Equal(t, "abc123", deployment.CommitHash)
+		assert.False(t, deployment.Pull)
+	}
+	assert.Equal(t, "abc123", checkedOut)
+	assert.Equal(t, ProjectStatusRunning, project.Status)
+	assert.Equal(t, DesiredStateRunning, project.DesiredState)
+
+	// Back up
+	web, worker := reconcileContainer("web", "running", 0), reconcileContainer("worker", "running", 0)
+	writeDriftContainers(t, tempDir, web, worker)
+	down, err = service.Reconcile(context.Background(), project.ID)
+	require.NoError(t, err)
+	assert.Empty(t, down)
+	assert.Len(t, deploymentRepo.deployments, 1)
+
+	// Stopped by hand
+	writeDriftContainers(t, tempDir)
+	project.DesiredState = DesiredStateStopped
+	down, err = service.Reconcile(context.Background(), project.ID)
+	require.NoError(t, err)
+	assert.Empty(t, down)
+	assert.Len(t, deploymentRepo.deployments, 1)
+}
+
+func TestProjectService_Reconcile_CheckoutFails(t *testing.T) {
+	service, repo, deploymentRepo, gitService, _ := setupMockProjectService(t)
+	gitService.CheckoutFunc = func(commit string, workingDir string) error {
+		return errors.New("object not found")
+	}
+	gitService.GetLatestCommitFunc = func(workingDir string) (string, error) {
+		return "bbbb2222", nil
+	}
+
+	tempDir := t.TempDir()
+	service.config.DockerCommand = setupDriftDocker(t, tempDir, reconcileContainer("web", "running", 0))
+
+	project := createTestProject()
+	project.WorkingDir = tempDir
+	project.Status = ProjectStatusRunning
+	project.DesiredState = DesiredStateRunning
+	require.NoError(t, os.Mkdir(filepath.Join(tempDir, GitDir), 0o755))
+	repo.projects[project.ID] = project
+
+	down, err := service.Reconcile(context.Background(), project.ID)
+	assert.ErrorContains(t, err, "failed to check out commit: object not found")
+	assert.Equal(t, []string{"worker"}, down)
+
+	// The failed attempt is recorded, nothing was deployed
+	require.Len(t, deploymentRepo.deployments, 1)
+	for _, deployment := range deploymentRepo.deployments {
+		assert.Equal(t, DeploymentTriggerReconcile, deployment.Trigger)
+		assert.Equal(t, DeploymentStatusFailed, deployment.Status)
+		assert.Equal(t, "abc123", deployment.CommitHash)
+		assert.NotNil(t, deployment.FinishedAt)
+		assert.Contains(t, deployment.Output, "object not found")
+	}
+	assert.Equal(t, ProjectStatusRunning, project.Status)
+}
+
+func TestProjectService_Reconcile_Deploying(t *testing.T) {
+	service, project, locks := setupLockingProjectService(t)
+	tempDir := t.TempDir()
+	service.config.DockerCommand = setupDriftDocker(t, tempDir)
+	project.WorkingDir = tempDir
+	project.DesiredState = DesiredStateRunning
+
+	acquired, err := locks.Acquire(&DeploymentLock{
+		ID:        uuid.New(),
+		ProjectID: project.ID,
+		Holder:    "other",
+		Trigger:   DeploymentTriggerWeb,
+		ExpiresAt: time.Now().Add(time.Minute),
+	}, time.Now())
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	_, err = service.Reconcile(context.Background(), project.ID)
+	assert.ErrorIs(t, err, ErrDeploymentInProgress)
+}
+
+func TestProjectService_DesiredState(t *testing.T) {
+	service, repo, _, gitService, _ := setupMockProjectService(t)
+	gitService.GetLatestCommitFunc = func(workingDir string) (string, error) {
+		return "abc123def456", nil
+	}
+
+	tempDir := t.TempDir()
+	service.config.DockerCommand = setupDriftDocker(t, tempDir)
+	project := createTestProject()
+	project.WorkingDir = tempDir
+	project.DesiredState = DesiredStateStopped
+	require.NoError(t, os.Mkdir(filepath.Join(tempDir, GitDir), 0o755))
+	repo.projects[project.ID] = project
+
+	// Failed deployments leave it as it was
+	dockerCommand := service.config.DockerCommand
+	service.config.DockerCommand = "false"
+	require.Error(t, service.DeployPiping(context.Background(), project.ID, DeployOptions{}))
+	assert.Equal(t, DesiredStateStopped, project.DesiredState)
+
+	service.config.DockerCommand = dockerCommand
+	require.NoError(t, service.DeployPiping(context.Background(), project.ID, DeployOptions{}))
+	assert.Equal(t, DesiredStateRunning, project.DesiredState)
+
+	require.NoError(t, service.Stop(project.ID))
+	assert.Equal(t, DesiredStateStopped, project.DesiredState)
+}
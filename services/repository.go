@@ -3,10 +3,12 @@ package services
 import (
 	"log/slog"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/oar-cd/oar/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ProjectRepository interface {
@@ -14,6 +16,7 @@ type ProjectRepository interface {
 	FindByName(name string) (*Project, error)
 	Create(project *Project) (*Project, error)
 	Update(project *Project) error
+	UpdateDrift(id uuid.UUID, drift []string) error
 	List() ([]*Project, error)
 	Delete(id uuid.UUID) error
 }
@@ -86,6 +89,14 @@ func (r *projectRepository) Update(project *Project) error {
 		Error
 }
 
+// UpdateDrift records the drift of a project, leaving the rest of it as it is
+func (r *projectRepository) UpdateDrift(id uuid.UUID, drift []string) error {
+	return r.db.Model(&models.ProjectModel{}).
+		Where("id = ?", id).
+		Update("drift", serializeFiles(drift)).
+		Error
+}
+
 func (r *projectRepository) Delete(id uuid.UUID) error {
 	err := r.db.Delete(&models.ProjectModel{}, id).Error
 	if err != nil {
@@ -165,6 +176,436 @@ func NewDeploymentRepository(db *gorm.DB) DeploymentRepository {
 	}
 }
 
+// DeploymentLockRepository stores the deployment locks of projects. Every operation is a single statement,
+// so that processes sharing the database never both hold a project's lock.
+type DeploymentLockRepository interface {
+	// Acquire creates lock, taking over the project's lock if it expired by now. It reports whether the
+	// lock was acquired; false means the project's lock is held.
+	Acquire(lock *DeploymentLock, now time.Time) (bool, error)
+	// Renew extends a held lock. It reports false if the lock was taken over after it expired.
+	Renew(id uuid.UUID, expiresAt time.Time) (bool, error)
+	Release(id uuid.UUID) error
+	FindByProjectID(projectID uuid.UUID) (*DeploymentLock, error)
+	// RequestCancel asks the process holding the project's lock to cancel its deployment. It reports false if
+	// the project's lock isn't held by now.
+	RequestCancel(projectID uuid.UUID, now time.Time) (bool, error)
+}
+
+type deploymentLockRepository struct {
+	db     *gorm.DB
+	mapper *DeploymentLockMapper
+}
+
+func (r *deploymentLockRepository) Acquire(lock *DeploymentLock, now time.Time) (bool, error) {
+	model := r.mapper.ToModel(lock)
+	res := r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "project_id"}},
+		DoUpdates: clause.AssignmentColumns(
+			[]string{"id", "holder", "trigger", "actor", "expires_at", "cancel_requested", "created_at", "updated_at"},
+		),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Lte{Column: clause.Column{Table: "deployment_locks", Name: "expires_at"}, Value: now.UTC()},
+		}},
+	}).Create(model)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return false, nil
+	}
+	*lock = *r.mapper.ToDomain(model)
+	return true, nil
+}
+
+func (r *deploymentLockRepository) Renew(id uuid.UUID, expiresAt time.Time) (bool, error) {
+	res := r.db.Model(&models.DeploymentLockModel{}).Where("id = ?", id).Update("expires_at", expiresAt.UTC())
+	return res.RowsAffected > 0, res.Error
+}
+
+func (r *deploymentLockRepository) Release(id uuid.UUID) error {
+	return r.db.Delete(&models.DeploymentLockModel{}, id).Error
+}
+
+func (r *deploymentLockRepository) FindByProjectID(projectID uuid.UUID) (*DeploymentLock, error) {
+	var model models.DeploymentLockModel
+	if err := r.db.Where("project_id = ?", projectID).First(&model).Error; err != nil {
+		return nil, err
+	}
+	return r.mapper.ToDomain(&model), nil
+}
+
+func (r *deploymentLockRepository) RequestCancel(projectID uuid.UUID, now time.Time) (bool, error) {
+	res := r.db.Model(&models.DeploymentLockModel{}).
+		Where("project_id = ? AND expires_at > ?", projectID, now.UTC()).
+		Update("cancel_requested", true)
+	return res.RowsAffected > 0, res.Error
+}
+
+func NewDeploymentLockRepository(db *gorm.DB) DeploymentLockRepository {
+	return &deploymentLockRepository{
+		db:     db,
+		mapper: &DeploymentLockMapper{},
+	}
+}
+
+type UserRepository interface {
+	FindByID(id uuid.UUID) (*User, error)
+	FindByUsername(username string) (*User, error)
+	Create(user *User) error
+	Update(user *User) error
+	List() ([]*User, error)
+	Delete(id uuid.UUID) error
+	Count() (int64, error)
+}
+
+type userRepository struct {
+	db     *gorm.DB
+	mapper *UserMapper
+}
+
+func (r *userRepository) FindByID(id uuid.UUID) (*User, error) {
+	var model models.UserModel
+	if err := r.db.First(&model, id).Error; err != nil {
+		return nil, err
+	}
+	return r.mapper.ToDomain(&model), nil
+}
+
+func (r *userRepository) FindByUsername(username string) (*User, error) {
+	var model models.UserModel
+	if err := r.db.Where("username = ?", username).First(&model).Error; err != nil {
+		return nil, err
+	}
+	return r.mapper.ToDomain(&model), nil
+}
+
+func (r *userRepository) Create(user *User) error {
+	model := r.mapper.ToModel(user)
+	if err := r.db.Create(model).Error; err != nil {
+		slog.Error("Database operation failed",
+			"layer", "repository",
+			"operation", "create_user",
+			"username", user.Username,
+			"error", err)
+		return err // Pass through as-is
+	}
+	// Update the domain object with the timestamps that GORM populated
+	*user = *r.mapper.ToDomain(model)
+	return nil
+}
+
+func (r *userRepository) Update(user *User) error {
+	model := r.mapper.ToModel(user)
+	if err := r.db.Save(model).Error; err != nil {
+		return err
+	}
+	*user = *r.mapper.ToDomain(model)
+	return nil
+}
+
+func (r *userRepository) List() ([]*User, error) {
+	var models []models.UserModel
+	if err := r.db.Order("username").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	users := make([]*User, len(models))
+	for i, model := range models {
+		users[i] = r.mapper.ToDomain(&model)
+	}
+	return users, nil
+}
+
+func (r *userRepository) Delete(id uuid.UUID) error {
+	err := r.db.Delete(&models.UserModel{}, id).Error
+	if err != nil {
+		slog.Error("Database operation failed",
+			"layer", "repository",
+			"operation", "delete_user",
+			"user_id", id,
+			"error", err)
+	}
+	return err // Pass through as-is
+}
+
+func (r *userRepository) Count() (int64, error) {
+	var count int64
+	err := r.db.Model(&models.UserModel{}).Count(&count).Error
+	return count, err
+}
+
+func NewUserRepository(db *gorm.DB) UserRepository {
+	return &userRepository{
+		db:     db,
+		mapper: &UserMapper{},
+	}
+}
+
+type SessionRepository interface {
+	FindByTokenHash(tokenHash string) (*Session, error)
+	Create(session *Session) error
+	Delete(id uuid.UUID) error
+	DeleteByUserID(userID uuid.UUID) error
+	DeleteExpired(now time.Time) (int64, error)
+}
+
+type sessionRepository struct {
+	db     *gorm.DB
+	mapper *SessionMapper
+}
+
+func (r *sessionRepository) FindByTokenHash(tokenHash string) (*Session, error) {
+	var model models.SessionModel
+	if err := r.db.Where("token_hash = ?", tokenHash).First(&model).Error; err != nil {
+		return nil, err
+	}
+	return r.mapper.ToDomain(&model), nil
+}
+
+func (r *sessionRepository) Create(session *Session) error {
+	model := r.mapper.ToModel(session)
+	if err := r.db.Create(model).Error; err != nil {
+		return err
+	}
+	*session = *r.mapper.ToDomain(model)
+	return nil
+}
+
+func (r *sessionRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.SessionModel{}, id).Error
+}
+
+func (r *sessionRepository) DeleteByUserID(userID uuid.UUID) error {
+	return r.db.Where("user_id = ?", userID).Delete(&models.SessionModel{}).Error
+}
+
+func (r *sessionRepository) DeleteExpired(now time.Time) (int64, error) {
+	res := r.db.Where("expires_at <= ?", now).Delete(&models.SessionModel{})
+	return res.RowsAffected, res.Error
+}
+
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepository{
+		db:     db,
+		mapper: &SessionMapper{},
+	}
+}
+
+type RoleBindingRepository interface {
+	Find(userID uuid.UUID, projectID *uuid.UUID) (*RoleBinding, error)
+	ListByUserID(userID uuid.UUID) ([]*RoleBinding, error)
+	Save(binding *RoleBinding) error
+	Delete(id uuid.UUID) error
+}
+
+type roleBindingRepository struct {
+	db     *gorm.DB
+	mapper *RoleBindingMapper
+}
+
+func (r *roleBindingRepository) Find(userID uuid.UUID, projectID *uuid.UUID) (*RoleBinding, error) {
+	query := r.db.Where("user_id = ?", userID)
+	if projectID == nil {
+		query = query.Where("project_id IS NULL")
+	} else {
+		query = query.Where("project_id = ?", *projectID)
+	}
+
+	var model models.RoleBindingModel
+	if err := query.First(&model).Error; err != nil {
+		return nil, err
+	}
+	return r.mapper.ToDomain(&model), nil
+}
+
+func (r *roleBindingRepository) ListByUserID(userID uuid.UUID) ([]*RoleBinding, error) {
+	var models []models.RoleBindingModel
+	if err := r.db.Where("user_id = ?", userID).Order("created_at").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	bindings := make([]*RoleBinding, len(models))
+	for i, model := range models {
+		bindings[i] = r.mapper.ToDomain(&model)
+	}
+	return bindings, nil
+}
+
+func (r *roleBindingRepository) Save(binding *RoleBinding) error {
+	model := r.mapper.ToModel(binding)
+	if err := r.db.Save(model).Error; err != nil {
+		slog.Error("Database operation failed",
+			"layer", "repository",
+			"operation", "save_role_binding",
+			"user_id", binding.UserID,
+			"project_id", binding.ProjectID,
+			"error", err)
+		return err // Pass through as-is
+	}
+	*binding = *r.mapper.ToDomain(model)
+	return nil
+}
+
+func (r *roleBindingRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.RoleBindingModel{}, id).Error
+}
+
+func NewRoleBindingRepository(db *gorm.DB) RoleBindingRepository {
+	return &roleBindingRepository{
+		db:     db,
+		mapper: &RoleBindingMapper{},
+	}
+}
+
+type NotifierRepository interface {
+	FindByID(id uuid.UUID) (*Notifier, error)
+	ListByProjectID(projectID uuid.UUID) ([]*Notifier, error)
+	Create(notifier *Notifier) error
+	Delete(id uuid.UUID) error
+}
+
+type notifierRepository struct {
+	db     *gorm.DB
+	mapper *NotifierMapper
+}
+
+func (r *notifierRepository) FindByID(id uuid.UUID) (*Notifier, error) {
+	var model models.NotifierModel
+	if err := r.db.First(&model, id).Error; err != nil {
+		return nil, err
+	}
+	return r.mapper.ToDomain(&model), nil
+}
+
+func (r *notifierRepository) ListByProjectID(projectID uuid.UUID) ([]*Notifier, error) {
+	var models []models.NotifierModel
+	if err := r.db.Where("project_id = ?", projectID).Order("created_at").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	notifiers := make([]*Notifier, len(models))
+	for i, model := range models {
+		notifiers[i] = r.mapper.ToDomain(&model)
+	}
+	return notifiers, nil
+}
+
+func (r *notifierRepository) Create(notifier *Notifier) error {
+	model, err := r.mapper.ToModel(notifier)
+	if err != nil {
+		return err
+	}
+	if err := r.db.Create(model).Error; err != nil {
+		slog.Error("Database operation failed",
+			"layer", "repository",
+			"operation", "create_notifier",
+			"notifier_id", notifier.ID,
+			"project_id", notifier.ProjectID,
+			"error", err)
+		return err // Pass through as-is
+	}
+	*notifier = *r.mapper.ToDomain(model)
+	return nil
+}
+
+func (r *notifierRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.NotifierModel{}, id).Error
+}
+
+func NewNotifierRepository(db *gorm.DB, encryption *EncryptionService) NotifierRepository {
+	return &notifierRepository{
+		db:     db,
+		mapper: &NotifierMapper{encryption: encryption},
+	}
+}
+
+type CatalogRepository interface {
+	FindByID(id uuid.UUID) (*Catalog, error)
+	FindByName(name string) (*Catalog, error)
+	List() ([]*Catalog, error)
+	Create(catalog *Catalog) error
+	Update(catalog *Catalog) error
+	Delete(id uuid.UUID) error
+}
+
+type catalogRepository struct {
+	db     *gorm.DB
+	mapper *CatalogMapper
+}
+
+func (r *catalogRepository) FindByID(id uuid.UUID) (*Catalog, error) {
+	var model models.CatalogModel
+	if err := r.db.First(&model, id).Error; err != nil {
+		return nil, err
+	}
+	return r.mapper.ToDomain(&model), nil
+}
+
+func (r *catalogRepository) FindByName(name string) (*Catalog, error) {
+	var model models.CatalogModel
+	if err := r.db.Where("name = ?", name).First(&model).Error; err != nil {
+		return nil, err
+	}
+	return r.mapper.ToDomain(&model), nil
+}
+
+func (r *catalogRepository) List() ([]*Catalog, error) {
+	var models []models.CatalogModel
+	if err := r.db.Order("name").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	catalogs := make([]*Catalog, len(models))
+	for i, model := range models {
+		catalogs[i] = r.mapper.ToDomain(&model)
+	}
+	return catalogs, nil
+}
+
+func (r *catalogRepository) Create(catalog *Catalog) error {
+	model, err := r.mapper.ToModel(catalog)
+	if err != nil {
+		return err
+	}
+	if err := r.db.Create(model).Error; err != nil {
+		slog.Error("Database operation failed",
+			"layer", "repository",
+			"operation", "create_catalog",
+			"catalog_id", catalog.ID,
+			"catalog_name", catalog.Name,
+			"error", err)
+		return err // Pass through as-is
+	}
+	*catalog = *r.mapper.ToDomain(model)
+	return nil
+}
+
+func (r *catalogRepository) Update(catalog *Catalog) error {
+	model, err := r.mapper.ToModel(catalog)
+	if err != nil {
+		return err
+	}
+
+	// Update all fields except CreatedAt, including empty ones
+	return r.db.Model(&models.CatalogModel{}).
+		Where("id = ?", model.ID).
+		Select("*").
+		Omit("created_at").
+		Updates(model).
+		Error
+}
+
+func (r *catalogRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.CatalogModel{}, id).Error
+}
+
+func NewCatalogRepository(db *gorm.DB, encryption *EncryptionService) CatalogRepository {
+	return &catalogRepository{
+		db:     db,
+		mapper: &CatalogMapper{encryption: encryption},
+	}
+}
+
 // Helper functions
 func parseFiles(s string) []string {
 	if s == "" {
@@ -284,6 +284,38 @@ func TestDeploymentRepository_FindByID_Success(t *testing.T) {
 	assert.Equal(t, originalDeployment.CommitHash, foundDeployment.CommitHash)
 }
 
+func TestDeploymentRepository_Metadata(t *testing.T) {
+	db := setupTestDB(t)
+	deploymentRepo := NewDeploymentRepository(db)
+	projectRepo := NewProjectRepository(db, setupTestEncryption(t))
+
+	project := createTestProject()
+	project.Name = "deployment-metadata-parent"
+	createdProject, err := projectRepo.Create(project)
+	require.NoError(t, err)
+
+	startedAt := time.Now().Add(-time.Minute).UTC()
+	finishedAt := time.Now().UTC()
+	deployment := createTestDeployment(createdProject.ID)
+	deployment.PreviousCommit = "def456"
+	deployment.Pull = true
+	deployment.Trigger = DeploymentTriggerWebhook
+	deployment.Actor = "octocat"
+	deployment.StartedAt = startedAt
+	deployment.FinishedAt = &finishedAt
+	require.NoError(t, deploymentRepo.Create(deployment))
+
+	found, err := deploymentRepo.FindByID(deployment.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "def456", found.PreviousCommit)
+	assert.True(t, found.Pull)
+	assert.Equal(t, DeploymentTriggerWebhook, found.Trigger)
+	assert.Equal(t, "octocat", found.Actor)
+	assert.True(t, startedAt.Equal(found.StartedAt))
+	require.NotNil(t, found.FinishedAt)
+	assert.True(t, finishedAt.Equal(*found.FinishedAt))
+}
+
 func TestDeploymentRepository_FindByID_NotFound(t *testing.T) {
 	db := setupTestDB(t)
 	repo := NewDeploymentRepository(db)
@@ -489,6 +521,9 @@ func TestDeploymentRepository_StatusMapping(t *testing.T) {
 		{"completed", DeploymentStatusCompleted},
 		{"failed", DeploymentStatusFailed},
 		{"unknown", DeploymentStatusUnknown},
+		{"cancelled", DeploymentStatusCancelled},
+		{"pending", DeploymentStatusPending},
+		{"rejected", DeploymentStatusRejected},
 	}
 
 	for _, test := range tests {
@@ -530,6 +565,164 @@ func TestProjectRepository_NullCommitHandling(t *testing.T) {
 	assert.Nil(t, retrievedProject.LastCommit)
 }
 
+func TestProjectRepository_RolledBackCommitCleared(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewProjectRepository(db, setupTestEncryption(t))
+
+	project := createTestProject()
+	project.RolledBackCommit = stringPtr("rolled-back-commit")
+	createdProject, err := repo.Create(project)
+	require.NoError(t, err)
+	assert.Equal(t, "rolled-back-commit", createdProject.RolledBackCommitStr())
+
+	// Clearing the rolled back commit must be persisted
+	createdProject.RolledBackCommit = nil
+	require.NoError(t, repo.Update(createdProject))
+
+	retrievedProject, err := repo.FindByID(createdProject.ID)
+	require.NoError(t, err)
+	assert.Nil(t, retrievedProject.RolledBackCommit)
+}
+
+func TestProjectRepository_TimeoutsMapping(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewProjectRepository(db, setupTestEncryption(t))
+
+	project := createTestProject()
+	project.Timeouts = ComposeTimeouts{
+		Pull:   30 * time.Minute,
+		Up:     20 * time.Minute,
+		Wait:   90 * time.Second,
+		Health: time.Minute,
+	}
+	createdProject, err := repo.Create(project)
+	require.NoError(t, err)
+
+	retrievedProject, err := repo.FindByID(createdProject.ID)
+	require.NoError(t, err)
+	assert.Equal(t, project.Timeouts, retrievedProject.Timeouts)
+
+	// Going back to the defaults must be persisted
+	retrievedProject.Timeouts = ComposeTimeouts{}
+	require.NoError(t, repo.Update(retrievedProject))
+
+	retrievedProject, err = repo.FindByID(createdProject.ID)
+	require.NoError(t, err)
+	assert.Equal(t, ComposeTimeouts{}, retrievedProject.Timeouts)
+}
+
+func TestProjectRepository_HealthChecksMapping(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewProjectRepository(db, setupTestEncryption(t))
+
+	project := createTestProject()
+	checks, err := ParseHealthChecks([]string{"http http://localhost:8080/health 200 ok", "command db pg_isready"})
+	require.NoError(t, err)
+	project.HealthChecks = checks
+	createdProject, err := repo.Create(project)
+	require.NoError(t, err)
+
+	retrievedProject, err := repo.FindByID(createdProject.ID)
+	require.NoError(t, err)
+	assert.Equal(t, checks, retrievedProject.HealthChecks)
+
+	retrievedProject.HealthChecks = nil
+	require.NoError(t, repo.Update(retrievedProject))
+
+	retrievedProject, err = repo.FindByID(createdProject.ID)
+	require.NoError(t, err)
+	assert.Empty(t, retrievedProject.HealthChecks)
+}
+
+func TestProjectRepository_HooksMapping(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewProjectRepository(db, setupTestEncryption(t))
+
+	project := createTestProject()
+	preDeployHooks, err := ParseHooks([]string{"run app ./manage.py migrate", "script ./scripts/backup.sh"})
+	require.NoError(t, err)
+	postDeployHooks, err := ParseHooks([]string{"exec cache redis-cli FLUSHALL"})
+	require.NoError(t, err)
+	project.PreDeployHooks = preDeployHooks
+	project.PostDeployHooks = postDeployHooks
+	createdProject, err := repo.Create(project)
+	require.NoError(t, err)
+
+	retrievedProject, err := repo.FindByID(createdProject.ID)
+	require.NoError(t, err)
+	assert.Equal(t, preDeployHooks, retrievedProject.PreDeployHooks)
+	assert.Equal(t, postDeployHooks, retrievedProject.PostDeployHooks)
+
+	retrievedProject.PreDeployHooks = nil
+	require.NoError(t, repo.Update(retrievedProject))
+
+	retrievedProject, err = repo.FindByID(createdProject.ID)
+	require.NoError(t, err)
+	assert.Empty(t, retrievedProject.PreDeployHooks)
+	assert.Equal(t, postDeployHooks, retrievedProject.PostDeployHooks)
+}
+
+func TestDeploymentRepository_HealthChecksMapping(t *testing.T) {
+	db := setupTestDB(t)
+	project, err := NewProjectRepository(db, setupTestEncryption(t)).Create(createTestProject())
+	require.NoError(t, err)
+	repo := NewDeploymentRepository(db)
+
+	deployment := createTestDeployment(project.ID)
+	deployment.HealthChecks = []HealthCheckResult{
+		{Check: "tcp db:5432", Passed: true, Message: "connected", Attempts: 3},
+		{Check: "http http://localhost/health", Message: "HTTP 503, expected 2xx", Attempts: 1},
+	}
+	require.NoError(t, repo.Create(deployment))
+
+	retrieved, err := repo.FindByID(deployment.ID)
+	require.NoError(t, err)
+	assert.Equal(t, deployment.HealthChecks, retrieved.HealthChecks)
+}
+
+func TestProjectRepository_GitRefMapping(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewProjectRepository(db, setupTestEncryption(t))
+
+	project := createTestProject()
+	project.GitRefType = GitRefTypeTag
+	project.GitRef = "~1.4"
+	createdProject, err := repo.Create(project)
+	require.NoError(t, err)
+
+	retrievedProject, err := repo.FindByID(createdProject.ID)
+	require.NoError(t, err)
+	assert.Equal(t, GitRefTypeTag, retrievedProject.GitRefType)
+	assert.Equal(t, "~1.4", retrievedProject.GitRef)
+
+	// Switching back to branch tracking must be persisted
+	retrievedProject.GitRefType = GitRefTypeBranch
+	retrievedProject.GitRef = ""
+	require.NoError(t, repo.Update(retrievedProject))
+
+	retrievedProject, err = repo.FindByID(createdProject.ID)
+	require.NoError(t, err)
+	assert.Equal(t, GitRefTypeBranch, retrievedProject.GitRefType)
+	assert.Empty(t, retrievedProject.GitRef)
+}
+
+func TestDeploymentRepository_RollbackFlag(t *testing.T) {
+	db := setupTestDB(t)
+	projectRepo := NewProjectRepository(db, setupTestEncryption(t))
+	deploymentRepo := NewDeploymentRepository(db)
+
+	project, err := projectRepo.Create(createTestProject())
+	require.NoError(t, err)
+
+	deployment := createTestDeployment(project.ID)
+	deployment.Rollback = true
+	require.NoError(t, deploymentRepo.Create(deployment))
+
+	retrieved, err := deploymentRepo.FindByID(deployment.ID)
+	require.NoError(t, err)
+	assert.True(t, retrieved.Rollback)
+}
+
 func TestProjectRepository_InvalidStatusHandling(t *testing.T) {
 	db := setupTestDB(t)
 
@@ -660,3 +853,304 @@ func TestNewDeploymentRepository(t *testing.T) {
 	assert.NotNil(t, repo)
 	assert.Implements(t, (*DeploymentRepository)(nil), repo)
 }
+
+// Tests for UserRepository
+func TestUserRepository_CreateAndFind(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepository(db)
+
+	user := &User{ID: uuid.New(), Username: "alice", PasswordHash: "hash"}
+	require.NoError(t, repo.Create(user))
+	assert.NotZero(t, user.CreatedAt)
+
+	byName, err := repo.FindByUsername("alice")
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, byName.ID)
+
+	byID, err := repo.FindByID(user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", byID.Username)
+
+	count, err := repo.Count()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestUserRepository_List_OrderedByUsername(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepository(db)
+
+	for _, name := range []string{"carol", "alice", "bob"} {
+		require.NoError(t, repo.Create(&User{ID: uuid.New(), Username: name, PasswordHash: "hash"}))
+	}
+
+	users, err := repo.List()
+	require.NoError(t, err)
+	require.Len(t, users, 3)
+	assert.Equal(t, "alice", users[0].Username)
+	assert.Equal(t, "bob", users[1].Username)
+	assert.Equal(t, "carol", users[2].Username)
+}
+
+// Tests for SessionRepository
+func TestSessionRepository_DeleteExpired(t *testing.T) {
+	db := setupTestDB(t)
+	userRepo := NewUserRepository(db)
+	repo := NewSessionRepository(db)
+
+	user := &User{ID: uuid.New(), Username: "alice", PasswordHash: "hash"}
+	require.NoError(t, userRepo.Create(user))
+
+	now := time.Now()
+	expired := &Session{ID: uuid.New(), UserID: user.ID, TokenHash: "expired", ExpiresAt: now.Add(-time.Minute)}
+	active := &Session{ID: uuid.New(), UserID: user.ID, TokenHash: "active", ExpiresAt: now.Add(time.Hour)}
+	require.NoError(t, repo.Create(expired))
+	require.NoError(t, repo.Create(active))
+
+	deleted, err := repo.DeleteExpired(now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	_, err = repo.FindByTokenHash("expired")
+	assert.True(t, IsNotFound(err))
+
+	found, err := repo.FindByTokenHash("active")
+	require.NoError(t, err)
+	assert.Equal(t, active.ID, found.ID)
+}
+
+// Tests for DeploymentLockRepository
+func TestDeploymentLockRepository_Acquire(t *testing.T) {
+	db := setupTestDB(t)
+	project, err := NewProjectRepository(db, setupTestEncryption(t)).Create(createTestProject())
+	require.NoError(t, err)
+	repo := NewDeploymentLockRepository(db)
+
+	now := time.Now()
+	newLock := func(holder string, expiresAt time.Time) *DeploymentLock {
+		return &DeploymentLock{
+			ID:        uuid.New(),
+			ProjectID: project.ID,
+			Holder:    holder,
+			Trigger:   DeploymentTriggerWatcher,
+			ExpiresAt: expiresAt,
+		}
+	}
+
+	first := newLock("web", now.Add(time.Minute))
+	acquired, err := repo.Acquire(first, now)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+
+	// Held until it expires
+	acquired, err = repo.Acquire(newLock("cli", now.Add(2*time.Minute)), now)
+	require.NoError(t, err)
+	assert.False(t, acquired)
+
+	found, err := repo.FindByProjectID(project.ID)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, found.ID)
+	assert.Equal(t, "web", found.Holder)
+	assert.Equal(t, DeploymentTriggerWatcher, found.Trigger)
+
+	// Taken over once it expired, after which the first holder can no longer renew it
+	later := now.Add(time.Minute)
+	second := newLock("cli", later.Add(time.Minute))
+	acquired, err = repo.Acquire(second, later)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+
+	renewed, err := repo.Renew(first.ID, later.Add(time.Minute))
+	require.NoError(t, err)
+	assert.False(t, renewed)
+
+	renewed, err = repo.Renew(second.ID, later.Add(2*time.Minute))
+	require.NoError(t, err)
+	assert.True(t, renewed)
+
+	require.NoError(t, repo.Release(second.ID))
+	_, err = repo.FindByProjectID(project.ID)
+	assert.True(t, IsNotFound(err))
+
+	acquired, err = repo.Acquire(newLock("watcher", later.Add(time.Minute)), later)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+}
+
+func TestDeploymentLockRepository_Acquire_TimeZones(t *testing.T) {
+	db := setupTestDB(t)
+	project, err := NewProjectRepository(db, setupTestEncryption(t)).Create(createTestProject())
+	require.NoError(t, err)
+	repo := NewDeploymentLockRepository(db)
+
+	// A lock taken by a process in UTC is still held for a process east of UTC
+	east := time.FixedZone("UTC+9", 9*60*60)
+	now := time.Now()
+	lock := &DeploymentLock{ID: uuid.New(), ProjectID: project.ID, Holder: "web", ExpiresAt: now.Add(time.Minute).UTC()}
+	acquired, err := repo.Acquire(lock, now.UTC())
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	acquired, err = repo.Acquire(
+		&DeploymentLock{ID: uuid.New(), ProjectID: project.ID, Holder: "cli", ExpiresAt: now.Add(time.Minute).In(east)},
+		now.In(east),
+	)
+	require.NoError(t, err)
+	assert.False(t, acquired)
+}
+
+func TestDeploymentLockRepository_RequestCancel(t *testing.T) {
+	db := setupTestDB(t)
+	project, err := NewProjectRepository(db, setupTestEncryption(t)).Create(createTestProject())
+	require.NoError(t, err)
+	repo := NewDeploymentLockRepository(db)
+
+	now := time.Now()
+	requested, err := repo.RequestCancel(project.ID, now)
+	require.NoError(t, err)
+	assert.False(t, requested)
+
+	lock := &DeploymentLock{ID: uuid.New(), ProjectID: project.ID, Holder: "watcher", ExpiresAt: now.Add(time.Minute)}
+	acquired, err := repo.Acquire(lock, now)
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	requested, err = repo.RequestCancel(project.ID, now)
+	require.NoError(t, err)
+	assert.True(t, requested)
+	found, err := repo.FindByProjectID(project.ID)
+	require.NoError(t, err)
+	assert.True(t, found.CancelRequested)
+
+	// Expired locks aren't held, and the request isn't inherited by the lock that takes over
+	later := now.Add(time.Minute)
+	requested, err = repo.RequestCancel(project.ID, later)
+	require.NoError(t, err)
+	assert.False(t, requested)
+
+	acquired, err = repo.Acquire(
+		&DeploymentLock{ID: uuid.New(), ProjectID: project.ID, Holder: "cli", ExpiresAt: later.Add(time.Minute)},
+		later,
+	)
+	require.NoError(t, err)
+	require.True(t, acquired)
+	found, err = repo.FindByProjectID(project.ID)
+	require.NoError(t, err)
+	assert.False(t, found.CancelRequested)
+}
+
+// Tests for NotifierRepository
+func TestNotifierRepository_ConfigEncrypted(t *testing.T) {
+	db := setupTestDB(t)
+	encryption := setupTestEncryption(t)
+	project, err := NewProjectRepository(db, encryption).Create(createTestProject())
+	require.NoError(t, err)
+	repo := NewNotifierRepository(db, encryption)
+
+	notifier := &Notifier{
+		ID:        uuid.New(),
+		ProjectID: project.ID,
+		Type:      NotifierTypeGotify,
+		Events:    []NotificationEvent{NotificationEventDeploymentFailed, NotificationEventDriftDetected},
+		Config:    NotifierConfig{URL: "https://gotify.example.com", Token: "app-token"},
+	}
+	require.NoError(t, repo.Create(notifier))
+
+	var model models.NotifierModel
+	require.NoError(t, db.First(&model, notifier.ID).Error)
+	assert.NotContains(t, model.Config, "app-token")
+
+	found, err := repo.FindByID(notifier.ID)
+	require.NoError(t, err)
+	assert.Equal(t, NotifierTypeGotify, found.Type)
+	assert.Equal(t, notifier.Events, found.Events)
+	assert.Equal(t, notifier.Config, found.Config)
+}
+
+func TestNotifierRepository_ListByProjectID(t *testing.T) {
+	db := setupTestDB(t)
+	encryption := setupTestEncryption(t)
+	projectRepo := NewProjectRepository(db, encryption)
+	project, err := projectRepo.Create(createTestProject())
+	require.NoError(t, err)
+	other, err := projectRepo.Create(createTestProjectWithOptions(ProjectOptions{Name: "other"}))
+	require.NoError(t, err)
+	repo := NewNotifierRepository(db, encryption)
+
+	first := &Notifier{ID: uuid.New(), ProjectID: project.ID, Type: NotifierTypeNtfy,
+		Config: NotifierConfig{URL: "https://ntfy.sh/a"}}
+	second := &Notifier{ID: uuid.New(), ProjectID: project.ID, Type: NotifierTypeSlack,
+		Config: NotifierConfig{URL: "https://hooks.example.com/b"}}
+	require.NoError(t, repo.Create(first))
+	require.NoError(t, repo.Create(second))
+	require.NoError(t, repo.Create(&Notifier{ID: uuid.New(), ProjectID: other.ID, Type: NotifierTypeNtfy,
+		Config: NotifierConfig{URL: "https://ntfy.sh/c"}}))
+
+	notifiers, err := repo.ListByProjectID(project.ID)
+	require.NoError(t, err)
+	require.Len(t, notifiers, 2)
+	assert.Equal(t, first.ID, notifiers[0].ID)
+	assert.Empty(t, notifiers[0].Events)
+	assert.Equal(t, second.ID, notifiers[1].ID)
+
+	require.NoError(t, repo.Delete(first.ID))
+	_, err = repo.FindByID(first.ID)
+	assert.True(t, IsNotFound(err))
+}
+
+func TestCatalogRepository_SecretsEncrypted(t *testing.T) {
+	db := setupTestDB(t)
+	encryption := setupTestEncryption(t)
+	repo := NewCatalogRepository(db, encryption)
+
+	catalog := &Catalog{
+		ID:         uuid.New(),
+		Name:       "platform",
+		GitURL:     "https://github.com/org/platform.git",
+		GitBranch:  "main",
+		GitAuth:    &GitAuthConfig{HTTPAuth: &GitHTTPAuthConfig{Username: "oar", Password: "git-token"}},
+		WorkingDir: "/tmp/catalogs/platform",
+		Secrets:    []string{"db-password=s3cret", "api-key=abc=def"},
+	}
+	require.NoError(t, repo.Create(catalog))
+
+	var model models.CatalogModel
+	require.NoError(t, db.First(&model, catalog.ID).Error)
+	assert.NotContains(t, model.Secrets, "s3cret")
+	require.NotNil(t, model.GitAuthCredentials)
+	assert.NotContains(t, *model.GitAuthCredentials, "git-token")
+
+	found, err := repo.FindByName("platform")
+	require.NoError(t, err)
+	assert.Equal(t, catalog.Secrets, found.Secrets)
+	assert.Equal(t, catalog.GitAuth, found.GitAuth)
+
+	// Clearing the secrets is saved too
+	found.Secrets = nil
+	commit := "abc123"
+	found.LastCommit = &commit
+	require.NoError(t, repo.Update(found))
+	found, err = repo.FindByID(catalog.ID)
+	require.NoError(t, err)
+	assert.Empty(t, found.Secrets)
+	assert.Equal(t, "abc123", found.LastCommitStr())
+}
+
+func TestProjectRepository_CatalogIDMapping(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewProjectRepository(db, setupTestEncryption(t))
+
+	catalogID := uuid.New()
+	project := createTestProject()
+	project.CatalogID = &catalogID
+	created, err := repo.Create(project)
+	require.NoError(t, err)
+	require.NotNil(t, created.CatalogID)
+	assert.Equal(t, catalogID, *created.CatalogID)
+
+	created.CatalogID = nil
+	require.NoError(t, repo.Update(created))
+	found, err := repo.FindByID(project.ID)
+	require.NoError(t, err)
+	assert.Nil(t, found.CatalogID)
+}
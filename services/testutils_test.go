@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"testing"
 	"time"
 
@@ -216,12 +217,13 @@ func setupProjectService(t *testing.T) (*ProjectService, string) {
 	// Create repositories (not mocks for integration testing)
 	projectRepo := NewProjectRepository(database, encryption)
 	deploymentRepo := NewDeploymentRepository(database)
+	lockRepo := NewDeploymentLockRepository(database)
 
 	// Create Git service (not mock for integration testing)
 	gitService := NewGitService(config)
 
 	// Create ProjectService with real dependencies
-	service := NewProjectService(projectRepo, deploymentRepo, gitService, config)
+	service := NewProjectService(projectRepo, deploymentRepo, lockRepo, gitService, nil, config)
 
 	return service, tempDir
 }
@@ -290,6 +292,16 @@ func (m *MockProjectRepository) Update(project *Project) error {
 	return nil
 }
 
+func (m *MockProjectRepository) UpdateDrift(id uuid.UUID, drift []string) error {
+	project, exists := m.projects[id]
+	if !exists {
+		return errors.New("project not found")
+	}
+
+	project.Drift = drift
+	return nil
+}
+
 func (m *MockProjectRepository) Delete(id uuid.UUID) error {
 	if _, exists := m.projects[id]; !exists {
 		return errors.New("project not found")
@@ -342,5 +354,9 @@ func (m *MockDeploymentRepository) ListByProjectID(projectID uuid.UUID) ([]*Depl
 			deployments = append(deployments, deployment)
 		}
 	}
+	// Newest first, like the GORM repository
+	sort.Slice(deployments, func(i, j int) bool {
+		return deployments[i].CreatedAt.After(deployments[j].CreatedAt)
+	})
 	return deployments, nil
 }
@@ -0,0 +1,336 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// VolumesDir is the directory of the backup directory that holds volume backups, one directory per project
+const VolumesDir = "volumes"
+
+// volumeArchiveExt is the extension of the archive of each volume in a volume backup
+const volumeArchiveExt = ".tar.gz"
+
+// ErrVolumeBackupNotFound is returned when restoring a volume backup that doesn't exist
+var ErrVolumeBackupNotFound = errors.New("volume backup not found")
+
+// ErrNoVolumes is returned when backing up a project that has no named volumes yet
+var ErrNoVolumes = errors.New("no volumes to back up")
+
+// ErrProjectRunning is returned when restoring the volumes of a project that is running
+var ErrProjectRunning = errors.New("project is running")
+
+// VolumeBackup is a backup of the named volumes of a project, a gzipped tarball per volume
+type VolumeBackup struct {
+	Name      string // Named after the time it was taken, e.g. 20250102T150405.000Z
+	ProjectID uuid.UUID
+	Path      string   // Directory holding the archives
+	Volumes   []string // Keys of the volumes in the Compose files
+	Size      int64
+	CreatedAt time.Time
+}
+
+// VolumeBackupService backs up and restores the named volumes of projects with helper containers
+type VolumeBackupService struct {
+	projects ProjectManager
+	config   *Config
+}
+
+// Ensure VolumeBackupService implements VolumeBackupManager
+var _ VolumeBackupManager = (*VolumeBackupService)(nil)
+
+// List returns the volume backups of a project, newest first
+func (s *VolumeBackupService) List(projectID uuid.UUID) ([]*VolumeBackup, error) {
+	return listVolumeBackups(s.dir(projectID), projectID)
+}
+
+// Create backs up the named volumes of a project that exist and removes the backups beyond the retention. Volumes
+// are archived while the project runs, so stop it first for a consistent backup of a database.
+func (s *VolumeBackupService) Create(ctx context.Context, projectID uuid.UUID) (*VolumeBackup, error) {
+	project, err := s.projects.Get(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("project not found: %w", err)
+	}
+	composeProject := NewComposeProject(project, s.config)
+	if composeProject == nil {
+		return nil, fmt.Errorf("failed to create compose project")
+	}
+	return backupVolumes(ctx, composeProject, project.ID, s.dir(project.ID), s.config.VolumeBackupRetention)
+}
+
+// Restore replaces the contents of the volumes of a project with those of the named backup. The project must be
+// stopped, so that no container writes to the volumes while they are restored, and it holds the deployment lock of
+// the project meanwhile, so that no deployment starts it. The trigger and actor of options are recorded with the lock.
+func (s *VolumeBackupService) Restore(
+	ctx context.Context,
+	projectID uuid.UUID,
+	name string,
+	options DeployOptions,
+) error {
+	unlock, err := s.projects.LockProject(projectID, options)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	project, err := s.projects.Get(projectID)
+	if err != nil {
+		return fmt.Errorf("project not found: %w", err)
+	}
+	if project.Status == ProjectStatusRunning {
+		return fmt.Errorf("%w, stop it before restoring its volumes", ErrProjectRunning)
+	}
+
+	backups, err := s.List(projectID)
+	if err != nil {
+		return err
+	}
+	index := slices.IndexFunc(backups, func(backup *VolumeBackup) bool { return backup.Name == name })
+	if index < 0 {
+		return fmt.Errorf("%w: %s", ErrVolumeBackupNotFound, name)
+	}
+	backup := backups[index]
+
+	composeProject := NewComposeProject(project, s.config)
+	if composeProject == nil {
+		return fmt.Errorf("failed to create compose project")
+	}
+	volumes, err := composeProject.Volumes(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Check that every volume is still declared before touching any of them
+	names := make(map[string]string, len(volumes))
+	for _, volume := range volumes {
+		names[volume.Key] = volume.Name
+	}
+	for _, key := range backup.Volumes {
+		if _, ok := names[key]; !ok {
+			return fmt.Errorf("volume %s of backup %s is no longer declared by the project", key, name)
+		}
+	}
+
+	for _, key := range backup.Volumes {
+		archive := filepath.Join(backup.Path, key+volumeArchiveExt)
+		if err := restoreVolume(ctx, composeProject, archive, names[key]); err != nil {
+			return fmt.Errorf("failed to restore volume %s: %w", key, err)
+		}
+	}
+
+	slog.Info("Project volumes restored",
+		"project_id", project.ID,
+		"project_name", project.Name,
+		"backup", name,
+		"volumes", backup.Volumes)
+	return nil
+}
+
+// dir returns the directory holding the volume backups of a project
+func (s *VolumeBackupService) dir(projectID uuid.UUID) string {
+	return volumeBackupDir(s.config, projectID)
+}
+
+// backupVolumesBeforeDeploy backs up the named volumes of a project about to be deployed, so that a deployment
+// that breaks their data, such as with a failed migration, can be undone. A project whose volumes don't exist yet
+// has nothing to back up.
+func (s *ProjectService) backupVolumesBeforeDeploy(
+	ctx context.Context,
+	project *Project,
+	composeProject ComposeProjectInterface,
+	output *deploymentOutput,
+) error {
+	output.send("Backing up volumes...", "info", "oar")
+
+	dir := volumeBackupDir(s.config, project.ID)
+	backup, err := backupVolumes(ctx, composeProject, project.ID, dir, s.config.VolumeBackupRetention)
+	if errors.Is(err, ErrNoVolumes) {
+		output.send("No volumes to back up", "info", "oar")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to back up volumes: %w", err)
+	}
+
+	output.send(fmt.Sprintf("Backed up volumes %s as %s", strings.Join(backup.Volumes, ", "), backup.Name),
+		"success", "oar")
+	return nil
+}
+
+// volumeBackupDir returns the directory holding the volume backups of a project
+func volumeBackupDir(config *Config, projectID uuid.UUID) string {
+	return filepath.Join(config.BackupDir, VolumesDir, projectID.String())
+}
+
+// backupVolumes archives the named volumes of a project that exist into a new backup in dir, and removes the
+// backups beyond retention. The archives are written to a temporary directory first, so that a failed backup
+// leaves nothing behind.
+func backupVolumes(
+	ctx context.Context,
+	composeProject ComposeProjectInterface,
+	projectID uuid.UUID,
+	dir string,
+	retention int,
+) (*VolumeBackup, error) {
+	volumes, err := composeProject.Volumes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	volumes = slices.DeleteFunc(volumes, func(volume ComposeVolume) bool { return !volume.Exists })
+	if len(volumes) == 0 {
+		return nil, ErrNoVolumes
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create volume backup directory: %w", err)
+	}
+	createdAt := time.Now().UTC().Truncate(time.Millisecond)
+	name := createdAt.Format(backupTimeFormat)
+	tmpDir, err := os.MkdirTemp(dir, "."+name+"-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create volume backup directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	backup := &VolumeBackup{Name: name, ProjectID: projectID, Path: filepath.Join(dir, name), CreatedAt: createdAt}
+	for _, volume := range volumes {
+		archive := filepath.Join(tmpDir, volume.Key+volumeArchiveExt)
+		size, err := archiveVolume(ctx, composeProject, volume.Name, archive)
+		if err != nil {
+			return nil, fmt.Errorf("failed to back up volume %s: %w", volume.Key, err)
+		}
+		backup.Volumes = append(backup.Volumes, volume.Key)
+		backup.Size += size
+	}
+
+	if err := os.Rename(tmpDir, backup.Path); err != nil {
+		return nil, fmt.Errorf("failed to save volume backup: %w", err)
+	}
+	slog.Info("Project volumes backed up",
+		"project_id", projectID,
+		"backup", name,
+		"volumes", backup.Volumes,
+		"size", backup.Size)
+
+	// The backup was taken, failing to clean up old ones only costs disk space
+	if err := pruneVolumeBackups(dir, projectID, retention); err != nil {
+		slog.Warn("Failed to remove old volume backups", "project_id", projectID, "error", err)
+	}
+	return backup, nil
+}
+
+// archiveVolume writes the archive of a volume to path, returning its size
+func archiveVolume(ctx context.Context, composeProject ComposeProjectInterface, volume, path string) (int64, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return 0, err
+	}
+	if err := composeProject.BackupVolume(ctx, volume, file); err != nil {
+		_ = file.Close()
+		return 0, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return 0, err
+	}
+	return info.Size(), file.Close()
+}
+
+// restoreVolume replaces the contents of a volume with those of the archive at path
+func restoreVolume(ctx context.Context, composeProject ComposeProjectInterface, path, volume string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+	return composeProject.RestoreVolume(ctx, volume, file)
+}
+
+// listVolumeBackups returns the volume backups in dir, newest first
+func listVolumeBackups(dir string, projectID uuid.UUID) ([]*VolumeBackup, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []*VolumeBackup{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read volume backup directory: %w", err)
+	}
+
+	backups := []*VolumeBackup{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		createdAt, err := time.Parse(backupTimeFormat, entry.Name())
+		if err != nil {
+			continue
+		}
+
+		backup := &VolumeBackup{
+			Name:      entry.Name(),
+			ProjectID: projectID,
+			Path:      filepath.Join(dir, entry.Name()),
+			CreatedAt: createdAt,
+		}
+		archives, err := os.ReadDir(backup.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read volume backup %s: %w", backup.Name, err)
+		}
+		for _, archive := range archives {
+			key, ok := strings.CutSuffix(archive.Name(), volumeArchiveExt)
+			if !ok {
+				continue
+			}
+			info, err := archive.Info()
+			if err != nil {
+				return nil, fmt.Errorf("failed to read volume backup %s: %w", backup.Name, err)
+			}
+			backup.Volumes = append(backup.Volumes, key)
+			backup.Size += info.Size()
+		}
+		backups = append(backups, backup)
+	}
+
+	slices.SortFunc(backups, func(a, b *VolumeBackup) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return backups, nil
+}
+
+// pruneVolumeBackups removes the volume backups in dir beyond retention, oldest first
+func pruneVolumeBackups(dir string, projectID uuid.UUID, retention int) error {
+	if retention <= 0 {
+		return nil
+	}
+
+	backups, err := listVolumeBackups(dir, projectID)
+	if err != nil {
+		return err
+	}
+	for _, backup := range backups[min(retention, len(backups)):] {
+		if err := os.RemoveAll(backup.Path); err != nil {
+			return fmt.Errorf("failed to remove volume backup %s: %w", backup.Name, err)
+		}
+		slog.Info("Old volume backup removed", "project_id", projectID, "backup", backup.Name)
+	}
+	return nil
+}
+
+// NewVolumeBackupService creates a new VolumeBackupService
+func NewVolumeBackupService(projects ProjectManager, config *Config) *VolumeBackupService {
+	return &VolumeBackupService{
+		projects: projects,
+		config:   config,
+	}
+}
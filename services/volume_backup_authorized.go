@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// AuthorizedVolumeBackupService wraps a VolumeBackupManager and checks the acting user's role before every
+// operation. Viewers may list the volume backups of a project and deployers may back up its volumes. Restoring
+// replaces the data of the project, so it requires the admin role on the project.
+type AuthorizedVolumeBackupService struct {
+	inner VolumeBackupManager
+	roles RoleManager
+	user  *User
+}
+
+// Ensure AuthorizedVolumeBackupService implements VolumeBackupManager
+var _ VolumeBackupManager = (*AuthorizedVolumeBackupService)(nil)
+
+func (s *AuthorizedVolumeBackupService) List(projectID uuid.UUID) ([]*VolumeBackup, error) {
+	if err := s.roles.Authorize(s.user, &projectID, RoleViewer); err != nil {
+		return nil, err
+	}
+	return s.inner.List(projectID)
+}
+
+func (s *AuthorizedVolumeBackupService) Create(ctx context.Context, projectID uuid.UUID) (*VolumeBackup, error) {
+	if err := s.roles.Authorize(s.user, &projectID, RoleDeployer); err != nil {
+		return nil, err
+	}
+	return s.inner.Create(ctx, projectID)
+}
+
+func (s *AuthorizedVolumeBackupService) Restore(
+	ctx context.Context,
+	projectID uuid.UUID,
+	name string,
+	options DeployOptions,
+) error {
+	if err := s.roles.Authorize(s.user, &projectID, RoleAdmin); err != nil {
+		return err
+	}
+	options.Actor = s.user.Username
+	return s.inner.Restore(ctx, projectID, name, options)
+}
+
+// NewAuthorizedVolumeBackupService wraps inner so that every operation is performed on behalf of user
+func NewAuthorizedVolumeBackupService(
+	inner VolumeBackupManager,
+	roles RoleManager,
+	user *User,
+) *AuthorizedVolumeBackupService {
+	return &AuthorizedVolumeBackupService{
+		inner: inner,
+		roles: roles,
+		user:  user,
+	}
+}
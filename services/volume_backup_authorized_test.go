@@ -0,0 +1,48 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthorizedVolumeBackupService_RequiresProjectRole(t *testing.T) {
+	calls := map[string]struct {
+		required Role
+		call     func(s VolumeBackupManager, p *Project) error
+	}{
+		"list": {RoleViewer, func(s VolumeBackupManager, p *Project) error {
+			_, err := s.List(p.ID)
+			return err
+		}},
+		"create": {RoleDeployer, func(s VolumeBackupManager, p *Project) error {
+			_, err := s.Create(context.Background(), p.ID)
+			return err
+		}},
+		"restore": {RoleAdmin, func(s VolumeBackupManager, p *Project) error {
+			return s.Restore(context.Background(), p.ID, "20250102T150405.000Z", DeployOptions{})
+		}},
+	}
+
+	for name, tt := range calls {
+		for _, granted := range []Role{RoleNone, RoleViewer, RoleDeployer, RoleAdmin} {
+			t.Run(name+" as "+granted.String(), func(t *testing.T) {
+				roles, user, project := setupAuthorizationService(t)
+				if granted != RoleNone {
+					require.NoError(t, roles.Grant(user.ID, &project.ID, granted))
+				}
+				service := NewAuthorizedVolumeBackupService(&MockVolumeBackupManager{}, roles, user)
+
+				err := tt.call(service, project)
+
+				if granted >= tt.required {
+					assert.NoError(t, err)
+				} else {
+					assert.ErrorIs(t, err, ErrPermissionDenied)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// fakeVolumeProjects keeps projects in memory, standing in for the project service
+type fakeVolumeProjects struct {
+	ProjectManager
+	projects    map[uuid.UUID]*Project
+	locked      map[uuid.UUID]bool
+	lockOptions DeployOptions
+}
+
+func (f *fakeVolumeProjects) LockProject(projectID uuid.UUID, options DeployOptions) (func(), error) {
+	if f.locked[projectID] {
+		return nil, ErrDeploymentInProgress
+	}
+	f.locked[projectID] = true
+	f.lockOptions = options
+	return func() { delete(f.locked, projectID) }, nil
+}
+
+func (f *fakeVolumeProjects) Get(id uuid.UUID) (*Project, error) {
+	project, ok := f.projects[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return project, nil
+}
+
+// setupVolumeDocker writes a script standing in for Docker to dir and returns its path. Its Compose configuration
+// declares the volumes data and cache, and an external one, of which those listed in dir/volumes exist. Helper
+// containers archive a volume as "archive of <volume>" and restore one to dir/restored-<volume>.
+func setupVolumeDocker(t *testing.T, dir string, existing ...string) string {
+	t.Helper()
+	t.Setenv("OAR_TEST_DIR", dir)
+	volumes := []byte(strings.Join(existing, "\n"))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "volumes"), volumes, 0o600))
+
+	script := `#!/bin/sh
+shift 2
+case "$1" in
+compose)
+	while [ "$1" != "config" ]; do [ "$1" = "up" ] || [ "$1" = "pull" ] && exit 0; shift; done
+	echo '{"volumes": {"data": {"name": "test-project_data"}, "cache": {"name": "test-project_cache"},
+		"shared": {"name": "shared", "external": true}}}'
+	;;
+volume)
+	cat "$OAR_TEST_DIR/volumes"
+	;;
+run)
+	while [ "$1" != "--volume" ]; do shift; done
+	volume=${2%%:*}
+	case "$*" in
+	*"tar -czf -"*) printf 'archive of %s' "$volume" ;;
+	*) cat > "$OAR_TEST_DIR/restored-$volume" ;;
+	esac
+	;;
+esac
+`
+	dockerCommand := filepath.Join(dir, "docker")
+	require.NoError(t, os.WriteFile(dockerCommand, []byte(script), 0o755))
+	return dockerCommand
+}
+
+// setupVolumeBackupService creates a volume backup service for a stopped project, with the given retention
+func setupVolumeBackupService(t *testing.T, retention int, existing ...string) (*VolumeBackupService, *Project) {
+	t.Helper()
+	dir := t.TempDir()
+	project := createTestProject()
+	project.WorkingDir = dir
+	require.NoError(t, os.Mkdir(filepath.Join(dir, GitDir), 0o755))
+
+	config := &Config{
+		DockerCommand:         setupVolumeDocker(t, dir, existing...),
+		DockerHost:            "unix:///var/run/docker.sock",
+		BackupDir:             filepath.Join(dir, BackupsDir),
+		VolumeBackupImage:     "alpine:3",
+		VolumeBackupRetention: retention,
+	}
+	projects := &fakeVolumeProjects{
+		projects: map[uuid.UUID]*Project{project.ID: project},
+		locked:   map[uuid.UUID]bool{},
+	}
+	return NewVolumeBackupService(projects, config), project
+}
+
+func TestVolumeBackupService_CreateRestore(t *testing.T) {
+	service, project := setupVolumeBackupService(t, 0, "test-project_data", "test-project_cache")
+
+	backups, err := service.List(project.ID)
+	require.NoError(t, err)
+	assert.Empty(t, backups)
+
+	backup, err := service.Create(context.Background(), project.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cache", "data"}, backup.Volumes)
+	assert.Equal(t, int64(len("archive of test-project_cache")+len("archive of test-project_data")), backup.Size)
+
+	data, err := os.ReadFile(filepath.Join(backup.Path, "data.tar.gz"))
+	require.NoError(t, err)
+	assert.Equal(t, "archive of test-project_data", string(data))
+
+	backups, err = service.List(project.ID)
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+	assert.Equal(t, backup.Name, backups[0].Name)
+	assert.Equal(t, backup.Volumes, backups[0].Volumes)
+	assert.Equal(t, backup.Size, backups[0].Size)
+
+	options := DeployOptions{Trigger: DeploymentTriggerCLI, Actor: "admin"}
+	require.NoError(t, service.Restore(context.Background(), project.ID, backup.Name, options))
+	assert.Equal(t, options, service.projects.(*fakeVolumeProjects).lockOptions)
+	for _, volume := range []string{"test-project_data", "test-project_cache"} {
+		restored, err := os.ReadFile(filepath.Join(project.WorkingDir, "restored-"+volume))
+		require.NoError(t, err)
+		assert.Equal(t, "archive of "+volume, string(restored))
+	}
+}
+
+func TestVolumeBackupService_Create_OnlyExistingVolumes(t *testing.T) {
+	service, project := setupVolumeBackupService(t, 0, "test-project_data", "shared")
+
+	backup, err := service.Create(context.Background(), project.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"data"}, backup.Volumes)
+
+	service, project = setupVolumeBackupService(t, 0, "shared")
+	_, err = service.Create(context.Background(), project.ID)
+	assert.ErrorIs(t, err, ErrNoVolumes)
+
+	// A failed backup leaves nothing behind
+	entries, err := os.ReadDir(service.dir(project.ID))
+	if err == nil {
+		assert.Empty(t, entries)
+	}
+}
+
+func TestVolumeBackupService_Retention(t *testing.T) {
+	service, project := setupVolumeBackupService(t, 2, "test-project_data")
+
+	var names []string
+	for range 4 {
+		backup, err := service.Create(context.Background(), project.ID)
+		require.NoError(t, err)
+		names = append(names, backup.Name)
+	}
+
+	backups, err := service.List(project.ID)
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+	assert.Equal(t, names[3], backups[0].Name)
+	assert.Equal(t, names[2], backups[1].Name)
+}
+
+func TestVolumeBackupService_Restore_Invalid(t *testing.T) {
+	service, project := setupVolumeBackupService(t, 0, "test-project_data")
+	backup, err := service.Create(context.Background(), project.ID)
+	require.NoError(t, err)
+
+	err = service.Restore(context.Background(), project.ID, "../"+backup.Name, DeployOptions{})
+	assert.ErrorIs(t, err, ErrVolumeBackupNotFound)
+
+	project.Status = ProjectStatusRunning
+	err = service.Restore(context.Background(), project.ID, backup.Name, DeployOptions{})
+	assert.ErrorIs(t, err, ErrProjectRunning)
+	project.Status = ProjectStatusStopped
+
+	// Nor while the project is being deployed, and the restore releases the lock it holds
+	projects := service.projects.(*fakeVolumeProjects)
+	projects.locked[project.ID] = true
+	err = service.Restore(context.Background(), project.ID, backup.Name, DeployOptions{})
+	assert.ErrorIs(t, err, ErrDeploymentInProgress)
+	delete(projects.locked, project.ID)
+
+	// A backup of a volume the project no longer declares is not restored at all
+	require.NoError(t, os.WriteFile(filepath.Join(backup.Path, "old.tar.gz"), []byte("archive"), 0o600))
+	err = service.Restore(context.Background(), project.ID, backup.Name, DeployOptions{})
+	assert.ErrorContains(t, err, "volume old of backup "+backup.Name+" is no longer declared by the project")
+	_, err = os.Stat(filepath.Join(project.WorkingDir, "restored-test-project_data"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	assert.Empty(t, projects.locked)
+
+	_, err = service.Create(context.Background(), uuid.New())
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestProjectService_DeployStreaming_BackupVolumes(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping streaming command test in short mode")
+	}
+
+	service, repo, deploymentRepo, gitService, _ := setupMockProjectService(t)
+	tempDir := t.TempDir()
+	service.config.DockerCommand = setupVolumeDocker(t, tempDir, "test-project_data")
+	service.config.BackupDir = filepath.Join(tempDir, BackupsDir)
+
+	project := createTestProject()
+	project.WorkingDir = tempDir
+	project.BackupVolumes = true
+	require.NoError(t, os.Mkdir(filepath.Join(tempDir, GitDir), 0o755))
+	repo.projects[project.ID] = project
+	gitService.GetLatestCommitFunc = func(workingDir string) (string, error) {
+		return "abc123def456789012345678901234567890abcd", nil
+	}
+
+	outputChan := make(chan string, 100)
+	err := service.DeployStreaming(context.Background(), project.ID, DeployOptions{}, outputChan)
+	close(outputChan)
+	require.NoError(t, err)
+
+	backups, err := listVolumeBackups(volumeBackupDir(service.config, project.ID), project.ID)
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+	assert.Equal(t, []string{"data"}, backups[0].Volumes)
+
+	require.Len(t, deploymentRepo.deployments, 1)
+	for _, deployment := range deploymentRepo.deployments {
+		assert.Equal(t, DeploymentStatusCompleted, deployment.Status)
+		assert.Contains(t, deployment.Output, "Backed up volumes data as "+backups[0].Name)
+	}
+}
@@ -0,0 +1,393 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Supported webhook providers
+const (
+	WebhookProviderGitHub  = "github"
+	WebhookProviderGitLab  = "gitlab"
+	WebhookProviderGitea   = "gitea"
+	WebhookProviderForgejo = "forgejo"
+)
+
+var (
+	// ErrWebhooksDisabled is returned when no webhook secret is configured
+	ErrWebhooksDisabled = errors.New("webhooks are disabled, set OAR_WEBHOOK_SECRET to enable them")
+	// ErrUnsupportedWebhookProvider is returned for providers other than GitHub, GitLab, Gitea and Forgejo
+	ErrUnsupportedWebhookProvider = errors.New("unsupported webhook provider")
+	// ErrInvalidWebhookSignature is returned when a delivery's signature or token does not match the secret
+	ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+	// ErrInvalidWebhookPayload is returned when a push payload cannot be parsed
+	ErrInvalidWebhookPayload = errors.New("invalid webhook payload")
+)
+
+// WebhookPush is a push event received from a Git provider
+type WebhookPush struct {
+	RepositoryURLs []string // Clone, SSH and web URLs of the pushed repository
+	Branch         string   // Pushed branch, empty for tag pushes
+	Tag            string   // Pushed tag, empty for branch pushes
+	Commit         string
+	Pusher         string // Who pushed, if the provider reports it
+}
+
+// WebhookResult describes what was done with a webhook delivery
+type WebhookResult struct {
+	Message  string
+	Projects []*Project // Projects for which a deployment was triggered
+}
+
+// WebhookService verifies Git provider webhook deliveries and deploys the projects a push affects.
+// Deployments run in the background, so that providers get a response before their delivery timeout.
+type WebhookService struct {
+	projectService ProjectManager
+	config         *Config
+	deployments    sync.WaitGroup
+}
+
+// Handle verifies and processes a webhook delivery. Push events deploy every running, watched project
+// whose repository URL and branch or tag pattern match the push. Other events are acknowledged and ignored.
+func (s *WebhookService) Handle(provider string, header http.Header, body []byte) (*WebhookResult, error) {
+	if s.config.WebhookSecret == "" {
+		return nil, ErrWebhooksDisabled
+	}
+
+	if err := verifyWebhookSignature(provider, header, body, s.config.WebhookSecret); err != nil {
+		return nil, err
+	}
+
+	event := webhookEvent(provider, header)
+	if event != "push" {
+		slog.Debug("Ignoring webhook event", "provider", provider, "event", event)
+		return &WebhookResult{Message: fmt.Sprintf("ignored %q event", event)}, nil
+	}
+
+	push, err := parseWebhookPush(body)
+	if err != nil {
+		return nil, err
+	}
+
+	if push.Branch == "" && push.Tag == "" {
+		return &WebhookResult{Message: "ignored push that is not to a branch or tag"}, nil
+	}
+	if strings.Trim(push.Commit, "0") == "" {
+		if push.Tag != "" {
+			return &WebhookResult{Message: "ignored tag deletion"}, nil
+		}
+		return &WebhookResult{Message: "ignored branch deletion"}, nil
+	}
+
+	projects, err := s.matchingProjects(push)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, project := range projects {
+		s.deploy(project, push)
+	}
+
+	slog.Info("Webhook push received",
+		"provider", provider,
+		"branch", push.Branch,
+		"tag", push.Tag,
+		"commit", push.Commit,
+		"triggered_deployments", len(projects))
+
+	return &WebhookResult{
+		Message:  fmt.Sprintf("triggered %d deployment(s)", len(projects)),
+		Projects: projects,
+	}, nil
+}
+
+// Wait blocks until all deployments triggered by webhooks have finished
+func (s *WebhookService) Wait() {
+	s.deployments.Wait()
+}
+
+// matchingProjects returns the projects a push should deploy. Like the watcher, only projects with
+// automatic deployments enabled that are running are deployed, and only if the commit is new to them
+// and was not rolled back. Branch pushes deploy projects tracking the branch, tag pushes projects whose
+// tag pattern matches the tag. Projects pinned to a commit are never deployed by a push.
+func (s *WebhookService) matchingProjects(push *WebhookPush) ([]*Project, error) {
+	projects, err := s.projectService.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list projects: %w", err)
+	}
+
+	pushedURLs := make(map[string]bool, len(push.RepositoryURLs))
+	for _, u := range push.RepositoryURLs {
+		if u != "" {
+			pushedURLs[normalizeRepositoryURL(u)] = true
+		}
+	}
+
+	var matched []*Project
+	for _, project := range projects {
+		if !pushedURLs[normalizeRepositoryURL(project.GitURL)] || !pushMatchesGitRef(push, project) {
+			continue
+		}
+		if !AutomaticDeployments(project) || project.Status != ProjectStatusRunning {
+			slog.Info("Webhook push matches project without automatic deployments - skipping",
+				"project_id", project.ID,
+				"project_name", project.Name,
+				"watcher_enabled", project.WatcherEnabled,
+				"status", project.Status.String())
+			continue
+		}
+		if project.LastCommitStr() == push.Commit {
+			slog.Debug("Webhook push commit is already deployed",
+				"project_id", project.ID,
+				"project_name", project.Name,
+				"commit", push.Commit)
+			continue
+		}
+		if project.RolledBackCommitStr() == push.Commit {
+			slog.Info("Webhook push commit was rolled back - skipping",
+				"project_id", project.ID,
+				"project_name", project.Name,
+				"commit", push.Commit)
+			continue
+		}
+		matched = append(matched, project)
+	}
+	return matched, nil
+}
+
+// pushMatchesGitRef reports whether a push updates the ref a project tracks
+func pushMatchesGitRef(push *WebhookPush, project *Project) bool {
+	switch project.GitRefType {
+	case GitRefTypeBranch:
+		return push.Branch != "" && push.Branch == project.GitBranch
+	case GitRefTypeTag:
+		return push.Tag != "" && TagMatches(project.GitRef, push.Tag)
+	default:
+		return false
+	}
+}
+
+// deploy starts a deployment of project in the background, discarding its streamed output. Projects that require
+// approval get a pending deployment of the commit their Git ref resolves to instead.
+func (s *WebhookService) deploy(project *Project, push *WebhookPush) {
+	s.deployments.Add(1)
+	go func() {
+		defer s.deployments.Done()
+
+		if project.RequireApproval {
+			s.requestApproval(project, push)
+			return
+		}
+
+		outputChan := make(chan string, 100)
+		go func() {
+			for range outputChan {
+			}
+		}()
+		defer close(outputChan)
+
+		slog.Info("Webhook triggering deployment",
+			"project_id", project.ID,
+			"project_name", project.Name,
+			"target_commit", push.Commit)
+
+		options := DeployOptions{Pull: true, Trigger: DeploymentTriggerWebhook, Actor: push.Pusher}
+		if err := s.projectService.DeployStreaming(context.Background(), project.ID, options, outputChan); err != nil {
+			slog.Error("Service operation failed",
+				"layer", "service",
+				"operation", "webhook_deploy",
+				"project_id", project.ID,
+				"project_name", project.Name,
+				"target_commit", push.Commit,
+				"error", err)
+			return
+		}
+
+		slog.Info("Webhook deployment completed successfully",
+			"project_id", project.ID,
+			"project_name", project.Name)
+	}()
+}
+
+// requestApproval records a pending deployment of the commit a push brought to project
+func (s *WebhookService) requestApproval(project *Project, push *WebhookPush) {
+	options := DeployOptions{Trigger: DeploymentTriggerWebhook, Actor: push.Pusher}
+	deployment, err := s.projectService.RequestApproval(project.ID, "", options)
+	if errors.Is(err, ErrCommitRejected) {
+		slog.Info("Webhook push commit was rejected - skipping",
+			"project_id", project.ID,
+			"project_name", project.Name,
+			"commit", push.Commit)
+		return
+	}
+	if err != nil {
+		slog.Error("Service operation failed",
+			"layer", "service",
+			"operation", "webhook_request_approval",
+			"project_id", project.ID,
+			"project_name", project.Name,
+			"target_commit", push.Commit,
+			"error", err)
+		return
+	}
+	if deployment != nil {
+		slog.Info("Webhook deployment awaiting approval",
+			"project_id", project.ID,
+			"project_name", project.Name,
+			"deployment_id", deployment.ID)
+	}
+}
+
+// verifyWebhookSignature checks a delivery against the shared secret: GitHub, Gitea and Forgejo sign the
+// body with HMAC-SHA256, GitLab sends the secret itself as a token
+func verifyWebhookSignature(provider string, header http.Header, body []byte, secret string) error {
+	var signature string
+	switch provider {
+	case WebhookProviderGitHub:
+		signature, _ = strings.CutPrefix(header.Get("X-Hub-Signature-256"), "sha256=")
+	case WebhookProviderGitea:
+		signature = header.Get("X-Gitea-Signature")
+	case WebhookProviderForgejo:
+		signature = header.Get("X-Forgejo-Signature")
+		if signature == "" {
+			signature = header.Get("X-Gitea-Signature")
+		}
+	case WebhookProviderGitLab:
+		token := header.Get("X-Gitlab-Token")
+		if token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+			return ErrInvalidWebhookSignature
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", ErrUnsupportedWebhookProvider, provider)
+	}
+
+	got, err := hex.DecodeString(signature)
+	if err != nil || len(got) == 0 {
+		return ErrInvalidWebhookSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return ErrInvalidWebhookSignature
+	}
+	return nil
+}
+
+// webhookEvent returns the event type of a delivery, with push events normalized to "push"
+func webhookEvent(provider string, header http.Header) string {
+	var event string
+	switch provider {
+	case WebhookProviderGitHub:
+		event = header.Get("X-GitHub-Event")
+	case WebhookProviderGitLab:
+		event = header.Get("X-Gitlab-Event")
+		if event == "Push Hook" {
+			return "push"
+		}
+	case WebhookProviderGitea:
+		event = header.Get("X-Gitea-Event")
+	case WebhookProviderForgejo:
+		event = header.Get("X-Forgejo-Event")
+		if event == "" {
+			event = header.Get("X-Gitea-Event")
+		}
+	}
+	return event
+}
+
+// parseWebhookPush extracts the repository, branch or tag, commit and pusher from a push payload. GitHub, Gitea
+// and Forgejo describe the repository under "repository" and the pusher under "pusher", GitLab the repository
+// under "project" and the pusher in "user_username".
+func parseWebhookPush(body []byte) (*WebhookPush, error) {
+	var payload struct {
+		Ref        string `json:"ref"`
+		After      string `json:"after"`
+		Repository struct {
+			CloneURL string `json:"clone_url"`
+			SSHURL   string `json:"ssh_url"`
+			HTMLURL  string `json:"html_url"`
+		} `json:"repository"`
+		Project struct {
+			GitHTTPURL string `json:"git_http_url"`
+			GitSSHURL  string `json:"git_ssh_url"`
+			WebURL     string `json:"web_url"`
+		} `json:"project"`
+		Pusher struct {
+			Login string `json:"login"`
+			Name  string `json:"name"`
+		} `json:"pusher"`
+		UserUsername string `json:"user_username"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWebhookPayload, err)
+	}
+	if payload.Ref == "" || payload.After == "" {
+		return nil, fmt.Errorf("%w: missing ref or commit", ErrInvalidWebhookPayload)
+	}
+
+	branch, isBranch := strings.CutPrefix(payload.Ref, "refs/heads/")
+	if !isBranch {
+		branch = ""
+	}
+	tag, isTag := strings.CutPrefix(payload.Ref, "refs/tags/")
+	if !isTag {
+		tag = ""
+	}
+
+	return &WebhookPush{
+		RepositoryURLs: []string{
+			payload.Repository.CloneURL,
+			payload.Repository.SSHURL,
+			payload.Repository.HTMLURL,
+			payload.Project.GitHTTPURL,
+			payload.Project.GitSSHURL,
+			payload.Project.WebURL,
+		},
+		Branch: branch,
+		Tag:    tag,
+		Commit: payload.After,
+		Pusher: cmp.Or(payload.Pusher.Login, payload.Pusher.Name, payload.UserUsername),
+	}, nil
+}
+
+// normalizeRepositoryURL reduces the HTTP(S), SSH and scp-like forms of a repository URL to
+// "host/path", so that e.g. https://github.com/org/repo.git and git@github.com:org/repo match
+func normalizeRepositoryURL(rawURL string) string {
+	u := strings.ToLower(strings.TrimSpace(rawURL))
+
+	if i := strings.Index(u, "://"); i >= 0 {
+		u = u[i+3:]
+	} else if i := strings.Index(u, ":"); i >= 0 && !strings.Contains(u[:i], "/") {
+		// scp-like syntax: [user@]host:path
+		u = u[:i] + "/" + u[i+1:]
+	}
+
+	host, path, _ := strings.Cut(u, "/")
+	if i := strings.LastIndex(host, "@"); i >= 0 {
+		host = host[i+1:] // Drop credentials
+	}
+	host, _, _ = strings.Cut(host, ":") // Drop the port, HTTP and SSH ports usually differ
+
+	return host + "/" + strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+}
+
+// NewWebhookService creates a new WebhookService
+func NewWebhookService(projectService ProjectManager, config *Config) *WebhookService {
+	return &WebhookService{
+		projectService: projectService,
+		config:         config,
+	}
+}
@@ -0,0 +1,60 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/oar-cd/oar/services"
+)
+
+// MockCatalogManager implements the CatalogManager interface for testing
+type MockCatalogManager struct {
+	ListFunc   func() ([]*services.Catalog, error)
+	GetFunc    func(id uuid.UUID) (*services.Catalog, error)
+	CreateFunc func(catalog *services.Catalog) (*services.Catalog, error)
+	UpdateFunc func(catalog *services.Catalog) error
+	RemoveFunc func(id uuid.UUID, removeProjects bool) error
+	SyncFunc   func(ctx context.Context, id uuid.UUID) (*services.CatalogSyncResult, error)
+}
+
+func (m *MockCatalogManager) List() ([]*services.Catalog, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc()
+	}
+	return []*services.Catalog{}, nil
+}
+
+func (m *MockCatalogManager) Get(id uuid.UUID) (*services.Catalog, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(id)
+	}
+	return &services.Catalog{ID: id}, nil
+}
+
+func (m *MockCatalogManager) Create(catalog *services.Catalog) (*services.Catalog, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(catalog)
+	}
+	return catalog, nil
+}
+
+func (m *MockCatalogManager) Update(catalog *services.Catalog) error {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(catalog)
+	}
+	return nil
+}
+
+func (m *MockCatalogManager) Remove(id uuid.UUID, removeProjects bool) error {
+	if m.RemoveFunc != nil {
+		return m.RemoveFunc(id, removeProjects)
+	}
+	return nil
+}
+
+func (m *MockCatalogManager) Sync(ctx context.Context, id uuid.UUID) (*services.CatalogSyncResult, error) {
+	if m.SyncFunc != nil {
+		return m.SyncFunc(ctx, id)
+	}
+	return &services.CatalogSyncResult{}, nil
+}
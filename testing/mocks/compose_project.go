@@ -2,6 +2,9 @@
 package mocks
 
 import (
+	"context"
+	"io"
+
 	"github.com/oar-cd/oar/services"
 	"github.com/stretchr/testify/mock"
 )
@@ -36,16 +39,65 @@ func (m *MockComposeProject) Status() (*services.ComposeStatus, error) {
 	return args.Get(0).(*services.ComposeStatus), args.Error(1)
 }
 
-func (m *MockComposeProject) UpStreaming(outputChan chan<- string) error {
+func (m *MockComposeProject) UpStreaming(ctx context.Context, outputChan chan<- string) error {
 	args := m.Called(outputChan)
 	return args.Error(0)
 }
 
-func (m *MockComposeProject) UpPiping() error {
+func (m *MockComposeProject) UpPiping(ctx context.Context) error {
 	args := m.Called()
 	return args.Error(0)
 }
 
+func (m *MockComposeProject) PullStreaming(ctx context.Context, outputChan chan<- string) error {
+	args := m.Called(outputChan)
+	return args.Error(0)
+}
+
+func (m *MockComposeProject) Exec(ctx context.Context, service, command string) (string, error) {
+	args := m.Called(service, command)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockComposeProject) RunHookStreaming(ctx context.Context, hook services.Hook, outputChan chan<- string) error {
+	args := m.Called(hook, outputChan)
+	return args.Error(0)
+}
+
+func (m *MockComposeProject) Volumes(ctx context.Context) ([]services.ComposeVolume, error) {
+	args := m.Called()
+	volumes, _ := args.Get(0).([]services.ComposeVolume)
+	return volumes, args.Error(1)
+}
+
+func (m *MockComposeProject) BackupVolume(ctx context.Context, volume string, w io.Writer) error {
+	args := m.Called(volume, w)
+	return args.Error(0)
+}
+
+func (m *MockComposeProject) RestoreVolume(ctx context.Context, volume string, r io.Reader) error {
+	args := m.Called(volume, r)
+	return args.Error(0)
+}
+
+func (m *MockComposeProject) Drift(ctx context.Context) ([]services.ServiceDrift, error) {
+	args := m.Called()
+	drift, _ := args.Get(0).([]services.ServiceDrift)
+	return drift, args.Error(1)
+}
+
+func (m *MockComposeProject) DownServices(ctx context.Context) ([]string, error) {
+	args := m.Called()
+	down, _ := args.Get(0).([]string)
+	return down, args.Error(1)
+}
+
+func (m *MockComposeProject) ImageUpdates(ctx context.Context) ([]services.ImageUpdate, error) {
+	args := m.Called()
+	updates, _ := args.Get(0).([]services.ImageUpdate)
+	return updates, args.Error(1)
+}
+
 func (m *MockComposeProject) DownStreaming(outputChan chan<- string) error {
 	args := m.Called(outputChan)
 	return args.Error(0)
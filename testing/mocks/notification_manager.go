@@ -0,0 +1,42 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/oar-cd/oar/services"
+)
+
+// MockNotificationManager implements the NotificationManager interface for testing
+type MockNotificationManager struct {
+	ListNotifiersFunc  func(projectID uuid.UUID) ([]*services.Notifier, error)
+	AddNotifierFunc    func(notifier *services.Notifier) error
+	RemoveNotifierFunc func(projectID, notifierID uuid.UUID) error
+	TestNotifierFunc   func(projectID, notifierID uuid.UUID) error
+}
+
+func (m *MockNotificationManager) ListNotifiers(projectID uuid.UUID) ([]*services.Notifier, error) {
+	if m.ListNotifiersFunc != nil {
+		return m.ListNotifiersFunc(projectID)
+	}
+	return []*services.Notifier{}, nil
+}
+
+func (m *MockNotificationManager) AddNotifier(notifier *services.Notifier) error {
+	if m.AddNotifierFunc != nil {
+		return m.AddNotifierFunc(notifier)
+	}
+	return nil
+}
+
+func (m *MockNotificationManager) RemoveNotifier(projectID, notifierID uuid.UUID) error {
+	if m.RemoveNotifierFunc != nil {
+		return m.RemoveNotifierFunc(projectID, notifierID)
+	}
+	return nil
+}
+
+func (m *MockNotificationManager) TestNotifier(projectID, notifierID uuid.UUID) error {
+	if m.TestNotifierFunc != nil {
+		return m.TestNotifierFunc(projectID, notifierID)
+	}
+	return nil
+}
@@ -1,27 +1,59 @@
 package mocks
 
 import (
+	"context"
+
 	"github.com/google/uuid"
 	"github.com/oar-cd/oar/services"
 )
 
 // MockProjectManager implements the ProjectManager interface for testing
 type MockProjectManager struct {
-	ListFunc             func() ([]*services.Project, error)
-	GetFunc              func(id uuid.UUID) (*services.Project, error)
-	CreateFunc           func(project *services.Project) (*services.Project, error)
-	UpdateFunc           func(project *services.Project) error
-	RemoveFunc           func(projectID uuid.UUID) error
-	DeployStreamingFunc  func(projectID uuid.UUID, pull bool, outputChan chan<- string) error
-	DeployPipingFunc     func(projectID uuid.UUID, pull bool) error
-	StopFunc             func(projectID uuid.UUID) error
-	StopStreamingFunc    func(projectID uuid.UUID, outputChan chan<- string) error
-	StopPipingFunc       func(projectID uuid.UUID) error
-	GetLogsStreamingFunc func(projectID uuid.UUID, outputChan chan<- string) error
-	GetLogsPipingFunc    func(projectID uuid.UUID) error
-	GetConfigFunc        func(projectID uuid.UUID) (string, error)
-	GetStatusFunc        func(projectID uuid.UUID) (*services.ComposeStatus, error)
-	ListDeploymentsFunc  func(projectID uuid.UUID) ([]*services.Deployment, error)
+	ListFunc              func() ([]*services.Project, error)
+	GetFunc               func(id uuid.UUID) (*services.Project, error)
+	CreateFunc            func(project *services.Project) (*services.Project, error)
+	UpdateFunc            func(project *services.Project) error
+	RemoveFunc            func(projectID uuid.UUID) error
+	CancelDeploymentFunc  func(projectID uuid.UUID) error
+	LockProjectFunc       func(projectID uuid.UUID, options services.DeployOptions) (func(), error)
+	DeployStreamingFunc   func(projectID uuid.UUID, options services.DeployOptions, outputChan chan<- string) error
+	DeployPipingFunc      func(projectID uuid.UUID, options services.DeployOptions) error
+	RollbackPipingFunc    func(projectID uuid.UUID, target string, options services.DeployOptions) error
+	ApprovePipingFunc     func(projectID, deploymentID uuid.UUID, options services.DeployOptions) error
+	RejectDeploymentFunc  func(projectID, deploymentID uuid.UUID, options services.DeployOptions) error
+	StopFunc              func(projectID uuid.UUID) error
+	StopStreamingFunc     func(projectID uuid.UUID, outputChan chan<- string) error
+	StopPipingFunc        func(projectID uuid.UUID) error
+	GetLogsStreamingFunc  func(projectID uuid.UUID, outputChan chan<- string) error
+	GetLogsPipingFunc     func(projectID uuid.UUID) error
+	GetConfigFunc         func(projectID uuid.UUID) (string, error)
+	GetStatusFunc         func(projectID uuid.UUID) (*services.ComposeStatus, error)
+	DetectDriftFunc       func(ctx context.Context, projectID uuid.UUID) ([]services.ServiceDrift, error)
+	ReconcileFunc         func(ctx context.Context, projectID uuid.UUID) ([]string, error)
+	CheckImagesFunc       func(ctx context.Context, projectID uuid.UUID) ([]services.ImageUpdate, error)
+	ListDeploymentsFunc   func(projectID uuid.UUID) ([]*services.Deployment, error)
+	RollbackStreamingFunc func(
+		projectID uuid.UUID,
+		target string,
+		options services.DeployOptions,
+		outputChan chan<- string,
+	) error
+	ApproveStreamingFunc func(
+		projectID uuid.UUID,
+		deploymentID uuid.UUID,
+		options services.DeployOptions,
+		outputChan chan<- string,
+	) error
+	RequestApprovalFunc func(
+		projectID uuid.UUID,
+		commit string,
+		options services.DeployOptions,
+	) (*services.Deployment, error)
+	PreviewDeployFunc func(
+		ctx context.Context,
+		projectID uuid.UUID,
+		options services.DeployOptions,
+	) (*services.DeployPreview, error)
 }
 
 func (m *MockProjectManager) List() ([]*services.Project, error) {
@@ -59,20 +91,111 @@ func (m *MockProjectManager) Remove(projectID uuid.UUID) error {
 	return nil
 }
 
-func (m *MockProjectManager) DeployStreaming(projectID uuid.UUID, pull bool, outputChan chan<- string) error {
+func (m *MockProjectManager) DeployStreaming(
+	ctx context.Context,
+	projectID uuid.UUID,
+	options services.DeployOptions,
+	outputChan chan<- string,
+) error {
 	if m.DeployStreamingFunc != nil {
-		return m.DeployStreamingFunc(projectID, pull, outputChan)
+		return m.DeployStreamingFunc(projectID, options, outputChan)
 	}
 	return nil
 }
 
-func (m *MockProjectManager) DeployPiping(projectID uuid.UUID, pull bool) error {
+func (m *MockProjectManager) DeployPiping(
+	ctx context.Context,
+	projectID uuid.UUID,
+	options services.DeployOptions,
+) error {
 	if m.DeployPipingFunc != nil {
-		return m.DeployPipingFunc(projectID, pull)
+		return m.DeployPipingFunc(projectID, options)
+	}
+	return nil
+}
+
+func (m *MockProjectManager) RollbackStreaming(
+	ctx context.Context,
+	projectID uuid.UUID,
+	target string,
+	options services.DeployOptions,
+	outputChan chan<- string,
+) error {
+	if m.RollbackStreamingFunc != nil {
+		return m.RollbackStreamingFunc(projectID, target, options, outputChan)
+	}
+	return nil
+}
+
+func (m *MockProjectManager) RollbackPiping(
+	ctx context.Context,
+	projectID uuid.UUID,
+	target string,
+	options services.DeployOptions,
+) error {
+	if m.RollbackPipingFunc != nil {
+		return m.RollbackPipingFunc(projectID, target, options)
+	}
+	return nil
+}
+
+func (m *MockProjectManager) RequestApproval(
+	projectID uuid.UUID,
+	commit string,
+	options services.DeployOptions,
+) (*services.Deployment, error) {
+	if m.RequestApprovalFunc != nil {
+		return m.RequestApprovalFunc(projectID, commit, options)
+	}
+	return nil, nil
+}
+
+func (m *MockProjectManager) ApproveStreaming(
+	ctx context.Context,
+	projectID uuid.UUID,
+	deploymentID uuid.UUID,
+	options services.DeployOptions,
+	outputChan chan<- string,
+) error {
+	if m.ApproveStreamingFunc != nil {
+		return m.ApproveStreamingFunc(projectID, deploymentID, options, outputChan)
 	}
 	return nil
 }
 
+func (m *MockProjectManager) ApprovePiping(
+	ctx context.Context,
+	projectID uuid.UUID,
+	deploymentID uuid.UUID,
+	options services.DeployOptions,
+) error {
+	if m.ApprovePipingFunc != nil {
+		return m.ApprovePipingFunc(projectID, deploymentID, options)
+	}
+	return nil
+}
+
+func (m *MockProjectManager) RejectDeployment(projectID, deploymentID uuid.UUID, options services.DeployOptions) error {
+	if m.RejectDeploymentFunc != nil {
+		return m.RejectDeploymentFunc(projectID, deploymentID, options)
+	}
+	return nil
+}
+
+func (m *MockProjectManager) CancelDeployment(projectID uuid.UUID) error {
+	if m.CancelDeploymentFunc != nil {
+		return m.CancelDeploymentFunc(projectID)
+	}
+	return nil
+}
+
+func (m *MockProjectManager) LockProject(projectID uuid.UUID, options services.DeployOptions) (func(), error) {
+	if m.LockProjectFunc != nil {
+		return m.LockProjectFunc(projectID, options)
+	}
+	return func() {}, nil
+}
+
 func (m *MockProjectManager) Stop(projectID uuid.UUID) error {
 	if m.StopFunc != nil {
 		return m.StopFunc(projectID)
@@ -122,6 +245,38 @@ func (m *MockProjectManager) GetStatus(projectID uuid.UUID) (*services.ComposeSt
 	return &services.ComposeStatus{}, nil
 }
 
+func (m *MockProjectManager) DetectDrift(ctx context.Context, projectID uuid.UUID) ([]services.ServiceDrift, error) {
+	if m.DetectDriftFunc != nil {
+		return m.DetectDriftFunc(ctx, projectID)
+	}
+	return nil, nil
+}
+
+func (m *MockProjectManager) Reconcile(ctx context.Context, projectID uuid.UUID) ([]string, error) {
+	if m.ReconcileFunc != nil {
+		return m.ReconcileFunc(ctx, projectID)
+	}
+	return nil, nil
+}
+
+func (m *MockProjectManager) CheckImages(ctx context.Context, projectID uuid.UUID) ([]services.ImageUpdate, error) {
+	if m.CheckImagesFunc != nil {
+		return m.CheckImagesFunc(ctx, projectID)
+	}
+	return nil, nil
+}
+
+func (m *MockProjectManager) PreviewDeploy(
+	ctx context.Context,
+	projectID uuid.UUID,
+	options services.DeployOptions,
+) (*services.DeployPreview, error) {
+	if m.PreviewDeployFunc != nil {
+		return m.PreviewDeployFunc(ctx, projectID, options)
+	}
+	return &services.DeployPreview{}, nil
+}
+
 func (m *MockProjectManager) ListDeployments(projectID uuid.UUID) ([]*services.Deployment, error) {
 	if m.ListDeploymentsFunc != nil {
 		return m.ListDeploymentsFunc(projectID)
@@ -0,0 +1,50 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/oar-cd/oar/services"
+)
+
+// MockRoleManager implements the RoleManager interface for testing
+type MockRoleManager struct {
+	GrantFunc         func(userID uuid.UUID, projectID *uuid.UUID, role services.Role) error
+	RevokeFunc        func(userID uuid.UUID, projectID *uuid.UUID) error
+	ListRolesFunc     func(userID uuid.UUID) ([]*services.RoleBinding, error)
+	EffectiveRoleFunc func(userID uuid.UUID, projectID *uuid.UUID) (services.Role, error)
+	AuthorizeFunc     func(user *services.User, projectID *uuid.UUID, required services.Role) error
+}
+
+func (m *MockRoleManager) Grant(userID uuid.UUID, projectID *uuid.UUID, role services.Role) error {
+	if m.GrantFunc != nil {
+		return m.GrantFunc(userID, projectID, role)
+	}
+	return nil
+}
+
+func (m *MockRoleManager) Revoke(userID uuid.UUID, projectID *uuid.UUID) error {
+	if m.RevokeFunc != nil {
+		return m.RevokeFunc(userID, projectID)
+	}
+	return nil
+}
+
+func (m *MockRoleManager) ListRoles(userID uuid.UUID) ([]*services.RoleBinding, error) {
+	if m.ListRolesFunc != nil {
+		return m.ListRolesFunc(userID)
+	}
+	return []*services.RoleBinding{}, nil
+}
+
+func (m *MockRoleManager) EffectiveRole(userID uuid.UUID, projectID *uuid.UUID) (services.Role, error) {
+	if m.EffectiveRoleFunc != nil {
+		return m.EffectiveRoleFunc(userID, projectID)
+	}
+	return services.RoleNone, nil
+}
+
+func (m *MockRoleManager) Authorize(user *services.User, projectID *uuid.UUID, required services.Role) error {
+	if m.AuthorizeFunc != nil {
+		return m.AuthorizeFunc(user, projectID, required)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/oar-cd/oar/services"
+)
+
+// MockUserManager implements the UserManager interface for testing
+type MockUserManager struct {
+	ListUsersFunc             func() ([]*services.User, error)
+	GetUserFunc               func(username string) (*services.User, error)
+	CreateUserFunc            func(username, password string) (*services.User, error)
+	RemoveUserFunc            func(username string) error
+	SetPasswordFunc           func(username, password string) error
+	AuthenticateFunc          func(username, password string) (*services.User, error)
+	CreateSessionFunc         func(userID uuid.UUID) (string, *services.Session, error)
+	ValidateSessionFunc       func(token string) (*services.User, error)
+	DeleteSessionFunc         func(token string) error
+	DeleteExpiredSessionsFunc func() error
+}
+
+func (m *MockUserManager) ListUsers() ([]*services.User, error) {
+	if m.ListUsersFunc != nil {
+		return m.ListUsersFunc()
+	}
+	return []*services.User{}, nil
+}
+
+func (m *MockUserManager) GetUser(username string) (*services.User, error) {
+	if m.GetUserFunc != nil {
+		return m.GetUserFunc(username)
+	}
+	return &services.User{ID: uuid.New(), Username: username}, nil
+}
+
+func (m *MockUserManager) CreateUser(username, password string) (*services.User, error) {
+	if m.CreateUserFunc != nil {
+		return m.CreateUserFunc(username, password)
+	}
+	return &services.User{ID: uuid.New(), Username: username}, nil
+}
+
+func (m *MockUserManager) RemoveUser(username string) error {
+	if m.RemoveUserFunc != nil {
+		return m.RemoveUserFunc(username)
+	}
+	return nil
+}
+
+func (m *MockUserManager) SetPassword(username, password string) error {
+	if m.SetPasswordFunc != nil {
+		return m.SetPasswordFunc(username, password)
+	}
+	return nil
+}
+
+func (m *MockUserManager) Authenticate(username, password string) (*services.User, error) {
+	if m.AuthenticateFunc != nil {
+		return m.AuthenticateFunc(username, password)
+	}
+	return nil, services.ErrInvalidCredentials
+}
+
+func (m *MockUserManager) CreateSession(userID uuid.UUID) (string, *services.Session, error) {
+	if m.CreateSessionFunc != nil {
+		return m.CreateSessionFunc(userID)
+	}
+	return "test-token", &services.Session{ID: uuid.New(), UserID: userID}, nil
+}
+
+func (m *MockUserManager) ValidateSession(token string) (*services.User, error) {
+	if m.ValidateSessionFunc != nil {
+		return m.ValidateSessionFunc(token)
+	}
+	return nil, services.ErrInvalidSession
+}
+
+func (m *MockUserManager) DeleteSession(token string) error {
+	if m.DeleteSessionFunc != nil {
+		return m.DeleteSessionFunc(token)
+	}
+	return nil
+}
+
+func (m *MockUserManager) DeleteExpiredSessions() error {
+	if m.DeleteExpiredSessionsFunc != nil {
+		return m.DeleteExpiredSessionsFunc()
+	}
+	return nil
+}
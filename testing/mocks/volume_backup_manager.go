@@ -0,0 +1,41 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/oar-cd/oar/services"
+)
+
+// MockVolumeBackupManager implements the VolumeBackupManager interface for testing
+type MockVolumeBackupManager struct {
+	ListFunc    func(projectID uuid.UUID) ([]*services.VolumeBackup, error)
+	CreateFunc  func(ctx context.Context, projectID uuid.UUID) (*services.VolumeBackup, error)
+	RestoreFunc func(ctx context.Context, projectID uuid.UUID, name string, options services.DeployOptions) error
+}
+
+func (m *MockVolumeBackupManager) List(projectID uuid.UUID) ([]*services.VolumeBackup, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(projectID)
+	}
+	return []*services.VolumeBackup{}, nil
+}
+
+func (m *MockVolumeBackupManager) Create(ctx context.Context, projectID uuid.UUID) (*services.VolumeBackup, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, projectID)
+	}
+	return &services.VolumeBackup{ProjectID: projectID}, nil
+}
+
+func (m *MockVolumeBackupManager) Restore(
+	ctx context.Context,
+	projectID uuid.UUID,
+	name string,
+	options services.DeployOptions,
+) error {
+	if m.RestoreFunc != nil {
+		return m.RestoreFunc(ctx, projectID, name, options)
+	}
+	return nil
+}
@@ -2,11 +2,16 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log"
 	"log/slog"
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
+	"time"
 
 	"github.com/oar-cd/oar/internal/app"
 	"github.com/oar-cd/oar/logging"
@@ -35,8 +40,10 @@ func main() {
 	// Initialize watcher service
 	watcherService := service.NewWatcherService(
 		app.GetProjectService(),
+		app.GetCatalogService(),
 		app.GetGitService(),
 		config.PollInterval, // Use configured poll interval
+		config.ImagePollInterval,
 	)
 
 	// Create context for graceful shutdown
@@ -52,6 +59,15 @@ func main() {
 		cancel()
 	}()
 
+	if config.WatcherMetricsPort != 0 {
+		addr := net.JoinHostPort(config.HTTPHost, strconv.Itoa(config.WatcherMetricsPort))
+		go serveMetrics(ctx, addr, config.MetricsToken)
+	}
+
+	if config.BackupInterval > 0 {
+		go runBackups(ctx, app.GetBackupService(), config.BackupInterval)
+	}
+
 	// Run watcher service in main thread
 	if err := watcherService.Start(ctx); err != nil {
 		slog.Error("Watcher service failed", "error", err)
@@ -60,3 +76,55 @@ func main() {
 
 	slog.Info("Watcher service stopped")
 }
+
+// serveMetrics serves the Prometheus metrics of the watcher at /metrics until ctx is cancelled. With a token, only
+// requests that carry it as their bearer token are served.
+func serveMetrics(ctx context.Context, addr, token string) {
+	handler := services.NewMetricsHandler(app.GetProjectService())
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token != "" && !services.MetricsTokenValid(r, token) {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	server := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+		_ = server.Shutdown(context.Background())
+	}()
+
+	slog.Info("Serving watcher metrics", "address", addr)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		slog.Error("Metrics server failed", "error", err)
+	}
+}
+
+// runBackups backs up the database every interval until ctx is cancelled. The first backup is taken an interval
+// after the latest existing one, so that restarting the watcher doesn't postpone backups.
+func runBackups(ctx context.Context, backups *services.BackupService, interval time.Duration) {
+	var wait time.Duration
+	if existing, err := backups.List(); err != nil {
+		slog.Error("Failed to list database backups", "error", err)
+	} else if len(existing) > 0 {
+		wait = max(0, time.Until(existing[0].CreatedAt.Add(interval)))
+	}
+
+	slog.Info("Scheduling database backups", "interval", interval, "next_backup_in", wait)
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			if _, err := backups.Create(ctx); err != nil {
+				slog.Error("Database backup failed", "error", err)
+			}
+			timer.Reset(interval)
+		}
+	}
+}
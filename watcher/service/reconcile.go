@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/oar-cd/oar/services"
+)
+
+// Projects whose services keep going down are brought back up at most every reconcileBackoffMin at first, doubling
+// with each attempt up to reconcileBackoffMax, so that a crash-looping project isn't redeployed on every poll
+const (
+	reconcileBackoffMin = time.Minute
+	reconcileBackoffMax = time.Hour
+)
+
+// reconcileBackoff is how long the watcher waits before bringing a project back up again
+type reconcileBackoff struct {
+	attempts int       // Attempts to bring the project back up since it was last seen up
+	next     time.Time // Time before which the project isn't checked
+}
+
+// delay returns how long to wait after the attempts so far
+func (b *reconcileBackoff) delay() time.Duration {
+	delay := reconcileBackoffMin
+	for range b.attempts - 1 {
+		delay *= 2
+		if delay >= reconcileBackoffMax {
+			return reconcileBackoffMax
+		}
+	}
+	return delay
+}
+
+// reconcileProjects brings the projects that are desired running back up when their services are down
+func (w *WatcherService) reconcileProjects(ctx context.Context, projects []*services.Project) {
+	desired := make(map[uuid.UUID]bool, len(projects))
+	for _, project := range projects {
+		if project.DesiredState != services.DesiredStateRunning {
+			continue
+		}
+		desired[project.ID] = true
+		w.reconcileProject(ctx, project)
+	}
+
+	// Projects that were stopped or removed start over
+	for projectID := range w.backoff {
+		if !desired[projectID] {
+			delete(w.backoff, projectID)
+		}
+	}
+}
+
+// reconcileProject brings a project that is desired running back up when its services are down, unless it is
+// backing off from an earlier attempt. The backoff is reset once the project is seen up after it elapsed.
+func (w *WatcherService) reconcileProject(ctx context.Context, project *services.Project) {
+	backoff := w.backoff[project.ID]
+	if backoff != nil && time.Now().Before(backoff.next) {
+		slog.Debug("Backing off from bringing project back up",
+			"project_id", project.ID,
+			"project_name", project.Name,
+			"attempts", backoff.attempts,
+			"next_attempt", backoff.next)
+		return
+	}
+
+	down, err := w.projectService.Reconcile(ctx, project.ID)
+	if errors.Is(err, services.ErrDeploymentInProgress) {
+		return
+	}
+	if err == nil && len(down) == 0 {
+		delete(w.backoff, project.ID)
+		return
+	}
+
+	if backoff == nil {
+		backoff = &reconcileBackoff{}
+		w.backoff[project.ID] = backoff
+	}
+	backoff.attempts++
+	backoff.next = time.Now().Add(backoff.delay())
+
+	if err != nil {
+		slog.Error("Failed to bring project back up",
+			"project_id", project.ID,
+			"project_name", project.Name,
+			"services", down,
+			"attempts", backoff.attempts,
+			"next_attempt", backoff.next,
+			"error", err)
+		return
+	}
+	slog.Info("Brought project back up",
+		"project_id", project.ID,
+		"project_name", project.Name,
+		"services", down,
+		"attempts", backoff.attempts)
+}
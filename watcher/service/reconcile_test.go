@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oar-cd/oar/services"
+)
+
+// createDesiredRunningProject creates a project that is meant to run, without automatic deployments
+func createDesiredRunningProject(name string, status services.ProjectStatus) *services.Project {
+	project := createTestProject(uuid.New(), name, status, false, "commit1")
+	project.DesiredState = services.DesiredStateRunning
+	return project
+}
+
+func TestReconcileBackoff_Delay(t *testing.T) {
+	var delays []time.Duration
+	for attempts := 1; attempts <= 8; attempts++ {
+		delays = append(delays, (&reconcileBackoff{attempts: attempts}).delay())
+	}
+	assert.Equal(t, []time.Duration{
+		time.Minute, 2 * time.Minute, 4 * time.Minute, 8 * time.Minute,
+		16 * time.Minute, 32 * time.Minute, time.Hour, time.Hour,
+	}, delays)
+}
+
+func TestWatcherService_reconcileProjects_OnlyDesiredRunning(t *testing.T) {
+	mockProjectService := &MockProjectManager{}
+	service := NewWatcherService(mockProjectService, nil, &MockGitExecutor{}, time.Minute, time.Hour)
+
+	up := createDesiredRunningProject("up", services.ProjectStatusRunning)
+	failed := createDesiredRunningProject("failed", services.ProjectStatusError)
+	stopped := createTestProject(uuid.New(), "stopped", services.ProjectStatusStopped, true, "commit2")
+	stopped.DesiredState = services.DesiredStateStopped
+	unrecorded := createTestProject(uuid.New(), "unrecorded", services.ProjectStatusError, true, "commit3")
+
+	mockProjectService.On("Reconcile", mock.Anything, up.ID).Return(nil, nil)
+	mockProjectService.On("Reconcile", mock.Anything, failed.ID).Return([]string{"web"}, nil)
+
+	service.reconcileProjects(context.Background(), []*services.Project{up, failed, stopped, unrecorded})
+
+	mockProjectService.AssertExpectations(t)
+	assert.NotContains(t, service.backoff, up.ID)
+	require.Contains(t, service.backoff, failed.ID)
+	assert.Equal(t, 1, service.backoff[failed.ID].attempts)
+
+	// Stopping the project forgets its backoff
+	failed.DesiredState = services.DesiredStateStopped
+	service.reconcileProjects(context.Background(), []*services.Project{up, failed, stopped, unrecorded})
+	assert.Empty(t, service.backoff)
+}
+
+func TestWatcherService_reconcileProject_BacksOff(t *testing.T) {
+	mockProjectService := &MockProjectManager{}
+	service := NewWatcherService(mockProjectService, nil, &MockGitExecutor{}, time.Minute, time.Hour)
+	project := createDesiredRunningProject("crashing", services.ProjectStatusRunning)
+
+	reconcile := mockProjectService.On("Reconcile", mock.Anything, project.ID).Return([]string{"web"}, nil)
+	service.reconcileProject(context.Background(), project)
+	require.Contains(t, service.backoff, project.ID)
+	backoff := service.backoff[project.ID]
+	assert.Equal(t, 1, backoff.attempts)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), backoff.next, time.Second)
+
+	// Not checked again until the backoff elapsed
+	service.reconcileProject(context.Background(), project)
+	mockProjectService.AssertNumberOfCalls(t, "Reconcile", 1)
+
+	// Down again, failing to come back up backs off longer
+	backoff.next = time.Now().Add(-time.Second)
+	reconcile.Return([]string{"web"}, assert.AnError)
+	service.reconcileProject(context.Background(), project)
+	assert.Equal(t, 2, backoff.attempts)
+	assert.WithinDuration(t, time.Now().Add(2*time.Minute), backoff.next, time.Second)
+
+	// Being deployed by someone else doesn't count as an attempt
+	backoff.next = time.Now().Add(-time.Second)
+	reconcile.Return(nil, services.ErrDeploymentInProgress)
+	service.reconcileProject(context.Background(), project)
+	assert.Equal(t, 2, backoff.attempts)
+
+	// Still up after the backoff elapsed
+	reconcile.Return(nil, nil)
+	service.reconcileProject(context.Background(), project)
+	assert.NotContains(t, service.backoff, project.ID)
+	mockProjectService.AssertNumberOfCalls(t, "Reconcile", 4)
+}
+
+func TestWatcherService_checkAllProjects_Reconciles(t *testing.T) {
+	mockProjectService := &MockProjectManager{}
+	service := NewWatcherService(mockProjectService, nil, &MockGitExecutor{}, time.Minute, time.Hour)
+
+	// The host rebooted, the project is recorded as running but its containers are down
+	project := createDesiredRunningProject("rebooted", services.ProjectStatusRunning)
+	mockProjectService.On("List").Return([]*services.Project{project}, nil)
+	mockProjectService.On("Reconcile", mock.Anything, project.ID).Return([]string{"db", "web"}, nil)
+	mockProjectService.On("DetectDrift", mock.Anything, project.ID).Return(nil, nil)
+
+	assert.NoError(t, service.checkAllProjects(context.Background()))
+	mockProjectService.AssertExpectations(t)
+	assert.Contains(t, service.backoff, project.ID)
+}
@@ -3,38 +3,52 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"time"
 
+	"github.com/google/uuid"
+
 	"github.com/oar-cd/oar/services"
 )
 
 type WatcherService struct {
 	projectService services.ProjectManager
+	catalogService services.CatalogManager // Nil if catalogs are not synced
 	gitService     services.GitExecutor
 	pollInterval   time.Duration
+	backoff        map[uuid.UUID]*reconcileBackoff // Projects recently brought back up, see reconcileProject
+
+	imagePollInterval time.Duration
+	imagesCheckedAt   time.Time // When images were last checked, see checkImages
 }
 
 func NewWatcherService(
 	projectService services.ProjectManager,
+	catalogService services.CatalogManager,
 	gitService services.GitExecutor,
 	pollInterval time.Duration,
+	imagePollInterval time.Duration,
 ) *WatcherService {
 	return &WatcherService{
-		projectService: projectService,
-		gitService:     gitService,
-		pollInterval:   pollInterval,
+		projectService:    projectService,
+		catalogService:    catalogService,
+		gitService:        gitService,
+		pollInterval:      pollInterval,
+		backoff:           make(map[uuid.UUID]*reconcileBackoff),
+		imagePollInterval: imagePollInterval,
 	}
 }
 
 func (w *WatcherService) Start(ctx context.Context) error {
-	slog.Info("Watcher service starting", "poll_interval", w.pollInterval)
+	slog.Info("Watcher service starting", "poll_interval", w.pollInterval, "image_poll_interval", w.imagePollInterval)
 
 	ticker := time.NewTicker(w.pollInterval)
 	defer ticker.Stop()
 
 	// Run initial check immediately
+	w.syncCatalogs(ctx)
 	if err := w.checkAllProjects(ctx); err != nil {
 		slog.Error("Initial project check failed", "error", err)
 	}
@@ -45,6 +59,7 @@ func (w *WatcherService) Start(ctx context.Context) error {
 			slog.Info("Watcher service shutting down")
 			return nil
 		case <-ticker.C:
+			w.syncCatalogs(ctx)
 			if err := w.checkAllProjects(ctx); err != nil {
 				slog.Error("Project check failed", "error", err)
 			}
@@ -52,8 +67,31 @@ func (w *WatcherService) Start(ctx context.Context) error {
 	}
 }
 
+// syncCatalogs syncs every catalog, so that the projects they list are created, updated and removed before the
+// projects are checked
+func (w *WatcherService) syncCatalogs(ctx context.Context) {
+	if w.catalogService == nil {
+		return
+	}
+
+	catalogs, err := w.catalogService.List()
+	if err != nil {
+		slog.Error("Failed to list catalogs", "error", err)
+		return
+	}
+	for _, catalog := range catalogs {
+		if ctx.Err() != nil {
+			return
+		}
+		// Failures are recorded on the catalog and logged by the sync itself
+		_, _ = w.catalogService.Sync(ctx, catalog.ID)
+	}
+}
+
 func (w *WatcherService) checkAllProjects(ctx context.Context) error {
 	slog.Debug("Starting project check cycle")
+	start := time.Now()
+	defer func() { services.ObserveWatcherPoll(time.Since(start)) }()
 
 	projects, err := w.projectService.List()
 	if err != nil {
@@ -63,7 +101,7 @@ func (w *WatcherService) checkAllProjects(ctx context.Context) error {
 	watcherEnabledCount := 0
 	activeProjectsChecked := 0
 	for _, project := range projects {
-		if project.WatcherEnabled {
+		if services.AutomaticDeployments(project) {
 			slog.Info("Checking project",
 				"project_id", project.ID,
 				"project_name", project.Name,
@@ -95,6 +133,23 @@ func (w *WatcherService) checkAllProjects(ctx context.Context) error {
 		}
 	}
 
+	// Projects whose services went down are brought back up at the commit they run, after any deployment above
+	w.reconcileProjects(ctx, projects)
+
+	// Projects watching their images are redeployed when the registry has new digests, less often than Git is
+	// checked, since registries limit how often they can be asked
+	if time.Since(w.imagesCheckedAt) >= w.imagePollInterval {
+		w.imagesCheckedAt = time.Now()
+		w.checkImages(ctx, projects)
+	}
+
+	// Drift is checked for every running project, also for those deployed by hand, after any deployment above
+	for _, project := range projects {
+		if project.Status == services.ProjectStatusRunning {
+			w.checkDrift(ctx, project)
+		}
+	}
+
 	slog.Debug("Project check cycle completed",
 		"total_projects", len(projects),
 		"watcher_enabled", watcherEnabledCount,
@@ -103,23 +158,43 @@ func (w *WatcherService) checkAllProjects(ctx context.Context) error {
 	return nil
 }
 
-func (w *WatcherService) checkProject(ctx context.Context, project *services.Project) error {
-	currentCommit := project.LastCommitStr()
-
-	gitDir, err := project.GitDir()
-	if err != nil {
-		return fmt.Errorf("failed to get git directory: %w", err)
+// checkImages redeploys the running projects that watch their images and whose containers run older images than
+// the registry has
+func (w *WatcherService) checkImages(ctx context.Context, projects []*services.Project) {
+	for _, project := range projects {
+		if !project.WatchImages || project.Status != services.ProjectStatusRunning {
+			continue
+		}
+		_, err := w.projectService.CheckImages(ctx, project.ID)
+		if err != nil && !errors.Is(err, services.ErrDeploymentInProgress) &&
+			!errors.Is(err, services.ErrCommitRejected) {
+			slog.Error("Failed to check images",
+				"project_id", project.ID,
+				"project_name", project.Name,
+				"error", err)
+		}
 	}
+}
 
-	// Fetch latest changes from remote
-	if err := w.gitService.Fetch(project.GitBranch, project.GitAuth, gitDir); err != nil {
-		return fmt.Errorf("failed to fetch from remote: %w", err)
+// checkDrift compares the containers of a running project with its deployed commit, which records whether it is
+// out of sync and notifies when it goes out of sync
+func (w *WatcherService) checkDrift(ctx context.Context, project *services.Project) {
+	_, err := w.projectService.DetectDrift(ctx, project.ID)
+	if err != nil && !errors.Is(err, services.ErrDeploymentInProgress) {
+		slog.Error("Failed to detect drift",
+			"project_id", project.ID,
+			"project_name", project.Name,
+			"error", err)
 	}
+}
 
-	// Get latest commit hash from remote
-	remoteCommit, err := w.gitService.GetRemoteLatestCommit(gitDir, project.GitBranch)
+func (w *WatcherService) checkProject(ctx context.Context, project *services.Project) error {
+	currentCommit := project.LastCommitStr()
+
+	// Fetch from remote and resolve the commit the project's branch, tag pattern or pinned commit points to
+	remoteCommit, remoteRef, err := services.FetchTargetCommit(w.gitService, project)
 	if err != nil {
-		return fmt.Errorf("failed to get remote commit: %w", err)
+		return err
 	}
 
 	// Log git check results
@@ -127,9 +202,25 @@ func (w *WatcherService) checkProject(ctx context.Context, project *services.Pro
 		"project_id", project.ID,
 		"project_name", project.Name,
 		"current_commit", currentCommit,
+		"remote_ref", remoteRef,
 		"remote_commit", remoteCommit,
 		"has_updates", currentCommit != remoteCommit)
 
+	// A rolled back commit is not redeployed until a newer one is pushed
+	if currentCommit != remoteCommit && project.RolledBackCommitStr() == remoteCommit {
+		slog.Info("Remote commit was rolled back, skipping automatic deployment",
+			"project_id", project.ID,
+			"project_name", project.Name,
+			"current_commit", currentCommit,
+			"rolled_back_commit", remoteCommit)
+		return nil
+	}
+
+	// Projects that require approval get a pending deployment of the commit instead
+	if currentCommit != remoteCommit && project.RequireApproval {
+		return w.requestApproval(project, remoteCommit)
+	}
+
 	// Compare with current commit
 	if currentCommit != remoteCommit {
 		slog.Info("New commit detected, triggering automatic deployment",
@@ -140,36 +231,30 @@ func (w *WatcherService) checkProject(ctx context.Context, project *services.Pro
 
 		// TODO: Consider creating a dedicated method for automatic deployments
 		// instead of using DeployPiping(). This would allow for:
-		// - Better logging/tracking of automatic vs manual deployments
 		// - Different error handling strategies
 		// - Deployment throttling/rate limiting
 		// - Automatic deployment-specific configuration
-		if err := w.projectService.DeployPiping(project.ID, true); err != nil {
-			slog.Error("Automatic deployment failed",
+		options := services.DeployOptions{Pull: true, Trigger: services.DeploymentTriggerWatcher}
+		err := w.projectService.DeployPiping(ctx, project.ID, options)
+		if errors.Is(err, services.ErrDeploymentInProgress) {
+			// Deployed by someone else right now, the next poll deploys the commit if it is still missing
+			slog.Info("Project is being deployed, skipping automatic deployment",
 				"project_id", project.ID,
 				"project_name", project.Name,
 				"target_commit", remoteCommit,
-				"error", err)
-			return fmt.Errorf("failed to deploy project: %w", err)
+				"reason", err)
+			return nil
 		}
-
-		// Update the project's LastCommit to the newly deployed commit
-		project.LastCommit = &remoteCommit
-		if err := w.projectService.Update(project); err != nil {
-			slog.Error("Failed to update project LastCommit after successful deployment",
+		if err != nil {
+			slog.Error("Automatic deployment failed",
 				"project_id", project.ID,
 				"project_name", project.Name,
-				"deployed_commit", remoteCommit,
+				"target_commit", remoteCommit,
 				"error", err)
-			// Don't return error here as deployment was successful
-			// This is just a tracking issue that won't break functionality
-		} else {
-			slog.Debug("Updated project LastCommit after successful deployment",
-				"project_id", project.ID,
-				"project_name", project.Name,
-				"deployed_commit", remoteCommit)
+			return fmt.Errorf("failed to deploy project: %w", err)
 		}
 
+		// The deployment recorded the deployed commit with the project, which is stale by now and not saved
 		slog.Info("Automatic deployment completed successfully",
 			"project_id", project.ID,
 			"project_name", project.Name,
@@ -178,3 +263,29 @@ func (w *WatcherService) checkProject(ctx context.Context, project *services.Pro
 
 	return nil
 }
+
+// requestApproval records a pending deployment of a new commit of a project that requires approval. Commits that
+// are already pending or were rejected are left as they are.
+func (w *WatcherService) requestApproval(project *services.Project, commit string) error {
+	options := services.DeployOptions{Trigger: services.DeploymentTriggerWatcher}
+	deployment, err := w.projectService.RequestApproval(project.ID, commit, options)
+	if errors.Is(err, services.ErrCommitRejected) || errors.Is(err, services.ErrDeploymentInProgress) {
+		slog.Info("Skipping approval request",
+			"project_id", project.ID,
+			"project_name", project.Name,
+			"target_commit", commit,
+			"reason", err)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to request approval: %w", err)
+	}
+	if deployment != nil {
+		slog.Debug("Deployment awaiting approval",
+			"project_id", project.ID,
+			"project_name", project.Name,
+			"deployment_id", deployment.ID,
+			"target_commit", commit)
+	}
+	return nil
+}
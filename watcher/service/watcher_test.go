@@ -2,6 +2,10 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -42,16 +46,93 @@ func (m *MockProjectManager) Remove(projectID uuid.UUID) error {
 	return args.Error(0)
 }
 
-func (m *MockProjectManager) DeployStreaming(projectID uuid.UUID, pull bool, outputChan chan<- string) error {
-	args := m.Called(projectID, pull, outputChan)
+func (m *MockProjectManager) DeployStreaming(
+	ctx context.Context,
+	projectID uuid.UUID,
+	options services.DeployOptions,
+	outputChan chan<- string,
+) error {
+	args := m.Called(projectID, options, outputChan)
+	return args.Error(0)
+}
+
+func (m *MockProjectManager) DeployPiping(
+	ctx context.Context,
+	projectID uuid.UUID,
+	options services.DeployOptions,
+) error {
+	args := m.Called(projectID, options)
+	return args.Error(0)
+}
+
+func (m *MockProjectManager) RollbackStreaming(
+	ctx context.Context,
+	projectID uuid.UUID,
+	target string,
+	options services.DeployOptions,
+	outputChan chan<- string,
+) error {
+	args := m.Called(projectID, target, options, outputChan)
+	return args.Error(0)
+}
+
+func (m *MockProjectManager) RollbackPiping(
+	ctx context.Context,
+	projectID uuid.UUID,
+	target string,
+	options services.DeployOptions,
+) error {
+	args := m.Called(projectID, target, options)
+	return args.Error(0)
+}
+
+func (m *MockProjectManager) RequestApproval(
+	projectID uuid.UUID,
+	commit string,
+	options services.DeployOptions,
+) (*services.Deployment, error) {
+	args := m.Called(projectID, commit, options)
+	deployment, _ := args.Get(0).(*services.Deployment)
+	return deployment, args.Error(1)
+}
+
+func (m *MockProjectManager) ApproveStreaming(
+	ctx context.Context,
+	projectID uuid.UUID,
+	deploymentID uuid.UUID,
+	options services.DeployOptions,
+	outputChan chan<- string,
+) error {
+	args := m.Called(projectID, deploymentID, options, outputChan)
+	return args.Error(0)
+}
+
+func (m *MockProjectManager) ApprovePiping(
+	ctx context.Context,
+	projectID uuid.UUID,
+	deploymentID uuid.UUID,
+	options services.DeployOptions,
+) error {
+	args := m.Called(projectID, deploymentID, options)
+	return args.Error(0)
+}
+
+func (m *MockProjectManager) RejectDeployment(projectID, deploymentID uuid.UUID, options services.DeployOptions) error {
+	args := m.Called(projectID, deploymentID, options)
 	return args.Error(0)
 }
 
-func (m *MockProjectManager) DeployPiping(projectID uuid.UUID, pull bool) error {
-	args := m.Called(projectID, pull)
+func (m *MockProjectManager) CancelDeployment(projectID uuid.UUID) error {
+	args := m.Called(projectID)
 	return args.Error(0)
 }
 
+func (m *MockProjectManager) LockProject(projectID uuid.UUID, options services.DeployOptions) (func(), error) {
+	args := m.Called(projectID, options)
+	unlock, _ := args.Get(0).(func())
+	return unlock, args.Error(1)
+}
+
 func (m *MockProjectManager) Stop(projectID uuid.UUID) error {
 	args := m.Called(projectID)
 	return args.Error(0)
@@ -87,12 +168,75 @@ func (m *MockProjectManager) GetStatus(projectID uuid.UUID) (*services.ComposeSt
 	return args.Get(0).(*services.ComposeStatus), args.Error(1)
 }
 
+func (m *MockProjectManager) DetectDrift(ctx context.Context, projectID uuid.UUID) ([]services.ServiceDrift, error) {
+	args := m.Called(ctx, projectID)
+	drift, _ := args.Get(0).([]services.ServiceDrift)
+	return drift, args.Error(1)
+}
+
+func (m *MockProjectManager) Reconcile(ctx context.Context, projectID uuid.UUID) ([]string, error) {
+	args := m.Called(ctx, projectID)
+	down, _ := args.Get(0).([]string)
+	return down, args.Error(1)
+}
+
+func (m *MockProjectManager) CheckImages(ctx context.Context, projectID uuid.UUID) ([]services.ImageUpdate, error) {
+	args := m.Called(ctx, projectID)
+	updates, _ := args.Get(0).([]services.ImageUpdate)
+	return updates, args.Error(1)
+}
+
+func (m *MockProjectManager) PreviewDeploy(
+	ctx context.Context,
+	projectID uuid.UUID,
+	options services.DeployOptions,
+) (*services.DeployPreview, error) {
+	args := m.Called(ctx, projectID, options)
+	preview, _ := args.Get(0).(*services.DeployPreview)
+	return preview, args.Error(1)
+}
+
 func (m *MockProjectManager) ListDeployments(projectID uuid.UUID) ([]*services.Deployment, error) {
 	args := m.Called(projectID)
 	return args.Get(0).([]*services.Deployment), args.Error(1)
 }
 
 // MockGitExecutor implements services.GitExecutor interface for testing
+// MockCatalogManager implements services.CatalogManager interface for testing
+type MockCatalogManager struct {
+	mock.Mock
+}
+
+func (m *MockCatalogManager) List() ([]*services.Catalog, error) {
+	args := m.Called()
+	return args.Get(0).([]*services.Catalog), args.Error(1)
+}
+
+func (m *MockCatalogManager) Get(id uuid.UUID) (*services.Catalog, error) {
+	args := m.Called(id)
+	return args.Get(0).(*services.Catalog), args.Error(1)
+}
+
+func (m *MockCatalogManager) Create(catalog *services.Catalog) (*services.Catalog, error) {
+	args := m.Called(catalog)
+	return args.Get(0).(*services.Catalog), args.Error(1)
+}
+
+func (m *MockCatalogManager) Update(catalog *services.Catalog) error {
+	args := m.Called(catalog)
+	return args.Error(0)
+}
+
+func (m *MockCatalogManager) Remove(id uuid.UUID, removeProjects bool) error {
+	args := m.Called(id, removeProjects)
+	return args.Error(0)
+}
+
+func (m *MockCatalogManager) Sync(ctx context.Context, id uuid.UUID) (*services.CatalogSyncResult, error) {
+	args := m.Called(id)
+	return args.Get(0).(*services.CatalogSyncResult), args.Error(1)
+}
+
 type MockGitExecutor struct {
 	mock.Mock
 }
@@ -127,6 +271,31 @@ func (m *MockGitExecutor) GetRemoteLatestCommit(workingDir string, gitBranch str
 	return args.String(0), args.Error(1)
 }
 
+func (m *MockGitExecutor) FetchTags(gitAuth *services.GitAuthConfig, workingDir string) error {
+	args := m.Called(gitAuth, workingDir)
+	return args.Error(0)
+}
+
+func (m *MockGitExecutor) ListTags(workingDir string) (map[string]string, error) {
+	args := m.Called(workingDir)
+	return args.Get(0).(map[string]string), args.Error(1)
+}
+
+func (m *MockGitExecutor) Checkout(commit string, workingDir string) error {
+	args := m.Called(commit, workingDir)
+	return args.Error(0)
+}
+
+func (m *MockGitExecutor) Log(workingDir, from, to string, limit int) ([]services.GitCommit, error) {
+	args := m.Called(workingDir, from, to, limit)
+	return args.Get(0).([]services.GitCommit), args.Error(1)
+}
+
+func (m *MockGitExecutor) Export(commit, workingDir, dir string) error {
+	args := m.Called(commit, workingDir, dir)
+	return args.Error(0)
+}
+
 func (m *MockGitExecutor) TestAuthentication(gitURL string, gitAuth *services.GitAuthConfig) error {
 	args := m.Called(gitURL, gitAuth)
 	return args.Error(0)
@@ -163,12 +332,15 @@ func createTestProject(
 	}
 }
 
+// watcherDeployOptions are the options of automatic deployments
+var watcherDeployOptions = services.DeployOptions{Pull: true, Trigger: services.DeploymentTriggerWatcher}
+
 func TestNewWatcherService(t *testing.T) {
 	mockProjectService := &MockProjectManager{}
 	mockGitService := &MockGitExecutor{}
 	pollInterval := 5 * time.Minute
 
-	service := NewWatcherService(mockProjectService, mockGitService, pollInterval)
+	service := NewWatcherService(mockProjectService, nil, mockGitService, pollInterval, time.Hour)
 
 	assert.NotNil(t, service)
 	assert.Equal(t, mockProjectService, service.projectService)
@@ -184,7 +356,7 @@ func TestWatcherService_Start_ContextCancellation(t *testing.T) {
 	// Mock empty project list for initial check
 	mockProjectService.On("List").Return([]*services.Project{}, nil)
 
-	service := NewWatcherService(mockProjectService, mockGitService, pollInterval)
+	service := NewWatcherService(mockProjectService, nil, mockGitService, pollInterval, time.Hour)
 
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -202,10 +374,27 @@ func TestWatcherService_Start_ContextCancellation(t *testing.T) {
 	mockProjectService.AssertExpectations(t)
 }
 
+func TestWatcherService_syncCatalogs(t *testing.T) {
+	mockProjectService := &MockProjectManager{}
+	mockCatalogService := &MockCatalogManager{}
+	service := NewWatcherService(mockProjectService, mockCatalogService, &MockGitExecutor{}, time.Minute, time.Hour)
+
+	failing := &services.Catalog{ID: uuid.New(), Name: "failing"}
+	syncing := &services.Catalog{ID: uuid.New(), Name: "syncing"}
+	mockCatalogService.On("List").Return([]*services.Catalog{failing, syncing}, nil)
+	mockCatalogService.On("Sync", failing.ID).Return((*services.CatalogSyncResult)(nil), errors.New("pull failed"))
+	mockCatalogService.On("Sync", syncing.ID).Return(&services.CatalogSyncResult{Created: []string{"web"}}, nil)
+
+	// A catalog that fails to sync doesn't stop the others
+	service.syncCatalogs(context.Background())
+
+	mockCatalogService.AssertExpectations(t)
+}
+
 func TestWatcherService_checkAllProjects_EmptyList(t *testing.T) {
 	mockProjectService := &MockProjectManager{}
 	mockGitService := &MockGitExecutor{}
-	service := NewWatcherService(mockProjectService, mockGitService, time.Minute)
+	service := NewWatcherService(mockProjectService, nil, mockGitService, time.Minute, time.Hour)
 
 	mockProjectService.On("List").Return([]*services.Project{}, nil)
 
@@ -217,7 +406,7 @@ func TestWatcherService_checkAllProjects_EmptyList(t *testing.T) {
 func TestWatcherService_checkAllProjects_MixedProjects(t *testing.T) {
 	mockProjectService := &MockProjectManager{}
 	mockGitService := &MockGitExecutor{}
-	service := NewWatcherService(mockProjectService, mockGitService, time.Minute)
+	service := NewWatcherService(mockProjectService, nil, mockGitService, time.Minute, time.Hour)
 
 	projects := []*services.Project{
 		createTestProject(uuid.New(), "running-with-watcher", services.ProjectStatusRunning, true, "commit1"),
@@ -234,6 +423,10 @@ func TestWatcherService_checkAllProjects_MixedProjects(t *testing.T) {
 	mockGitService.On("GetRemoteLatestCommit", "/tmp/test-project-running-with-watcher/git", "main").
 		Return("commit1", nil)
 
+	// Drift is detected for both running projects
+	mockProjectService.On("DetectDrift", mock.Anything, projects[0].ID).Return(nil, nil)
+	mockProjectService.On("DetectDrift", mock.Anything, projects[2].ID).Return(nil, nil)
+
 	err := service.checkAllProjects(context.Background())
 	assert.NoError(t, err)
 
@@ -241,10 +434,68 @@ func TestWatcherService_checkAllProjects_MixedProjects(t *testing.T) {
 	mockGitService.AssertExpectations(t)
 }
 
+func TestWatcherService_checkAllProjects_DriftErrors(t *testing.T) {
+	mockProjectService := &MockProjectManager{}
+	mockGitService := &MockGitExecutor{}
+	service := NewWatcherService(mockProjectService, nil, mockGitService, time.Minute, time.Hour)
+
+	projects := []*services.Project{
+		createTestProject(uuid.New(), "deploying", services.ProjectStatusRunning, false, "commit1"),
+		createTestProject(uuid.New(), "failing", services.ProjectStatusRunning, false, "commit2"),
+		createTestProject(uuid.New(), "drifted", services.ProjectStatusRunning, false, "commit3"),
+	}
+	mockProjectService.On("List").Return(projects, nil)
+
+	// A failing check doesn't keep the others from running
+	mockProjectService.On("DetectDrift", mock.Anything, projects[0].ID).Return(nil, services.ErrDeploymentInProgress)
+	mockProjectService.On("DetectDrift", mock.Anything, projects[1].ID).Return(nil, assert.AnError)
+	mockProjectService.On("DetectDrift", mock.Anything, projects[2].ID).Return(
+		[]services.ServiceDrift{{Service: "web", Kind: services.DriftKindMissing}}, nil)
+
+	err := service.checkAllProjects(context.Background())
+	assert.NoError(t, err)
+	mockProjectService.AssertExpectations(t)
+}
+
+func TestWatcherService_checkAllProjects_Images(t *testing.T) {
+	mockProjectService := &MockProjectManager{}
+	mockGitService := &MockGitExecutor{}
+	service := NewWatcherService(mockProjectService, nil, mockGitService, time.Minute, time.Hour)
+
+	projects := []*services.Project{
+		createTestProject(uuid.New(), "watching", services.ProjectStatusRunning, false, "commit1"),
+		createTestProject(uuid.New(), "failing", services.ProjectStatusRunning, false, "commit2"),
+		createTestProject(uuid.New(), "stopped", services.ProjectStatusStopped, false, "commit3"),
+		createTestProject(uuid.New(), "not-watching", services.ProjectStatusRunning, false, "commit4"),
+	}
+	for _, project := range projects[:3] {
+		project.WatchImages = true
+	}
+	mockProjectService.On("List").Return(projects, nil)
+	mockProjectService.On("DetectDrift", mock.Anything, mock.Anything).Return(nil, nil)
+
+	// A failing check doesn't keep the others from running
+	mockProjectService.On("CheckImages", mock.Anything, projects[0].ID).Return(
+		[]services.ImageUpdate{{Service: "web", Image: "nginx:latest", Latest: "sha256:2222"}}, nil).Once()
+	mockProjectService.On("CheckImages", mock.Anything, projects[1].ID).Return(nil, assert.AnError).Once()
+
+	assert.NoError(t, service.checkAllProjects(context.Background()))
+	mockProjectService.AssertExpectations(t)
+
+	// Images aren't checked again until the image poll interval has passed
+	assert.NoError(t, service.checkAllProjects(context.Background()))
+	mockProjectService.AssertNumberOfCalls(t, "CheckImages", 2)
+
+	service.imagesCheckedAt = time.Now().Add(-time.Hour)
+	mockProjectService.On("CheckImages", mock.Anything, mock.Anything).Return(nil, nil)
+	assert.NoError(t, service.checkAllProjects(context.Background()))
+	mockProjectService.AssertNumberOfCalls(t, "CheckImages", 4)
+}
+
 func TestWatcherService_checkProject_NoChanges(t *testing.T) {
 	mockProjectService := &MockProjectManager{}
 	mockGitService := &MockGitExecutor{}
-	service := NewWatcherService(mockProjectService, mockGitService, time.Minute)
+	service := NewWatcherService(mockProjectService, nil, mockGitService, time.Minute, time.Hour)
 
 	project := createTestProject(uuid.New(), "test-project", services.ProjectStatusRunning, true, "commit1")
 
@@ -262,16 +513,55 @@ func TestWatcherService_checkProject_NoChanges(t *testing.T) {
 func TestWatcherService_checkProject_WithChanges(t *testing.T) {
 	mockProjectService := &MockProjectManager{}
 	mockGitService := &MockGitExecutor{}
-	service := NewWatcherService(mockProjectService, mockGitService, time.Minute)
+	service := NewWatcherService(mockProjectService, nil, mockGitService, time.Minute, time.Hour)
+
+	project := createTestProject(uuid.New(), "test-project", services.ProjectStatusRunning, true, "commit1")
+
+	mockGitService.On("Fetch", "main", (*services.GitAuthConfig)(nil), "/tmp/test-project-test-project/git").Return(nil)
+	mockGitService.On("GetRemoteLatestCommit", "/tmp/test-project-test-project/git", "main").Return("commit2", nil)
+	mockProjectService.On("DeployPiping", project.ID, watcherDeployOptions).Return(nil)
+
+	err := service.checkProject(context.Background(), project)
+	assert.NoError(t, err)
+
+	mockGitService.AssertExpectations(t)
+	mockProjectService.AssertExpectations(t)
+	// The deployment records the commit, saving the project loaded before it would overwrite what it recorded
+	mockProjectService.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestWatcherService_checkProject_RolledBackCommit(t *testing.T) {
+	mockProjectService := &MockProjectManager{}
+	mockGitService := &MockGitExecutor{}
+	service := NewWatcherService(mockProjectService, nil, mockGitService, time.Minute, time.Hour)
 
 	project := createTestProject(uuid.New(), "test-project", services.ProjectStatusRunning, true, "commit1")
+	rolledBackCommit := "commit2"
+	project.RolledBackCommit = &rolledBackCommit
 
 	mockGitService.On("Fetch", "main", (*services.GitAuthConfig)(nil), "/tmp/test-project-test-project/git").Return(nil)
 	mockGitService.On("GetRemoteLatestCommit", "/tmp/test-project-test-project/git", "main").Return("commit2", nil)
-	mockProjectService.On("DeployPiping", project.ID, true).Return(nil)
-	mockProjectService.On("Update", mock.MatchedBy(func(p *services.Project) bool {
-		return p.ID == project.ID && p.LastCommit != nil && *p.LastCommit == "commit2"
-	})).Return(nil)
+
+	err := service.checkProject(context.Background(), project)
+	assert.NoError(t, err)
+
+	mockGitService.AssertExpectations(t)
+	// DeployPiping should not be called since the remote commit was rolled back
+	mockProjectService.AssertNotCalled(t, "DeployPiping")
+}
+
+func TestWatcherService_checkProject_NewCommitAfterRollback(t *testing.T) {
+	mockProjectService := &MockProjectManager{}
+	mockGitService := &MockGitExecutor{}
+	service := NewWatcherService(mockProjectService, nil, mockGitService, time.Minute, time.Hour)
+
+	project := createTestProject(uuid.New(), "test-project", services.ProjectStatusRunning, true, "commit1")
+	rolledBackCommit := "commit2"
+	project.RolledBackCommit = &rolledBackCommit
+
+	mockGitService.On("Fetch", "main", (*services.GitAuthConfig)(nil), "/tmp/test-project-test-project/git").Return(nil)
+	mockGitService.On("GetRemoteLatestCommit", "/tmp/test-project-test-project/git", "main").Return("commit3", nil)
+	mockProjectService.On("DeployPiping", project.ID, watcherDeployOptions).Return(nil)
 
 	err := service.checkProject(context.Background(), project)
 	assert.NoError(t, err)
@@ -283,7 +573,7 @@ func TestWatcherService_checkProject_WithChanges(t *testing.T) {
 func TestWatcherService_checkProject_FetchError(t *testing.T) {
 	mockProjectService := &MockProjectManager{}
 	mockGitService := &MockGitExecutor{}
-	service := NewWatcherService(mockProjectService, mockGitService, time.Minute)
+	service := NewWatcherService(mockProjectService, nil, mockGitService, time.Minute, time.Hour)
 
 	project := createTestProject(uuid.New(), "test-project", services.ProjectStatusRunning, true, "commit1")
 
@@ -301,7 +591,7 @@ func TestWatcherService_checkProject_FetchError(t *testing.T) {
 func TestWatcherService_checkProject_GetRemoteCommitError(t *testing.T) {
 	mockProjectService := &MockProjectManager{}
 	mockGitService := &MockGitExecutor{}
-	service := NewWatcherService(mockProjectService, mockGitService, time.Minute)
+	service := NewWatcherService(mockProjectService, nil, mockGitService, time.Minute, time.Hour)
 
 	project := createTestProject(uuid.New(), "test-project", services.ProjectStatusRunning, true, "commit1")
 
@@ -319,13 +609,13 @@ func TestWatcherService_checkProject_GetRemoteCommitError(t *testing.T) {
 func TestWatcherService_checkProject_DeploymentError(t *testing.T) {
 	mockProjectService := &MockProjectManager{}
 	mockGitService := &MockGitExecutor{}
-	service := NewWatcherService(mockProjectService, mockGitService, time.Minute)
+	service := NewWatcherService(mockProjectService, nil, mockGitService, time.Minute, time.Hour)
 
 	project := createTestProject(uuid.New(), "test-project", services.ProjectStatusRunning, true, "commit1")
 
 	mockGitService.On("Fetch", "main", (*services.GitAuthConfig)(nil), "/tmp/test-project-test-project/git").Return(nil)
 	mockGitService.On("GetRemoteLatestCommit", "/tmp/test-project-test-project/git", "main").Return("commit2", nil)
-	mockProjectService.On("DeployPiping", project.ID, true).Return(assert.AnError)
+	mockProjectService.On("DeployPiping", project.ID, watcherDeployOptions).Return(assert.AnError)
 
 	err := service.checkProject(context.Background(), project)
 	assert.Error(t, err)
@@ -335,32 +625,62 @@ func TestWatcherService_checkProject_DeploymentError(t *testing.T) {
 	mockProjectService.AssertExpectations(t)
 }
 
-func TestWatcherService_checkProject_UpdateError(t *testing.T) {
+func TestWatcherService_checkProject_DeploymentInProgress(t *testing.T) {
+	mockProjectService := &MockProjectManager{}
+	mockGitService := &MockGitExecutor{}
+	service := NewWatcherService(mockProjectService, nil, mockGitService, time.Minute, time.Hour)
+
+	project := createTestProject(uuid.New(), "test-project", services.ProjectStatusRunning, true, "commit1")
+
+	mockGitService.On("Fetch", "main", (*services.GitAuthConfig)(nil), "/tmp/test-project-test-project/git").Return(nil)
+	mockGitService.On("GetRemoteLatestCommit", "/tmp/test-project-test-project/git", "main").Return("commit2", nil)
+	mockProjectService.On("DeployPiping", project.ID, watcherDeployOptions).
+		Return(fmt.Errorf("%w: started by web", services.ErrDeploymentInProgress))
+
+	// Another process is deploying the project, the next poll picks up the change if it is still needed
+	err := service.checkProject(context.Background(), project)
+	assert.NoError(t, err)
+
+	mockGitService.AssertExpectations(t)
+	mockProjectService.AssertExpectations(t)
+	mockProjectService.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestWatcherService_checkProject_RequireApproval(t *testing.T) {
 	mockProjectService := &MockProjectManager{}
 	mockGitService := &MockGitExecutor{}
-	service := NewWatcherService(mockProjectService, mockGitService, time.Minute)
+	service := NewWatcherService(mockProjectService, nil, mockGitService, time.Minute, time.Hour)
 
 	project := createTestProject(uuid.New(), "test-project", services.ProjectStatusRunning, true, "commit1")
+	project.RequireApproval = true
 
 	mockGitService.On("Fetch", "main", (*services.GitAuthConfig)(nil), "/tmp/test-project-test-project/git").Return(nil)
 	mockGitService.On("GetRemoteLatestCommit", "/tmp/test-project-test-project/git", "main").Return("commit2", nil)
-	mockProjectService.On("DeployPiping", project.ID, true).Return(nil)
-	mockProjectService.On("Update", mock.MatchedBy(func(p *services.Project) bool {
-		return p.ID == project.ID && p.LastCommit != nil && *p.LastCommit == "commit2"
-	})).Return(assert.AnError)
+	options := services.DeployOptions{Trigger: services.DeploymentTriggerWatcher}
+	mockProjectService.On("RequestApproval", project.ID, "commit2", options).
+		Return(&services.Deployment{ID: uuid.New(), Status: services.DeploymentStatusPending}, nil).Once()
 
-	// Should not fail even if update fails - deployment was successful
+	// The commit waits for approval instead of being deployed
 	err := service.checkProject(context.Background(), project)
 	assert.NoError(t, err)
+	assert.Equal(t, "commit1", project.LastCommitStr())
+
+	// A rejected commit isn't asked about again
+	mockProjectService.On("RequestApproval", project.ID, "commit2", options).
+		Return(nil, fmt.Errorf("%w: commit2", services.ErrCommitRejected)).Once()
+	err = service.checkProject(context.Background(), project)
+	assert.NoError(t, err)
 
 	mockGitService.AssertExpectations(t)
 	mockProjectService.AssertExpectations(t)
+	mockProjectService.AssertNotCalled(t, "DeployPiping", mock.Anything, mock.Anything)
+	mockProjectService.AssertNotCalled(t, "Update", mock.Anything)
 }
 
 func TestWatcherService_checkAllProjects_ListError(t *testing.T) {
 	mockProjectService := &MockProjectManager{}
 	mockGitService := &MockGitExecutor{}
-	service := NewWatcherService(mockProjectService, mockGitService, time.Minute)
+	service := NewWatcherService(mockProjectService, nil, mockGitService, time.Minute, time.Hour)
 
 	mockProjectService.On("List").Return([]*services.Project{}, assert.AnError)
 
@@ -377,7 +697,7 @@ func TestWatcherService_Start_WithPolling(t *testing.T) {
 
 	// Use a very short poll interval for testing
 	pollInterval := 50 * time.Millisecond
-	service := NewWatcherService(mockProjectService, mockGitService, pollInterval)
+	service := NewWatcherService(mockProjectService, nil, mockGitService, pollInterval, time.Hour)
 
 	// Mock will be called multiple times
 	mockProjectService.On("List").Return([]*services.Project{}, nil)
@@ -395,7 +715,7 @@ func TestWatcherService_Start_WithPolling(t *testing.T) {
 func TestWatcherService_checkProject_WithGitAuth(t *testing.T) {
 	mockProjectService := &MockProjectManager{}
 	mockGitService := &MockGitExecutor{}
-	service := NewWatcherService(mockProjectService, mockGitService, time.Minute)
+	service := NewWatcherService(mockProjectService, nil, mockGitService, time.Minute, time.Hour)
 
 	gitAuth := &services.GitAuthConfig{
 		HTTPAuth: &services.GitHTTPAuthConfig{
@@ -415,3 +735,18 @@ func TestWatcherService_checkProject_WithGitAuth(t *testing.T) {
 
 	mockGitService.AssertExpectations(t)
 }
+
+func TestWatcherService_checkAllProjects_ObservesPollDuration(t *testing.T) {
+	mockProjectService := &MockProjectManager{}
+	mockGitService := &MockGitExecutor{}
+	service := NewWatcherService(mockProjectService, nil, mockGitService, time.Minute, time.Hour)
+
+	mockProjectService.On("List").Return([]*services.Project{}, nil)
+
+	assert.NoError(t, service.checkAllProjects(context.Background()))
+
+	w := httptest.NewRecorder()
+	services.NewMetricsHandler(mockProjectService).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Contains(t, w.Body.String(), "oar_watcher_poll_duration_seconds_count")
+	assert.NotContains(t, w.Body.String(), "oar_watcher_poll_duration_seconds_count 0")
+}
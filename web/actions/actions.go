@@ -2,10 +2,13 @@
 package actions
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
-	"github.com/oar-cd/oar/internal/app"
+	"github.com/oar-cd/oar/services"
 	"github.com/oar-cd/oar/web/handlers"
 )
 
@@ -15,13 +18,26 @@ import (
 func CreateProject(r *http.Request) error {
 	// Extract form data into request struct
 	req := &ProjectCreateRequest{
-		Name:           r.FormValue("name"),
-		GitURL:         r.FormValue("git_url"),
-		GitBranch:      r.FormValue("git_branch"),
-		ComposeFiles:   r.FormValue("compose_files"),
-		Variables:      r.FormValue("variables"),
-		GitAuth:        handlers.BuildGitAuthConfig(r),
-		WatcherEnabled: r.FormValue("watcher_enabled") == "on",
+		Name:            r.FormValue("name"),
+		GitURL:          r.FormValue("git_url"),
+		GitBranch:       r.FormValue("git_branch"),
+		GitRefType:      r.FormValue("git_ref_type"),
+		GitRef:          r.FormValue("git_ref"),
+		ComposeFiles:    r.FormValue("compose_files"),
+		Variables:       r.FormValue("variables"),
+		GitAuth:         handlers.BuildGitAuthConfig(r),
+		WatcherEnabled:  r.FormValue("watcher_enabled") == "on",
+		RequireApproval: r.FormValue("require_approval") == "on",
+		WatchImages:     r.FormValue("watch_images") == "on",
+		AutoRollback:    r.FormValue("auto_rollback") == "on",
+		BackupVolumes:   r.FormValue("backup_volumes") == "on",
+		HealthChecks:    r.FormValue("health_checks"),
+		PreDeployHooks:  r.FormValue("pre_deploy_hooks"),
+		PostDeployHooks: r.FormValue("post_deploy_hooks"),
+		PullTimeout:     r.FormValue("pull_timeout"),
+		UpTimeout:       r.FormValue("up_timeout"),
+		WaitTimeout:     r.FormValue("wait_timeout"),
+		HealthTimeout:   r.FormValue("health_timeout"),
 	}
 
 	// Validate request
@@ -33,7 +49,7 @@ func CreateProject(r *http.Request) error {
 	newProject := buildProjectFromCreateRequest(req)
 
 	// Create project using service
-	projectService := app.GetProjectService()
+	projectService := handlers.ProjectService(r.Context())
 	_, err := projectService.Create(newProject)
 	return err
 }
@@ -47,12 +63,25 @@ func UpdateProject(r *http.Request) error {
 
 	// Extract form data into request struct
 	req := &ProjectUpdateRequest{
-		ID:             projectID,
-		Name:           r.FormValue("name"),
-		ComposeFiles:   r.FormValue("compose_files"),
-		Variables:      r.FormValue("variables"),
-		GitAuth:        handlers.BuildGitAuthConfig(r),
-		WatcherEnabled: r.FormValue("watcher_enabled") == "on",
+		ID:              projectID,
+		Name:            r.FormValue("name"),
+		GitRefType:      r.FormValue("git_ref_type"),
+		GitRef:          r.FormValue("git_ref"),
+		ComposeFiles:    r.FormValue("compose_files"),
+		Variables:       r.FormValue("variables"),
+		GitAuth:         handlers.BuildGitAuthConfig(r),
+		WatcherEnabled:  r.FormValue("watcher_enabled") == "on",
+		RequireApproval: r.FormValue("require_approval") == "on",
+		WatchImages:     r.FormValue("watch_images") == "on",
+		AutoRollback:    r.FormValue("auto_rollback") == "on",
+		BackupVolumes:   r.FormValue("backup_volumes") == "on",
+		HealthChecks:    r.FormValue("health_checks"),
+		PreDeployHooks:  r.FormValue("pre_deploy_hooks"),
+		PostDeployHooks: r.FormValue("post_deploy_hooks"),
+		PullTimeout:     r.FormValue("pull_timeout"),
+		UpTimeout:       r.FormValue("up_timeout"),
+		WaitTimeout:     r.FormValue("wait_timeout"),
+		HealthTimeout:   r.FormValue("health_timeout"),
 	}
 
 	// Validate request
@@ -61,7 +90,7 @@ func UpdateProject(r *http.Request) error {
 	}
 
 	// Get existing project
-	projectService := app.GetProjectService()
+	projectService := handlers.ProjectService(r.Context())
 	existingProject, err := projectService.Get(projectID)
 	if err != nil {
 		return err
@@ -81,26 +110,64 @@ func DeleteProject(r *http.Request) error {
 		return err
 	}
 
-	projectService := app.GetProjectService()
+	projectService := handlers.ProjectService(r.Context())
 	return projectService.Remove(projectID)
 }
 
 // Streaming action functions
 
-// DeployProject handles project deployment streaming
-func DeployProject(projectID uuid.UUID, outputChan chan<- string) error {
-	projectService := app.GetProjectService()
-	return projectService.DeployStreaming(projectID, true, outputChan)
+// DeployProject handles project deployment streaming. The deployment outlives the stream, so that closing
+// the browser doesn't interrupt it; it is cancelled with CancelDeployment.
+func DeployProject(ctx context.Context, projectID uuid.UUID, outputChan chan<- string) error {
+	projectService := handlers.ProjectService(ctx)
+	options := services.DeployOptions{Pull: true, Trigger: services.DeploymentTriggerWeb}
+	return projectService.DeployStreaming(context.WithoutCancel(ctx), projectID, options, outputChan)
+}
+
+// RollbackProject handles streaming a project rollback to the deployment given in the URL
+func RollbackProject(ctx context.Context, projectID uuid.UUID, outputChan chan<- string) error {
+	deploymentID, err := uuid.Parse(chi.URLParamFromCtx(ctx, "deploymentID"))
+	if err != nil {
+		return fmt.Errorf("invalid deployment ID: %w", err)
+	}
+
+	projectService := handlers.ProjectService(ctx)
+	options := services.DeployOptions{Trigger: services.DeploymentTriggerWeb}
+	return projectService.RollbackStreaming(
+		context.WithoutCancel(ctx),
+		projectID,
+		deploymentID.String(),
+		options,
+		outputChan,
+	)
+}
+
+// ApproveProject handles streaming the deployment of the pending deployment given in the URL
+func ApproveProject(ctx context.Context, projectID uuid.UUID, outputChan chan<- string) error {
+	deploymentID, err := uuid.Parse(chi.URLParamFromCtx(ctx, "deploymentID"))
+	if err != nil {
+		return fmt.Errorf("invalid deployment ID: %w", err)
+	}
+
+	projectService := handlers.ProjectService(ctx)
+	options := services.DeployOptions{Trigger: services.DeploymentTriggerWeb}
+	return projectService.ApproveStreaming(context.WithoutCancel(ctx), projectID, deploymentID, options, outputChan)
+}
+
+// CancelDeployment cancels the deployment or rollback of the project in progress
+func CancelDeployment(ctx context.Context, projectID uuid.UUID) error {
+	projectService := handlers.ProjectService(ctx)
+	return projectService.CancelDeployment(projectID)
 }
 
 // StopProject handles project stop streaming
-func StopProject(projectID uuid.UUID, outputChan chan<- string) error {
-	projectService := app.GetProjectService()
+func StopProject(ctx context.Context, projectID uuid.UUID, outputChan chan<- string) error {
+	projectService := handlers.ProjectService(ctx)
 	return projectService.StopStreaming(projectID, outputChan)
 }
 
 // GetProjectLogs handles project logs streaming
-func GetProjectLogs(projectID uuid.UUID, outputChan chan<- string) error {
-	projectService := app.GetProjectService()
+func GetProjectLogs(ctx context.Context, projectID uuid.UUID, outputChan chan<- string) error {
+	projectService := handlers.ProjectService(ctx)
 	return projectService.GetLogsStreaming(projectID, outputChan)
 }
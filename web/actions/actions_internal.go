@@ -2,7 +2,9 @@ package actions
 
 import (
 	"errors"
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/oar-cd/oar/services"
@@ -10,23 +12,49 @@ import (
 
 // ProjectCreateRequest represents the data needed to create a project
 type ProjectCreateRequest struct {
-	Name           string
-	GitURL         string
-	GitBranch      string
-	ComposeFiles   string
-	Variables      string
-	GitAuth        *services.GitAuthConfig
-	WatcherEnabled bool
+	Name            string
+	GitURL          string
+	GitBranch       string
+	GitRefType      string
+	GitRef          string
+	ComposeFiles    string
+	Variables       string
+	GitAuth         *services.GitAuthConfig
+	WatcherEnabled  bool
+	RequireApproval bool
+	WatchImages     bool
+	AutoRollback    bool
+	BackupVolumes   bool
+	HealthChecks    string
+	PreDeployHooks  string
+	PostDeployHooks string
+	PullTimeout     string
+	UpTimeout       string
+	WaitTimeout     string
+	HealthTimeout   string
 }
 
 // ProjectUpdateRequest represents the data needed to update a project
 type ProjectUpdateRequest struct {
-	ID             uuid.UUID
-	Name           string
-	ComposeFiles   string
-	Variables      string
-	GitAuth        *services.GitAuthConfig
-	WatcherEnabled bool
+	ID              uuid.UUID
+	Name            string
+	GitRefType      string
+	GitRef          string
+	ComposeFiles    string
+	Variables       string
+	GitAuth         *services.GitAuthConfig
+	WatcherEnabled  bool
+	RequireApproval bool
+	WatchImages     bool
+	AutoRollback    bool
+	BackupVolumes   bool
+	HealthChecks    string
+	PreDeployHooks  string
+	PostDeployHooks string
+	PullTimeout     string
+	UpTimeout       string
+	WaitTimeout     string
+	HealthTimeout   string
 }
 
 // validateProjectCreateRequest validates a project creation request
@@ -40,6 +68,21 @@ func validateProjectCreateRequest(req *ProjectCreateRequest) error {
 	if strings.TrimSpace(req.ComposeFiles) == "" {
 		return errors.New("compose files are required")
 	}
+	if _, err := services.ParseGitRefType(req.GitRefType); err != nil {
+		return err
+	}
+	if _, err := parseHealthChecks(req.HealthChecks); err != nil {
+		return err
+	}
+	if _, err := parseHooks(req.PreDeployHooks); err != nil {
+		return err
+	}
+	if _, err := parseHooks(req.PostDeployHooks); err != nil {
+		return err
+	}
+	if _, err := parseTimeouts(req.PullTimeout, req.UpTimeout, req.WaitTimeout, req.HealthTimeout); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -51,6 +94,21 @@ func validateProjectUpdateRequest(req *ProjectUpdateRequest) error {
 	if strings.TrimSpace(req.ComposeFiles) == "" {
 		return errors.New("compose files are required")
 	}
+	if _, err := services.ParseGitRefType(req.GitRefType); err != nil {
+		return err
+	}
+	if _, err := parseHealthChecks(req.HealthChecks); err != nil {
+		return err
+	}
+	if _, err := parseHooks(req.PreDeployHooks); err != nil {
+		return err
+	}
+	if _, err := parseHooks(req.PostDeployHooks); err != nil {
+		return err
+	}
+	if _, err := parseTimeouts(req.PullTimeout, req.UpTimeout, req.WaitTimeout, req.HealthTimeout); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -70,26 +128,103 @@ func parseVariables(variables string) []string {
 	return strings.Split(strings.TrimSpace(variables), "\n")
 }
 
+// parseHealthChecks converts health checks string, one per line, to the ones used by the project
+func parseHealthChecks(healthChecks string) ([]services.HealthCheck, error) {
+	return services.ParseHealthChecks(strings.Split(healthChecks, "\n"))
+}
+
+// parseHooks converts deployment hooks string, one per line, to the ones used by the project
+func parseHooks(hooks string) ([]services.Hook, error) {
+	return services.ParseHooks(strings.Split(hooks, "\n"))
+}
+
+// parseGitRef converts the ref type and ref to the ones used by the project, dropping the ref when the
+// project tracks its branch
+func parseGitRef(refType, ref string) (services.GitRefType, string) {
+	gitRefType, _ := services.ParseGitRefType(refType) // Validated with the request
+	if gitRefType == services.GitRefTypeBranch {
+		return gitRefType, ""
+	}
+	return gitRefType, ref
+}
+
+// parseTimeouts converts the Docker Compose timeouts of the form, durations such as 10m, to the ones used by the
+// project. Empty timeouts use the defaults.
+func parseTimeouts(pull, up, wait, health string) (services.ComposeTimeouts, error) {
+	var timeouts services.ComposeTimeouts
+	fields := []struct {
+		name     string
+		value    string
+		duration *time.Duration
+	}{
+		{"pull", pull, &timeouts.Pull},
+		{"up", up, &timeouts.Up},
+		{"wait", wait, &timeouts.Wait},
+		{"health", health, &timeouts.Health},
+	}
+
+	for _, field := range fields {
+		value := strings.TrimSpace(field.value)
+		if value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return services.ComposeTimeouts{}, fmt.Errorf("%w: %s timeout %q is not a duration such as 10m",
+				services.ErrInvalidTimeout, field.name, value)
+		}
+		*field.duration = d
+	}
+
+	return timeouts, timeouts.Validate()
+}
+
 // buildProjectFromCreateRequest converts create request to Project struct
 func buildProjectFromCreateRequest(req *ProjectCreateRequest) *services.Project {
+	gitRefType, gitRef := parseGitRef(req.GitRefType, req.GitRef)
+	// Validated with the request
+	healthChecks, _ := parseHealthChecks(req.HealthChecks)
+	preDeployHooks, _ := parseHooks(req.PreDeployHooks)
+	postDeployHooks, _ := parseHooks(req.PostDeployHooks)
+	timeouts, _ := parseTimeouts(req.PullTimeout, req.UpTimeout, req.WaitTimeout, req.HealthTimeout)
 	return &services.Project{
-		ID:             uuid.New(),
-		Name:           req.Name,
-		GitURL:         req.GitURL,
-		GitBranch:      req.GitBranch,
-		GitAuth:        req.GitAuth,
-		ComposeFiles:   parseComposeFiles(req.ComposeFiles),
-		Variables:      parseVariables(req.Variables),
-		Status:         services.ProjectStatusStopped,
-		WatcherEnabled: req.WatcherEnabled,
+		ID:              uuid.New(),
+		Name:            req.Name,
+		GitURL:          req.GitURL,
+		GitBranch:       req.GitBranch,
+		GitRefType:      gitRefType,
+		GitRef:          gitRef,
+		GitAuth:         req.GitAuth,
+		ComposeFiles:    parseComposeFiles(req.ComposeFiles),
+		Variables:       parseVariables(req.Variables),
+		Status:          services.ProjectStatusStopped,
+		WatcherEnabled:  req.WatcherEnabled,
+		RequireApproval: req.RequireApproval,
+		WatchImages:     req.WatchImages,
+		AutoRollback:    req.AutoRollback,
+		BackupVolumes:   req.BackupVolumes,
+		HealthChecks:    healthChecks,
+		PreDeployHooks:  preDeployHooks,
+		PostDeployHooks: postDeployHooks,
+		Timeouts:        timeouts,
 	}
 }
 
 // applyProjectUpdateRequest applies update request to existing project
 func applyProjectUpdateRequest(project *services.Project, req *ProjectUpdateRequest) {
 	project.Name = req.Name
+	project.GitRefType, project.GitRef = parseGitRef(req.GitRefType, req.GitRef)
 	project.GitAuth = req.GitAuth
 	project.ComposeFiles = parseComposeFiles(req.ComposeFiles)
 	project.Variables = parseVariables(req.Variables)
 	project.WatcherEnabled = req.WatcherEnabled
+	project.RequireApproval = req.RequireApproval
+	project.WatchImages = req.WatchImages
+	project.AutoRollback = req.AutoRollback
+	project.BackupVolumes = req.BackupVolumes
+	// Validated with the request
+	project.HealthChecks, _ = parseHealthChecks(req.HealthChecks)
+	project.PreDeployHooks, _ = parseHooks(req.PreDeployHooks)
+	project.PostDeployHooks, _ = parseHooks(req.PostDeployHooks)
+	project.Timeouts, _ = parseTimeouts(req.PullTimeout, req.UpTimeout, req.WaitTimeout, req.HealthTimeout)
 }
@@ -2,6 +2,7 @@ package actions
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/oar-cd/oar/services"
@@ -82,6 +83,17 @@ func TestValidateProjectCreateRequest(t *testing.T) {
 			expectError: true,
 			errorMsg:    "compose files are required",
 		},
+		{
+			name: "invalid git ref type",
+			req: &ProjectCreateRequest{
+				Name:         "test-project",
+				GitURL:       "https://github.com/test/repo",
+				ComposeFiles: "docker-compose.yml",
+				GitRefType:   "sha",
+			},
+			expectError: true,
+			errorMsg:    "invalid git ref type",
+		},
 	}
 
 	for _, tt := range tests {
@@ -152,6 +164,50 @@ func TestValidateProjectUpdateRequest(t *testing.T) {
 			expectError: true,
 			errorMsg:    "compose files are required",
 		},
+		{
+			name: "timeout that is not a duration",
+			req: &ProjectUpdateRequest{
+				ID:           uuid.New(),
+				Name:         "updated-project",
+				ComposeFiles: "docker-compose.yml",
+				UpTimeout:    "ten minutes",
+			},
+			expectError: true,
+			errorMsg:    `up timeout "ten minutes" is not a duration such as 10m`,
+		},
+		{
+			name: "negative timeout",
+			req: &ProjectUpdateRequest{
+				ID:           uuid.New(),
+				Name:         "updated-project",
+				ComposeFiles: "docker-compose.yml",
+				WaitTimeout:  "-1m",
+			},
+			expectError: true,
+			errorMsg:    "wait timeout must not be negative",
+		},
+		{
+			name: "invalid health check",
+			req: &ProjectUpdateRequest{
+				ID:           uuid.New(),
+				Name:         "updated-project",
+				ComposeFiles: "docker-compose.yml",
+				HealthChecks: "tcp db:5432\nhttp localhost",
+			},
+			expectError: true,
+			errorMsg:    `"localhost" is not an http or https URL`,
+		},
+		{
+			name: "invalid hook",
+			req: &ProjectUpdateRequest{
+				ID:              uuid.New(),
+				Name:            "updated-project",
+				ComposeFiles:    "docker-compose.yml",
+				PostDeployHooks: "exec app",
+			},
+			expectError: true,
+			errorMsg:    "exec hooks need a service and a command",
+		},
 	}
 
 	for _, tt := range tests {
@@ -306,6 +362,133 @@ func TestBuildProjectFromCreateRequest_WatcherDisabled(t *testing.T) {
 	assert.False(t, project.WatcherEnabled) // Should be false
 }
 
+func TestBuildProjectFromCreateRequest_GitRef(t *testing.T) {
+	req := &ProjectCreateRequest{
+		Name:         "test-project",
+		GitURL:       "https://github.com/test/repo",
+		ComposeFiles: "docker-compose.yml",
+		GitRefType:   "tag",
+		GitRef:       "~1.4",
+	}
+
+	project := buildProjectFromCreateRequest(req)
+
+	assert.Equal(t, services.GitRefTypeTag, project.GitRefType)
+	assert.Equal(t, "~1.4", project.GitRef)
+
+	// A ref left in the form is dropped when the branch is tracked
+	req.GitRefType = "branch"
+	project = buildProjectFromCreateRequest(req)
+
+	assert.Equal(t, services.GitRefTypeBranch, project.GitRefType)
+	assert.Empty(t, project.GitRef)
+}
+
+func TestBuildProjectFromCreateRequest_Timeouts(t *testing.T) {
+	req := &ProjectCreateRequest{
+		Name:          "test-project",
+		GitURL:        "https://github.com/test/repo",
+		ComposeFiles:  "docker-compose.yml",
+		PullTimeout:   "30m",
+		WaitTimeout:   " 90s ",
+		HealthTimeout: "1m",
+	}
+
+	project := buildProjectFromCreateRequest(req)
+
+	expected := services.ComposeTimeouts{Pull: 30 * time.Minute, Wait: 90 * time.Second, Health: time.Minute}
+	assert.Equal(t, expected, project.Timeouts)
+}
+
+func TestBuildProjectFromCreateRequest_HealthChecks(t *testing.T) {
+	req := &ProjectCreateRequest{
+		Name:         "test-project",
+		GitURL:       "https://github.com/test/repo",
+		ComposeFiles: "docker-compose.yml",
+		HealthChecks: "http http://localhost:8080/health 200\r\n\r\ncommand db pg_isready\n",
+	}
+
+	project := buildProjectFromCreateRequest(req)
+
+	assert.Equal(t, []services.HealthCheck{
+		{Type: services.HealthCheckTypeHTTP, URL: "http://localhost:8080/health", Status: 200},
+		{Type: services.HealthCheckTypeCommand, Service: "db", Command: "pg_isready"},
+	}, project.HealthChecks)
+}
+
+func TestBuildProjectFromCreateRequest_Hooks(t *testing.T) {
+	req := &ProjectCreateRequest{
+		Name:            "test-project",
+		GitURL:          "https://github.com/test/repo",
+		ComposeFiles:    "docker-compose.yml",
+		PreDeployHooks:  "run app ./manage.py migrate\r\nscript ./scripts/backup.sh\r\n",
+		PostDeployHooks: "exec cache redis-cli FLUSHALL",
+	}
+
+	project := buildProjectFromCreateRequest(req)
+
+	assert.Equal(t, []services.Hook{
+		{Type: services.HookTypeRun, Service: "app", Command: "./manage.py migrate"},
+		{Type: services.HookTypeScript, Command: "./scripts/backup.sh"},
+	}, project.PreDeployHooks)
+	assert.Equal(t, []services.Hook{
+		{Type: services.HookTypeExec, Service: "cache", Command: "redis-cli FLUSHALL"},
+	}, project.PostDeployHooks)
+}
+
+func TestBuildProjectFromCreateRequest_AutoRollback(t *testing.T) {
+	req := &ProjectCreateRequest{
+		Name:         "test-project",
+		GitURL:       "https://github.com/test/repo",
+		ComposeFiles: "docker-compose.yml",
+		AutoRollback: true,
+	}
+
+	project := buildProjectFromCreateRequest(req)
+
+	assert.True(t, project.AutoRollback)
+}
+
+func TestBuildProjectFromCreateRequest_BackupVolumes(t *testing.T) {
+	req := &ProjectCreateRequest{
+		Name:          "test-project",
+		GitURL:        "https://github.com/test/repo",
+		ComposeFiles:  "docker-compose.yml",
+		BackupVolumes: true,
+	}
+
+	project := buildProjectFromCreateRequest(req)
+
+	assert.True(t, project.BackupVolumes)
+}
+
+func TestBuildProjectFromCreateRequest_RequireApproval(t *testing.T) {
+	req := &ProjectCreateRequest{
+		Name:            "test-project",
+		GitURL:          "https://github.com/test/repo",
+		ComposeFiles:    "docker-compose.yml",
+		WatcherEnabled:  true,
+		RequireApproval: true,
+	}
+
+	project := buildProjectFromCreateRequest(req)
+
+	assert.True(t, project.RequireApproval)
+}
+
+func TestBuildProjectFromCreateRequest_WatchImages(t *testing.T) {
+	req := &ProjectCreateRequest{
+		Name:         "test-project",
+		GitURL:       "https://github.com/test/repo",
+		ComposeFiles: "docker-compose.yml",
+		WatchImages:  true,
+	}
+
+	project := buildProjectFromCreateRequest(req)
+
+	assert.True(t, project.WatchImages)
+}
+
 func TestApplyProjectUpdateRequest(t *testing.T) {
 	// Create original project
 	originalProject := &services.Project{
@@ -11,7 +11,9 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/oar-cd/oar/internal/app"
 	"github.com/oar-cd/oar/services"
+	"github.com/oar-cd/oar/testing/mocks"
 	"github.com/oar-cd/oar/web/handlers"
 	"github.com/stretchr/testify/assert"
 )
@@ -347,3 +349,50 @@ func TestUpdateProjectValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestRollbackProject(t *testing.T) {
+	projectID := uuid.New()
+	deploymentID := uuid.New()
+
+	tests := []struct {
+		name         string
+		deploymentID string
+		expectError  bool
+	}{
+		{name: "valid deployment ID", deploymentID: deploymentID.String()},
+		{name: "invalid deployment ID", deploymentID: "abc123", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var target string
+			app.SetProjectServiceForTesting(&mocks.MockProjectManager{
+				RollbackStreamingFunc: func(
+					id uuid.UUID,
+					to string,
+					options services.DeployOptions,
+					outputChan chan<- string,
+				) error {
+					assert.Equal(t, services.DeploymentTriggerWeb, options.Trigger)
+					target = to
+					return nil
+				},
+			})
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", projectID.String())
+			rctx.URLParams.Add("deploymentID", tt.deploymentID)
+			ctx := context.WithValue(context.Background(), chi.RouteCtxKey, rctx)
+
+			err := RollbackProject(ctx, projectID, make(chan string, 1))
+
+			if tt.expectError {
+				assert.ErrorContains(t, err, "invalid deployment ID")
+				assert.Empty(t, target)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, deploymentID.String(), target)
+			}
+		})
+	}
+}
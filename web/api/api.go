@@ -0,0 +1,833 @@
+// Package api provides the versioned JSON REST API for the web server.
+package api
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/oar-cd/oar/services"
+	"github.com/oar-cd/oar/web/handlers"
+)
+
+// maxRequestBodySize limits the size of JSON request bodies
+const maxRequestBodySize = 1 << 20
+
+//go:embed openapi.yaml
+var openAPISpec []byte
+
+// OpenAPISpec serves the OpenAPI document describing this API
+func OpenAPISpec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/yaml")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(openAPISpec); err != nil {
+		handlers.LogOperationError("api_openapi_spec", "api", err)
+	}
+}
+
+// ListProjects returns all projects
+func ListProjects(w http.ResponseWriter, r *http.Request) {
+	projects, err := handlers.ProjectService(r.Context()).List()
+	if err != nil {
+		writeServiceError(w, "api_list_projects", err)
+		return
+	}
+
+	response := make([]ProjectResponse, len(projects))
+	for i, p := range projects {
+		response[i] = newProjectResponse(p)
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// CreateProject clones a repository and registers it as a new project
+func CreateProject(w http.ResponseWriter, r *http.Request) {
+	var req ProjectCreateRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := validateProjectCreateRequest(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	project := services.NewProject(req.Name, req.GitURL, req.ComposeFiles, req.Variables)
+	project.GitBranch = req.GitBranch
+	project.GitRefType, _ = services.ParseGitRefType(req.GitRefType) // Validated with the request
+	project.GitRef = req.GitRef
+	project.GitAuth = req.GitAuth.toGitAuthConfig()
+	if req.WatcherEnabled != nil {
+		project.WatcherEnabled = *req.WatcherEnabled
+	}
+	if req.RequireApproval != nil {
+		project.RequireApproval = *req.RequireApproval
+	}
+	if req.WatchImages != nil {
+		project.WatchImages = *req.WatchImages
+	}
+	project.AutoRollback = req.AutoRollback
+	project.BackupVolumes = req.BackupVolumes
+	// Validated with the request
+	project.HealthChecks, _ = services.ParseHealthChecks(req.HealthChecks)
+	project.PreDeployHooks, _ = services.ParseHooks(req.PreDeployHooks)
+	project.PostDeployHooks, _ = services.ParseHooks(req.PostDeployHooks)
+	project.Timeouts = req.Timeouts.toComposeTimeouts()
+
+	created, err := handlers.ProjectService(r.Context()).Create(&project)
+	if err != nil {
+		writeServiceError(w, "api_create_project", err, "git_url", req.GitURL)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, newProjectResponse(created))
+}
+
+// GetProject returns a single project
+func GetProject(w http.ResponseWriter, r *http.Request) {
+	projectID, err := handlers.ParseProjectID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	project, err := handlers.ProjectService(r.Context()).Get(projectID)
+	if err != nil {
+		writeServiceError(w, "api_get_project", err, "project_id", projectID)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newProjectResponse(project))
+}
+
+// UpdateProject applies a partial update to a project
+func UpdateProject(w http.ResponseWriter, r *http.Request) {
+	projectID, err := handlers.ParseProjectID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var req ProjectUpdateRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	projectService := handlers.ProjectService(r.Context())
+	project, err := projectService.Get(projectID)
+	if err != nil {
+		writeServiceError(w, "api_update_project", err, "project_id", projectID)
+		return
+	}
+
+	if err := applyProjectUpdateRequest(project, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := validateProject(project); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := projectService.Update(project); err != nil {
+		writeServiceError(w, "api_update_project", err, "project_id", projectID)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newProjectResponse(project))
+}
+
+// DeleteProject stops and removes a project
+func DeleteProject(w http.ResponseWriter, r *http.Request) {
+	projectID, err := handlers.ParseProjectID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := handlers.ProjectService(r.Context()).Remove(projectID); err != nil {
+		writeServiceError(w, "api_delete_project", err, "project_id", projectID)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListDeployments returns the deployment history of a project, newest first
+func ListDeployments(w http.ResponseWriter, r *http.Request) {
+	projectID, err := handlers.ParseProjectID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	deployments, err := handlers.ProjectService(r.Context()).ListDeployments(projectID)
+	if err != nil {
+		writeServiceError(w, "api_list_deployments", err, "project_id", projectID)
+		return
+	}
+
+	response := make([]DeploymentResponse, len(deployments))
+	for i, d := range deployments {
+		response[i] = newDeploymentResponse(d)
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// GetStatus returns the container status of a project
+func GetStatus(w http.ResponseWriter, r *http.Request) {
+	projectID, err := handlers.ParseProjectID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	status, err := handlers.ProjectService(r.Context()).GetStatus(projectID)
+	if err != nil {
+		writeServiceError(w, "api_get_status", err, "project_id", projectID)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newStatusResponse(status))
+}
+
+// GetConfig returns the rendered Docker Compose configuration of a project
+func GetConfig(w http.ResponseWriter, r *http.Request) {
+	projectID, err := handlers.ParseProjectID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	config, err := handlers.ProjectService(r.Context()).GetConfig(projectID)
+	if err != nil {
+		writeServiceError(w, "api_get_config", err, "project_id", projectID)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ConfigResponse{Config: config})
+}
+
+// PreviewDeploy returns what deploying a project would change without deploying it: the commits it would bring in
+// and the changes to the rendered Docker Compose configuration
+func PreviewDeploy(w http.ResponseWriter, r *http.Request) {
+	projectID, err := handlers.ParseProjectID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	pull, err := parsePull(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	options := services.DeployOptions{Pull: pull, Trigger: services.DeploymentTriggerAPI}
+	preview, err := handlers.ProjectService(r.Context()).PreviewDeploy(r.Context(), projectID, options)
+	if err != nil {
+		writeServiceError(w, "api_preview_deploy", err, "project_id", projectID)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newDeployPreviewResponse(preview))
+}
+
+// parsePull returns the pull query parameter of a deployment request, true if it is absent
+func parsePull(r *http.Request) (bool, error) {
+	v := r.URL.Query().Get("pull")
+	if v == "" {
+		return true, nil
+	}
+	pull, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("invalid pull parameter %q: must be a boolean", v)
+	}
+	return pull, nil
+}
+
+// DeployProject deploys a project and returns the resulting deployment record.
+// The request blocks until the deployment has finished or is cancelled with CancelDeployment.
+func DeployProject(w http.ResponseWriter, r *http.Request) {
+	projectID, err := handlers.ParseProjectID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	pull, err := parsePull(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// The deployment outlives the request, so that a client disconnecting doesn't interrupt it
+	ctx := context.WithoutCancel(r.Context())
+	projectService := handlers.ProjectService(ctx)
+	runDeployment(w, "api_deploy_project", projectService, projectID, uuid.Nil, func(outputChan chan<- string) error {
+		options := services.DeployOptions{Pull: pull, Trigger: services.DeploymentTriggerAPI}
+		return projectService.DeployStreaming(ctx, projectID, options, outputChan)
+	})
+}
+
+// RollbackProject redeploys the commit of an earlier deployment and returns the resulting deployment record.
+// The request blocks until the deployment has finished.
+func RollbackProject(w http.ResponseWriter, r *http.Request) {
+	projectID, err := handlers.ParseProjectID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	target := r.URL.Query().Get("to")
+
+	ctx := context.WithoutCancel(r.Context())
+	projectService := handlers.ProjectService(ctx)
+	runDeployment(w, "api_rollback_project", projectService, projectID, uuid.Nil, func(outputChan chan<- string) error {
+		options := services.DeployOptions{Trigger: services.DeploymentTriggerAPI}
+		return projectService.RollbackStreaming(ctx, projectID, target, options, outputChan)
+	})
+}
+
+// ApproveDeployment deploys a deployment pending approval and returns its record.
+// The request blocks until the deployment has finished.
+func ApproveDeployment(w http.ResponseWriter, r *http.Request) {
+	projectID, deploymentID, err := parseDeploymentID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := context.WithoutCancel(r.Context())
+	projectService := handlers.ProjectService(ctx)
+	approve := func(outputChan chan<- string) error {
+		options := services.DeployOptions{Trigger: services.DeploymentTriggerAPI}
+		return projectService.ApproveStreaming(ctx, projectID, deploymentID, options, outputChan)
+	}
+	runDeployment(w, "api_approve_deployment", projectService, projectID, deploymentID, approve)
+}
+
+// RejectDeployment rejects a deployment pending approval and returns its record
+func RejectDeployment(w http.ResponseWriter, r *http.Request) {
+	projectID, deploymentID, err := parseDeploymentID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	projectService := handlers.ProjectService(r.Context())
+	options := services.DeployOptions{Trigger: services.DeploymentTriggerAPI}
+	if err := projectService.RejectDeployment(projectID, deploymentID, options); err != nil {
+		writeServiceError(w, "api_reject_deployment", err, "project_id", projectID, "deployment_id", deploymentID)
+		return
+	}
+	writeDeployment(w, "api_reject_deployment", projectService, projectID, deploymentID)
+}
+
+// parseDeploymentID parses the project and deployment IDs from the URL
+func parseDeploymentID(r *http.Request) (uuid.UUID, uuid.UUID, error) {
+	projectID, err := handlers.ParseProjectID(r)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+
+	deploymentID, err := uuid.Parse(chi.URLParam(r, "deploymentID"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, errors.New("invalid deployment ID format")
+	}
+	return projectID, deploymentID, nil
+}
+
+// runDeployment runs a streaming deployment and responds with its record: the one with deploymentID, or the newest
+// one, which the deployment created, if it is uuid.Nil
+func runDeployment(
+	w http.ResponseWriter,
+	operation string,
+	projectService services.ProjectManager,
+	projectID uuid.UUID,
+	deploymentID uuid.UUID,
+	deploy func(outputChan chan<- string) error,
+) {
+	// Output is persisted in the deployment record, so the stream itself is discarded
+	outputChan := make(chan string, 100)
+	done := make(chan bool)
+	go func() {
+		defer func() { done <- true }()
+		for range outputChan {
+		}
+	}()
+
+	deployErr := deploy(outputChan)
+	close(outputChan)
+	<-done
+
+	if deployErr != nil {
+		writeServiceError(w, operation, deployErr, "project_id", projectID)
+		return
+	}
+
+	writeDeployment(w, operation, projectService, projectID, deploymentID)
+}
+
+// writeDeployment responds with the deployment record of a project with deploymentID, or its newest one if it is
+// uuid.Nil
+func writeDeployment(
+	w http.ResponseWriter,
+	operation string,
+	projectService services.ProjectManager,
+	projectID uuid.UUID,
+	deploymentID uuid.UUID,
+) {
+	deployments, err := projectService.ListDeployments(projectID)
+	if err != nil {
+		writeServiceError(w, operation, err, "project_id", projectID)
+		return
+	}
+	for _, deployment := range deployments {
+		if deploymentID == uuid.Nil || deployment.ID == deploymentID {
+			writeJSON(w, http.StatusOK, newDeploymentResponse(deployment))
+			return
+		}
+	}
+	writeError(w, http.StatusInternalServerError, "deployment record not found")
+}
+
+// CancelDeployment cancels the deployment or rollback of a project in progress
+func CancelDeployment(w http.ResponseWriter, r *http.Request) {
+	projectID, err := handlers.ParseProjectID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	projectService := handlers.ProjectService(r.Context())
+	if err := projectService.CancelDeployment(projectID); err != nil {
+		writeServiceError(w, "api_cancel_deployment", err, "project_id", projectID)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// StopProject stops a project's containers and returns the updated project
+func StopProject(w http.ResponseWriter, r *http.Request) {
+	projectID, err := handlers.ParseProjectID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	projectService := handlers.ProjectService(r.Context())
+	if err := projectService.Stop(projectID); err != nil {
+		writeServiceError(w, "api_stop_project", err, "project_id", projectID)
+		return
+	}
+
+	project, err := projectService.Get(projectID)
+	if err != nil {
+		writeServiceError(w, "api_stop_project", err, "project_id", projectID)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newProjectResponse(project))
+}
+
+// ListNotifiers returns the notifiers of a project
+func ListNotifiers(w http.ResponseWriter, r *http.Request) {
+	projectID, err := handlers.ParseProjectID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	notifiers, err := handlers.NotificationService(r.Context()).ListNotifiers(projectID)
+	if err != nil {
+		writeServiceError(w, "api_list_notifiers", err, "project_id", projectID)
+		return
+	}
+
+	response := make([]NotifierResponse, len(notifiers))
+	for i, n := range notifiers {
+		response[i] = newNotifierResponse(n)
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// CreateNotifier adds a notifier to a project
+func CreateNotifier(w http.ResponseWriter, r *http.Request) {
+	projectID, err := handlers.ParseProjectID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var req NotifierCreateRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	notifier, err := req.toNotifier(projectID)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := handlers.NotificationService(r.Context()).AddNotifier(notifier); err != nil {
+		writeServiceError(w, "api_create_notifier", err, "project_id", projectID)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, newNotifierResponse(notifier))
+}
+
+// DeleteNotifier removes a notifier from a project
+func DeleteNotifier(w http.ResponseWriter, r *http.Request) {
+	projectID, notifierID, err := parseNotifierID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := handlers.NotificationService(r.Context()).RemoveNotifier(projectID, notifierID); err != nil {
+		writeServiceError(w, "api_delete_notifier", err, "project_id", projectID, "notifier_id", notifierID)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// TestNotifier sends a test notification, reporting delivery failures as 502 Bad Gateway
+func TestNotifier(w http.ResponseWriter, r *http.Request) {
+	projectID, notifierID, err := parseNotifierID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := handlers.NotificationService(r.Context()).TestNotifier(projectID, notifierID); err != nil {
+		writeServiceError(w, "api_test_notifier", err, "project_id", projectID, "notifier_id", notifierID)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseNotifierID extracts the project and notifier IDs from URL parameters
+func parseNotifierID(r *http.Request) (uuid.UUID, uuid.UUID, error) {
+	projectID, err := handlers.ParseProjectID(r)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+
+	notifierID, err := uuid.Parse(chi.URLParam(r, "notifierID"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, errors.New("invalid notifier ID format")
+	}
+	return projectID, notifierID, nil
+}
+
+// ListCatalogs returns all catalogs
+func ListCatalogs(w http.ResponseWriter, r *http.Request) {
+	catalogs, err := handlers.CatalogService(r.Context()).List()
+	if err != nil {
+		writeServiceError(w, "api_list_catalogs", err)
+		return
+	}
+
+	response := make([]CatalogResponse, len(catalogs))
+	for i, c := range catalogs {
+		response[i] = newCatalogResponse(c)
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// CreateCatalog clones a catalog repository. Its projects are created by syncing it with SyncCatalog.
+func CreateCatalog(w http.ResponseWriter, r *http.Request) {
+	var req CatalogCreateRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	created, err := handlers.CatalogService(r.Context()).Create(&services.Catalog{
+		Name:      req.Name,
+		GitURL:    req.GitURL,
+		GitBranch: req.GitBranch,
+		GitAuth:   req.GitAuth.toGitAuthConfig(),
+		Secrets:   secretsFromMap(req.Secrets),
+	})
+	if err != nil {
+		writeServiceError(w, "api_create_catalog", err, "git_url", req.GitURL)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, newCatalogResponse(created))
+}
+
+// GetCatalog returns a single catalog
+func GetCatalog(w http.ResponseWriter, r *http.Request) {
+	catalogID, err := parseCatalogID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	catalog, err := handlers.CatalogService(r.Context()).Get(catalogID)
+	if err != nil {
+		writeServiceError(w, "api_get_catalog", err, "catalog_id", catalogID)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newCatalogResponse(catalog))
+}
+
+// UpdateCatalog applies a partial update to a catalog
+func UpdateCatalog(w http.ResponseWriter, r *http.Request) {
+	catalogID, err := parseCatalogID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var req CatalogUpdateRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	catalogService := handlers.CatalogService(r.Context())
+	catalog, err := catalogService.Get(catalogID)
+	if err != nil {
+		writeServiceError(w, "api_update_catalog", err, "catalog_id", catalogID)
+		return
+	}
+
+	applyCatalogUpdateRequest(catalog, &req)
+	if err := catalogService.Update(catalog); err != nil {
+		writeServiceError(w, "api_update_catalog", err, "catalog_id", catalogID)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newCatalogResponse(catalog))
+}
+
+// DeleteCatalog removes a catalog, keeping its projects unless remove_projects=true is given
+func DeleteCatalog(w http.ResponseWriter, r *http.Request) {
+	catalogID, err := parseCatalogID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	removeProjects := false
+	if v := r.URL.Query().Get("remove_projects"); v != "" {
+		removeProjects, err = strconv.ParseBool(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest,
+				fmt.Sprintf("invalid remove_projects parameter %q: must be a boolean", v))
+			return
+		}
+	}
+
+	if err := handlers.CatalogService(r.Context()).Remove(catalogID, removeProjects); err != nil {
+		writeServiceError(w, "api_delete_catalog", err, "catalog_id", catalogID)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SyncCatalog syncs the projects of a catalog with its repository and reports what changed.
+// The request blocks until the projects it creates or updates have been deployed.
+func SyncCatalog(w http.ResponseWriter, r *http.Request) {
+	catalogID, err := parseCatalogID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// The sync outlives the request, so that a client disconnecting doesn't interrupt deployments
+	ctx := context.WithoutCancel(r.Context())
+	result, err := handlers.CatalogService(ctx).Sync(ctx, catalogID)
+	if err != nil {
+		writeServiceError(w, "api_sync_catalog", err, "catalog_id", catalogID)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newCatalogSyncResponse(result))
+}
+
+// parseCatalogID extracts the catalog ID from URL parameters
+func parseCatalogID(r *http.Request) (uuid.UUID, error) {
+	catalogID, err := uuid.Parse(chi.URLParam(r, "catalogID"))
+	if err != nil {
+		return uuid.Nil, errors.New("invalid catalog ID format")
+	}
+	return catalogID, nil
+}
+
+// validateProjectCreateRequest validates a project creation request
+func validateProjectCreateRequest(req *ProjectCreateRequest) error {
+	if strings.TrimSpace(req.Name) == "" {
+		return errors.New("name is required")
+	}
+	if strings.TrimSpace(req.GitURL) == "" {
+		return errors.New("git_url is required")
+	}
+	if len(req.ComposeFiles) == 0 {
+		return errors.New("compose_files are required")
+	}
+	if _, err := services.ParseGitRefType(req.GitRefType); err != nil {
+		return err
+	}
+	if _, err := services.ParseHealthChecks(req.HealthChecks); err != nil {
+		return err
+	}
+	if _, err := services.ParseHooks(req.PreDeployHooks); err != nil {
+		return err
+	}
+	if _, err := services.ParseHooks(req.PostDeployHooks); err != nil {
+		return err
+	}
+	return nil
+}
+
+// validateProject validates a project after an update request has been applied
+func validateProject(project *services.Project) error {
+	if strings.TrimSpace(project.Name) == "" {
+		return errors.New("name is required")
+	}
+	if len(project.ComposeFiles) == 0 {
+		return errors.New("compose_files are required")
+	}
+	return nil
+}
+
+// applyProjectUpdateRequest applies the fields present in the request to the project
+func applyProjectUpdateRequest(project *services.Project, req *ProjectUpdateRequest) error {
+	if req.Name != nil {
+		project.Name = *req.Name
+	}
+	if req.GitRefType != nil {
+		gitRefType, err := services.ParseGitRefType(*req.GitRefType)
+		if err != nil {
+			return err
+		}
+		project.GitRefType = gitRefType
+		if gitRefType == services.GitRefTypeBranch {
+			project.GitRef = ""
+		}
+	}
+	if req.GitRef != nil {
+		project.GitRef = *req.GitRef
+	}
+	if req.GitAuth != nil {
+		project.GitAuth = req.GitAuth.toGitAuthConfig()
+	}
+	if req.ComposeFiles != nil {
+		project.ComposeFiles = *req.ComposeFiles
+	}
+	if req.Variables != nil {
+		project.Variables = *req.Variables
+	}
+	if req.WatcherEnabled != nil {
+		project.WatcherEnabled = *req.WatcherEnabled
+	}
+	if req.RequireApproval != nil {
+		project.RequireApproval = *req.RequireApproval
+	}
+	if req.WatchImages != nil {
+		project.WatchImages = *req.WatchImages
+	}
+	if req.AutoRollback != nil {
+		project.AutoRollback = *req.AutoRollback
+	}
+	if req.BackupVolumes != nil {
+		project.BackupVolumes = *req.BackupVolumes
+	}
+	if req.HealthChecks != nil {
+		healthChecks, err := services.ParseHealthChecks(*req.HealthChecks)
+		if err != nil {
+			return err
+		}
+		project.HealthChecks = healthChecks
+	}
+	if req.PreDeployHooks != nil {
+		hooks, err := services.ParseHooks(*req.PreDeployHooks)
+		if err != nil {
+			return err
+		}
+		project.PreDeployHooks = hooks
+	}
+	if req.PostDeployHooks != nil {
+		hooks, err := services.ParseHooks(*req.PostDeployHooks)
+		if err != nil {
+			return err
+		}
+		project.PostDeployHooks = hooks
+	}
+	if req.Timeouts != nil {
+		project.Timeouts = req.Timeouts.toComposeTimeouts()
+	}
+	return nil
+}
+
+// decodeJSON decodes a size-limited JSON request body, rejecting unknown fields
+func decodeJSON(w http.ResponseWriter, r *http.Request, v any) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodySize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("invalid request body: %w", err)
+	}
+	return nil
+}
+
+// writeJSON encodes v as the JSON response body
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		handlers.LogOperationError("api_write_json", "api", err)
+	}
+}
+
+// writeError writes an ErrorResponse with the given status code
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, ErrorResponse{Error: message})
+}
+
+// writeServiceError logs a service error and maps it to an HTTP status code
+func writeServiceError(w http.ResponseWriter, operation string, err error, fields ...any) {
+	handlers.LogOperationError(operation, "api", err, fields...)
+
+	status := http.StatusInternalServerError
+	switch {
+	case services.IsNotFound(err):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrPermissionDenied):
+		status = http.StatusForbidden
+	case errors.Is(err, services.ErrInvalidRollbackTarget), errors.Is(err, services.ErrInvalidGitRef),
+		errors.Is(err, services.ErrInvalidNotifier), errors.Is(err, services.ErrInvalidTimeout),
+		errors.Is(err, services.ErrInvalidHealthCheck), errors.Is(err, services.ErrInvalidHook),
+		errors.Is(err, services.ErrInvalidCatalog), errors.Is(err, services.ErrApprovalWithoutWatcher):
+		status = http.StatusBadRequest
+	case errors.Is(err, services.ErrNoDeploymentInProgress), errors.Is(err, services.ErrDeploymentCancelled),
+		errors.Is(err, services.ErrDeploymentInProgress), errors.Is(err, services.ErrDeploymentNotPending),
+		errors.Is(err, services.ErrCommitRejected):
+		status = http.StatusConflict
+	case errors.Is(err, services.ErrNotificationDelivery):
+		status = http.StatusBadGateway
+	}
+	writeError(w, status, err.Error())
+}
@@ -0,0 +1,1364 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/oar-cd/oar/services"
+	"github.com/oar-cd/oar/testing/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// Helper function to add project ID to request context
+func addProjectIDToRequest(req *http.Request, projectID string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", projectID)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+func decodeResponse[T any](t *testing.T, w *httptest.ResponseRecorder) T {
+	t.Helper()
+	var v T
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&v))
+	return v
+}
+
+func newTestProject(id uuid.UUID) *services.Project {
+	commit := "abc123"
+	return &services.Project{
+		ID:             id,
+		Name:           "test-project",
+		GitURL:         "https://github.com/test/repo.git",
+		GitBranch:      "main",
+		GitAuth:        &services.GitAuthConfig{HTTPAuth: &services.GitHTTPAuthConfig{Username: "u", Password: "secret"}},
+		ComposeFiles:   []string{"compose.yaml"},
+		Status:         services.ProjectStatusRunning,
+		LastCommit:     &commit,
+		WatcherEnabled: true,
+		CreatedAt:      time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		UpdatedAt:      time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestListProjects(t *testing.T) {
+	projectID := uuid.New()
+	app.SetProjectServiceForTesting(&mocks.MockProjectManager{
+		ListFunc: func() ([]*services.Project, error) {
+			return []*services.Project{newTestProject(projectID)}, nil
+		},
+	})
+
+	w := httptest.NewRecorder()
+	ListProjects(w, httptest.NewRequest(http.MethodGet, "/api/v1/projects", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	projects := decodeResponse[[]ProjectResponse](t, w)
+	require.Len(t, projects, 1)
+	assert.Equal(t, projectID, projects[0].ID)
+	assert.Equal(t, "running", projects[0].Status)
+	assert.Equal(t, "http", projects[0].GitAuthType)
+	assert.Equal(t, []string{}, projects[0].Variables)
+	assert.Equal(t, []string{}, projects[0].Drift)
+	assert.NotContains(t, w.Body.String(), "secret")
+}
+
+func TestListProjects_Error(t *testing.T) {
+	app.SetProjectServiceForTesting(&mocks.MockProjectManager{
+		ListFunc: func() ([]*services.Project, error) {
+			return nil, errors.New("database error")
+		},
+	})
+
+	w := httptest.NewRecorder()
+	ListProjects(w, httptest.NewRequest(http.MethodGet, "/api/v1/projects", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "database error", decodeResponse[ErrorResponse](t, w).Error)
+}
+
+func TestCreateProject(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name: "valid request",
+			body: `{"name":"test","git_url":"https://github.com/test/repo.git","git_branch":"dev",` +
+				`"compose_files":["compose.yaml"],"variables":["A=1"],"watcher_enabled":false,"auto_rollback":true,` +
+				`"backup_volumes":true,"git_auth":{"ssh":{"private_key":"KEY","user":"git"}},` +
+				`"timeouts":{"pull":1800,"up":0,"wait":90},` +
+				`"health_checks":["tcp db:5432",""],"pre_deploy_hooks":["run app migrate"]}`,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "missing name",
+			body:           `{"git_url":"https://github.com/test/repo.git","compose_files":["compose.yaml"]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "name is required",
+		},
+		{
+			name:           "missing git url",
+			body:           `{"name":"test","compose_files":["compose.yaml"]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "git_url is required",
+		},
+		{
+			name:           "missing compose files",
+			body:           `{"name":"test","git_url":"https://github.com/test/repo.git"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "compose_files are required",
+		},
+		{
+			name: "invalid git ref type",
+			body: `{"name":"test","git_url":"https://github.com/test/repo.git",` +
+				`"compose_files":["compose.yaml"],"git_ref_type":"sha"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "invalid git ref type",
+		},
+		{
+			name: "invalid health check",
+			body: `{"name":"test","git_url":"https://github.com/test/repo.git",` +
+				`"compose_files":["compose.yaml"],"health_checks":["tcp db"]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "invalid health check",
+		},
+		{
+			name: "invalid hook",
+			body: `{"name":"test","git_url":"https://github.com/test/repo.git",` +
+				`"compose_files":["compose.yaml"],"post_deploy_hooks":["exec app"]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "invalid hook",
+		},
+		{
+			name:           "unknown field",
+			body:           `{"name":"test","bogus":true}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "invalid request body",
+		},
+		{
+			name:           "malformed json",
+			body:           `{`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "invalid request body",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created *services.Project
+			app.SetProjectServiceForTesting(&mocks.MockProjectManager{
+				CreateFunc: func(project *services.Project) (*services.Project, error) {
+					created = project
+					project.ID = uuid.New()
+					return project, nil
+				},
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/projects", strings.NewReader(tt.body))
+			CreateProject(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedError != "" {
+				assert.Contains(t, decodeResponse[ErrorResponse](t, w).Error, tt.expectedError)
+				assert.Nil(t, created)
+				return
+			}
+
+			require.NotNil(t, created)
+			assert.Equal(t, "dev", created.GitBranch)
+			assert.False(t, created.WatcherEnabled)
+			assert.True(t, created.AutoRollback)
+			assert.True(t, created.BackupVolumes)
+			assert.Equal(t, []string{"A=1"}, created.Variables)
+			require.NotNil(t, created.GitAuth)
+			require.NotNil(t, created.GitAuth.SSHAuth)
+			assert.Equal(t, "KEY", created.GitAuth.SSHAuth.PrivateKey)
+			assert.Equal(t, services.ComposeTimeouts{Pull: 30 * time.Minute, Wait: 90 * time.Second}, created.Timeouts)
+			assert.Equal(t, []services.HealthCheck{{Type: services.HealthCheckTypeTCP, Address: "db:5432"}},
+				created.HealthChecks)
+			assert.Equal(t, []services.Hook{{Type: services.HookTypeRun, Service: "app", Command: "migrate"}},
+				created.PreDeployHooks)
+
+			project := decodeResponse[ProjectResponse](t, w)
+			assert.Equal(t, created.ID, project.ID)
+			assert.Equal(t, "ssh", project.GitAuthType)
+			assert.Equal(t, ComposeTimeouts{Pull: 1800, Wait: 90}, project.Timeouts)
+			assert.Equal(t, []string{"tcp db:5432"}, project.HealthChecks)
+			assert.Equal(t, []string{"run app migrate"}, project.PreDeployHooks)
+			assert.Empty(t, project.PostDeployHooks)
+		})
+	}
+}
+
+func TestGetProject(t *testing.T) {
+	projectID := uuid.New()
+
+	tests := []struct {
+		name           string
+		id             string
+		getErr         error
+		expectedStatus int
+	}{
+		{name: "found", id: projectID.String(), expectedStatus: http.StatusOK},
+		{
+			name:           "not found",
+			id:             projectID.String(),
+			getErr:         fmt.Errorf("project not found: %w", gorm.ErrRecordNotFound),
+			expectedStatus: http.StatusNotFound,
+		},
+		{name: "invalid id", id: "not-a-uuid", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app.SetProjectServiceForTesting(&mocks.MockProjectManager{
+				GetFunc: func(id uuid.UUID) (*services.Project, error) {
+					if tt.getErr != nil {
+						return nil, tt.getErr
+					}
+					return newTestProject(id), nil
+				},
+			})
+
+			w := httptest.NewRecorder()
+			req := addProjectIDToRequest(httptest.NewRequest(http.MethodGet, "/", nil), tt.id)
+			GetProject(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				project := decodeResponse[ProjectResponse](t, w)
+				assert.Equal(t, projectID, project.ID)
+				require.NotNil(t, project.LastCommit)
+				assert.Equal(t, "abc123", *project.LastCommit)
+			}
+		})
+	}
+}
+
+func TestUpdateProject(t *testing.T) {
+	projectID := uuid.New()
+
+	t.Run("partial update", func(t *testing.T) {
+		var updated *services.Project
+		app.SetProjectServiceForTesting(&mocks.MockProjectManager{
+			GetFunc: func(id uuid.UUID) (*services.Project, error) {
+				return newTestProject(id), nil
+			},
+			UpdateFunc: func(project *services.Project) error {
+				updated = project
+				return nil
+			},
+		})
+
+		w := httptest.NewRecorder()
+		body := `{"require_approval":true,"watch_images":true,"auto_rollback":true,"backup_volumes":true}`
+		req := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(body))
+		UpdateProject(w, addProjectIDToRequest(req, projectID.String()))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		require.NotNil(t, updated)
+		assert.True(t, updated.WatcherEnabled)
+		assert.True(t, updated.RequireApproval)
+		assert.True(t, updated.WatchImages)
+		assert.True(t, updated.AutoRollback)
+		assert.True(t, updated.BackupVolumes)
+		assert.Equal(t, "test-project", updated.Name)
+		assert.Equal(t, []string{"compose.yaml"}, updated.ComposeFiles)
+		require.NotNil(t, updated.GitAuth, "omitted credentials must be preserved")
+	})
+
+	t.Run("empty compose files rejected", func(t *testing.T) {
+		app.SetProjectServiceForTesting(&mocks.MockProjectManager{
+			GetFunc: func(id uuid.UUID) (*services.Project, error) {
+				return newTestProject(id), nil
+			},
+			UpdateFunc: func(project *services.Project) error {
+				t.Fatal("Update must not be called")
+				return nil
+			},
+		})
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(`{"compose_files":[]}`))
+		UpdateProject(w, addProjectIDToRequest(req, projectID.String()))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "compose_files are required", decodeResponse[ErrorResponse](t, w).Error)
+	})
+
+	t.Run("approval without watcher rejected", func(t *testing.T) {
+		var updated *services.Project
+		app.SetProjectServiceForTesting(&mocks.MockProjectManager{
+			GetFunc: func(id uuid.UUID) (*services.Project, error) {
+				return newTestProject(id), nil
+			},
+			UpdateFunc: func(project *services.Project) error {
+				updated = project
+				return services.ErrApprovalWithoutWatcher
+			},
+		})
+
+		w := httptest.NewRecorder()
+		body := `{"watcher_enabled":false,"require_approval":true}`
+		req := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(body))
+		UpdateProject(w, addProjectIDToRequest(req, projectID.String()))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		require.NotNil(t, updated)
+		assert.False(t, updated.WatcherEnabled)
+		assert.True(t, updated.RequireApproval)
+	})
+
+	t.Run("git ref", func(t *testing.T) {
+		var updated *services.Project
+		app.SetProjectServiceForTesting(&mocks.MockProjectManager{
+			GetFunc: func(id uuid.UUID) (*services.Project, error) {
+				return newTestProject(id), nil
+			},
+			UpdateFunc: func(project *services.Project) error {
+				updated = project
+				return nil
+			},
+		})
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(`{"git_ref_type":"tag","git_ref":"~1.4"}`))
+		UpdateProject(w, addProjectIDToRequest(req, projectID.String()))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		require.NotNil(t, updated)
+		assert.Equal(t, services.GitRefTypeTag, updated.GitRefType)
+		assert.Equal(t, "~1.4", updated.GitRef)
+
+		project := decodeResponse[ProjectResponse](t, w)
+		assert.Equal(t, "tag", project.GitRefType)
+		assert.Equal(t, "~1.4", project.GitRef)
+	})
+
+	t.Run("invalid git ref rejected", func(t *testing.T) {
+		app.SetProjectServiceForTesting(&mocks.MockProjectManager{
+			GetFunc: func(id uuid.UUID) (*services.Project, error) {
+				return newTestProject(id), nil
+			},
+			UpdateFunc: func(project *services.Project) error {
+				return fmt.Errorf("%w: %q is not a full commit SHA", services.ErrInvalidGitRef, project.GitRef)
+			},
+		})
+
+		w := httptest.NewRecorder()
+		body := `{"git_ref_type":"commit","git_ref":"abc"}`
+		req := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(body))
+		UpdateProject(w, addProjectIDToRequest(req, projectID.String()))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("timeouts", func(t *testing.T) {
+		var updated *services.Project
+		app.SetProjectServiceForTesting(&mocks.MockProjectManager{
+			GetFunc: func(id uuid.UUID) (*services.Project, error) {
+				project := newTestProject(id)
+				project.Timeouts = services.ComposeTimeouts{Pull: time.Hour}
+				return project, nil
+			},
+			UpdateFunc: func(project *services.Project) error {
+				updated = project
+				return nil
+			},
+		})
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(`{"timeouts":{"up":1200}}`))
+		UpdateProject(w, addProjectIDToRequest(req, projectID.String()))
+
+		// The timeouts are replaced as a whole
+		assert.Equal(t, http.StatusOK, w.Code)
+		require.NotNil(t, updated)
+		assert.Equal(t, services.ComposeTimeouts{Up: 20 * time.Minute}, updated.Timeouts)
+	})
+
+	t.Run("invalid timeouts rejected", func(t *testing.T) {
+		app.SetProjectServiceForTesting(&mocks.MockProjectManager{
+			GetFunc: func(id uuid.UUID) (*services.Project, error) {
+				return newTestProject(id), nil
+			},
+			UpdateFunc: func(project *services.Project) error {
+				return project.Timeouts.Validate()
+			},
+		})
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(`{"timeouts":{"wait":-1}}`))
+		UpdateProject(w, addProjectIDToRequest(req, projectID.String()))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, decodeResponse[ErrorResponse](t, w).Error, "wait timeout must not be negative")
+	})
+
+	t.Run("health checks", func(t *testing.T) {
+		var updated *services.Project
+		app.SetProjectServiceForTesting(&mocks.MockProjectManager{
+			GetFunc: func(id uuid.UUID) (*services.Project, error) {
+				return newTestProject(id), nil
+			},
+			UpdateFunc: func(project *services.Project) error {
+				updated = project
+				return nil
+			},
+		})
+
+		w := httptest.NewRecorder()
+		body := `{"health_checks":["http http://localhost:8080/health 200 ok","command db pg_isready"]}`
+		req := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(body))
+		UpdateProject(w, addProjectIDToRequest(req, projectID.String()))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		require.NotNil(t, updated)
+		require.Len(t, updated.HealthChecks, 2)
+		assert.Equal(t, "ok", updated.HealthChecks[0].Body)
+		assert.Equal(t, "pg_isready", updated.HealthChecks[1].Command)
+
+		project := decodeResponse[ProjectResponse](t, w)
+		assert.Equal(t, []string{"http http://localhost:8080/health 200 ok", "command db pg_isready"},
+			project.HealthChecks)
+	})
+
+	t.Run("hooks", func(t *testing.T) {
+		var updated *services.Project
+		app.SetProjectServiceForTesting(&mocks.MockProjectManager{
+			GetFunc: func(id uuid.UUID) (*services.Project, error) {
+				project := newTestProject(id)
+				project.PreDeployHooks = []services.Hook{{Type: services.HookTypeScript, Command: "make backup"}}
+				return project, nil
+			},
+			UpdateFunc: func(project *services.Project) error {
+				updated = project
+				return nil
+			},
+		})
+
+		w := httptest.NewRecorder()
+		body := `{"post_deploy_hooks":["exec cache redis-cli FLUSHALL"]}`
+		req := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(body))
+		UpdateProject(w, addProjectIDToRequest(req, projectID.String()))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		project := decodeResponse[ProjectResponse](t, w)
+		assert.Equal(t, []string{"script make backup"}, project.PreDeployHooks, "omitted hooks must be preserved")
+		assert.Equal(t, []string{"exec cache redis-cli FLUSHALL"}, project.PostDeployHooks)
+		require.NotNil(t, updated)
+		require.Len(t, updated.PostDeployHooks, 1)
+		assert.Equal(t, "cache", updated.PostDeployHooks[0].Service)
+	})
+
+	t.Run("invalid health check rejected", func(t *testing.T) {
+		app.SetProjectServiceForTesting(&mocks.MockProjectManager{
+			GetFunc: func(id uuid.UUID) (*services.Project, error) {
+				return newTestProject(id), nil
+			},
+			UpdateFunc: func(project *services.Project) error {
+				t.Fatal("Update must not be called")
+				return nil
+			},
+		})
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(`{"health_checks":["ping db"]}`))
+		UpdateProject(w, addProjectIDToRequest(req, projectID.String()))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, decodeResponse[ErrorResponse](t, w).Error, "must start with http, tcp or command")
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		app.SetProjectServiceForTesting(&mocks.MockProjectManager{
+			GetFunc: func(id uuid.UUID) (*services.Project, error) {
+				return nil, gorm.ErrRecordNotFound
+			},
+		})
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(`{}`))
+		UpdateProject(w, addProjectIDToRequest(req, projectID.String()))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestDeleteProject(t *testing.T) {
+	projectID := uuid.New()
+	var removed uuid.UUID
+	app.SetProjectServiceForTesting(&mocks.MockProjectManager{
+		RemoveFunc: func(id uuid.UUID) error {
+			removed = id
+			return nil
+		},
+	})
+
+	w := httptest.NewRecorder()
+	DeleteProject(w, addProjectIDToRequest(httptest.NewRequest(http.MethodDelete, "/", nil), projectID.String()))
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, projectID, removed)
+	assert.Empty(t, w.Body.String())
+}
+
+func TestListDeployments(t *testing.T) {
+	projectID := uuid.New()
+	startedAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	finishedAt := startedAt.Add(45 * time.Second)
+	app.SetProjectServiceForTesting(&mocks.MockProjectManager{
+		ListDeploymentsFunc: func(id uuid.UUID) ([]*services.Deployment, error) {
+			return []*services.Deployment{
+				{
+					ID:         uuid.New(),
+					ProjectID:  id,
+					CommitHash: "abc",
+					Status:     services.DeploymentStatusCompleted,
+					Trigger:    services.DeploymentTriggerWatcher,
+					Pull:       true,
+					StartedAt:  startedAt,
+					FinishedAt: &finishedAt,
+					HealthChecks: []services.HealthCheckResult{
+						{Check: "tcp db:5432", Passed: true, Message: "connected", Attempts: 2},
+					},
+				},
+			}, nil
+		},
+	})
+
+	w := httptest.NewRecorder()
+	ListDeployments(w, addProjectIDToRequest(httptest.NewRequest(http.MethodGet, "/", nil), projectID.String()))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	deployments := decodeResponse[[]DeploymentResponse](t, w)
+	require.Len(t, deployments, 1)
+	assert.Equal(t, projectID, deployments[0].ProjectID)
+	assert.Equal(t, "completed", deployments[0].Status)
+	assert.Equal(t, "watcher", deployments[0].Trigger)
+	assert.True(t, deployments[0].Pull)
+	assert.Equal(t, 45.0, deployments[0].DurationSeconds)
+	expectedResults := []services.HealthCheckResult{
+		{Check: "tcp db:5432", Passed: true, Message: "connected", Attempts: 2},
+	}
+	assert.Equal(t, expectedResults, deployments[0].HealthChecks)
+	require.NotNil(t, deployments[0].FinishedAt)
+	assert.True(t, finishedAt.Equal(*deployments[0].FinishedAt))
+}
+
+func TestGetStatus(t *testing.T) {
+	app.SetProjectServiceForTesting(&mocks.MockProjectManager{
+		GetStatusFunc: func(id uuid.UUID) (*services.ComposeStatus, error) {
+			return &services.ComposeStatus{
+				Status:     "running",
+				Uptime:     "1h",
+				Containers: []services.ContainerInfo{{Service: "web", Name: "web-1", State: "running"}},
+			}, nil
+		},
+	})
+
+	w := httptest.NewRecorder()
+	GetStatus(w, addProjectIDToRequest(httptest.NewRequest(http.MethodGet, "/", nil), uuid.New().String()))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	status := decodeResponse[StatusResponse](t, w)
+	assert.Equal(t, "running", status.Status)
+	require.Len(t, status.Containers, 1)
+	assert.Equal(t, "web", status.Containers[0].Service)
+}
+
+func TestGetConfig(t *testing.T) {
+	app.SetProjectServiceForTesting(&mocks.MockProjectManager{
+		GetConfigFunc: func(id uuid.UUID) (string, error) {
+			return "services: {}\n", nil
+		},
+	})
+
+	w := httptest.NewRecorder()
+	GetConfig(w, addProjectIDToRequest(httptest.NewRequest(http.MethodGet, "/", nil), uuid.New().String()))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "services: {}\n", decodeResponse[ConfigResponse](t, w).Config)
+}
+
+func TestPreviewDeploy(t *testing.T) {
+	projectID := uuid.New()
+
+	tests := []struct {
+		name           string
+		query          string
+		previewErr     error
+		expectedStatus int
+		expectedPull   bool
+	}{
+		{name: "default pull", expectedStatus: http.StatusOK, expectedPull: true},
+		{name: "no pull", query: "?pull=false", expectedStatus: http.StatusOK, expectedPull: false},
+		{name: "invalid pull", query: "?pull=maybe", expectedStatus: http.StatusBadRequest},
+		{
+			name:           "preview error",
+			previewErr:     errors.New("failed to fetch from remote"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedPull:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app.SetProjectServiceForTesting(&mocks.MockProjectManager{
+				PreviewDeployFunc: func(
+					ctx context.Context,
+					id uuid.UUID,
+					options services.DeployOptions,
+				) (*services.DeployPreview, error) {
+					assert.Equal(t, projectID, id)
+					assert.Equal(t, tt.expectedPull, options.Pull)
+					assert.Equal(t, services.DeploymentTriggerAPI, options.Trigger)
+					if tt.previewErr != nil {
+						return nil, tt.previewErr
+					}
+					return &services.DeployPreview{
+						CurrentCommit: "abc123",
+						TargetCommit:  "def456",
+						TargetRef:     "branch main",
+						Commits:       []services.GitCommit{{Hash: "def456", Author: "alice", Message: "Update nginx"}},
+						ConfigDiff:    "--- abc123\n+++ def456\n",
+					}, nil
+				},
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/"+tt.query, nil)
+			PreviewDeploy(w, addProjectIDToRequest(req, projectID.String()))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			response := decodeResponse[DeployPreviewResponse](t, w)
+			assert.Equal(t, "def456", response.TargetCommit)
+			assert.Equal(t, "branch main", response.TargetRef)
+			assert.False(t, response.UpToDate)
+			require.Len(t, response.Commits, 1)
+			assert.Equal(t, "Update nginx", response.Commits[0].Message)
+			assert.Equal(t, "--- abc123\n+++ def456\n", response.ConfigDiff)
+		})
+	}
+}
+
+func TestDeployProject(t *testing.T) {
+	projectID := uuid.New()
+	deploymentID := uuid.New()
+
+	tests := []struct {
+		name           string
+		query          string
+		deployErr      error
+		expectedStatus int
+		expectedPull   bool
+	}{
+		{name: "default pull", expectedStatus: http.StatusOK, expectedPull: true},
+		{name: "no pull", query: "?pull=false", expectedStatus: http.StatusOK, expectedPull: false},
+		{name: "invalid pull", query: "?pull=maybe", expectedStatus: http.StatusBadRequest},
+		{
+			name:           "deploy error",
+			deployErr:      errors.New("compose up failed"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedPull:   true,
+		},
+		{
+			name:           "cancelled",
+			deployErr:      services.ErrDeploymentCancelled,
+			expectedStatus: http.StatusConflict,
+			expectedPull:   true,
+		},
+		{
+			name:           "in progress",
+			deployErr:      fmt.Errorf("%w: started by watcher", services.ErrDeploymentInProgress),
+			expectedStatus: http.StatusConflict,
+			expectedPull:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPull *bool
+			app.SetProjectServiceForTesting(&mocks.MockProjectManager{
+				DeployStreamingFunc: func(
+					id uuid.UUID,
+					options services.DeployOptions,
+					outputChan chan<- string,
+				) error {
+					assert.Equal(t, services.DeploymentTriggerAPI, options.Trigger)
+					gotPull = &options.Pull
+					outputChan <- `{"type":"info","message":"deploying"}`
+					return tt.deployErr
+				},
+				ListDeploymentsFunc: func(id uuid.UUID) ([]*services.Deployment, error) {
+					return []*services.Deployment{
+						{ID: deploymentID, ProjectID: id, Status: services.DeploymentStatusCompleted},
+					}, nil
+				},
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/"+tt.query, nil)
+			DeployProject(w, addProjectIDToRequest(req, projectID.String()))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusBadRequest {
+				assert.Nil(t, gotPull)
+				return
+			}
+
+			require.NotNil(t, gotPull)
+			assert.Equal(t, tt.expectedPull, *gotPull)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, deploymentID, decodeResponse[DeploymentResponse](t, w).ID)
+			}
+		})
+	}
+}
+
+func TestRollbackProject(t *testing.T) {
+	projectID := uuid.New()
+	deploymentID := uuid.New()
+
+	tests := []struct {
+		name           string
+		query          string
+		rollbackErr    error
+		expectedStatus int
+		expectedTarget string
+	}{
+		{name: "previous deployment", expectedStatus: http.StatusOK},
+		{name: "to commit", query: "?to=abc123", expectedStatus: http.StatusOK, expectedTarget: "abc123"},
+		{
+			name:           "invalid target",
+			query:          "?to=zzz",
+			rollbackErr:    fmt.Errorf("%w: commit \"zzz\" was never deployed", services.ErrInvalidRollbackTarget),
+			expectedStatus: http.StatusBadRequest,
+			expectedTarget: "zzz",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotTarget string
+			app.SetProjectServiceForTesting(&mocks.MockProjectManager{
+				RollbackStreamingFunc: func(
+					id uuid.UUID,
+					target string,
+					options services.DeployOptions,
+					outputChan chan<- string,
+				) error {
+					assert.Equal(t, services.DeploymentTriggerAPI, options.Trigger)
+					gotTarget = target
+					outputChan <- `{"type":"info","message":"rolling back"}`
+					return tt.rollbackErr
+				},
+				ListDeploymentsFunc: func(id uuid.UUID) ([]*services.Deployment, error) {
+					return []*services.Deployment{
+						{ID: deploymentID, ProjectID: id, Status: services.DeploymentStatusCompleted, Rollback: true},
+					}, nil
+				},
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/"+tt.query, nil)
+			RollbackProject(w, addProjectIDToRequest(req, projectID.String()))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedTarget, gotTarget)
+			if tt.expectedStatus == http.StatusOK {
+				deployment := decodeResponse[DeploymentResponse](t, w)
+				assert.Equal(t, deploymentID, deployment.ID)
+				assert.True(t, deployment.Rollback)
+			}
+		})
+	}
+}
+
+func TestApproveDeployment(t *testing.T) {
+	projectID := uuid.New()
+	pendingID := uuid.New()
+
+	var approveErr error
+	app.SetProjectServiceForTesting(&mocks.MockProjectManager{
+		ApproveStreamingFunc: func(
+			id uuid.UUID,
+			deploymentID uuid.UUID,
+			options services.DeployOptions,
+			outputChan chan<- string,
+		) error {
+			assert.Equal(t, pendingID, deploymentID)
+			assert.Equal(t, services.DeploymentTriggerAPI, options.Trigger)
+			return approveErr
+		},
+		ListDeploymentsFunc: func(id uuid.UUID) ([]*services.Deployment, error) {
+			// The approved deployment was created when it was detected, so it isn't the newest
+			return []*services.Deployment{
+				{ID: uuid.New(), ProjectID: id, Status: services.DeploymentStatusRejected},
+				{ID: pendingID, ProjectID: id, Status: services.DeploymentStatusCompleted},
+			}, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	w := httptest.NewRecorder()
+	ApproveDeployment(w, addDeploymentIDToRequest(req, projectID.String(), pendingID.String()))
+	assert.Equal(t, http.StatusOK, w.Code)
+	deployment := decodeResponse[DeploymentResponse](t, w)
+	assert.Equal(t, pendingID, deployment.ID)
+	assert.Equal(t, "completed", deployment.Status)
+
+	approveErr = fmt.Errorf("%w: deployment %s is completed", services.ErrDeploymentNotPending, pendingID)
+	w = httptest.NewRecorder()
+	ApproveDeployment(w, addDeploymentIDToRequest(req, projectID.String(), pendingID.String()))
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = httptest.NewRecorder()
+	ApproveDeployment(w, addDeploymentIDToRequest(req, projectID.String(), "not-a-uuid"))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestRejectDeployment(t *testing.T) {
+	projectID := uuid.New()
+	pending := &services.Deployment{ID: uuid.New(), ProjectID: projectID, Status: services.DeploymentStatusPending}
+
+	app.SetProjectServiceForTesting(&mocks.MockProjectManager{
+		RejectDeploymentFunc: func(id, deploymentID uuid.UUID, options services.DeployOptions) error {
+			if deploymentID != pending.ID {
+				return fmt.Errorf("deployment not found: %w", gorm.ErrRecordNotFound)
+			}
+			assert.Equal(t, services.DeploymentTriggerAPI, options.Trigger)
+			pending.Status = services.DeploymentStatusRejected
+			return nil
+		},
+		ListDeploymentsFunc: func(id uuid.UUID) ([]*services.Deployment, error) {
+			return []*services.Deployment{pending}, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	w := httptest.NewRecorder()
+	RejectDeployment(w, addDeploymentIDToRequest(req, projectID.String(), pending.ID.String()))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "rejected", decodeResponse[DeploymentResponse](t, w).Status)
+
+	w = httptest.NewRecorder()
+	RejectDeployment(w, addDeploymentIDToRequest(req, projectID.String(), uuid.NewString()))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+// addDeploymentIDToRequest adds the project and deployment IDs to the request's route context
+func addDeploymentIDToRequest(req *http.Request, projectID, deploymentID string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", projectID)
+	rctx.URLParams.Add("deploymentID", deploymentID)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+func TestStopProject(t *testing.T) {
+	projectID := uuid.New()
+	stopped := false
+	app.SetProjectServiceForTesting(&mocks.MockProjectManager{
+		StopFunc: func(id uuid.UUID) error {
+			stopped = true
+			return nil
+		},
+		GetFunc: func(id uuid.UUID) (*services.Project, error) {
+			project := newTestProject(id)
+			project.Status = services.ProjectStatusStopped
+			return project, nil
+		},
+	})
+
+	w := httptest.NewRecorder()
+	StopProject(w, addProjectIDToRequest(httptest.NewRequest(http.MethodPost, "/", nil), projectID.String()))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, stopped)
+	assert.Equal(t, "stopped", decodeResponse[ProjectResponse](t, w).Status)
+}
+
+func TestCancelDeployment(t *testing.T) {
+	tests := []struct {
+		name           string
+		cancelErr      error
+		expectedStatus int
+	}{
+		{name: "in progress", expectedStatus: http.StatusAccepted},
+		{name: "not in progress", cancelErr: services.ErrNoDeploymentInProgress, expectedStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectID := uuid.New()
+			var cancelled uuid.UUID
+			app.SetProjectServiceForTesting(&mocks.MockProjectManager{
+				CancelDeploymentFunc: func(id uuid.UUID) error {
+					cancelled = id
+					return tt.cancelErr
+				},
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			CancelDeployment(w, addProjectIDToRequest(req, projectID.String()))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, projectID, cancelled)
+		})
+	}
+}
+
+func TestOpenAPISpec(t *testing.T) {
+	w := httptest.NewRecorder()
+	OpenAPISpec(w, httptest.NewRequest(http.MethodGet, "/api/v1/openapi.yaml", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/yaml", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "openapi: 3.0.3")
+	assert.Contains(t, w.Body.String(), "/projects/{id}/deploy:")
+}
+
+func TestDeleteProject_PermissionDenied(t *testing.T) {
+	projectID := uuid.New()
+	viewer := &services.User{ID: uuid.New(), Username: "viewer"}
+	app.SetProjectServiceForTesting(&mocks.MockProjectManager{
+		RemoveFunc: func(id uuid.UUID) error {
+			t.Fatal("Remove must not be called")
+			return nil
+		},
+	})
+	app.SetRoleServiceForTesting(&mocks.MockRoleManager{
+		AuthorizeFunc: func(user *services.User, projectID *uuid.UUID, required services.Role) error {
+			if required > services.RoleViewer {
+				return services.ErrPermissionDenied
+			}
+			return nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodDelete, "/", nil)
+	req = req.WithContext(services.ContextWithUser(req.Context(), viewer))
+	w := httptest.NewRecorder()
+	DeleteProject(w, addProjectIDToRequest(req, projectID.String()))
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, decodeResponse[ErrorResponse](t, w).Error, "permission denied")
+}
+
+// addNotifierIDToRequest adds the project and notifier IDs to the request's route context
+func addNotifierIDToRequest(req *http.Request, projectID, notifierID string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", projectID)
+	rctx.URLParams.Add("notifierID", notifierID)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+func TestListNotifiers(t *testing.T) {
+	projectID := uuid.New()
+	app.SetNotificationServiceForTesting(&mocks.MockNotificationManager{
+		ListNotifiersFunc: func(id uuid.UUID) ([]*services.Notifier, error) {
+			return []*services.Notifier{{
+				ID:        uuid.New(),
+				ProjectID: id,
+				Type:      services.NotifierTypeGotify,
+				Events:    []services.NotificationEvent{services.NotificationEventDeploymentFailed},
+				Config:    services.NotifierConfig{URL: "https://gotify.example.com/", Token: "app-token"},
+			}}, nil
+		},
+	})
+
+	w := httptest.NewRecorder()
+	ListNotifiers(w, addProjectIDToRequest(httptest.NewRequest(http.MethodGet, "/", nil), projectID.String()))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "app-token")
+	notifiers := decodeResponse[[]NotifierResponse](t, w)
+	require.Len(t, notifiers, 1)
+	assert.Equal(t, projectID, notifiers[0].ProjectID)
+	assert.Equal(t, "gotify", notifiers[0].Type)
+	assert.Equal(t, []string{"deployment_failed"}, notifiers[0].Events)
+	assert.Equal(t, "https://gotify.example.com", notifiers[0].Destination)
+}
+
+func TestCreateNotifier(t *testing.T) {
+	projectID := uuid.New()
+
+	tests := []struct {
+		name           string
+		body           string
+		serviceErr     error
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:           "ntfy notifier",
+			body:           `{"type":"ntfy","url":"https://ntfy.sh/deploys","events":["deployment_failed"]}`,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "invalid type",
+			body:           `{"type":"pager","url":"https://example.com"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "invalid notifier type",
+		},
+		{
+			name:           "invalid event",
+			body:           `{"type":"ntfy","url":"https://ntfy.sh/deploys","events":["deployed"]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "invalid notification event",
+		},
+		{
+			name:           "rejected by service",
+			body:           `{"type":"gotify","url":"https://gotify.example.com"}`,
+			serviceErr:     fmt.Errorf("%w: gotify requires an application token", services.ErrInvalidNotifier),
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "gotify requires an application token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var added *services.Notifier
+			app.SetNotificationServiceForTesting(&mocks.MockNotificationManager{
+				AddNotifierFunc: func(notifier *services.Notifier) error {
+					added = notifier
+					notifier.ID = uuid.New()
+					return tt.serviceErr
+				},
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			CreateNotifier(w, addProjectIDToRequest(req, projectID.String()))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedError != "" {
+				assert.Contains(t, decodeResponse[ErrorResponse](t, w).Error, tt.expectedError)
+				return
+			}
+			require.NotNil(t, added)
+			assert.Equal(t, projectID, added.ProjectID)
+			assert.Equal(t, "https://ntfy.sh/deploys", added.Config.URL)
+			response := decodeResponse[NotifierResponse](t, w)
+			assert.Equal(t, added.ID, response.ID)
+			assert.Equal(t, "ntfy", response.Type)
+		})
+	}
+}
+
+func TestDeleteNotifier(t *testing.T) {
+	projectID, notifierID := uuid.New(), uuid.New()
+	app.SetNotificationServiceForTesting(&mocks.MockNotificationManager{
+		RemoveNotifierFunc: func(pID, nID uuid.UUID) error {
+			if nID != notifierID {
+				return gorm.ErrRecordNotFound
+			}
+			return nil
+		},
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodDelete, "/", nil)
+	DeleteNotifier(w, addNotifierIDToRequest(req, projectID.String(), notifierID.String()))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = httptest.NewRecorder()
+	DeleteNotifier(w, addNotifierIDToRequest(req, projectID.String(), uuid.NewString()))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	DeleteNotifier(w, addNotifierIDToRequest(req, projectID.String(), "not-a-uuid"))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestTestNotifier(t *testing.T) {
+	projectID, notifierID := uuid.New(), uuid.New()
+	var deliveryErr error
+	app.SetNotificationServiceForTesting(&mocks.MockNotificationManager{
+		TestNotifierFunc: func(pID, nID uuid.UUID) error {
+			return deliveryErr
+		},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	w := httptest.NewRecorder()
+	TestNotifier(w, addNotifierIDToRequest(req, projectID.String(), notifierID.String()))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	deliveryErr = fmt.Errorf("%w: slack: server responded with HTTP 404", services.ErrNotificationDelivery)
+	w = httptest.NewRecorder()
+	TestNotifier(w, addNotifierIDToRequest(req, projectID.String(), notifierID.String()))
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+	assert.Contains(t, decodeResponse[ErrorResponse](t, w).Error, "HTTP 404")
+}
+
+// addCatalogIDToRequest adds the catalog ID to the request's route context
+func addCatalogIDToRequest(req *http.Request, catalogID string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("catalogID", catalogID)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+func newTestCatalog(id uuid.UUID) *services.Catalog {
+	return &services.Catalog{
+		ID:        id,
+		Name:      "platform",
+		GitURL:    "https://github.com/org/platform.git",
+		GitBranch: "main",
+		GitAuth:   &services.GitAuthConfig{HTTPAuth: &services.GitHTTPAuthConfig{Username: "u", Password: "hunter2"}},
+		Secrets:   []string{"web-db-password=s3cret", "api-token=t0ken"},
+	}
+}
+
+func TestListCatalogs(t *testing.T) {
+	catalogID := uuid.New()
+	app.SetCatalogServiceForTesting(&mocks.MockCatalogManager{
+		ListFunc: func() ([]*services.Catalog, error) {
+			return []*services.Catalog{newTestCatalog(catalogID)}, nil
+		},
+	})
+
+	w := httptest.NewRecorder()
+	ListCatalogs(w, httptest.NewRequest(http.MethodGet, "/api/v1/catalogs", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "s3cret")
+	assert.NotContains(t, w.Body.String(), "hunter2")
+	catalogs := decodeResponse[[]CatalogResponse](t, w)
+	require.Len(t, catalogs, 1)
+	assert.Equal(t, catalogID, catalogs[0].ID)
+	assert.Equal(t, "http", catalogs[0].GitAuthType)
+	assert.Equal(t, []string{"web-db-password", "api-token"}, catalogs[0].SecretNames)
+}
+
+func TestCreateCatalog(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		serviceErr     error
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name: "catalog with secrets",
+			body: `{"name":"platform","git_url":"https://github.com/org/platform.git",` +
+				`"secrets":{"web-db-password":"s3cret","api-token":"t0ken"}}`,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "rejected by service",
+			body:           `{"git_url":"https://github.com/org/platform.git"}`,
+			serviceErr:     fmt.Errorf("%w: name is required", services.ErrInvalidCatalog),
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "name is required",
+		},
+		{
+			name:           "unknown field",
+			body:           `{"name":"platform","projects":[]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "unknown field",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created *services.Catalog
+			app.SetCatalogServiceForTesting(&mocks.MockCatalogManager{
+				CreateFunc: func(catalog *services.Catalog) (*services.Catalog, error) {
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					created = catalog
+					catalog.ID = uuid.New()
+					return catalog, nil
+				},
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			CreateCatalog(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedError != "" {
+				assert.Contains(t, decodeResponse[ErrorResponse](t, w).Error, tt.expectedError)
+				return
+			}
+			require.NotNil(t, created)
+			assert.Equal(t, []string{"api-token=t0ken", "web-db-password=s3cret"}, created.Secrets)
+			assert.NotContains(t, w.Body.String(), "s3cret")
+			assert.Equal(t, created.ID, decodeResponse[CatalogResponse](t, w).ID)
+		})
+	}
+}
+
+func TestGetCatalog(t *testing.T) {
+	catalogID := uuid.New()
+	app.SetCatalogServiceForTesting(&mocks.MockCatalogManager{
+		GetFunc: func(id uuid.UUID) (*services.Catalog, error) {
+			if id != catalogID {
+				return nil, gorm.ErrRecordNotFound
+			}
+			return newTestCatalog(id), nil
+		},
+	})
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	w := httptest.NewRecorder()
+	GetCatalog(w, addCatalogIDToRequest(req, catalogID.String()))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "platform", decodeResponse[CatalogResponse](t, w).Name)
+
+	w = httptest.NewRecorder()
+	GetCatalog(w, addCatalogIDToRequest(req, uuid.NewString()))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	GetCatalog(w, addCatalogIDToRequest(req, "not-a-uuid"))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestUpdateCatalog(t *testing.T) {
+	catalogID := uuid.New()
+	var updated *services.Catalog
+	app.SetCatalogServiceForTesting(&mocks.MockCatalogManager{
+		GetFunc: func(id uuid.UUID) (*services.Catalog, error) {
+			return newTestCatalog(id), nil
+		},
+		UpdateFunc: func(catalog *services.Catalog) error {
+			updated = catalog
+			return nil
+		},
+	})
+
+	body := `{"git_branch":"production","secrets":{"api-token":"n3w","registry-password":"r3g"},` +
+		`"unset_secrets":["web-db-password"]}`
+	req := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	UpdateCatalog(w, addCatalogIDToRequest(req, catalogID.String()))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.NotNil(t, updated)
+	assert.Equal(t, "production", updated.GitBranch)
+	assert.Equal(t, []string{"api-token=n3w", "registry-password=r3g"}, updated.Secrets)
+	// Credentials are kept unless given
+	assert.Equal(t, "u", updated.GitAuth.HTTPAuth.Username)
+	assert.Equal(t, []string{"api-token", "registry-password"}, decodeResponse[CatalogResponse](t, w).SecretNames)
+}
+
+func TestDeleteCatalog(t *testing.T) {
+	catalogID := uuid.New()
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		removeProjects bool
+	}{
+		{name: "keep projects", expectedStatus: http.StatusNoContent},
+		{
+			name:           "remove projects",
+			query:          "?remove_projects=true",
+			expectedStatus: http.StatusNoContent,
+			removeProjects: true,
+		},
+		{name: "invalid parameter", query: "?remove_projects=maybe", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			removed := false
+			var removedProjects bool
+			app.SetCatalogServiceForTesting(&mocks.MockCatalogManager{
+				RemoveFunc: func(id uuid.UUID, removeProjects bool) error {
+					removed, removedProjects = true, removeProjects
+					return nil
+				},
+			})
+
+			req := httptest.NewRequest(http.MethodDelete, "/"+tt.query, nil)
+			w := httptest.NewRecorder()
+			DeleteCatalog(w, addCatalogIDToRequest(req, catalogID.String()))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedStatus == http.StatusNoContent, removed)
+			assert.Equal(t, tt.removeProjects, removedProjects)
+		})
+	}
+}
+
+func TestSyncCatalog(t *testing.T) {
+	catalogID := uuid.New()
+	var syncErr error
+	app.SetCatalogServiceForTesting(&mocks.MockCatalogManager{
+		SyncFunc: func(ctx context.Context, id uuid.UUID) (*services.CatalogSyncResult, error) {
+			if syncErr != nil {
+				return nil, syncErr
+			}
+			return &services.CatalogSyncResult{
+				Commit:  "abc123",
+				Created: []string{"web"},
+				Errors:  []string{"admin: a project with this name exists and is not managed by the catalog"},
+			}, nil
+		},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	w := httptest.NewRecorder()
+	SyncCatalog(w, addCatalogIDToRequest(req, catalogID.String()))
+	assert.Equal(t, http.StatusOK, w.Code)
+	result := decodeResponse[CatalogSyncResponse](t, w)
+	assert.Equal(t, []string{"web"}, result.Created)
+	assert.Equal(t, []string{}, result.Removed)
+	assert.Len(t, result.Errors, 1)
+
+	syncErr = fmt.Errorf("%w: .oar-catalog.yaml: project 1: name is required", services.ErrInvalidCatalog)
+	w = httptest.NewRecorder()
+	SyncCatalog(w, addCatalogIDToRequest(req, catalogID.String()))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestSyncCatalog_PermissionDenied(t *testing.T) {
+	viewer := &services.User{ID: uuid.New(), Username: "viewer"}
+	app.SetCatalogServiceForTesting(&mocks.MockCatalogManager{
+		SyncFunc: func(ctx context.Context, id uuid.UUID) (*services.CatalogSyncResult, error) {
+			t.Fatal("Sync must not be called")
+			return nil, nil
+		},
+	})
+	app.SetRoleServiceForTesting(&mocks.MockRoleManager{
+		AuthorizeFunc: func(user *services.User, projectID *uuid.UUID, required services.Role) error {
+			if required > services.RoleViewer {
+				return services.ErrPermissionDenied
+			}
+			return nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req = req.WithContext(services.ContextWithUser(req.Context(), viewer))
+	w := httptest.NewRecorder()
+	SyncCatalog(w, addCatalogIDToRequest(req, uuid.NewString()))
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
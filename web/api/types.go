@@ -0,0 +1,514 @@
+package api
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/oar-cd/oar/services"
+)
+
+// ErrorResponse is returned for every non-2xx API response
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// GitAuthRequest carries Git credentials in create and update requests.
+// Credentials are write-only: they are never returned by the API.
+type GitAuthRequest struct {
+	HTTP *GitHTTPAuthRequest `json:"http,omitempty"`
+	SSH  *GitSSHAuthRequest  `json:"ssh,omitempty"`
+}
+
+type GitHTTPAuthRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type GitSSHAuthRequest struct {
+	PrivateKey string `json:"private_key"`
+	User       string `json:"user"`
+}
+
+// ComposeTimeouts are the Docker Compose timeouts of a project in seconds, 0 uses the server's default
+type ComposeTimeouts struct {
+	Pull   int `json:"pull"`
+	Up     int `json:"up"`
+	Wait   int `json:"wait"`
+	Health int `json:"health"` // Time for the health checks to pass
+}
+
+// ProjectCreateRequest is the body of POST /api/v1/projects
+type ProjectCreateRequest struct {
+	Name            string           `json:"name"`
+	GitURL          string           `json:"git_url"`
+	GitBranch       string           `json:"git_branch"`
+	GitRefType      string           `json:"git_ref_type"` // branch (default), tag or commit
+	GitRef          string           `json:"git_ref"`      // Tag pattern or semver constraint, or commit SHA
+	GitAuth         *GitAuthRequest  `json:"git_auth,omitempty"`
+	ComposeFiles    []string         `json:"compose_files"`
+	Variables       []string         `json:"variables"`
+	WatcherEnabled  *bool            `json:"watcher_enabled,omitempty"`
+	RequireApproval *bool            `json:"require_approval,omitempty"`
+	WatchImages     *bool            `json:"watch_images,omitempty"`
+	AutoRollback    bool             `json:"auto_rollback"`
+	BackupVolumes   bool             `json:"backup_volumes"`
+	HealthChecks    []string         `json:"health_checks"`    // One per entry, such as "tcp <host>:<port>"
+	PreDeployHooks  []string         `json:"pre_deploy_hooks"` // One per entry, such as "run <service> <command>"
+	PostDeployHooks []string         `json:"post_deploy_hooks"`
+	Timeouts        *ComposeTimeouts `json:"timeouts,omitempty"`
+}
+
+// ProjectUpdateRequest is the body of PATCH /api/v1/projects/{id}.
+// Omitted fields are left unchanged.
+type ProjectUpdateRequest struct {
+	Name            *string          `json:"name,omitempty"`
+	GitRefType      *string          `json:"git_ref_type,omitempty"`
+	GitRef          *string          `json:"git_ref,omitempty"`
+	GitAuth         *GitAuthRequest  `json:"git_auth,omitempty"`
+	ComposeFiles    *[]string        `json:"compose_files,omitempty"`
+	Variables       *[]string        `json:"variables,omitempty"`
+	WatcherEnabled  *bool            `json:"watcher_enabled,omitempty"`
+	RequireApproval *bool            `json:"require_approval,omitempty"`
+	WatchImages     *bool            `json:"watch_images,omitempty"`
+	AutoRollback    *bool            `json:"auto_rollback,omitempty"`
+	BackupVolumes   *bool            `json:"backup_volumes,omitempty"`
+	HealthChecks    *[]string        `json:"health_checks,omitempty"`
+	PreDeployHooks  *[]string        `json:"pre_deploy_hooks,omitempty"`
+	PostDeployHooks *[]string        `json:"post_deploy_hooks,omitempty"`
+	Timeouts        *ComposeTimeouts `json:"timeouts,omitempty"`
+}
+
+// ProjectResponse is the API representation of a project
+type ProjectResponse struct {
+	ID               uuid.UUID       `json:"id"`
+	Name             string          `json:"name"`
+	GitURL           string          `json:"git_url"`
+	GitBranch        string          `json:"git_branch"`
+	GitRefType       string          `json:"git_ref_type"`
+	GitRef           string          `json:"git_ref"`
+	GitAuthType      string          `json:"git_auth_type"`
+	ComposeFiles     []string        `json:"compose_files"`
+	Variables        []string        `json:"variables"`
+	Status           string          `json:"status"`
+	DesiredState     string          `json:"desired_state"` // running or stopped, empty if not recorded yet
+	LastCommit       *string         `json:"last_commit"`
+	RolledBackCommit *string         `json:"rolled_back_commit"`
+	WatcherEnabled   bool            `json:"watcher_enabled"`
+	RequireApproval  bool            `json:"require_approval"`
+	WatchImages      bool            `json:"watch_images"`
+	AutoRollback     bool            `json:"auto_rollback"`
+	BackupVolumes    bool            `json:"backup_volumes"`
+	HealthChecks     []string        `json:"health_checks"`
+	PreDeployHooks   []string        `json:"pre_deploy_hooks"`
+	PostDeployHooks  []string        `json:"post_deploy_hooks"`
+	Timeouts         ComposeTimeouts `json:"timeouts"`
+	Drift            []string        `json:"drift"`      // Differences from the deployed commit, empty when in sync
+	CatalogID        *uuid.UUID      `json:"catalog_id"` // Catalog managing the project, null if added by hand
+	CreatedAt        time.Time       `json:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at"`
+}
+
+// DeploymentResponse is the API representation of a deployment
+type DeploymentResponse struct {
+	ID              uuid.UUID                    `json:"id"`
+	ProjectID       uuid.UUID                    `json:"project_id"`
+	CommitHash      string                       `json:"commit_hash"`
+	Status          string                       `json:"status"`
+	Output          string                       `json:"output"`
+	Rollback        bool                         `json:"rollback"`
+	PreviousCommit  string                       `json:"previous_commit"`
+	Pull            bool                         `json:"pull"`
+	Trigger         string                       `json:"trigger"`
+	Actor           string                       `json:"actor"`
+	HealthChecks    []services.HealthCheckResult `json:"health_checks"`
+	StartedAt       *time.Time                   `json:"started_at"`
+	FinishedAt      *time.Time                   `json:"finished_at"`
+	DurationSeconds float64                      `json:"duration_seconds"`
+	CreatedAt       time.Time                    `json:"created_at"`
+	UpdatedAt       time.Time                    `json:"updated_at"`
+}
+
+// NotifierCreateRequest is the body of POST /api/v1/projects/{id}/notifiers.
+// Which destination fields are required depends on the type. Credentials are write-only.
+type NotifierCreateRequest struct {
+	Type         string   `json:"type"`   // webhook, slack, email, ntfy or gotify
+	Events       []string `json:"events"` // Events to notify about, all events if empty
+	URL          string   `json:"url"`
+	Token        string   `json:"token"`
+	SMTPHost     string   `json:"smtp_host"`
+	SMTPPort     int      `json:"smtp_port"`
+	SMTPUsername string   `json:"smtp_username"`
+	SMTPPassword string   `json:"smtp_password"`
+	From         string   `json:"from"`
+	To           []string `json:"to"`
+}
+
+// NotifierResponse is the API representation of a notifier, without its credentials
+type NotifierResponse struct {
+	ID          uuid.UUID `json:"id"`
+	ProjectID   uuid.UUID `json:"project_id"`
+	Type        string    `json:"type"`
+	Events      []string  `json:"events"`
+	Destination string    `json:"destination"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// CatalogCreateRequest is the body of POST /api/v1/catalogs.
+// Secret values are write-only: only their names are returned by the API.
+type CatalogCreateRequest struct {
+	Name      string            `json:"name"`
+	GitURL    string            `json:"git_url"`
+	GitBranch string            `json:"git_branch"`
+	GitAuth   *GitAuthRequest   `json:"git_auth,omitempty"`
+	Secrets   map[string]string `json:"secrets"` // Values by name, referenced by the projects of the catalog
+}
+
+// CatalogUpdateRequest is the body of PATCH /api/v1/catalogs/{catalogID}.
+// Omitted fields are left unchanged.
+type CatalogUpdateRequest struct {
+	GitBranch    *string           `json:"git_branch,omitempty"`
+	GitAuth      *GitAuthRequest   `json:"git_auth,omitempty"`
+	Secrets      map[string]string `json:"secrets,omitempty"`       // Secrets to set, replacing those of the same name
+	UnsetSecrets []string          `json:"unset_secrets,omitempty"` // Names of secrets to remove
+}
+
+// CatalogResponse is the API representation of a catalog, without its credentials and secret values
+type CatalogResponse struct {
+	ID            uuid.UUID  `json:"id"`
+	Name          string     `json:"name"`
+	GitURL        string     `json:"git_url"`
+	GitBranch     string     `json:"git_branch"`
+	GitAuthType   string     `json:"git_auth_type"`
+	SecretNames   []string   `json:"secret_names"`
+	LastCommit    *string    `json:"last_commit"`
+	LastSyncedAt  *time.Time `json:"last_synced_at"`
+	LastSyncError string     `json:"last_sync_error"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// CatalogSyncResponse reports what syncing a catalog changed
+type CatalogSyncResponse struct {
+	Commit  string   `json:"commit"`
+	Created []string `json:"created"`
+	Updated []string `json:"updated"`
+	Removed []string `json:"removed"`
+	Errors  []string `json:"errors"` // Projects that could not be synced and why
+}
+
+// ContainerResponse is the API representation of a single container
+type ContainerResponse struct {
+	Service    string `json:"service"`
+	Name       string `json:"name"`
+	State      string `json:"state"`
+	Status     string `json:"status"`
+	RunningFor string `json:"running_for"`
+}
+
+// StatusResponse is the API representation of a project's container status
+type StatusResponse struct {
+	Status     string              `json:"status"`
+	Uptime     string              `json:"uptime"`
+	Containers []ContainerResponse `json:"containers"`
+}
+
+// ConfigResponse wraps the rendered Docker Compose configuration
+type ConfigResponse struct {
+	Config string `json:"config"`
+}
+
+// DeployPreviewResponse is what deploying a project would change
+type DeployPreviewResponse struct {
+	CurrentCommit string           `json:"current_commit"`
+	TargetCommit  string           `json:"target_commit"`
+	TargetRef     string           `json:"target_ref"`
+	UpToDate      bool             `json:"up_to_date"`
+	Commits       []CommitResponse `json:"commits"`
+	ConfigDiff    string           `json:"config_diff"`
+}
+
+// CommitResponse is the API representation of a commit of a project's repository
+type CommitResponse struct {
+	Hash    string    `json:"hash"`
+	Author  string    `json:"author"`
+	Date    time.Time `json:"date"`
+	Message string    `json:"message"`
+}
+
+// toGitAuthConfig converts API credentials to the service representation
+func (a *GitAuthRequest) toGitAuthConfig() *services.GitAuthConfig {
+	if a == nil {
+		return nil
+	}
+
+	if a.HTTP != nil {
+		return &services.GitAuthConfig{
+			HTTPAuth: &services.GitHTTPAuthConfig{
+				Username: a.HTTP.Username,
+				Password: a.HTTP.Password,
+			},
+		}
+	}
+
+	if a.SSH != nil {
+		return &services.GitAuthConfig{
+			SSHAuth: &services.GitSSHAuthConfig{
+				PrivateKey: a.SSH.PrivateKey,
+				User:       a.SSH.User,
+			},
+		}
+	}
+
+	return nil
+}
+
+// toComposeTimeouts converts API timeouts to the service representation
+func (t *ComposeTimeouts) toComposeTimeouts() services.ComposeTimeouts {
+	if t == nil {
+		return services.ComposeTimeouts{}
+	}
+	return services.ComposeTimeouts{
+		Pull:   time.Duration(t.Pull) * time.Second,
+		Up:     time.Duration(t.Up) * time.Second,
+		Wait:   time.Duration(t.Wait) * time.Second,
+		Health: time.Duration(t.Health) * time.Second,
+	}
+}
+
+// newComposeTimeouts converts service timeouts to their API representation
+func newComposeTimeouts(t services.ComposeTimeouts) ComposeTimeouts {
+	return ComposeTimeouts{
+		Pull:   int(t.Pull / time.Second),
+		Up:     int(t.Up / time.Second),
+		Wait:   int(t.Wait / time.Second),
+		Health: int(t.Health / time.Second),
+	}
+}
+
+// gitAuthType returns the authentication method name without exposing credentials
+func gitAuthType(auth *services.GitAuthConfig) string {
+	switch {
+	case auth == nil:
+		return "none"
+	case auth.HTTPAuth != nil:
+		return services.GitAuthTypeHTTP.String()
+	case auth.SSHAuth != nil:
+		return services.GitAuthTypeSSH.String()
+	default:
+		return "none"
+	}
+}
+
+// newProjectResponse converts a service project to its API representation
+func newProjectResponse(p *services.Project) ProjectResponse {
+	return ProjectResponse{
+		ID:               p.ID,
+		Name:             p.Name,
+		GitURL:           p.GitURL,
+		GitBranch:        p.GitBranch,
+		GitRefType:       p.GitRefType.String(),
+		GitRef:           p.GitRef,
+		GitAuthType:      gitAuthType(p.GitAuth),
+		ComposeFiles:     nonNil(p.ComposeFiles),
+		Variables:        nonNil(p.Variables),
+		Status:           p.Status.String(),
+		DesiredState:     p.DesiredState.String(),
+		LastCommit:       p.LastCommit,
+		RolledBackCommit: p.RolledBackCommit,
+		WatcherEnabled:   p.WatcherEnabled,
+		RequireApproval:  p.RequireApproval,
+		WatchImages:      p.WatchImages,
+		AutoRollback:     p.AutoRollback,
+		BackupVolumes:    p.BackupVolumes,
+		HealthChecks:     services.FormatHealthChecks(p.HealthChecks),
+		PreDeployHooks:   services.FormatHooks(p.PreDeployHooks),
+		PostDeployHooks:  services.FormatHooks(p.PostDeployHooks),
+		Timeouts:         newComposeTimeouts(p.Timeouts),
+		Drift:            nonNil(p.Drift),
+		CatalogID:        p.CatalogID,
+		CreatedAt:        p.CreatedAt,
+		UpdatedAt:        p.UpdatedAt,
+	}
+}
+
+// newDeploymentResponse converts a service deployment to its API representation
+func newDeploymentResponse(d *services.Deployment) DeploymentResponse {
+	var startedAt *time.Time
+	if !d.StartedAt.IsZero() {
+		startedAt = &d.StartedAt
+	}
+
+	return DeploymentResponse{
+		ID:              d.ID,
+		ProjectID:       d.ProjectID,
+		CommitHash:      d.CommitHash,
+		Status:          d.Status.String(),
+		Output:          d.Output,
+		Rollback:        d.Rollback,
+		PreviousCommit:  d.PreviousCommit,
+		Pull:            d.Pull,
+		Trigger:         d.Trigger.String(),
+		Actor:           d.Actor,
+		HealthChecks:    nonNil(d.HealthChecks),
+		StartedAt:       startedAt,
+		FinishedAt:      d.FinishedAt,
+		DurationSeconds: d.Duration().Seconds(),
+		CreatedAt:       d.CreatedAt,
+		UpdatedAt:       d.UpdatedAt,
+	}
+}
+
+// toNotifier converts a notifier creation request to the service representation
+func (req *NotifierCreateRequest) toNotifier(projectID uuid.UUID) (*services.Notifier, error) {
+	notifierType, err := services.ParseNotifierType(req.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []services.NotificationEvent
+	for _, name := range req.Events {
+		event, err := services.ParseNotificationEvent(name)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return &services.Notifier{
+		ProjectID: projectID,
+		Type:      notifierType,
+		Events:    events,
+		Config: services.NotifierConfig{
+			URL:          req.URL,
+			Token:        req.Token,
+			SMTPHost:     req.SMTPHost,
+			SMTPPort:     req.SMTPPort,
+			SMTPUsername: req.SMTPUsername,
+			SMTPPassword: req.SMTPPassword,
+			From:         req.From,
+			To:           req.To,
+		},
+	}, nil
+}
+
+// newNotifierResponse converts a service notifier to its API representation
+func newNotifierResponse(n *services.Notifier) NotifierResponse {
+	events := make([]string, len(n.Events))
+	for i, event := range n.Events {
+		events[i] = string(event)
+	}
+
+	return NotifierResponse{
+		ID:          n.ID,
+		ProjectID:   n.ProjectID,
+		Type:        string(n.Type),
+		Events:      events,
+		Destination: n.Destination(),
+		CreatedAt:   n.CreatedAt,
+		UpdatedAt:   n.UpdatedAt,
+	}
+}
+
+// secretsFromMap converts secrets by name to the KEY=value form of the service, sorted by name
+func secretsFromMap(secrets map[string]string) []string {
+	result := make([]string, 0, len(secrets))
+	for name, value := range secrets {
+		result = append(result, name+"="+value)
+	}
+	slices.Sort(result)
+	return result
+}
+
+// applyCatalogUpdateRequest applies the fields set in a catalog update request
+func applyCatalogUpdateRequest(catalog *services.Catalog, req *CatalogUpdateRequest) {
+	if req.GitBranch != nil {
+		catalog.GitBranch = *req.GitBranch
+	}
+	if req.GitAuth != nil {
+		catalog.GitAuth = req.GitAuth.toGitAuthConfig()
+	}
+
+	replaced := slices.Concat(req.UnsetSecrets, slices.Collect(maps.Keys(req.Secrets)))
+	secrets := slices.DeleteFunc(catalog.Secrets, func(secret string) bool {
+		name, _, _ := strings.Cut(secret, "=")
+		return slices.Contains(replaced, name)
+	})
+	catalog.Secrets = append(secrets, secretsFromMap(req.Secrets)...)
+}
+
+// newCatalogResponse converts a service catalog to its API representation
+func newCatalogResponse(c *services.Catalog) CatalogResponse {
+	return CatalogResponse{
+		ID:            c.ID,
+		Name:          c.Name,
+		GitURL:        c.GitURL,
+		GitBranch:     c.GitBranch,
+		GitAuthType:   gitAuthType(c.GitAuth),
+		SecretNames:   c.SecretNames(),
+		LastCommit:    c.LastCommit,
+		LastSyncedAt:  c.LastSyncedAt,
+		LastSyncError: c.LastSyncError,
+		CreatedAt:     c.CreatedAt,
+		UpdatedAt:     c.UpdatedAt,
+	}
+}
+
+// newCatalogSyncResponse converts a catalog sync result to its API representation
+func newCatalogSyncResponse(r *services.CatalogSyncResult) CatalogSyncResponse {
+	return CatalogSyncResponse{
+		Commit:  r.Commit,
+		Created: nonNil(r.Created),
+		Updated: nonNil(r.Updated),
+		Removed: nonNil(r.Removed),
+		Errors:  nonNil(r.Errors),
+	}
+}
+
+// newStatusResponse converts a compose status to its API representation
+func newStatusResponse(s *services.ComposeStatus) StatusResponse {
+	containers := make([]ContainerResponse, len(s.Containers))
+	for i, c := range s.Containers {
+		containers[i] = ContainerResponse{
+			Service:    c.Service,
+			Name:       c.Name,
+			State:      c.State,
+			Status:     c.Status,
+			RunningFor: c.RunningFor,
+		}
+	}
+
+	return StatusResponse{
+		Status:     s.Status,
+		Uptime:     s.Uptime,
+		Containers: containers,
+	}
+}
+
+// newDeployPreviewResponse converts a deploy preview to its API representation
+func newDeployPreviewResponse(p *services.DeployPreview) DeployPreviewResponse {
+	commits := make([]CommitResponse, len(p.Commits))
+	for i, c := range p.Commits {
+		commits[i] = CommitResponse{Hash: c.Hash, Author: c.Author, Date: c.Date, Message: c.Message}
+	}
+
+	return DeployPreviewResponse{
+		CurrentCommit: p.CurrentCommit,
+		TargetCommit:  p.TargetCommit,
+		TargetRef:     p.TargetRef,
+		UpToDate:      p.UpToDate(),
+		Commits:       commits,
+		ConfigDiff:    p.ConfigDiff,
+	}
+}
+
+// nonNil makes sure empty lists are encoded as [] rather than null
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
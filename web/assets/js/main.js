@@ -450,6 +450,14 @@ document.addEventListener('DOMContentLoaded', function() {
             // Update button state
             elements.button.disabled = true;
 
+            // Show the cancel button while the operation runs, if it can be cancelled
+            const cancelButton = config.cancelEndpoint ? document.getElementById(`${config.btnId}-cancel`) : null;
+            if (cancelButton) {
+                cancelButton.disabled = false;
+                cancelButton.dataset.cancelRequested = 'false';
+                cancelButton.classList.remove('hidden');
+            }
+
             // Append connecting message (preserve existing content)
             elements.content.innerHTML += `\n<span class="deploy-text-frontend-generic">${config.connectingMsg}</span>\n`;
             elements.content.className = 'streaming-output';
@@ -492,9 +500,15 @@ document.addEventListener('DOMContentLoaded', function() {
 
                 return processServerSentEvents(reader, decoder, elements.content, elements.output, (hasError) => {
                     elements.button.disabled = false;
+                    if (cancelButton) {
+                        cancelButton.classList.add('hidden');
+                    }
 
                     // Check if operation was successful based on tracked error state
-                    if (hasError) {
+                    if (hasError && cancelButton && cancelButton.dataset.cancelRequested === 'true') {
+                        elements.content.innerHTML += `\n<span class="deploy-text-frontend-error">${config.cancelledMsg}</span>\n`;
+                        showToast(config.cancelledMsg, 'warning');
+                    } else if (hasError) {
                         elements.content.innerHTML += `\n<span class="deploy-text-frontend-error">${config.errorMsg}</span>\n`;
                         showToast(config.errorMsg, 'error');
                     } else {
@@ -519,12 +533,37 @@ document.addEventListener('DOMContentLoaded', function() {
 
                 console.error(`${config.name} streaming error:`, error);
                 elements.button.disabled = false;
+                if (cancelButton) {
+                    cancelButton.classList.add('hidden');
+                }
                 elements.content.innerHTML += `\n<span class="deploy-text-frontend-error">ERROR: Connection to ${config.name.toLowerCase()} stream failed</span>\n`;
                 showToast(`${config.name} connection failed`, 'error');
             });
         };
     }
 
+    // Cancel an operation streamed by createStreamingHandler
+    function cancelStreaming(config, projectId) {
+        const cancelButton = document.getElementById(`${config.btnId}-cancel`);
+        if (!cancelButton) return;
+
+        cancelButton.disabled = true;
+        cancelButton.dataset.cancelRequested = 'true';
+
+        fetch(config.cancelEndpoint(projectId), { method: 'POST' })
+        .then(response => {
+            if (!response.ok) {
+                throw new Error(`HTTP error! status: ${response.status}`);
+            }
+        })
+        .catch(error => {
+            console.error(`${config.name} cancel error:`, error);
+            cancelButton.disabled = false;
+            cancelButton.dataset.cancelRequested = 'false';
+            showToast(`Failed to cancel ${config.name.toLowerCase()}`, 'error');
+        });
+    }
+
     // Event delegation for deploy and stop buttons
     document.addEventListener('click', function(event) {
         if (event.target.id === 'deploy-btn' && event.target.dataset.projectId) {
@@ -533,6 +572,21 @@ document.addEventListener('DOMContentLoaded', function() {
         } else if (event.target.id === 'stop-btn' && event.target.dataset.projectId) {
             event.preventDefault();
             startStop(event.target.dataset.projectId);
+        } else if (event.target.id === 'rollback-btn' && event.target.dataset.projectId) {
+            event.preventDefault();
+            startRollback(event.target.dataset.projectId);
+        } else if (event.target.id === 'approve-btn' && event.target.dataset.projectId) {
+            event.preventDefault();
+            startApproval(event.target.dataset.projectId);
+        } else if (event.target.id === 'deploy-btn-cancel' && event.target.dataset.projectId) {
+            event.preventDefault();
+            cancelStreaming(deployConfig, event.target.dataset.projectId);
+        } else if (event.target.id === 'rollback-btn-cancel' && event.target.dataset.projectId) {
+            event.preventDefault();
+            cancelStreaming(rollbackConfig, event.target.dataset.projectId);
+        } else if (event.target.id === 'approve-btn-cancel' && event.target.dataset.projectId) {
+            event.preventDefault();
+            cancelStreaming(approveConfig, event.target.dataset.projectId);
         }
     });
 
@@ -543,10 +597,12 @@ document.addEventListener('DOMContentLoaded', function() {
         contentId: 'deploy-content',
         outputId: 'deploy-output',
         endpoint: (projectId) => `/projects/${projectId}/deploy/stream`,
+        cancelEndpoint: (projectId) => `/projects/${projectId}/deploy/cancel`,
         connectingMsg: 'Connecting to deployment stream...',
         startingMsg: 'Starting deployment...',
         successMsg: 'Deployment completed successfully',
         errorMsg: 'Deployment failed',
+        cancelledMsg: 'Deployment cancelled',
         updateStatus: true,
         useAbortController: false
     };
@@ -565,6 +621,44 @@ document.addEventListener('DOMContentLoaded', function() {
         useAbortController: false
     };
 
+    const rollbackConfig = {
+        name: 'Rollback',
+        btnId: 'rollback-btn',
+        contentId: 'rollback-content',
+        outputId: 'rollback-output',
+        endpoint: (projectId) => {
+            const deploymentId = document.getElementById('rollback-output').dataset.deploymentId;
+            return `/projects/${projectId}/deployments/${deploymentId}/rollback/stream`;
+        },
+        cancelEndpoint: (projectId) => `/projects/${projectId}/deploy/cancel`,
+        connectingMsg: 'Connecting to rollback stream...',
+        startingMsg: 'Starting rollback...',
+        successMsg: 'Rollback completed successfully',
+        errorMsg: 'Rollback failed',
+        cancelledMsg: 'Rollback cancelled',
+        updateStatus: true,
+        useAbortController: false
+    };
+
+    const approveConfig = {
+        name: 'Approved deployment',
+        btnId: 'approve-btn',
+        contentId: 'approve-content',
+        outputId: 'approve-output',
+        endpoint: (projectId) => {
+            const deploymentId = document.getElementById('approve-output').dataset.deploymentId;
+            return `/projects/${projectId}/deployments/${deploymentId}/approve/stream`;
+        },
+        cancelEndpoint: (projectId) => `/projects/${projectId}/deploy/cancel`,
+        connectingMsg: 'Connecting to deployment stream...',
+        startingMsg: 'Starting approved deployment...',
+        successMsg: 'Deployment completed successfully',
+        errorMsg: 'Deployment failed',
+        cancelledMsg: 'Deployment cancelled',
+        updateStatus: true,
+        useAbortController: false
+    };
+
     const logsConfig = {
         name: 'Logs',
         btnId: 'logs-btn', // Not used since logs don't have a button
@@ -585,6 +679,12 @@ document.addEventListener('DOMContentLoaded', function() {
     // Stop streaming functionality
     window.startStop = createStreamingHandler(stopConfig);
 
+    // Rollback streaming functionality
+    window.startRollback = createStreamingHandler(rollbackConfig);
+
+    // Approved deployment streaming functionality
+    window.startApproval = createStreamingHandler(approveConfig);
+
     // Global variable to store logs stream controller for cancellation
     let currentLogsController = null;
 
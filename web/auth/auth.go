@@ -0,0 +1,198 @@
+// Package auth provides session-based authentication for the web server.
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/oar-cd/oar/services"
+	"github.com/oar-cd/oar/web/handlers"
+	"github.com/oar-cd/oar/web/pages"
+)
+
+// SessionCookieName is the name of the cookie holding the session token
+const SessionCookieName = "oar_session"
+
+// RequireAuth is middleware that only lets requests with a valid session cookie or
+// HTTP basic credentials through. The authenticated user is stored in the request context,
+// so that services.UserFromContext and handlers.ProjectService act on their behalf.
+func RequireAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, err := authenticateRequest(r)
+		if err != nil {
+			if !errors.Is(err, services.ErrInvalidSession) && !errors.Is(err, services.ErrInvalidCredentials) {
+				handlers.LogOperationError("authenticate_request", "auth", err, "path", r.URL.Path)
+			}
+			rejectUnauthenticated(w, r)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(services.ContextWithUser(r.Context(), user)))
+	})
+}
+
+// RequireMetricsAuth is middleware for /metrics, which covers every project. It lets through requests with the
+// metrics bearer token, if one is configured, and those of users whose global role lets them view every project,
+// signed in or with HTTP basic credentials.
+func RequireMetricsAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if config := app.GetConfig(); config != nil && services.MetricsTokenValid(r, config.MetricsToken) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		user, err := authenticateRequest(r)
+		if err == nil {
+			err = app.GetRoleService().Authorize(user, nil, services.RoleViewer)
+		}
+		if err != nil {
+			if !errors.Is(err, services.ErrInvalidSession) && !errors.Is(err, services.ErrInvalidCredentials) &&
+				!errors.Is(err, services.ErrPermissionDenied) {
+				handlers.LogOperationError("authenticate_request", "auth", err, "path", r.URL.Path)
+			}
+			w.Header().Set("WWW-Authenticate", `Basic realm="Oar"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// LoginPage renders the sign-in form
+func LoginPage(w http.ResponseWriter, r *http.Request) {
+	renderLogin(w, r, http.StatusOK, "", safeRedirectTarget(r.URL.Query().Get("next")))
+}
+
+// Login checks the submitted credentials and starts a session
+func Login(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		handlers.LogOperationError("parse_form", "auth", err)
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	next := safeRedirectTarget(r.FormValue("next"))
+	authService := app.GetAuthService()
+
+	user, err := authService.Authenticate(r.FormValue("username"), r.FormValue("password"))
+	if err != nil {
+		if !errors.Is(err, services.ErrInvalidCredentials) {
+			handlers.LogOperationError("login", "auth", err)
+		}
+		renderLogin(w, r, http.StatusUnauthorized, "Invalid username or password", next)
+		return
+	}
+
+	token, session, err := authService.CreateSession(user.ID)
+	if err != nil {
+		handlers.LogOperationError("create_session", "auth", err, "username", user.Username)
+		renderLogin(w, r, http.StatusInternalServerError, "Failed to sign in, please try again", next)
+		return
+	}
+
+	setSessionCookie(w, token, session.ExpiresAt)
+	http.Redirect(w, r, next, http.StatusSeeOther)
+}
+
+// Logout ends the current session and returns to the sign-in page
+func Logout(w http.ResponseWriter, r *http.Request) {
+	if cookie, err := r.Cookie(SessionCookieName); err == nil {
+		if err := app.GetAuthService().DeleteSession(cookie.Value); err != nil {
+			handlers.LogOperationError("logout", "auth", err)
+		}
+	}
+
+	clearSessionCookie(w)
+	http.Redirect(w, r, "/login", http.StatusSeeOther)
+}
+
+// authenticateRequest resolves the user from the session cookie, falling back to basic auth
+func authenticateRequest(r *http.Request) (*services.User, error) {
+	authService := app.GetAuthService()
+
+	if cookie, err := r.Cookie(SessionCookieName); err == nil {
+		user, err := authService.ValidateSession(cookie.Value)
+		if err == nil || !errors.Is(err, services.ErrInvalidSession) {
+			return user, err
+		}
+	}
+
+	if username, password, ok := r.BasicAuth(); ok {
+		return authService.Authenticate(username, password)
+	}
+
+	return nil, services.ErrInvalidSession
+}
+
+// rejectUnauthenticated responds in the way the client expects: JSON for the API,
+// an HX-Redirect for htmx requests and a redirect to the sign-in page otherwise
+func rejectUnauthenticated(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case strings.HasPrefix(r.URL.Path, "/api/"):
+		w.Header().Set("WWW-Authenticate", `Basic realm="Oar"`)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		if _, err := w.Write([]byte(`{"error":"authentication required"}` + "\n")); err != nil {
+			handlers.LogOperationError("reject_unauthenticated", "auth", err)
+		}
+	case r.Header.Get("HX-Request") == "true":
+		w.Header().Set("HX-Redirect", "/login")
+		w.WriteHeader(http.StatusUnauthorized)
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		http.Redirect(w, r, "/login?next="+url.QueryEscape(r.URL.RequestURI()), http.StatusSeeOther)
+	default:
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	}
+}
+
+// safeRedirectTarget only allows redirects to local paths
+func safeRedirectTarget(next string) string {
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") || strings.HasPrefix(next, "/\\") {
+		return "/"
+	}
+	return next
+}
+
+func renderLogin(w http.ResponseWriter, r *http.Request, status int, errorMessage, next string) {
+	w.Header().Set("Content-Type", "text/html")
+	w.WriteHeader(status)
+	component := pages.Login(errorMessage, next, handlers.GetServerVersion())
+	if err := component.Render(r.Context(), w); err != nil {
+		handlers.LogOperationError("login_page", "auth", err)
+	}
+}
+
+func setSessionCookie(w http.ResponseWriter, token string, expiresAt time.Time) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     SessionCookieName,
+		Value:    token,
+		Path:     "/",
+		Expires:  expiresAt,
+		HttpOnly: true,
+		Secure:   secureCookies(),
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func clearSessionCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     SessionCookieName,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   secureCookies(),
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// secureCookies reports whether the session cookie should only be sent over HTTPS
+func secureCookies() bool {
+	config := app.GetConfig()
+	return config != nil && config.SessionCookieSecure
+}
@@ -0,0 +1,334 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/oar-cd/oar/services"
+	"github.com/oar-cd/oar/testing/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testUser = &services.User{ID: uuid.New(), Username: "alice"}
+
+// newTestUserManager accepts the session token "valid-token" and the password "correct-horse"
+func newTestUserManager() *mocks.MockUserManager {
+	return &mocks.MockUserManager{
+		ValidateSessionFunc: func(token string) (*services.User, error) {
+			if token == "valid-token" {
+				return testUser, nil
+			}
+			return nil, services.ErrInvalidSession
+		},
+		AuthenticateFunc: func(username, password string) (*services.User, error) {
+			if username == "alice" && password == "correct-horse" {
+				return testUser, nil
+			}
+			return nil, services.ErrInvalidCredentials
+		},
+		CreateSessionFunc: func(userID uuid.UUID) (string, *services.Session, error) {
+			return "new-token", &services.Session{UserID: userID, ExpiresAt: time.Now().Add(time.Hour)}, nil
+		},
+	}
+}
+
+func protectedHandler() http.Handler {
+	return RequireAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user := services.UserFromContext(r.Context())
+		if user == nil {
+			http.Error(w, "no user in context", http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(user.Username))
+	}))
+}
+
+func TestRequireAuth(t *testing.T) {
+	tests := []struct {
+		name             string
+		method           string
+		path             string
+		cookie           string
+		basicAuth        []string
+		headers          map[string]string
+		expectedStatus   int
+		expectedLocation string
+		expectedHeader   [2]string
+	}{
+		{
+			name:           "valid session cookie",
+			method:         http.MethodGet,
+			path:           "/projects/x/edit",
+			cookie:         "valid-token",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "valid basic auth",
+			method:         http.MethodGet,
+			path:           "/api/v1/projects",
+			basicAuth:      []string{"alice", "correct-horse"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid cookie falls back to basic auth",
+			method:         http.MethodGet,
+			path:           "/api/v1/projects",
+			cookie:         "stale-token",
+			basicAuth:      []string{"alice", "correct-horse"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:             "browser navigation redirects to login",
+			method:           http.MethodGet,
+			path:             "/?tab=all",
+			expectedStatus:   http.StatusSeeOther,
+			expectedLocation: "/login?next=" + url.QueryEscape("/?tab=all"),
+		},
+		{
+			name:           "htmx request gets HX-Redirect",
+			method:         http.MethodPost,
+			path:           "/projects/x/deploy/stream",
+			headers:        map[string]string{"HX-Request": "true"},
+			expectedStatus: http.StatusUnauthorized,
+			expectedHeader: [2]string{"HX-Redirect", "/login"},
+		},
+		{
+			name:           "api request gets JSON 401",
+			method:         http.MethodDelete,
+			path:           "/api/v1/projects/x",
+			basicAuth:      []string{"alice", "wrong"},
+			expectedStatus: http.StatusUnauthorized,
+			expectedHeader: [2]string{"WWW-Authenticate", `Basic realm="Oar"`},
+		},
+		{
+			name:           "non-GET request is rejected",
+			method:         http.MethodDelete,
+			path:           "/projects/x/",
+			cookie:         "stale-token",
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app.SetAuthServiceForTesting(newTestUserManager())
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: SessionCookieName, Value: tt.cookie})
+			}
+			if tt.basicAuth != nil {
+				req.SetBasicAuth(tt.basicAuth[0], tt.basicAuth[1])
+			}
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+
+			protectedHandler().ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, "alice", w.Body.String())
+			}
+			if tt.expectedLocation != "" {
+				assert.Equal(t, tt.expectedLocation, w.Header().Get("Location"))
+			}
+			if tt.expectedHeader[0] != "" {
+				assert.Equal(t, tt.expectedHeader[1], w.Header().Get(tt.expectedHeader[0]))
+			}
+		})
+	}
+}
+
+func TestRequireAuth_ServiceError(t *testing.T) {
+	app.SetAuthServiceForTesting(&mocks.MockUserManager{
+		ValidateSessionFunc: func(token string) (*services.User, error) {
+			return nil, errors.New("database is locked")
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/projects", nil)
+	req.AddCookie(&http.Cookie{Name: SessionCookieName, Value: "valid-token"})
+	w := httptest.NewRecorder()
+
+	protectedHandler().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestLogin(t *testing.T) {
+	tests := []struct {
+		name             string
+		username         string
+		password         string
+		next             string
+		expectedStatus   int
+		expectedLocation string
+	}{
+		{
+			name:             "valid credentials",
+			username:         "alice",
+			password:         "correct-horse",
+			next:             "/projects/x/edit",
+			expectedStatus:   http.StatusSeeOther,
+			expectedLocation: "/projects/x/edit",
+		},
+		{
+			name:             "external next is ignored",
+			username:         "alice",
+			password:         "correct-horse",
+			next:             "//evil.example.com/",
+			expectedStatus:   http.StatusSeeOther,
+			expectedLocation: "/",
+		},
+		{
+			name:           "invalid credentials",
+			username:       "alice",
+			password:       "wrong",
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app.SetAuthServiceForTesting(newTestUserManager())
+
+			form := url.Values{"username": {tt.username}, "password": {tt.password}, "next": {tt.next}}
+			req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			w := httptest.NewRecorder()
+
+			Login(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusSeeOther {
+				assert.Contains(t, w.Body.String(), "Invalid username or password")
+				assert.Empty(t, w.Result().Cookies())
+				return
+			}
+
+			assert.Equal(t, tt.expectedLocation, w.Header().Get("Location"))
+			cookies := w.Result().Cookies()
+			require.Len(t, cookies, 1)
+			assert.Equal(t, SessionCookieName, cookies[0].Name)
+			assert.Equal(t, "new-token", cookies[0].Value)
+			assert.True(t, cookies[0].HttpOnly)
+			assert.Equal(t, http.SameSiteLaxMode, cookies[0].SameSite)
+		})
+	}
+}
+
+func TestLoginPage(t *testing.T) {
+	w := httptest.NewRecorder()
+	LoginPage(w, httptest.NewRequest(http.MethodGet, "/login?next=/projects", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `action="/login"`)
+	assert.Contains(t, w.Body.String(), `value="/projects"`)
+}
+
+func TestLogout(t *testing.T) {
+	var deletedToken string
+	app.SetAuthServiceForTesting(&mocks.MockUserManager{
+		DeleteSessionFunc: func(token string) error {
+			deletedToken = token
+			return nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/logout", nil)
+	req.AddCookie(&http.Cookie{Name: SessionCookieName, Value: "valid-token"})
+	w := httptest.NewRecorder()
+
+	Logout(w, req)
+
+	assert.Equal(t, http.StatusSeeOther, w.Code)
+	assert.Equal(t, "/login", w.Header().Get("Location"))
+	assert.Equal(t, "valid-token", deletedToken)
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, -1, cookies[0].MaxAge)
+}
+
+func TestSafeRedirectTarget(t *testing.T) {
+	assert.Equal(t, "/projects", safeRedirectTarget("/projects"))
+	assert.Equal(t, "/", safeRedirectTarget(""))
+	assert.Equal(t, "/", safeRedirectTarget("https://evil.example.com"))
+	assert.Equal(t, "/", safeRedirectTarget("//evil.example.com"))
+	assert.Equal(t, "/", safeRedirectTarget(`/\evil.example.com`))
+}
+
+func TestRequireMetricsAuth(t *testing.T) {
+	viewer := &services.User{ID: uuid.New(), Username: "bob"}
+	tests := []struct {
+		name           string
+		token          string
+		authorization  string
+		basicAuth      []string
+		expectedStatus int
+	}{
+		{name: "bearer token", token: "scrape-token", authorization: "Bearer scrape-token",
+			expectedStatus: http.StatusOK},
+		{name: "wrong bearer token", token: "scrape-token", authorization: "Bearer other-token",
+			expectedStatus: http.StatusUnauthorized},
+		{name: "bearer token not configured", authorization: "Bearer ", expectedStatus: http.StatusUnauthorized},
+		{name: "global viewer", basicAuth: []string{"bob", "correct-horse"}, expectedStatus: http.StatusOK},
+		{name: "viewer of some projects", basicAuth: []string{"alice", "correct-horse"},
+			expectedStatus: http.StatusUnauthorized},
+		{name: "no credentials", token: "scrape-token", expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app.SetConfigForTesting(&services.Config{MetricsToken: tt.token})
+			t.Cleanup(func() { app.SetConfigForTesting(nil) })
+			app.SetAuthServiceForTesting(&mocks.MockUserManager{
+				AuthenticateFunc: func(username, password string) (*services.User, error) {
+					switch {
+					case password != "correct-horse":
+						return nil, services.ErrInvalidCredentials
+					case username == viewer.Username:
+						return viewer, nil
+					default:
+						return testUser, nil
+					}
+				},
+			})
+			app.SetRoleServiceForTesting(&mocks.MockRoleManager{
+				AuthorizeFunc: func(user *services.User, projectID *uuid.UUID, required services.Role) error {
+					if user.ID != viewer.ID || projectID != nil {
+						return services.ErrPermissionDenied
+					}
+					return nil
+				},
+			})
+
+			handler := RequireMetricsAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte("metrics"))
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			if tt.basicAuth != nil {
+				req.SetBasicAuth(tt.basicAuth[0], tt.basicAuth[1])
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusUnauthorized {
+				assert.Equal(t, `Basic realm="Oar"`, w.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
@@ -8,7 +8,10 @@ package modals
 import "github.com/a-h/templ"
 import templruntime "github.com/a-h/templ/runtime"
 
-import "github.com/oar-cd/oar/web/components/project"
+import (
+	"fmt"
+	"github.com/oar-cd/oar/web/components/project"
+)
 
 // DeployProjectModal renders the project deployment modal
 func DeployProjectModal(proj project.ProjectView) templ.Component {
@@ -32,7 +35,7 @@ func DeployProjectModal(proj project.ProjectView) templ.Component {
 			templ_7745c5c3_Var1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = LargeModal("Deploy "+proj.Name, deployProjectBody(proj), StreamingActionFooter("Deploy", "deploy-btn", proj.ID.String())).Render(ctx, templ_7745c5c3_Buffer)
+		templ_7745c5c3_Err = LargeModal("Deploy "+proj.Name, deployProjectBody(proj), DeploymentActionFooter("Deploy", "deploy-btn", proj.ID.String())).Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -69,13 +72,26 @@ func deployProjectBody(proj project.ProjectView) templ.Component {
 		var templ_7745c5c3_Var3 string
 		templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs(proj.Name)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/modals/deploy-project.templ`, Line: 14, Col: 30}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/modals/deploy-project.templ`, Line: 17, Col: 30}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var3))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "\" using Docker Compose. The output will be shown in real-time below.</p></div><div class=\"deploy-output-container\"><div id=\"deploy-output\" class=\"deploy-code-block\"><pre id=\"deploy-content\" class=\"streaming-output\"><span class=\"deploy-text-info\">Ready to deploy...</span></pre></div></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "\" using Docker Compose. The output will be shown in real-time below.</p><div id=\"deploy-preview\" hx-get=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var4 string
+		templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("/projects/%s/deploy/preview", proj.ID.String()))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/modals/deploy-project.templ`, Line: 21, Col: 72}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var4))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "\" hx-trigger=\"load\" hx-swap=\"innerHTML\"><p class=\"deploy-preview-loading\">Fetching changes to preview...</p></div></div><div class=\"deploy-output-container\"><div id=\"deploy-output\" class=\"deploy-code-block\"><pre id=\"deploy-content\" class=\"streaming-output\"><span class=\"deploy-text-info\">Ready to deploy...</span></pre></div></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -10,6 +10,7 @@ import templruntime "github.com/a-h/templ/runtime"
 
 import "github.com/oar-cd/oar/web/components/forms"
 import "github.com/oar-cd/oar/web/components/project"
+import "time"
 
 // EditProjectModal renders the edit project modal
 func EditProjectModal(proj project.ProjectView) templ.Component {
@@ -64,18 +65,31 @@ func editProjectBody(proj project.ProjectView) templ.Component {
 		}
 		ctx = templ.ClearChildren(ctx)
 		templ_7745c5c3_Err = forms.ProjectForm(forms.ProjectFormData{
-			IsEdit:         true,
-			ProjectID:      proj.ID.String(),
-			Name:           proj.Name,
-			GitURL:         proj.GitURL,
-			GitBranch:      proj.GitBranch,
-			AuthMethod:     getAuthMethodFromProject(proj),
-			Username:       getUsernameFromProject(proj),
-			Password:       getPasswordFromProject(proj),
-			PrivateKey:     getPrivateKeyFromProject(proj),
-			ComposeFiles:   joinStringSlice(proj.ComposeFiles, "\n"),
-			Variables:      joinStringSlice(proj.Variables, "\n"),
-			WatcherEnabled: proj.WatcherEnabled,
+			IsEdit:          true,
+			ProjectID:       proj.ID.String(),
+			Name:            proj.Name,
+			GitURL:          proj.GitURL,
+			GitBranch:       proj.GitBranch,
+			GitRefType:      proj.GitRefType,
+			GitRef:          proj.GitRef,
+			AuthMethod:      getAuthMethodFromProject(proj),
+			Username:        getUsernameFromProject(proj),
+			Password:        getPasswordFromProject(proj),
+			PrivateKey:      getPrivateKeyFromProject(proj),
+			ComposeFiles:    joinStringSlice(proj.ComposeFiles, "\n"),
+			Variables:       joinStringSlice(proj.Variables, "\n"),
+			WatcherEnabled:  proj.WatcherEnabled,
+			RequireApproval: proj.RequireApproval,
+			WatchImages:     proj.WatchImages,
+			AutoRollback:    proj.AutoRollback,
+			BackupVolumes:   proj.BackupVolumes,
+			HealthChecks:    joinStringSlice(proj.HealthChecks, "\n"),
+			PreDeployHooks:  joinStringSlice(proj.PreDeployHooks, "\n"),
+			PostDeployHooks: joinStringSlice(proj.PostDeployHooks, "\n"),
+			PullTimeout:     formatTimeout(proj.PullTimeout),
+			UpTimeout:       formatTimeout(proj.UpTimeout),
+			WaitTimeout:     formatTimeout(proj.WaitTimeout),
+			HealthTimeout:   formatTimeout(proj.HealthTimeout),
 		}).Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
@@ -155,6 +169,14 @@ func getPrivateKeyFromProject(proj project.ProjectView) string {
 	return proj.GitAuth.SSHAuth.PrivateKey
 }
 
+// formatTimeout formats a timeout for the project form, leaving the default empty
+func formatTimeout(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return d.String()
+}
+
 func joinStringSlice(slice []string, separator string) string {
 	if len(slice) == 0 {
 		return ""
@@ -109,18 +109,32 @@ func EmptyState() templ.Component {
 
 // ProjectView represents the frontend view data for a project (simplified from backend Project)
 type ProjectView struct {
-	ID             uuid.UUID
-	Name           string
-	GitURL         string
-	GitBranch      string
-	GitAuth        *GitAuthConfig // Git authentication configuration
-	Status         string         // "running", "stopped", "error" (string representation)
-	LastCommit     *string        // Git commit SHA (first 8 chars)
-	ComposeFiles   []string
-	Variables      []string
-	WatcherEnabled bool
-	CreatedAt      time.Time
-	UpdatedAt      time.Time
+	ID              uuid.UUID
+	Name            string
+	GitURL          string
+	GitBranch       string
+	GitRefType      string         // "branch", "tag", "commit"
+	GitRef          string         // Tag pattern or semver constraint, or commit SHA
+	GitAuth         *GitAuthConfig // Git authentication configuration
+	Status          string         // "running", "stopped", "error" (string representation)
+	LastCommit      *string        // Git commit SHA (first 8 chars)
+	ComposeFiles    []string
+	Variables       []string
+	WatcherEnabled  bool
+	RequireApproval bool
+	WatchImages     bool
+	AutoRollback    bool
+	BackupVolumes   bool
+	HealthChecks    []string      // One per entry, as written in the form
+	PreDeployHooks  []string      // One per entry, as written in the form
+	PostDeployHooks []string      // One per entry, as written in the form
+	Drift           []string      // Differences between the deployed commit and the containers, empty when in sync
+	PullTimeout     time.Duration // 0 uses the default
+	UpTimeout       time.Duration // 0 uses the default
+	WaitTimeout     time.Duration // 0 uses the default
+	HealthTimeout   time.Duration // 0 uses the default
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
 }
 
 // GitAuthConfig holds Git authentication configuration for a project
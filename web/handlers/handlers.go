@@ -2,6 +2,7 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -27,6 +28,21 @@ func GetServerVersion() string {
 
 // Helper functions for common operations
 
+// ProjectService returns the project service acting on behalf of the user signed in to the request in ctx
+func ProjectService(ctx context.Context) services.ProjectManager {
+	return app.GetProjectServiceFor(services.UserFromContext(ctx))
+}
+
+// NotificationService returns the notification service acting on behalf of the user signed in to the request in ctx
+func NotificationService(ctx context.Context) services.NotificationManager {
+	return app.GetNotificationServiceFor(services.UserFromContext(ctx))
+}
+
+// CatalogService returns the catalog service acting on behalf of the user signed in to the request in ctx
+func CatalogService(ctx context.Context) services.CatalogManager {
+	return app.GetCatalogServiceFor(services.UserFromContext(ctx))
+}
+
 // ParseProjectID extracts and validates project ID from URL parameters
 func ParseProjectID(r *http.Request) (uuid.UUID, error) {
 	projectID := chi.URLParam(r, "id")
@@ -77,18 +93,32 @@ func BuildGitAuthConfig(r *http.Request) *services.GitAuthConfig {
 // ConvertProjectToView converts a backend Project to frontend ProjectView
 func ConvertProjectToView(p *services.Project) project.ProjectView {
 	return project.ProjectView{
-		ID:             p.ID,
-		Name:           p.Name,
-		GitURL:         p.GitURL,
-		GitBranch:      p.GitBranch,
-		GitAuth:        ConvertGitAuthConfig(p.GitAuth),
-		Status:         p.Status.String(),
-		LastCommit:     p.LastCommit,
-		ComposeFiles:   p.ComposeFiles,
-		Variables:      p.Variables,
-		WatcherEnabled: p.WatcherEnabled,
-		CreatedAt:      p.CreatedAt,
-		UpdatedAt:      p.UpdatedAt,
+		ID:              p.ID,
+		Name:            p.Name,
+		GitURL:          p.GitURL,
+		GitBranch:       p.GitBranch,
+		GitRefType:      p.GitRefType.String(),
+		GitRef:          p.GitRef,
+		GitAuth:         ConvertGitAuthConfig(p.GitAuth),
+		Status:          p.Status.String(),
+		LastCommit:      p.LastCommit,
+		ComposeFiles:    p.ComposeFiles,
+		Variables:       p.Variables,
+		WatcherEnabled:  p.WatcherEnabled,
+		RequireApproval: p.RequireApproval,
+		WatchImages:     p.WatchImages,
+		AutoRollback:    p.AutoRollback,
+		BackupVolumes:   p.BackupVolumes,
+		HealthChecks:    services.FormatHealthChecks(p.HealthChecks),
+		PreDeployHooks:  services.FormatHooks(p.PreDeployHooks),
+		PostDeployHooks: services.FormatHooks(p.PostDeployHooks),
+		Drift:           p.Drift,
+		PullTimeout:     p.Timeouts.Pull,
+		UpTimeout:       p.Timeouts.Up,
+		WaitTimeout:     p.Timeouts.Wait,
+		HealthTimeout:   p.Timeouts.Health,
+		CreatedAt:       p.CreatedAt,
+		UpdatedAt:       p.UpdatedAt,
 	}
 }
 
@@ -128,7 +158,7 @@ func ConvertGitAuthConfig(auth *services.GitAuthConfig) *project.GitAuthConfig {
 
 // renderProjectGrid fetches projects and renders the project grid
 func renderProjectGrid(w http.ResponseWriter, r *http.Request, trigger string) error {
-	projectService := app.GetProjectService()
+	projectService := ProjectService(r.Context())
 	projects, err := projectService.List()
 	if err != nil {
 		slog.Error("Failed to fetch projects for grid render",
@@ -238,11 +268,18 @@ func WithFormParsing(next http.HandlerFunc) http.HandlerFunc {
 // Generic handler patterns
 
 // HandleModal creates a generic handler for modal endpoints
-func HandleModal(modalFunc func(uuid.UUID) (templ.Component, error), operation string) http.HandlerFunc {
+func HandleModal(
+	modalFunc func(context.Context, uuid.UUID) (templ.Component, error),
+	operation string,
+) http.HandlerFunc {
 	return withProjectID(func(w http.ResponseWriter, r *http.Request, projectID uuid.UUID) {
-		component, err := modalFunc(projectID)
+		component, err := modalFunc(r.Context(), projectID)
 		if err != nil {
 			LogOperationError(operation, "handlers", err, "project_id", projectID)
+			if errors.Is(err, services.ErrPermissionDenied) {
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
 			http.Error(w, "Project not found", http.StatusNotFound)
 			return
 		}
@@ -253,7 +290,10 @@ func HandleModal(modalFunc func(uuid.UUID) (templ.Component, error), operation s
 }
 
 // HandleStream creates a generic handler for streaming endpoints
-func HandleStream(streamFunc func(uuid.UUID, chan<- string) error, streamType string) http.HandlerFunc {
+func HandleStream(
+	streamFunc func(context.Context, uuid.UUID, chan<- string) error,
+	streamType string,
+) http.HandlerFunc {
 	return withProjectID(func(w http.ResponseWriter, r *http.Request, projectID uuid.UUID) {
 		SetupSSE(w)
 
@@ -262,7 +302,7 @@ func HandleStream(streamFunc func(uuid.UUID, chan<- string) error, streamType st
 		// Start streaming in a goroutine
 		go func() {
 			defer close(outputChan) // Close channel when streaming function completes
-			if err := streamFunc(projectID, outputChan); err != nil {
+			if err := streamFunc(r.Context(), projectID, outputChan); err != nil {
 				LogOperationError(fmt.Sprintf("%s_stream", streamType), "handlers", err, "project_id", projectID)
 				// Send error as JSON message to match deployment format
 				errorMsg := map[string]string{
@@ -285,6 +325,34 @@ func HandleStream(streamFunc func(uuid.UUID, chan<- string) error, streamType st
 		if err := StreamOutput(w, outputChan, streamType); err != nil {
 			LogOperationError(fmt.Sprintf("%s_stream_output", streamType), "handlers", err, "project_id", projectID)
 		}
+
+		// Deployments outlive the stream when the client goes away, keep reading so that they don't block on
+		// sending output nobody reads
+		go func() {
+			for range outputChan {
+			}
+		}()
+	})
+}
+
+// HandleCancel creates a handler that cancels an operation of a project in progress. It responds with
+// 204 No Content, or 409 Conflict if the project has no such operation in progress.
+func HandleCancel(cancelFunc func(context.Context, uuid.UUID) error, operation string) http.HandlerFunc {
+	return withProjectID(func(w http.ResponseWriter, r *http.Request, projectID uuid.UUID) {
+		if err := cancelFunc(r.Context(), projectID); err != nil {
+			LogOperationError(operation, "handlers", err, "project_id", projectID)
+			switch {
+			case errors.Is(err, services.ErrPermissionDenied):
+				http.Error(w, err.Error(), http.StatusForbidden)
+			case errors.Is(err, services.ErrNoDeploymentInProgress):
+				http.Error(w, err.Error(), http.StatusConflict)
+			default:
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
 	})
 }
 
@@ -293,6 +361,14 @@ func HandleProjectAction(actionFunc func(*http.Request) error, successTrigger, o
 	return WithFormParsing(func(w http.ResponseWriter, r *http.Request) {
 		if err := actionFunc(r); err != nil {
 			LogOperationError(operation, "handlers", err)
+			if errors.Is(err, services.ErrPermissionDenied) {
+				http.Error(
+					w,
+					fmt.Sprintf("Not allowed to %s project: %v", strings.ReplaceAll(operation, "_", " "), err),
+					http.StatusForbidden,
+				)
+				return
+			}
 			http.Error(
 				w,
 				fmt.Sprintf("Failed to %s project: %v", strings.ReplaceAll(operation, "_", " "), err),
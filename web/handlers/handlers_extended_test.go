@@ -2,14 +2,21 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/a-h/templ"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/oar-cd/oar/services"
+	"github.com/oar-cd/oar/testing/mocks"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -273,3 +280,136 @@ func TestWithFormParsing(t *testing.T) {
 		})
 	}
 }
+
+func TestProjectService(t *testing.T) {
+	inner := &mocks.MockProjectManager{}
+	app.SetProjectServiceForTesting(inner)
+	app.SetRoleServiceForTesting(&mocks.MockRoleManager{})
+
+	assert.Same(t, inner, ProjectService(context.Background()))
+
+	ctx := services.ContextWithUser(context.Background(), &services.User{ID: uuid.New(), Username: "alice"})
+	assert.IsType(t, &services.AuthorizedProjectService{}, ProjectService(ctx))
+}
+
+func TestHandleModal(t *testing.T) {
+	tests := []struct {
+		name           string
+		modalErr       error
+		expectedStatus int
+	}{
+		{name: "success", expectedStatus: http.StatusOK},
+		{name: "not found", modalErr: errors.New("record not found"), expectedStatus: http.StatusNotFound},
+		{
+			name:           "permission denied",
+			modalErr:       fmt.Errorf("%w: project viewer role required", services.ErrPermissionDenied),
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := HandleModal(func(ctx context.Context, projectID uuid.UUID) (templ.Component, error) {
+				if tt.modalErr != nil {
+					return nil, tt.modalErr
+				}
+				return templ.Raw("modal"), nil
+			}, "test_modal")
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", uuid.New().String())
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+			w := httptest.NewRecorder()
+
+			handler(w, r)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestHandleProjectAction_PermissionDenied(t *testing.T) {
+	handler := HandleProjectAction(func(r *http.Request) error {
+		return fmt.Errorf("%w: project admin role required", services.ErrPermissionDenied)
+	}, "projectDeleted", "delete_project")
+
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodDelete, "/", nil))
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "Not allowed to delete project")
+}
+
+func TestHandleCancel(t *testing.T) {
+	tests := []struct {
+		name           string
+		cancelErr      error
+		expectedStatus int
+	}{
+		{name: "cancelled", expectedStatus: http.StatusNoContent},
+		{name: "not in progress", cancelErr: services.ErrNoDeploymentInProgress, expectedStatus: http.StatusConflict},
+		{
+			name:           "permission denied",
+			cancelErr:      fmt.Errorf("%w: project deployer role required", services.ErrPermissionDenied),
+			expectedStatus: http.StatusForbidden,
+		},
+		{name: "error", cancelErr: errors.New("boom"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectID := uuid.New()
+			var cancelled uuid.UUID
+			handler := HandleCancel(func(ctx context.Context, id uuid.UUID) error {
+				cancelled = id
+				return tt.cancelErr
+			}, "cancel_deployment")
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", projectID.String())
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+			w := httptest.NewRecorder()
+
+			handler(w, r)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, projectID, cancelled)
+		})
+	}
+}
+
+// brokenStreamWriter is a ResponseWriter of a client that went away
+type brokenStreamWriter struct {
+	*httptest.ResponseRecorder
+}
+
+func (w brokenStreamWriter) Write([]byte) (int, error) {
+	return 0, errors.New("broken pipe")
+}
+
+func TestHandleStream_ClientGoesAway(t *testing.T) {
+	finished := make(chan struct{})
+	handler := HandleStream(func(ctx context.Context, projectID uuid.UUID, outputChan chan<- string) error {
+		defer close(finished)
+		// More output than the channel buffers
+		for i := 0; i < 500; i++ {
+			outputChan <- `{"type":"docker","message":"output"}`
+		}
+		return nil
+	}, "deployment")
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", uuid.New().String())
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+	handler(brokenStreamWriter{httptest.NewRecorder()}, r)
+
+	select {
+	case <-finished:
+	case <-time.After(5 * time.Second):
+		t.Fatal("streaming function blocked on sending output after the client went away")
+	}
+}
@@ -213,12 +213,17 @@ func TestConvertProjectToView(t *testing.T) {
 				Password: "github_pat_123",
 			},
 		},
-		Status:       services.ProjectStatusRunning,
-		LastCommit:   &lastCommit,
-		ComposeFiles: []string{"docker-compose.yml", "docker-compose.prod.yml"},
-		Variables:    []string{"ENV=production", "PORT=8080"},
-		CreatedAt:    time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
-		UpdatedAt:    time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC),
+		Status:         services.ProjectStatusRunning,
+		LastCommit:     &lastCommit,
+		ComposeFiles:   []string{"docker-compose.yml", "docker-compose.prod.yml"},
+		Variables:      []string{"ENV=production", "PORT=8080"},
+		AutoRollback:   true,
+		HealthChecks:   []services.HealthCheck{{Type: services.HealthCheckTypeTCP, Address: "localhost:8080"}},
+		PreDeployHooks: []services.Hook{{Type: services.HookTypeRun, Service: "app", Command: "migrate"}},
+		Drift:          []string{"db: no container"},
+		Timeouts:       services.ComposeTimeouts{Pull: 30 * time.Minute, Wait: 90 * time.Second, Health: time.Minute},
+		CreatedAt:      time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		UpdatedAt:      time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC),
 	}
 
 	view := ConvertProjectToView(project)
@@ -230,6 +235,15 @@ func TestConvertProjectToView(t *testing.T) {
 	assert.Equal(t, &lastCommit, view.LastCommit)
 	assert.Equal(t, []string{"docker-compose.yml", "docker-compose.prod.yml"}, view.ComposeFiles)
 	assert.Equal(t, []string{"ENV=production", "PORT=8080"}, view.Variables)
+	assert.True(t, view.AutoRollback)
+	assert.Equal(t, 30*time.Minute, view.PullTimeout)
+	assert.Zero(t, view.UpTimeout)
+	assert.Equal(t, 90*time.Second, view.WaitTimeout)
+	assert.Equal(t, time.Minute, view.HealthTimeout)
+	assert.Equal(t, []string{"tcp localhost:8080"}, view.HealthChecks)
+	assert.Equal(t, []string{"run app migrate"}, view.PreDeployHooks)
+	assert.Empty(t, view.PostDeployHooks)
+	assert.Equal(t, []string{"db: no container"}, view.Drift)
 	assert.Equal(t, time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), view.CreatedAt)
 	assert.Equal(t, time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC), view.UpdatedAt)
 
@@ -10,6 +10,7 @@ import (
 	"github.com/oar-cd/oar/internal/app"
 	"github.com/oar-cd/oar/logging"
 	"github.com/oar-cd/oar/services"
+	"github.com/oar-cd/oar/web/auth"
 	"github.com/oar-cd/oar/web/routes"
 )
 
@@ -28,16 +29,27 @@ func main() {
 		log.Fatalf("Failed to initialize application: %v", err)
 	}
 
+	if err := app.GetAuthService().DeleteExpiredSessions(); err != nil {
+		log.Printf("Failed to delete expired sessions: %v", err)
+	}
+
 	r := chi.NewRouter()
 	r.Use(middleware.Logger)
 
 	// Serve static files
 	r.Handle("/assets/*", http.StripPrefix("/assets/", http.FileServer(http.Dir("./web/assets/"))))
 
-	// Register all routes
-	routes.RegisterHomeRoutes(r)
-	routes.RegisterProjectRoutes(r)
-	routes.RegisterUtilityRoutes(r)
+	// Sign-in and health check are available without a session
+	routes.RegisterPublicRoutes(r)
+
+	// Everything else requires a signed-in user
+	r.Group(func(r chi.Router) {
+		r.Use(auth.RequireAuth)
+		routes.RegisterHomeRoutes(r)
+		routes.RegisterProjectRoutes(r)
+		routes.RegisterUtilityRoutes(r)
+		routes.RegisterAPIRoutes(r)
+	})
 
 	// Start server
 	address := fmt.Sprintf("%s:%d", config.HTTPHost, config.HTTPPort)
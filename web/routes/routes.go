@@ -2,6 +2,8 @@
 package routes
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -12,18 +14,42 @@ import (
 	"github.com/oar-cd/oar/internal/app"
 	"github.com/oar-cd/oar/services"
 	"github.com/oar-cd/oar/web/actions"
+	"github.com/oar-cd/oar/web/api"
+	"github.com/oar-cd/oar/web/auth"
 	"github.com/oar-cd/oar/web/components/modals"
 	"github.com/oar-cd/oar/web/components/project"
 	"github.com/oar-cd/oar/web/handlers"
 	"github.com/oar-cd/oar/web/pages"
+	"github.com/oar-cd/oar/web/webhooks"
 )
 
 // Route registration functions
 
+// RegisterPublicRoutes registers routes that are available without signing in
+func RegisterPublicRoutes(r chi.Router) {
+	r.Get("/login", auth.LoginPage)
+	r.Post("/login", auth.Login)
+	r.Post("/logout", auth.Logout)
+
+	// Git provider webhooks authenticate with a signature instead of a session
+	r.Post("/webhooks/{provider}", webhooks.Receive)
+
+	// Health check
+	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write([]byte("OK")); err != nil {
+			handlers.LogOperationError("health_check", "main", err)
+		}
+	})
+
+	// Prometheus metrics authenticate with a bearer token, or as a user who may view every project
+	r.With(auth.RequireMetricsAuth).Handle("/metrics", services.NewMetricsHandler(app.GetProjectService()))
+}
+
 // RegisterHomeRoutes registers the home page route
 func RegisterHomeRoutes(r chi.Router) {
 	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
-		projectService := app.GetProjectService()
+		projectService := handlers.ProjectService(r.Context())
 		projects, err := projectService.List()
 		if err != nil {
 			handlers.LogOperationError("list_projects", "main", err)
@@ -73,13 +99,35 @@ func RegisterProjectRoutes(r chi.Router) {
 			// Project actions
 			r.Get("/config", handlers.HandleModal(getConfigProjectModal, "config_project_modal"))
 			r.Get("/deploy", handlers.HandleModal(getDeployProjectModal, "deploy_project_modal"))
+			r.Get("/deploy/preview", handlers.HandleModal(getDeployPreview, "deploy_preview"))
 			r.Get("/stop", handlers.HandleModal(getStopProjectModal, "stop_project_modal"))
 			r.Get("/logs", handlers.HandleModal(getLogsProjectModal, "logs_project_modal"))
 			r.Get("/deployments", handlers.HandleModal(getDeploymentsProjectModal, "deployments_project_modal"))
+			r.Get(
+				"/deployments/{deploymentID}/rollback",
+				handlers.HandleModal(getRollbackProjectModal, "rollback_project_modal"),
+			)
+			r.Get(
+				"/deployments/{deploymentID}/approve",
+				handlers.HandleModal(getApproveProjectModal, "approve_project_modal"),
+			)
+			r.Post(
+				"/deployments/{deploymentID}/reject",
+				handlers.HandleModal(rejectDeployment, "reject_deployment"),
+			)
 
 			// Streaming endpoints
 			r.Post("/deploy/stream", handlers.HandleStream(actions.DeployProject, "deployment"))
+			r.Post("/deploy/cancel", handlers.HandleCancel(actions.CancelDeployment, "cancel_deployment"))
 			r.Post("/stop/stream", handlers.HandleStream(actions.StopProject, "stop"))
+			r.Post(
+				"/deployments/{deploymentID}/rollback/stream",
+				handlers.HandleStream(actions.RollbackProject, "rollback"),
+			)
+			r.Post(
+				"/deployments/{deploymentID}/approve/stream",
+				handlers.HandleStream(actions.ApproveProject, "approval"),
+			)
 			r.Post("/logs/stream", handlers.HandleStream(actions.GetProjectLogs, "logs"))
 
 			// Status pill updates
@@ -88,7 +136,54 @@ func RegisterProjectRoutes(r chi.Router) {
 	})
 }
 
-// RegisterUtilityRoutes registers utility routes like auth testing and discovery
+// RegisterAPIRoutes registers the versioned JSON REST API
+func RegisterAPIRoutes(r chi.Router) {
+	r.Route("/api/v1", func(r chi.Router) {
+		r.Get("/openapi.yaml", api.OpenAPISpec)
+
+		r.Route("/projects", func(r chi.Router) {
+			r.Get("/", api.ListProjects)
+			r.Post("/", api.CreateProject)
+
+			r.Route("/{id}", func(r chi.Router) {
+				r.Get("/", api.GetProject)
+				r.Patch("/", api.UpdateProject)
+				r.Delete("/", api.DeleteProject)
+
+				r.Get("/deployments", api.ListDeployments)
+				r.Post("/deployments/{deploymentID}/approve", api.ApproveDeployment)
+				r.Post("/deployments/{deploymentID}/reject", api.RejectDeployment)
+				r.Get("/status", api.GetStatus)
+				r.Get("/config", api.GetConfig)
+
+				r.Get("/deploy/preview", api.PreviewDeploy)
+				r.Post("/deploy", api.DeployProject)
+				r.Post("/rollback", api.RollbackProject)
+				r.Post("/cancel", api.CancelDeployment)
+				r.Post("/stop", api.StopProject)
+
+				r.Get("/notifiers", api.ListNotifiers)
+				r.Post("/notifiers", api.CreateNotifier)
+				r.Delete("/notifiers/{notifierID}", api.DeleteNotifier)
+				r.Post("/notifiers/{notifierID}/test", api.TestNotifier)
+			})
+		})
+
+		r.Route("/catalogs", func(r chi.Router) {
+			r.Get("/", api.ListCatalogs)
+			r.Post("/", api.CreateCatalog)
+
+			r.Route("/{catalogID}", func(r chi.Router) {
+				r.Get("/", api.GetCatalog)
+				r.Patch("/", api.UpdateCatalog)
+				r.Delete("/", api.DeleteCatalog)
+				r.Post("/sync", api.SyncCatalog)
+			})
+		})
+	})
+}
+
+// RegisterUtilityRoutes registers utility routes like git auth testing and discovery
 func RegisterUtilityRoutes(r chi.Router) {
 	// Test git authentication
 	r.Post("/test-git-auth", handlers.WithFormParsing(func(w http.ResponseWriter, r *http.Request) {
@@ -181,20 +276,12 @@ func RegisterUtilityRoutes(r chi.Router) {
 			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 		}
 	}))
-
-	// Health check
-	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		if _, err := w.Write([]byte("OK")); err != nil {
-			handlers.LogOperationError("health_check", "main", err)
-		}
-	})
 }
 
 // Modal helper functions
 
-func getEditProjectModal(projectID uuid.UUID) (templ.Component, error) {
-	projectService := app.GetProjectService()
+func getEditProjectModal(ctx context.Context, projectID uuid.UUID) (templ.Component, error) {
+	projectService := handlers.ProjectService(ctx)
 	targetProject, err := projectService.Get(projectID)
 	if err != nil {
 		return nil, err
@@ -204,8 +291,8 @@ func getEditProjectModal(projectID uuid.UUID) (templ.Component, error) {
 	return modals.EditProjectModal(projectView), nil
 }
 
-func getDeleteProjectModal(projectID uuid.UUID) (templ.Component, error) {
-	projectService := app.GetProjectService()
+func getDeleteProjectModal(ctx context.Context, projectID uuid.UUID) (templ.Component, error) {
+	projectService := handlers.ProjectService(ctx)
 	targetProject, err := projectService.Get(projectID)
 	if err != nil {
 		return nil, err
@@ -216,8 +303,8 @@ func getDeleteProjectModal(projectID uuid.UUID) (templ.Component, error) {
 	return modals.DeleteProjectModal(projectView, deletedDirPath), nil
 }
 
-func getConfigProjectModal(projectID uuid.UUID) (templ.Component, error) {
-	projectService := app.GetProjectService()
+func getConfigProjectModal(ctx context.Context, projectID uuid.UUID) (templ.Component, error) {
+	projectService := handlers.ProjectService(ctx)
 	targetProject, err := projectService.Get(projectID)
 	if err != nil {
 		return nil, err
@@ -235,8 +322,8 @@ func getConfigProjectModal(projectID uuid.UUID) (templ.Component, error) {
 	return modals.ConfigProjectModal(projectView, config), nil
 }
 
-func getDeployProjectModal(projectID uuid.UUID) (templ.Component, error) {
-	projectService := app.GetProjectService()
+func getDeployProjectModal(ctx context.Context, projectID uuid.UUID) (templ.Component, error) {
+	projectService := handlers.ProjectService(ctx)
 	targetProject, err := projectService.Get(projectID)
 	if err != nil {
 		return nil, err
@@ -246,8 +333,22 @@ func getDeployProjectModal(projectID uuid.UUID) (templ.Component, error) {
 	return modals.DeployProjectModal(projectView), nil
 }
 
-func getStopProjectModal(projectID uuid.UUID) (templ.Component, error) {
-	projectService := app.GetProjectService()
+// getDeployPreview renders what deploying a project from the deploy modal would change. Failing to preview it is
+// shown in the modal, since the deployment may still work.
+func getDeployPreview(ctx context.Context, projectID uuid.UUID) (templ.Component, error) {
+	options := services.DeployOptions{Pull: true, Trigger: services.DeploymentTriggerWeb}
+	preview, err := handlers.ProjectService(ctx).PreviewDeploy(ctx, projectID, options)
+	if errors.Is(err, services.ErrPermissionDenied) {
+		return nil, err
+	}
+	if err != nil {
+		return modals.DeployPreviewError(err), nil
+	}
+	return modals.DeployPreview(preview), nil
+}
+
+func getStopProjectModal(ctx context.Context, projectID uuid.UUID) (templ.Component, error) {
+	projectService := handlers.ProjectService(ctx)
 	targetProject, err := projectService.Get(projectID)
 	if err != nil {
 		return nil, err
@@ -257,8 +358,8 @@ func getStopProjectModal(projectID uuid.UUID) (templ.Component, error) {
 	return modals.StopProjectModal(projectView), nil
 }
 
-func getLogsProjectModal(projectID uuid.UUID) (templ.Component, error) {
-	projectService := app.GetProjectService()
+func getLogsProjectModal(ctx context.Context, projectID uuid.UUID) (templ.Component, error) {
+	projectService := handlers.ProjectService(ctx)
 	targetProject, err := projectService.Get(projectID)
 	if err != nil {
 		return nil, err
@@ -268,8 +369,8 @@ func getLogsProjectModal(projectID uuid.UUID) (templ.Component, error) {
 	return modals.LogsProjectModal(projectView), nil
 }
 
-func getProjectStatusPill(projectID uuid.UUID) (templ.Component, error) {
-	projectService := app.GetProjectService()
+func getProjectStatusPill(ctx context.Context, projectID uuid.UUID) (templ.Component, error) {
+	projectService := handlers.ProjectService(ctx)
 	targetProject, err := projectService.Get(projectID)
 	if err != nil {
 		return nil, err
@@ -279,8 +380,8 @@ func getProjectStatusPill(projectID uuid.UUID) (templ.Component, error) {
 	return project.StatusPill(projectView.ID.String(), projectView.Status), nil
 }
 
-func getDeploymentsProjectModal(projectID uuid.UUID) (templ.Component, error) {
-	projectService := app.GetProjectService()
+func getDeploymentsProjectModal(ctx context.Context, projectID uuid.UUID) (templ.Component, error) {
+	projectService := handlers.ProjectService(ctx)
 	targetProject, err := projectService.Get(projectID)
 	if err != nil {
 		return nil, err
@@ -294,3 +395,70 @@ func getDeploymentsProjectModal(projectID uuid.UUID) (templ.Component, error) {
 	projectView := handlers.ConvertProjectToView(targetProject)
 	return modals.DeploymentsProjectModal(projectView, deployments), nil
 }
+
+func getRollbackProjectModal(ctx context.Context, projectID uuid.UUID) (templ.Component, error) {
+	deploymentID, err := uuid.Parse(chi.URLParamFromCtx(ctx, "deploymentID"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid deployment ID: %w", err)
+	}
+
+	projectService := handlers.ProjectService(ctx)
+	targetProject, err := projectService.Get(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	deployments, err := projectService.ListDeployments(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, deployment := range deployments {
+		if deployment.ID == deploymentID {
+			projectView := handlers.ConvertProjectToView(targetProject)
+			return modals.RollbackProjectModal(projectView, deployment), nil
+		}
+	}
+	return nil, fmt.Errorf("deployment %s not found", deploymentID)
+}
+
+func getApproveProjectModal(ctx context.Context, projectID uuid.UUID) (templ.Component, error) {
+	deploymentID, err := uuid.Parse(chi.URLParamFromCtx(ctx, "deploymentID"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid deployment ID: %w", err)
+	}
+
+	projectService := handlers.ProjectService(ctx)
+	targetProject, err := projectService.Get(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	deployments, err := projectService.ListDeployments(projectID)
+	if err != nil {
+		return nil, err
+	}
+	for _, deployment := range deployments {
+		if deployment.ID == deploymentID && deployment.Status == services.DeploymentStatusPending {
+			projectView := handlers.ConvertProjectToView(targetProject)
+			return modals.ApproveProjectModal(projectView, deployment), nil
+		}
+	}
+	return nil, fmt.Errorf("pending deployment %s not found", deploymentID)
+}
+
+// rejectDeployment rejects the deployment given in the URL and renders the deployments modal again. A deployment
+// that is no longer pending was approved or rejected by someone else, the modal shows which.
+func rejectDeployment(ctx context.Context, projectID uuid.UUID) (templ.Component, error) {
+	deploymentID, err := uuid.Parse(chi.URLParamFromCtx(ctx, "deploymentID"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid deployment ID: %w", err)
+	}
+
+	options := services.DeployOptions{Trigger: services.DeploymentTriggerWeb}
+	err = handlers.ProjectService(ctx).RejectDeployment(projectID, deploymentID, options)
+	if err != nil && !errors.Is(err, services.ErrDeploymentNotPending) {
+		return nil, err
+	}
+	return getDeploymentsProjectModal(ctx, projectID)
+}
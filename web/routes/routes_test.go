@@ -1,6 +1,8 @@
 package routes
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -8,6 +10,11 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/oar-cd/oar/services"
+	"github.com/oar-cd/oar/testing/mocks"
 	"github.com/oar-cd/oar/web/handlers"
 	"github.com/stretchr/testify/assert"
 )
@@ -55,6 +62,159 @@ func TestHealthCheckRoute(t *testing.T) {
 	assert.Equal(t, "OK", body)
 }
 
+func TestMetricsRoute(t *testing.T) {
+	app.SetProjectServiceForTesting(&mocks.MockProjectManager{
+		ListFunc: func() ([]*services.Project, error) {
+			return []*services.Project{{ID: uuid.New(), Name: "routed-app"}}, nil
+		},
+		GetStatusFunc: func(projectID uuid.UUID) (*services.ComposeStatus, error) {
+			return &services.ComposeStatus{Containers: []services.ContainerInfo{{State: "running"}}}, nil
+		},
+	})
+
+	user := &services.User{ID: uuid.New(), Username: "alice"}
+	app.SetAuthServiceForTesting(&mocks.MockUserManager{
+		AuthenticateFunc: func(username, password string) (*services.User, error) {
+			if username == user.Username && password == "correct-horse" {
+				return user, nil
+			}
+			return nil, services.ErrInvalidCredentials
+		},
+	})
+	app.SetRoleServiceForTesting(&mocks.MockRoleManager{
+		AuthorizeFunc: func(u *services.User, projectID *uuid.UUID, required services.Role) error {
+			assert.Nil(t, projectID)
+			assert.Equal(t, services.RoleViewer, required)
+			return nil
+		},
+	})
+
+	r := chi.NewRouter()
+	RegisterPublicRoutes(r)
+
+	// Metrics cover every project, so they aren't served to anyone
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.SetBasicAuth("alice", "correct-horse")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `oar_project_containers_running{project="routed-app"} 1`)
+	assert.Contains(t, w.Body.String(), "oar_watcher_poll_duration_seconds_count")
+}
+
+func TestDeployPreviewRoute(t *testing.T) {
+	projectID := uuid.New()
+	preview := &services.DeployPreview{
+		CurrentCommit: "1111111111111111111111111111111111111111",
+		TargetCommit:  "2222222222222222222222222222222222222222",
+		TargetRef:     "branch main",
+		Commits: []services.GitCommit{
+			{Hash: "2222222222222222222222222222222222222222", Author: "alice", Message: "Update nginx"},
+		},
+		ConfigDiff: "--- 11111111\n+++ 22222222\n@@ -1 +1 @@\n-image: nginx:1.25\n+image: nginx:1.27\n",
+	}
+	var previewErr error
+	app.SetProjectServiceForTesting(&mocks.MockProjectManager{
+		PreviewDeployFunc: func(
+			ctx context.Context,
+			id uuid.UUID,
+			options services.DeployOptions,
+		) (*services.DeployPreview, error) {
+			assert.Equal(t, projectID, id)
+			assert.True(t, options.Pull)
+			assert.Equal(t, services.DeploymentTriggerWeb, options.Trigger)
+			return preview, previewErr
+		},
+	})
+
+	r := chi.NewRouter()
+	RegisterProjectRoutes(r)
+	path := "/projects/" + projectID.String() + "/deploy/preview"
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	for _, expected := range []string{"1 new commit, configuration changed", "Update nginx", "branch main"} {
+		assert.Contains(t, w.Body.String(), expected)
+	}
+	assert.Contains(t, w.Body.String(), `<span class="diff-added">+image: nginx:1.27`)
+
+	// Failing to preview is shown in the modal
+	previewErr = errors.New("failed to fetch from remote")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Preview unavailable: failed to fetch from remote")
+
+	previewErr = services.ErrPermissionDenied
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestApproveRejectRoutes(t *testing.T) {
+	projectID := uuid.New()
+	pending := &services.Deployment{
+		ID:             uuid.New(),
+		ProjectID:      projectID,
+		CommitHash:     "2222222222222222222222222222222222222222",
+		PreviousCommit: "1111111111111111111111111111111111111111",
+		Status:         services.DeploymentStatusPending,
+		Trigger:        services.DeploymentTriggerWatcher,
+	}
+	var rejectErr error
+	app.SetProjectServiceForTesting(&mocks.MockProjectManager{
+		GetFunc: func(id uuid.UUID) (*services.Project, error) {
+			return &services.Project{ID: id, Name: "web"}, nil
+		},
+		ListDeploymentsFunc: func(id uuid.UUID) ([]*services.Deployment, error) {
+			return []*services.Deployment{pending}, nil
+		},
+		RejectDeploymentFunc: func(id, deploymentID uuid.UUID, options services.DeployOptions) error {
+			assert.Equal(t, projectID, id)
+			assert.Equal(t, pending.ID, deploymentID)
+			if rejectErr == nil {
+				pending.Status = services.DeploymentStatusRejected
+			}
+			return rejectErr
+		},
+	})
+
+	r := chi.NewRouter()
+	RegisterProjectRoutes(r)
+	path := "/projects/" + projectID.String() + "/deployments/" + pending.ID.String()
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path+"/approve", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Approve deployment of web")
+	assert.Contains(t, w.Body.String(), "22222222")
+	assert.Contains(t, w.Body.String(), `data-deployment-id="`+pending.ID.String()+`"`)
+
+	rejectErr = services.ErrPermissionDenied
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path+"/reject", nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// Rejecting renders the deployments again
+	rejectErr = nil
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path+"/reject", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "deployment-status-rejected")
+	assert.NotContains(t, w.Body.String(), "/approve")
+
+	// Only pending deployments can be approved
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path+"/approve", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
 // Test utility route form validation logic
 func TestTestGitAuthRouteFormValidation(t *testing.T) {
 	tests := []struct {
@@ -0,0 +1,82 @@
+// Package webhooks receives push notifications from Git providers and triggers deployments.
+package webhooks
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/oar-cd/oar/services"
+	"github.com/oar-cd/oar/web/handlers"
+)
+
+// maxPayloadSize limits webhook request bodies, large pushes produce payloads of a few megabytes
+const maxPayloadSize = 10 << 20
+
+// Response is the JSON body returned to the Git provider
+type Response struct {
+	Message  string            `json:"message,omitempty"`
+	Projects []ProjectResponse `json:"projects,omitempty"`
+	Error    string            `json:"error,omitempty"`
+}
+
+// ProjectResponse identifies a project a deployment was triggered for
+type ProjectResponse struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Receive handles POST /webhooks/{provider}. Requests are authenticated by their signature
+// or token rather than a session, so this handler must be registered outside RequireAuth.
+func Receive(w http.ResponseWriter, r *http.Request) {
+	provider := chi.URLParam(r, "provider")
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPayloadSize))
+	if err != nil {
+		writeJSON(w, http.StatusRequestEntityTooLarge, Response{Error: "failed to read payload: " + err.Error()})
+		return
+	}
+
+	result, err := app.GetWebhookService().Handle(provider, r.Header, body)
+	if err != nil {
+		handlers.LogOperationError("receive_webhook", "webhooks", err, "provider", provider)
+		writeJSON(w, statusForError(err), Response{Error: err.Error()})
+		return
+	}
+
+	response := Response{Message: result.Message}
+	for _, p := range result.Projects {
+		response.Projects = append(response.Projects, ProjectResponse{ID: p.ID.String(), Name: p.Name})
+	}
+
+	status := http.StatusOK
+	if len(result.Projects) > 0 {
+		status = http.StatusAccepted
+	}
+	writeJSON(w, status, response)
+}
+
+// statusForError maps webhook service errors to HTTP status codes
+func statusForError(err error) int {
+	switch {
+	case errors.Is(err, services.ErrWebhooksDisabled), errors.Is(err, services.ErrUnsupportedWebhookProvider):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrInvalidWebhookSignature):
+		return http.StatusUnauthorized
+	case errors.Is(err, services.ErrInvalidWebhookPayload):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		handlers.LogOperationError("webhook_write_json", "webhooks", err)
+	}
+}
@@ -0,0 +1,139 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/oar-cd/oar/internal/app"
+	"github.com/oar-cd/oar/services"
+	"github.com/oar-cd/oar/testing/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testSecret  = "webhook-secret"
+	pushPayload = `{
+		"ref": "refs/heads/main",
+		"after": "1111111111111111111111111111111111111111",
+		"repository": {"clone_url": "https://github.com/org/app.git"}
+	}`
+)
+
+func sign(body string) string {
+	mac := hmac.New(sha256.New, []byte(testSecret))
+	mac.Write([]byte(body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func newWebhookRequest(provider, body string, header map[string]string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/"+provider, strings.NewReader(body))
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("provider", provider)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+func TestReceive(t *testing.T) {
+	project := &services.Project{
+		ID:             uuid.New(),
+		Name:           "app",
+		GitURL:         "git@github.com:org/app.git",
+		GitBranch:      "main",
+		Status:         services.ProjectStatusRunning,
+		WatcherEnabled: true,
+	}
+
+	tests := []struct {
+		name           string
+		secret         string
+		provider       string
+		header         map[string]string
+		expectedStatus int
+		expectDeploy   bool
+	}{
+		{
+			name:           "push triggers deployment",
+			secret:         testSecret,
+			provider:       "github",
+			header:         map[string]string{"X-GitHub-Event": "push", "X-Hub-Signature-256": sign(pushPayload)},
+			expectedStatus: http.StatusAccepted,
+			expectDeploy:   true,
+		},
+		{
+			name:           "other event is acknowledged",
+			secret:         testSecret,
+			provider:       "github",
+			header:         map[string]string{"X-GitHub-Event": "ping", "X-Hub-Signature-256": sign(pushPayload)},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid signature",
+			secret:         testSecret,
+			provider:       "github",
+			header:         map[string]string{"X-GitHub-Event": "push", "X-Hub-Signature-256": "sha256=00"},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "unsupported provider",
+			secret:         testSecret,
+			provider:       "bitbucket",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "webhooks disabled",
+			provider:       "github",
+			header:         map[string]string{"X-GitHub-Event": "push", "X-Hub-Signature-256": sign(pushPayload)},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deployed := make(chan uuid.UUID, 1)
+			service := services.NewWebhookService(&mocks.MockProjectManager{
+				ListFunc: func() ([]*services.Project, error) {
+					return []*services.Project{project}, nil
+				},
+				DeployStreamingFunc: func(
+					projectID uuid.UUID,
+					options services.DeployOptions,
+					outputChan chan<- string,
+				) error {
+					deployed <- projectID
+					return nil
+				},
+			}, &services.Config{WebhookSecret: tt.secret})
+			app.SetWebhookServiceForTesting(service)
+
+			w := httptest.NewRecorder()
+			Receive(w, newWebhookRequest(tt.provider, pushPayload, tt.header))
+			service.Wait()
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+			var response Response
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+			if tt.expectDeploy {
+				assert.Equal(t, project.ID, <-deployed)
+				require.Len(t, response.Projects, 1)
+				assert.Equal(t, project.ID.String(), response.Projects[0].ID)
+			} else {
+				assert.Empty(t, deployed)
+				assert.Empty(t, response.Projects)
+			}
+		})
+	}
+}